@@ -36,4 +36,7 @@ CGO_ENABLED=0 GOOS=windows GOARCH=amd64 GO111MODULE=on go build \
 		-ldflags "${LD_FLAGS}" \
 		-o "${out_file}" main.go
 
+# the checksum and signature are verified by gardenctl self-update
+./hack/sign-release-binary.sh "${out_file}"
+
 popd > /dev/null
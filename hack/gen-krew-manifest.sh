@@ -0,0 +1,95 @@
+#!/usr/bin/env bash
+
+# SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+#
+# SPDX-License-Identifier: Apache-2.0
+
+set -e
+
+# Packages the binaries that have been built with make build as kubectl plugin archives and
+# generates the krew plugin manifest garden.yaml that refers to the archives of the release.
+# BINARY_PATH - path to the directory that contains the build results, defaults to bin.
+# EFFECTIVE_VERSION - version of the release, defaults to the content of the VERSION file.
+
+if [[ -z "${MAIN_REPO_DIR}" ]]; then
+  export MAIN_REPO_DIR="$(readlink -f $(dirname ${0})/..)"
+else
+  export MAIN_REPO_DIR="$(readlink -f "${MAIN_REPO_DIR}")"
+fi
+
+if [[ -z "${BINARY_PATH}" ]]; then
+  export BINARY_PATH="${MAIN_REPO_DIR}/bin"
+else
+  export BINARY_PATH="$(readlink -f "${BINARY_PATH}")"
+fi
+
+VERSION="${EFFECTIVE_VERSION:-$(cat "${MAIN_REPO_DIR}/VERSION")}"
+RELEASE_URL="https://github.com/gardener/gardenctl-v2/releases/download/${VERSION}"
+manifest="${BINARY_PATH}/garden.yaml"
+
+cat > "${manifest}" <<MANIFEST
+apiVersion: krew.googlecontainertools.github.com/v1alpha2
+kind: Plugin
+metadata:
+  name: garden
+spec:
+  version: ${VERSION}
+  homepage: https://github.com/gardener/gardenctl-v2
+  shortDescription: Target and access the clusters of Gardener installations
+  description: |
+    kubectl garden is gardenctl running as kubectl plugin. It targets the garden,
+    seed and shoot clusters of Gardener installations, sets up their kubeconfigs
+    and cloud provider CLIs and opens SSH connections to the nodes of shoots.
+    All gardenctl commands are available, e.g. kubectl garden target shoot my-shoot.
+  platforms:
+MANIFEST
+
+for platform in darwin-amd64 darwin-arm64 linux-amd64 windows-amd64; do
+  os="${platform%-*}"
+  arch="${platform#*-}"
+  ext=""
+  if [[ "${os}" == "windows" ]]; then
+    ext=".exe"
+  fi
+
+  binary="${BINARY_PATH}/${platform}/gardenctl_v2_${os}_${arch}${ext}"
+  archive="${BINARY_PATH}/kubectl-garden_${os}_${arch}.tar.gz"
+  staging="$(mktemp -d)"
+
+  cp "${binary}" "${staging}/kubectl-garden${ext}"
+  cp "${MAIN_REPO_DIR}/LICENSE" "${staging}/LICENSE"
+  if [[ "${os}" != "windows" ]]; then
+    # kubectl uses kubectl_complete-garden for the shell completion of the plugin
+    printf '#!/usr/bin/env sh\nexec kubectl garden __complete "$@"\n' > "${staging}/kubectl_complete-garden"
+    chmod +x "${staging}/kubectl_complete-garden"
+  fi
+
+  tar -czf "${archive}" -C "${staging}" .
+  rm -rf "${staging}"
+
+  echo "packaged kubectl plugin for ${platform}: ${archive}"
+
+  cat >> "${manifest}" <<MANIFEST
+  - selector:
+      matchLabels:
+        os: ${os}
+        arch: ${arch}
+    uri: ${RELEASE_URL}/kubectl-garden_${os}_${arch}.tar.gz
+    sha256: $(sha256sum "${archive}" | cut -d' ' -f1)
+    bin: kubectl-garden${ext}
+    files:
+    - from: kubectl-garden${ext}
+      to: .
+    - from: LICENSE
+      to: .
+MANIFEST
+
+  if [[ "${os}" != "windows" ]]; then
+    cat >> "${manifest}" <<MANIFEST
+    - from: kubectl_complete-garden
+      to: .
+MANIFEST
+  fi
+done
+
+echo "generated krew plugin manifest: ${manifest}"
@@ -39,3 +39,12 @@ do
         -X $PACKAGE_PATH/version.buildDate=$(date '+%Y-%m-%dT%H:%M:%S%z' | sed 's/\([0-9][0-9]\)$/:\1/g')
         -X $PACKAGE_PATH/version/verflag.programName=$PROGRAM_NAME"
 done
+
+# the public key that gardenctl self-update verifies the signatures of the release binaries with,
+# it is derived from the private key the binaries are signed with, see sign-release-binary.sh
+if [[ -z "${RELEASE_SIGNING_PUBLIC_KEY}" && -n "${RELEASE_SIGNING_KEY_FILE}" ]]; then
+  RELEASE_SIGNING_PUBLIC_KEY="$(openssl pkey -in "${RELEASE_SIGNING_KEY_FILE}" -pubout -outform DER | tail -c 32 | openssl base64 -A)"
+fi
+if [[ -n "${RELEASE_SIGNING_PUBLIC_KEY}" ]]; then
+  echo "-X github.com/gardener/gardenctl-v2/internal/release.signingPublicKey=$RELEASE_SIGNING_PUBLIC_KEY"
+fi
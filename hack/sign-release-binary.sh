@@ -0,0 +1,53 @@
+#!/usr/bin/env bash
+
+# SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+#
+# SPDX-License-Identifier: Apache-2.0
+
+set -e
+
+# Writes the files that gardenctl self-update verifies a release binary with:
+# <binary>.sha256 - the SHA256 checksum in the format of sha256sum
+# <binary>.sig    - the base64 encoded ed25519 signature, if RELEASE_SIGNING_KEY_FILE is set
+#
+# RELEASE_SIGNING_KEY_FILE - path to the PEM encoded ed25519 private key the release binaries are signed with.
+# RELEASE_SIGNING_PUBLIC_KEY - base64 encoded public key that is compiled into the binaries, see get-build-ld-flags.sh.
+#                              It must belong to the private key, if both are set.
+
+binary="${1:?usage: $0 <binary>}"
+
+dir="$(dirname "${binary}")"
+name="$(basename "${binary}")"
+
+pushd "${dir}" > /dev/null
+
+# sha256sum is not available on macOS by default
+if command -v sha256sum > /dev/null; then
+  sha256sum "${name}" > "${name}.sha256"
+else
+  shasum -a 256 "${name}" > "${name}.sha256"
+fi
+
+rm -f "${name}.sig"
+
+if [[ -z "${RELEASE_SIGNING_KEY_FILE}" ]]; then
+  if [[ -n "${RELEASE_SIGNING_PUBLIC_KEY}" ]]; then
+    # the binary refuses to update to releases without signature, do not publish binaries that cannot be verified
+    echo "RELEASE_SIGNING_PUBLIC_KEY is set, but RELEASE_SIGNING_KEY_FILE is not, ${name} cannot be signed" >&2
+    exit 1
+  fi
+
+  popd > /dev/null
+  exit 0
+fi
+
+# the public key is the last 32 bytes of the DER encoded SubjectPublicKeyInfo
+public_key="$(openssl pkey -in "${RELEASE_SIGNING_KEY_FILE}" -pubout -outform DER | tail -c 32 | openssl base64 -A)"
+if [[ -n "${RELEASE_SIGNING_PUBLIC_KEY}" && "${RELEASE_SIGNING_PUBLIC_KEY}" != "${public_key}" ]]; then
+  echo "RELEASE_SIGNING_PUBLIC_KEY does not belong to the key in ${RELEASE_SIGNING_KEY_FILE}" >&2
+  exit 1
+fi
+
+openssl pkeyutl -sign -rawin -inkey "${RELEASE_SIGNING_KEY_FILE}" -in "${name}" | openssl base64 -A > "${name}.sig"
+
+popd > /dev/null
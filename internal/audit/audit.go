@@ -0,0 +1,160 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	// ResultSuccess is the result of actions that succeeded
+	ResultSuccess = "success"
+	// ResultFailure is the result of actions that failed, the error is recorded as well
+	ResultFailure = "failure"
+)
+
+// Target is the target of the gardenctl session in which an action has been executed
+type Target struct {
+	Garden       string `json:"garden,omitempty"`
+	Project      string `json:"project,omitempty"`
+	Seed         string `json:"seed,omitempty"`
+	Shoot        string `json:"shoot,omitempty"`
+	ControlPlane bool   `json:"controlPlane,omitempty"`
+}
+
+// Entry is a line of the audit log
+type Entry struct {
+	// Timestamp is the time at which the action has been executed
+	Timestamp time.Time `json:"timestamp"`
+	// User is the name of the operating system user that executed gardenctl
+	User string `json:"user,omitempty"`
+	// Command is the gardenctl command that executed the action, e.g. gardenctl shoot hibernate
+	Command string `json:"command,omitempty"`
+	// Target is the target of the session at the time of the action
+	Target *Target `json:"target,omitempty"`
+	// Action is the change that has been made, e.g. the verb of a request to a cluster or write for files
+	Action string `json:"action"`
+	// Resource is the changed resource, e.g. Shoot garden-dev/my-shoot or the path of a file
+	Resource string `json:"resource,omitempty"`
+	// Args are the arguments of commands that are recorded as a whole, e.g. of kubectl
+	Args []string `json:"args,omitempty"`
+	// Result is either success or failure
+	Result string `json:"result"`
+	// Error is the error of failed actions
+	Error string `json:"error,omitempty"`
+}
+
+// Logger appends the mutating actions of gardenctl as JSON lines to a file. A nil Logger does not log anything,
+// hence callers do not need to check whether the audit log has been configured.
+type Logger struct {
+	// Filename is the path of the audit log, the file and its directory are created if they do not exist
+	Filename string
+	// Command is recorded for entries that do not specify a command
+	Command string
+	// User is recorded for entries that do not specify a user
+	User string
+	// Target is recorded for entries that do not specify a target
+	Target *Target
+	// ErrOut receives a warning if an entry cannot be written. Failing to write the audit log does not
+	// fail the command, because the action has already been executed.
+	ErrOut io.Writer
+	// Now returns the timestamp of the entries. Defaults to time.Now
+	Now func() time.Time
+
+	mutex sync.Mutex
+}
+
+// Log records the action of the entry with the given result. The timestamp and the fields of the entry that are
+// not set are completed with the defaults of the Logger.
+func (l *Logger) Log(entry Entry, err error) {
+	if l == nil || l.Filename == "" {
+		return
+	}
+
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = l.now()
+	}
+
+	if entry.User == "" {
+		entry.User = l.User
+	}
+
+	if entry.Command == "" {
+		entry.Command = l.Command
+	}
+
+	if entry.Target == nil {
+		entry.Target = l.Target
+	}
+
+	entry.Result = ResultSuccess
+	if err != nil {
+		entry.Result = ResultFailure
+		entry.Error = err.Error()
+	}
+
+	if writeErr := l.write(entry); writeErr != nil && l.ErrOut != nil {
+		fmt.Fprintf(l.ErrOut, "Warning: failed to write audit log: %v\n", writeErr)
+	}
+}
+
+func (l *Logger) now() time.Time {
+	if l.Now != nil {
+		return l.Now().UTC()
+	}
+
+	return time.Now().UTC()
+}
+
+// write appends the entry as a single line, concurrent gardenctl processes do not interleave their lines
+// because each line is appended with a single write
+func (l *Logger) write(entry Entry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(l.Filename), 0700); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(l.Filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+// CurrentUser returns the name of the operating system user, or the value of the USER environment variable
+// if the user cannot be looked up
+func CurrentUser() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+
+	if name, ok := os.LookupEnv("USER"); ok {
+		return name
+	}
+
+	return os.Getenv("USERNAME")
+}
@@ -0,0 +1,93 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package audit_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/gardener/gardenctl-v2/internal/audit"
+)
+
+var _ = Describe("Audit", func() {
+	var (
+		dir    string
+		errOut *bytes.Buffer
+		logger *audit.Logger
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "audit-")
+		Expect(err).NotTo(HaveOccurred())
+
+		errOut = &bytes.Buffer{}
+		logger = &audit.Logger{
+			Filename: filepath.Join(dir, "logs", "audit.log"),
+			Command:  "gardenctl shoot hibernate",
+			User:     "jdoe",
+			Target:   &audit.Target{Garden: "prod", Project: "foo", Shoot: "bar"},
+			ErrOut:   errOut,
+			Now: func() time.Time {
+				return time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+			},
+		}
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	readLines := func() []string {
+		data, err := os.ReadFile(logger.Filename)
+		Expect(err).NotTo(HaveOccurred())
+
+		return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	}
+
+	It("should append the entries as JSON lines", func() {
+		logger.Log(audit.Entry{Action: "patch", Resource: "Shoot garden-foo/bar"}, nil)
+		logger.Log(audit.Entry{Action: "delete", Resource: "Shoot garden-foo/bar"}, errors.New("forbidden"))
+
+		Expect(readLines()).To(Equal([]string{
+			`{"timestamp":"2022-06-01T12:00:00Z","user":"jdoe","command":"gardenctl shoot hibernate","target":{"garden":"prod","project":"foo","shoot":"bar"},"action":"patch","resource":"Shoot garden-foo/bar","result":"success"}`,
+			`{"timestamp":"2022-06-01T12:00:00Z","user":"jdoe","command":"gardenctl shoot hibernate","target":{"garden":"prod","project":"foo","shoot":"bar"},"action":"delete","resource":"Shoot garden-foo/bar","result":"failure","error":"forbidden"}`,
+		}))
+		Expect(errOut.String()).To(BeEmpty())
+
+		info, err := os.Stat(logger.Filename)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+	})
+
+	It("should keep the target of the entry", func() {
+		logger.Log(audit.Entry{Target: &audit.Target{Garden: "dev"}, Action: "write", Resource: "target.yaml"}, nil)
+
+		Expect(readLines()).To(ConsistOf(ContainSubstring(`"target":{"garden":"dev"}`)))
+	})
+
+	It("should warn if the audit log cannot be written", func() {
+		Expect(os.WriteFile(filepath.Join(dir, "logs"), nil, 0600)).To(Succeed())
+
+		logger.Log(audit.Entry{Action: "patch"}, nil)
+
+		Expect(errOut.String()).To(HavePrefix("Warning: failed to write audit log: "))
+	})
+
+	It("should not log anything without logger", func() {
+		var nilLogger *audit.Logger
+
+		Expect(func() { nilLogger.Log(audit.Entry{Action: "patch"}, nil) }).NotTo(Panic())
+	})
+})
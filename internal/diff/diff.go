@@ -0,0 +1,159 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package diff prints the line-based differences between two versions of a file,
+// e.g. the changes that gardenctl would make to the configuration in dry-run mode.
+package diff
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// contextLines is the number of unchanged lines that are printed before and after each change
+const contextLines = 3
+
+// op is a line of the edit script that transforms the old into the new content
+type op struct {
+	// kind is ' ' for an unchanged line, '-' for a removed line and '+' for an added line
+	kind byte
+	line string
+}
+
+// hunk is a range of the edit script that contains changes, start is inclusive and end is exclusive
+type hunk struct {
+	start, end int
+}
+
+// Print writes the differences between the old and the new content of the file to w in the unified diff format.
+// It prints a single line instead if the contents are equal.
+func Print(w io.Writer, filename string, oldData, newData []byte) error {
+	ops := editScript(lines(oldData), lines(newData))
+
+	var hunks []hunk
+
+	for k, o := range ops {
+		if o.kind == ' ' {
+			continue
+		}
+
+		start, end := k-contextLines, k+contextLines+1
+		if start < 0 {
+			start = 0
+		}
+
+		if end > len(ops) {
+			end = len(ops)
+		}
+
+		if n := len(hunks); n > 0 && start <= hunks[n-1].end {
+			hunks[n-1].end = end
+		} else {
+			hunks = append(hunks, hunk{start: start, end: end})
+		}
+	}
+
+	if len(hunks) == 0 {
+		_, err := fmt.Fprintf(w, "No changes to %s\n", filename)
+		return err
+	}
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", filename, filename)
+
+	for _, h := range hunks {
+		oldStart, oldLen := position(ops[:h.start], ops[h.start:h.end], '+')
+		newStart, newLen := position(ops[:h.start], ops[h.start:h.end], '-')
+
+		fmt.Fprintf(&b, "@@ -%d,%d +%d,%d @@\n", oldStart, oldLen, newStart, newLen)
+
+		for _, o := range ops[h.start:h.end] {
+			fmt.Fprintf(&b, "%c%s\n", o.kind, o.line)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// lines splits the content into its lines without the line breaks
+func lines(data []byte) []string {
+	if len(data) == 0 {
+		return nil
+	}
+
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+}
+
+// editScript returns the shortest edit script that transforms a into b, based on their longest common subsequence
+func editScript(a, b []string) []op {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	ops := make([]op, 0, len(a)+len(b))
+	i, j := 0, 0
+
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			ops = append(ops, op{kind: ' ', line: a[i]})
+			i++
+			j++
+		case j < len(b) && (i == len(a) || lcs[i][j+1] > lcs[i+1][j]):
+			ops = append(ops, op{kind: '+', line: b[j]})
+			j++
+		default:
+			ops = append(ops, op{kind: '-', line: a[i]})
+			i++
+		}
+	}
+
+	return ops
+}
+
+// position returns the first line number and the number of lines of a hunk in the old or new content.
+// The lines of the given kind do not belong to the content, i.e. '+' for the old and '-' for the new content.
+func position(before, ops []op, skip byte) (int, int) {
+	start, length := 0, 0
+
+	for _, o := range before {
+		if o.kind != skip {
+			start++
+		}
+	}
+
+	for _, o := range ops {
+		if o.kind != skip {
+			length++
+		}
+	}
+
+	// the line numbers start with 1, an empty range refers to the line before it
+	if length > 0 {
+		start++
+	}
+
+	return start, length
+}
@@ -0,0 +1,63 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package diff_test
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/gardener/gardenctl-v2/internal/diff"
+)
+
+var _ = Describe("Diff", func() {
+	var out *bytes.Buffer
+
+	BeforeEach(func() {
+		out = &bytes.Buffer{}
+	})
+
+	It("should print that there are no changes", func() {
+		Expect(diff.Print(out, "target.yaml", []byte("garden: foo\n"), []byte("garden: foo\n"))).To(Succeed())
+		Expect(out.String()).To(Equal("No changes to target.yaml\n"))
+	})
+
+	It("should print a new file", func() {
+		Expect(diff.Print(out, "target.yaml", nil, []byte("garden: foo\nproject: bar\n"))).To(Succeed())
+		Expect(out.String()).To(Equal(`--- target.yaml
++++ target.yaml
+@@ -0,0 +1,2 @@
++garden: foo
++project: bar
+`))
+	})
+
+	It("should print the changed lines with their context", func() {
+		oldData := []byte("a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm\n")
+		newData := []byte("a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk\nm\nn\n")
+
+		Expect(diff.Print(out, "gardenctl-v2.yaml", oldData, newData)).To(Succeed())
+		Expect(out.String()).To(Equal(`--- gardenctl-v2.yaml
++++ gardenctl-v2.yaml
+@@ -1,5 +1,5 @@
+ a
+-b
++B
+ c
+ d
+ e
+@@ -9,5 +9,5 @@
+ i
+ j
+ k
+-l
+ m
++n
+`))
+	})
+})
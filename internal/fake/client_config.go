@@ -0,0 +1,24 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fake
+
+import (
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// NewClientConfig returns a client config for a cluster with the given name, e.g. https://api.<name>.example.com,
+// whose current context uses the given namespace
+func NewClientConfig(name, namespace string) clientcmd.ClientConfig {
+	config := clientcmdapi.NewConfig()
+	config.Clusters[name] = &clientcmdapi.Cluster{Server: "https://api." + name + ".example.com"}
+	config.AuthInfos[name] = &clientcmdapi.AuthInfo{Token: "token"}
+	config.Contexts[name] = &clientcmdapi.Context{Cluster: name, AuthInfo: name, Namespace: namespace}
+	config.CurrentContext = name
+
+	return clientcmd.NewDefaultClientConfig(*config, nil)
+}
@@ -10,6 +10,8 @@ import (
 	"context"
 	"os"
 
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/utils/pointer"
 
 	"github.com/gardener/gardenctl-v2/internal/util"
@@ -34,6 +36,10 @@ type Factory struct {
 	// Override the clock implementation. Will use a real clock if not set.
 	ClockImpl util.Clock
 
+	// Override the clientset constructor, e.g. to return a fake clientset.
+	// Will create a real clientset if not set.
+	ClientsetImpl func(clientConfig clientcmd.ClientConfig) (kubernetes.Interface, error)
+
 	// GardenHomeDirectory is the home directory for all gardenctl
 	// related files. While some files can be explicitly loaded from
 	// different locations, cache files will always be placed inside
@@ -73,7 +79,24 @@ func (f *Factory) Manager() (target.Manager, error) {
 
 	sessionDir := os.TempDir()
 
-	return target.NewManager(f.Config, f.TargetProviderImpl, f.ClientProviderImpl, sessionDir)
+	clientProvider := f.ClientProviderImpl
+	if f.Config.Audit != nil && clientProvider != nil {
+		clientProvider = target.NewAuditClientProvider(clientProvider, f.Config.Audit)
+	}
+
+	if f.Config.IsDryRun() && clientProvider != nil {
+		clientProvider = target.NewDryRunClientProvider(clientProvider, *f.Config.DryRun)
+	}
+
+	return target.NewManager(f.Config, f.TargetProviderImpl, clientProvider, sessionDir)
+}
+
+func (f *Factory) Clientset(clientConfig clientcmd.ClientConfig) (kubernetes.Interface, error) {
+	if f.ClientsetImpl != nil {
+		return f.ClientsetImpl(clientConfig)
+	}
+
+	return (&util.FactoryImpl{}).Clientset(clientConfig)
 }
 
 func (f *Factory) Context() context.Context {
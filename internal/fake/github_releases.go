@@ -0,0 +1,104 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fake
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/gardener/gardenctl-v2/internal/release"
+)
+
+// GitHubReleases serves the releases of gardenctl and their assets like the GitHub API
+type GitHubReleases struct {
+	*httptest.Server
+
+	lock     sync.Mutex
+	releases []release.Release
+	assets   map[string][]byte
+}
+
+// NewGitHubReleases starts a new GitHubReleases server, it has to be closed after use
+func NewGitHubReleases() *GitHubReleases {
+	g := &GitHubReleases{
+		assets: map[string][]byte{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/releases", g.list)
+	mux.HandleFunc("/download/", g.download)
+	g.Server = httptest.NewServer(mux)
+
+	return g
+}
+
+// ReleasesURL returns the URL that lists the releases
+func (g *GitHubReleases) ReleasesURL() string {
+	return g.URL + "/releases"
+}
+
+// AddRelease adds a release with the binary for the current operating system and architecture and its checksum
+func (g *GitHubReleases) AddRelease(tag string, prerelease bool, binary []byte) {
+	g.lock.Lock()
+	g.releases = append(g.releases, release.Release{
+		TagName:    tag,
+		HTMLURL:    "https://github.com/gardener/gardenctl-v2/releases/tag/" + tag,
+		Prerelease: prerelease,
+	})
+	g.lock.Unlock()
+
+	name := release.AssetName(runtime.GOOS, runtime.GOARCH)
+	checksum := sha256.Sum256(binary)
+
+	g.SetAsset(tag, name, binary)
+	g.SetAsset(tag, name+".sha256", []byte(fmt.Sprintf("%x  %s\n", checksum, name)))
+}
+
+// SetAsset adds or replaces an asset of the release with the given tag
+func (g *GitHubReleases) SetAsset(tag, name string, data []byte) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	key := tag + "/" + name
+	if _, ok := g.assets[key]; !ok {
+		for i := range g.releases {
+			if g.releases[i].TagName == tag {
+				g.releases[i].Assets = append(g.releases[i].Assets, release.Asset{
+					Name:        name,
+					DownloadURL: g.URL + "/download/" + key,
+				})
+			}
+		}
+	}
+
+	g.assets[key] = data
+}
+
+func (g *GitHubReleases) list(w http.ResponseWriter, r *http.Request) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	writeJSON(w, g.releases)
+}
+
+func (g *GitHubReleases) download(w http.ResponseWriter, r *http.Request) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	data, ok := g.assets[strings.TrimPrefix(r.URL.Path, "/download/")]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	_, _ = w.Write(data)
+}
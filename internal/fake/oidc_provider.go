@@ -0,0 +1,184 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fake
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// OIDCProvider is a minimal OIDC provider that supports the authorization code flow with PKCE and refresh tokens
+type OIDCProvider struct {
+	*httptest.Server
+
+	// ClientID is the only client ID that is accepted
+	ClientID string
+	// TokenLifetime is the lifetime of the issued ID tokens
+	TokenLifetime time.Duration
+
+	lock         sync.Mutex
+	codes        int
+	challenges   map[string]string
+	issued       int
+	refreshed    int
+	refreshToken string
+}
+
+// NewOIDCProvider starts a new OIDCProvider, it has to be closed after use
+func NewOIDCProvider(clientID string) *OIDCProvider {
+	p := &OIDCProvider{
+		ClientID:      clientID,
+		TokenLifetime: time.Hour,
+		challenges:    map[string]string{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", p.discovery)
+	mux.HandleFunc("/authorize", p.authorize)
+	mux.HandleFunc("/token", p.token)
+	p.Server = httptest.NewServer(mux)
+
+	return p
+}
+
+// Browser simulates a user that opens the authorization URL in the browser and logs in successfully
+func (p *OIDCProvider) Browser(authURL string) error {
+	resp, err := http.Get(authURL)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("login failed: %s", resp.Status)
+	}
+
+	return nil
+}
+
+// Issued returns the number of ID tokens that have been issued for authorization codes
+func (p *OIDCProvider) Issued() int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return p.issued
+}
+
+// Refreshed returns the number of ID tokens that have been issued for refresh tokens
+func (p *OIDCProvider) Refreshed() int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return p.refreshed
+}
+
+// RefreshToken returns the refresh token that has been issued last, only this token can be used to refresh the ID token
+func (p *OIDCProvider) RefreshToken() string {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return p.refreshToken
+}
+
+// IDToken returns an unsigned ID token with the given expiry
+func (p *OIDCProvider) IDToken(expiry time.Time) string {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`))
+	claims := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"iss":%q,"aud":%q,"exp":%d}`, p.URL, p.ClientID, expiry.Unix())))
+
+	return header + "." + claims + ".signature"
+}
+
+func (p *OIDCProvider) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string]string{
+		"issuer":                 p.URL,
+		"authorization_endpoint": p.URL + "/authorize",
+		"token_endpoint":         p.URL + "/token",
+	})
+}
+
+func (p *OIDCProvider) authorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("client_id") != p.ClientID || query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "" {
+		http.Error(w, "invalid authorization request", http.StatusBadRequest)
+		return
+	}
+
+	p.lock.Lock()
+	p.codes++
+	code := fmt.Sprintf("code-%d", p.codes)
+	p.challenges[code] = query.Get("code_challenge")
+	p.lock.Unlock()
+
+	redirectURL, err := url.Parse(query.Get("redirect_uri"))
+	if err != nil {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+
+	values := redirectURL.Query()
+	values.Set("code", code)
+	values.Set("state", query.Get("state"))
+	redirectURL.RawQuery = values.Encode()
+
+	http.Redirect(w, r, redirectURL.String(), http.StatusFound)
+}
+
+func (p *OIDCProvider) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	switch r.PostForm.Get("grant_type") {
+	case "authorization_code":
+		challenge, ok := p.challenges[r.PostForm.Get("code")]
+		delete(p.challenges, r.PostForm.Get("code"))
+
+		verifier := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+		if !ok || base64.RawURLEncoding.EncodeToString(verifier[:]) != challenge {
+			http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+			return
+		}
+
+		p.issued++
+	case "refresh_token":
+		if r.PostForm.Get("refresh_token") != p.refreshToken {
+			http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+			return
+		}
+
+		p.refreshed++
+	default:
+		http.Error(w, `{"error":"unsupported_grant_type"}`, http.StatusBadRequest)
+		return
+	}
+
+	p.refreshToken = fmt.Sprintf("refresh-%d-%d", p.issued, p.refreshed)
+
+	writeJSON(w, map[string]interface{}{
+		"access_token":  "access",
+		"token_type":    "Bearer",
+		"expires_in":    int(p.TokenLifetime.Seconds()),
+		"refresh_token": p.refreshToken,
+		"id_token":      p.IDToken(time.Now().Add(p.TokenLifetime)),
+	})
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,90 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package filelock provides advisory file locks that serialize the access of
+// concurrent gardenctl processes to the configuration and target files.
+package filelock
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+var (
+	// Timeout is the maximum duration to wait for a lock
+	Timeout = 30 * time.Second
+	// retryInterval is the duration to wait before trying to acquire a lock again
+	retryInterval = 50 * time.Millisecond
+
+	// errLocked is returned by tryLock if the lock is held by another process
+	errLocked = errors.New("file is locked")
+)
+
+// Unlock releases a lock
+type Unlock func()
+
+// Lock acquires an exclusive lock for the given file. The lock is held on a separate
+// lock file (filename.lock), so that it survives the file being replaced atomically.
+func Lock(filename string) (Unlock, error) {
+	f, err := os.OpenFile(lockFilename(filename), os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+
+	return acquire(f, true)
+}
+
+// RLock acquires a shared lock for the given file. An existing lock file is opened read-only if it cannot be
+// opened for writing. If the directory of the file does not exist, the file cannot exist either and there is nothing
+// to lock. Otherwise an error is returned if the lock file cannot be opened or created.
+func RLock(filename string) (Unlock, error) {
+	f, err := os.OpenFile(lockFilename(filename), os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		if _, statErr := os.Stat(filepath.Dir(filename)); errors.Is(statErr, fs.ErrNotExist) {
+			return func() {}, nil
+		}
+
+		if f, err = os.Open(lockFilename(filename)); err != nil {
+			return nil, fmt.Errorf("failed to open lock file: %w", err)
+		}
+	}
+
+	return acquire(f, false)
+}
+
+func lockFilename(filename string) string {
+	return filename + ".lock"
+}
+
+func acquire(f *os.File, exclusive bool) (Unlock, error) {
+	deadline := time.Now().Add(Timeout)
+
+	for {
+		err := tryLock(f, exclusive)
+		if err == nil {
+			return func() {
+				_ = unlock(f)
+				f.Close()
+			}, nil
+		}
+
+		if !errors.Is(err, errLocked) {
+			f.Close()
+			return nil, fmt.Errorf("failed to lock %s: %w", f.Name(), err)
+		}
+
+		if time.Now().After(deadline) {
+			f.Close()
+			return nil, fmt.Errorf("timed out waiting for lock %s", f.Name())
+		}
+
+		time.Sleep(retryInterval)
+	}
+}
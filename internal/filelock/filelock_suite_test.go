@@ -0,0 +1,19 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package filelock_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestFilelock(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Filelock Test Suite")
+}
@@ -0,0 +1,97 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package filelock_test
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/gardener/gardenctl-v2/internal/filelock"
+)
+
+var _ = Describe("Filelock", func() {
+	var (
+		dir      string
+		filename string
+		timeout  time.Duration
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "filelock-")
+		Expect(err).NotTo(HaveOccurred())
+
+		filename = filepath.Join(dir, "config.yaml")
+		timeout = filelock.Timeout
+		filelock.Timeout = 200 * time.Millisecond
+	})
+
+	AfterEach(func() {
+		filelock.Timeout = timeout
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("should allow multiple shared locks", func() {
+		unlock1, err := filelock.RLock(filename)
+		Expect(err).NotTo(HaveOccurred())
+
+		defer unlock1()
+
+		unlock2, err := filelock.RLock(filename)
+		Expect(err).NotTo(HaveOccurred())
+		unlock2()
+
+		Expect(filepath.Join(dir, "config.yaml.lock")).To(BeAnExistingFile())
+	})
+
+	It("should time out while another lock is held", func() {
+		unlock, err := filelock.RLock(filename)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = filelock.Lock(filename)
+		Expect(err).To(MatchError(HavePrefix("timed out waiting for lock")))
+
+		unlock()
+
+		unlock, err = filelock.Lock(filename)
+		Expect(err).NotTo(HaveOccurred())
+		unlock()
+	})
+
+	It("should wait until an exclusive lock is released", func() {
+		unlock, err := filelock.Lock(filename)
+		Expect(err).NotTo(HaveOccurred())
+
+		time.AfterFunc(50*time.Millisecond, unlock)
+
+		unlock, err = filelock.RLock(filename)
+		Expect(err).NotTo(HaveOccurred())
+		unlock()
+	})
+
+	It("should not fail to acquire a shared lock if the directory does not exist", func() {
+		unlock, err := filelock.RLock(filepath.Join(dir, "does-not-exist", "config.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+		unlock()
+	})
+
+	It("should fail to acquire a shared lock if the lock file cannot be created", func() {
+		Expect(os.WriteFile(filepath.Join(dir, "file"), nil, 0600)).To(Succeed())
+
+		_, err := filelock.RLock(filepath.Join(dir, "file", "config.yaml"))
+		Expect(err).To(MatchError(HavePrefix("failed to open lock file")))
+	})
+
+	It("should fail to acquire an exclusive lock if the lock file cannot be created", func() {
+		_, err := filelock.Lock(filepath.Join(dir, "does-not-exist", "config.yaml"))
+		Expect(err).To(MatchError(HavePrefix("failed to open lock file")))
+	})
+})
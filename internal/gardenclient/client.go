@@ -8,31 +8,50 @@ package gardenclient
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
+	"time"
 
 	openstackinstall "github.com/gardener/gardener-extension-provider-openstack/pkg/apis/openstack/install"
 	openstackv1alpha1 "github.com/gardener/gardener-extension-provider-openstack/pkg/apis/openstack/v1alpha1"
+	authenticationv1alpha1 "github.com/gardener/gardener/pkg/apis/authentication/v1alpha1"
 	gardencore "github.com/gardener/gardener/pkg/apis/core"
 	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	operationsv1alpha1 "github.com/gardener/gardener/pkg/apis/operations/v1alpha1"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-var decoder runtime.Decoder
+var (
+	decoder runtime.Decoder
+	// authenticationCodecs is used by the REST client for kubeconfig requests
+	authenticationCodecs serializer.CodecFactory
+)
+
+// ErrAccessDenied is returned if a SelfSubjectAccessReview denies an action
+var ErrAccessDenied = errors.New("access denied")
 
 func init() {
 	extensionsScheme := runtime.NewScheme()
 	utilruntime.Must(openstackinstall.AddToScheme(extensionsScheme))
 	decoder = serializer.NewCodecFactory(extensionsScheme).UniversalDecoder()
+
+	authenticationScheme := runtime.NewScheme()
+	utilruntime.Must(authenticationv1alpha1.AddToScheme(authenticationScheme))
+	authenticationCodecs = serializer.NewCodecFactory(authenticationScheme)
 }
 
 //go:generate mockgen -destination=./mocks/mock_client.go -package=mocks github.com/gardener/gardenctl-v2/internal/gardenclient Client
@@ -65,9 +84,18 @@ type Client interface {
 
 	// GetSecretBinding returns a Gardener secretbinding resource
 	GetSecretBinding(ctx context.Context, namespace, name string) (*gardencorev1beta1.SecretBinding, error)
+	// ListSecretBindings returns all Gardener secretbinding resources in a namespace
+	ListSecretBindings(ctx context.Context, namespace string) (*gardencorev1beta1.SecretBindingList, error)
+
+	// GetBastion returns a Gardener bastion resource in a namespace by name
+	GetBastion(ctx context.Context, namespace, name string) (*operationsv1alpha1.Bastion, error)
+	// ListBastions returns all Gardener bastion resources, filtered by a list option
+	ListBastions(ctx context.Context, opts ...client.ListOption) (*operationsv1alpha1.BastionList, error)
 
 	// GetCloudProfile returns a Gardener cloudprofile resource
 	GetCloudProfile(ctx context.Context, name string) (*gardencorev1beta1.CloudProfile, error)
+	// ListCloudProfiles returns all Gardener cloudprofile resources
+	ListCloudProfiles(ctx context.Context, opts ...client.ListOption) (*gardencorev1beta1.CloudProfileList, error)
 
 	// GetNamespace returns a Kubernetes namespace resource
 	GetNamespace(ctx context.Context, name string) (*corev1.Namespace, error)
@@ -76,6 +104,27 @@ type Client interface {
 	// GetConfigMap returns a Kubernetes configmap resource
 	GetConfigMap(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error)
 
+	// CreateAdminKubeconfigRequest requests a kubeconfig with admin credentials for a shoot, which expire after the given duration.
+	// The request is sent to the shoots/adminkubeconfig subresource.
+	CreateAdminKubeconfigRequest(ctx context.Context, namespace, name string, expiration time.Duration) (*authenticationv1alpha1.AdminKubeconfigRequest, error)
+
+	// CreateViewerKubeconfigRequest requests a kubeconfig with read-only credentials for a shoot, which expire after the given duration.
+	// The request is sent to the shoots/viewerkubeconfig subresource.
+	CreateViewerKubeconfigRequest(ctx context.Context, namespace, name string, expiration time.Duration) (*authenticationv1alpha1.AdminKubeconfigRequest, error)
+
+	// GetShootCredentialsBinding returns the CredentialsBinding referenced by a shoot.
+	// If the shoot references a SecretBinding instead, nil is returned.
+	GetShootCredentialsBinding(ctx context.Context, namespace, name string) (*CredentialsBinding, error)
+	// GetWorkloadIdentity returns a Gardener workloadidentity resource
+	GetWorkloadIdentity(ctx context.Context, namespace, name string) (*WorkloadIdentity, error)
+	// CreateWorkloadIdentityToken requests a token for a workload identity, which expires after the given duration.
+	// The request is sent to the workloadidentities/token subresource.
+	CreateWorkloadIdentityToken(ctx context.Context, namespace, name string, expiration time.Duration) (*WorkloadIdentityToken, error)
+
+	// CheckAccess verifies with a SelfSubjectAccessReview that the user is allowed to perform the given action.
+	// If the action is not allowed, an error wrapping ErrAccessDenied is returned.
+	CheckAccess(ctx context.Context, attributes authorizationv1.ResourceAttributes) error
+
 	// RuntimeClient returns the underlying kubernetes runtime client
 	// TODO: Remove this when we switched all APIs to the new gardenclient
 	RuntimeClient() client.Client
@@ -83,6 +132,8 @@ type Client interface {
 
 type clientImpl struct {
 	c client.Client
+	// clientConfig is used to create a REST client for subresources that are not supported by the runtime client
+	clientConfig clientcmd.ClientConfig
 }
 
 // NewGardenClient returns a new gardenclient
@@ -92,6 +143,14 @@ func NewGardenClient(client client.Client) Client {
 	}
 }
 
+// NewGardenClientWithClientConfig returns a new gardenclient that is also able to request subresources like shoots/adminkubeconfig
+func NewGardenClientWithClientConfig(client client.Client, clientConfig clientcmd.ClientConfig) Client {
+	return &clientImpl{
+		c:            client,
+		clientConfig: clientConfig,
+	}
+}
+
 func (g *clientImpl) GetProject(ctx context.Context, name string) (*gardencorev1beta1.Project, error) {
 	project := &gardencorev1beta1.Project{}
 	key := types.NamespacedName{Name: name}
@@ -168,7 +227,13 @@ func (g *clientImpl) FindShoot(ctx context.Context, opts ...client.ListOption) (
 	}
 
 	if len(shootList.Items) == 0 {
-		return nil, fmt.Errorf("no shoot found matching the given list options %q", opts)
+		// the error is reported as NotFound status, so that it can be told apart from failed requests
+		return nil, &apierrors.StatusError{ErrStatus: metav1.Status{
+			Status:  metav1.StatusFailure,
+			Code:    http.StatusNotFound,
+			Reason:  metav1.StatusReasonNotFound,
+			Message: fmt.Sprintf("no shoot found matching the given list options %q", opts),
+		}}
 	}
 
 	var remainingItemCount int64 = 0
@@ -233,6 +298,38 @@ func (g *clientImpl) GetSecretBinding(ctx context.Context, namespace, name strin
 	return secretBinding, nil
 }
 
+// ListSecretBindings returns all Gardener secretbinding resources in a namespace
+func (g *clientImpl) ListSecretBindings(ctx context.Context, namespace string) (*gardencorev1beta1.SecretBindingList, error) {
+	secretBindingList := &gardencorev1beta1.SecretBindingList{}
+	if err := g.c.List(ctx, secretBindingList, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list secretbindings in namespace %s: %w", namespace, err)
+	}
+
+	return secretBindingList, nil
+}
+
+// GetBastion returns a Gardener bastion resource in a namespace by name
+func (g *clientImpl) GetBastion(ctx context.Context, namespace, name string) (*operationsv1alpha1.Bastion, error) {
+	bastion := &operationsv1alpha1.Bastion{}
+	key := types.NamespacedName{Namespace: namespace, Name: name}
+
+	if err := g.c.Get(ctx, key, bastion); err != nil {
+		return nil, fmt.Errorf("failed to get bastion %v: %w", key, err)
+	}
+
+	return bastion, nil
+}
+
+// ListBastions returns all Gardener bastion resources, filtered by a list option
+func (g *clientImpl) ListBastions(ctx context.Context, opts ...client.ListOption) (*operationsv1alpha1.BastionList, error) {
+	bastionList := &operationsv1alpha1.BastionList{}
+	if err := g.c.List(ctx, bastionList, opts...); err != nil {
+		return nil, fmt.Errorf("failed to list bastions with list options %q: %w", opts, err)
+	}
+
+	return bastionList, nil
+}
+
 // GetSecret returns a Kubernetes secret resource
 func (g *clientImpl) GetSecret(ctx context.Context, namespace, name string) (*corev1.Secret, error) {
 	secret := &corev1.Secret{}
@@ -315,6 +412,130 @@ func (g *clientImpl) GetCloudProfile(ctx context.Context, name string) (*gardenc
 	return cloudProfile, nil
 }
 
+func (g *clientImpl) ListCloudProfiles(ctx context.Context, opts ...client.ListOption) (*gardencorev1beta1.CloudProfileList, error) {
+	cloudProfileList := &gardencorev1beta1.CloudProfileList{}
+	if err := g.c.List(ctx, cloudProfileList, opts...); err != nil {
+		return nil, fmt.Errorf("failed to list cloudprofiles: %w", err)
+	}
+
+	return cloudProfileList, nil
+}
+
+func (g *clientImpl) CreateAdminKubeconfigRequest(ctx context.Context, namespace, name string, expiration time.Duration) (*authenticationv1alpha1.AdminKubeconfigRequest, error) {
+	return g.createKubeconfigRequest(ctx, namespace, name, "adminkubeconfig", "AdminKubeconfigRequest", expiration)
+}
+
+func (g *clientImpl) CreateViewerKubeconfigRequest(ctx context.Context, namespace, name string, expiration time.Duration) (*authenticationv1alpha1.AdminKubeconfigRequest, error) {
+	return g.createKubeconfigRequest(ctx, namespace, name, "viewerkubeconfig", "ViewerKubeconfigRequest", expiration)
+}
+
+// createKubeconfigRequest sends a kubeconfig request of the given kind to the given subresource of a shoot.
+// AdminKubeconfigRequest and ViewerKubeconfigRequest share the same spec and status, hence the AdminKubeconfigRequest type is used for both.
+func (g *clientImpl) createKubeconfigRequest(ctx context.Context, namespace, name, subresource, kind string, expiration time.Duration) (*authenticationv1alpha1.AdminKubeconfigRequest, error) {
+	key := types.NamespacedName{Namespace: namespace, Name: name}
+
+	restClient, err := g.restClient(gardencorev1beta1.SchemeGroupVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s for shoot %v: %w", kind, key, err)
+	}
+
+	expirationSeconds := int64(expiration.Seconds())
+	request := &authenticationv1alpha1.AdminKubeconfigRequest{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: authenticationv1alpha1.SchemeGroupVersion.String(),
+			Kind:       kind,
+		},
+		Spec: authenticationv1alpha1.AdminKubeconfigRequestSpec{
+			ExpirationSeconds: &expirationSeconds,
+		},
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", kind, err)
+	}
+
+	data, err := restClient.Post().
+		Namespace(namespace).
+		Resource("shoots").
+		Name(name).
+		SubResource(subresource).
+		Body(body).
+		Do(ctx).
+		Raw()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s for shoot %v: %w", kind, key, err)
+	}
+
+	result := &authenticationv1alpha1.AdminKubeconfigRequest{}
+	if err := json.Unmarshal(data, result); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", kind, err)
+	}
+
+	return result, nil
+}
+
+// restClient returns a REST client for the given group version, which is used for subresources that are not supported by the runtime client
+func (g *clientImpl) restClient(groupVersion schema.GroupVersion) (*rest.RESTClient, error) {
+	if g.clientConfig == nil {
+		return nil, errors.New("no client configuration available")
+	}
+
+	config, err := g.clientConfig.ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create restclient config: %w", err)
+	}
+
+	config = rest.CopyConfig(config)
+	config.APIPath = "/apis"
+	config.GroupVersion = &groupVersion
+	config.ContentType = runtime.ContentTypeJSON
+	config.NegotiatedSerializer = authenticationCodecs.WithoutConversion()
+
+	restClient, err := rest.RESTClientFor(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create restclient: %w", err)
+	}
+
+	return restClient, nil
+}
+
+func (g *clientImpl) CheckAccess(ctx context.Context, attributes authorizationv1.ResourceAttributes) error {
+	review := &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &attributes,
+		},
+	}
+
+	if err := g.c.Create(ctx, review); err != nil {
+		return fmt.Errorf("failed to create self subject access review: %w", err)
+	}
+
+	if review.Status.Allowed {
+		return nil
+	}
+
+	resource := attributes.Resource
+	if attributes.Subresource != "" {
+		resource += "/" + attributes.Subresource
+	}
+
+	if attributes.Group != "" {
+		resource += "." + attributes.Group
+	}
+
+	target := fmt.Sprintf("%s %q", resource, attributes.Name)
+	if attributes.Namespace != "" {
+		target += fmt.Sprintf(" in namespace %q", attributes.Namespace)
+	}
+
+	if review.Status.Reason != "" {
+		return fmt.Errorf("%w: you are not allowed to %s %s: %s", ErrAccessDenied, attributes.Verb, target, review.Status.Reason)
+	}
+
+	return fmt.Errorf("%w: you are not allowed to %s %s", ErrAccessDenied, attributes.Verb, target)
+}
+
 // RuntimeClient returns the underlying Kubernetes runtime client
 func (g *clientImpl) RuntimeClient() client.Client {
 	return g.c
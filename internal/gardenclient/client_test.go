@@ -9,14 +9,17 @@ package gardenclient_test
 import (
 	"context"
 
+	operationsv1alpha1 "github.com/gardener/gardener/pkg/apis/operations/v1alpha1"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/client-go/tools/clientcmd"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/gardener/gardenctl-v2/internal/fake"
 	"github.com/gardener/gardenctl-v2/internal/gardenclient"
@@ -74,6 +77,91 @@ var _ = Describe("Client", func() {
 			})
 		})
 	})
+
+	Describe("ListBastions", func() {
+		It("should list the bastions matching the list options", func() {
+			ctx := context.Background()
+			newBastion := func(namespace, name string, labels map[string]string) *operationsv1alpha1.Bastion {
+				return &operationsv1alpha1.Bastion{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      name,
+						Namespace: namespace,
+						Labels:    labels,
+					},
+				}
+			}
+			managed := map[string]string{"app": "gardenctl"}
+			gardenClient := gardenclient.NewGardenClient(fake.NewClientWithObjects(
+				newBastion("garden-prod1", "cli-1", managed),
+				newBastion("garden-prod1", "other", nil),
+				newBastion("garden-prod2", "cli-2", managed),
+			))
+
+			bastions, err := gardenClient.ListBastions(ctx, client.InNamespace("garden-prod1"), client.MatchingLabels(managed))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(bastions.Items).To(HaveLen(1))
+			Expect(bastions.Items[0].Name).To(Equal("cli-1"))
+
+			bastion, err := gardenClient.GetBastion(ctx, "garden-prod2", "cli-2")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(bastion.Name).To(Equal("cli-2"))
+		})
+	})
+
+	Describe("GetShootCredentialsBinding", func() {
+		var (
+			ctx   context.Context
+			shoot *unstructured.Unstructured
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			shoot = &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "core.gardener.cloud/v1beta1",
+				"kind":       "Shoot",
+				"metadata": map[string]interface{}{
+					"name":      "shoot",
+					"namespace": "garden-prod",
+				},
+				"spec": map[string]interface{}{
+					"credentialsBindingName": "credentials-binding",
+				},
+			}}
+		})
+
+		It("should return the CredentialsBinding referenced by the shoot", func() {
+			credentialsBinding := &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "security.gardener.cloud/v1alpha1",
+				"kind":       "CredentialsBinding",
+				"metadata": map[string]interface{}{
+					"name":      "credentials-binding",
+					"namespace": "garden-prod",
+				},
+				"credentialsRef": map[string]interface{}{
+					"apiVersion": "security.gardener.cloud/v1alpha1",
+					"kind":       "WorkloadIdentity",
+					"namespace":  "garden-prod",
+					"name":       "workload-identity",
+				},
+			}}
+			gardenClient := gardenclient.NewGardenClient(fake.NewClientWithObjects(shoot, credentialsBinding))
+
+			result, err := gardenClient.GetShootCredentialsBinding(ctx, "garden-prod", "shoot")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Name).To(Equal("credentials-binding"))
+			Expect(result.CredentialsRef.Kind).To(Equal("WorkloadIdentity"))
+			Expect(result.CredentialsRef.Name).To(Equal("workload-identity"))
+		})
+
+		It("should return nil if the shoot does not reference a CredentialsBinding", func() {
+			unstructured.RemoveNestedField(shoot.Object, "spec", "credentialsBindingName")
+			gardenClient := gardenclient.NewGardenClient(fake.NewClientWithObjects(shoot))
+
+			result, err := gardenClient.GetShootCredentialsBinding(ctx, "garden-prod", "shoot")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(BeNil())
+		})
+	})
 })
 
 // TODO copied from target_suite_test. Move into a test helper package for better reuse
@@ -9,10 +9,17 @@ package gardenclient_test
 import (
 	"testing"
 
+	operationsv1alpha1 "github.com/gardener/gardener/pkg/apis/operations/v1alpha1"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
 )
 
+func init() {
+	utilruntime.Must(operationsv1alpha1.AddToScheme(scheme.Scheme))
+}
+
 func TestCloudEnvCommand(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Gardenclient Test Suite")
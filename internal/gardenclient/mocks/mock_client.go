@@ -7,10 +7,15 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
+	gardenclient "github.com/gardener/gardenctl-v2/internal/gardenclient"
+	v1alpha1 "github.com/gardener/gardener/pkg/apis/authentication/v1alpha1"
 	v1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	v1alpha10 "github.com/gardener/gardener/pkg/apis/operations/v1alpha1"
 	gomock "github.com/golang/mock/gomock"
-	v1 "k8s.io/api/core/v1"
+	v1 "k8s.io/api/authorization/v1"
+	v10 "k8s.io/api/core/v1"
 	clientcmd "k8s.io/client-go/tools/clientcmd"
 	client "sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -38,6 +43,65 @@ func (m *MockClient) EXPECT() *MockClientMockRecorder {
 	return m.recorder
 }
 
+// CheckAccess mocks base method.
+func (m *MockClient) CheckAccess(arg0 context.Context, arg1 v1.ResourceAttributes) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckAccess", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckAccess indicates an expected call of CheckAccess.
+func (mr *MockClientMockRecorder) CheckAccess(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckAccess", reflect.TypeOf((*MockClient)(nil).CheckAccess), arg0, arg1)
+}
+
+// CreateAdminKubeconfigRequest mocks base method.
+func (m *MockClient) CreateAdminKubeconfigRequest(arg0 context.Context, arg1, arg2 string, arg3 time.Duration) (*v1alpha1.AdminKubeconfigRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAdminKubeconfigRequest", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*v1alpha1.AdminKubeconfigRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAdminKubeconfigRequest indicates an expected call of CreateAdminKubeconfigRequest.
+func (mr *MockClientMockRecorder) CreateAdminKubeconfigRequest(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAdminKubeconfigRequest", reflect.TypeOf((*MockClient)(nil).CreateAdminKubeconfigRequest), arg0, arg1, arg2, arg3)
+}
+
+// CreateViewerKubeconfigRequest mocks base method.
+func (m *MockClient) CreateViewerKubeconfigRequest(arg0 context.Context, arg1, arg2 string, arg3 time.Duration) (*v1alpha1.AdminKubeconfigRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateViewerKubeconfigRequest", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*v1alpha1.AdminKubeconfigRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateViewerKubeconfigRequest indicates an expected call of CreateViewerKubeconfigRequest.
+func (mr *MockClientMockRecorder) CreateViewerKubeconfigRequest(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateViewerKubeconfigRequest", reflect.TypeOf((*MockClient)(nil).CreateViewerKubeconfigRequest), arg0, arg1, arg2, arg3)
+}
+
+// CreateWorkloadIdentityToken mocks base method.
+func (m *MockClient) CreateWorkloadIdentityToken(arg0 context.Context, arg1, arg2 string, arg3 time.Duration) (*gardenclient.WorkloadIdentityToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWorkloadIdentityToken", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*gardenclient.WorkloadIdentityToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWorkloadIdentityToken indicates an expected call of CreateWorkloadIdentityToken.
+func (mr *MockClientMockRecorder) CreateWorkloadIdentityToken(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWorkloadIdentityToken", reflect.TypeOf((*MockClient)(nil).CreateWorkloadIdentityToken), arg0, arg1, arg2, arg3)
+}
+
 // FindShoot mocks base method.
 func (m *MockClient) FindShoot(arg0 context.Context, arg1 ...client.ListOption) (*v1beta1.Shoot, error) {
 	m.ctrl.T.Helper()
@@ -58,6 +122,21 @@ func (mr *MockClientMockRecorder) FindShoot(arg0 interface{}, arg1 ...interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindShoot", reflect.TypeOf((*MockClient)(nil).FindShoot), varargs...)
 }
 
+// GetBastion mocks base method.
+func (m *MockClient) GetBastion(arg0 context.Context, arg1, arg2 string) (*v1alpha10.Bastion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBastion", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*v1alpha10.Bastion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBastion indicates an expected call of GetBastion.
+func (mr *MockClientMockRecorder) GetBastion(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBastion", reflect.TypeOf((*MockClient)(nil).GetBastion), arg0, arg1, arg2)
+}
+
 // GetCloudProfile mocks base method.
 func (m *MockClient) GetCloudProfile(arg0 context.Context, arg1 string) (*v1beta1.CloudProfile, error) {
 	m.ctrl.T.Helper()
@@ -74,10 +153,10 @@ func (mr *MockClientMockRecorder) GetCloudProfile(arg0, arg1 interface{}) *gomoc
 }
 
 // GetConfigMap mocks base method.
-func (m *MockClient) GetConfigMap(arg0 context.Context, arg1, arg2 string) (*v1.ConfigMap, error) {
+func (m *MockClient) GetConfigMap(arg0 context.Context, arg1, arg2 string) (*v10.ConfigMap, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetConfigMap", arg0, arg1, arg2)
-	ret0, _ := ret[0].(*v1.ConfigMap)
+	ret0, _ := ret[0].(*v10.ConfigMap)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -89,10 +168,10 @@ func (mr *MockClientMockRecorder) GetConfigMap(arg0, arg1, arg2 interface{}) *go
 }
 
 // GetNamespace mocks base method.
-func (m *MockClient) GetNamespace(arg0 context.Context, arg1 string) (*v1.Namespace, error) {
+func (m *MockClient) GetNamespace(arg0 context.Context, arg1 string) (*v10.Namespace, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetNamespace", arg0, arg1)
-	ret0, _ := ret[0].(*v1.Namespace)
+	ret0, _ := ret[0].(*v10.Namespace)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -134,10 +213,10 @@ func (mr *MockClientMockRecorder) GetProjectByNamespace(arg0, arg1 interface{})
 }
 
 // GetSecret mocks base method.
-func (m *MockClient) GetSecret(arg0 context.Context, arg1, arg2 string) (*v1.Secret, error) {
+func (m *MockClient) GetSecret(arg0 context.Context, arg1, arg2 string) (*v10.Secret, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetSecret", arg0, arg1, arg2)
-	ret0, _ := ret[0].(*v1.Secret)
+	ret0, _ := ret[0].(*v10.Secret)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -223,6 +302,76 @@ func (mr *MockClientMockRecorder) GetShootClientConfig(arg0, arg1, arg2 interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShootClientConfig", reflect.TypeOf((*MockClient)(nil).GetShootClientConfig), arg0, arg1, arg2)
 }
 
+// GetShootCredentialsBinding mocks base method.
+func (m *MockClient) GetShootCredentialsBinding(arg0 context.Context, arg1, arg2 string) (*gardenclient.CredentialsBinding, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetShootCredentialsBinding", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*gardenclient.CredentialsBinding)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetShootCredentialsBinding indicates an expected call of GetShootCredentialsBinding.
+func (mr *MockClientMockRecorder) GetShootCredentialsBinding(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShootCredentialsBinding", reflect.TypeOf((*MockClient)(nil).GetShootCredentialsBinding), arg0, arg1, arg2)
+}
+
+// GetWorkloadIdentity mocks base method.
+func (m *MockClient) GetWorkloadIdentity(arg0 context.Context, arg1, arg2 string) (*gardenclient.WorkloadIdentity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkloadIdentity", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*gardenclient.WorkloadIdentity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkloadIdentity indicates an expected call of GetWorkloadIdentity.
+func (mr *MockClientMockRecorder) GetWorkloadIdentity(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkloadIdentity", reflect.TypeOf((*MockClient)(nil).GetWorkloadIdentity), arg0, arg1, arg2)
+}
+
+// ListBastions mocks base method.
+func (m *MockClient) ListBastions(arg0 context.Context, arg1 ...client.ListOption) (*v1alpha10.BastionList, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListBastions", varargs...)
+	ret0, _ := ret[0].(*v1alpha10.BastionList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBastions indicates an expected call of ListBastions.
+func (mr *MockClientMockRecorder) ListBastions(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBastions", reflect.TypeOf((*MockClient)(nil).ListBastions), varargs...)
+}
+
+// ListCloudProfiles mocks base method.
+func (m *MockClient) ListCloudProfiles(arg0 context.Context, arg1 ...client.ListOption) (*v1beta1.CloudProfileList, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListCloudProfiles", varargs...)
+	ret0, _ := ret[0].(*v1beta1.CloudProfileList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCloudProfiles indicates an expected call of ListCloudProfiles.
+func (mr *MockClientMockRecorder) ListCloudProfiles(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCloudProfiles", reflect.TypeOf((*MockClient)(nil).ListCloudProfiles), varargs...)
+}
+
 // ListProjects mocks base method.
 func (m *MockClient) ListProjects(arg0 context.Context, arg1 ...client.ListOption) (*v1beta1.ProjectList, error) {
 	m.ctrl.T.Helper()
@@ -243,6 +392,21 @@ func (mr *MockClientMockRecorder) ListProjects(arg0 interface{}, arg1 ...interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListProjects", reflect.TypeOf((*MockClient)(nil).ListProjects), varargs...)
 }
 
+// ListSecretBindings mocks base method.
+func (m *MockClient) ListSecretBindings(arg0 context.Context, arg1 string) (*v1beta1.SecretBindingList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSecretBindings", arg0, arg1)
+	ret0, _ := ret[0].(*v1beta1.SecretBindingList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSecretBindings indicates an expected call of ListSecretBindings.
+func (mr *MockClientMockRecorder) ListSecretBindings(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSecretBindings", reflect.TypeOf((*MockClient)(nil).ListSecretBindings), arg0, arg1)
+}
+
 // ListSeeds mocks base method.
 func (m *MockClient) ListSeeds(arg0 context.Context, arg1 ...client.ListOption) (*v1beta1.SeedList, error) {
 	m.ctrl.T.Helper()
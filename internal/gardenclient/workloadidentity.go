@@ -0,0 +1,172 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gardenclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// SecurityGroupVersion is the group version of the Gardener security API.
+// The API is not part of the vendored Gardener API types, hence the resources are read as unstructured objects.
+var SecurityGroupVersion = schema.GroupVersion{Group: "security.gardener.cloud", Version: "v1alpha1"}
+
+// CredentialsBinding references the credentials of a cloud provider account, either a Secret or a WorkloadIdentity
+type CredentialsBinding struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// CredentialsRef is a reference to the credentials, i.e. a Secret or a WorkloadIdentity
+	CredentialsRef corev1.ObjectReference `json:"credentialsRef"`
+}
+
+// WorkloadIdentity is an identity of a cloud provider account that is trusted by the cloud provider
+// to exchange short-lived tokens issued by Gardener for cloud provider credentials
+type WorkloadIdentity struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Spec is the specification of the workload identity
+	Spec WorkloadIdentitySpec `json:"spec"`
+}
+
+// WorkloadIdentitySpec is the specification of a WorkloadIdentity
+type WorkloadIdentitySpec struct {
+	// Audiences are the audiences of the issued tokens
+	Audiences []string `json:"audiences,omitempty"`
+	// TargetSystem is the system that trusts the issued tokens
+	TargetSystem TargetSystem `json:"targetSystem"`
+}
+
+// TargetSystem is the system that trusts the tokens of a WorkloadIdentity
+type TargetSystem struct {
+	// Type is the cloud provider type, e.g. aws
+	Type string `json:"type"`
+	// ProviderConfig is the cloud provider specific configuration, e.g. the role ARN for aws
+	ProviderConfig *runtime.RawExtension `json:"providerConfig,omitempty"`
+}
+
+// WorkloadIdentityToken is a token issued for a WorkloadIdentity
+type WorkloadIdentityToken struct {
+	// Token is the issued token
+	Token string `json:"token"`
+	// ExpirationTimestamp is the time when the token expires
+	ExpirationTimestamp metav1.Time `json:"expirationTimestamp"`
+}
+
+// tokenRequest is the request sent to the workloadidentities/token subresource
+type tokenRequest struct {
+	metav1.TypeMeta `json:",inline"`
+	Spec            struct {
+		ExpirationSeconds *int64 `json:"expirationSeconds,omitempty"`
+	} `json:"spec"`
+	Status WorkloadIdentityToken `json:"status,omitempty"`
+}
+
+func (g *clientImpl) GetShootCredentialsBinding(ctx context.Context, namespace, name string) (*CredentialsBinding, error) {
+	key := types.NamespacedName{Namespace: namespace, Name: name}
+
+	shoot := &unstructured.Unstructured{}
+	shoot.SetGroupVersionKind(gardencorev1beta1.SchemeGroupVersion.WithKind("Shoot"))
+
+	if err := g.c.Get(ctx, key, shoot); err != nil {
+		return nil, fmt.Errorf("failed to get shoot %v: %w", key, err)
+	}
+
+	credentialsBindingName, _, err := unstructured.NestedString(shoot.Object, "spec", "credentialsBindingName")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read credentialsBindingName of shoot %v: %w", key, err)
+	}
+
+	if credentialsBindingName == "" {
+		return nil, nil
+	}
+
+	credentialsBinding := &CredentialsBinding{}
+	if err := g.getSecurityObject(ctx, namespace, credentialsBindingName, "CredentialsBinding", credentialsBinding); err != nil {
+		return nil, err
+	}
+
+	return credentialsBinding, nil
+}
+
+func (g *clientImpl) GetWorkloadIdentity(ctx context.Context, namespace, name string) (*WorkloadIdentity, error) {
+	workloadIdentity := &WorkloadIdentity{}
+	if err := g.getSecurityObject(ctx, namespace, name, "WorkloadIdentity", workloadIdentity); err != nil {
+		return nil, err
+	}
+
+	return workloadIdentity, nil
+}
+
+// getSecurityObject reads a resource of the Gardener security API and converts it into the given object
+func (g *clientImpl) getSecurityObject(ctx context.Context, namespace, name, kind string, obj interface{}) error {
+	key := types.NamespacedName{Namespace: namespace, Name: name}
+
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(SecurityGroupVersion.WithKind(kind))
+
+	if err := g.c.Get(ctx, key, u); err != nil {
+		return fmt.Errorf("failed to get %s %v: %w", kind, key, err)
+	}
+
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, obj); err != nil {
+		return fmt.Errorf("failed to convert %s %v: %w", kind, key, err)
+	}
+
+	return nil
+}
+
+func (g *clientImpl) CreateWorkloadIdentityToken(ctx context.Context, namespace, name string, expiration time.Duration) (*WorkloadIdentityToken, error) {
+	key := types.NamespacedName{Namespace: namespace, Name: name}
+
+	restClient, err := g.restClient(SecurityGroupVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request token for workload identity %v: %w", key, err)
+	}
+
+	expirationSeconds := int64(expiration.Seconds())
+	request := &tokenRequest{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: SecurityGroupVersion.String(),
+			Kind:       "TokenRequest",
+		},
+	}
+	request.Spec.ExpirationSeconds = &expirationSeconds
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode TokenRequest: %w", err)
+	}
+
+	data, err := restClient.Post().
+		Namespace(namespace).
+		Resource("workloadidentities").
+		Name(name).
+		SubResource("token").
+		Body(body).
+		Do(ctx).
+		Raw()
+	if err != nil {
+		return nil, fmt.Errorf("failed to request token for workload identity %v: %w", key, err)
+	}
+
+	result := &tokenRequest{}
+	if err := json.Unmarshal(data, result); err != nil {
+		return nil, fmt.Errorf("failed to decode TokenRequest: %w", err)
+	}
+
+	return &result.Status, nil
+}
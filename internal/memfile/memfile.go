@@ -0,0 +1,16 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package memfile passes sensitive data, e.g. kubeconfigs, to child processes without writing it to disk.
+package memfile
+
+import "strconv"
+
+// ChildPath returns the path under which a child process can read the file that has been passed as
+// exec.Cmd.ExtraFiles[index], e.g. /dev/fd/3 for the first extra file
+func ChildPath(index int) string {
+	return "/dev/fd/" + strconv.Itoa(3+index)
+}
@@ -0,0 +1,40 @@
+//go:build linux
+// +build linux
+
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package memfile
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// New returns an anonymous memory-backed file with the given content that can be passed to a child process.
+// On Linux, the child process can open the file as often as needed, e.g. if it reads its kubeconfig twice.
+func New(name string, data []byte) (*os.File, error) {
+	fd, err := unix.MemfdCreate(name, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create memory file: %w", err)
+	}
+
+	f := os.NewFile(uintptr(fd), name)
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to write memory file: %w", err)
+	}
+
+	if _, err := f.Seek(0, 0); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to write memory file: %w", err)
+	}
+
+	return f, nil
+}
@@ -0,0 +1,46 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package memfile_test
+
+import (
+	"io"
+	"os/exec"
+	"runtime"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/gardener/gardenctl-v2/internal/memfile"
+)
+
+var _ = Describe("Memfile", func() {
+	BeforeEach(func() {
+		if runtime.GOOS == "windows" {
+			Skip("memory files are not supported on windows")
+		}
+	})
+
+	It("should provide the content", func() {
+		f, err := memfile.New("kubeconfig", []byte("kind: Config\n"))
+		Expect(err).NotTo(HaveOccurred())
+		defer f.Close()
+
+		data, err := io.ReadAll(f)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(Equal("kind: Config\n"))
+	})
+
+	It("should pass the content to a child process", func() {
+		f, err := memfile.New("kubeconfig", []byte("kind: Config\n"))
+		Expect(err).NotTo(HaveOccurred())
+		defer f.Close()
+
+		cmd := exec.Command("cat", memfile.ChildPath(0))
+		cmd.ExtraFiles = append(cmd.ExtraFiles, f)
+		Expect(cmd.Output()).To(Equal([]byte("kind: Config\n")))
+	})
+})
@@ -0,0 +1,32 @@
+//go:build !linux && !windows
+// +build !linux,!windows
+
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package memfile
+
+import (
+	"fmt"
+	"os"
+)
+
+// New returns the read end of an anonymous pipe that provides the given content to a child process.
+// The content can only be read once.
+func New(name string, data []byte) (*os.File, error) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pipe: %w", err)
+	}
+
+	// the write fails once the read end is closed, e.g. because the child process exited without reading
+	go func() {
+		_, _ = w.Write(data)
+		_ = w.Close()
+	}()
+
+	return r, nil
+}
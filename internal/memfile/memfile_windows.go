@@ -0,0 +1,20 @@
+//go:build windows
+// +build windows
+
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package memfile
+
+import (
+	"errors"
+	"os"
+)
+
+// New is not supported on Windows, there is no path under which a child process can read an inherited file
+func New(name string, data []byte) (*os.File, error) {
+	return nil, errors.New("passing files to child processes in memory is not supported on windows")
+}
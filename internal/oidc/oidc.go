@@ -0,0 +1,291 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// ErrNotLoggedIn is returned if there is neither a valid nor a refreshable token for a garden
+var ErrNotLoggedIn = errors.New("not logged in")
+
+// Provider configures the OIDC provider that issues the tokens for a garden cluster
+type Provider struct {
+	// IssuerURL is the URL of the OIDC provider, it must match the issuer configured for the API server
+	IssuerURL string
+	// ClientID is the client ID of gardenctl at the OIDC provider
+	ClientID string
+	// ClientSecret is only required if the OIDC provider does not support public clients
+	ClientSecret string
+	// Scopes are requested in addition to the openid scope
+	Scopes []string
+}
+
+// LoginOptions configure the interactive login
+type LoginOptions struct {
+	// CallbackPort is the port of the local callback listener, a random port is used if 0
+	CallbackPort int
+	// OpenBrowser opens the authorization URL. The URL is only printed if it is nil or fails.
+	OpenBrowser func(url string) error
+	// Out receives the instructions for the user
+	Out io.Writer
+}
+
+type discoveryDocument struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+}
+
+// discover fetches the endpoints of the OIDC provider from its discovery document
+func discover(ctx context.Context, issuerURL string) (*oauth2.Endpoint, error) {
+	issuerURL = strings.TrimSuffix(issuerURL, "/")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, issuerURL+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch discovery document of OIDC provider %s: %w", issuerURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch discovery document of OIDC provider %s: %s", issuerURL, resp.Status)
+	}
+
+	doc := &discoveryDocument{}
+	if err := json.NewDecoder(resp.Body).Decode(doc); err != nil {
+		return nil, fmt.Errorf("failed to decode discovery document of OIDC provider %s: %w", issuerURL, err)
+	}
+
+	if strings.TrimSuffix(doc.Issuer, "/") != issuerURL {
+		return nil, fmt.Errorf("issuer %q of discovery document does not match issuer URL %q", doc.Issuer, issuerURL)
+	}
+
+	return &oauth2.Endpoint{
+		AuthURL:   doc.AuthorizationEndpoint,
+		TokenURL:  doc.TokenEndpoint,
+		AuthStyle: oauth2.AuthStyleAutoDetect,
+	}, nil
+}
+
+func (p Provider) oauth2Config(ctx context.Context, redirectURL string) (*oauth2.Config, error) {
+	endpoint, err := discover(ctx, p.IssuerURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &oauth2.Config{
+		ClientID:     p.ClientID,
+		ClientSecret: p.ClientSecret,
+		Endpoint:     *endpoint,
+		RedirectURL:  redirectURL,
+		Scopes:       append([]string{"openid"}, p.Scopes...),
+	}, nil
+}
+
+type callbackResult struct {
+	code string
+	err  error
+}
+
+// Login runs the OIDC authorization code flow with PKCE. The authorization URL is opened in the browser
+// and the authorization code is received by a listener on the loopback interface.
+func Login(ctx context.Context, provider Provider, opts LoginOptions) (*Token, error) {
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", opts.CallbackPort))
+	if err != nil {
+		return nil, fmt.Errorf("failed to start callback listener: %w", err)
+	}
+	defer listener.Close()
+
+	// the redirect URL uses the address of the listener, localhost could resolve to ::1 first
+	redirectURL := fmt.Sprintf("http://127.0.0.1:%d/callback", listener.Addr().(*net.TCPAddr).Port)
+
+	config, err := provider.oauth2Config(ctx, redirectURL)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := randomString()
+	if err != nil {
+		return nil, err
+	}
+
+	verifier, err := randomString()
+	if err != nil {
+		return nil, err
+	}
+
+	challenge := sha256.Sum256([]byte(verifier))
+	authURL := config.AuthCodeURL(state,
+		oauth2.SetAuthURLParam("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:])),
+		oauth2.SetAuthURLParam("code_challenge_method", "S256"),
+	)
+
+	results := make(chan callbackResult, 1)
+	server := &http.Server{Handler: callbackHandler(state, results)}
+
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	defer func() {
+		_ = server.Close()
+	}()
+
+	if opts.OpenBrowser == nil || opts.OpenBrowser(authURL) != nil {
+		fmt.Fprintf(opts.Out, "Open the following URL in your browser to log in:\n\n%s\n\n", authURL)
+	} else {
+		fmt.Fprintf(opts.Out, "Your browser has been opened to log in. If it did not open, visit the following URL:\n\n%s\n\n", authURL)
+	}
+
+	var result callbackResult
+
+	select {
+	case result = <-results:
+	case <-ctx.Done():
+		return nil, fmt.Errorf("login has not been completed: %w", ctx.Err())
+	}
+
+	if result.err != nil {
+		return nil, result.err
+	}
+
+	oauth2Token, err := config.Exchange(ctx, result.code, oauth2.SetAuthURLParam("code_verifier", verifier))
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange authorization code: %w", err)
+	}
+
+	return tokenFromOAuth2(oauth2Token)
+}
+
+// callbackHandler receives the redirect of the OIDC provider and sends the authorization code or the error to results.
+// Requests that are not a response to the authorization request, e.g. for a favicon, are ignored.
+func callbackHandler(state string, results chan<- callbackResult) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/callback", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("state") != state {
+			http.Error(w, "invalid state", http.StatusBadRequest)
+			return
+		}
+
+		result := callbackResult{code: query.Get("code")}
+
+		switch {
+		case query.Get("error") != "":
+			result.err = fmt.Errorf("authorization failed: %s %s", query.Get("error"), query.Get("error_description"))
+		case result.code == "":
+			result.err = errors.New("authorization failed: the response contains no authorization code")
+		}
+
+		if result.err != nil {
+			http.Error(w, result.err.Error(), http.StatusUnauthorized)
+		} else {
+			fmt.Fprintln(w, "Login successful, you can close this window and return to gardenctl.")
+		}
+
+		select {
+		case results <- result:
+		default:
+		}
+	})
+
+	return mux
+}
+
+// Refresh gets a new ID token with the refresh token of the given token
+func Refresh(ctx context.Context, provider Provider, token *Token) (*Token, error) {
+	if token == nil || token.RefreshToken == "" {
+		return nil, errors.New("no refresh token available")
+	}
+
+	config, err := provider.oauth2Config(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	oauth2Token, err := config.TokenSource(ctx, &oauth2.Token{RefreshToken: token.RefreshToken}).Token()
+	if err != nil {
+		return nil, fmt.Errorf("failed to refresh token: %w", err)
+	}
+
+	return tokenFromOAuth2(oauth2Token)
+}
+
+// ValidToken returns a valid token with the given key from the cache. An expired token is refreshed
+// and the refreshed token is stored in the cache. ErrNotLoggedIn is returned if there is no token that
+// can be used.
+func ValidToken(ctx context.Context, cache *TokenCache, key string, provider Provider, now time.Time) (*Token, error) {
+	token, err := cache.Load(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if token.Valid(now) {
+		return token, nil
+	}
+
+	if token == nil || token.RefreshToken == "" {
+		return nil, ErrNotLoggedIn
+	}
+
+	refreshed, err := Refresh(ctx, provider, token)
+	if err != nil {
+		return nil, fmt.Errorf("%w, the token has expired and could not be refreshed: %v", ErrNotLoggedIn, err)
+	}
+
+	if err := cache.Save(key, refreshed); err != nil {
+		return nil, err
+	}
+
+	return refreshed, nil
+}
+
+func tokenFromOAuth2(oauth2Token *oauth2.Token) (*Token, error) {
+	idToken, ok := oauth2Token.Extra("id_token").(string)
+	if !ok || idToken == "" {
+		return nil, errors.New("the token response contains no id_token")
+	}
+
+	expiry, err := idTokenExpiry(idToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Token{
+		IDToken:      idToken,
+		RefreshToken: oauth2Token.RefreshToken,
+		Expiry:       expiry,
+	}, nil
+}
+
+// randomString returns a random URL safe string that is used as state and PKCE code verifier
+func randomString() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate random string: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,148 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package oidc_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	internalfake "github.com/gardener/gardenctl-v2/internal/fake"
+	"github.com/gardener/gardenctl-v2/internal/oidc"
+)
+
+var _ = Describe("OIDC", func() {
+	var (
+		ctx      context.Context
+		cancel   context.CancelFunc
+		idp      *internalfake.OIDCProvider
+		provider oidc.Provider
+		cache    *oidc.TokenCache
+	)
+
+	BeforeEach(func() {
+		ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
+		idp = internalfake.NewOIDCProvider("gardenctl")
+		provider = oidc.Provider{IssuerURL: idp.URL, ClientID: "gardenctl"}
+
+		dir, err := os.MkdirTemp("", "gctlv2-tokens-")
+		Expect(err).NotTo(HaveOccurred())
+		cache = oidc.NewTokenCache(dir)
+	})
+
+	AfterEach(func() {
+		cancel()
+		idp.Close()
+		Expect(os.RemoveAll(cache.Dir)).To(Succeed())
+	})
+
+	Describe("Login", func() {
+		It("should get a token with the authorization code flow", func() {
+			out := &bytes.Buffer{}
+			token, err := oidc.Login(ctx, provider, oidc.LoginOptions{OpenBrowser: idp.Browser, Out: out})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(token.IDToken).NotTo(BeEmpty())
+			Expect(token.RefreshToken).To(Equal(idp.RefreshToken()))
+			Expect(token.Valid(time.Now())).To(BeTrue())
+			Expect(token.Expiry).To(BeTemporally("~", time.Now().Add(time.Hour), 5*time.Second))
+			Expect(idp.Issued()).To(Equal(1))
+			Expect(out.String()).To(ContainSubstring("Your browser has been opened to log in"))
+			Expect(out.String()).To(ContainSubstring(idp.URL + "/authorize?"))
+		})
+
+		It("should print the URL if the browser cannot be opened", func() {
+			out := &bytes.Buffer{}
+			shortCtx, shortCancel := context.WithTimeout(ctx, 100*time.Millisecond)
+			defer shortCancel()
+
+			_, err := oidc.Login(shortCtx, provider, oidc.LoginOptions{
+				OpenBrowser: func(url string) error { return errors.New("no browser") },
+				Out:         out,
+			})
+			Expect(err).To(MatchError(ContainSubstring("login has not been completed")))
+			Expect(out.String()).To(HavePrefix("Open the following URL in your browser to log in:"))
+		})
+
+		It("should fail if the issuer does not match", func() {
+			provider.IssuerURL = idp.URL + "/other"
+			_, err := oidc.Login(ctx, provider, oidc.LoginOptions{OpenBrowser: idp.Browser, Out: &bytes.Buffer{}})
+			Expect(err).To(MatchError(ContainSubstring("failed to fetch discovery document")))
+		})
+	})
+
+	Describe("ValidToken", func() {
+		It("should fail if there is no cached token", func() {
+			_, err := oidc.ValidToken(ctx, cache, "garden", provider, time.Now())
+			Expect(errors.Is(err, oidc.ErrNotLoggedIn)).To(BeTrue())
+		})
+
+		It("should return a cached token that has not expired", func() {
+			token := &oidc.Token{IDToken: "id", Expiry: time.Now().Add(time.Hour)}
+			Expect(cache.Save("garden", token)).To(Succeed())
+
+			cached, err := oidc.ValidToken(ctx, cache, "garden", provider, time.Now())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cached.IDToken).To(Equal("id"))
+			Expect(idp.Refreshed()).To(BeZero())
+		})
+
+		It("should refresh and cache an expired token", func() {
+			token, err := oidc.Login(ctx, provider, oidc.LoginOptions{OpenBrowser: idp.Browser, Out: &bytes.Buffer{}})
+			Expect(err).NotTo(HaveOccurred())
+			token.Expiry = time.Now().Add(-time.Minute)
+			Expect(cache.Save("garden", token)).To(Succeed())
+
+			refreshed, err := oidc.ValidToken(ctx, cache, "garden", provider, time.Now())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(refreshed.Valid(time.Now())).To(BeTrue())
+			Expect(refreshed.RefreshToken).To(Equal(idp.RefreshToken()))
+			Expect(idp.Refreshed()).To(Equal(1))
+
+			cached, err := cache.Load("garden")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cached.IDToken).To(Equal(refreshed.IDToken))
+			Expect(cached.RefreshToken).To(Equal(refreshed.RefreshToken))
+		})
+
+		It("should fail if an expired token cannot be refreshed", func() {
+			token := &oidc.Token{IDToken: "id", RefreshToken: "revoked", Expiry: time.Now().Add(-time.Minute)}
+			Expect(cache.Save("garden", token)).To(Succeed())
+
+			_, err := oidc.ValidToken(ctx, cache, "garden", provider, time.Now())
+			Expect(errors.Is(err, oidc.ErrNotLoggedIn)).To(BeTrue())
+			Expect(err).To(MatchError(ContainSubstring("the token has expired and could not be refreshed")))
+		})
+	})
+
+	Describe("TokenCache", func() {
+		It("should save, load and delete tokens", func() {
+			token := &oidc.Token{IDToken: "id", RefreshToken: "refresh", Expiry: time.Unix(1700000000, 0)}
+			Expect(cache.Save("garden", token)).To(Succeed())
+
+			info, err := os.Stat(cache.Filename("garden"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+
+			cached, err := cache.Load("garden")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cached.IDToken).To(Equal("id"))
+			Expect(cached.RefreshToken).To(Equal("refresh"))
+			Expect(cached.Expiry.Equal(token.Expiry)).To(BeTrue())
+
+			Expect(cache.Delete("garden")).To(Succeed())
+			Expect(cache.Delete("garden")).To(Succeed())
+			cached, err = cache.Load("garden")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cached).To(BeNil())
+		})
+	})
+})
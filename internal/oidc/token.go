@@ -0,0 +1,150 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package oidc
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// expirySkew is subtracted from the expiry of a token, so that a token is not sent to an API server shortly before it expires
+const expirySkew = 30 * time.Second
+
+// Token holds the tokens that have been issued to gardenctl by an OIDC provider
+type Token struct {
+	// IDToken is sent as bearer token to the API server
+	IDToken string `json:"idToken"`
+	// RefreshToken is used to get a new ID token without user interaction once the ID token expired
+	// +optional
+	RefreshToken string `json:"refreshToken,omitempty"`
+	// Expiry is the time the ID token expires
+	Expiry time.Time `json:"expiry"`
+}
+
+// Valid returns true if the ID token has not yet expired at the given time
+func (t *Token) Valid(now time.Time) bool {
+	return t != nil && t.IDToken != "" && now.Before(t.Expiry.Add(-expirySkew))
+}
+
+// idTokenExpiry returns the expiry of an ID token, which is taken from its exp claim.
+// The signature of the token is not verified, it has been received directly from the token endpoint.
+func idTokenExpiry(idToken string) (time.Time, error) {
+	parts := strings.Split(idToken, ".")
+	if len(parts) != 3 {
+		return time.Time{}, errors.New("id token is not a JWT")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to decode id token payload: %w", err)
+	}
+
+	claims := struct {
+		Exp int64 `json:"exp"`
+	}{}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return time.Time{}, fmt.Errorf("failed to decode id token claims: %w", err)
+	}
+
+	if claims.Exp == 0 {
+		return time.Time{}, errors.New("id token has no exp claim")
+	}
+
+	return time.Unix(claims.Exp, 0), nil
+}
+
+// TokenCache stores the tokens of the gardens in a directory, one file per cluster identity of a garden.
+// The tokens are not stored by the name of the garden, so that they are kept if a garden is renamed.
+type TokenCache struct {
+	// Dir is the directory the tokens are stored in
+	Dir string
+}
+
+// NewTokenCache returns a TokenCache for the given directory
+func NewTokenCache(dir string) *TokenCache {
+	return &TokenCache{Dir: dir}
+}
+
+// Filename returns the name of the file the token with the given key is stored in. The key is hashed, as
+// it may contain characters that are not allowed in file names, e.g. if it is the server URL of a garden.
+func (c *TokenCache) Filename(key string) string {
+	return filepath.Join(c.Dir, c.basename(key)+".json")
+}
+
+func (c *TokenCache) basename(key string) string {
+	return fmt.Sprintf("token-%x", sha256.Sum256([]byte(key)))
+}
+
+// Load returns the cached token with the given key or nil if there is no cached token
+func (c *TokenCache) Load(key string) (*Token, error) {
+	data, err := os.ReadFile(c.Filename(key))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("failed to read cached token: %w", err)
+	}
+
+	token := &Token{}
+	if err := json.Unmarshal(data, token); err != nil {
+		return nil, fmt.Errorf("failed to decode cached token: %w", err)
+	}
+
+	return token, nil
+}
+
+// Save stores the token with the given key. The file is only readable by the current user.
+func (c *TokenCache) Save(key string, token *Token) error {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return fmt.Errorf("failed to encode token: %w", err)
+	}
+
+	if err := os.MkdirAll(c.Dir, 0700); err != nil {
+		return fmt.Errorf("failed to create token cache directory: %w", err)
+	}
+
+	// write to a temporary file first, so that concurrent readers never see a partially written token
+	tmp, err := os.CreateTemp(c.Dir, "."+c.basename(key)+"-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to write token: %w", err)
+	}
+
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write token: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write token: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), c.Filename(key)); err != nil {
+		return fmt.Errorf("failed to write token: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes the cached token with the given key, it does not fail if there is no cached token
+func (c *TokenCache) Delete(key string) error {
+	if err := os.Remove(c.Filename(key)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove cached token: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,17 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package release
+
+// SetSigningPublicKey sets the release signing key and returns a function that restores the previous key
+func SetSigningPublicKey(key string) func() {
+	previous := signingPublicKey
+	signingPublicKey = key
+
+	return func() {
+		signingPublicKey = previous
+	}
+}
@@ -0,0 +1,187 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package release
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/version"
+)
+
+// DefaultReleasesURL is the GitHub API endpoint that lists the releases of gardenctl
+const DefaultReleasesURL = "https://api.github.com/repos/gardener/gardenctl-v2/releases"
+
+// securityFixRegexp matches release notes that mention security fixes
+var securityFixRegexp = regexp.MustCompile(`(?i)\bsecurity\b|\bCVE-\d{4}-\d+\b`)
+
+// Asset is a file that is attached to a release, e.g. a binary or a checksum file
+type Asset struct {
+	// Name is the file name of the asset
+	Name string `json:"name"`
+	// DownloadURL is the URL the asset can be downloaded from
+	DownloadURL string `json:"browser_download_url"`
+}
+
+// Release is a GitHub release of gardenctl
+type Release struct {
+	// TagName is the tag of the release, which is the semantic version prefixed with v
+	TagName string `json:"tag_name"`
+	// HTMLURL is the URL of the release page
+	HTMLURL string `json:"html_url"`
+	// Body contains the release notes
+	Body string `json:"body"`
+	// Draft is true for releases that have not been published yet
+	Draft bool `json:"draft"`
+	// Prerelease is true for releases that are not meant for production use
+	Prerelease bool `json:"prerelease"`
+	// PublishedAt is the time the release has been published
+	PublishedAt time.Time `json:"published_at"`
+	// Assets are the files that are attached to the release
+	Assets []Asset `json:"assets"`
+}
+
+// SecurityFixes returns true if the release notes mention security fixes
+func (r Release) SecurityFixes() bool {
+	return securityFixRegexp.MatchString(r.Body)
+}
+
+// Asset returns the asset with the given name or nil if the release has no such asset
+func (r Release) Asset(name string) *Asset {
+	for i := range r.Assets {
+		if r.Assets[i].Name == name {
+			return &r.Assets[i]
+		}
+	}
+
+	return nil
+}
+
+// List returns the releases from the GitHub API endpoint at the given URL
+func List(ctx context.Context, url string) ([]Release, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list releases: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to list releases: %s", resp.Status)
+	}
+
+	var releases []Release
+	if err := json.NewDecoder(resp.Body).Decode(&releases); err != nil {
+		return nil, fmt.Errorf("failed to decode releases: %w", err)
+	}
+
+	return releases, nil
+}
+
+// ParseVersion parses a gardenctl version. The build metadata is ignored, so that development
+// builds like v0.0.0-master+$Format:%H$ are treated as pre-releases of their version.
+func ParseVersion(v string) (*version.Version, error) {
+	if i := strings.Index(v, "+"); i >= 0 {
+		v = v[:i]
+	}
+
+	parsed, err := version.ParseSemantic(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse version %q: %w", v, err)
+	}
+
+	return parsed, nil
+}
+
+// Latest returns the release with the highest version. Drafts and releases whose tag is not a
+// semantic version are ignored, pre-releases only if includePrereleases is true. Nil is returned
+// if there is no such release.
+func Latest(releases []Release, includePrereleases bool) *Release {
+	var (
+		latest        *Release
+		latestVersion *version.Version
+	)
+
+	for i := range releases {
+		r := &releases[i]
+		if r.Draft || (r.Prerelease && !includePrereleases) {
+			continue
+		}
+
+		v, err := ParseVersion(r.TagName)
+		if err != nil {
+			continue
+		}
+
+		if latestVersion == nil || latestVersion.LessThan(v) {
+			latest, latestVersion = r, v
+		}
+	}
+
+	return latest
+}
+
+// CheckResult is the result of the comparison of the current version with the latest release
+type CheckResult struct {
+	// CurrentVersion is the version of the running gardenctl binary
+	CurrentVersion string `json:"currentVersion"`
+	// LatestVersion is the version of the latest release
+	LatestVersion string `json:"latestVersion"`
+	// URL is the URL of the release page of the latest release
+	URL string `json:"url"`
+	// UpdateAvailable is true if the latest release is newer than the current version
+	UpdateAvailable bool `json:"updateAvailable"`
+	// SecurityFixes is true if any release that is newer than the current version contains security fixes
+	SecurityFixes bool `json:"securityFixes"`
+}
+
+// Check compares the current version with the latest release that is not a pre-release
+func Check(releases []Release, currentVersion string) (*CheckResult, error) {
+	current, err := ParseVersion(currentVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	latest := Latest(releases, false)
+	if latest == nil {
+		return nil, errors.New("no release found")
+	}
+
+	result := &CheckResult{
+		CurrentVersion: currentVersion,
+		LatestVersion:  latest.TagName,
+		URL:            latest.HTMLURL,
+	}
+
+	for _, r := range releases {
+		if r.Draft || r.Prerelease {
+			continue
+		}
+
+		v, err := ParseVersion(r.TagName)
+		if err != nil || !current.LessThan(v) {
+			continue
+		}
+
+		result.UpdateAvailable = true
+		result.SecurityFixes = result.SecurityFixes || r.SecurityFixes()
+	}
+
+	return result, nil
+}
@@ -0,0 +1,109 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package release_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/gardener/gardenctl-v2/internal/release"
+)
+
+var _ = Describe("Release", func() {
+	var releases []release.Release
+
+	BeforeEach(func() {
+		releases = []release.Release{
+			{TagName: "v2.1.0", Body: "Fixes CVE-2022-1234"},
+			{TagName: "v2.2.0-rc.1", Prerelease: true},
+			{TagName: "v2.3.0", Draft: true},
+			{TagName: "v2.0.1", Body: "Bug fixes"},
+			{TagName: "nightly"},
+			{TagName: "v2.0.0"},
+		}
+	})
+
+	Describe("List", func() {
+		It("should list the releases", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Expect(r.Header.Get("Accept")).To(Equal("application/vnd.github.v3+json"))
+				Expect(json.NewEncoder(w).Encode(releases)).To(Succeed())
+			}))
+			defer server.Close()
+
+			list, err := release.List(context.Background(), server.URL)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(list).To(Equal(releases))
+		})
+
+		It("should fail if the releases cannot be listed", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "rate limit exceeded", http.StatusForbidden)
+			}))
+			defer server.Close()
+
+			_, err := release.List(context.Background(), server.URL)
+			Expect(err).To(MatchError("failed to list releases: 403 Forbidden"))
+		})
+	})
+
+	Describe("Latest", func() {
+		It("should ignore drafts, pre-releases and invalid versions", func() {
+			Expect(release.Latest(releases, false).TagName).To(Equal("v2.1.0"))
+		})
+
+		It("should include pre-releases", func() {
+			Expect(release.Latest(releases, true).TagName).To(Equal("v2.2.0-rc.1"))
+		})
+
+		It("should return nil if there is no release", func() {
+			Expect(release.Latest(releases[4:5], true)).To(BeNil())
+		})
+	})
+
+	Describe("Check", func() {
+		It("should report a newer release with security fixes", func() {
+			result, err := release.Check(releases, "v2.0.0")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.LatestVersion).To(Equal("v2.1.0"))
+			Expect(result.UpdateAvailable).To(BeTrue())
+			Expect(result.SecurityFixes).To(BeTrue())
+		})
+
+		It("should report a newer release without security fixes", func() {
+			releases[0].Body = "New features"
+
+			result, err := release.Check(releases, "v2.0.0")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.UpdateAvailable).To(BeTrue())
+			Expect(result.SecurityFixes).To(BeFalse())
+		})
+
+		It("should report that the current version is up to date", func() {
+			result, err := release.Check(releases, "v2.1.0")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.UpdateAvailable).To(BeFalse())
+			Expect(result.SecurityFixes).To(BeFalse())
+		})
+
+		It("should treat development builds as pre-releases", func() {
+			result, err := release.Check(releases, "v2.1.0-master+$Format:%H$")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.UpdateAvailable).To(BeTrue())
+		})
+
+		It("should fail for an invalid current version", func() {
+			_, err := release.Check(releases, "master")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,253 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package release
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+const (
+	// ChannelStable selects the latest release that is not a pre-release
+	ChannelStable = "stable"
+	// ChannelLatest selects the latest release including pre-releases
+	ChannelLatest = "latest"
+)
+
+// signingPublicKey is the base64 encoded ed25519 public key the release binaries are signed with.
+// It is set at build time, see hack/get-build-ld-flags.sh. Signatures are not verified if it is empty.
+var signingPublicKey = ""
+
+// packageManagerPaths are path elements of binaries that are managed by a package manager
+var packageManagerPaths = []string{"/Cellar/", "/nix/store/", "/snap/", "/.krew/", "\\chocolatey\\", "\\scoop\\", "\\.krew\\"}
+
+// AssetName returns the name of the release binary for the given operating system and architecture
+func AssetName(goos, goarch string) string {
+	name := fmt.Sprintf("gardenctl_v2_%s_%s", goos, goarch)
+	if goos == "windows" {
+		name += ".exe"
+	}
+
+	return name
+}
+
+// LatestOfChannel returns the latest release of the given channel
+func LatestOfChannel(releases []Release, channel string) (*Release, error) {
+	var latest *Release
+
+	switch channel {
+	case ChannelStable:
+		latest = Latest(releases, false)
+	case ChannelLatest:
+		latest = Latest(releases, true)
+	default:
+		return nil, fmt.Errorf("invalid channel %q, must be one of %s, %s", channel, ChannelStable, ChannelLatest)
+	}
+
+	if latest == nil {
+		return nil, fmt.Errorf("no release found in channel %s", channel)
+	}
+
+	return latest, nil
+}
+
+// SigningKeyConfigured returns true if the binary has been built with a release signing key,
+// i.e. if DownloadBinary verifies the signatures of the downloaded binaries
+func SigningKeyConfigured() bool {
+	return signingPublicKey != ""
+}
+
+// DownloadBinary downloads the binary of the current operating system and architecture from the release.
+// The binary is verified with the SHA256 checksum that is attached to the release as <binary>.sha256 and,
+// if a signing key is configured, with the ed25519 signature that is attached as <binary>.sig. Signed releases
+// are rejected if no signing key is configured.
+func DownloadBinary(ctx context.Context, r *Release) ([]byte, error) {
+	name := AssetName(runtime.GOOS, runtime.GOARCH)
+
+	binary, err := downloadAsset(ctx, r, name)
+	if err != nil {
+		return nil, err
+	}
+
+	checksum, err := downloadAsset(ctx, r, name+".sha256")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := VerifyChecksum(binary, checksum); err != nil {
+		return nil, fmt.Errorf("failed to verify %s of release %s: %w", name, r.TagName, err)
+	}
+
+	if !SigningKeyConfigured() {
+		// the release is signed, but the signature cannot be verified, do not fall back to the checksum only
+		if r.Asset(name+".sig") != nil {
+			return nil, fmt.Errorf("release %s is signed, but this build of gardenctl contains no release signing key to verify %s, download the release manually", r.TagName, name)
+		}
+
+		return binary, nil
+	}
+
+	signature, err := downloadAsset(ctx, r, name+".sig")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := VerifySignature(binary, signature, signingPublicKey); err != nil {
+		return nil, fmt.Errorf("failed to verify %s of release %s: %w", name, r.TagName, err)
+	}
+
+	return binary, nil
+}
+
+func downloadAsset(ctx context.Context, r *Release, name string) ([]byte, error) {
+	asset := r.Asset(name)
+	if asset == nil {
+		return nil, fmt.Errorf("release %s has no asset %s", r.TagName, name)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, asset.DownloadURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download %s: %s", name, resp.Status)
+	}
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", name, err)
+	}
+
+	return data, nil
+}
+
+// VerifyChecksum checks the SHA256 checksum of the data. The checksum file contains the hex encoded
+// checksum, optionally followed by the file name like the output of sha256sum.
+func VerifyChecksum(data, checksumFile []byte) error {
+	fields := strings.Fields(string(checksumFile))
+	if len(fields) == 0 {
+		return errors.New("the checksum file is empty")
+	}
+
+	expected, err := hex.DecodeString(fields[0])
+	if err != nil {
+		return fmt.Errorf("the checksum file is invalid: %w", err)
+	}
+
+	actual := sha256.Sum256(data)
+	if !bytes.Equal(expected, actual[:]) {
+		return fmt.Errorf("checksum mismatch, expected %x but got %x", expected, actual)
+	}
+
+	return nil
+}
+
+// VerifySignature checks the ed25519 signature of the data with the base64 encoded public key.
+// The signature may be base64 encoded.
+func VerifySignature(data, signature []byte, publicKey string) error {
+	key, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return errors.New("the signing public key is invalid")
+	}
+
+	if len(signature) != ed25519.SignatureSize {
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+		if err != nil {
+			return fmt.Errorf("the signature is invalid: %w", err)
+		}
+
+		signature = decoded
+	}
+
+	if !ed25519.Verify(ed25519.PublicKey(key), data, signature) {
+		return errors.New("the signature does not match")
+	}
+
+	return nil
+}
+
+// ManagedByPackageManager returns true if the binary at the given path has been installed by a package manager
+// like Homebrew, Nix, Snap, Chocolatey, Scoop or krew
+func ManagedByPackageManager(path string) bool {
+	for _, p := range packageManagerPaths {
+		if strings.Contains(path, p) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ReplaceBinary atomically replaces the binary at the given path. The new binary is written to a temporary file
+// in the same directory, which is then renamed. On Windows, the running binary cannot be overwritten, it is moved
+// aside to <path>.old first.
+func ReplaceBinary(path string, binary []byte) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".new-")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName)
+
+	if _, err := tmp.Write(binary); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", tmpName, err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", tmpName, err)
+	}
+
+	if err := os.Chmod(tmpName, info.Mode().Perm()|0111); err != nil {
+		return fmt.Errorf("failed to make %s executable: %w", tmpName, err)
+	}
+
+	old := path + ".old"
+
+	if runtime.GOOS == "windows" {
+		_ = os.Remove(old)
+
+		if err := os.Rename(path, old); err != nil {
+			return fmt.Errorf("failed to move %s aside: %w", path, err)
+		}
+	}
+
+	if err := os.Rename(tmpName, path); err != nil {
+		if runtime.GOOS == "windows" {
+			_ = os.Rename(old, path)
+		}
+
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+
+	return nil
+}
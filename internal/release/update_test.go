@@ -0,0 +1,203 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package release_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/gardener/gardenctl-v2/internal/fake"
+	"github.com/gardener/gardenctl-v2/internal/release"
+)
+
+var _ = Describe("Update", func() {
+	binary := []byte("new gardenctl binary")
+
+	Describe("AssetName", func() {
+		It("should return the name of the release binary", func() {
+			Expect(release.AssetName("linux", "amd64")).To(Equal("gardenctl_v2_linux_amd64"))
+			Expect(release.AssetName("windows", "amd64")).To(Equal("gardenctl_v2_windows_amd64.exe"))
+		})
+	})
+
+	Describe("LatestOfChannel", func() {
+		releases := []release.Release{
+			{TagName: "v2.1.0"},
+			{TagName: "v2.2.0-rc.1", Prerelease: true},
+		}
+
+		It("should return the latest release of the channel", func() {
+			Expect(release.LatestOfChannel(releases, release.ChannelStable)).To(HaveField("TagName", "v2.1.0"))
+			Expect(release.LatestOfChannel(releases, release.ChannelLatest)).To(HaveField("TagName", "v2.2.0-rc.1"))
+		})
+
+		It("should fail for an invalid channel", func() {
+			_, err := release.LatestOfChannel(releases, "beta")
+			Expect(err).To(MatchError(`invalid channel "beta", must be one of stable, latest`))
+		})
+	})
+
+	Describe("VerifyChecksum", func() {
+		checksum := sha256.Sum256(binary)
+
+		It("should accept a matching checksum", func() {
+			Expect(release.VerifyChecksum(binary, []byte(fmt.Sprintf("%x", checksum)))).To(Succeed())
+			Expect(release.VerifyChecksum(binary, []byte(fmt.Sprintf("%x  gardenctl_v2_linux_amd64\n", checksum)))).To(Succeed())
+		})
+
+		It("should reject a checksum that does not match", func() {
+			Expect(release.VerifyChecksum([]byte("tampered"), []byte(fmt.Sprintf("%x", checksum)))).To(MatchError(ContainSubstring("checksum mismatch")))
+		})
+
+		It("should reject an invalid checksum file", func() {
+			Expect(release.VerifyChecksum(binary, nil)).To(MatchError("the checksum file is empty"))
+			Expect(release.VerifyChecksum(binary, []byte("not hex"))).To(MatchError(ContainSubstring("the checksum file is invalid")))
+		})
+	})
+
+	Describe("VerifySignature", func() {
+		var (
+			publicKey  string
+			privateKey ed25519.PrivateKey
+		)
+
+		BeforeEach(func() {
+			pub, priv, err := ed25519.GenerateKey(rand.Reader)
+			Expect(err).NotTo(HaveOccurred())
+
+			publicKey = base64.StdEncoding.EncodeToString(pub)
+			privateKey = priv
+		})
+
+		It("should accept a raw and a base64 encoded signature", func() {
+			signature := ed25519.Sign(privateKey, binary)
+
+			Expect(release.VerifySignature(binary, signature, publicKey)).To(Succeed())
+			Expect(release.VerifySignature(binary, []byte(base64.StdEncoding.EncodeToString(signature)+"\n"), publicKey)).To(Succeed())
+		})
+
+		It("should reject a signature of other data", func() {
+			signature := ed25519.Sign(privateKey, []byte("other"))
+
+			Expect(release.VerifySignature(binary, signature, publicKey)).To(MatchError("the signature does not match"))
+		})
+
+		It("should reject an invalid public key", func() {
+			Expect(release.VerifySignature(binary, ed25519.Sign(privateKey, binary), "invalid")).To(MatchError("the signing public key is invalid"))
+		})
+	})
+
+	Describe("DownloadBinary", func() {
+		var releases *fake.GitHubReleases
+
+		BeforeEach(func() {
+			releases = fake.NewGitHubReleases()
+			releases.AddRelease("v2.1.0", false, binary)
+		})
+
+		AfterEach(func() {
+			releases.Close()
+		})
+
+		latest := func() *release.Release {
+			list, err := release.List(context.Background(), releases.ReleasesURL())
+			Expect(err).NotTo(HaveOccurred())
+
+			return release.Latest(list, false)
+		}
+
+		It("should download and verify the binary", func() {
+			Expect(release.DownloadBinary(context.Background(), latest())).To(Equal(binary))
+		})
+
+		It("should fail if the checksum does not match", func() {
+			releases.SetAsset("v2.1.0", release.AssetName(runtime.GOOS, runtime.GOARCH), []byte("tampered"))
+
+			_, err := release.DownloadBinary(context.Background(), latest())
+			Expect(err).To(MatchError(ContainSubstring("checksum mismatch")))
+		})
+
+		It("should fail if the release is signed, but no signing key is configured", func() {
+			releases.SetAsset("v2.1.0", release.AssetName(runtime.GOOS, runtime.GOARCH)+".sig", []byte("signature"))
+
+			_, err := release.DownloadBinary(context.Background(), latest())
+			Expect(err).To(MatchError(ContainSubstring("release v2.1.0 is signed, but this build of gardenctl contains no release signing key")))
+		})
+
+		Context("with a signing key", func() {
+			var (
+				privateKey ed25519.PrivateKey
+				reset      func()
+			)
+
+			BeforeEach(func() {
+				pub, priv, err := ed25519.GenerateKey(rand.Reader)
+				Expect(err).NotTo(HaveOccurred())
+
+				privateKey = priv
+				reset = release.SetSigningPublicKey(base64.StdEncoding.EncodeToString(pub))
+			})
+
+			AfterEach(func() {
+				reset()
+			})
+
+			It("should verify the signature", func() {
+				releases.SetAsset("v2.1.0", release.AssetName(runtime.GOOS, runtime.GOARCH)+".sig", ed25519.Sign(privateKey, binary))
+
+				Expect(release.DownloadBinary(context.Background(), latest())).To(Equal(binary))
+			})
+
+			It("should fail without a signature", func() {
+				_, err := release.DownloadBinary(context.Background(), latest())
+				Expect(err).To(MatchError(fmt.Sprintf("release v2.1.0 has no asset %s.sig", release.AssetName(runtime.GOOS, runtime.GOARCH))))
+			})
+		})
+	})
+
+	Describe("ManagedByPackageManager", func() {
+		It("should detect binaries of package managers", func() {
+			Expect(release.ManagedByPackageManager("/usr/local/Cellar/gardenctl-v2/2.0.0/bin/gardenctl")).To(BeTrue())
+			Expect(release.ManagedByPackageManager("/nix/store/abc-gardenctl/bin/gardenctl")).To(BeTrue())
+			Expect(release.ManagedByPackageManager("/home/user/.krew/store/garden/v2.1.0/kubectl-garden")).To(BeTrue())
+			Expect(release.ManagedByPackageManager("/usr/local/bin/gardenctl")).To(BeFalse())
+		})
+	})
+
+	Describe("ReplaceBinary", func() {
+		It("should replace the binary and keep it executable", func() {
+			dir, err := ioutil.TempDir("", "gctlv2-*")
+			Expect(err).NotTo(HaveOccurred())
+			defer os.RemoveAll(dir)
+
+			path := filepath.Join(dir, "gardenctl")
+			Expect(ioutil.WriteFile(path, []byte("old"), 0700)).To(Succeed())
+
+			Expect(release.ReplaceBinary(path, binary)).To(Succeed())
+			Expect(ioutil.ReadFile(path)).To(Equal(binary))
+
+			info, err := os.Stat(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.Mode().Perm()).To(Equal(os.FileMode(0711)))
+
+			entries, err := ioutil.ReadDir(dir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(HaveLen(1))
+		})
+	})
+})
@@ -0,0 +1,87 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shoottemplate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// templateExtension is the extension of the template files in the template directory
+const templateExtension = ".yaml"
+
+// Library provides the shoot templates of a directory and an OCI registry
+type Library struct {
+	// Directory contains the template files <name>.yaml, it is not used if empty
+	Directory string
+	// Registry is the prefix of the OCI repositories of the templates, it is not used if empty
+	Registry string
+	// HTTPClient is used to pull the templates from the registry, http.DefaultClient is used if nil
+	HTTPClient *http.Client
+}
+
+// Load returns the template with the given name. A name that refers to an existing file, e.g. ./my-shoot.yaml,
+// is read from this file. Otherwise, the template is read from the directory and, if it does not exist
+// there, pulled from the registry. The name may contain a tag or digest for the registry, e.g. aws:v1.
+func (l *Library) Load(ctx context.Context, name string) ([]byte, error) {
+	if name == "" {
+		return nil, errors.New("the template name must not be empty")
+	}
+
+	if isFilePath(name) {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template file: %w", err)
+		}
+
+		return data, nil
+	}
+
+	if l.Directory != "" && !strings.ContainsAny(name, ":@") {
+		data, err := os.ReadFile(filepath.Join(l.Directory, name+templateExtension))
+		if err == nil {
+			return data, nil
+		}
+
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("failed to read template %q: %w", name, err)
+		}
+	}
+
+	if l.Registry != "" {
+		return l.pull(ctx, name)
+	}
+
+	if l.Directory == "" {
+		return nil, fmt.Errorf("template %q not found, no template directory or registry is configured", name)
+	}
+
+	return nil, fmt.Errorf("template %q not found in directory %s", name, l.Directory)
+}
+
+// isFilePath returns true if the name refers to a template file instead of a template of the library
+func isFilePath(name string) bool {
+	if strings.ContainsRune(name, os.PathSeparator) || strings.ContainsRune(name, '/') || strings.HasSuffix(name, templateExtension) || strings.HasSuffix(name, ".yml") {
+		_, err := os.Stat(name)
+		return err == nil
+	}
+
+	return false
+}
+
+func (l *Library) httpClient() *http.Client {
+	if l.HTTPClient != nil {
+		return l.HTTPClient
+	}
+
+	return http.DefaultClient
+}
@@ -0,0 +1,135 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shoottemplate_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/gardener/gardenctl-v2/internal/shoottemplate"
+)
+
+var _ = Describe("Library", func() {
+	var (
+		ctx context.Context
+		dir string
+	)
+
+	BeforeEach(func() {
+		var err error
+
+		ctx = context.Background()
+		dir, err = os.MkdirTemp("", "shoottemplates-")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.WriteFile(filepath.Join(dir, "aws.yaml"), []byte("kind: Shoot\n"), 0o600)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("should load a template of the directory", func() {
+		library := &shoottemplate.Library{Directory: dir}
+		Expect(library.Load(ctx, "aws")).To(Equal([]byte("kind: Shoot\n")))
+	})
+
+	It("should load a template file", func() {
+		library := &shoottemplate.Library{}
+		Expect(library.Load(ctx, filepath.Join(dir, "aws.yaml"))).To(Equal([]byte("kind: Shoot\n")))
+	})
+
+	It("should fail if the template does not exist", func() {
+		library := &shoottemplate.Library{Directory: dir}
+		_, err := library.Load(ctx, "gcp")
+		Expect(err).To(MatchError(fmt.Sprintf("template \"gcp\" not found in directory %s", dir)))
+
+		library = &shoottemplate.Library{}
+		_, err = library.Load(ctx, "gcp")
+		Expect(err).To(MatchError("template \"gcp\" not found, no template directory or registry is configured"))
+	})
+
+	Context("with a registry", func() {
+		const (
+			template = "kind: Shoot\nmetadata:\n  name: from-registry\n"
+			token    = "anonymous-token"
+		)
+
+		var (
+			server   *httptest.Server
+			requests []string
+		)
+
+		BeforeEach(func() {
+			sum := sha256.Sum256([]byte(template))
+			digest := "sha256:" + hex.EncodeToString(sum[:])
+			requests = nil
+
+			server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests = append(requests, r.URL.Path)
+
+				if r.URL.Path == "/token" {
+					Expect(r.URL.Query().Get("scope")).To(Equal("repository:templates/gcp:pull"))
+					fmt.Fprintf(w, `{"token":%q}`, token)
+
+					return
+				}
+
+				if r.Header.Get("Authorization") != "Bearer "+token {
+					w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="https://%s/token",service="registry",scope="repository:templates/gcp:pull"`, r.Host))
+					w.WriteHeader(http.StatusUnauthorized)
+
+					return
+				}
+
+				switch r.URL.Path {
+				case "/v2/templates/gcp/manifests/v1":
+					Expect(r.Header.Get("Accept")).To(Equal("application/vnd.oci.image.manifest.v1+json"))
+					fmt.Fprintf(w, `{"schemaVersion":2,"layers":[{"mediaType":"application/yaml","digest":%q,"size":%d}]}`, digest, len(template))
+				case "/v2/templates/gcp/blobs/" + digest:
+					fmt.Fprint(w, template)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("should pull the template from the registry if it is not in the directory", func() {
+			library := &shoottemplate.Library{
+				Directory:  dir,
+				Registry:   strings.TrimPrefix(server.URL, "https://") + "/templates",
+				HTTPClient: server.Client(),
+			}
+
+			Expect(library.Load(ctx, "gcp:v1")).To(Equal([]byte(template)))
+			Expect(requests).To(ContainElements("/token", "/v2/templates/gcp/manifests/v1"))
+		})
+
+		It("should fail if the tag does not exist", func() {
+			library := &shoottemplate.Library{
+				Registry:   strings.TrimPrefix(server.URL, "https://") + "/templates",
+				HTTPClient: server.Client(),
+			}
+
+			_, err := library.Load(ctx, "gcp")
+			Expect(err).To(MatchError(ContainSubstring("failed to pull template \"gcp\": unexpected status 404 Not Found")))
+		})
+	})
+})
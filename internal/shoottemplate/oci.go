@@ -0,0 +1,218 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shoottemplate
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const (
+	// mediaTypeOCIManifest is the media type of OCI image manifests, which are also used for artifacts like the templates
+	mediaTypeOCIManifest = "application/vnd.oci.image.manifest.v1+json"
+	// defaultTag is the tag that is pulled if the template name contains no tag or digest
+	defaultTag = "latest"
+	// maxTemplateSize limits the size of the manifests and templates that are read from the registry
+	maxTemplateSize = 1 << 20
+)
+
+// challengeParamRegexp matches the parameters of a WWW-Authenticate challenge, e.g. realm="https://ghcr.io/token"
+var challengeParamRegexp = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// manifest is the subset of an OCI image manifest that is used to find the template
+type manifest struct {
+	Layers []descriptor `json:"layers"`
+}
+
+type descriptor struct {
+	MediaType string `json:"mediaType"`
+	Digest    string `json:"digest"`
+	Size      int64  `json:"size"`
+}
+
+// pull pulls the template from the OCI repository <registry>/<name>. The template is the content of the first
+// layer of the artifact, like it is pushed with oras push <registry>/<name>:<tag> <file>.yaml.
+// Only registries that allow anonymous pulls are supported.
+func (l *Library) pull(ctx context.Context, name string) ([]byte, error) {
+	host, repository, reference := splitReference(l.Registry, name)
+
+	// the token is requested lazily if the registry requires it and reused for the blob
+	token := ""
+
+	data, err := l.get(ctx, host, repository, "manifests/"+reference, mediaTypeOCIManifest, &token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pull template %q: %w", name, err)
+	}
+
+	m := &manifest{}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest of template %q: %w", name, err)
+	}
+
+	if len(m.Layers) == 0 {
+		return nil, fmt.Errorf("the artifact of template %q has no layers", name)
+	}
+
+	layer := m.Layers[0]
+
+	data, err = l.get(ctx, host, repository, "blobs/"+layer.Digest, "", &token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pull template %q: %w", name, err)
+	}
+
+	if err := verifyDigest(data, layer.Digest); err != nil {
+		return nil, fmt.Errorf("failed to verify template %q: %w", name, err)
+	}
+
+	return data, nil
+}
+
+// splitReference returns the host, the repository and the tag or digest of the template
+func splitReference(registry, name string) (string, string, string) {
+	parts := strings.SplitN(registry, "/", 2)
+	host, repository := parts[0], ""
+
+	if len(parts) == 2 {
+		repository = parts[1] + "/"
+	}
+
+	reference := defaultTag
+
+	if i := strings.Index(name, "@"); i >= 0 {
+		name, reference = name[:i], name[i+1:]
+	} else if i := strings.LastIndex(name, ":"); i >= 0 {
+		name, reference = name[:i], name[i+1:]
+	}
+
+	return host, repository + name, reference
+}
+
+// get reads a manifest or blob of the repository. If the registry requires a bearer token,
+// an anonymous token is requested from the token service of the registry.
+func (l *Library) get(ctx context.Context, host, repository, path, accept string, token *string) ([]byte, error) {
+	u := fmt.Sprintf("https://%s/v2/%s/%s", host, repository, path)
+
+	resp, err := l.do(ctx, u, accept, *token)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized && *token == "" {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+
+		*token, err = l.anonymousToken(ctx, challenge)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err = l.do(ctx, u, accept, *token)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s for %s", resp.Status, u)
+	}
+
+	return io.ReadAll(io.LimitReader(resp.Body, maxTemplateSize))
+}
+
+func (l *Library) do(ctx context.Context, u, accept, token string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	return l.httpClient().Do(req)
+}
+
+// anonymousToken requests a token from the token service of the Bearer challenge of the registry
+func (l *Library) anonymousToken(ctx context.Context, challenge string) (string, error) {
+	if !strings.HasPrefix(challenge, "Bearer ") {
+		return "", errors.New("the registry requires authentication, only registries that allow anonymous pulls are supported")
+	}
+
+	params := map[string]string{}
+	for _, match := range challengeParamRegexp.FindAllStringSubmatch(challenge, -1) {
+		params[match[1]] = match[2]
+	}
+
+	realm, err := url.Parse(params["realm"])
+	if err != nil || params["realm"] == "" {
+		return "", fmt.Errorf("invalid authentication challenge of the registry: %q", challenge)
+	}
+
+	query := realm.Query()
+
+	for _, key := range []string{"service", "scope"} {
+		if value, ok := params[key]; ok {
+			query.Set(key, value)
+		}
+	}
+
+	realm.RawQuery = query.Encode()
+
+	resp, err := l.do(ctx, realm.String(), "", "")
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to get an anonymous token from %s: unexpected status %s", realm.Host, resp.Status)
+	}
+
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxTemplateSize)).Decode(&body); err != nil {
+		return "", fmt.Errorf("failed to parse token response: %w", err)
+	}
+
+	if body.Token != "" {
+		return body.Token, nil
+	}
+
+	return body.AccessToken, nil
+}
+
+// verifyDigest checks that the data matches the sha256 digest
+func verifyDigest(data []byte, digest string) error {
+	if !strings.HasPrefix(digest, "sha256:") {
+		return fmt.Errorf("unsupported digest %q", digest)
+	}
+
+	sum := sha256.Sum256(data)
+	if actual := "sha256:" + hex.EncodeToString(sum[:]); actual != digest {
+		return fmt.Errorf("digest mismatch, expected %s but got %s", digest, actual)
+	}
+
+	return nil
+}
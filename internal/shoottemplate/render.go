@@ -0,0 +1,150 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shoottemplate
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+
+	sprigv3 "github.com/Masterminds/sprig/v3"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/yaml"
+)
+
+// Data is the data the templates are rendered with
+type Data struct {
+	// Name is the name of the shoot
+	Name string
+	// Project is the name of the project of the shoot
+	Project string
+	// Namespace is the namespace of the project
+	Namespace string
+	// Values are the values of the --values files and --set flags
+	Values map[string]interface{}
+}
+
+// Render renders the template with the given data and returns the resulting shoot. Besides the functions of
+// sprig, the templates can use required to fail if a value is missing, e.g. {{ required "region" .Values.region }}.
+// The name and namespace of the shoot are set from the data.
+func Render(name string, text []byte, data Data) (*gardencorev1beta1.Shoot, error) {
+	tmpl, err := template.New(name).
+		Funcs(sprigv3.TxtFuncMap()).
+		Funcs(template.FuncMap{"required": required}).
+		Parse(string(text))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template %q: %w", name, err)
+	}
+
+	if data.Values == nil {
+		data.Values = map[string]interface{}{}
+	}
+
+	buf := &bytes.Buffer{}
+	if err := tmpl.Execute(buf, data); err != nil {
+		return nil, fmt.Errorf("failed to render template %q: %w", name, err)
+	}
+
+	// like helm, missing values are rendered as empty strings instead of <no value>
+	rendered := strings.ReplaceAll(buf.String(), "<no value>", "")
+
+	shoot := &gardencorev1beta1.Shoot{}
+	if err := yaml.UnmarshalStrict([]byte(rendered), shoot); err != nil {
+		return nil, fmt.Errorf("the rendered template %q is not a valid shoot: %w", name, err)
+	}
+
+	if shoot.Kind != "" && shoot.Kind != "Shoot" {
+		return nil, fmt.Errorf("the rendered template %q is a %s, not a Shoot", name, shoot.Kind)
+	}
+
+	shoot.SetGroupVersionKind(gardencorev1beta1.SchemeGroupVersion.WithKind("Shoot"))
+	shoot.Name = data.Name
+	shoot.Namespace = data.Namespace
+
+	return shoot, nil
+}
+
+func required(message string, value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, fmt.Errorf("missing required value: %s", message)
+	}
+
+	if s, ok := value.(string); ok && s == "" {
+		return nil, fmt.Errorf("missing required value: %s", message)
+	}
+
+	return value, nil
+}
+
+// ParseValues reads the YAML values files and sets the key=value pairs, which take precedence.
+// Keys are paths of nested values separated by dots, e.g. workers.maximum=5. The values of the
+// pairs are always strings.
+func ParseValues(files [][]byte, pairs []string) (map[string]interface{}, error) {
+	values := map[string]interface{}{}
+
+	for _, file := range files {
+		fileValues := map[string]interface{}{}
+		if err := yaml.Unmarshal(file, &fileValues); err != nil {
+			return nil, fmt.Errorf("failed to parse values: %w", err)
+		}
+
+		mergeValues(values, fileValues)
+	}
+
+	for _, pair := range pairs {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, fmt.Errorf("invalid value %q, must have the format key=value", pair)
+		}
+
+		if err := setValue(values, strings.Split(kv[0], "."), kv[1]); err != nil {
+			return nil, fmt.Errorf("invalid value %q: %w", pair, err)
+		}
+	}
+
+	return values, nil
+}
+
+// mergeValues merges src into dst, nested maps are merged recursively
+func mergeValues(dst, src map[string]interface{}) {
+	for key, value := range src {
+		srcMap, srcIsMap := value.(map[string]interface{})
+		dstMap, dstIsMap := dst[key].(map[string]interface{})
+
+		if srcIsMap && dstIsMap {
+			mergeValues(dstMap, srcMap)
+			continue
+		}
+
+		dst[key] = value
+	}
+}
+
+func setValue(values map[string]interface{}, path []string, value string) error {
+	for i, key := range path {
+		if key == "" {
+			return errors.New("the key must not contain empty path elements")
+		}
+
+		if i == len(path)-1 {
+			values[key] = value
+			break
+		}
+
+		next, ok := values[key].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			values[key] = next
+		}
+
+		values = next
+	}
+
+	return nil
+}
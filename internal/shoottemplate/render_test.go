@@ -0,0 +1,80 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shoottemplate_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/gardener/gardenctl-v2/internal/shoottemplate"
+)
+
+var _ = Describe("Render", func() {
+	const text = `apiVersion: core.gardener.cloud/v1beta1
+kind: Shoot
+metadata:
+  name: ignored
+  labels:
+    project: {{ .Project }}
+spec:
+  region: {{ required "the region" .Values.region }}
+  kubernetes:
+    version: {{ .Values.kubernetes.version | default "1.23.1" | quote }}
+  provider:
+    type: aws
+    workers:
+    - name: {{ .Name }}-worker
+      minimum: 1
+      maximum: {{ .Values.workers.maximum | default 2 }}
+`
+
+	It("should render the shoot with the values", func() {
+		values, err := shoottemplate.ParseValues(
+			[][]byte{[]byte("region: eu-west-1\nworkers:\n  maximum: 3\n")},
+			[]string{"workers.maximum=5", "kubernetes.version=1.22.2"},
+		)
+		Expect(err).NotTo(HaveOccurred())
+
+		shoot, err := shoottemplate.Render("aws", []byte(text), shoottemplate.Data{
+			Name:      "myshoot",
+			Project:   "prod1",
+			Namespace: "garden-prod1",
+			Values:    values,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(shoot.Name).To(Equal("myshoot"))
+		Expect(shoot.Namespace).To(Equal("garden-prod1"))
+		Expect(shoot.Kind).To(Equal("Shoot"))
+		Expect(shoot.Labels).To(HaveKeyWithValue("project", "prod1"))
+		Expect(shoot.Spec.Region).To(Equal("eu-west-1"))
+		Expect(shoot.Spec.Kubernetes.Version).To(Equal("1.22.2"))
+		Expect(shoot.Spec.Provider.Workers).To(HaveLen(1))
+		Expect(shoot.Spec.Provider.Workers[0].Name).To(Equal("myshoot-worker"))
+		Expect(shoot.Spec.Provider.Workers[0].Maximum).To(BeEquivalentTo(5))
+	})
+
+	It("should fail if a required value is missing", func() {
+		_, err := shoottemplate.Render("aws", []byte(text), shoottemplate.Data{Name: "myshoot"})
+		Expect(err).To(MatchError(ContainSubstring("missing required value: the region")))
+	})
+
+	It("should fail if the rendered template is not a shoot", func() {
+		_, err := shoottemplate.Render("cm", []byte("kind: ConfigMap\n"), shoottemplate.Data{Name: "myshoot"})
+		Expect(err).To(MatchError("the rendered template \"cm\" is a ConfigMap, not a Shoot"))
+
+		_, err = shoottemplate.Render("typo", []byte("kind: Shoot\nspec:\n  regoin: eu-west-1\n"), shoottemplate.Data{Name: "myshoot"})
+		Expect(err).To(MatchError(ContainSubstring("the rendered template \"typo\" is not a valid shoot")))
+	})
+
+	It("should reject invalid values", func() {
+		_, err := shoottemplate.ParseValues(nil, []string{"region"})
+		Expect(err).To(MatchError("invalid value \"region\", must have the format key=value"))
+
+		_, err = shoottemplate.ParseValues(nil, []string{"workers..maximum=5"})
+		Expect(err).To(MatchError("invalid value \"workers..maximum=5\": the key must not contain empty path elements"))
+	})
+})
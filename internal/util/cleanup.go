@@ -0,0 +1,179 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// cleanupMarkerFilename is the file in the sessions directory whose modification time is the time of the last automatic cleanup
+	cleanupMarkerFilename = ".cleanup"
+
+	// cleanupInterval is the minimum duration between two automatic cleanups
+	cleanupInterval = time.Hour
+)
+
+// CleanupDirectories are the directories that contain the files written by gardenctl
+type CleanupDirectories struct {
+	// SessionsDirectory is the parent directory of the session directories
+	SessionsDirectory string
+	// CacheDirectory contains the cached completions and kubeconfigs of exec commands
+	CacheDirectory string
+	// TokenDirectory contains the cached tokens of garden logins
+	TokenDirectory string
+}
+
+// Cleanup removes the files written by gardenctl that have not been modified since the given time and returns their paths.
+// Session directories that have not been used since then are removed completely. In the remaining sessions, the kubeconfigs,
+// client configurations and provider CLI configurations are removed, the target, the target history and the currently
+// targeted kubeconfig are kept. Files are removed on a best effort basis, errors are ignored.
+func Cleanup(dirs CleanupDirectories, notModifiedSince time.Time) []string {
+	var removed []string
+
+	if dirs.SessionsDirectory != "" {
+		removed = append(removed, removeStaleSessions(dirs.SessionsDirectory, notModifiedSince)...)
+
+		entries, err := os.ReadDir(dirs.SessionsDirectory)
+		if err == nil {
+			for _, entry := range entries {
+				if entry.IsDir() && sidRegexp.MatchString(entry.Name()) {
+					removed = append(removed, cleanupSession(filepath.Join(dirs.SessionsDirectory, entry.Name()), notModifiedSince)...)
+				}
+			}
+		}
+	}
+
+	for _, dir := range []string{dirs.CacheDirectory, dirs.TokenDirectory} {
+		if dir != "" {
+			removed = append(removed, removeStaleFiles(dir, notModifiedSince, nil)...)
+		}
+	}
+
+	return removed
+}
+
+// cleanupSession removes the files of a session directory that have not been modified since the given time
+func cleanupSession(sessionDirectory string, notModifiedSince time.Time) []string {
+	keep := map[string]bool{
+		"target.yaml":         true,
+		"target-history.yaml": true,
+		"kubeconfig.yaml":     true,
+	}
+
+	// the kubeconfig of the current target is referenced by the kubeconfig.yaml symlink
+	if link, err := os.Readlink(filepath.Join(sessionDirectory, "kubeconfig.yaml")); err == nil {
+		keep[filepath.Base(link)] = true
+	}
+
+	var removed []string
+
+	entries, err := os.ReadDir(sessionDirectory)
+	if err != nil {
+		return nil
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		// the subdirectories contain client configurations, requested kubeconfigs and provider CLI configurations
+		dir := filepath.Join(sessionDirectory, entry.Name())
+		if entry.Name() == ".config" {
+			subEntries, err := os.ReadDir(dir)
+			if err != nil {
+				continue
+			}
+
+			for _, subEntry := range subEntries {
+				if subEntry.IsDir() {
+					removed = append(removed, removeStaleFiles(filepath.Join(dir, subEntry.Name()), notModifiedSince, nil)...)
+				}
+			}
+
+			continue
+		}
+
+		removed = append(removed, removeStaleFiles(dir, notModifiedSince, nil)...)
+	}
+
+	return append(removed, removeStaleFiles(sessionDirectory, notModifiedSince, func(entry os.DirEntry) bool {
+		return entry.IsDir() || keep[entry.Name()]
+	})...)
+}
+
+// removeStaleFiles removes the files and directories in the given directory that have not been modified since the given time.
+// A directory is only removed if none of the files it contains have been modified since then. Lock files are never removed,
+// as they must exist as long as the locked file exists.
+func removeStaleFiles(dir string, notModifiedSince time.Time, skip func(os.DirEntry) bool) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	var removed []string
+
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), ".lock") || (skip != nil && skip(entry)) {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		if !lastModified(path).Before(notModifiedSince) {
+			continue
+		}
+
+		if err := os.RemoveAll(path); err == nil {
+			removed = append(removed, path)
+		}
+	}
+
+	return removed
+}
+
+// lastModified returns the latest modification time of the file or of the files in the directory.
+// The modification times of directories are ignored, they change whenever a file is removed.
+func lastModified(path string) time.Time {
+	var latest time.Time
+
+	_ = filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			// files that cannot be read are treated as modified, so that they are not removed
+			latest = time.Now()
+			return filepath.SkipDir
+		}
+
+		if !info.IsDir() && info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+
+		return nil
+	})
+
+	return latest
+}
+
+// shouldCleanup returns true if the last automatic cleanup is longer ago than the cleanup interval and records the given time as
+// the time of the last cleanup
+func shouldCleanup(sessionsDirectory string, now time.Time) bool {
+	marker := filepath.Join(sessionsDirectory, cleanupMarkerFilename)
+
+	info, err := os.Stat(marker)
+	if err == nil && info.ModTime().After(now.Add(-cleanupInterval)) {
+		return false
+	}
+
+	if err := os.WriteFile(marker, nil, 0600); err != nil {
+		return false
+	}
+
+	return os.Chtimes(marker, now, now) == nil
+}
@@ -0,0 +1,101 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package util_test
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/gardener/gardenctl-v2/internal/util"
+)
+
+var _ = Describe("Cleanup", func() {
+	var (
+		dir  string
+		dirs util.CleanupDirectories
+		now  time.Time
+	)
+
+	writeFile := func(path string, modTime time.Time) {
+		Expect(os.MkdirAll(filepath.Dir(path), 0700)).To(Succeed())
+		Expect(os.WriteFile(path, nil, 0600)).To(Succeed())
+		Expect(os.Chtimes(path, modTime, modTime)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "garden-*")
+		Expect(err).NotTo(HaveOccurred())
+
+		dirs = util.CleanupDirectories{
+			SessionsDirectory: filepath.Join(dir, "sessions"),
+			CacheDirectory:    filepath.Join(dir, "cache"),
+			TokenDirectory:    filepath.Join(dir, "tokens"),
+		}
+		now = time.Now()
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("should remove the stale files of active sessions and keep the target", func() {
+		stale := now.Add(-8 * 24 * time.Hour)
+		session := filepath.Join(dirs.SessionsDirectory, "active")
+
+		writeFile(filepath.Join(session, "target.yaml"), stale)
+		writeFile(filepath.Join(session, "target-history.yaml"), stale)
+		writeFile(filepath.Join(session, "kubeconfig.current.yaml"), stale)
+		Expect(os.Symlink(filepath.Join(session, "kubeconfig.current.yaml"), filepath.Join(session, "kubeconfig.yaml"))).To(Succeed())
+		writeFile(filepath.Join(session, "kubeconfig.old.yaml"), stale)
+		writeFile(filepath.Join(session, "client-configs", "old.yaml"), stale)
+		writeFile(filepath.Join(session, "client-configs", "new.yaml"), now)
+		writeFile(filepath.Join(session, ".config", "gcloud", "garden--project--shoot", "credentials.json"), stale)
+		writeFile(filepath.Join(session, ".config", "az", "config"), now)
+		writeFile(filepath.Join(dirs.CacheDirectory, "kubeconfig-exec-abc.yaml"), stale)
+		writeFile(filepath.Join(dirs.TokenDirectory, "garden.json"), stale)
+		writeFile(filepath.Join(dirs.TokenDirectory, "garden.json.lock"), stale)
+
+		removed := util.Cleanup(dirs, now.Add(-7*24*time.Hour))
+		Expect(removed).To(ConsistOf(
+			filepath.Join(session, "kubeconfig.old.yaml"),
+			filepath.Join(session, "client-configs", "old.yaml"),
+			filepath.Join(session, ".config", "gcloud", "garden--project--shoot"),
+			filepath.Join(dirs.CacheDirectory, "kubeconfig-exec-abc.yaml"),
+			filepath.Join(dirs.TokenDirectory, "garden.json"),
+		))
+
+		Expect(filepath.Join(session, "target.yaml")).To(BeARegularFile())
+		Expect(filepath.Join(session, "target-history.yaml")).To(BeARegularFile())
+		Expect(filepath.Join(session, "kubeconfig.yaml")).To(BeARegularFile())
+		Expect(filepath.Join(session, "client-configs", "new.yaml")).To(BeARegularFile())
+		Expect(filepath.Join(session, ".config", "az", "config")).To(BeARegularFile())
+		Expect(filepath.Join(dirs.TokenDirectory, "garden.json.lock")).To(BeARegularFile())
+	})
+
+	It("should remove stale sessions", func() {
+		session := filepath.Join(dirs.SessionsDirectory, "stale")
+		writeFile(filepath.Join(session, "target.yaml"), now.Add(-8*24*time.Hour))
+
+		modTime := now.Add(-8 * 24 * time.Hour)
+		Expect(os.Chtimes(session, modTime, modTime)).To(Succeed())
+
+		Expect(util.Cleanup(dirs, now.Add(-7*24*time.Hour))).To(ConsistOf(session))
+		Expect(session).NotTo(BeADirectory())
+	})
+
+	It("should only clean up automatically once per interval", func() {
+		Expect(os.MkdirAll(dirs.SessionsDirectory, 0700)).To(Succeed())
+		Expect(util.ShouldCleanup(dirs.SessionsDirectory, now)).To(BeTrue())
+		Expect(util.ShouldCleanup(dirs.SessionsDirectory, now.Add(30*time.Minute))).To(BeFalse())
+		Expect(util.ShouldCleanup(dirs.SessionsDirectory, now.Add(2*time.Hour))).To(BeTrue())
+	})
+})
@@ -0,0 +1,117 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package util
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gardener/gardenctl-v2/pkg/target"
+)
+
+// completionCacheClock provides the current time for the completion cache
+var completionCacheClock Clock = &RealClock{}
+
+// completionCache holds the completion values of one garden. The values are only valid for the
+// target they have been queried for, the cache is discarded as soon as the target changes.
+type completionCache struct {
+	Target  string                          `json:"target"`
+	Entries map[string]completionCacheEntry `json:"entries"`
+}
+
+type completionCacheEntry struct {
+	Expires time.Time `json:"expires"`
+	Values  []string  `json:"values"`
+}
+
+// CachedCompletion returns the completion values of the given kind, e.g. shoots, for the given target.
+// The values are read from a cache file per garden in the cache directory of the gardenctl configuration.
+// If they are not cached, expired or have been cached for another target, they are computed by the given
+// function and written to the cache. The values are always computed if the cache is disabled.
+// If the garden cannot be reached, the expired values of the target are returned.
+func CachedCompletion(manager target.Manager, t target.Target, kind string, compute func() ([]string, error)) ([]string, error) {
+	config := manager.Configuration()
+	if config == nil || config.CacheDir == "" || t.GardenName() == "" {
+		return compute()
+	}
+
+	ttl := config.CompletionCacheTTL()
+	if ttl <= 0 {
+		return compute()
+	}
+
+	filename := CompletionCacheFile(config.CacheDir, t.GardenName())
+	cache := readCompletionCache(filename)
+	now := completionCacheClock.Now()
+
+	targetKey := fmt.Sprintf("%s/%s/%s/%s", t.GardenName(), t.ProjectName(), t.SeedName(), t.ShootName())
+	if cache.Target != targetKey {
+		cache = completionCache{Target: targetKey, Entries: map[string]completionCacheEntry{}}
+	}
+
+	entry, cached := cache.Entries[kind]
+	if cached && now.Before(entry.Expires) {
+		return entry.Values, nil
+	}
+
+	values, err := compute()
+	if err != nil {
+		// expired values are better than no completion at all if the garden cannot be reached
+		if cached && target.IsUnreachable(err) {
+			return entry.Values, nil
+		}
+
+		return nil, err
+	}
+
+	cache.Entries[kind] = completionCacheEntry{
+		Expires: now.Add(ttl),
+		Values:  values,
+	}
+
+	// the cache is only an optimization, completion must not fail if it cannot be written
+	_ = writeCompletionCache(filename, cache)
+
+	return values, nil
+}
+
+// CompletionCacheFile returns the name of the file the completion values of the given garden are cached in
+func CompletionCacheFile(cacheDir, gardenName string) string {
+	return filepath.Join(cacheDir, fmt.Sprintf("completion-%x.json", sha256.Sum256([]byte(gardenName))))
+}
+
+func readCompletionCache(filename string) completionCache {
+	cache := completionCache{}
+
+	data, err := os.ReadFile(filename)
+	if err == nil {
+		_ = json.Unmarshal(data, &cache)
+	}
+
+	if cache.Entries == nil {
+		cache.Entries = map[string]completionCacheEntry{}
+	}
+
+	return cache
+}
+
+func writeCompletionCache(filename string, cache completionCache) error {
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filename), 0700); err != nil {
+		return err
+	}
+
+	return os.WriteFile(filename, data, 0600)
+}
@@ -0,0 +1,147 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package util_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/gardener/gardenctl-v2/internal/fake"
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/config"
+	"github.com/gardener/gardenctl-v2/pkg/target"
+)
+
+type completionClock struct {
+	now time.Time
+}
+
+func (c *completionClock) Now() time.Time {
+	return c.now
+}
+
+var _ = Describe("Completion Cache", func() {
+	var (
+		cacheDir      string
+		cfg           *config.Config
+		manager       target.Manager
+		currentTarget target.Target
+		clock         *completionClock
+		calls         int
+		values        []string
+		compute       func() ([]string, error)
+	)
+
+	BeforeEach(func() {
+		var err error
+		cacheDir, err = os.MkdirTemp("", "gctlv2-cache-")
+		Expect(err).NotTo(HaveOccurred())
+
+		cfg = &config.Config{
+			CacheDir: cacheDir,
+			Gardens:  []config.Garden{{Name: "garden"}},
+		}
+		currentTarget = target.NewTarget("garden", "project", "", "")
+		manager, err = target.NewManager(cfg, fake.NewFakeTargetProvider(currentTarget), nil, "")
+		Expect(err).NotTo(HaveOccurred())
+
+		clock = &completionClock{now: time.Date(2022, 4, 1, 12, 0, 0, 0, time.UTC)}
+		util.SetCompletionCacheClock(clock)
+
+		calls = 0
+		values = []string{"foo", "bar"}
+		compute = func() ([]string, error) {
+			calls++
+			return values, nil
+		}
+	})
+
+	AfterEach(func() {
+		util.SetCompletionCacheClock(&util.RealClock{})
+		Expect(os.RemoveAll(cacheDir)).To(Succeed())
+	})
+
+	It("should return cached values until they expire", func() {
+		Expect(util.CachedCompletion(manager, currentTarget, "shoots", compute)).To(Equal([]string{"foo", "bar"}))
+		Expect(util.CompletionCacheFile(cfg.CacheDir, "garden")).To(BeARegularFile())
+
+		values = []string{"baz"}
+		clock.now = clock.now.Add(20 * time.Second)
+		Expect(util.CachedCompletion(manager, currentTarget, "shoots", compute)).To(Equal([]string{"foo", "bar"}))
+		Expect(calls).To(Equal(1))
+
+		clock.now = clock.now.Add(20 * time.Second)
+		Expect(util.CachedCompletion(manager, currentTarget, "shoots", compute)).To(Equal([]string{"baz"}))
+		Expect(calls).To(Equal(2))
+	})
+
+	It("should respect the configured cache TTL", func() {
+		cfg.Completion = &config.Completion{CacheTTL: "5m"}
+		Expect(util.CachedCompletion(manager, currentTarget, "shoots", compute)).To(Equal([]string{"foo", "bar"}))
+
+		clock.now = clock.now.Add(4 * time.Minute)
+		Expect(util.CachedCompletion(manager, currentTarget, "shoots", compute)).To(Equal([]string{"foo", "bar"}))
+		Expect(calls).To(Equal(1))
+
+		cfg.Completion.CacheTTL = "0s"
+		Expect(util.CachedCompletion(manager, currentTarget, "shoots", compute)).To(Equal([]string{"foo", "bar"}))
+		Expect(calls).To(Equal(2))
+	})
+
+	It("should cache the values per kind", func() {
+		Expect(util.CachedCompletion(manager, currentTarget, "projects", compute)).To(Equal([]string{"foo", "bar"}))
+
+		values = []string{"baz"}
+		Expect(util.CachedCompletion(manager, currentTarget, "seeds", compute)).To(Equal([]string{"baz"}))
+		Expect(util.CachedCompletion(manager, currentTarget, "projects", compute)).To(Equal([]string{"foo", "bar"}))
+		Expect(calls).To(Equal(2))
+	})
+
+	It("should invalidate the cache when the target changes", func() {
+		Expect(util.CachedCompletion(manager, currentTarget, "shoots", compute)).To(Equal([]string{"foo", "bar"}))
+
+		values = []string{"baz"}
+		Expect(util.CachedCompletion(manager, currentTarget.WithProjectName("other"), "shoots", compute)).To(Equal([]string{"baz"}))
+		Expect(util.CachedCompletion(manager, currentTarget, "shoots", compute)).To(Equal([]string{"baz"}))
+		Expect(calls).To(Equal(3))
+	})
+
+	It("should not cache errors", func() {
+		_, err := util.CachedCompletion(manager, currentTarget, "shoots", func() ([]string, error) {
+			return nil, errors.New("list failed")
+		})
+		Expect(err).To(MatchError("list failed"))
+
+		Expect(util.CachedCompletion(manager, currentTarget, "shoots", compute)).To(Equal([]string{"foo", "bar"}))
+		Expect(calls).To(Equal(1))
+	})
+
+	It("should return expired values if the garden is unreachable", func() {
+		Expect(util.CachedCompletion(manager, currentTarget, "shoots", compute)).To(Equal([]string{"foo", "bar"}))
+
+		clock.now = clock.now.Add(time.Hour)
+		offline := func() ([]string, error) {
+			return nil, fmt.Errorf("failed to create garden cluster client: %w", target.ErrOffline)
+		}
+		Expect(util.CachedCompletion(manager, currentTarget, "shoots", offline)).To(Equal([]string{"foo", "bar"}))
+
+		_, err := util.CachedCompletion(manager, currentTarget, "seeds", offline)
+		Expect(err).To(MatchError(ContainSubstring(target.ErrOffline.Error())))
+	})
+
+	It("should not cache without a cache directory", func() {
+		cfg.CacheDir = ""
+		Expect(util.CachedCompletion(manager, currentTarget, "shoots", compute)).To(Equal([]string{"foo", "bar"}))
+		Expect(util.CachedCompletion(manager, currentTarget, "shoots", compute)).To(Equal([]string{"foo", "bar"}))
+		Expect(calls).To(Equal(2))
+	})
+})
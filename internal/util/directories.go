@@ -0,0 +1,119 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/mitchellh/go-homedir"
+)
+
+const (
+	// EnvGardenHomeDir is the environment variable that overrides the gardenctl home directory
+	EnvGardenHomeDir = "GCTL_HOME"
+	// EnvCacheDir is the environment variable that overrides the cache directory
+	EnvCacheDir = "GCTL_CACHE_DIR"
+	// EnvTokenDir is the environment variable that overrides the token directory
+	EnvTokenDir = "GCTL_TOKEN_DIR"
+	// EnvSessionsDir is the environment variable that overrides the parent directory of the session directories
+	EnvSessionsDir = "GCTL_SESSIONS_DIR"
+
+	// LegacyGardenHomeFolder is the folder in the home directory of the user that contained all gardenctl files
+	// before the XDG base directory layout has been introduced
+	LegacyGardenHomeFolder = ".garden"
+
+	// xdgFolder is the folder of gardenctl in the XDG base directories
+	xdgFolder = "gardenctl"
+)
+
+// Directories are the directories in which gardenctl stores its configuration and state
+type Directories struct {
+	// Home is the gardenctl home directory, which contains the configuration file and the templates
+	Home string
+	// Cache contains the cached completion values and kubeconfigs of kubeconfigExec commands
+	Cache string
+	// Tokens contains the cached tokens of garden logins
+	Tokens string
+}
+
+// LegacyDirectories returns the directories of the ~/.garden layout
+func LegacyDirectories() (Directories, error) {
+	home, err := homedir.Dir()
+	if err != nil {
+		return Directories{}, err
+	}
+
+	return HomeDirectories(filepath.Join(home, LegacyGardenHomeFolder)), nil
+}
+
+// HomeDirectories returns the directories of the layout in which the cache and the tokens are stored in the gardenctl home directory
+func HomeDirectories(home string) Directories {
+	return Directories{
+		Home:   home,
+		Cache:  filepath.Join(home, "cache"),
+		Tokens: filepath.Join(home, "tokens"),
+	}
+}
+
+// XDGDirectories returns the directories of the XDG base directory layout, i.e. $XDG_CONFIG_HOME/gardenctl,
+// $XDG_CACHE_HOME/gardenctl and $XDG_STATE_HOME/gardenctl/tokens with the defaults of the XDG base directory specification
+func XDGDirectories() (Directories, error) {
+	home, err := homedir.Dir()
+	if err != nil {
+		return Directories{}, err
+	}
+
+	xdgDir := func(env string, defaultDir ...string) string {
+		if dir := os.Getenv(env); filepath.IsAbs(dir) {
+			return filepath.Join(dir, xdgFolder)
+		}
+
+		return filepath.Join(append(append([]string{home}, defaultDir...), xdgFolder)...)
+	}
+
+	return Directories{
+		Home:   xdgDir("XDG_CONFIG_HOME", ".config"),
+		Cache:  xdgDir("XDG_CACHE_HOME", ".cache"),
+		Tokens: filepath.Join(xdgDir("XDG_STATE_HOME", ".local", "state"), "tokens"),
+	}, nil
+}
+
+// DefaultDirectories returns the directories that are used if they are not overridden. The XDG base directory layout is
+// used on Linux, unless the ~/.garden directory exists. It is moved to the XDG base directories with gardenctl config migrate-directories.
+func DefaultDirectories() (Directories, error) {
+	return defaultDirectories(runtime.GOOS)
+}
+
+func defaultDirectories(goos string) (Directories, error) {
+	legacy, err := LegacyDirectories()
+	if err != nil {
+		return Directories{}, err
+	}
+
+	if goos != "linux" {
+		return legacy, nil
+	}
+
+	if _, err := os.Stat(legacy.Home); err == nil {
+		return legacy, nil
+	}
+
+	return XDGDirectories()
+}
+
+// firstDirectory returns the first of the given directories that is not empty
+func firstDirectory(dirs ...string) string {
+	for _, dir := range dirs {
+		if dir != "" {
+			return dir
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,66 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package util_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/gardener/gardenctl-v2/internal/util"
+)
+
+var _ = Describe("Directories", func() {
+	var env map[string]string
+
+	BeforeEach(func() {
+		env = map[string]string{}
+
+		for _, key := range []string{"XDG_CONFIG_HOME", "XDG_CACHE_HOME", "XDG_STATE_HOME"} {
+			if value, ok := os.LookupEnv(key); ok {
+				env[key] = value
+			}
+		}
+	})
+
+	AfterEach(func() {
+		for _, key := range []string{"XDG_CONFIG_HOME", "XDG_CACHE_HOME", "XDG_STATE_HOME"} {
+			if value, ok := env[key]; ok {
+				Expect(os.Setenv(key, value)).To(Succeed())
+			} else {
+				Expect(os.Unsetenv(key)).To(Succeed())
+			}
+		}
+	})
+
+	It("should return the XDG base directories of the environment variables", func() {
+		Expect(os.Setenv("XDG_CONFIG_HOME", "/xdg/config")).To(Succeed())
+		Expect(os.Setenv("XDG_CACHE_HOME", "/xdg/cache")).To(Succeed())
+		Expect(os.Setenv("XDG_STATE_HOME", "relative/paths/are/ignored")).To(Succeed())
+
+		home, err := os.UserHomeDir()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(util.XDGDirectories()).To(Equal(util.Directories{
+			Home:   filepath.Join("/xdg/config", "gardenctl"),
+			Cache:  filepath.Join("/xdg/cache", "gardenctl"),
+			Tokens: filepath.Join(home, ".local", "state", "gardenctl", "tokens"),
+		}))
+	})
+
+	It("should use the ~/.garden directory on other operating systems than Linux", func() {
+		legacy, err := util.LegacyDirectories()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(legacy.Cache).To(Equal(filepath.Join(legacy.Home, "cache")))
+		Expect(legacy.Tokens).To(Equal(filepath.Join(legacy.Home, "tokens")))
+
+		Expect(util.DefaultDirectoriesFor("darwin")).To(Equal(legacy))
+		Expect(util.DefaultDirectoriesFor("windows")).To(Equal(legacy))
+	})
+})
@@ -0,0 +1,20 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package util
+
+var (
+	GetSessionID        = getSessionID
+	ParentProcessID     = parentProcessID
+	RemoveStaleSessions = removeStaleSessions
+	ShouldCleanup       = shouldCleanup
+
+	DefaultDirectoriesFor = defaultDirectories
+)
+
+func SetCompletionCacheClock(clock Clock) {
+	completionCacheClock = clock
+}
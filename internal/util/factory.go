@@ -9,6 +9,7 @@ package util
 import (
 	"context"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net"
 	"net/http"
@@ -16,7 +17,13 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
 
+	"github.com/mitchellh/go-homedir"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/gardener/gardenctl-v2/internal/audit"
 	"github.com/gardener/gardenctl-v2/pkg/config"
 	"github.com/gardener/gardenctl-v2/pkg/target"
 )
@@ -43,6 +50,8 @@ type Factory interface {
 	GardenHomeDir() string
 	// Manager returns the target manager used to read and change the currently targeted system.
 	Manager() (target.Manager, error)
+	// Clientset returns a Kubernetes clientset for the cluster of the client configuration.
+	Clientset(clientConfig clientcmd.ClientConfig) (kubernetes.Interface, error)
 	// PublicIPs returns the current host's public IP addresses. It's
 	// recommended to provide a context with a timeout/deadline. The
 	// returned slice can contain IPv6, IPv4 or both, in no particular
@@ -53,9 +62,9 @@ type Factory interface {
 // FactoryImpl implements util.Factory interface
 type FactoryImpl struct {
 	// GardenHomeDirectory is the home directory for all gardenctl
-	// related files. While some files can be explicitly loaded from
-	// different locations, cache files will always be placed inside
-	// the garden home.
+	// related files. Unless the gardenctl home directory is the XDG
+	// config directory or other directories are configured, cache
+	// files are placed inside the garden home.
 	GardenHomeDirectory string
 
 	// ConfigFile is the location of the gardenctlv2 configuration file.
@@ -63,9 +72,53 @@ type FactoryImpl struct {
 	// if empty.
 	ConfigFile string
 
+	// ConfigSources is a list of additional gardenctl configuration files
+	// that are merged into the configuration file in descending order of
+	// precedence. These files are never written by gardenctl.
+	ConfigSources []string
+
 	// TargetFlags can be used to completely override the target configuration
 	// stored on the filesystem via a CLI flags.
 	TargetFlags target.TargetFlags
+
+	// Quiet discards the informational messages and warnings that commands
+	// write to stderr. Errors are still printed.
+	Quiet bool
+
+	// NoColor disables colored output.
+	NoColor bool
+
+	// Offline prevents any request to the garden, seed and shoot clusters. Commands that
+	// require access to a cluster fail, cached data is used where possible.
+	Offline bool
+
+	// ClientPolicy holds the values of the client policy that are set by CLI flags,
+	// they override the client policy of the gardenctl configuration.
+	ClientPolicy config.ClientPolicy
+
+	// KubectlPlugin is true if gardenctl has been invoked as kubectl plugin, i.e. as
+	// kubectl garden. The session is then bound to the shell that executed kubectl.
+	KubectlPlugin bool
+
+	// DryRun is the dry-run strategy of the --dry-run flag. In dry-run mode, the changes to the configuration,
+	// the target and the cluster resources are printed to DryRunOut instead of being persisted.
+	DryRun config.DryRunStrategy
+
+	// DryRunOut receives the changes that would have been made in dry-run mode.
+	DryRunOut io.Writer
+
+	// Command is the path of the executed command, e.g. gardenctl shoot hibernate. It is recorded in the audit log.
+	Command string
+
+	// ErrOut receives the warnings that are not related to the output of a command, e.g. if the audit log
+	// cannot be written.
+	ErrOut io.Writer
+
+	// Confirm asks the user to confirm changes to protected gardens. The changes are denied if it is not set.
+	Confirm func(question string) (bool, error)
+
+	// confirmation remembers the answers of the user, so that they are shared by all managers of the command
+	confirmation *config.Confirmation
 }
 
 var _ Factory = &FactoryImpl{}
@@ -75,33 +128,114 @@ func (f *FactoryImpl) Context() context.Context {
 }
 
 func (f *FactoryImpl) Manager() (target.Manager, error) {
-	cfg, err := config.LoadFromFile(f.ConfigFile)
+	cfg, err := config.LoadFromFiles(f.ConfigFile, f.ConfigSources...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
 
-	sid, err := getSessionID()
+	// the directories of the environment variables take precedence over the directories of the configuration
+	dirs := f.defaultDirectories()
+	cfg.CacheDir = firstDirectory(envDirectory(EnvCacheDir), cfg.CacheDirectory(), dirs.Cache)
+	cfg.TokenDir = firstDirectory(envDirectory(EnvTokenDir), cfg.TokenDirectory(), dirs.Tokens)
+
+	sid, err := getSessionID(f.KubectlPlugin)
 	if err != nil {
 		return nil, err
 	}
 
-	sessionDirectory := filepath.Join(os.TempDir(), "garden", sid)
+	sessionsDirectory := firstDirectory(envDirectory(EnvSessionsDir), cfg.SessionsDirectory(), filepath.Join(os.TempDir(), "garden"))
+	sessionDirectory := filepath.Join(sessionsDirectory, sid)
 
 	err = os.MkdirAll(sessionDirectory, 0700)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create session directory: %w", err)
 	}
 
+	// mark the session as used, so that only the sessions of closed shells are removed
+	now := f.Clock().Now()
+	if err := os.Chtimes(sessionDirectory, now, now); err != nil {
+		return nil, fmt.Errorf("failed to update session directory: %w", err)
+	}
+
+	// nothing is removed in dry-run mode
+	if cfg.AutomaticCleanup() && f.DryRun == config.DryRunNone && shouldCleanup(sessionsDirectory, now) {
+		Cleanup(CleanupDirectories{
+			SessionsDirectory: sessionsDirectory,
+			CacheDirectory:    cfg.CacheDir,
+			TokenDirectory:    cfg.TokenDir,
+		}, now.Add(-cfg.CleanupRetention()))
+	}
+
 	targetProvider := target.NewTargetProvider(filepath.Join(sessionDirectory, "target.yaml"), f.TargetFlags)
-	clientProvider := target.NewClientProvider()
+	clientProvider := target.NewClientProvider(cfg.ClientPolicy.WithOverrides(f.ClientPolicy))
+	if f.Offline {
+		cfg.Offline = true
+		clientProvider = target.NewOfflineClientProvider()
+	}
+
+	if f.confirmation == nil {
+		f.confirmation = &config.Confirmation{Confirm: f.Confirm}
+	}
+
+	cfg.Confirmation = f.confirmation
+
+	if auditFile := cfg.AuditFile(); auditFile != "" {
+		cfg.Audit = f.auditLogger(auditFile, targetProvider)
+		clientProvider = target.NewAuditClientProvider(clientProvider, cfg.Audit)
+	}
+
+	if f.DryRun != config.DryRunNone {
+		cfg.DryRun = &config.DryRun{
+			Strategy: f.DryRun,
+			Out:      f.DryRunOut,
+		}
+		clientProvider = target.NewDryRunClientProvider(clientProvider, *cfg.DryRun)
+	}
 
 	return target.NewManager(cfg, targetProvider, clientProvider, sessionDirectory)
 }
 
+// auditLogger returns the logger of the audit log, the entries are recorded with the target of the session
+// at the time the command has been started
+func (f *FactoryImpl) auditLogger(filename string, targetProvider target.TargetProvider) *audit.Logger {
+	logger := &audit.Logger{
+		Filename: filename,
+		Command:  f.Command,
+		User:     audit.CurrentUser(),
+		ErrOut:   f.ErrOut,
+		Now:      f.Clock().Now,
+	}
+
+	if t, err := targetProvider.Read(); err == nil {
+		logger.Target = target.AuditTarget(t)
+	}
+
+	return logger
+}
+
 func (f *FactoryImpl) GardenHomeDir() string {
 	return f.GardenHomeDirectory
 }
 
+// defaultDirectories returns the directories of the XDG base directory layout if the gardenctl home directory is
+// the XDG config directory, otherwise the cache and the tokens are stored in the gardenctl home directory
+func (f *FactoryImpl) defaultDirectories() Directories {
+	if xdg, err := XDGDirectories(); err == nil && xdg.Home == f.GardenHomeDirectory {
+		return xdg
+	}
+
+	return HomeDirectories(f.GardenHomeDirectory)
+}
+
+func (f *FactoryImpl) Clientset(clientConfig clientcmd.ClientConfig) (kubernetes.Interface, error) {
+	restConfig, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return kubernetes.NewForConfig(restConfig)
+}
+
 func (f *FactoryImpl) Clock() Clock {
 	return &RealClock{}
 }
@@ -154,7 +288,17 @@ func callIPify(ctx context.Context, domain string) (*net.IP, error) {
 	return &netIP, nil
 }
 
-func getSessionID() (string, error) {
+// envDirectory returns the directory of the environment variable with an expanded leading ~
+func envDirectory(key string) string {
+	dir, err := homedir.Expand(os.Getenv(key))
+	if err != nil {
+		return ""
+	}
+
+	return dir
+}
+
+func getSessionID(kubectlPlugin bool) (string, error) {
 	if value, ok := os.LookupEnv(envSessionID); ok {
 		if sidRegexp.MatchString(value) {
 			return value, nil
@@ -170,5 +314,44 @@ func getSessionID() (string, error) {
 		}
 	}
 
-	return "", fmt.Errorf("Environment variable %s is required. Use \"gardenctl help\" for more information about the requirements of gardenctl", envSessionID)
+	// fall back to the process ID of the parent process, which usually is the shell of the terminal
+	ppid := os.Getppid()
+
+	// the parent process of a kubectl plugin is kubectl, which is started by the shell
+	if kubectlPlugin {
+		if pid, err := parentProcessID(ppid); err == nil {
+			ppid = pid
+		}
+	}
+
+	return fmt.Sprintf("ppid-%d", ppid), nil
+}
+
+// removeStaleSessions removes the session directories that have not been used since the given time and returns their paths.
+// Sessions are removed on a best effort basis, errors are ignored as they must not prevent using gardenctl.
+func removeStaleSessions(sessionsDirectory string, notUsedSince time.Time) []string {
+	entries, err := os.ReadDir(sessionsDirectory)
+	if err != nil {
+		return nil
+	}
+
+	var removed []string
+
+	for _, entry := range entries {
+		if !entry.IsDir() || !sidRegexp.MatchString(entry.Name()) {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil || !info.ModTime().Before(notUsedSince) {
+			continue
+		}
+
+		path := filepath.Join(sessionsDirectory, entry.Name())
+		if err := os.RemoveAll(path); err == nil {
+			removed = append(removed, path)
+		}
+	}
+
+	return removed
 }
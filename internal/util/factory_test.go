@@ -0,0 +1,96 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package util_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/gardener/gardenctl-v2/internal/util"
+)
+
+var _ = Describe("Factory", func() {
+	Describe("getting the session ID", func() {
+		var env map[string]string
+
+		BeforeEach(func() {
+			env = map[string]string{}
+
+			for _, key := range []string{"GCTL_SESSION_ID", "TERM_SESSION_ID"} {
+				if value, ok := os.LookupEnv(key); ok {
+					env[key] = value
+				}
+
+				Expect(os.Unsetenv(key)).To(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			for _, key := range []string{"GCTL_SESSION_ID", "TERM_SESSION_ID"} {
+				Expect(os.Unsetenv(key)).To(Succeed())
+			}
+
+			for key, value := range env {
+				Expect(os.Setenv(key, value)).To(Succeed())
+			}
+		})
+
+		It("should use GCTL_SESSION_ID", func() {
+			Expect(os.Setenv("GCTL_SESSION_ID", "my-session")).To(Succeed())
+			Expect(os.Setenv("TERM_SESSION_ID", "w0t0p0:8C6B9A39-D6D5-4D3A-9F8A-8F0F5A9E1D2B")).To(Succeed())
+			Expect(util.GetSessionID(false)).To(Equal("my-session"))
+
+			Expect(os.Setenv("GCTL_SESSION_ID", "../foo")).To(Succeed())
+			_, err := util.GetSessionID(false)
+			Expect(err).To(MatchError(ContainSubstring("must only contain alphanumeric characters")))
+		})
+
+		It("should use the UUID of TERM_SESSION_ID", func() {
+			Expect(os.Setenv("TERM_SESSION_ID", "w0t0p0:8C6B9A39-D6D5-4D3A-9F8A-8F0F5A9E1D2B")).To(Succeed())
+			Expect(util.GetSessionID(false)).To(Equal("8c6b9a39-d6d5-4d3a-9f8a-8f0f5a9e1d2b"))
+		})
+
+		It("should fall back to the parent process ID", func() {
+			Expect(util.GetSessionID(false)).To(Equal(fmt.Sprintf("ppid-%d", os.Getppid())))
+		})
+
+		It("should fall back to the grandparent process ID for kubectl plugins", func() {
+			ppid, err := util.ParentProcessID(os.Getppid())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(util.GetSessionID(true)).To(Equal(fmt.Sprintf("ppid-%d", ppid)))
+		})
+	})
+
+	Describe("removing stale sessions", func() {
+		It("should only remove sessions that have not been used since the given time", func() {
+			dir, err := os.MkdirTemp("", "garden-*")
+			Expect(err).NotTo(HaveOccurred())
+			defer os.RemoveAll(dir)
+
+			now := time.Now()
+			for name, modTime := range map[string]time.Time{
+				"stale":  now.Add(-8 * 24 * time.Hour),
+				"active": now.Add(-time.Hour),
+			} {
+				sessionDir := filepath.Join(dir, name)
+				Expect(os.Mkdir(sessionDir, 0700)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(sessionDir, "target.yaml"), nil, 0600)).To(Succeed())
+				Expect(os.Chtimes(sessionDir, modTime, modTime)).To(Succeed())
+			}
+
+			util.RemoveStaleSessions(dir, now.Add(-7*24*time.Hour))
+
+			Expect(filepath.Join(dir, "stale")).NotTo(BeADirectory())
+			Expect(filepath.Join(dir, "active")).To(BeADirectory())
+		})
+	})
+})
@@ -32,6 +32,35 @@ func NewIOStreams() IOStreams {
 	}
 }
 
+// NewQuietWriter returns a writer that writes to w unless quiet returns true, in which case everything is discarded.
+// quiet is evaluated on every write, so that it can depend on flags that are parsed after the writer has been created.
+func NewQuietWriter(w io.Writer, quiet func() bool) io.Writer {
+	return &quietWriter{writer: w, quiet: quiet}
+}
+
+// UnwrapQuietWriter returns the writer that has been wrapped by NewQuietWriter or w itself if it is not a quiet writer.
+// It is used for the stderr of child processes like kubectl, whose errors must not be discarded with --quiet.
+func UnwrapQuietWriter(w io.Writer) io.Writer {
+	if q, ok := w.(*quietWriter); ok {
+		return q.writer
+	}
+
+	return w
+}
+
+type quietWriter struct {
+	writer io.Writer
+	quiet  func() bool
+}
+
+func (w *quietWriter) Write(p []byte) (int, error) {
+	if w.quiet() {
+		return len(p), nil
+	}
+
+	return w.writer.Write(p)
+}
+
 // NewTestIOStreams returns a valid IOStreams and in, out, errout buffers for unit tests
 func NewTestIOStreams() (IOStreams, *SafeBytesBuffer, *SafeBytesBuffer, *SafeBytesBuffer) {
 	in := &SafeBytesBuffer{}
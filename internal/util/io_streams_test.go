@@ -0,0 +1,36 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package util_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/gardener/gardenctl-v2/internal/util"
+)
+
+var _ = Describe("Quiet Writer", func() {
+	It("should discard the output while quiet", func() {
+		quiet := false
+		out := &util.SafeBytesBuffer{}
+		w := util.NewQuietWriter(out, func() bool {
+			return quiet
+		})
+
+		fmt.Fprint(w, "visible ")
+		quiet = true
+		n, err := fmt.Fprint(w, "discarded ")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(n).To(Equal(len("discarded ")))
+		quiet = false
+		fmt.Fprint(w, "visible")
+
+		Expect(out.String()).To(Equal("visible visible"))
+	})
+})
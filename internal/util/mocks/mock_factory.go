@@ -11,6 +11,8 @@ import (
 	util "github.com/gardener/gardenctl-v2/internal/util"
 	target "github.com/gardener/gardenctl-v2/pkg/target"
 	gomock "github.com/golang/mock/gomock"
+	kubernetes "k8s.io/client-go/kubernetes"
+	clientcmd "k8s.io/client-go/tools/clientcmd"
 )
 
 // MockFactory is a mock of Factory interface.
@@ -36,6 +38,21 @@ func (m *MockFactory) EXPECT() *MockFactoryMockRecorder {
 	return m.recorder
 }
 
+// Clientset mocks base method.
+func (m *MockFactory) Clientset(arg0 clientcmd.ClientConfig) (kubernetes.Interface, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Clientset", arg0)
+	ret0, _ := ret[0].(kubernetes.Interface)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Clientset indicates an expected call of Clientset.
+func (mr *MockFactoryMockRecorder) Clientset(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Clientset", reflect.TypeOf((*MockFactory)(nil).Clientset), arg0)
+}
+
 // Clock mocks base method.
 func (m *MockFactory) Clock() util.Clock {
 	m.ctrl.T.Helper()
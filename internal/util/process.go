@@ -0,0 +1,37 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package util
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// parentProcessID returns the process ID of the parent of the given process. It is read from
+// the proc filesystem on Linux and determined with ps on other Unix systems.
+func parentProcessID(pid int) (int, error) {
+	if stat, err := ioutil.ReadFile(fmt.Sprintf("/proc/%d/stat", pid)); err == nil {
+		// the command name in the second field may contain spaces and parentheses,
+		// the parent process ID is the second field after the closing parenthesis
+		fields := strings.Fields(string(stat[strings.LastIndex(string(stat), ")")+1:]))
+		if len(fields) < 2 {
+			return 0, fmt.Errorf("failed to parse /proc/%d/stat", pid)
+		}
+
+		return strconv.Atoi(fields[1])
+	}
+
+	out, err := exec.Command("ps", "-o", "ppid=", "-p", strconv.Itoa(pid)).Output()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get parent process of process %d: %w", pid, err)
+	}
+
+	return strconv.Atoi(strings.TrimSpace(string(out)))
+}
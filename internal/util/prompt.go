@@ -0,0 +1,227 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package util
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Confirm prints the given question to the output stream and reads the answer from the input stream.
+// It returns true only if the answer is "y" or "yes" (case-insensitive).
+func Confirm(ioStreams IOStreams, question string) (bool, error) {
+	fmt.Fprintf(ioStreams.Out, "%s [y/N]: ", question)
+
+	answer, _, err := readAnswer(bufio.NewReader(ioStreams.In))
+	if err != nil {
+		return false, err
+	}
+
+	return isYes(answer), nil
+}
+
+// Prompter asks a series of questions on the given IOStreams. In contrast to Confirm, it can be used for
+// multiple prompts because all answers are read from the same buffered reader.
+type Prompter struct {
+	ioStreams IOStreams
+	reader    *bufio.Reader
+}
+
+// NewPrompter returns a new Prompter that reads the answers from the input stream of the given IOStreams.
+func NewPrompter(ioStreams IOStreams) *Prompter {
+	return &Prompter{
+		ioStreams: ioStreams,
+		reader:    bufio.NewReader(ioStreams.In),
+	}
+}
+
+// Input asks for a value and returns the trimmed answer or the default value if the answer is empty.
+// If validate is not nil and the value is invalid, the error is printed and the question is asked again.
+func (p *Prompter) Input(question, defaultValue string, validate func(string) error) (string, error) {
+	for {
+		if defaultValue != "" {
+			fmt.Fprintf(p.ioStreams.Out, "%s [%s]: ", question, defaultValue)
+		} else {
+			fmt.Fprintf(p.ioStreams.Out, "%s: ", question)
+		}
+
+		answer, eof, err := readAnswer(p.reader)
+		if err != nil {
+			return "", err
+		}
+
+		if answer == "" {
+			answer = defaultValue
+		}
+
+		if validate == nil {
+			return answer, nil
+		}
+
+		err = validate(answer)
+		if err == nil {
+			return answer, nil
+		}
+
+		if eof {
+			return "", fmt.Errorf("invalid answer: %w", err)
+		}
+
+		fmt.Fprintf(p.ioStreams.Out, "Invalid answer: %v\n", err)
+	}
+}
+
+// Select asks to choose one of the given options by number and returns the selected option.
+// The option with the index defaultIndex is selected if the answer is empty.
+func (p *Prompter) Select(question string, options []string, defaultIndex int) (string, error) {
+	if len(options) == 0 {
+		return "", errors.New("there are no options to select from")
+	}
+
+	fmt.Fprintf(p.ioStreams.Out, "%s\n", question)
+
+	for i, option := range options {
+		fmt.Fprintf(p.ioStreams.Out, "  %d) %s\n", i+1, option)
+	}
+
+	answer, err := p.Input("Enter a number", strconv.Itoa(defaultIndex+1), func(answer string) error {
+		if i, err := strconv.Atoi(answer); err != nil || i < 1 || i > len(options) {
+			return fmt.Errorf("must be a number between 1 and %d", len(options))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	i, _ := strconv.Atoi(answer)
+
+	return options[i-1], nil
+}
+
+// maxFilterOptions is the maximum number of options that are listed by Filter
+const maxFilterOptions = 20
+
+// Filter asks to choose one of the given options and returns the index of the selected option.
+// The options can be narrowed down incrementally by entering a fuzzy filter, i.e. the characters of the filter
+// must occur in the option in the same order. An option is selected by entering its number, or by an empty answer
+// if it is the only one that matches the filter.
+func (p *Prompter) Filter(question string, options []string) (int, error) {
+	if len(options) == 0 {
+		return -1, errors.New("there are no options to select from")
+	}
+
+	fmt.Fprintf(p.ioStreams.Out, "%s\n", question)
+
+	filter := ""
+	matches := fuzzyFilter(filter, options)
+
+	for {
+		for i, m := range matches {
+			if i == maxFilterOptions {
+				fmt.Fprintf(p.ioStreams.Out, "  ... and %d more, enter a filter to narrow down the list\n", len(matches)-maxFilterOptions)
+				break
+			}
+
+			fmt.Fprintf(p.ioStreams.Out, "  %d) %s\n", i+1, options[m])
+		}
+
+		fmt.Fprint(p.ioStreams.Out, "Enter a number or a filter: ")
+
+		answer, eof, err := readAnswer(p.reader)
+		if err != nil {
+			return -1, err
+		}
+
+		if i, err := strconv.Atoi(answer); err == nil && i >= 1 && i <= len(matches) && i <= maxFilterOptions {
+			return matches[i-1], nil
+		}
+
+		if answer == "" && len(matches) == 1 {
+			return matches[0], nil
+		}
+
+		if eof {
+			return -1, errors.New("no option has been selected")
+		}
+
+		if answer == "" {
+			continue
+		}
+
+		if m := fuzzyFilter(answer, options); len(m) > 0 {
+			filter = answer
+			matches = m
+		} else {
+			fmt.Fprintf(p.ioStreams.Out, "No option matches %q\n", answer)
+		}
+
+		fmt.Fprintf(p.ioStreams.Out, "Options matching %q:\n", filter)
+	}
+}
+
+// fuzzyFilter returns the indices of the options that contain the characters of the filter in the same order, ignoring case
+func fuzzyFilter(filter string, options []string) []int {
+	matches := []int{}
+
+	for i, option := range options {
+		if fuzzyMatch(strings.ToLower(filter), strings.ToLower(option)) {
+			matches = append(matches, i)
+		}
+	}
+
+	return matches
+}
+
+func fuzzyMatch(filter, s string) bool {
+	for _, r := range filter {
+		i := strings.IndexRune(s, r)
+		if i < 0 {
+			return false
+		}
+
+		s = s[i+len(string(r)):]
+	}
+
+	return true
+}
+
+// Confirm asks a yes/no question and returns true only if the answer is "y" or "yes" (case-insensitive).
+func (p *Prompter) Confirm(question string) (bool, error) {
+	fmt.Fprintf(p.ioStreams.Out, "%s [y/N]: ", question)
+
+	answer, _, err := readAnswer(p.reader)
+	if err != nil {
+		return false, err
+	}
+
+	return isYes(answer), nil
+}
+
+// readAnswer reads a single line and returns it trimmed. It returns true if the end of the input has been reached.
+func readAnswer(reader *bufio.Reader) (string, bool, error) {
+	answer, err := reader.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", false, fmt.Errorf("failed to read answer: %w", err)
+	}
+
+	return strings.TrimSpace(answer), err != nil, nil
+}
+
+func isYes(answer string) bool {
+	switch strings.ToLower(answer) {
+	case "y", "yes":
+		return true
+	default:
+		return false
+	}
+}
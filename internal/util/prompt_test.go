@@ -0,0 +1,131 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package util_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/gardener/gardenctl-v2/internal/util"
+)
+
+var _ = Describe("Prompt Utilities", func() {
+	DescribeTable("asking for confirmation",
+		func(answer string, expected bool) {
+			streams, in, out, _ := util.NewTestIOStreams()
+			_, err := in.Write([]byte(answer))
+			Expect(err).NotTo(HaveOccurred())
+
+			confirmed, err := util.Confirm(streams, "Continue?")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(confirmed).To(Equal(expected))
+			Expect(out.String()).To(Equal("Continue? [y/N]: "))
+		},
+		Entry("when the answer is y", "y\n", true),
+		Entry("when the answer is YES", "YES\n", true),
+		Entry("when the answer is n", "n\n", false),
+		Entry("when the answer is empty", "\n", false),
+		Entry("when there is no input", "", false),
+		Entry("when the answer is not terminated by a newline", " yes ", true),
+	)
+
+	Describe("Prompter", func() {
+		var (
+			streams util.IOStreams
+			in      *util.SafeBytesBuffer
+			out     *util.SafeBytesBuffer
+			p       *util.Prompter
+		)
+
+		BeforeEach(func() {
+			streams, in, out, _ = util.NewTestIOStreams()
+			p = util.NewPrompter(streams)
+		})
+
+		It("should read multiple answers", func() {
+			_, err := in.Write([]byte("foo\n\ny\n"))
+			Expect(err).NotTo(HaveOccurred())
+
+			answer, err := p.Input("Name", "", nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(answer).To(Equal("foo"))
+
+			answer, err = p.Input("Context", "default", nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(answer).To(Equal("default"))
+
+			confirmed, err := p.Confirm("Continue?")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(confirmed).To(BeTrue())
+			Expect(out.String()).To(Equal("Name: Context [default]: Continue? [y/N]: "))
+		})
+
+		It("should ask again if the answer is invalid", func() {
+			_, err := in.Write([]byte("foo\nbar\n"))
+			Expect(err).NotTo(HaveOccurred())
+
+			answer, err := p.Input("Name", "", func(answer string) error {
+				if answer != "bar" {
+					return errors.New("must be bar")
+				}
+
+				return nil
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(answer).To(Equal("bar"))
+			Expect(out.String()).To(Equal("Name: Invalid answer: must be bar\nName: "))
+		})
+
+		It("should fail if the last answer is invalid", func() {
+			_, err := in.Write([]byte("foo"))
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = p.Input("Name", "", func(answer string) error {
+				return errors.New("must be bar")
+			})
+			Expect(err).To(MatchError("invalid answer: must be bar"))
+		})
+
+		It("should select an option by number", func() {
+			_, err := in.Write([]byte("3\n2\n"))
+			Expect(err).NotTo(HaveOccurred())
+
+			option, err := p.Select("Context", []string{"a", "b"}, 0)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(option).To(Equal("b"))
+			Expect(out.String()).To(Equal("Context\n  1) a\n  2) b\nEnter a number [1]: Invalid answer: must be a number between 1 and 2\nEnter a number [1]: "))
+		})
+
+		It("should narrow down the options with a fuzzy filter", func() {
+			_, err := in.Write([]byte("prd\nxyz\n2\n"))
+			Expect(err).NotTo(HaveOccurred())
+
+			i, err := p.Filter("Shoot", []string{"dev-cluster", "prod-cluster", "prod-db"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(i).To(Equal(2))
+			Expect(out.String()).To(Equal("Shoot\n" +
+				"  1) dev-cluster\n  2) prod-cluster\n  3) prod-db\nEnter a number or a filter: " +
+				"Options matching \"prd\":\n  1) prod-cluster\n  2) prod-db\nEnter a number or a filter: " +
+				"No option matches \"xyz\"\nOptions matching \"prd\":\n  1) prod-cluster\n  2) prod-db\nEnter a number or a filter: "))
+		})
+
+		It("should select the only matching option and fail without selection", func() {
+			_, err := in.Write([]byte("DB\n\n"))
+			Expect(err).NotTo(HaveOccurred())
+
+			i, err := p.Filter("Shoot", []string{"dev-cluster", "prod-db"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(i).To(Equal(1))
+
+			_, err = p.Filter("Shoot", []string{"dev-cluster", "prod-db"})
+			Expect(err).To(MatchError("no option has been selected"))
+		})
+	})
+})
@@ -0,0 +1,32 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package util
+
+import (
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	corev1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
+)
+
+// IsHibernated returns true if the hibernation of the shoot is enabled
+func IsHibernated(shoot gardencorev1beta1.Shoot) bool {
+	return shoot.Spec.Hibernation != nil && shoot.Spec.Hibernation.Enabled != nil && *shoot.Spec.Hibernation.Enabled
+}
+
+// ShootStatus returns the status of the shoot from its status label, e.g. healthy or unhealthy, followed by
+// (hibernated) if the shoot is hibernated. The status is unknown if the label is not set.
+func ShootStatus(shoot gardencorev1beta1.Shoot) string {
+	status := shoot.Labels[corev1beta1constants.ShootStatus]
+	if status == "" {
+		status = "unknown"
+	}
+
+	if IsHibernated(shoot) {
+		status += " (hibernated)"
+	}
+
+	return status
+}
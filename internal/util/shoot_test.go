@@ -0,0 +1,45 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package util_test
+
+import (
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	"github.com/gardener/gardenctl-v2/internal/util"
+)
+
+var _ = Describe("Shoot Utilities", func() {
+	newShoot := func(status string, hibernated *bool) gardencorev1beta1.Shoot {
+		shoot := gardencorev1beta1.Shoot{ObjectMeta: metav1.ObjectMeta{Name: "myshoot"}}
+
+		if status != "" {
+			shoot.Labels = map[string]string{"shoot.gardener.cloud/status": status}
+		}
+
+		if hibernated != nil {
+			shoot.Spec.Hibernation = &gardencorev1beta1.Hibernation{Enabled: hibernated}
+		}
+
+		return shoot
+	}
+
+	It("should return whether the shoot is hibernated", func() {
+		Expect(util.IsHibernated(newShoot("", nil))).To(BeFalse())
+		Expect(util.IsHibernated(newShoot("", pointer.Bool(false)))).To(BeFalse())
+		Expect(util.IsHibernated(newShoot("", pointer.Bool(true)))).To(BeTrue())
+	})
+
+	It("should return the status of the shoot", func() {
+		Expect(util.ShootStatus(newShoot("", nil))).To(Equal("unknown"))
+		Expect(util.ShootStatus(newShoot("healthy", pointer.Bool(false)))).To(Equal("healthy"))
+		Expect(util.ShootStatus(newShoot("unhealthy", pointer.Bool(true)))).To(Equal("unhealthy (hibernated)"))
+	})
+})
@@ -55,3 +55,23 @@ func StripUnsafe(s string) string {
 		return -1
 	}, s)
 }
+
+// ValueOrNone returns the value or <none> if it is empty, like kubectl prints empty columns
+func ValueOrNone(value string) string {
+	if value == "" {
+		return "<none>"
+	}
+
+	return value
+}
+
+// ContainsString returns true if the values contain the given value
+func ContainsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -32,4 +32,19 @@ var _ = Describe("String Utilities", func() {
 			Expect(util.ShellEscape("a", "b")).To(Equal("'a' 'b'"))
 		})
 	})
+
+	Describe("printing empty values", func() {
+		It("should print <none> for empty values", func() {
+			Expect(util.ValueOrNone("")).To(Equal("<none>"))
+			Expect(util.ValueOrNone("value")).To(Equal("value"))
+		})
+	})
+
+	Describe("looking up strings", func() {
+		It("should return whether the value is contained", func() {
+			Expect(util.ContainsString([]string{"a", "b"}, "b")).To(BeTrue())
+			Expect(util.ContainsString([]string{"a", "b"}, "c")).To(BeFalse())
+			Expect(util.ContainsString(nil, "a")).To(BeFalse())
+		})
+	})
 })
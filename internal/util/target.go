@@ -24,12 +24,19 @@ func ShootForTarget(ctx context.Context, gardenClient gardenclient.Client, t tar
 }
 
 // ShootNamesForTarget returns all possible shoots for a given target.
+// The names are cached for shell completion.
 func ShootNamesForTarget(ctx context.Context, manager target.Manager) ([]string, error) {
 	t, err := manager.CurrentTarget()
 	if err != nil {
 		return nil, err
 	}
 
+	return CachedCompletion(manager, t, "shoots", func() ([]string, error) {
+		return shootNamesForTarget(ctx, manager, t)
+	})
+}
+
+func shootNamesForTarget(ctx context.Context, manager target.Manager, t target.Target) ([]string, error) {
 	gardenClient, err := manager.GardenClient(t.GardenName())
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Kubernetes client for garden cluster %q: %w", t.GardenName(), err)
@@ -59,13 +66,19 @@ func SeedForTarget(ctx context.Context, gardenClient gardenclient.Client, t targ
 }
 
 // SeedNamesForTarget returns all possible seeds for a given target. The
-// target must at least point to a garden.
+// target must at least point to a garden. The names are cached for shell completion.
 func SeedNamesForTarget(ctx context.Context, manager target.Manager) ([]string, error) {
 	t, err := manager.CurrentTarget()
 	if err != nil {
 		return nil, err
 	}
 
+	return CachedCompletion(manager, t, "seeds", func() ([]string, error) {
+		return seedNamesForTarget(ctx, manager, t)
+	})
+}
+
+func seedNamesForTarget(ctx context.Context, manager target.Manager, t target.Target) ([]string, error) {
 	gardenClient, err := manager.GardenClient(t.GardenName())
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Kubernetes client for garden cluster %q: %w", t.GardenName(), err)
@@ -95,13 +108,19 @@ func ProjectForTarget(ctx context.Context, gardenClient gardenclient.Client, t t
 }
 
 // ProjectNamesForTarget returns all projects for the targeted garden.
-// target must at least point to a garden.
+// target must at least point to a garden. The names are cached for shell completion.
 func ProjectNamesForTarget(ctx context.Context, manager target.Manager) ([]string, error) {
 	t, err := manager.CurrentTarget()
 	if err != nil {
 		return nil, err
 	}
 
+	return CachedCompletion(manager, t, "projects", func() ([]string, error) {
+		return projectNamesForTarget(ctx, manager, t)
+	})
+}
+
+func projectNamesForTarget(ctx context.Context, manager target.Manager, t target.Target) ([]string, error) {
 	gardenClient, err := manager.GardenClient(t.GardenName())
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Kubernetes client for garden cluster %q: %w", t.GardenName(), err)
@@ -120,7 +139,39 @@ func ProjectNamesForTarget(ctx context.Context, manager target.Manager) ([]strin
 	return names.List(), nil
 }
 
-// GardenNames returns all names of configured Gardens
+// CloudProfileNamesForTarget returns all cloud profiles of the targeted garden.
+// target must at least point to a garden. The names are cached for shell completion.
+func CloudProfileNamesForTarget(ctx context.Context, manager target.Manager) ([]string, error) {
+	t, err := manager.CurrentTarget()
+	if err != nil {
+		return nil, err
+	}
+
+	return CachedCompletion(manager, t, "cloudprofiles", func() ([]string, error) {
+		return cloudProfileNamesForTarget(ctx, manager, t)
+	})
+}
+
+func cloudProfileNamesForTarget(ctx context.Context, manager target.Manager, t target.Target) ([]string, error) {
+	gardenClient, err := manager.GardenClient(t.GardenName())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kubernetes client for garden cluster %q: %w", t.GardenName(), err)
+	}
+
+	cloudProfileList, err := gardenClient.ListCloudProfiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	names := sets.NewString()
+	for _, cloudProfile := range cloudProfileList.Items {
+		names.Insert(cloudProfile.Name)
+	}
+
+	return names.List(), nil
+}
+
+// GardenNames returns all names and aliases of configured Gardens
 func GardenNames(manager target.Manager) ([]string, error) {
 	config := manager.Configuration()
 	if config == nil {
@@ -130,6 +181,7 @@ func GardenNames(manager target.Manager) ([]string, error) {
 	names := sets.NewString()
 	for _, garden := range config.Gardens {
 		names.Insert(garden.Name)
+		names.Insert(garden.Aliases...)
 	}
 
 	return names.List(), nil
@@ -0,0 +1,68 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package auth
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/config"
+)
+
+// NewCmdAuth returns a new auth command.
+func NewCmdAuth(f util.Factory, ioStreams util.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "auth",
+		Short: "Inspect and purge the cached credentials of the gardens",
+		Long: `Inspect and purge the cached credentials of the gardens. The tokens of gardenctl login are cached in the tokens
+directory and the output of kubeconfigExec commands is cached in the cache directory of the gardenctl home directory.
+Purging expired or revoked credentials forces gardenctl to fetch new ones.`,
+	}
+
+	cmd.AddCommand(NewCmdStatus(f, NewStatusOptions(ioStreams)))
+	cmd.AddCommand(NewCmdPurge(f, NewPurgeOptions(ioStreams)))
+
+	return cmd
+}
+
+// validGardenArgsFunction completes the garden identities that have not been given yet
+func validGardenArgsFunction(f util.Factory, ioStreams util.IOStreams) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		manager, err := f.Manager()
+		if err != nil {
+			fmt.Fprintln(ioStreams.ErrOut, err.Error())
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		names := sets.NewString(manager.Configuration().GardenNames()...).Delete(args...).List()
+
+		return util.FilterStringsByPrefix(toComplete, names), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// cachedCredentials returns the cached credentials of the given gardens or of all gardens if no garden is given
+func cachedCredentials(cfg *config.Config, gardenNames []string) ([]config.CachedCredential, error) {
+	if len(gardenNames) == 0 {
+		gardenNames = cfg.GardenNames()
+	}
+
+	credentials := []config.CachedCredential{}
+
+	for _, name := range gardenNames {
+		c, err := cfg.CachedCredentials(name)
+		if err != nil {
+			return nil, err
+		}
+
+		credentials = append(credentials, c...)
+	}
+
+	return credentials, nil
+}
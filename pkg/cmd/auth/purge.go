@@ -0,0 +1,120 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package auth
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
+)
+
+// NewCmdPurge returns a new auth purge command.
+func NewCmdPurge(f util.Factory, o *PurgeOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "purge [GARDEN...]",
+		Short: "Delete the cached credentials of gardens",
+		Long: `Delete the cached credentials of the given gardens or, with --all, of all gardens.
+This helps to recover from expired or revoked credentials: the output of kubeconfigExec commands is fetched again
+and gardens that use an OIDC provider require a new gardenctl login.`,
+		Example: `# delete the cached credentials of the garden my-garden
+gardenctl auth purge my-garden
+
+# delete the cached credentials of all gardens
+gardenctl auth purge --all`,
+		ValidArgsFunction: validGardenArgsFunction(f, o.IOStreams),
+		RunE:              base.WrapRunE(o, f),
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// PurgeOptions is a struct to support auth purge command
+type PurgeOptions struct {
+	base.Options
+
+	// Gardens are the identities or aliases of the gardens whose credentials are deleted
+	Gardens []string
+	// All deletes the cached credentials of all gardens
+	All bool
+}
+
+// NewPurgeOptions returns initialized PurgeOptions
+func NewPurgeOptions(ioStreams util.IOStreams) *PurgeOptions {
+	return &PurgeOptions{
+		Options: base.Options{
+			IOStreams: ioStreams,
+		},
+	}
+}
+
+// AddFlags binds the command options to a given flagset
+func (o *PurgeOptions) AddFlags(flags *pflag.FlagSet) {
+	flags.BoolVar(&o.All, "all", o.All, "Delete the cached credentials of all gardens.")
+}
+
+// Complete adapts from the command line args to the data required.
+func (o *PurgeOptions) Complete(f util.Factory, cmd *cobra.Command, args []string) error {
+	o.Gardens = args
+
+	return nil
+}
+
+// Validate validates the provided options
+func (o *PurgeOptions) Validate() error {
+	if o.All && len(o.Gardens) > 0 {
+		return errors.New("gardens must not be given together with --all")
+	}
+
+	if !o.All && len(o.Gardens) == 0 {
+		return errors.New("specify the gardens whose credentials should be deleted or --all")
+	}
+
+	return nil
+}
+
+// Run executes the command
+func (o *PurgeOptions) Run(f util.Factory) error {
+	manager, err := f.Manager()
+	if err != nil {
+		return err
+	}
+
+	cfg := manager.Configuration()
+
+	gardenNames := o.Gardens
+	if o.All {
+		gardenNames = cfg.GardenNames()
+	}
+
+	removed := 0
+
+	for _, name := range gardenNames {
+		credentials, err := cfg.PurgeCachedCredentials(name)
+		if err != nil {
+			return err
+		}
+
+		for _, c := range credentials {
+			fmt.Fprintf(o.IOStreams.Out, "Deleted %s of garden %s\n", c.Kind, c.Garden)
+		}
+
+		removed += len(credentials)
+	}
+
+	if removed == 0 {
+		fmt.Fprintln(o.IOStreams.Out, "No cached credentials found")
+	}
+
+	return nil
+}
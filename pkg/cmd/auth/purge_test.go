@@ -0,0 +1,82 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package auth_test
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	internalfake "github.com/gardener/gardenctl-v2/internal/fake"
+	"github.com/gardener/gardenctl-v2/internal/oidc"
+	"github.com/gardener/gardenctl-v2/internal/util"
+	cmdauth "github.com/gardener/gardenctl-v2/pkg/cmd/auth"
+	"github.com/gardener/gardenctl-v2/pkg/config"
+	"github.com/gardener/gardenctl-v2/pkg/target"
+)
+
+var _ = Describe("Auth Purge Command", func() {
+	var (
+		homeDir string
+		cfg     *config.Config
+		streams util.IOStreams
+		out     *util.SafeBytesBuffer
+		factory *internalfake.Factory
+	)
+
+	BeforeEach(func() {
+		var err error
+		homeDir, err = os.MkdirTemp("", "gctlv2-auth-")
+		Expect(err).NotTo(HaveOccurred())
+
+		cfg = &config.Config{
+			CacheDir: filepath.Join(homeDir, "cache"),
+			TokenDir: filepath.Join(homeDir, "tokens"),
+			Gardens: []config.Garden{
+				{Name: "garden1", ClusterIdentity: "landscape1", OIDC: &config.OIDC{IssuerURL: "https://issuer.example.com", ClientID: "gardenctl"}},
+				{Name: "garden2", ClusterIdentity: "landscape2", OIDC: &config.OIDC{IssuerURL: "https://issuer.example.com", ClientID: "gardenctl"}},
+			},
+		}
+
+		for _, g := range cfg.Gardens {
+			Expect(cfg.TokenCache().Save(g.ClusterIdentity, &oidc.Token{IDToken: "id", Expiry: time.Now().Add(time.Hour)})).To(Succeed())
+		}
+
+		streams, _, out, _ = util.NewTestIOStreams()
+		factory = internalfake.NewFakeFactory(cfg, nil, nil, internalfake.NewFakeTargetProvider(target.NewTarget("", "", "", "")))
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(homeDir)).To(Succeed())
+	})
+
+	It("should delete the cached credentials of the given garden", func() {
+		cmd := cmdauth.NewCmdPurge(factory, cmdauth.NewPurgeOptions(streams))
+		Expect(cmd.RunE(cmd, []string{"garden1"})).To(Succeed())
+		Expect(out.String()).To(Equal("Deleted oidc-token of garden garden1\n"))
+		Expect(cfg.TokenCache().Filename("landscape1")).NotTo(BeAnExistingFile())
+		Expect(cfg.TokenCache().Filename("landscape2")).To(BeARegularFile())
+	})
+
+	It("should delete the cached credentials of all gardens", func() {
+		cmd := cmdauth.NewCmdPurge(factory, cmdauth.NewPurgeOptions(streams))
+		Expect(cmd.Flags().Set("all", "true")).To(Succeed())
+		Expect(cmd.RunE(cmd, nil)).To(Succeed())
+		Expect(out.String()).To(Equal("Deleted oidc-token of garden garden1\nDeleted oidc-token of garden garden2\n"))
+
+		Expect(cmd.RunE(cmd, nil)).To(Succeed())
+		Expect(out.String()).To(HaveSuffix("No cached credentials found\n"))
+	})
+
+	It("should require gardens or --all", func() {
+		cmd := cmdauth.NewCmdPurge(factory, cmdauth.NewPurgeOptions(streams))
+		Expect(cmd.RunE(cmd, nil)).To(MatchError("specify the gardens whose credentials should be deleted or --all"))
+	})
+})
@@ -0,0 +1,141 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package auth
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1beta1 "k8s.io/apimachinery/pkg/apis/meta/v1beta1"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/cli-runtime/pkg/printers"
+
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
+	"github.com/gardener/gardenctl-v2/pkg/config"
+)
+
+// NewCmdStatus returns a new auth status command.
+func NewCmdStatus(f util.Factory, o *StatusOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status [GARDEN...]",
+		Short: "Show the cached credentials of the gardens",
+		Long: `Show the cached credentials of the given gardens or of all gardens, when they expire and whether they are
+renewed automatically once they expired. Expired credentials that cannot be renewed require a new gardenctl login.`,
+		Example: `# show the cached credentials of all gardens
+gardenctl auth status
+
+# show the cached credentials of the garden my-garden as yaml
+gardenctl auth status my-garden -o yaml`,
+		ValidArgsFunction: validGardenArgsFunction(f, o.IOStreams),
+		RunE:              base.WrapRunE(o, f),
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// StatusOptions is a struct to support auth status command
+type StatusOptions struct {
+	base.Options
+
+	// Gardens are the identities or aliases of the gardens whose credentials are shown
+	Gardens []string
+}
+
+// NewStatusOptions returns initialized StatusOptions
+func NewStatusOptions(ioStreams util.IOStreams) *StatusOptions {
+	return &StatusOptions{
+		Options: base.Options{
+			IOStreams: ioStreams,
+		},
+	}
+}
+
+// Complete adapts from the command line args to the data required.
+func (o *StatusOptions) Complete(f util.Factory, cmd *cobra.Command, args []string) error {
+	o.Gardens = args
+
+	return nil
+}
+
+// Run executes the command
+func (o *StatusOptions) Run(f util.Factory) error {
+	manager, err := f.Manager()
+	if err != nil {
+		return err
+	}
+
+	credentials, err := cachedCredentials(manager.Configuration(), o.Gardens)
+	if err != nil {
+		return err
+	}
+
+	if o.Output != "" {
+		return o.PrintObject(credentials)
+	}
+
+	if len(credentials) == 0 {
+		fmt.Fprintln(o.IOStreams.Out, "No cached credentials found")
+		return nil
+	}
+
+	now := f.Clock().Now()
+
+	table := &metav1beta1.Table{
+		ColumnDefinitions: []metav1.TableColumnDefinition{
+			{Name: "Garden", Type: "string", Format: "name"},
+			{Name: "Kind", Type: "string"},
+			{Name: "Status", Type: "string"},
+			{Name: "Expires", Type: "string"},
+			{Name: "File", Type: "string"},
+		},
+		Rows: []metav1.TableRow{},
+	}
+
+	for _, c := range credentials {
+		table.Rows = append(table.Rows, metav1.TableRow{
+			Cells: []interface{}{
+				c.Garden,
+				c.Kind,
+				credentialStatus(c, now),
+				expires(c.Expiry, now),
+				c.Filename,
+			},
+		})
+	}
+
+	printer := printers.NewTablePrinter(printers.PrintOptions{})
+	if err := printer.PrintObj(table, o.IOStreams.Out); err != nil {
+		return fmt.Errorf("failed to output credentials table: %w", err)
+	}
+
+	return nil
+}
+
+func credentialStatus(c config.CachedCredential, now time.Time) string {
+	switch {
+	case !c.Expired(now):
+		return "Valid"
+	case c.Refreshable:
+		return "Expired, refreshable"
+	default:
+		return "Expired, login required"
+	}
+}
+
+// expires returns a human readable duration until the expiry, e.g. in 45m or 2h ago
+func expires(expiry, now time.Time) string {
+	if now.Before(expiry) {
+		return "in " + duration.HumanDuration(expiry.Sub(now))
+	}
+
+	return duration.HumanDuration(now.Sub(expiry)) + " ago"
+}
@@ -0,0 +1,97 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package auth_test
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	internalfake "github.com/gardener/gardenctl-v2/internal/fake"
+	"github.com/gardener/gardenctl-v2/internal/oidc"
+	"github.com/gardener/gardenctl-v2/internal/util"
+	cmdauth "github.com/gardener/gardenctl-v2/pkg/cmd/auth"
+	"github.com/gardener/gardenctl-v2/pkg/config"
+	"github.com/gardener/gardenctl-v2/pkg/target"
+)
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time {
+	return time.Time(c)
+}
+
+var _ = Describe("Auth Status Command", func() {
+	var (
+		homeDir string
+		now     time.Time
+		cfg     *config.Config
+		streams util.IOStreams
+		out     *util.SafeBytesBuffer
+		factory *internalfake.Factory
+	)
+
+	BeforeEach(func() {
+		var err error
+		homeDir, err = os.MkdirTemp("", "gctlv2-auth-")
+		Expect(err).NotTo(HaveOccurred())
+
+		now = time.Now()
+		cfg = &config.Config{
+			CacheDir: filepath.Join(homeDir, "cache"),
+			TokenDir: filepath.Join(homeDir, "tokens"),
+			Gardens: []config.Garden{
+				{Name: "oidc", ClusterIdentity: "landscape-oidc", OIDC: &config.OIDC{IssuerURL: "https://issuer.example.com", ClientID: "gardenctl"}},
+				{Name: "expired", ClusterIdentity: "landscape-expired", OIDC: &config.OIDC{IssuerURL: "https://issuer.example.com", ClientID: "gardenctl"}},
+				{Name: "plain", Kubeconfig: "/kubeconfig.yaml"},
+			},
+		}
+		Expect(cfg.TokenCache().Save("landscape-oidc", &oidc.Token{IDToken: "id", RefreshToken: "refresh", Expiry: now.Add(45 * time.Minute)})).To(Succeed())
+		Expect(cfg.TokenCache().Save("landscape-expired", &oidc.Token{IDToken: "id", Expiry: now.Add(-5 * time.Hour)})).To(Succeed())
+
+		streams, _, out, _ = util.NewTestIOStreams()
+		factory = internalfake.NewFakeFactory(cfg, fixedClock(now), nil, internalfake.NewFakeTargetProvider(target.NewTarget("", "", "", "")))
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(homeDir)).To(Succeed())
+	})
+
+	It("should show the cached credentials of all gardens", func() {
+		cmd := cmdauth.NewCmdStatus(factory, cmdauth.NewStatusOptions(streams))
+		Expect(cmd.RunE(cmd, nil)).To(Succeed())
+
+		lines := out.String()
+		Expect(lines).To(MatchRegexp(`(?m)^GARDEN\s+KIND\s+STATUS\s+EXPIRES\s+FILE$`))
+		Expect(lines).To(MatchRegexp(`(?m)^oidc\s+oidc-token\s+Valid\s+in 45m\s+` + cfg.TokenCache().Filename("landscape-oidc") + `$`))
+		Expect(lines).To(MatchRegexp(`(?m)^expired\s+oidc-token\s+Expired, login required\s+5h ago\s+`))
+		Expect(lines).NotTo(ContainSubstring("plain"))
+	})
+
+	It("should show the cached credentials of the given garden as json", func() {
+		cmd := cmdauth.NewCmdStatus(factory, cmdauth.NewStatusOptions(streams))
+		Expect(cmd.Flags().Set("output", "json")).To(Succeed())
+		Expect(cmd.RunE(cmd, []string{"oidc"})).To(Succeed())
+		Expect(out.String()).To(ContainSubstring(`"garden": "oidc"`))
+		Expect(out.String()).To(ContainSubstring(`"refreshable": true`))
+		Expect(out.String()).NotTo(ContainSubstring(`"garden": "expired"`))
+	})
+
+	It("should report if there are no cached credentials", func() {
+		cmd := cmdauth.NewCmdStatus(factory, cmdauth.NewStatusOptions(streams))
+		Expect(cmd.RunE(cmd, []string{"plain"})).To(Succeed())
+		Expect(out.String()).To(Equal("No cached credentials found\n"))
+	})
+
+	It("should fail for an unknown garden", func() {
+		cmd := cmdauth.NewCmdStatus(factory, cmdauth.NewStatusOptions(streams))
+		Expect(cmd.RunE(cmd, []string{"unknown"})).To(MatchError(`garden "unknown" is not defined in gardenctl configuration`))
+	})
+})
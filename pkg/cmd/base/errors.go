@@ -0,0 +1,205 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package base
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/gardener/gardenctl-v2/pkg/config"
+	"github.com/gardener/gardenctl-v2/pkg/target"
+)
+
+// Exit codes of gardenctl, they are part of the command line interface and must not be changed
+const (
+	// ExitCodeError is the exit code of all errors that do not belong to a more specific category
+	ExitCodeError = 1
+	// ExitCodeUsage is the exit code of invalid commands, arguments and flags
+	ExitCodeUsage = 2
+	// ExitCodeNotFound is the exit code if a garden, project, seed, shoot or other resource does not exist
+	ExitCodeNotFound = 3
+	// ExitCodeAuth is the exit code if a cluster rejected the credentials or denied the access, or if a change
+	// to a protected garden has been denied
+	ExitCodeAuth = 4
+	// ExitCodeTimeout is the exit code if an operation or a request timed out
+	ExitCodeTimeout = 5
+	// ExitCodeUnreachable is the exit code if a cluster cannot be reached or gardenctl runs in offline mode
+	ExitCodeUnreachable = 6
+	// ExitCodeCommandFailed is the exit code if a command executed by gardenctl, e.g. kubectl, failed. The exit code
+	// of the command is only part of the structured error, so that it does not collide with the other exit codes.
+	ExitCodeCommandFailed = 7
+)
+
+// Reasons of the structured errors, they categorize the errors like the exit codes
+const (
+	ReasonError         = "Error"
+	ReasonUsage         = "Usage"
+	ReasonNotFound      = "NotFound"
+	ReasonUnauthorized  = "Unauthorized"
+	ReasonTimeout       = "Timeout"
+	ReasonUnreachable   = "Unreachable"
+	ReasonCommandFailed = "CommandFailed"
+)
+
+// Error formats of the --error-format flag
+const (
+	// ErrorFormatText prints errors as human-readable text
+	ErrorFormatText = "text"
+	// ErrorFormatJSON prints errors as JSON objects with the exit code, the reason and the message
+	ErrorFormatJSON = "json"
+)
+
+// UsageError is returned if a command has been invoked with invalid arguments or flags
+type UsageError struct {
+	Err error
+}
+
+var _ error = &UsageError{}
+
+// NewUsageError wraps the given error into a UsageError
+func NewUsageError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &UsageError{Err: err}
+}
+
+func (e *UsageError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *UsageError) Unwrap() error {
+	return e.Err
+}
+
+// TimeoutError is returned if gardenctl timed out waiting for an operation, e.g. for a bastion to become ready
+type TimeoutError struct {
+	Err error
+}
+
+var _ error = &TimeoutError{}
+
+// NewTimeoutError wraps the given error into a TimeoutError
+func NewTimeoutError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &TimeoutError{Err: err}
+}
+
+func (e *TimeoutError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// IsTimeout returns true if the error has been caused by a timeout
+func IsTimeout(err error) bool {
+	var (
+		timeoutErr *TimeoutError
+		netErr     net.Error
+	)
+
+	return errors.As(err, &timeoutErr) ||
+		errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, os.ErrDeadlineExceeded) ||
+		errors.Is(err, wait.ErrWaitTimeout) ||
+		apierrors.IsTimeout(err) ||
+		apierrors.IsServerTimeout(err) ||
+		(errors.As(err, &netErr) && netErr.Timeout())
+}
+
+// ErrorObject is the structured representation of an error, which is printed with --error-format json
+type ErrorObject struct {
+	// Code is the exit code of gardenctl
+	Code int `json:"code"`
+	// Reason is the category of the error, e.g. NotFound
+	Reason string `json:"reason"`
+	// Message is the error message
+	Message string `json:"message"`
+	// CommandExitCode is the exit code of the failed command that has been executed by gardenctl, e.g. kubectl
+	CommandExitCode int `json:"commandExitCode,omitempty"`
+}
+
+// NewErrorObject returns the structured representation of the given error
+func NewErrorObject(err error) ErrorObject {
+	code, reason := classifyError(err)
+	object := ErrorObject{
+		Code:    code,
+		Reason:  reason,
+		Message: err.Error(),
+	}
+
+	if exitErr := commandExitError(err); exitErr != nil {
+		object.CommandExitCode = exitErr.ExitCode()
+	}
+
+	return object
+}
+
+// ExitCode returns the exit code of gardenctl for the given error
+func ExitCode(err error) int {
+	code, _ := classifyError(err)
+	return code
+}
+
+// PrintError prints the error in the given format, the text format is the same as the one of cobra
+func PrintError(w io.Writer, format string, err error) {
+	if format == ErrorFormatJSON {
+		if data, marshalErr := json.Marshal(NewErrorObject(err)); marshalErr == nil {
+			fmt.Fprintln(w, string(data))
+			return
+		}
+	}
+
+	fmt.Fprintln(w, "Error:", err.Error())
+}
+
+func classifyError(err error) (int, string) {
+	var usageErr *UsageError
+
+	switch {
+	case errors.As(err, &usageErr):
+		return ExitCodeUsage, ReasonUsage
+	case commandExitError(err) != nil:
+		return ExitCodeCommandFailed, ReasonCommandFailed
+	case apierrors.IsUnauthorized(err), apierrors.IsForbidden(err), config.IsMutationDenied(err):
+		return ExitCodeAuth, ReasonUnauthorized
+	case apierrors.IsNotFound(err), config.IsGardenNotFound(err):
+		return ExitCodeNotFound, ReasonNotFound
+	// an unreachable cluster is reported as such, even if the connection attempt timed out
+	case target.IsUnreachable(err):
+		return ExitCodeUnreachable, ReasonUnreachable
+	case IsTimeout(err):
+		return ExitCodeTimeout, ReasonTimeout
+	}
+
+	return ExitCodeError, ReasonError
+}
+
+// commandExitError returns the error of a command executed by gardenctl that exited with a non-zero exit code, or nil
+func commandExitError(err error) *exec.ExitError {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+		return exitErr
+	}
+
+	return nil
+}
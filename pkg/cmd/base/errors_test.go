@@ -0,0 +1,89 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package base_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
+	"github.com/gardener/gardenctl-v2/pkg/config"
+	"github.com/gardener/gardenctl-v2/pkg/target"
+)
+
+var _ = Describe("Errors", func() {
+	DescribeTable("classifying errors",
+		func(err error, code int, reason string) {
+			Expect(base.ExitCode(err)).To(Equal(code))
+			Expect(base.NewErrorObject(err)).To(Equal(base.ErrorObject{Code: code, Reason: reason, Message: err.Error()}))
+		},
+		Entry("generic error", errors.New("foo"), base.ExitCodeError, base.ReasonError),
+		Entry("usage error", base.NewUsageError(errors.New("foo")), base.ExitCodeUsage, base.ReasonUsage),
+		Entry("shoot not found", fmt.Errorf("failed: %w", apierrors.NewNotFound(gardencorev1beta1.Resource("shoots"), "foo")), base.ExitCodeNotFound, base.ReasonNotFound),
+		Entry("garden not found", fmt.Errorf("failed: %w", &config.GardenNotFoundError{Name: "foo"}), base.ExitCodeNotFound, base.ReasonNotFound),
+		Entry("unauthorized", apierrors.NewUnauthorized("foo"), base.ExitCodeAuth, base.ReasonUnauthorized),
+		Entry("forbidden", apierrors.NewForbidden(gardencorev1beta1.Resource("shoots"), "foo", errors.New("denied")), base.ExitCodeAuth, base.ReasonUnauthorized),
+		Entry("mutation denied", fmt.Errorf("failed: %w", &config.MutationDeniedError{Garden: "prod", Action: "patch Shoot garden-foo/bar", Reason: "denied"}), base.ExitCodeAuth, base.ReasonUnauthorized),
+		Entry("deadline exceeded", fmt.Errorf("failed: %w", context.DeadlineExceeded), base.ExitCodeTimeout, base.ReasonTimeout),
+		Entry("timeout error", base.NewTimeoutError(errors.New("timed out waiting")), base.ExitCodeTimeout, base.ReasonTimeout),
+		Entry("server timeout", apierrors.NewTimeoutError("foo", 1), base.ExitCodeTimeout, base.ReasonTimeout),
+		Entry("unreachable", &target.UnreachableError{Host: "foo", Err: context.DeadlineExceeded}, base.ExitCodeUnreachable, base.ReasonUnreachable),
+		Entry("offline", target.ErrOffline, base.ExitCodeUnreachable, base.ReasonUnreachable),
+	)
+
+	Describe("failed commands", func() {
+		var err error
+
+		BeforeEach(func() {
+			err = fmt.Errorf("kubectl failed: %w", exec.Command("sh", "-c", "exit 3").Run())
+		})
+
+		It("should not pass the exit code of the command through", func() {
+			Expect(base.ExitCode(err)).To(Equal(base.ExitCodeCommandFailed))
+		})
+
+		It("should add the exit code of the command to the structured error", func() {
+			Expect(base.NewErrorObject(err)).To(Equal(base.ErrorObject{
+				Code:            base.ExitCodeCommandFailed,
+				Reason:          base.ReasonCommandFailed,
+				Message:         "kubectl failed: exit status 3",
+				CommandExitCode: 3,
+			}))
+		})
+	})
+
+	Describe("printing errors", func() {
+		var (
+			out *util.SafeBytesBuffer
+			err error
+		)
+
+		BeforeEach(func() {
+			out = &util.SafeBytesBuffer{}
+			err = base.NewUsageError(errors.New("invalid \"foo\""))
+		})
+
+		It("should print the error as text", func() {
+			base.PrintError(out, base.ErrorFormatText, err)
+			Expect(out.String()).To(Equal("Error: invalid \"foo\"\n"))
+		})
+
+		It("should print the error as json", func() {
+			base.PrintError(out, base.ErrorFormatJSON, err)
+			Expect(out.String()).To(Equal(`{"code":2,"reason":"Usage","message":"invalid \"foo\""}` + "\n"))
+		})
+	})
+})
@@ -10,6 +10,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -37,13 +38,14 @@ type Options struct {
 	// IOStreams provides the standard names for iostreams
 	IOStreams util.IOStreams
 
-	// Output defines the output format of the version information. Either 'yaml' or 'json'
+	// Output defines the output format, see ValidateOutput for the supported formats
 	Output string
 }
 
 var _ CommandOptions = &Options{}
 
-// WrapRunE creates a cobra RunE function that has access to the factory
+// WrapRunE creates a cobra RunE function that has access to the factory.
+// Validation errors are returned as UsageError.
 func WrapRunE(o CommandOptions, f util.Factory) func(cmd *cobra.Command, args []string) error {
 	return func(cmd *cobra.Command, args []string) error {
 		if err := o.Complete(f, cmd, args); err != nil {
@@ -51,7 +53,7 @@ func WrapRunE(o CommandOptions, f util.Factory) func(cmd *cobra.Command, args []
 		}
 
 		if err := o.Validate(); err != nil {
-			return err
+			return NewUsageError(err)
 		}
 
 		return o.Run(f)
@@ -67,16 +69,17 @@ func NewOptions(ioStreams util.IOStreams) *Options {
 
 // AddFlags adds flags to adjust the output to a cobra command
 func (o *Options) AddFlags(flags *pflag.FlagSet) {
-	flags.StringVarP(&o.Output, "output", "o", o.Output, "One of 'yaml' or 'json'.")
+	flags.StringVarP(&o.Output, "output", "o", o.Output, OutputFormatsUsage())
 }
 
-// PrintObject prints an object to IOStreams.out, using o.Output to print in the selected output format
+// PrintObject prints an object to IOStreams.out, using o.Output to print in the selected output format.
+// Without an output format, the object is printed with its default format.
 func (o *Options) PrintObject(obj interface{}) error {
 	switch o.Output {
 	case "":
 		fmt.Fprintf(o.IOStreams.Out, "%v", obj)
 
-	case "yaml":
+	case OutputYAML:
 		yamlEncoder := yaml.NewEncoder(o.IOStreams.Out)
 		defer yamlEncoder.Close()
 
@@ -86,7 +89,7 @@ func (o *Options) PrintObject(obj interface{}) error {
 		if err != nil {
 			return err
 		}
-	case "json":
+	case OutputJSON:
 		marshalled, err := json.MarshalIndent(&obj, "", "  ")
 		if err != nil {
 			return err
@@ -94,7 +97,18 @@ func (o *Options) PrintObject(obj interface{}) error {
 
 		fmt.Fprintln(o.IOStreams.Out, string(marshalled))
 
+	case OutputName:
+		return printNames(o.IOStreams.Out, obj)
+
 	default:
+		if strings.HasPrefix(o.Output, OutputJSONPathPrefix) {
+			return printJSONPath(o.IOStreams.Out, strings.TrimPrefix(o.Output, OutputJSONPathPrefix), obj)
+		}
+
+		if strings.HasPrefix(o.Output, OutputGoTemplatePrefix) {
+			return printGoTemplate(o.IOStreams.Out, strings.TrimPrefix(o.Output, OutputGoTemplatePrefix), obj)
+		}
+
 		// There is a bug in the program if we hit this case.
 		// However, we follow a policy of never panicking.
 		return fmt.Errorf("options were not validated: --output=%q should have been rejected", o.Output)
@@ -103,10 +117,15 @@ func (o *Options) PrintObject(obj interface{}) error {
 	return nil
 }
 
-// Validate validates the provided options.
+// Validate validates the provided options. The table output format is normalized to an
+// empty output format, so that commands only have to check for the human-readable output once.
 func (o *Options) Validate() error {
-	if o.Output != "" && o.Output != "yaml" && o.Output != "json" {
-		return errors.New("--output must be either 'yaml' or 'json'")
+	if err := ValidateOutput(o.Output); err != nil {
+		return err
+	}
+
+	if o.Output == OutputTable {
+		o.Output = ""
 	}
 
 	return nil
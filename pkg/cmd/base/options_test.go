@@ -107,13 +107,78 @@ bar:
 			})
 		})
 
+		Context("when the output is table", func() {
+			BeforeEach(func() {
+				options.Output = "table"
+			})
+
+			It("validate should normalize the output", func() {
+				Expect(options.Validate()).To(Succeed())
+				Expect(options.Output).To(BeEmpty())
+			})
+		})
+
+		Context("when the output is name", func() {
+			type item struct {
+				Name string `json:"name"`
+			}
+
+			BeforeEach(func() {
+				options.Output = "name"
+			})
+
+			It("should print the names of the items", func() {
+				Expect(options.Validate()).To(Succeed())
+				Expect(options.PrintObject([]item{{Name: "a"}, {Name: "b"}})).To(Succeed())
+				Expect(buf.String()).To(Equal("a\nb\n"))
+			})
+
+			It("should fail for objects without a name", func() {
+				Expect(options.PrintObject(foo)).To(MatchError("--output name is not supported, the printed objects have no name"))
+			})
+		})
+
+		Context("when the output is jsonpath", func() {
+			It("should print the result of the expression", func() {
+				options.Output = "jsonpath={.Bar.Baz}"
+				Expect(options.Validate()).To(Succeed())
+				Expect(options.PrintObject(foo)).To(Succeed())
+				Expect(buf.String()).To(Equal("baz\n"))
+			})
+
+			It("should accept expressions without braces", func() {
+				options.Output = "jsonpath=.Foo"
+				Expect(options.PrintObject(foo)).To(Succeed())
+				Expect(buf.String()).To(Equal("foo\n"))
+			})
+
+			It("validate should fail for an invalid expression", func() {
+				options.Output = "jsonpath={.Foo"
+				Expect(options.Validate()).To(MatchError(HavePrefix("invalid jsonpath output:")))
+			})
+		})
+
+		Context("when the output is go-template", func() {
+			It("should print the result of the template", func() {
+				options.Output = "go-template={{.Foo}}/{{.Bar.Baz}}"
+				Expect(options.Validate()).To(Succeed())
+				Expect(options.PrintObject(foo)).To(Succeed())
+				Expect(buf.String()).To(Equal("foo/baz"))
+			})
+
+			It("validate should fail for an invalid template", func() {
+				options.Output = "go-template={{.Foo"
+				Expect(options.Validate()).To(MatchError(HavePrefix("invalid go-template output:")))
+			})
+		})
+
 		Context("when the output is invalid", func() {
 			BeforeEach(func() {
 				options.Output = "invalid"
 			})
 
 			It("validate should fail", func() {
-				Expect(options.Validate()).To(MatchError(ContainSubstring("--output must be either 'yaml' or 'json")))
+				Expect(options.Validate()).To(MatchError("--output must be one of 'table', 'yaml', 'json', 'name', 'jsonpath=<expr>' or 'go-template=<template>'"))
 			})
 		})
 	})
@@ -175,9 +240,12 @@ bar:
 				mockOptions.EXPECT().Complete(mockFactory, cmd, args)
 			})
 
-			It("should fail to run the wrapped options with a validation error", func() {
+			It("should fail to run the wrapped options with a usage error", func() {
 				mockOptions.EXPECT().Validate().Return(err)
-				Expect(runE(cmd, args)).To(BeIdenticalTo(err))
+
+				runErr := runE(cmd, args)
+				Expect(runErr).To(MatchError(err))
+				Expect(base.ExitCode(runErr)).To(Equal(base.ExitCodeUsage))
 			})
 		})
 	})
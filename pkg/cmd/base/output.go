@@ -0,0 +1,218 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package base
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// Output formats that are supported by all commands that print objects
+const (
+	// OutputTable is the human-readable output of a command, it is the same as an empty output format
+	OutputTable = "table"
+	// OutputYAML prints the object as YAML
+	OutputYAML = "yaml"
+	// OutputJSON prints the object as JSON
+	OutputJSON = "json"
+	// OutputName prints only the names of the printed objects, one per line
+	OutputName = "name"
+	// OutputJSONPathPrefix prints the result of the JSONPath expression following the prefix, e.g. jsonpath={.items[*].name}
+	OutputJSONPathPrefix = "jsonpath="
+	// OutputGoTemplatePrefix prints the result of the Go template following the prefix, e.g. go-template={{.name}}
+	OutputGoTemplatePrefix = "go-template="
+)
+
+// OutputFormatsUsage returns the usage of an output flag that supports all output formats and the given additional formats
+func OutputFormatsUsage(additionalFormats ...string) string {
+	return fmt.Sprintf("One of %s.", outputFormats(additionalFormats))
+}
+
+// ValidateOutput validates the output format. Besides the additional formats that are specific to a
+// command, all formats of PrintObject are supported. The expressions of the jsonpath and go-template
+// formats must be parsable.
+func ValidateOutput(output string, additionalFormats ...string) error {
+	for _, format := range additionalFormats {
+		if output == format {
+			return nil
+		}
+	}
+
+	switch {
+	case output == "", output == OutputTable, output == OutputYAML, output == OutputJSON, output == OutputName:
+		return nil
+	case strings.HasPrefix(output, OutputJSONPathPrefix):
+		if _, err := parseJSONPath(strings.TrimPrefix(output, OutputJSONPathPrefix)); err != nil {
+			return fmt.Errorf("invalid jsonpath output: %w", err)
+		}
+
+		return nil
+	case strings.HasPrefix(output, OutputGoTemplatePrefix):
+		if _, err := parseGoTemplate(strings.TrimPrefix(output, OutputGoTemplatePrefix)); err != nil {
+			return fmt.Errorf("invalid go-template output: %w", err)
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("--output must be one of %s", outputFormats(additionalFormats))
+}
+
+// outputFormats returns a readable enumeration of all output formats, e.g. 'table', 'yaml' or 'json'
+func outputFormats(additionalFormats []string) string {
+	formats := append([]string{OutputTable}, additionalFormats...)
+	formats = append(formats, OutputYAML, OutputJSON, OutputName, OutputJSONPathPrefix+"<expr>", OutputGoTemplatePrefix+"<template>")
+
+	quoted := make([]string, len(formats))
+	for i, format := range formats {
+		quoted[i] = "'" + format + "'"
+	}
+
+	return strings.Join(quoted[:len(quoted)-1], ", ") + " or " + quoted[len(quoted)-1]
+}
+
+func parseJSONPath(expr string) (*jsonpath.JSONPath, error) {
+	if expr == "" {
+		return nil, errors.New("the expression must not be empty")
+	}
+
+	// like kubectl, accept expressions without the surrounding braces, e.g. .items[*].name
+	if !strings.Contains(expr, "{") {
+		expr = "{" + expr + "}"
+	}
+
+	jp := jsonpath.New("output").AllowMissingKeys(true)
+	if err := jp.Parse(expr); err != nil {
+		return nil, err
+	}
+
+	return jp, nil
+}
+
+func parseGoTemplate(text string) (*template.Template, error) {
+	if text == "" {
+		return nil, errors.New("the template must not be empty")
+	}
+
+	return template.New("output").Parse(text)
+}
+
+// genericObject converts obj into the generic representation of its JSON encoding, so that
+// jsonpath expressions and templates refer to the same field names as the json output
+func genericObject(obj interface{}) (interface{}, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, err
+	}
+
+	return generic, nil
+}
+
+func printJSONPath(out io.Writer, expr string, obj interface{}) error {
+	jp, err := parseJSONPath(expr)
+	if err != nil {
+		return fmt.Errorf("invalid jsonpath output: %w", err)
+	}
+
+	generic, err := genericObject(obj)
+	if err != nil {
+		return err
+	}
+
+	if err := jp.Execute(out, generic); err != nil {
+		return fmt.Errorf("failed to execute jsonpath %q: %w", expr, err)
+	}
+
+	fmt.Fprintln(out)
+
+	return nil
+}
+
+func printGoTemplate(out io.Writer, text string, obj interface{}) error {
+	tmpl, err := parseGoTemplate(text)
+	if err != nil {
+		return fmt.Errorf("invalid go-template output: %w", err)
+	}
+
+	generic, err := genericObject(obj)
+	if err != nil {
+		return err
+	}
+
+	if err := tmpl.Execute(out, generic); err != nil {
+		return fmt.Errorf("failed to execute go-template: %w", err)
+	}
+
+	return nil
+}
+
+// printNames prints the name of the object or the names of the items of a list, one per line.
+// The name is either the name field of an item or the name in its metadata.
+func printNames(out io.Writer, obj interface{}) error {
+	generic, err := genericObject(obj)
+	if err != nil {
+		return err
+	}
+
+	items := []interface{}{generic}
+
+	switch v := generic.(type) {
+	case []interface{}:
+		items = v
+	case map[string]interface{}:
+		if list, ok := v["items"].([]interface{}); ok {
+			items = list
+		}
+	}
+
+	names := make([]string, 0, len(items))
+
+	for _, item := range items {
+		name, ok := objectName(item)
+		if !ok {
+			return errors.New("--output name is not supported, the printed objects have no name")
+		}
+
+		names = append(names, name)
+	}
+
+	for _, name := range names {
+		fmt.Fprintln(out, name)
+	}
+
+	return nil
+}
+
+func objectName(item interface{}) (string, bool) {
+	fields, ok := item.(map[string]interface{})
+	if !ok {
+		return "", false
+	}
+
+	if name, ok := fields["name"].(string); ok {
+		return name, true
+	}
+
+	if metadata, ok := fields["metadata"].(map[string]interface{}); ok {
+		if name, ok := metadata["name"].(string); ok {
+			return name, true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,28 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cache
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/gardener/gardenctl-v2/internal/util"
+)
+
+// NewCmdCache returns a new cache command.
+func NewCmdCache(f util.Factory, ioStreams util.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the cache of gardenctl",
+		Long: `Manage the cache of gardenctl. The project, seed, shoot and node names that are queried for shell completion
+are cached per garden for a short time, see completion.cacheTTL in the gardenctl configuration. The cached names are
+discarded automatically when the target changes.`,
+	}
+
+	cmd.AddCommand(NewCmdClear(f, NewClearOptions(ioStreams)))
+
+	return cmd
+}
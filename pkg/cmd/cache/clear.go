@@ -0,0 +1,96 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cache
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
+)
+
+// NewCmdClear returns a new cache clear command.
+func NewCmdClear(f util.Factory, o *ClearOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "clear",
+		Short: "Clear the cached completion values",
+		Long: `Clear the cached project, seed, shoot and node names of all gardens, e.g. after a shoot has been created.
+With --all, the cached output of kubeconfigExec commands is removed as well, so that the kubeconfigs of the gardens are fetched again.`,
+		Example: `# clear the cached completion values
+gardenctl cache clear
+
+# clear the whole cache
+gardenctl cache clear --all`,
+		Args: cobra.NoArgs,
+		RunE: base.WrapRunE(o, f),
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// ClearOptions is a struct to support cache clear command
+type ClearOptions struct {
+	base.Options
+
+	// All removes all cached files instead of only the completion values
+	All bool
+}
+
+// NewClearOptions returns initialized ClearOptions
+func NewClearOptions(ioStreams util.IOStreams) *ClearOptions {
+	return &ClearOptions{
+		Options: base.Options{
+			IOStreams: ioStreams,
+		},
+	}
+}
+
+// AddFlags binds the command options to a given flagset
+func (o *ClearOptions) AddFlags(flags *pflag.FlagSet) {
+	flags.BoolVar(&o.All, "all", o.All, "Clear the whole cache, including the cached output of kubeconfigExec commands.")
+}
+
+// Run executes the command
+func (o *ClearOptions) Run(f util.Factory) error {
+	manager, err := f.Manager()
+	if err != nil {
+		return err
+	}
+
+	cacheDir := manager.Configuration().CacheDir
+	if cacheDir == "" {
+		fmt.Fprintln(o.IOStreams.Out, "Caching is disabled")
+		return nil
+	}
+
+	pattern := "completion-*.json"
+	if o.All {
+		pattern = "*"
+	}
+
+	files, err := filepath.Glob(filepath.Join(cacheDir, pattern))
+	if err != nil {
+		return fmt.Errorf("failed to list cached files: %w", err)
+	}
+
+	for _, file := range files {
+		if err := os.RemoveAll(file); err != nil {
+			return fmt.Errorf("failed to remove cached file: %w", err)
+		}
+	}
+
+	fmt.Fprintf(o.IOStreams.Out, "Removed %d cached files from %s\n", len(files), cacheDir)
+
+	return nil
+}
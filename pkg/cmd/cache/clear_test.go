@@ -0,0 +1,71 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cache_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	internalfake "github.com/gardener/gardenctl-v2/internal/fake"
+	"github.com/gardener/gardenctl-v2/internal/util"
+	cmdcache "github.com/gardener/gardenctl-v2/pkg/cmd/cache"
+	"github.com/gardener/gardenctl-v2/pkg/config"
+	"github.com/gardener/gardenctl-v2/pkg/target"
+)
+
+var _ = Describe("Cache Clear Command", func() {
+	var (
+		cacheDir       string
+		streams        util.IOStreams
+		out            *util.SafeBytesBuffer
+		factory        *internalfake.Factory
+		completionFile string
+		execFile       string
+	)
+
+	BeforeEach(func() {
+		var err error
+		cacheDir, err = os.MkdirTemp("", "gctlv2-cache-")
+		Expect(err).NotTo(HaveOccurred())
+
+		completionFile = util.CompletionCacheFile(cacheDir, "garden")
+		Expect(os.WriteFile(completionFile, []byte("{}"), 0600)).To(Succeed())
+		execFile = filepath.Join(cacheDir, "kubeconfig-exec-abc.yaml")
+		Expect(os.WriteFile(execFile, []byte("kind: Config"), 0600)).To(Succeed())
+
+		cfg := &config.Config{
+			CacheDir: cacheDir,
+			Gardens:  []config.Garden{{Name: "garden"}},
+		}
+		streams, _, out, _ = util.NewTestIOStreams()
+		factory = internalfake.NewFakeFactory(cfg, nil, nil, internalfake.NewFakeTargetProvider(target.NewTarget("", "", "", "")))
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(cacheDir)).To(Succeed())
+	})
+
+	It("should remove the cached completion values", func() {
+		cmd := cmdcache.NewCmdClear(factory, cmdcache.NewClearOptions(streams))
+		Expect(cmd.RunE(cmd, nil)).To(Succeed())
+		Expect(out.String()).To(Equal("Removed 1 cached files from " + cacheDir + "\n"))
+		Expect(completionFile).NotTo(BeAnExistingFile())
+		Expect(execFile).To(BeARegularFile())
+	})
+
+	It("should remove all cached files with --all", func() {
+		cmd := cmdcache.NewCmdClear(factory, cmdcache.NewClearOptions(streams))
+		Expect(cmd.Flags().Set("all", "true")).To(Succeed())
+		Expect(cmd.RunE(cmd, nil)).To(Succeed())
+		Expect(out.String()).To(Equal("Removed 2 cached files from " + cacheDir + "\n"))
+		Expect(completionFile).NotTo(BeAnExistingFile())
+		Expect(execFile).NotTo(BeAnExistingFile())
+	})
+})
@@ -0,0 +1,115 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cleanup
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
+)
+
+// NewCmdCleanup returns a new cleanup command.
+func NewCmdCleanup(f util.Factory, o *CleanupOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cleanup",
+		Short: "Remove unused kubeconfigs, session files and cached credentials",
+		Long: `Remove the files that gardenctl has written and that have not been modified within the retention period, e.g. kubeconfigs,
+client configurations, cloud provider CLI configurations, the directories of closed sessions, cached completion values and cached tokens.
+The target, the target history and the kubeconfig of the current target of active sessions are kept.
+
+The retention defaults to the value of cleanup.retention in the gardenctl configuration (168h if not set).
+Unless cleanup.automatic is set to false, gardenctl removes these files automatically at most once per hour.`,
+		Example: `# remove files that have not been modified within the configured retention
+gardenctl cleanup
+
+# remove files that have not been modified within the last day
+gardenctl cleanup --retention 24h`,
+		Args: cobra.NoArgs,
+		RunE: base.WrapRunE(o, f),
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// CleanupOptions is a struct to support the cleanup command
+type CleanupOptions struct {
+	base.Options
+
+	// Retention is the duration after which unmodified files are removed.
+	// It defaults to the retention of the gardenctl configuration.
+	Retention time.Duration
+
+	// Directories are the directories from which the files are removed
+	Directories util.CleanupDirectories
+}
+
+// NewCleanupOptions returns initialized CleanupOptions
+func NewCleanupOptions(ioStreams util.IOStreams) *CleanupOptions {
+	return &CleanupOptions{
+		Options: base.Options{
+			IOStreams: ioStreams,
+		},
+	}
+}
+
+// AddFlags binds the command options to a given flagset
+func (o *CleanupOptions) AddFlags(flags *pflag.FlagSet) {
+	flags.DurationVar(&o.Retention, "retention", o.Retention, "Remove files that have not been modified within this duration. Defaults to cleanup.retention of the gardenctl configuration.")
+}
+
+// Complete adapts from the command line args to the data required.
+func (o *CleanupOptions) Complete(f util.Factory, _ *cobra.Command, _ []string) error {
+	manager, err := f.Manager()
+	if err != nil {
+		return err
+	}
+
+	cfg := manager.Configuration()
+
+	if o.Retention == 0 {
+		o.Retention = cfg.CleanupRetention()
+	}
+
+	o.Directories = util.CleanupDirectories{
+		SessionsDirectory: filepath.Dir(manager.SessionDir()),
+		CacheDirectory:    cfg.CacheDir,
+		TokenDirectory:    cfg.TokenDir,
+	}
+
+	return nil
+}
+
+// Validate validates the provided options
+func (o *CleanupOptions) Validate() error {
+	if o.Retention <= 0 {
+		return errors.New("--retention must be a positive duration")
+	}
+
+	return nil
+}
+
+// Run executes the command
+func (o *CleanupOptions) Run(f util.Factory) error {
+	removed := util.Cleanup(o.Directories, f.Clock().Now().Add(-o.Retention))
+
+	for _, path := range removed {
+		fmt.Fprintf(o.IOStreams.Out, "Removed %s\n", path)
+	}
+
+	fmt.Fprintf(o.IOStreams.Out, "Removed %d files and directories\n", len(removed))
+
+	return nil
+}
@@ -0,0 +1,87 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cleanup_test
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	internalfake "github.com/gardener/gardenctl-v2/internal/fake"
+	"github.com/gardener/gardenctl-v2/internal/util"
+	cmdcleanup "github.com/gardener/gardenctl-v2/pkg/cmd/cleanup"
+	"github.com/gardener/gardenctl-v2/pkg/config"
+	"github.com/gardener/gardenctl-v2/pkg/target"
+)
+
+var _ = Describe("Cleanup Command", func() {
+	var (
+		homeDir string
+		streams util.IOStreams
+		out     *util.SafeBytesBuffer
+		factory *internalfake.Factory
+		options *cmdcleanup.CleanupOptions
+	)
+
+	writeFile := func(path string, age time.Duration) {
+		Expect(os.MkdirAll(filepath.Dir(path), 0700)).To(Succeed())
+		Expect(os.WriteFile(path, nil, 0600)).To(Succeed())
+
+		modTime := time.Now().Add(-age)
+		Expect(os.Chtimes(path, modTime, modTime)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		var err error
+		homeDir, err = os.MkdirTemp("", "gctlv2-cleanup-")
+		Expect(err).NotTo(HaveOccurred())
+
+		cfg := &config.Config{Gardens: []config.Garden{{Name: "garden"}}}
+		streams, _, out, _ = util.NewTestIOStreams()
+		factory = internalfake.NewFakeFactory(cfg, nil, nil, internalfake.NewFakeTargetProvider(target.NewTarget("", "", "", "")))
+
+		options = cmdcleanup.NewCleanupOptions(streams)
+		options.Retention = 24 * time.Hour
+		options.Directories = util.CleanupDirectories{
+			SessionsDirectory: filepath.Join(homeDir, "sessions"),
+			CacheDirectory:    filepath.Join(homeDir, "cache"),
+			TokenDirectory:    filepath.Join(homeDir, "tokens"),
+		}
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(homeDir)).To(Succeed())
+	})
+
+	It("should have Use and Flags", func() {
+		cmd := cmdcleanup.NewCmdCleanup(factory, options)
+		Expect(cmd.Use).To(Equal("cleanup"))
+		Expect(cmd.Flags().Lookup("retention")).NotTo(BeNil())
+	})
+
+	It("should fail for a non-positive retention", func() {
+		options.Retention = -time.Hour
+		Expect(options.Validate()).To(MatchError("--retention must be a positive duration"))
+	})
+
+	It("should remove the files that have not been modified within the retention", func() {
+		staleToken := filepath.Join(options.Directories.TokenDirectory, "garden.json")
+		writeFile(staleToken, 48*time.Hour)
+		writeFile(staleToken+".lock", 48*time.Hour)
+		activeCompletion := filepath.Join(options.Directories.CacheDirectory, "completion-garden.json")
+		writeFile(activeCompletion, time.Hour)
+
+		Expect(options.Run(factory)).To(Succeed())
+		Expect(out.String()).To(Equal("Removed " + staleToken + "\nRemoved 1 files and directories\n"))
+		Expect(staleToken).NotTo(BeAnExistingFile())
+		Expect(staleToken + ".lock").To(BeARegularFile())
+		Expect(activeCompletion).To(BeARegularFile())
+	})
+})
@@ -0,0 +1,160 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cloudprofile
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/version"
+
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/target"
+)
+
+// NewCmdCloudProfile returns a new cloudprofile command.
+func NewCmdCloudProfile(f util.Factory, ioStreams util.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "cloudprofile",
+		Aliases: []string{"cloudprofiles"},
+		Short:   "Explore the cloud profiles of the targeted garden",
+		Long: `Explore the machine types, volume types, regions, zones and versions that the cloud profiles of the targeted garden offer.
+If no cloud profile name is given, the cloud profile of the targeted shoot is used.`,
+	}
+
+	cmd.AddCommand(NewCmdDescribe(f, NewDescribeOptions(ioStreams)))
+	cmd.AddCommand(NewCmdListMachineTypes(f, NewListMachineTypesOptions(ioStreams)))
+	cmd.AddCommand(NewCmdListKubernetesVersions(f, NewListKubernetesVersionsOptions(ioStreams)))
+
+	return cmd
+}
+
+// VersionSummary is an offered version of Kubernetes or a machine image
+type VersionSummary struct {
+	// Version is the version identifier
+	Version string `json:"version" yaml:"version"`
+	// Classification is the classification of the version, i.e. preview, supported or deprecated
+	Classification string `json:"classification,omitempty" yaml:"classification,omitempty"`
+	// ExpirationDate is the time at which the version expires
+	ExpirationDate *metav1.Time `json:"expirationDate,omitempty" yaml:"expirationDate,omitempty"`
+	// Expired is true if the expiration date has passed
+	Expired bool `json:"expired" yaml:"expired"`
+}
+
+// validArgsFunction completes the name of a cloud profile of the targeted garden
+func validArgsFunction(f util.Factory, ioStreams util.IOStreams) func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) != 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		manager, err := f.Manager()
+		if err != nil {
+			fmt.Fprintln(ioStreams.ErrOut, err.Error())
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		names, err := util.CloudProfileNamesForTarget(f.Context(), manager)
+		if err != nil {
+			fmt.Fprintln(ioStreams.ErrOut, err.Error())
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		return util.FilterStringsByPrefix(toComplete, names), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// getCloudProfile returns the cloud profile with the given name or, if the name is empty, the cloud profile of the targeted shoot
+func getCloudProfile(f util.Factory, name string) (*gardencorev1beta1.CloudProfile, error) {
+	manager, err := f.Manager()
+	if err != nil {
+		return nil, err
+	}
+
+	currentTarget, err := manager.CurrentTarget()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current target: %w", err)
+	}
+
+	if currentTarget.GardenName() == "" {
+		return nil, target.ErrNoGardenTargeted
+	}
+
+	gardenClient, err := manager.GardenClient(currentTarget.GardenName())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create garden cluster client: %w", err)
+	}
+
+	ctx := f.Context()
+
+	if name == "" {
+		if currentTarget.ShootName() == "" {
+			return nil, errors.New("no cloud profile name given and no shoot targeted")
+		}
+
+		shoot, err := util.ShootForTarget(ctx, gardenClient, currentTarget)
+		if err != nil {
+			return nil, err
+		}
+
+		name = shoot.Spec.CloudProfileName
+	}
+
+	return gardenClient.GetCloudProfile(ctx, name)
+}
+
+// summarizeVersions returns the versions sorted from the newest to the oldest
+func summarizeVersions(versions []gardencorev1beta1.ExpirableVersion, now time.Time) []VersionSummary {
+	summaries := make([]VersionSummary, len(versions))
+
+	for i, v := range versions {
+		summaries[i] = VersionSummary{
+			Version:        v.Version,
+			ExpirationDate: v.ExpirationDate,
+			Expired:        v.ExpirationDate != nil && !now.Before(v.ExpirationDate.Time),
+		}
+
+		if v.Classification != nil {
+			summaries[i].Classification = string(*v.Classification)
+		}
+	}
+
+	sort.SliceStable(summaries, func(i, j int) bool {
+		a, errA := version.ParseGeneric(summaries[i].Version)
+		b, errB := version.ParseGeneric(summaries[j].Version)
+
+		if errA != nil || errB != nil {
+			return summaries[i].Version > summaries[j].Version
+		}
+
+		return b.LessThan(a)
+	})
+
+	return summaries
+}
+
+// versionStatus returns the classification and the expiration of the version in a human-readable format
+func versionStatus(v VersionSummary) string {
+	status := v.Classification
+	if status == "" {
+		status = "<none>"
+	}
+
+	switch {
+	case v.Expired:
+		status += ", expired"
+	case v.ExpirationDate != nil:
+		status += ", expires " + v.ExpirationDate.UTC().Format("2006-01-02")
+	}
+
+	return status
+}
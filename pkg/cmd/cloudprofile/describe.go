@@ -0,0 +1,260 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cloudprofile
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/spf13/cobra"
+
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
+)
+
+// NewCmdDescribe returns a new cloudprofile describe command.
+func NewCmdDescribe(f util.Factory, o *DescribeOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "describe [NAME]",
+		Short: "Show the versions, volume types, regions and zones of a cloud profile",
+		Long: `Show the Kubernetes versions, machine images, volume types, regions and zones of a cloud profile of the targeted garden,
+including the classification and expiration date of the versions. Use list-machine-types to show the machine types.
+If no name is given, the cloud profile of the targeted shoot is described.`,
+		Example: `# describe the cloud profile of the targeted shoot
+gardenctl cloudprofile describe
+
+# describe the cloud profile aws as yaml
+gardenctl cloudprofile describe aws -o yaml`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: validArgsFunction(f, o.IOStreams),
+		RunE:              base.WrapRunE(o, f),
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// DescribeOptions is a struct to support cloudprofile describe command
+type DescribeOptions struct {
+	base.Options
+
+	// Name is the name of the cloud profile
+	Name string
+}
+
+// NewDescribeOptions returns initialized DescribeOptions
+func NewDescribeOptions(ioStreams util.IOStreams) *DescribeOptions {
+	return &DescribeOptions{
+		Options: base.Options{
+			IOStreams: ioStreams,
+		},
+	}
+}
+
+// Description is the machine-readable output of cloudprofile describe
+type Description struct {
+	// Name is the name of the cloud profile
+	Name string `json:"name" yaml:"name"`
+	// Provider is the provider type of the cloud profile
+	Provider string `json:"provider" yaml:"provider"`
+	// KubernetesVersions are the offered Kubernetes versions
+	KubernetesVersions []VersionSummary `json:"kubernetesVersions" yaml:"kubernetesVersions"`
+	// MachineImages are the offered machine images
+	MachineImages []MachineImageSummary `json:"machineImages" yaml:"machineImages"`
+	// MachineTypes is the number of offered machine types
+	MachineTypes int `json:"machineTypes" yaml:"machineTypes"`
+	// VolumeTypes are the offered volume types
+	VolumeTypes []VolumeTypeSummary `json:"volumeTypes" yaml:"volumeTypes"`
+	// Regions are the offered regions
+	Regions []RegionSummary `json:"regions" yaml:"regions"`
+}
+
+// MachineImageSummary is an offered machine image
+type MachineImageSummary struct {
+	// Name is the name of the machine image
+	Name string `json:"name" yaml:"name"`
+	// Versions are the offered versions of the machine image
+	Versions []VersionSummary `json:"versions" yaml:"versions"`
+}
+
+// VolumeTypeSummary is an offered volume type
+type VolumeTypeSummary struct {
+	// Name is the name of the volume type
+	Name string `json:"name" yaml:"name"`
+	// Class is the class of the volume type
+	Class string `json:"class" yaml:"class"`
+	// Usable is false if the volume type cannot be used for shoots
+	Usable bool `json:"usable" yaml:"usable"`
+}
+
+// RegionSummary is an offered region
+type RegionSummary struct {
+	// Name is the name of the region
+	Name string `json:"name" yaml:"name"`
+	// Zones are the availability zones of the region
+	Zones []ZoneSummary `json:"zones" yaml:"zones"`
+}
+
+// ZoneSummary is an availability zone of a region
+type ZoneSummary struct {
+	// Name is the name of the zone
+	Name string `json:"name" yaml:"name"`
+	// UnavailableMachineTypes are the machine types that are not available in the zone
+	UnavailableMachineTypes []string `json:"unavailableMachineTypes,omitempty" yaml:"unavailableMachineTypes,omitempty"`
+	// UnavailableVolumeTypes are the volume types that are not available in the zone
+	UnavailableVolumeTypes []string `json:"unavailableVolumeTypes,omitempty" yaml:"unavailableVolumeTypes,omitempty"`
+}
+
+// Complete adapts from the command line args to the data required.
+func (o *DescribeOptions) Complete(f util.Factory, cmd *cobra.Command, args []string) error {
+	if len(args) > 0 {
+		o.Name = strings.TrimSpace(args[0])
+	}
+
+	return nil
+}
+
+// Run executes the command
+func (o *DescribeOptions) Run(f util.Factory) error {
+	cloudProfile, err := getCloudProfile(f, o.Name)
+	if err != nil {
+		return err
+	}
+
+	description := describeCloudProfile(cloudProfile, f.Clock().Now())
+
+	if o.Output != "" {
+		return o.PrintObject(description)
+	}
+
+	printDescription(o.IOStreams.Out, description)
+
+	return nil
+}
+
+func describeCloudProfile(cloudProfile *gardencorev1beta1.CloudProfile, now time.Time) *Description {
+	description := &Description{
+		Name:               cloudProfile.Name,
+		Provider:           cloudProfile.Spec.Type,
+		KubernetesVersions: summarizeVersions(cloudProfile.Spec.Kubernetes.Versions, now),
+		MachineImages:      []MachineImageSummary{},
+		MachineTypes:       len(cloudProfile.Spec.MachineTypes),
+		VolumeTypes:        []VolumeTypeSummary{},
+		Regions:            []RegionSummary{},
+	}
+
+	for _, machineImage := range cloudProfile.Spec.MachineImages {
+		versions := make([]gardencorev1beta1.ExpirableVersion, len(machineImage.Versions))
+		for i, v := range machineImage.Versions {
+			versions[i] = v.ExpirableVersion
+		}
+
+		description.MachineImages = append(description.MachineImages, MachineImageSummary{
+			Name:     machineImage.Name,
+			Versions: summarizeVersions(versions, now),
+		})
+	}
+
+	for _, volumeType := range cloudProfile.Spec.VolumeTypes {
+		description.VolumeTypes = append(description.VolumeTypes, VolumeTypeSummary{
+			Name:   volumeType.Name,
+			Class:  volumeType.Class,
+			Usable: volumeType.Usable == nil || *volumeType.Usable,
+		})
+	}
+
+	for _, region := range cloudProfile.Spec.Regions {
+		summary := RegionSummary{Name: region.Name, Zones: []ZoneSummary{}}
+
+		for _, zone := range region.Zones {
+			summary.Zones = append(summary.Zones, ZoneSummary{
+				Name:                    zone.Name,
+				UnavailableMachineTypes: zone.UnavailableMachineTypes,
+				UnavailableVolumeTypes:  zone.UnavailableVolumeTypes,
+			})
+		}
+
+		description.Regions = append(description.Regions, summary)
+	}
+
+	return description
+}
+
+// printDescription prints the cloud profile description in a human-readable format
+func printDescription(out io.Writer, d *Description) {
+	fmt.Fprintf(out, "Name:           %s\n", d.Name)
+	fmt.Fprintf(out, "Provider:       %s\n", d.Provider)
+	fmt.Fprintf(out, "Machine Types:  %d (see gardenctl cloudprofile list-machine-types %s)\n", d.MachineTypes, d.Name)
+
+	fmt.Fprintln(out, "Kubernetes Versions:")
+	printVersions(out, "  ", d.KubernetesVersions)
+
+	fmt.Fprintln(out, "Machine Images:")
+
+	if len(d.MachineImages) == 0 {
+		fmt.Fprintln(out, "  <none>")
+	}
+
+	for _, machineImage := range d.MachineImages {
+		fmt.Fprintf(out, "  %s:\n", machineImage.Name)
+		printVersions(out, "    ", machineImage.Versions)
+	}
+
+	fmt.Fprintln(out, "Volume Types:")
+
+	if len(d.VolumeTypes) == 0 {
+		fmt.Fprintln(out, "  <none>")
+	}
+
+	for _, volumeType := range d.VolumeTypes {
+		usable := ""
+		if !volumeType.Usable {
+			usable = ", not usable"
+		}
+
+		fmt.Fprintf(out, "  %s (%s%s)\n", volumeType.Name, volumeType.Class, usable)
+	}
+
+	fmt.Fprintln(out, "Regions:")
+
+	if len(d.Regions) == 0 {
+		fmt.Fprintln(out, "  <none>")
+	}
+
+	for _, region := range d.Regions {
+		zones := make([]string, len(region.Zones))
+		for i, zone := range region.Zones {
+			zones[i] = zone.Name
+		}
+
+		fmt.Fprintf(out, "  %s: %s\n", region.Name, strings.Join(zones, ", "))
+
+		for _, zone := range region.Zones {
+			if len(zone.UnavailableMachineTypes) > 0 {
+				fmt.Fprintf(out, "    %s: unavailable machine types %s\n", zone.Name, strings.Join(zone.UnavailableMachineTypes, ", "))
+			}
+
+			if len(zone.UnavailableVolumeTypes) > 0 {
+				fmt.Fprintf(out, "    %s: unavailable volume types %s\n", zone.Name, strings.Join(zone.UnavailableVolumeTypes, ", "))
+			}
+		}
+	}
+}
+
+func printVersions(out io.Writer, indent string, versions []VersionSummary) {
+	if len(versions) == 0 {
+		fmt.Fprintf(out, "%s<none>\n", indent)
+	}
+
+	for _, v := range versions {
+		fmt.Fprintf(out, "%s%s (%s)\n", indent, v.Version, versionStatus(v))
+	}
+}
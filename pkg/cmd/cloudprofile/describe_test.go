@@ -0,0 +1,184 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cloudprofile_test
+
+import (
+	"context"
+	"time"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	internalfake "github.com/gardener/gardenctl-v2/internal/fake"
+	"github.com/gardener/gardenctl-v2/internal/util"
+	cmdcloudprofile "github.com/gardener/gardenctl-v2/pkg/cmd/cloudprofile"
+	"github.com/gardener/gardenctl-v2/pkg/config"
+	"github.com/gardener/gardenctl-v2/pkg/target"
+	targetmocks "github.com/gardener/gardenctl-v2/pkg/target/mocks"
+)
+
+const gardenName = "mygarden"
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time {
+	return time.Time(c)
+}
+
+var now = time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// newFactory returns a factory for the given target with a garden that has the cloud profile aws and a shoot using it
+func newFactory(ctrl *gomock.Controller, t target.Target) *internalfake.Factory {
+	preview := gardencorev1beta1.ClassificationPreview
+	deprecated := gardencorev1beta1.ClassificationDeprecated
+	supported := gardencorev1beta1.ClassificationSupported
+
+	cfg := &config.Config{
+		LinkKubeconfig: pointer.Bool(false),
+		Gardens: []config.Garden{{
+			Name:       gardenName,
+			Kubeconfig: "/not/a/real/kubeconfig",
+		}},
+	}
+
+	project := &gardencorev1beta1.Project{
+		ObjectMeta: metav1.ObjectMeta{Name: "prod1"},
+		Spec:       gardencorev1beta1.ProjectSpec{Namespace: pointer.String("garden-prod1")},
+	}
+
+	shoot := &gardencorev1beta1.Shoot{
+		ObjectMeta: metav1.ObjectMeta{Name: "myshoot", Namespace: "garden-prod1"},
+		Spec:       gardencorev1beta1.ShootSpec{CloudProfileName: "aws"},
+	}
+
+	cloudProfile := &gardencorev1beta1.CloudProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "aws"},
+		Spec: gardencorev1beta1.CloudProfileSpec{
+			Type: "aws",
+			Kubernetes: gardencorev1beta1.KubernetesSettings{
+				Versions: []gardencorev1beta1.ExpirableVersion{
+					{Version: "1.21.9", Classification: &deprecated, ExpirationDate: &metav1.Time{Time: now.Add(-time.Hour)}},
+					{Version: "1.23.0", Classification: &preview},
+					{Version: "1.22.10", Classification: &deprecated, ExpirationDate: &metav1.Time{Time: now.Add(30 * 24 * time.Hour)}},
+					{Version: "1.22.12", Classification: &supported},
+				},
+			},
+			MachineImages: []gardencorev1beta1.MachineImage{{
+				Name: "gardenlinux",
+				Versions: []gardencorev1beta1.MachineImageVersion{
+					{ExpirableVersion: gardencorev1beta1.ExpirableVersion{Version: "576.9.0", Classification: &supported}},
+				},
+			}},
+			MachineTypes: []gardencorev1beta1.MachineType{
+				{Name: "p3.2xlarge", CPU: resource.MustParse("8"), GPU: resource.MustParse("1"), Memory: resource.MustParse("61Gi")},
+				{Name: "m5.large", CPU: resource.MustParse("2"), GPU: resource.MustParse("0"), Memory: resource.MustParse("8Gi"),
+					Storage: &gardencorev1beta1.MachineTypeStorage{Class: "standard", StorageSize: resourceQuantity("50Gi")}},
+				{Name: "t2.micro", CPU: resource.MustParse("1"), GPU: resource.MustParse("0"), Memory: resource.MustParse("1Gi"), Usable: pointer.Bool(false)},
+			},
+			VolumeTypes: []gardencorev1beta1.VolumeType{
+				{Name: "gp2", Class: "standard"},
+				{Name: "io1", Class: "premium", Usable: pointer.Bool(false)},
+			},
+			Regions: []gardencorev1beta1.Region{{
+				Name: "eu-west-1",
+				Zones: []gardencorev1beta1.AvailabilityZone{
+					{Name: "eu-west-1a"},
+					{Name: "eu-west-1b", UnavailableMachineTypes: []string{"p3.2xlarge"}},
+				},
+			}, {
+				Name:  "us-east-1",
+				Zones: []gardencorev1beta1.AvailabilityZone{{Name: "us-east-1a", UnavailableMachineTypes: []string{"p3.2xlarge"}}},
+			}},
+		},
+	}
+
+	gardenClient := internalfake.NewClientWithObjects(project, shoot, cloudProfile)
+
+	clientProvider := targetmocks.NewMockClientProvider(ctrl)
+	clientConfig, err := cfg.ClientConfig(gardenName)
+	Expect(err).ToNot(HaveOccurred())
+	clientProvider.EXPECT().FromClientConfig(gomock.Eq(clientConfig)).Return(gardenClient, nil).AnyTimes()
+
+	factory := internalfake.NewFakeFactory(cfg, fixedClock(now), clientProvider, internalfake.NewFakeTargetProvider(t))
+	factory.ContextImpl = context.Background()
+
+	return factory
+}
+
+func resourceQuantity(value string) *resource.Quantity {
+	quantity := resource.MustParse(value)
+	return &quantity
+}
+
+var _ = Describe("CloudProfile Describe Command", func() {
+	var (
+		ctrl    *gomock.Controller
+		streams util.IOStreams
+		out     *util.SafeBytesBuffer
+		factory *internalfake.Factory
+		options *cmdcloudprofile.DescribeOptions
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		factory = newFactory(ctrl, target.NewTarget(gardenName, "prod1", "", "myshoot"))
+		streams, _, out, _ = util.NewTestIOStreams()
+		options = cmdcloudprofile.NewDescribeOptions(streams)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("should describe the cloud profile of the targeted shoot", func() {
+		cmd := cmdcloudprofile.NewCmdDescribe(factory, options)
+		Expect(cmd.RunE(cmd, nil)).To(Succeed())
+		Expect(out.String()).To(Equal(`Name:           aws
+Provider:       aws
+Machine Types:  3 (see gardenctl cloudprofile list-machine-types aws)
+Kubernetes Versions:
+  1.23.0 (preview)
+  1.22.12 (supported)
+  1.22.10 (deprecated, expires 2022-03-31)
+  1.21.9 (deprecated, expired)
+Machine Images:
+  gardenlinux:
+    576.9.0 (supported)
+Volume Types:
+  gp2 (standard)
+  io1 (premium, not usable)
+Regions:
+  eu-west-1: eu-west-1a, eu-west-1b
+    eu-west-1b: unavailable machine types p3.2xlarge
+  us-east-1: us-east-1a
+    us-east-1a: unavailable machine types p3.2xlarge
+`))
+	})
+
+	It("should print the description as yaml", func() {
+		options.Output = "yaml"
+		cmd := cmdcloudprofile.NewCmdDescribe(factory, options)
+		Expect(cmd.RunE(cmd, []string{"aws"})).To(Succeed())
+		Expect(out.String()).To(ContainSubstring(`
+  - version: 1.21.9
+    classification: deprecated
+    expirationDate: "2022-03-01T11:00:00Z"
+    expired: true
+`))
+	})
+
+	It("should fail without a name if no shoot is targeted", func() {
+		factory = newFactory(ctrl, target.NewTarget(gardenName, "", "", ""))
+		cmd := cmdcloudprofile.NewCmdDescribe(factory, options)
+		Expect(cmd.RunE(cmd, nil)).To(MatchError("no cloud profile name given and no shoot targeted"))
+	})
+})
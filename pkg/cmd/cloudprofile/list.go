@@ -0,0 +1,328 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cloudprofile
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1beta1 "k8s.io/apimachinery/pkg/apis/meta/v1beta1"
+	"k8s.io/cli-runtime/pkg/printers"
+
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
+)
+
+// NewCmdListMachineTypes returns a new cloudprofile list-machine-types command.
+func NewCmdListMachineTypes(f util.Factory, o *ListMachineTypesOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list-machine-types [NAME]",
+		Short: "List the machine types of a cloud profile",
+		Long: `List the machine types of a cloud profile of the targeted garden with their CPU, GPU, memory and storage.
+With --region, only the machine types that are available in at least one zone of the region are listed, together with
+the zones in which they are available. If no name is given, the cloud profile of the targeted shoot is used.`,
+		Example: `# list the machine types of the cloud profile of the targeted shoot
+gardenctl cloudprofile list-machine-types
+
+# list the machine types of the cloud profile aws that are available in the region eu-west-1
+gardenctl cloudprofile list-machine-types aws --region eu-west-1`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: validArgsFunction(f, o.IOStreams),
+		RunE:              base.WrapRunE(o, f),
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// ListMachineTypesOptions is a struct to support cloudprofile list-machine-types command
+type ListMachineTypesOptions struct {
+	base.Options
+
+	// Name is the name of the cloud profile
+	Name string
+	// Region only lists the machine types that are available in the region
+	Region string
+}
+
+// NewListMachineTypesOptions returns initialized ListMachineTypesOptions
+func NewListMachineTypesOptions(ioStreams util.IOStreams) *ListMachineTypesOptions {
+	return &ListMachineTypesOptions{
+		Options: base.Options{
+			IOStreams: ioStreams,
+		},
+	}
+}
+
+// MachineTypeSummary is the machine-readable output of cloudprofile list-machine-types
+type MachineTypeSummary struct {
+	// Name is the name of the machine type
+	Name string `json:"name" yaml:"name"`
+	// CPU is the number of CPUs
+	CPU string `json:"cpu" yaml:"cpu"`
+	// GPU is the number of GPUs
+	GPU string `json:"gpu" yaml:"gpu"`
+	// Memory is the amount of memory
+	Memory string `json:"memory" yaml:"memory"`
+	// Storage is the size and class of the root volume, if the machine type has a fixed root volume
+	Storage string `json:"storage,omitempty" yaml:"storage,omitempty"`
+	// Usable is false if the machine type cannot be used for shoots
+	Usable bool `json:"usable" yaml:"usable"`
+	// Zones are the zones of the region in which the machine type is available, only set if a region is given
+	Zones []string `json:"zones,omitempty" yaml:"zones,omitempty"`
+}
+
+// AddFlags binds the command options to a given flagset
+func (o *ListMachineTypesOptions) AddFlags(flags *pflag.FlagSet) {
+	o.Options.AddFlags(flags)
+	flags.StringVar(&o.Region, "region", o.Region, "Only list the machine types that are available in the region.")
+}
+
+// Complete adapts from the command line args to the data required.
+func (o *ListMachineTypesOptions) Complete(f util.Factory, cmd *cobra.Command, args []string) error {
+	if len(args) > 0 {
+		o.Name = strings.TrimSpace(args[0])
+	}
+
+	return nil
+}
+
+// Run executes the command
+func (o *ListMachineTypesOptions) Run(f util.Factory) error {
+	cloudProfile, err := getCloudProfile(f, o.Name)
+	if err != nil {
+		return err
+	}
+
+	machineTypes, err := listMachineTypes(cloudProfile, o.Region)
+	if err != nil {
+		return err
+	}
+
+	if o.Output != "" {
+		return o.PrintObject(machineTypes)
+	}
+
+	if len(machineTypes) == 0 {
+		fmt.Fprintf(o.IOStreams.ErrOut, "No machine types found in cloud profile %s\n", cloudProfile.Name)
+		return nil
+	}
+
+	table := &metav1beta1.Table{
+		ColumnDefinitions: []metav1.TableColumnDefinition{
+			{Name: "Name", Type: "string", Format: "name"},
+			{Name: "CPU", Type: "string"},
+			{Name: "GPU", Type: "string"},
+			{Name: "Memory", Type: "string"},
+			{Name: "Storage", Type: "string"},
+			{Name: "Usable", Type: "string"},
+		},
+		Rows: make([]metav1.TableRow, len(machineTypes)),
+	}
+
+	if o.Region != "" {
+		table.ColumnDefinitions = append(table.ColumnDefinitions, metav1.TableColumnDefinition{Name: "Zones", Type: "string"})
+	}
+
+	for i, machineType := range machineTypes {
+		cells := []interface{}{
+			machineType.Name,
+			machineType.CPU,
+			machineType.GPU,
+			machineType.Memory,
+			util.ValueOrNone(machineType.Storage),
+			fmt.Sprintf("%t", machineType.Usable),
+		}
+
+		if o.Region != "" {
+			cells = append(cells, strings.Join(machineType.Zones, ","))
+		}
+
+		table.Rows[i] = metav1.TableRow{Cells: cells}
+	}
+
+	printer := printers.NewTablePrinter(printers.PrintOptions{})
+	if err := printer.PrintObj(table, o.IOStreams.Out); err != nil {
+		return fmt.Errorf("failed to output machine type table: %w", err)
+	}
+
+	return nil
+}
+
+// listMachineTypes returns the machine types of the cloud profile sorted by name. If a region is given,
+// only the machine types that are available in at least one zone of the region are returned.
+func listMachineTypes(cloudProfile *gardencorev1beta1.CloudProfile, region string) ([]MachineTypeSummary, error) {
+	var zones []gardencorev1beta1.AvailabilityZone
+
+	if region != "" {
+		found := false
+
+		for _, r := range cloudProfile.Spec.Regions {
+			if r.Name == region {
+				zones, found = r.Zones, true
+				break
+			}
+		}
+
+		if !found {
+			return nil, fmt.Errorf("cloud profile %s does not offer region %q", cloudProfile.Name, region)
+		}
+	}
+
+	machineTypes := []MachineTypeSummary{}
+
+	for _, machineType := range cloudProfile.Spec.MachineTypes {
+		summary := MachineTypeSummary{
+			Name:   machineType.Name,
+			CPU:    machineType.CPU.String(),
+			GPU:    machineType.GPU.String(),
+			Memory: machineType.Memory.String(),
+			Usable: machineType.Usable == nil || *machineType.Usable,
+		}
+
+		if storage := machineType.Storage; storage != nil && storage.StorageSize != nil {
+			summary.Storage = fmt.Sprintf("%s (%s)", storage.StorageSize.String(), storage.Class)
+		}
+
+		if region != "" {
+			summary.Zones = availableZones(zones, machineType.Name)
+			if len(summary.Zones) == 0 {
+				continue
+			}
+		}
+
+		machineTypes = append(machineTypes, summary)
+	}
+
+	sort.Slice(machineTypes, func(i, j int) bool {
+		return machineTypes[i].Name < machineTypes[j].Name
+	})
+
+	return machineTypes, nil
+}
+
+// availableZones returns the names of the zones in which the machine type is available
+func availableZones(zones []gardencorev1beta1.AvailabilityZone, machineType string) []string {
+	available := []string{}
+
+zones:
+	for _, zone := range zones {
+		for _, unavailable := range zone.UnavailableMachineTypes {
+			if unavailable == machineType {
+				continue zones
+			}
+		}
+
+		available = append(available, zone.Name)
+	}
+
+	return available
+}
+
+// NewCmdListKubernetesVersions returns a new cloudprofile list-kubernetes-versions command.
+func NewCmdListKubernetesVersions(f util.Factory, o *ListKubernetesVersionsOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list-kubernetes-versions [NAME]",
+		Short: "List the Kubernetes versions of a cloud profile",
+		Long: `List the Kubernetes versions of a cloud profile of the targeted garden with their classification and expiration date,
+sorted from the newest to the oldest version. If no name is given, the cloud profile of the targeted shoot is used.`,
+		Example: `# list the Kubernetes versions of the cloud profile of the targeted shoot
+gardenctl cloudprofile list-kubernetes-versions
+
+# list the Kubernetes versions of the cloud profile aws as json
+gardenctl cloudprofile list-kubernetes-versions aws -o json`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: validArgsFunction(f, o.IOStreams),
+		RunE:              base.WrapRunE(o, f),
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// ListKubernetesVersionsOptions is a struct to support cloudprofile list-kubernetes-versions command
+type ListKubernetesVersionsOptions struct {
+	base.Options
+
+	// Name is the name of the cloud profile
+	Name string
+}
+
+// NewListKubernetesVersionsOptions returns initialized ListKubernetesVersionsOptions
+func NewListKubernetesVersionsOptions(ioStreams util.IOStreams) *ListKubernetesVersionsOptions {
+	return &ListKubernetesVersionsOptions{
+		Options: base.Options{
+			IOStreams: ioStreams,
+		},
+	}
+}
+
+// Complete adapts from the command line args to the data required.
+func (o *ListKubernetesVersionsOptions) Complete(f util.Factory, cmd *cobra.Command, args []string) error {
+	if len(args) > 0 {
+		o.Name = strings.TrimSpace(args[0])
+	}
+
+	return nil
+}
+
+// Run executes the command
+func (o *ListKubernetesVersionsOptions) Run(f util.Factory) error {
+	cloudProfile, err := getCloudProfile(f, o.Name)
+	if err != nil {
+		return err
+	}
+
+	versions := summarizeVersions(cloudProfile.Spec.Kubernetes.Versions, f.Clock().Now())
+
+	if o.Output != "" {
+		return o.PrintObject(versions)
+	}
+
+	if len(versions) == 0 {
+		fmt.Fprintf(o.IOStreams.ErrOut, "No Kubernetes versions found in cloud profile %s\n", cloudProfile.Name)
+		return nil
+	}
+
+	table := &metav1beta1.Table{
+		ColumnDefinitions: []metav1.TableColumnDefinition{
+			{Name: "Version", Type: "string"},
+			{Name: "Classification", Type: "string"},
+			{Name: "Expiration", Type: "string"},
+		},
+		Rows: make([]metav1.TableRow, len(versions)),
+	}
+
+	for i, v := range versions {
+		expiration := "<none>"
+		if v.ExpirationDate != nil {
+			expiration = v.ExpirationDate.UTC().Format("2006-01-02")
+		}
+
+		if v.Expired {
+			expiration += " (expired)"
+		}
+
+		table.Rows[i] = metav1.TableRow{
+			Cells: []interface{}{v.Version, util.ValueOrNone(v.Classification), expiration},
+		}
+	}
+
+	printer := printers.NewTablePrinter(printers.PrintOptions{})
+	if err := printer.PrintObj(table, o.IOStreams.Out); err != nil {
+		return fmt.Errorf("failed to output Kubernetes version table: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cloudprofile_test
+
+import (
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	internalfake "github.com/gardener/gardenctl-v2/internal/fake"
+	"github.com/gardener/gardenctl-v2/internal/util"
+	cmdcloudprofile "github.com/gardener/gardenctl-v2/pkg/cmd/cloudprofile"
+	"github.com/gardener/gardenctl-v2/pkg/target"
+)
+
+var _ = Describe("CloudProfile List Commands", func() {
+	var (
+		ctrl    *gomock.Controller
+		streams util.IOStreams
+		out     *util.SafeBytesBuffer
+		factory *internalfake.Factory
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		factory = newFactory(ctrl, target.NewTarget(gardenName, "", "", ""))
+		streams, _, out, _ = util.NewTestIOStreams()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("list-machine-types", func() {
+		It("should list the machine types sorted by name", func() {
+			cmd := cmdcloudprofile.NewCmdListMachineTypes(factory, cmdcloudprofile.NewListMachineTypesOptions(streams))
+			Expect(cmd.RunE(cmd, []string{"aws"})).To(Succeed())
+			Expect(out.String()).To(Equal(`NAME         CPU   GPU   MEMORY   STORAGE           USABLE
+m5.large     2     0     8Gi      50Gi (standard)   true
+p3.2xlarge   8     1     61Gi     <none>            true
+t2.micro     1     0     1Gi      <none>            false
+`))
+		})
+
+		It("should only list the machine types that are available in the region", func() {
+			cmd := cmdcloudprofile.NewCmdListMachineTypes(factory, cmdcloudprofile.NewListMachineTypesOptions(streams))
+			Expect(cmd.Flags().Set("region", "us-east-1")).To(Succeed())
+			Expect(cmd.RunE(cmd, []string{"aws"})).To(Succeed())
+			Expect(out.String()).To(Equal(`NAME       CPU   GPU   MEMORY   STORAGE           USABLE   ZONES
+m5.large   2     0     8Gi      50Gi (standard)   true     us-east-1a
+t2.micro   1     0     1Gi      <none>            false    us-east-1a
+`))
+		})
+
+		It("should fail for an unknown region", func() {
+			cmd := cmdcloudprofile.NewCmdListMachineTypes(factory, cmdcloudprofile.NewListMachineTypesOptions(streams))
+			Expect(cmd.Flags().Set("region", "ap-south-1")).To(Succeed())
+			Expect(cmd.RunE(cmd, []string{"aws"})).To(MatchError("cloud profile aws does not offer region \"ap-south-1\""))
+		})
+	})
+
+	Describe("list-kubernetes-versions", func() {
+		It("should list the versions from the newest to the oldest", func() {
+			cmd := cmdcloudprofile.NewCmdListKubernetesVersions(factory, cmdcloudprofile.NewListKubernetesVersionsOptions(streams))
+			Expect(cmd.RunE(cmd, []string{"aws"})).To(Succeed())
+			Expect(out.String()).To(Equal(`VERSION   CLASSIFICATION   EXPIRATION
+1.23.0    preview          <none>
+1.22.12   supported        <none>
+1.22.10   deprecated       2022-03-31
+1.21.9    deprecated       2022-03-01 (expired)
+`))
+		})
+	})
+})
@@ -12,7 +12,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/fatih/color"
 	"github.com/mitchellh/go-homedir"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -21,43 +23,115 @@ import (
 	cliflag "k8s.io/component-base/cli/flag"
 	"k8s.io/klog/v2"
 
+	"github.com/gardener/gardenctl-v2/internal/audit"
 	"github.com/gardener/gardenctl-v2/internal/util"
+	cmdauth "github.com/gardener/gardenctl-v2/pkg/cmd/auth"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
+	cmdcache "github.com/gardener/gardenctl-v2/pkg/cmd/cache"
+	cmdcleanup "github.com/gardener/gardenctl-v2/pkg/cmd/cleanup"
+	cmdcloudprofile "github.com/gardener/gardenctl-v2/pkg/cmd/cloudprofile"
+	cmdcompletion "github.com/gardener/gardenctl-v2/pkg/cmd/completion"
 	cmdconfig "github.com/gardener/gardenctl-v2/pkg/cmd/config"
+	cmddashboard "github.com/gardener/gardenctl-v2/pkg/cmd/dashboard"
+	cmddescribe "github.com/gardener/gardenctl-v2/pkg/cmd/describe"
 	cmdenv "github.com/gardener/gardenctl-v2/pkg/cmd/env"
+	cmdevents "github.com/gardener/gardenctl-v2/pkg/cmd/events"
+	cmdget "github.com/gardener/gardenctl-v2/pkg/cmd/get"
+	cmdkubeconfig "github.com/gardener/gardenctl-v2/pkg/cmd/kubeconfig"
+	cmdkubectl "github.com/gardener/gardenctl-v2/pkg/cmd/kubectl"
+	cmdlogin "github.com/gardener/gardenctl-v2/pkg/cmd/login"
+	cmdlogs "github.com/gardener/gardenctl-v2/pkg/cmd/logs"
+	cmdopen "github.com/gardener/gardenctl-v2/pkg/cmd/open"
+	cmdportforward "github.com/gardener/gardenctl-v2/pkg/cmd/portforward"
+	cmdreport "github.com/gardener/gardenctl-v2/pkg/cmd/report"
+	cmdselfupdate "github.com/gardener/gardenctl-v2/pkg/cmd/selfupdate"
+	cmdshoot "github.com/gardener/gardenctl-v2/pkg/cmd/shoot"
 	cmdssh "github.com/gardener/gardenctl-v2/pkg/cmd/ssh"
+	cmdstatus "github.com/gardener/gardenctl-v2/pkg/cmd/status"
 	cmdtarget "github.com/gardener/gardenctl-v2/pkg/cmd/target"
+	cmdterminal "github.com/gardener/gardenctl-v2/pkg/cmd/terminal"
 	cmdversion "github.com/gardener/gardenctl-v2/pkg/cmd/version"
+	"github.com/gardener/gardenctl-v2/pkg/config"
 	"github.com/gardener/gardenctl-v2/pkg/target"
 )
 
 const (
 	envPrefix        = "GCTL"
-	envGardenHomeDir = envPrefix + "_HOME"
+	envGardenHomeDir = util.EnvGardenHomeDir
 	envConfigName    = envPrefix + "_CONFIG_NAME"
+	envConfigSources = envPrefix + "_CONFIG_SOURCES"
 
-	gardenHomeFolder = ".garden"
-	configName       = "gardenctl-v2"
-	configExtension  = "yaml"
+	configName      = "gardenctl-v2"
+	configExtension = "yaml"
+
+	// systemConfigFile is the system-wide configuration file that is merged into the
+	// configuration file of the user if no explicit config sources have been specified
+	systemConfigFile = "/etc/gardenctl/gardenctl-v2.yaml"
+
+	// flags that override the client policy of the gardenctl configuration
+	flagQPS     = "qps"
+	flagBurst   = "burst"
+	flagRetries = "retries"
+
+	flagErrorFormat = "error-format"
+	flagDryRun      = "dry-run"
 )
 
+// configExtensions are the extensions of the config file in the order they are searched for,
+// which is the order viper used to search for them
+var configExtensions = []string{"json", "toml", "yaml", "yml"}
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the root cmd.
 func Execute() {
 	cmd := NewDefaultGardenctlCommand()
-	// any error would already be printed, so avoid doing it again here
-	if cmd.Execute() != nil {
-		os.Exit(1)
+	// the error is printed by handleError in the requested error format
+	cmd.SilenceErrors = true
+
+	if c, err := cmd.ExecuteC(); err != nil {
+		os.Exit(handleError(cmd, c, err))
 	}
 }
 
+// handleError prints the error of the executed command c in the error format of the --error-format flag
+// and returns the exit code of gardenctl
+func handleError(root *cobra.Command, c *cobra.Command, err error) int {
+	// the root command and the command groups are not runnable, they only fail if the subcommand is unknown
+	if !c.Runnable() {
+		err = base.NewUsageError(err)
+	}
+
+	errorFormat := root.PersistentFlags().Lookup(flagErrorFormat).Value.String()
+
+	switch {
+	case errorFormat == base.ErrorFormatJSON:
+		base.PrintError(root.ErrOrStderr(), errorFormat, err)
+	// commands that already printed their error, e.g. kubectl, silence it
+	case c == root || !c.SilenceErrors:
+		base.PrintError(root.ErrOrStderr(), errorFormat, err)
+
+		if !c.Runnable() {
+			fmt.Fprintf(root.ErrOrStderr(), "Run '%v --help' for usage.\n", c.CommandPath())
+		}
+	}
+
+	return base.ExitCode(err)
+}
+
 // NewDefaultGardenctlCommand creates the `gardenctl` command with defaults
 func NewDefaultGardenctlCommand() *cobra.Command {
 	factory := util.FactoryImpl{
-		TargetFlags: target.NewTargetFlags("", "", "", "", false),
+		TargetFlags:   target.NewTargetFlags("", "", "", "", false),
+		KubectlPlugin: isKubectlPlugin(os.Args[0]),
 	}
 	ioStreams := util.NewIOStreams()
 
-	return NewGardenctlCommand(&factory, ioStreams)
+	cmd := NewGardenctlCommand(&factory, ioStreams)
+	if factory.KubectlPlugin {
+		adaptToKubectlPlugin(cmd)
+	}
+
+	return cmd
 }
 
 // NewGardenctlCommand creates the `gardenctl` command
@@ -70,8 +144,9 @@ func NewGardenctlCommand(f *util.FactoryImpl, ioStreams util.IOStreams) *cobra.C
 The state of gardenctl is bound to a shell session and is not shared across windows, tabs or panes.
 A shell session is defined by the environment variable GCTL_SESSION_ID. If this is not defined,
 the value of the TERM_SESSION_ID environment variable is used instead. If both are not defined,
-this leads to an error and gardenctl cannot be executed. The target.yaml and temporary
+the process ID of the parent process, which usually is the shell, is used. The target.yaml and temporary
 kubeconfig.*.yaml files are store in the following directory ${TMPDIR}/garden/${GCTL_SESSION_ID}.
+Session directories that have not been used for 7 days are removed.
 
 You can make sure that GCTL_SESSION_ID or TERM_SESSION_ID is always present by adding
 the following code to your terminal profile ~/.profile, ~/.bashrc or comparable file.
@@ -92,13 +167,41 @@ Find more information at: https://github.com/gardener/gardenctl-v2/blob/master/R
 		SilenceUsage: true,
 	}
 
+	if f.DryRunOut == nil {
+		f.DryRunOut = ioStreams.Out
+	}
+
 	cmd.SetIn(ioStreams.In)
 	cmd.SetOut(ioStreams.Out)
 	cmd.SetErr(ioStreams.ErrOut)
 
+	// the errors of all commands are printed by cobra, the subcommands only write informational
+	// messages and warnings to stderr, which are discarded with --quiet. Child processes like kubectl
+	// write to the unwrapped stderr, so that their errors are still printed.
+	ioStreams.ErrOut = util.NewQuietWriter(ioStreams.ErrOut, func() bool {
+		return f.Quiet
+	})
+
+	if f.ErrOut == nil {
+		f.ErrOut = ioStreams.ErrOut
+	}
+
+	if f.Confirm == nil {
+		f.Confirm = func(question string) (bool, error) {
+			return util.Confirm(ioStreams, question)
+		}
+	}
+
+	// the command path is recorded in the audit log
+	cmd.PersistentPreRun = func(c *cobra.Command, _ []string) {
+		f.Command = c.CommandPath()
+	}
+
 	// register initializers
 	cobra.OnInitialize(func() {
 		initConfig(f)
+		initColor(f)
+		initClientPolicy(f, cmd.PersistentFlags())
 	})
 
 	flags := cmd.PersistentFlags()
@@ -109,10 +212,23 @@ Find more information at: https://github.com/gardener/gardenctl-v2/blob/master/R
 
 	addKlogFlags(flags)
 
+	flags.BoolVarP(&f.Quiet, "quiet", "q", false, "suppress informational messages and warnings, errors are still printed")
+	flags.BoolVar(&f.NoColor, "no-color", false, "disable colored output, colors are also disabled if the NO_COLOR environment variable is set")
+	flags.BoolVar(&f.Offline, "offline", false, "do not connect to any cluster, commands that require a cluster fail and cached data is used where possible")
+	flags.Float32(flagQPS, config.DefaultClientQPS, "maximum number of requests per second to a cluster, overrides clientPolicy.qps of the gardenctl configuration")
+	flags.Int(flagBurst, config.DefaultClientBurst, "maximum burst of requests to a cluster, overrides clientPolicy.burst of the gardenctl configuration")
+	flags.Int(flagRetries, config.DefaultClientRetries, "number of retries of requests that failed with a transient error, overrides clientPolicy.retries of the gardenctl configuration")
+	flags.Var(newErrorFormatValue(), flagErrorFormat, fmt.Sprintf("format of the error printed on stderr, one of '%s' or '%s'", base.ErrorFormatText, base.ErrorFormatJSON))
+	flags.Var((*dryRunValue)(&f.DryRun), flagDryRun, fmt.Sprintf("only print the changes to the configuration, the target and cluster resources, one of '%s' or '%s'. "+
+		"With '%s' the changes of cluster resources are sent to the cluster as dry-run requests", config.DryRunClient, config.DryRunServer, config.DryRunServer))
+	flags.Lookup(flagDryRun).NoOptDefVal = string(config.DryRunClient)
+
 	// Do not precalculate what $HOME is for the help text, because it prevents
 	// usage where the current user has no home directory (which might _just_ be
 	// the reason the user chose to specify an explicit config file).
-	flags.StringVar(&f.ConfigFile, "config", "", fmt.Sprintf("config file (default is %s)", filepath.Join("~", gardenHomeFolder, configName+"."+configExtension)))
+	flags.StringVar(&f.ConfigFile, "config", "", fmt.Sprintf("config file (default is %s on Linux or %s)",
+		filepath.Join("$XDG_CONFIG_HOME", "gardenctl", configName+"."+configExtension),
+		filepath.Join("~", util.LegacyGardenHomeFolder, configName+"."+configExtension)))
 
 	// allow to temporarily re-target a different cluster
 	f.TargetFlags.AddFlags(flags)
@@ -121,79 +237,304 @@ Find more information at: https://github.com/gardener/gardenctl-v2/blob/master/R
 
 	// add subcommands
 	cmd.AddCommand(cmdssh.NewCmdSSH(f, cmdssh.NewSSHOptions(ioStreams)))
+	cmd.AddCommand(cmdssh.NewCmdSCP(f, cmdssh.NewSCPOptions(ioStreams)))
+	cmd.AddCommand(cmdterminal.NewCmdTerminal(f, cmdterminal.NewTerminalOptions(ioStreams)))
+	cmd.AddCommand(cmdlogs.NewCmdLogs(f, cmdlogs.NewLogsOptions(ioStreams)))
+	cmd.AddCommand(cmdevents.NewCmdEvents(f, cmdevents.NewEventsOptions(ioStreams)))
 	cmd.AddCommand(cmdtarget.NewCmdTarget(f, ioStreams))
 	cmd.AddCommand(cmdversion.NewCmdVersion(f, cmdversion.NewVersionOptions(ioStreams)))
+	cmd.AddCommand(cmdselfupdate.NewCmdSelfUpdate(f, cmdselfupdate.NewSelfUpdateOptions(ioStreams)))
 	cmd.AddCommand(cmdconfig.NewCmdConfig(f, ioStreams))
 	cmd.AddCommand(cmdenv.NewCmdProviderEnv(f, ioStreams))
 	cmd.AddCommand(cmdenv.NewCmdKubectlEnv(f, ioStreams))
+	cmd.AddCommand(cmdkubeconfig.NewCmdKubeconfig(f, cmdkubeconfig.NewKubeconfigOptions(ioStreams)))
+	cmd.AddCommand(cmdkubectl.NewCmdKubectl(f, cmdkubectl.NewKubectlOptions(ioStreams)))
 	cmd.AddCommand(cmdenv.NewCmdRC(f, ioStreams))
+	cmd.AddCommand(cmdcompletion.NewCmdCompletion(f, ioStreams))
+	cmd.AddCommand(cmdget.NewCmdGet(f, ioStreams))
+	cmd.AddCommand(cmddescribe.NewCmdDescribe(f, ioStreams))
+	cmd.AddCommand(cmdcloudprofile.NewCmdCloudProfile(f, ioStreams))
+	cmd.AddCommand(cmdreport.NewCmdReport(f, ioStreams))
+	cmd.AddCommand(cmddashboard.NewCmdDashboard(f, cmddashboard.NewDashboardOptions(ioStreams)))
+	cmd.AddCommand(cmdstatus.NewCmdStatus(f, cmdstatus.NewStatusOptions(ioStreams)))
+	cmd.AddCommand(cmdshoot.NewCmdShoot(f, ioStreams))
+	cmd.AddCommand(cmdcache.NewCmdCache(f, ioStreams))
+	cmd.AddCommand(cmdcleanup.NewCmdCleanup(f, cmdcleanup.NewCleanupOptions(ioStreams)))
+	cmd.AddCommand(cmdlogin.NewCmdLogin(f, cmdlogin.NewLoginOptions(ioStreams)))
+	cmd.AddCommand(cmdauth.NewCmdAuth(f, ioStreams))
+	cmd.AddCommand(cmdopen.NewCmdOpen(f, ioStreams))
+	cmd.AddCommand(cmdportforward.NewCmdPortForward(f, cmdportforward.NewPortForwardOptions(ioStreams)))
+
+	rejectDryRun(cmd, f)
+	auditCommands(cmd, f)
+	markUsageErrors(cmd)
 
 	return cmd
 }
 
+// commandsWithoutDryRun are the commands that change files or the gardenctl binary without being able to print
+// the changes instead, they fail in dry-run mode rather than applying the changes
+var commandsWithoutDryRun = [][]string{
+	{"auth", "purge"},
+	{"cache", "clear"},
+	{"cleanup"},
+	{"config", "migrate-directories"},
+	{"login"},
+	{"provider-env"},
+	{"self-update"},
+}
+
+// commandsRunningGardenctl run other gardenctl commands as child processes, which would not be in dry-run mode
+var commandsRunningGardenctl = [][]string{
+	{"dashboard"},
+}
+
+// rejectDryRun lets the commands that do not support the dry-run mode and their subcommands fail if --dry-run is set
+func rejectDryRun(cmd *cobra.Command, f *util.FactoryImpl) {
+	for _, path := range append(commandsWithoutDryRun, commandsRunningGardenctl...) {
+		c, _, err := cmd.Find(path)
+		if err != nil || c == cmd {
+			continue
+		}
+
+		for _, c := range withSubcommands(c) {
+			c.PreRunE = func(c *cobra.Command, _ []string) error {
+				if f.DryRun != config.DryRunNone {
+					return base.NewUsageError(fmt.Errorf("--%s is not supported by %q", flagDryRun, c.CommandPath()))
+				}
+
+				return nil
+			}
+		}
+	}
+}
+
+// auditCommands records the execution of the commands that change files directly in the audit log. The changes of
+// the other commands are recorded when the configuration, the target or cluster resources are written.
+func auditCommands(cmd *cobra.Command, f *util.FactoryImpl) {
+	for _, path := range commandsWithoutDryRun {
+		c, _, err := cmd.Find(path)
+		if err != nil || c == cmd {
+			continue
+		}
+
+		for _, c := range withSubcommands(c) {
+			if c.RunE == nil {
+				continue
+			}
+
+			runE := c.RunE
+			c.RunE = func(c *cobra.Command, args []string) error {
+				err := runE(c, args)
+
+				// the command is not recorded if the configuration cannot be loaded
+				if manager, managerErr := f.Manager(); managerErr == nil {
+					manager.Configuration().Audit.Log(audit.Entry{Action: "run"}, err)
+				}
+
+				return err
+			}
+		}
+	}
+}
+
+// withSubcommands returns the command and all its subcommands, e.g. the shells of provider-env
+func withSubcommands(cmd *cobra.Command) []*cobra.Command {
+	commands := []*cobra.Command{cmd}
+
+	for _, sub := range cmd.Commands() {
+		commands = append(commands, withSubcommands(sub)...)
+	}
+
+	return commands
+}
+
+// markUsageErrors wraps the errors of invalid flags and arguments of the command and its subcommands into a UsageError
+func markUsageErrors(cmd *cobra.Command) {
+	cmd.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+		return base.NewUsageError(err)
+	})
+
+	var mark func(c *cobra.Command)
+
+	mark = func(c *cobra.Command) {
+		if args := c.Args; args != nil {
+			c.Args = func(c *cobra.Command, a []string) error {
+				return base.NewUsageError(args(c, a))
+			}
+		}
+
+		for _, sub := range c.Commands() {
+			mark(sub)
+		}
+	}
+
+	mark(cmd)
+}
+
+// errorFormatValue is the value of the --error-format flag, unsupported formats are rejected while the flags are parsed
+type errorFormatValue string
+
+var _ pflag.Value = new(errorFormatValue)
+
+func newErrorFormatValue() *errorFormatValue {
+	v := errorFormatValue(base.ErrorFormatText)
+	return &v
+}
+
+func (v *errorFormatValue) String() string {
+	return string(*v)
+}
+
+func (v *errorFormatValue) Set(format string) error {
+	if format != base.ErrorFormatText && format != base.ErrorFormatJSON {
+		return fmt.Errorf("must be one of '%s' or '%s'", base.ErrorFormatText, base.ErrorFormatJSON)
+	}
+
+	*v = errorFormatValue(format)
+
+	return nil
+}
+
+func (v *errorFormatValue) Type() string {
+	return "string"
+}
+
+// dryRunValue is the value of the --dry-run flag, unsupported strategies are rejected while the flags are parsed
+type dryRunValue config.DryRunStrategy
+
+var _ pflag.Value = new(dryRunValue)
+
+func (v *dryRunValue) String() string {
+	return string(*v)
+}
+
+func (v *dryRunValue) Set(value string) error {
+	strategy, err := config.ParseDryRunStrategy(value)
+	if err != nil {
+		return err
+	}
+
+	*v = dryRunValue(strategy)
+
+	return nil
+}
+
+func (v *dryRunValue) Type() string {
+	return "string"
+}
+
 // initConfig reads in config file and ENV variables if set.
 func initConfig(f *util.FactoryImpl) {
-	var configFile string
-
-	if f.ConfigFile != "" {
-		// Use config file from the flag.
-		viper.SetConfigFile(f.ConfigFile)
-		configFile = f.ConfigFile
-	} else {
-		// Find home directory.
-		home, err := homedir.Dir()
+	if f.ConfigFile == "" {
+		// Find the default gardenctl home directory, i.e. $XDG_CONFIG_HOME/gardenctl on Linux or ~/.garden
+		dirs, err := util.DefaultDirectories()
 		cobra.CheckErr(err)
 
-		configPath := filepath.Join(home, gardenHomeFolder)
-		configFile = configPath
+		configPath := dirs.Home
+		searchPaths := []string{configPath}
 
-		// Search config in ~/.garden or in path provided with the env variable GCTL_HOME with name "gardenctl-v2" (without extension) or name from env variable GCTL_CONFIG_NAME.
+		// Search config in the default gardenctl home directory or in path provided with the env variable GCTL_HOME with name "gardenctl-v2" (without extension) or name from env variable GCTL_CONFIG_NAME.
 		envHomeDir, ok := os.LookupEnv(envGardenHomeDir)
 		if ok {
 			envHomeDir, err = homedir.Expand(envHomeDir)
 			cobra.CheckErr(err)
-			configFile = envHomeDir
-			viper.AddConfigPath(envHomeDir)
+			configPath = envHomeDir
+			searchPaths = append([]string{envHomeDir}, searchPaths...)
 		}
 
-		viper.AddConfigPath(configPath)
+		name := configName
+		if n, ok := os.LookupEnv(envConfigName); ok {
+			name = n
+		}
 
-		if name, ok := os.LookupEnv(envConfigName); ok {
-			viper.SetConfigName(name)
-			configFile = filepath.Join(configFile, name+"."+configExtension)
+		if filename, ok := findConfigFile(name, searchPaths...); ok {
+			f.ConfigFile = filename
 		} else {
-			viper.SetConfigName(configName)
-			configFile = filepath.Join(configFile, configName+"."+configExtension)
+			klog.V(1).Infof("config file %s not found in %s", name, strings.Join(searchPaths, ", "))
+
+			f.ConfigFile = filepath.Join(configPath, name+"."+configExtension)
 		}
-	}
 
-	viper.SetEnvPrefix(envPrefix)
-	viper.AutomaticEnv() // read in environment variables that match
+		f.ConfigSources = []string{systemConfigFile}
 
-	// If a config file is found, read it in.
-	if err := viper.ReadInConfig(); err != nil {
-		klog.V(1).Infof("failed to read config file: %v", err)
+		if sources, ok := os.LookupEnv(envConfigSources); ok {
+			f.ConfigSources = []string{}
 
-		f.ConfigFile = configFile
-	} else {
-		f.ConfigFile = viper.ConfigFileUsed()
+			for _, source := range filepath.SplitList(sources) {
+				source, err = homedir.Expand(source)
+				cobra.CheckErr(err)
+				f.ConfigSources = append(f.ConfigSources, source)
+			}
+		}
 	}
 
+	viper.SetEnvPrefix(envPrefix)
+	viper.AutomaticEnv() // read in environment variables that match
+
 	// initialize the factory
 
 	// prefer an explicit GCTL_HOME env,
-	// but fallback to the system-defined home directory
+	// but fallback to the default gardenctl home directory
 	home := os.Getenv(envGardenHomeDir)
 	if len(home) == 0 {
-		dir, err := homedir.Dir()
+		dirs, err := util.DefaultDirectories()
 		cobra.CheckErr(err)
 
-		home = filepath.Join(dir, gardenHomeFolder)
+		home = dirs.Home
 	}
 
 	f.GardenHomeDirectory = home
 }
 
+// findConfigFile returns the first file with the given name and one of the supported config extensions
+// in the given directories. The file is not parsed here, because it may be encrypted, it is decrypted and
+// parsed when the config is loaded.
+func findConfigFile(name string, dirs ...string) (string, bool) {
+	for _, dir := range dirs {
+		for _, ext := range configExtensions {
+			filename := filepath.Join(dir, name+"."+ext)
+			if info, err := os.Stat(filename); err == nil && !info.IsDir() {
+				return filename, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// initColor disables colored output if requested, fatih/color already considers the NO_COLOR environment variable
+func initColor(f *util.FactoryImpl) {
+	if f.NoColor {
+		color.NoColor = true
+	}
+}
+
+// initClientPolicy sets the client policy values of the flags that have been set explicitly, so that
+// the flag defaults do not override the client policy of the gardenctl configuration
+func initClientPolicy(f *util.FactoryImpl, flags *pflag.FlagSet) {
+	if flags.Changed(flagQPS) {
+		qps, err := flags.GetFloat32(flagQPS)
+		cobra.CheckErr(err)
+
+		f.ClientPolicy.QPS = &qps
+	}
+
+	if flags.Changed(flagBurst) {
+		burst, err := flags.GetInt(flagBurst)
+		cobra.CheckErr(err)
+
+		f.ClientPolicy.Burst = &burst
+	}
+
+	if flags.Changed(flagRetries) {
+		retries, err := flags.GetInt(flagRetries)
+		cobra.CheckErr(err)
+
+		f.ClientPolicy.Retries = &retries
+	}
+}
+
 func registerCompletionFuncForGlobalFlags(cmd *cobra.Command, f *util.FactoryImpl, ioStreams util.IOStreams) {
 	utilruntime.Must(cmd.RegisterFlagCompletionFunc("garden", completionWrapper(f, ioStreams, gardenFlagCompletionFunc)))
 	utilruntime.Must(cmd.RegisterFlagCompletionFunc("project", completionWrapper(f, ioStreams, projectFlagCompletionFunc)))
@@ -58,6 +58,7 @@ var _ = BeforeSuite(func() {
 		}, {
 			Name:       "bar",
 			Kubeconfig: "/not/a/real/garden-bar/kubeconfig",
+			Aliases:    []string{"baz"},
 		}},
 	}
 	Expect(cfg.Save()).To(Succeed())
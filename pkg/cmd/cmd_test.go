@@ -10,6 +10,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
 
@@ -26,6 +27,7 @@ import (
 	"github.com/gardener/gardenctl-v2/internal/fake"
 	"github.com/gardener/gardenctl-v2/internal/util"
 	"github.com/gardener/gardenctl-v2/pkg/cmd"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
 	"github.com/gardener/gardenctl-v2/pkg/target"
 	targetmocks "github.com/gardener/gardenctl-v2/pkg/target/mocks"
 )
@@ -191,13 +193,13 @@ var _ = Describe("Gardenctl command", func() {
 		})
 
 		Describe("Complete garden flag values", func() {
-			It("should return all garden names, alphabetically sorted", func() {
+			It("should return all garden names and aliases, alphabetically sorted", func() {
 				manager, err := factory.Manager()
 				Expect(err).NotTo(HaveOccurred())
 
 				values, err := cmd.GardenFlagCompletionFunc(factory.Context(), manager)
 				Expect(err).NotTo(HaveOccurred())
-				Expect(values).To(Equal([]string{gardenName2, gardenName1}))
+				Expect(values).To(Equal([]string{gardenName2, "baz", gardenName1}))
 			})
 		})
 
@@ -343,6 +345,121 @@ var _ = Describe("Gardenctl command", func() {
 				Expect(current.SeedName()).To(BeEmpty())
 				Expect(current.ShootName()).To(Equal(shootName))
 			})
+
+			It("should find an encrypted config file that is not in YAML format", func() {
+				Expect(os.Setenv(cmd.EnvConfigName, "encrypted")).To(Succeed())
+				defer func() {
+					Expect(os.Unsetenv(cmd.EnvConfigName)).To(Succeed())
+				}()
+
+				filename := filepath.Join(gardenHomeDir, "encrypted.json")
+				Expect(os.WriteFile(filename, []byte("-----BEGIN AGE ENCRYPTED FILE-----\nYWdlLWVuY3J5cHRpb24=\n-----END AGE ENCRYPTED FILE-----\n"), 0600)).To(Succeed())
+				defer os.Remove(filename)
+
+				factory.ConfigFile = ""
+
+				cmd := cmd.NewGardenctlCommand(factory, streams)
+				cmd.SetArgs([]string{"completion", "zsh"})
+				Expect(cmd.Execute()).To(Succeed())
+				Expect(factory.ConfigFile).To(Equal(filename))
+			})
+		})
+	})
+	Describe("kubectl plugin", func() {
+		It("should detect the invocation as kubectl plugin", func() {
+			Expect(cmd.IsKubectlPlugin("/usr/local/bin/kubectl-garden")).To(BeTrue())
+			Expect(cmd.IsKubectlPlugin("kubectl-garden.exe")).To(BeTrue())
+			Expect(cmd.IsKubectlPlugin("/usr/local/bin/gardenctl")).To(BeFalse())
+		})
+
+		It("should refer to kubectl garden in the help texts", func() {
+			root := cmd.NewGardenctlCommand(&util.FactoryImpl{TargetFlags: targetFlags}, streams)
+			cmd.AdaptToKubectlPlugin(root)
+
+			targetCmd, _, err := root.Find([]string{"target"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(targetCmd.CommandPath()).To(Equal("kubectl\u00a0garden target"))
+			Expect(targetCmd.Example).To(ContainSubstring("\nkubectl garden target shoot my-shoot\n"))
+			Expect(targetCmd.Example).NotTo(ContainSubstring("gardenctl "))
+
+			loginCmd, _, err := root.Find([]string{"login"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(loginCmd.Long).To(ContainSubstring("configured in the oidc section of the garden in the gardenctl configuration"))
+		})
+	})
+
+	Describe("error handling", func() {
+		execute := func(args ...string) int {
+			root := cmd.NewGardenctlCommand(&util.FactoryImpl{TargetFlags: targetFlags, ConfigFile: configFile}, streams)
+			root.SilenceErrors = true
+			root.SetArgs(args)
+
+			c, err := root.ExecuteC()
+			Expect(err).To(HaveOccurred())
+
+			return cmd.HandleError(root, c, err)
+		}
+
+		It("should fail with a usage error if the command is unknown", func() {
+			Expect(execute("foo")).To(Equal(base.ExitCodeUsage))
+			Expect(errOut.String()).To(Equal("Error: unknown command \"foo\" for \"gardenctl\"\nRun 'gardenctl --help' for usage.\n"))
+		})
+
+		It("should fail with a usage error if the arguments are invalid", func() {
+			Expect(execute("completion", "zsh", "foo")).To(Equal(base.ExitCodeUsage))
+			Expect(errOut.String()).To(Equal("Error: unknown command \"foo\" for \"gardenctl completion zsh\"\n"))
+		})
+
+		It("should print the error as json", func() {
+			Expect(execute("--error-format", "json", "completion", "zsh", "--foo")).To(Equal(base.ExitCodeUsage))
+			Expect(errOut.String()).To(Equal(`{"code":2,"reason":"Usage","message":"unknown flag: --foo"}` + "\n"))
+		})
+
+		It("should reject an unsupported error format", func() {
+			Expect(execute("--error-format", "yaml", "version")).To(Equal(base.ExitCodeUsage))
+			Expect(errOut.String()).To(Equal("Error: invalid argument \"yaml\" for \"--error-format\" flag: must be one of 'text' or 'json'\n"))
+		})
+
+		It("should reject an unsupported dry-run strategy", func() {
+			Expect(execute("--dry-run=all", "version")).To(Equal(base.ExitCodeUsage))
+			Expect(errOut.String()).To(Equal("Error: invalid argument \"all\" for \"--dry-run\" flag: must be one of 'client' or 'server'\n"))
+		})
+
+		It("should reject dry-run mode for commands that do not support it", func() {
+			Expect(execute("--dry-run", "cache", "clear")).To(Equal(base.ExitCodeUsage))
+			Expect(errOut.String()).To(Equal("Error: --dry-run is not supported by \"gardenctl cache clear\"\n"))
+		})
+
+		It("should reject dry-run mode for provider-env, which writes the credentials to files", func() {
+			Expect(execute("--dry-run", "provider-env", "bash")).To(Equal(base.ExitCodeUsage))
+			Expect(errOut.String()).To(Equal("Error: --dry-run is not supported by \"gardenctl provider-env bash\"\n"))
+		})
+
+		It("should reject dry-run mode for the dashboard, which runs gardenctl commands", func() {
+			Expect(execute("--dry-run=server", "dashboard")).To(Equal(base.ExitCodeUsage))
+			Expect(errOut.String()).To(Equal("Error: --dry-run is not supported by \"gardenctl dashboard\"\n"))
+		})
+	})
+
+	Describe("audit log", func() {
+		It("should record the commands that change files directly", func() {
+			auditFile := filepath.Join(gardenHomeDir, "audit.log")
+			auditConfigFile := filepath.Join(gardenHomeDir, "audit.yaml")
+			Expect(os.WriteFile(auditConfigFile, []byte("logging:\n  auditFile: "+auditFile+"\n"), 0600)).To(Succeed())
+
+			defer func() {
+				Expect(os.Remove(auditFile)).To(Succeed())
+				Expect(os.Remove(auditConfigFile)).To(Succeed())
+			}()
+
+			factory := &util.FactoryImpl{TargetFlags: targetFlags, GardenHomeDirectory: gardenHomeDir}
+			root := cmd.NewGardenctlCommand(factory, streams)
+			root.SetArgs([]string{"--config", auditConfigFile, "cache", "clear"})
+			Expect(root.Execute()).To(Succeed())
+
+			data, err := os.ReadFile(auditFile)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(ContainSubstring(`"command":"gardenctl cache clear","target":{"garden":"` + gardenName1 + `","project":"` + projectName + `","shoot":"` + shootName + `"},"action":"run","result":"success"}`))
 		})
 	})
 })
@@ -0,0 +1,223 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package completion
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/term"
+
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
+)
+
+// shell is a shell that gardenctl generates a completion script for
+type shell struct {
+	// name is the name of the shell and of the subcommand
+	name string
+	// instructions describe how to load the completion script
+	instructions string
+	// generate writes the completion script for the root command
+	generate func(o *CompletionOptions) error
+}
+
+var shells = []shell{
+	{
+		name: "bash",
+		instructions: `The completion script depends on the bash-completion package, install it with your package manager if
+it is not installed yet.
+
+To load the completions in your current shell session:
+
+    source <(gardenctl completion bash)
+
+To load the completions for every new session, execute once:
+
+  Linux:
+
+    gardenctl completion bash > /etc/bash_completion.d/gardenctl
+
+  macOS:
+
+    gardenctl completion bash > $(brew --prefix)/etc/bash_completion.d/gardenctl
+`,
+		generate: func(o *CompletionOptions) error {
+			return o.Root.GenBashCompletionV2(o.IOStreams.Out, !o.NoDescriptions)
+		},
+	},
+	{
+		name: "zsh",
+		instructions: `If shell completion is not already enabled in your environment, you need to enable it once:
+
+    echo "autoload -U compinit; compinit" >> ~/.zshrc
+
+To load the completions in your current shell session:
+
+    source <(gardenctl completion zsh); compdef _gardenctl gardenctl
+
+To load the completions for every new session, execute once:
+
+  Linux:
+
+    gardenctl completion zsh > "${fpath[1]}/_gardenctl"
+
+  macOS:
+
+    gardenctl completion zsh > $(brew --prefix)/share/zsh/site-functions/_gardenctl
+`,
+		generate: func(o *CompletionOptions) error {
+			if o.NoDescriptions {
+				return o.Root.GenZshCompletionNoDesc(o.IOStreams.Out)
+			}
+
+			return o.Root.GenZshCompletion(o.IOStreams.Out)
+		},
+	},
+	{
+		name: "fish",
+		instructions: `To load the completions in your current shell session:
+
+    gardenctl completion fish | source
+
+To load the completions for every new session, execute once:
+
+    gardenctl completion fish > ~/.config/fish/completions/gardenctl.fish
+`,
+		generate: func(o *CompletionOptions) error {
+			return o.Root.GenFishCompletion(o.IOStreams.Out, !o.NoDescriptions)
+		},
+	},
+	{
+		name: "powershell",
+		instructions: `To load the completions in your current shell session:
+
+    gardenctl completion powershell | Out-String | Invoke-Expression
+
+To load the completions for every new session, add the output of the above command to your PowerShell profile:
+
+    gardenctl completion powershell >> $PROFILE
+`,
+		generate: func(o *CompletionOptions) error {
+			if o.NoDescriptions {
+				return o.Root.GenPowerShellCompletion(o.IOStreams.Out)
+			}
+
+			return o.Root.GenPowerShellCompletionWithDesc(o.IOStreams.Out)
+		},
+	},
+}
+
+// NewCmdCompletion returns a new completion command.
+func NewCmdCompletion(f util.Factory, ioStreams util.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "completion",
+		Short: "Generate the completion script for the specified shell",
+		Long: `Generate the completion script for gardenctl for the specified shell.
+The completion script completes the commands and flags of gardenctl as well as garden names from the gardenctl configuration
+and project, seed, shoot and node names, which are queried from the garden and shoot clusters.
+The startup script generated by "gardenctl rc" loads the completions already.
+
+See each sub-command's help for details on how to use the generated completion script.
+`,
+	}
+
+	for _, s := range shells {
+		o := NewCompletionOptions(ioStreams)
+		subCmd := &cobra.Command{
+			Use:   s.name,
+			Short: fmt.Sprintf("Generate the completion script for %s", s.name),
+			Long: fmt.Sprintf(`Generate the completion script for gardenctl for the %s shell.
+
+%s
+You will need to start a new shell for this setup to take effect.
+`, s.name, s.instructions),
+			Args:              cobra.NoArgs,
+			ValidArgsFunction: cobra.NoFileCompletions,
+			RunE:              base.WrapRunE(o, f),
+		}
+
+		o.AddFlags(subCmd.Flags())
+		cmd.AddCommand(subCmd)
+	}
+
+	return cmd
+}
+
+// CompletionOptions is a struct to support the completion command
+type CompletionOptions struct {
+	base.Options
+
+	// Shell is the shell to generate the completion script for
+	Shell string
+
+	// Root is the root command whose completion script is generated
+	Root *cobra.Command
+
+	// NoDescriptions disables the descriptions of the completion values
+	NoDescriptions bool
+}
+
+// NewCompletionOptions returns initialized CompletionOptions
+func NewCompletionOptions(ioStreams util.IOStreams) *CompletionOptions {
+	return &CompletionOptions{
+		Options: base.Options{
+			IOStreams: ioStreams,
+		},
+	}
+}
+
+// AddFlags binds the command options to a given flagset
+func (o *CompletionOptions) AddFlags(flags *pflag.FlagSet) {
+	flags.BoolVar(&o.NoDescriptions, "no-descriptions", o.NoDescriptions, "disable completion descriptions")
+}
+
+// Complete adapts from the command line args to the data required.
+func (o *CompletionOptions) Complete(_ util.Factory, cmd *cobra.Command, _ []string) error {
+	o.Shell = cmd.Name()
+	o.Root = cmd.Root()
+
+	return nil
+}
+
+// Validate validates the provided options
+func (o *CompletionOptions) Validate() error {
+	if _, ok := lookupShell(o.Shell); !ok {
+		return fmt.Errorf("unsupported shell %q", o.Shell)
+	}
+
+	return nil
+}
+
+// Run executes the command
+func (o *CompletionOptions) Run(_ util.Factory) error {
+	s, _ := lookupShell(o.Shell)
+
+	if err := s.generate(o); err != nil {
+		return fmt.Errorf("failed to generate the completion script: %w", err)
+	}
+
+	// the completion script is of no use in a terminal, show how to load it instead
+	if out, ok := o.IOStreams.Out.(*os.File); ok && term.IsTerminal(int(out.Fd())) {
+		fmt.Fprintf(o.IOStreams.ErrOut, "\n%s", s.instructions)
+	}
+
+	return nil
+}
+
+// lookupShell returns the shell with the given name
+func lookupShell(name string) (shell, bool) {
+	for _, s := range shells {
+		if s.name == name {
+			return s, true
+		}
+	}
+
+	return shell{}, false
+}
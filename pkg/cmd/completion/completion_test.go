@@ -0,0 +1,74 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package completion_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+
+	internalfake "github.com/gardener/gardenctl-v2/internal/fake"
+	"github.com/gardener/gardenctl-v2/internal/util"
+	cmdcompletion "github.com/gardener/gardenctl-v2/pkg/cmd/completion"
+	"github.com/gardener/gardenctl-v2/pkg/config"
+	"github.com/gardener/gardenctl-v2/pkg/target"
+)
+
+var _ = Describe("Completion Command", func() {
+	var (
+		streams util.IOStreams
+		out     *util.SafeBytesBuffer
+		errOut  *util.SafeBytesBuffer
+		root    *cobra.Command
+	)
+
+	BeforeEach(func() {
+		streams, _, out, errOut = util.NewTestIOStreams()
+		factory := internalfake.NewFakeFactory(&config.Config{}, nil, nil, internalfake.NewFakeTargetProvider(target.NewTarget("", "", "", "")))
+
+		root = &cobra.Command{Use: "gardenctl"}
+		root.AddCommand(cmdcompletion.NewCmdCompletion(factory, streams))
+	})
+
+	It("should have a subcommand for each shell", func() {
+		cmd, _, err := root.Find([]string{"completion"})
+		Expect(err).NotTo(HaveOccurred())
+
+		var names []string
+		for _, c := range cmd.Commands() {
+			names = append(names, c.Name())
+		}
+
+		Expect(names).To(Equal([]string{"bash", "fish", "powershell", "zsh"}))
+	})
+
+	DescribeTable("should generate the completion script",
+		func(shell string, noDescriptions bool, matcher OmegaMatcher) {
+			args := []string{"completion", shell}
+			if noDescriptions {
+				args = append(args, "--no-descriptions")
+			}
+
+			root.SetArgs(args)
+			Expect(root.Execute()).To(Succeed())
+			Expect(out.String()).To(matcher)
+			Expect(errOut.String()).To(BeEmpty())
+		},
+		Entry("bash", "bash", false, ContainSubstring("__start_gardenctl")),
+		Entry("zsh", "zsh", false, HavePrefix("#compdef _gardenctl gardenctl")),
+		Entry("zsh without descriptions", "zsh", true, ContainSubstring("__completeNoDesc")),
+		Entry("fish", "fish", false, ContainSubstring("complete -c gardenctl")),
+		Entry("powershell", "powershell", false, ContainSubstring("Register-ArgumentCompleter -CommandName 'gardenctl'")),
+	)
+
+	It("should describe how to load the completion script", func() {
+		cmd, _, err := root.Find([]string{"completion", "powershell"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cmd.Long).To(ContainSubstring("gardenctl completion powershell | Out-String | Invoke-Expression"))
+	})
+})
@@ -0,0 +1,686 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1beta1 "k8s.io/apimachinery/pkg/apis/meta/v1beta1"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/client-go/tools/clientcmd"
+	crclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/gardener/gardenctl-v2/internal/gardenclient"
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
+	"github.com/gardener/gardenctl-v2/pkg/config"
+	"github.com/gardener/gardenctl-v2/pkg/target"
+)
+
+// NewCmdConfigAddGarden returns a new (config) add-garden command.
+func NewCmdConfigAddGarden(f util.Factory, ioStreams util.IOStreams) *cobra.Command {
+	o := &addGardenOptions{
+		Options: base.Options{
+			IOStreams: ioStreams,
+		},
+		Timeout: 10 * time.Second,
+	}
+	cmd := &cobra.Command{
+		Use:   "add-garden",
+		Short: "Add a Garden to the gardenctl configuration using its cluster identity",
+		Long: `Add a Garden to the gardenctl configuration using its cluster identity.
+The cluster identity is read from the cluster-identity ConfigMap in the kube-system namespace of the garden cluster
+that the given kubeconfig points to. It is used as identity of the Garden, which is required to share the configuration with gardenlogin.
+If a Garden with the same identity is already defined, you are asked whether it should be updated instead.
+An update replaces the kubeconfig path and the context and adds the given aliases and patterns. Use --overwrite to update without confirmation.
+Multiple gardens can be added at once with a glob pattern for --kubeconfig or with --from-dir, which scans a directory for kubeconfig files.
+In this case, gardens that are already defined are skipped unless --overwrite is set and a summary of all files is printed.
+With --from-current-context, the current context of the kubeconfig that kubectl would use, e.g. set with the KUBECONFIG environment variable,
+is added after verifying that it points to a Gardener API server.
+With --interactive, you are guided through the selection of the kubeconfig and the context and asked for the identity, aliases and patterns.
+Patterns can be downloaded from an https:// URL that serves one pattern per line.`,
+		Example: `# add the garden cluster of the given kubeconfig
+gardenctl config add-garden --kubeconfig ~/path/to/garden-cluster/kubeconfig.yaml
+
+# add the garden cluster with an alias and a pattern, an existing garden with the same identity is updated without confirmation
+gardenctl config add-garden --kubeconfig ~/path/to/garden-cluster/kubeconfig.yaml --alias dev --pattern "^shoot--(?P<project>.+)--(?P<shoot>.+)$" --overwrite
+
+# add the garden clusters of all kubeconfig files in a directory and its subdirectories
+gardenctl config add-garden --from-dir ~/kubeconfigs --recursive
+
+# add a garden cluster interactively
+gardenctl config add-garden -i
+
+# add the garden cluster of the current kubectl context
+gardenctl config add-garden --from-current-context
+
+# add the garden clusters of all kubeconfig files matching a glob pattern
+gardenctl config add-garden --kubeconfig "$HOME/kubeconfigs/garden-*.yaml"`,
+		RunE: base.WrapRunE(o, f),
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+type addGardenOptions struct {
+	base.Options
+	// Manager is the target manager used to create the garden client
+	Manager target.Manager
+	// Configuration is the gardenctl configuration
+	Configuration *config.Config
+	// Kubeconfig is the path to the kubeconfig file of the Garden cluster, it may be a glob pattern
+	Kubeconfig string
+	// FromDir is a directory that is scanned for kubeconfig files
+	FromDir string
+	// Recursive scans the subdirectories of FromDir as well
+	Recursive bool
+	// FromCurrentContext uses the current context of the default kubeconfig loading rules, which respect $KUBECONFIG
+	FromCurrentContext bool
+	// Context overrides the current-context of the garden cluster kubeconfig
+	Context string
+	// Aliases is a list of alternative names that can be used to target the Garden
+	Aliases []string
+	// Patterns is a list of regex patterns that can be defined to use custom input formats for targeting
+	Patterns []string
+	// Interactive asks for all values of the Garden
+	Interactive bool
+	// Overwrite updates an existing Garden with the same identity without confirmation
+	Overwrite bool
+	// Timeout is the maximum duration to wait for a garden cluster to respond
+	Timeout time.Duration
+}
+
+// addGardenResult is the outcome of adding the garden of a single kubeconfig file
+type addGardenResult struct {
+	// Kubeconfig is the path of the kubeconfig file
+	Kubeconfig string
+	// Garden is the cluster identity of the garden, it is empty if it could not be determined
+	Garden string
+	// Result is either added, updated or skipped
+	Result string
+	// Reason describes why the kubeconfig file has been skipped
+	Reason string
+}
+
+// Complete adapts from the command line args to the data required.
+func (o *addGardenOptions) Complete(f util.Factory, cmd *cobra.Command, args []string) error {
+	manager, err := f.Manager()
+	if err != nil {
+		return fmt.Errorf("failed to get target manager: %w", err)
+	}
+
+	o.Manager = manager
+
+	o.Configuration = manager.Configuration()
+	if o.Configuration == nil {
+		return errors.New("failed to get configuration")
+	}
+
+	if o.Kubeconfig != "" {
+		if o.Kubeconfig, err = filepath.Abs(o.Kubeconfig); err != nil {
+			return fmt.Errorf("failed to get absolute path of kubeconfig: %w", err)
+		}
+	}
+
+	if o.FromDir != "" {
+		if o.FromDir, err = filepath.Abs(o.FromDir); err != nil {
+			return fmt.Errorf("failed to get absolute path of directory: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Validate validates the provided options
+func (o *addGardenOptions) Validate() error {
+	sources := 0
+
+	for _, set := range []bool{o.Kubeconfig != "", o.FromDir != "", o.FromCurrentContext} {
+		if set {
+			sources++
+		}
+	}
+
+	if o.Interactive {
+		if sources > 0 || o.Context != "" || len(o.Aliases) > 0 || len(o.Patterns) > 0 {
+			return errors.New("--interactive must not be used with --kubeconfig, --from-dir, --from-current-context, --context, --alias or --pattern")
+		}
+	} else if sources != 1 {
+		return errors.New("exactly one of --kubeconfig, --from-dir or --from-current-context is required")
+	}
+
+	if o.FromCurrentContext && o.Context != "" {
+		return errors.New("--context must not be used with --from-current-context")
+	}
+
+	if o.Recursive && o.FromDir == "" {
+		return errors.New("--recursive can only be used with --from-dir")
+	}
+
+	if o.bulk() && (o.Context != "" || len(o.Aliases) > 0 || len(o.Patterns) > 0) {
+		return errors.New("--context, --alias and --pattern can only be used with a single kubeconfig")
+	}
+
+	if o.Timeout <= 0 {
+		return errors.New("--timeout must be a positive duration")
+	}
+
+	return validatePatterns(o.Patterns)
+}
+
+// AddFlags adds flags to adjust the output to a cobra command
+func (o *addGardenOptions) AddFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&o.Kubeconfig, "kubeconfig", "", "path to kubeconfig file of the garden cluster, a glob pattern adds all matching files")
+	flags.StringVar(&o.FromDir, "from-dir", "", "directory that is scanned for kubeconfig files of garden clusters")
+	flags.BoolVar(&o.Recursive, "recursive", false, "scan the subdirectories of --from-dir as well")
+	flags.BoolVar(&o.FromCurrentContext, "from-current-context", false, "use the current context of the kubeconfig that is used by kubectl, e.g. set with the KUBECONFIG environment variable")
+	flags.StringVar(&o.Context, "context", "", "override the current-context of the garden cluster kubeconfig")
+	flags.StringArrayVar(&o.Aliases, "alias", nil, "alternative name that can be used to target the garden, can be specified multiple times")
+	flags.StringArrayVar(&o.Patterns, "pattern", nil, "regex match pattern for custom input formats for targeting, can be specified multiple times")
+	flags.BoolVarP(&o.Interactive, "interactive", "i", false, "ask for the kubeconfig, the context, the identity, the aliases and the patterns of the garden")
+	flags.BoolVar(&o.Overwrite, "overwrite", false, "update an existing garden with the same identity without confirmation")
+	flags.DurationVar(&o.Timeout, "timeout", o.Timeout, "maximum duration to wait for a garden cluster to respond")
+}
+
+// bulk returns true if multiple kubeconfig files may be added
+func (o *addGardenOptions) bulk() bool {
+	return o.FromDir != "" || isGlobPattern(o.Kubeconfig)
+}
+
+// Run executes the command
+func (o *addGardenOptions) Run(f util.Factory) error {
+	if o.Interactive {
+		return o.runInteractive(f)
+	}
+
+	if o.bulk() {
+		return o.runBulk(f)
+	}
+
+	if o.FromCurrentContext {
+		kubeconfig, contextName, err := currentKubeconfigContext()
+		if err != nil {
+			return err
+		}
+
+		if err := o.verifyGardenerAPIServer(f.Context(), kubeconfig, contextName); err != nil {
+			return err
+		}
+
+		o.Kubeconfig = kubeconfig
+		o.Context = contextName
+	}
+
+	identity, err := o.clusterIdentity(f.Context(), o.Kubeconfig, o.Context)
+	if err != nil {
+		return err
+	}
+
+	garden := config.Garden{
+		Name:       identity,
+		Kubeconfig: o.Kubeconfig,
+		Context:    o.Context,
+		Aliases:    o.Aliases,
+		Patterns:   o.Patterns,
+	}
+
+	return o.saveGarden(garden, func(question string) (bool, error) {
+		return util.Confirm(o.IOStreams, question)
+	})
+}
+
+// saveGarden adds the given garden or updates an existing garden with the same identity, which has to be
+// confirmed unless Overwrite is set, and saves the configuration
+func (o *addGardenOptions) saveGarden(garden config.Garden, confirm func(question string) (bool, error)) error {
+	action := "added"
+
+	err := o.Configuration.AddGarden(garden)
+	if errors.Is(err, config.ErrGardenExists) {
+		if !o.Overwrite {
+			question := fmt.Sprintf("Garden %q is already defined. Do you want to update its kubeconfig, context, aliases and patterns?", garden.Name)
+
+			confirmed, confirmErr := confirm(question)
+			if confirmErr != nil {
+				return confirmErr
+			}
+
+			if !confirmed {
+				return err
+			}
+		}
+
+		if err := o.Configuration.UpdateGarden(garden); err != nil {
+			return err
+		}
+
+		action = "updated"
+	} else if err != nil {
+		return err
+	}
+
+	if err := o.Configuration.Save(); err != nil {
+		return fmt.Errorf("failed to save configuration: %w", err)
+	}
+
+	// in dry-run mode the changes have only been printed
+	if o.Configuration.IsDryRun() {
+		return nil
+	}
+
+	fmt.Fprintf(o.IOStreams.Out, "Successfully %s garden %q\n", action, garden.Name)
+
+	return nil
+}
+
+// runInteractive asks for the kubeconfig, the context, the name, the aliases and the patterns of the garden
+func (o *addGardenOptions) runInteractive(f util.Factory) error {
+	p := util.NewPrompter(o.IOStreams)
+
+	kubeconfig, err := p.Input("Path to the kubeconfig file of the garden cluster", defaultKubeconfigPath(), func(answer string) error {
+		path, err := homedir.Expand(answer)
+		if err != nil {
+			return err
+		}
+
+		_, err = clientcmd.LoadFromFile(path)
+
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if kubeconfig, err = homedir.Expand(kubeconfig); err != nil {
+		return err
+	}
+
+	if kubeconfig, err = filepath.Abs(kubeconfig); err != nil {
+		return fmt.Errorf("failed to get absolute path of kubeconfig: %w", err)
+	}
+
+	rawConfig, err := clientcmd.LoadFromFile(kubeconfig)
+	if err != nil {
+		return err
+	}
+
+	contexts := []string{}
+	for name := range rawConfig.Contexts {
+		contexts = append(contexts, name)
+	}
+
+	sort.Strings(contexts)
+
+	contextName := rawConfig.CurrentContext
+
+	if len(contexts) > 1 {
+		if contextName, err = p.Select("Context of the garden cluster", contexts, indexOf(contexts, rawConfig.CurrentContext)); err != nil {
+			return err
+		}
+	}
+
+	if contextName == rawConfig.CurrentContext {
+		contextName = ""
+	}
+
+	identity, err := o.clusterIdentity(f.Context(), kubeconfig, contextName)
+	if err != nil {
+		return err
+	}
+
+	name, err := p.Input("Identity of the garden, keep the cluster identity to share the configuration with gardenlogin", identity, func(answer string) error {
+		if answer == "" {
+			return errors.New("identity must not be empty")
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	aliases, err := p.Input("Aliases of the garden, separated by commas", "", func(answer string) error {
+		for _, alias := range splitList(answer) {
+			if i, ok := o.Configuration.IndexOfGarden(alias); ok && o.Configuration.Gardens[i].Name != name {
+				return fmt.Errorf("alias %q collides with garden %q", alias, alias)
+			}
+
+			if i, ok := o.Configuration.IndexOfGardenAlias(alias); ok && o.Configuration.Gardens[i].Name != name {
+				return fmt.Errorf("alias %q collides with an alias of garden %q", alias, o.Configuration.Gardens[i].Name)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	patterns := []string{}
+
+	for {
+		var downloaded []string
+
+		pattern, err := p.Input("Pattern for targeting or https:// URL to download patterns from, leave empty to finish", "", func(answer string) error {
+			if strings.HasPrefix(answer, "https://") {
+				var err error
+				downloaded, err = config.FetchPatterns(f.Context(), answer)
+
+				return err
+			}
+
+			if answer == "" {
+				return nil
+			}
+
+			return config.ValidatePattern(answer)
+		})
+		if err != nil {
+			return err
+		}
+
+		if pattern == "" {
+			break
+		}
+
+		if downloaded != nil {
+			fmt.Fprintf(o.IOStreams.Out, "Downloaded %d patterns\n", len(downloaded))
+			patterns = append(patterns, downloaded...)
+		} else {
+			patterns = append(patterns, pattern)
+		}
+	}
+
+	garden := config.Garden{
+		Name:       name,
+		Kubeconfig: kubeconfig,
+		Context:    contextName,
+		Aliases:    splitList(aliases),
+		Patterns:   patterns,
+	}
+
+	if len(garden.Patterns) == 0 {
+		garden.Patterns = nil
+	}
+
+	return o.saveGarden(garden, p.Confirm)
+}
+
+// defaultKubeconfigPath returns the first kubeconfig file that kubectl would use
+func defaultKubeconfigPath() string {
+	precedence := clientcmd.NewDefaultClientConfigLoadingRules().GetLoadingPrecedence()
+	if len(precedence) == 0 {
+		return ""
+	}
+
+	return precedence[0]
+}
+
+// splitList splits a comma separated list and drops empty items
+func splitList(value string) []string {
+	var items []string
+
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+func indexOf(list []string, value string) int {
+	for i, v := range list {
+		if v == value {
+			return i
+		}
+	}
+
+	return 0
+}
+
+// runBulk adds the gardens of all kubeconfig files found and prints a summary
+func (o *addGardenOptions) runBulk(f util.Factory) error {
+	files, err := o.kubeconfigFiles()
+	if err != nil {
+		return err
+	}
+
+	results := []addGardenResult{}
+	changed := false
+
+	for _, file := range files {
+		result := o.addGardenFromFile(f.Context(), file)
+		if result.Result != "skipped" {
+			changed = true
+		}
+
+		results = append(results, result)
+	}
+
+	if changed {
+		if err := o.Configuration.Save(); err != nil {
+			return fmt.Errorf("failed to save configuration: %w", err)
+		}
+	}
+
+	return o.printResults(results)
+}
+
+// addGardenFromFile adds or updates the garden of a single kubeconfig file. It never fails,
+// problems are reported as reason of a skipped result.
+func (o *addGardenOptions) addGardenFromFile(ctx context.Context, file string) addGardenResult {
+	result := addGardenResult{Kubeconfig: file, Result: "skipped"}
+
+	if _, err := clientcmd.LoadFromFile(file); err != nil {
+		result.Reason = "not a kubeconfig file"
+		return result
+	}
+
+	identity, err := o.clusterIdentity(ctx, file, "")
+	if err != nil {
+		result.Reason = err.Error()
+		return result
+	}
+
+	result.Garden = identity
+	garden := config.Garden{Name: identity, Kubeconfig: file}
+
+	err = o.Configuration.AddGarden(garden)
+	if errors.Is(err, config.ErrGardenExists) {
+		if !o.Overwrite {
+			result.Reason = "garden is already defined"
+			return result
+		}
+
+		err = o.Configuration.UpdateGarden(garden)
+		if err == nil {
+			result.Result = "updated"
+			return result
+		}
+	}
+
+	if err != nil {
+		result.Reason = err.Error()
+		return result
+	}
+
+	result.Result = "added"
+
+	return result
+}
+
+// kubeconfigFiles returns the files matching the --kubeconfig glob pattern or the files in the --from-dir directory
+func (o *addGardenOptions) kubeconfigFiles() ([]string, error) {
+	if o.FromDir == "" {
+		files, err := filepath.Glob(o.Kubeconfig)
+		if err != nil {
+			return nil, fmt.Errorf("invalid glob pattern %q: %w", o.Kubeconfig, err)
+		}
+
+		return files, nil
+	}
+
+	files := []string{}
+
+	err := filepath.WalkDir(o.FromDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if path != o.FromDir && (!o.Recursive || strings.HasPrefix(d.Name(), ".")) {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if d.Type().IsRegular() && !strings.HasPrefix(d.Name(), ".") {
+			files = append(files, path)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan directory %s: %w", o.FromDir, err)
+	}
+
+	return files, nil
+}
+
+// currentKubeconfigContext returns the kubeconfig file and the name of the current context that kubectl would use.
+// The kubeconfig file is the file that defines the current context, the cluster and the user must be defined in the same file.
+func currentKubeconfigContext() (string, string, error) {
+	rawConfig, err := clientcmd.NewDefaultClientConfigLoadingRules().Load()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+
+	contextName := rawConfig.CurrentContext
+	if contextName == "" {
+		return "", "", errors.New("current context is not set in kubeconfig")
+	}
+
+	kubeContext, ok := rawConfig.Contexts[contextName]
+	if !ok {
+		return "", "", fmt.Errorf("current context %q is not defined in kubeconfig", contextName)
+	}
+
+	filename := kubeContext.LocationOfOrigin
+
+	if cluster, ok := rawConfig.Clusters[kubeContext.Cluster]; !ok || cluster.LocationOfOrigin != filename {
+		return "", "", fmt.Errorf("cluster of current context %q is not defined in %s", contextName, filename)
+	}
+
+	if authInfo, ok := rawConfig.AuthInfos[kubeContext.AuthInfo]; !ok || authInfo.LocationOfOrigin != filename {
+		return "", "", fmt.Errorf("user of current context %q is not defined in %s", contextName, filename)
+	}
+
+	return filename, contextName, nil
+}
+
+// verifyGardenerAPIServer checks that the API server of the given kubeconfig context serves the Gardener API
+func (o *addGardenOptions) verifyGardenerAPIServer(ctx context.Context, kubeconfig, contextName string) error {
+	client, err := o.gardenClient(kubeconfig, contextName)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, o.Timeout)
+	defer cancel()
+
+	_, err = client.ListProjects(ctx, crclient.Limit(1))
+
+	var noKindMatchErr *meta.NoKindMatchError
+	if errors.As(err, &noKindMatchErr) || apierrors.IsNotFound(err) {
+		return fmt.Errorf("current context %q does not point to a Gardener API server", contextName)
+	}
+
+	// other errors, e.g. missing permissions to list projects, do not indicate that the Gardener API is not served
+	return nil
+}
+
+func (o *addGardenOptions) gardenClient(kubeconfig, contextName string) (gardenclient.Client, error) {
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		&clientcmd.ClientConfigLoadingRules{ExplicitPath: kubeconfig},
+		&clientcmd.ConfigOverrides{CurrentContext: contextName},
+	)
+
+	client, err := o.Manager.GardenClientFromConfig(clientConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create garden cluster client: %w", err)
+	}
+
+	return client, nil
+}
+
+// clusterIdentity reads the cluster identity of the garden cluster the given kubeconfig points to
+func (o *addGardenOptions) clusterIdentity(ctx context.Context, kubeconfig, contextName string) (string, error) {
+	client, err := o.gardenClient(kubeconfig, contextName)
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, o.Timeout)
+	defer cancel()
+
+	identity, err := getClusterIdentity(ctx, client)
+	if err != nil {
+		return "", fmt.Errorf("failed to get cluster identity: %w", err)
+	}
+
+	if identity == "" {
+		return "", fmt.Errorf("ConfigMap %s/%s does not contain a cluster identity", clusterIdentityNamespace, clusterIdentityName)
+	}
+
+	return identity, nil
+}
+
+func (o *addGardenOptions) printResults(results []addGardenResult) error {
+	table := &metav1beta1.Table{
+		ColumnDefinitions: []metav1.TableColumnDefinition{
+			{Name: "Kubeconfig", Type: "string"},
+			{Name: "Garden", Type: "string"},
+			{Name: "Result", Type: "string"},
+			{Name: "Reason", Type: "string"},
+		},
+		Rows: []metav1.TableRow{},
+	}
+
+	for _, r := range results {
+		table.Rows = append(table.Rows, metav1.TableRow{
+			Cells: []interface{}{r.Kubeconfig, r.Garden, r.Result, r.Reason},
+		})
+	}
+
+	printer := printers.NewTablePrinter(printers.PrintOptions{})
+	if err := printer.PrintObj(table, o.IOStreams.Out); err != nil {
+		return fmt.Errorf("failed to output add-garden result: %w", err)
+	}
+
+	return nil
+}
+
+// isGlobPattern returns true if the given path contains any of the special characters of filepath.Match
+func isGlobPattern(path string) bool {
+	return strings.ContainsAny(path, "*?[")
+}
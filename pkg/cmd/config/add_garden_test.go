@@ -0,0 +1,293 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	gardenclientmocks "github.com/gardener/gardenctl-v2/internal/gardenclient/mocks"
+	cmdconfig "github.com/gardener/gardenctl-v2/pkg/cmd/config"
+	"github.com/gardener/gardenctl-v2/pkg/config"
+)
+
+var _ = Describe("Config Subcommand AddGarden", func() {
+	Describe("Instance", func() {
+		var cmd *cobra.Command
+
+		BeforeEach(func() {
+			cmd = cmdconfig.NewCmdConfigAddGarden(factory, streams)
+		})
+
+		It("should have Use and Flags", func() {
+			Expect(cmd.Use).To(Equal("add-garden"))
+			assertAllFlagNames(cmd.Flags(), "alias", "context", "from-current-context", "from-dir", "interactive", "kubeconfig", "overwrite", "pattern", "recursive", "timeout")
+		})
+	})
+
+	Describe("Options", func() {
+		var options *cmdconfig.AddGardenOptions
+
+		BeforeEach(func() {
+			options = cmdconfig.NewAddGardenOptions()
+			options.IOStreams = streams
+		})
+
+		Describe("Complete", func() {
+			It("should make the kubeconfig path absolute", func() {
+				factory.EXPECT().Manager().Return(manager, nil)
+				manager.EXPECT().Configuration().Return(cfg)
+				options.Kubeconfig = "kubeconfig.yaml"
+				Expect(options.Complete(factory, nil, nil)).To(Succeed())
+				Expect(options.Configuration).To(BeIdenticalTo(cfg))
+				Expect(filepath.IsAbs(options.Kubeconfig)).To(BeTrue())
+			})
+		})
+
+		Describe("Validate", func() {
+			It("should require either a kubeconfig or a directory", func() {
+				Expect(options.Validate()).To(MatchError("exactly one of --kubeconfig, --from-dir or --from-current-context is required"))
+				options.Kubeconfig = "/kubeconfig.yaml"
+				options.FromDir = "/kubeconfigs"
+				Expect(options.Validate()).To(MatchError("exactly one of --kubeconfig, --from-dir or --from-current-context is required"))
+				options.FromDir = ""
+				options.FromCurrentContext = true
+				Expect(options.Validate()).To(MatchError("exactly one of --kubeconfig, --from-dir or --from-current-context is required"))
+			})
+
+			It("should not allow other sources in interactive mode", func() {
+				options.Interactive = true
+				Expect(options.Validate()).To(Succeed())
+				options.Aliases = []string{"dev"}
+				Expect(options.Validate()).To(MatchError(HavePrefix("--interactive must not be used with")))
+			})
+
+			It("should only allow recursive with a directory", func() {
+				options.Kubeconfig = "/kubeconfig.yaml"
+				options.Recursive = true
+				Expect(options.Validate()).To(MatchError("--recursive can only be used with --from-dir"))
+			})
+
+			It("should not allow aliases when adding multiple gardens", func() {
+				options.Kubeconfig = "/kubeconfigs/*.yaml"
+				options.Aliases = []string{"dev"}
+				Expect(options.Validate()).To(MatchError("--context, --alias and --pattern can only be used with a single kubeconfig"))
+			})
+
+			It("should fail for invalid patterns", func() {
+				options.Kubeconfig = "/kubeconfig.yaml"
+				options.Patterns = []string{"("}
+				Expect(options.Validate()).To(MatchError(HavePrefix("pattern[0] is not a valid regular expression")))
+			})
+		})
+
+		Describe("Run", func() {
+			var (
+				client         *gardenclientmocks.MockClient
+				kubeconfigFile string
+			)
+
+			BeforeEach(func() {
+				kubeconfigFile = filepath.Join(gardenHomeDir, "add-garden-kubeconfig.yaml")
+				writeKubeconfig(kubeconfigFile, time.Now().Add(time.Hour))
+
+				client = gardenclientmocks.NewMockClient(ctrl)
+
+				options.Manager = manager
+				options.Configuration = cfg
+				options.Kubeconfig = kubeconfigFile
+				options.Aliases = []string{"dev"}
+
+				factory.EXPECT().Context().Return(context.Background())
+				manager.EXPECT().GardenClientFromConfig(gomock.Any()).Return(client, nil)
+			})
+
+			It("should add the garden with the cluster identity", func() {
+				client.EXPECT().GetConfigMap(gomock.Any(), "kube-system", "cluster-identity").Return(clusterIdentityConfigMap(gardenIdentity3), nil)
+				Expect(options.Run(factory)).To(Succeed())
+				Expect(out.String()).To(Equal("Successfully added garden \"bazGarden\"\n"))
+				assertGarden(cfg, &config.Garden{Name: gardenIdentity3, Kubeconfig: kubeconfigFile, Aliases: []string{"dev"}})
+				assertConfigHasBeenSaved(cfg)
+			})
+
+			It("should update an existing garden after confirmation", func() {
+				in.Write([]byte("y\n"))
+				client.EXPECT().GetConfigMap(gomock.Any(), "kube-system", "cluster-identity").Return(clusterIdentityConfigMap(gardenIdentity1), nil)
+				Expect(options.Run(factory)).To(Succeed())
+				Expect(out.String()).To(HaveSuffix("Successfully updated garden \"fooGarden\"\n"))
+				assertGarden(cfg, &config.Garden{Name: gardenIdentity1, Kubeconfig: kubeconfigFile, Context: gardenContext1, Aliases: []string{"dev"}})
+				assertConfigHasBeenSaved(cfg)
+			})
+
+			It("should update an existing garden without confirmation if overwrite is set", func() {
+				options.Overwrite = true
+				client.EXPECT().GetConfigMap(gomock.Any(), "kube-system", "cluster-identity").Return(clusterIdentityConfigMap(gardenIdentity1), nil)
+				Expect(options.Run(factory)).To(Succeed())
+				Expect(out.String()).To(Equal("Successfully updated garden \"fooGarden\"\n"))
+			})
+
+			It("should fail if updating an existing garden is not confirmed", func() {
+				in.Write([]byte("n\n"))
+				client.EXPECT().GetConfigMap(gomock.Any(), "kube-system", "cluster-identity").Return(clusterIdentityConfigMap(gardenIdentity1), nil)
+				Expect(options.Run(factory)).To(MatchError(config.ErrGardenExists))
+				assertGarden(cfg, &config.Garden{Name: gardenIdentity1, Kubeconfig: kubeconfig, Context: gardenContext1})
+			})
+		})
+
+		Describe("Run interactively", func() {
+			var (
+				client         *gardenclientmocks.MockClient
+				kubeconfigFile string
+			)
+
+			BeforeEach(func() {
+				kubeconfigFile = filepath.Join(gardenHomeDir, "interactive-kubeconfig.yaml")
+				writeKubeconfig(kubeconfigFile, time.Now().Add(time.Hour))
+
+				client = gardenclientmocks.NewMockClient(ctrl)
+
+				options.Manager = manager
+				options.Configuration = cfg
+				options.Interactive = true
+
+				factory.EXPECT().Context().Return(context.Background()).AnyTimes()
+				manager.EXPECT().GardenClientFromConfig(gomock.Any()).Return(client, nil)
+			})
+
+			It("should ask for all values of the garden", func() {
+				in.Write([]byte(kubeconfigFile + "\n1\n\ndev, one\n(\n^foo/(?P<shoot>.+)$\n\n"))
+				client.EXPECT().GetConfigMap(gomock.Any(), "kube-system", "cluster-identity").Return(clusterIdentityConfigMap(gardenIdentity3), nil)
+				Expect(options.Run(factory)).To(Succeed())
+				Expect(out.String()).To(ContainSubstring("  1) my-context\n  2) token\nEnter a number [2]: "))
+				Expect(out.String()).To(ContainSubstring("Invalid answer: is not a valid regular expression"))
+				Expect(out.String()).To(HaveSuffix("Successfully added garden \"bazGarden\"\n"))
+				assertGarden(cfg, &config.Garden{
+					Name:       gardenIdentity3,
+					Kubeconfig: kubeconfigFile,
+					Context:    gardenContext1,
+					Aliases:    []string{"dev", "one"},
+					Patterns:   []string{"^foo/(?P<shoot>.+)$"},
+				})
+				assertConfigHasBeenSaved(cfg)
+			})
+
+			It("should ask again for aliases colliding with other gardens", func() {
+				in.Write([]byte(kubeconfigFile + "\n\nmy-garden\nbarGarden\n\n\n"))
+				client.EXPECT().GetConfigMap(gomock.Any(), "kube-system", "cluster-identity").Return(clusterIdentityConfigMap(gardenIdentity3), nil)
+				Expect(options.Run(factory)).To(Succeed())
+				Expect(out.String()).To(ContainSubstring(`Invalid answer: alias "barGarden" collides with garden "barGarden"`))
+				assertGarden(cfg, &config.Garden{Name: "my-garden", Kubeconfig: kubeconfigFile})
+			})
+		})
+
+		Describe("Run from the current context", func() {
+			var (
+				client         *gardenclientmocks.MockClient
+				kubeconfigFile string
+				oldKubeconfig  string
+			)
+
+			BeforeEach(func() {
+				kubeconfigFile = filepath.Join(gardenHomeDir, "current-kubeconfig.yaml")
+				writeKubeconfig(kubeconfigFile, time.Now().Add(time.Hour))
+				oldKubeconfig = os.Getenv("KUBECONFIG")
+				Expect(os.Setenv("KUBECONFIG", kubeconfigFile)).To(Succeed())
+
+				client = gardenclientmocks.NewMockClient(ctrl)
+
+				options.Manager = manager
+				options.Configuration = cfg
+				options.FromCurrentContext = true
+
+				factory.EXPECT().Context().Return(context.Background()).AnyTimes()
+				manager.EXPECT().GardenClientFromConfig(gomock.Any()).Return(client, nil).AnyTimes()
+			})
+
+			AfterEach(func() {
+				Expect(os.Setenv("KUBECONFIG", oldKubeconfig)).To(Succeed())
+			})
+
+			It("should add the garden with the kubeconfig file and the current context", func() {
+				client.EXPECT().ListProjects(gomock.Any(), gomock.Any()).Return(nil, apierrors.NewForbidden(schema.GroupResource{Resource: "projects"}, "", errors.New("forbidden")))
+				client.EXPECT().GetConfigMap(gomock.Any(), "kube-system", "cluster-identity").Return(clusterIdentityConfigMap(gardenIdentity3), nil)
+				Expect(options.Run(factory)).To(Succeed())
+				assertGarden(cfg, &config.Garden{Name: gardenIdentity3, Kubeconfig: kubeconfigFile, Context: "token"})
+			})
+
+			It("should fail if the current context does not point to a Gardener API server", func() {
+				client.EXPECT().ListProjects(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("failed to list projects: %w", &meta.NoKindMatchError{}))
+				Expect(options.Run(factory)).To(MatchError(`current context "token" does not point to a Gardener API server`))
+				assertGardenNames(cfg, gardenIdentity1, gardenIdentity2)
+			})
+		})
+
+		Describe("Run with multiple kubeconfig files", func() {
+			var (
+				client *gardenclientmocks.MockClient
+				dir    string
+			)
+
+			BeforeEach(func() {
+				var err error
+				dir, err = os.MkdirTemp(gardenHomeDir, "kubeconfigs-*")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(os.Mkdir(filepath.Join(dir, "nested"), 0700)).To(Succeed())
+				writeKubeconfig(filepath.Join(dir, "a.yaml"), time.Now().Add(time.Hour))
+				writeKubeconfig(filepath.Join(dir, "b.yaml"), time.Now().Add(time.Hour))
+				writeKubeconfig(filepath.Join(dir, "nested", "c.yaml"), time.Now().Add(time.Hour))
+				Expect(os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("- foo\n"), 0600)).To(Succeed())
+
+				client = gardenclientmocks.NewMockClient(ctrl)
+
+				options.Manager = manager
+				options.Configuration = cfg
+
+				factory.EXPECT().Context().Return(context.Background()).AnyTimes()
+				manager.EXPECT().GardenClientFromConfig(gomock.Any()).Return(client, nil).AnyTimes()
+			})
+
+			It("should add the gardens of all kubeconfig files in the directory and its subdirectories", func() {
+				options.FromDir = dir
+				options.Recursive = true
+				client.EXPECT().GetConfigMap(gomock.Any(), "kube-system", "cluster-identity").Return(clusterIdentityConfigMap(gardenIdentity3), nil)
+				client.EXPECT().GetConfigMap(gomock.Any(), "kube-system", "cluster-identity").Return(clusterIdentityConfigMap(gardenIdentity1), nil)
+				client.EXPECT().GetConfigMap(gomock.Any(), "kube-system", "cluster-identity").Return(nil, errors.New("connection refused"))
+				Expect(options.Run(factory)).To(Succeed())
+				Expect(out.String()).To(MatchRegexp(`a\.yaml\s+bazGarden\s+added\s+\n`))
+				Expect(out.String()).To(MatchRegexp(`b\.yaml\s+fooGarden\s+skipped\s+garden is already defined\n`))
+				Expect(out.String()).To(MatchRegexp(`c\.yaml\s+skipped\s+failed to get cluster identity: connection refused\n`))
+				Expect(out.String()).To(MatchRegexp(`notes\.txt\s+skipped\s+not a kubeconfig file\n`))
+				assertGardenNames(cfg, gardenIdentity1, gardenIdentity2, gardenIdentity3)
+				assertConfigHasBeenSaved(cfg)
+			})
+
+			It("should update existing gardens of the files matching a glob pattern", func() {
+				options.Kubeconfig = filepath.Join(dir, "*.yaml")
+				options.Overwrite = true
+				client.EXPECT().GetConfigMap(gomock.Any(), "kube-system", "cluster-identity").Return(clusterIdentityConfigMap(gardenIdentity1), nil)
+				client.EXPECT().GetConfigMap(gomock.Any(), "kube-system", "cluster-identity").Return(clusterIdentityConfigMap(gardenIdentity2), nil)
+				Expect(options.Run(factory)).To(Succeed())
+				Expect(out.String()).To(MatchRegexp(`a\.yaml\s+fooGarden\s+updated`))
+				Expect(out.String()).To(MatchRegexp(`b\.yaml\s+barGarden\s+updated`))
+				Expect(out.String()).NotTo(ContainSubstring("c.yaml"))
+				Expect(cfg.Gardens[1].Kubeconfig).To(Equal(filepath.Join(dir, "b.yaml")))
+			})
+		})
+	})
+})
@@ -0,0 +1,194 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
+	"github.com/gardener/gardenctl-v2/pkg/config"
+)
+
+// NewCmdConfigAlias returns a new (config) alias command.
+func NewCmdConfigAlias(f util.Factory, ioStreams util.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "alias",
+		Short: "Manage the aliases of a Garden using subcommands",
+		Long: `Manage the aliases of a Garden using subcommands like "gardenctl config alias add my-garden dev".
+An alias must not resolve to two gardens, i.e. it must neither be the identity nor an alias of another Garden.`,
+	}
+
+	cmd.AddCommand(NewCmdConfigAliasAdd(f, ioStreams))
+	cmd.AddCommand(NewCmdConfigAliasRemove(f, ioStreams))
+
+	return cmd
+}
+
+// NewCmdConfigAliasAdd returns a new (config alias) add command.
+func NewCmdConfigAliasAdd(f util.Factory, ioStreams util.IOStreams) *cobra.Command {
+	o := &aliasOptions{
+		Options: base.Options{
+			IOStreams: ioStreams,
+		},
+	}
+	cmd := &cobra.Command{
+		Use:   "add GARDEN ALIAS",
+		Short: "Add an alias to a Garden",
+		Long: `Add an alias to a Garden of the gardenctl configuration.
+The Garden can be specified by its identity or by one of its aliases.`,
+		Example: `# add the alias dev to my-garden
+gardenctl config alias add my-garden dev`,
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: validGardenArgsFunctionWrapper(f, ioStreams),
+		RunE:              base.WrapRunE(o, f),
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// NewCmdConfigAliasRemove returns a new (config alias) remove command.
+func NewCmdConfigAliasRemove(f util.Factory, ioStreams util.IOStreams) *cobra.Command {
+	o := &aliasOptions{
+		Options: base.Options{
+			IOStreams: ioStreams,
+		},
+		Remove: true,
+	}
+	cmd := &cobra.Command{
+		Use:   "remove GARDEN ALIAS",
+		Short: "Remove an alias from a Garden",
+		Long: `Remove an alias from a Garden of the gardenctl configuration.
+If the default garden refers to the removed alias, it is set to the identity of the Garden.`,
+		Example: `# remove the alias dev from my-garden
+gardenctl config alias remove my-garden dev`,
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: validGardenAliasArgsFunctionWrapper(f, ioStreams),
+		RunE:              base.WrapRunE(o, f),
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+type aliasOptions struct {
+	base.Options
+	// Configuration is the gardenctl configuration
+	Configuration *config.Config
+	// Name is the identity or an alias of the Garden
+	Name string
+	// Alias is the alias that is added or removed
+	Alias string
+	// Remove removes the alias instead of adding it
+	Remove bool
+}
+
+// Complete adapts from the command line args to the data required.
+func (o *aliasOptions) Complete(f util.Factory, cmd *cobra.Command, args []string) error {
+	config, err := getConfiguration(f)
+	if err != nil {
+		return err
+	}
+
+	o.Configuration = config
+
+	if len(args) > 0 {
+		o.Name = strings.TrimSpace(args[0])
+	}
+
+	if len(args) > 1 {
+		o.Alias = strings.TrimSpace(args[1])
+	}
+
+	return nil
+}
+
+// Validate validates the provided options
+func (o *aliasOptions) Validate() error {
+	if o.Name == "" {
+		return errors.New("garden identity is required")
+	}
+
+	if o.Alias == "" {
+		return errors.New("alias is required")
+	}
+
+	return nil
+}
+
+// AddFlags adds flags to adjust the output to a cobra command
+func (o *aliasOptions) AddFlags(_ *pflag.FlagSet) {}
+
+// Run executes the command
+func (o *aliasOptions) Run(_ util.Factory) error {
+	garden, err := o.Configuration.Garden(o.Name)
+	if err != nil {
+		return err
+	}
+
+	name := garden.Name
+
+	if o.Remove {
+		err = o.Configuration.RemoveGardenAlias(name, o.Alias)
+	} else {
+		err = o.Configuration.AddGardenAlias(name, o.Alias)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if err := o.Configuration.Save(); err != nil {
+		return fmt.Errorf("failed to configure garden: %w", err)
+	}
+
+	// in dry-run mode the changes have only been printed
+	if o.Configuration.IsDryRun() {
+		return nil
+	}
+
+	if o.Remove {
+		fmt.Fprintf(o.IOStreams.Out, "Successfully removed alias %q from garden %q\n", o.Alias, name)
+	} else {
+		fmt.Fprintf(o.IOStreams.Out, "Successfully added alias %q to garden %q\n", o.Alias, name)
+	}
+
+	return nil
+}
+
+func validGardenAliasArgsFunctionWrapper(f util.Factory, ioStreams util.IOStreams) cobraValidArgsFunction {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 1 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		config, err := getConfiguration(f)
+		if err != nil {
+			fmt.Fprintln(ioStreams.ErrOut, err.Error())
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		if len(args) == 0 {
+			return util.FilterStringsByPrefix(toComplete, config.GardenNames()), cobra.ShellCompDirectiveNoFileComp
+		}
+
+		garden, err := config.Garden(args[0])
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		return util.FilterStringsByPrefix(toComplete, garden.Aliases), cobra.ShellCompDirectiveNoFileComp
+	}
+}
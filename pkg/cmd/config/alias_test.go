@@ -0,0 +1,100 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+
+	cmdconfig "github.com/gardener/gardenctl-v2/pkg/cmd/config"
+)
+
+var _ = Describe("Config Subcommand Alias", func() {
+	Describe("Instance", func() {
+		It("should have the subcommands add and remove", func() {
+			cmd := cmdconfig.NewCmdConfigAlias(factory, streams)
+			Expect(cmd.Use).To(Equal("alias"))
+			subCommands := []string{}
+			for _, c := range cmd.Commands() {
+				subCommands = append(subCommands, c.Name())
+			}
+			Expect(subCommands).To(Equal([]string{"add", "remove"}))
+		})
+	})
+
+	Describe("Options", func() {
+		var options *cmdconfig.AliasOptions
+
+		BeforeEach(func() {
+			options = cmdconfig.NewAliasOptions(false)
+			options.IOStreams = streams
+		})
+
+		Describe("Complete", func() {
+			It("should set the garden and the alias", func() {
+				factory.EXPECT().Manager().Return(manager, nil)
+				manager.EXPECT().Configuration().Return(cfg)
+				Expect(options.Complete(factory, nil, []string{" foo ", " dev "})).To(Succeed())
+				Expect(options.Configuration).To(BeIdenticalTo(cfg))
+				Expect(options.Name).To(Equal("foo"))
+				Expect(options.Alias).To(Equal("dev"))
+			})
+		})
+
+		Describe("Validate", func() {
+			It("should fail without an alias", func() {
+				options.Name = "foo"
+				Expect(options.Validate()).To(MatchError("alias is required"))
+			})
+		})
+
+		Describe("Run", func() {
+			BeforeEach(func() {
+				options.Configuration = cfg
+			})
+
+			It("should add an alias", func() {
+				options.Name = gardenIdentity1
+				options.Alias = "dev"
+				Expect(options.Run(nil)).To(Succeed())
+				Expect(cfg.Gardens[0].Aliases).To(Equal([]string{"dev"}))
+				assertConfigHasBeenSaved(cfg)
+				Expect(out.String()).To(Equal("Successfully added alias \"dev\" to garden \"fooGarden\"\n"))
+			})
+
+			It("should fail if the alias resolves to another garden", func() {
+				cfg.Gardens[1].Aliases = []string{"dev"}
+				options.Name = gardenIdentity1
+				options.Alias = "dev"
+				Expect(options.Run(nil)).To(MatchError(`failed to add alias "dev": it is already an alias of garden "barGarden"`))
+			})
+
+			It("should remove an alias of a garden specified by alias", func() {
+				cfg.Gardens[0].Aliases = []string{"dev", "foo"}
+				options.Remove = true
+				options.Name = "foo"
+				options.Alias = "dev"
+				Expect(options.Run(nil)).To(Succeed())
+				Expect(cfg.Gardens[0].Aliases).To(Equal([]string{"foo"}))
+				assertConfigHasBeenSaved(cfg)
+				Expect(out.String()).To(Equal("Successfully removed alias \"dev\" from garden \"fooGarden\"\n"))
+			})
+		})
+	})
+
+	Describe("ValidGardenAliasArgsFunction", func() {
+		It("should complete the aliases of the garden", func() {
+			cfg.Gardens[0].Aliases = []string{"dev", "foo"}
+			factory.EXPECT().Manager().Return(manager, nil)
+			manager.EXPECT().Configuration().Return(cfg)
+			values, directive := cmdconfig.ValidGardenAliasArgsFunctionWrapper(factory, streams)(nil, []string{gardenIdentity1}, "d")
+			Expect(values).To(Equal([]string{"dev"}))
+			Expect(directive).To(Equal(cobra.ShellCompDirectiveNoFileComp))
+		})
+	})
+})
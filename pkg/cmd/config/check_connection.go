@@ -0,0 +1,300 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1beta1 "k8s.io/apimachinery/pkg/apis/meta/v1beta1"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
+	"github.com/gardener/gardenctl-v2/pkg/config"
+)
+
+const (
+	// authStatusAuthenticated is the auth status of a garden that accepted the credentials of its kubeconfig
+	authStatusAuthenticated = "authenticated"
+	// authStatusUnauthorized is the auth status of a garden that rejected the credentials of its kubeconfig
+	authStatusUnauthorized = "unauthorized"
+	// authStatusUnknown is the auth status of a garden that could not be checked
+	authStatusUnknown = "unknown"
+)
+
+// NewCmdConfigCheckConnection returns a new (config) check-connection command.
+func NewCmdConfigCheckConnection(f util.Factory, ioStreams util.IOStreams) *cobra.Command {
+	o := &checkConnectionOptions{
+		Options: base.Options{
+			IOStreams: ioStreams,
+		},
+		Timeout: 10 * time.Second,
+	}
+	cmd := &cobra.Command{
+		Use:   "check-connection",
+		Short: "Check the connection to the configured gardens",
+		Long: `Check the connection to the specified garden or to all configured gardens.
+The version of each garden cluster is requested and the credentials of its kubeconfig are verified with a
+SelfSubjectAccessReview. The latency of the version request and the auth status are printed for every garden.
+The gardens are checked concurrently, each with its own timeout. The command fails if a garden is not reachable
+or rejects the credentials, so it can be used to verify the access to the gardens before starting bulk operations.`,
+		Example: `# check the connection to all gardens
+gardenctl config check-connection
+
+# check the connection to my-garden and wait at most 3 seconds for a response
+gardenctl config check-connection my-garden --timeout 3s`,
+		ValidArgsFunction: validGardenArgsFunctionWrapper(f, ioStreams),
+		RunE:              base.WrapRunE(o, f),
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// connectionStatus is the result of checking the connection to a single garden
+type connectionStatus struct {
+	// Garden is the identity of the garden
+	Garden string `yaml:"garden" json:"garden"`
+	// Server is the address of the API server of the garden cluster
+	Server string `yaml:"server,omitempty" json:"server,omitempty"`
+	// Version is the Kubernetes version of the garden cluster
+	Version string `yaml:"version,omitempty" json:"version,omitempty"`
+	// Latency is the duration of the version request
+	Latency string `yaml:"latency,omitempty" json:"latency,omitempty"`
+	// Auth is the auth status, one of authenticated, unauthorized or unknown
+	Auth string `yaml:"auth" json:"auth"`
+	// Error is the error that occurred while checking the connection
+	Error string `yaml:"error,omitempty" json:"error,omitempty"`
+}
+
+// ok returns true if the garden is reachable and accepted the credentials
+func (s *connectionStatus) ok() bool {
+	return s.Error == "" && s.Auth == authStatusAuthenticated
+}
+
+type checkConnectionOptions struct {
+	base.Options
+	// Configuration is the gardenctl configuration
+	Configuration *config.Config
+	// Name is the identity or an alias of the garden to check, all gardens are checked if empty
+	Name string
+	// Timeout is the maximum duration to wait for a garden cluster to respond
+	Timeout time.Duration
+}
+
+// Complete adapts from the command line args to the data required.
+func (o *checkConnectionOptions) Complete(f util.Factory, cmd *cobra.Command, args []string) error {
+	config, err := getConfiguration(f)
+	if err != nil {
+		return err
+	}
+
+	o.Configuration = config
+
+	if len(args) > 0 {
+		o.Name = strings.TrimSpace(args[0])
+	}
+
+	return nil
+}
+
+// Validate validates the provided options
+func (o *checkConnectionOptions) Validate() error {
+	if o.Timeout <= 0 {
+		return errors.New("--timeout must be a positive duration")
+	}
+
+	return o.Options.Validate()
+}
+
+// AddFlags adds flags to adjust the output to a cobra command
+func (o *checkConnectionOptions) AddFlags(flags *pflag.FlagSet) {
+	o.Options.AddFlags(flags)
+	flags.DurationVar(&o.Timeout, "timeout", o.Timeout, "maximum duration to wait for a garden cluster to respond")
+}
+
+// Run executes the command
+func (o *checkConnectionOptions) Run(f util.Factory) error {
+	names := o.Configuration.GardenNames()
+
+	if o.Name != "" {
+		garden, err := o.Configuration.Garden(o.Name)
+		if err != nil {
+			return err
+		}
+
+		names = []string{garden.Name}
+	}
+
+	var wg sync.WaitGroup
+
+	statuses := make([]connectionStatus, len(names))
+
+	for i, name := range names {
+		wg.Add(1)
+
+		go func(i int, name string) {
+			defer wg.Done()
+
+			statuses[i] = o.check(f.Context(), name)
+		}(i, name)
+	}
+
+	wg.Wait()
+
+	if o.Output == "" {
+		if err := o.printTable(statuses); err != nil {
+			return err
+		}
+	} else if err := o.PrintObject(statuses); err != nil {
+		return err
+	}
+
+	failed := 0
+
+	for _, s := range statuses {
+		if !s.ok() {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("the connection to %d of %d gardens failed", failed, len(statuses))
+	}
+
+	return nil
+}
+
+// check requests the version of a single garden cluster and verifies the credentials of its kubeconfig.
+// A new client is used instead of the cached garden client, so that neither the API discovery nor retries
+// distort the latency.
+func (o *checkConnectionOptions) check(ctx context.Context, name string) connectionStatus {
+	status := connectionStatus{
+		Garden: name,
+		Auth:   authStatusUnknown,
+	}
+
+	clientConfig, err := o.Configuration.ClientConfig(name)
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+
+	restConfig, err := clientConfig.ClientConfig()
+	if err != nil {
+		status.Error = fmt.Sprintf("failed to create restclient config: %v", err)
+		return status
+	}
+
+	status.Server = restConfig.Host
+	restConfig.Timeout = o.Timeout
+
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		status.Error = fmt.Sprintf("failed to create client: %v", err)
+		return status
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, o.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	body, err := clientset.Discovery().RESTClient().Get().AbsPath("/version").Do(ctx).Raw()
+	latency := time.Since(start)
+
+	if apierrors.IsUnauthorized(err) {
+		status.Latency = latency.Round(time.Millisecond).String()
+		status.Auth = authStatusUnauthorized
+
+		return status
+	}
+
+	if err != nil {
+		status.Error = fmt.Sprintf("garden is not reachable: %v", err)
+		return status
+	}
+
+	status.Latency = latency.Round(time.Millisecond).String()
+	status.Version = gitVersion(body)
+
+	// the version endpoint can be accessed anonymously, the credentials are only verified by an authenticated request
+	review := &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Group:    "core.gardener.cloud",
+				Resource: "projects",
+				Verb:     "list",
+			},
+		},
+	}
+
+	_, err = clientset.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+
+	switch {
+	case err == nil:
+		status.Auth = authStatusAuthenticated
+	case apierrors.IsUnauthorized(err):
+		status.Auth = authStatusUnauthorized
+	default:
+		status.Error = fmt.Sprintf("failed to verify the credentials: %v", err)
+	}
+
+	return status
+}
+
+// gitVersion returns the gitVersion of a version response or an empty string if it cannot be decoded
+func gitVersion(body []byte) string {
+	info := struct {
+		GitVersion string `json:"gitVersion"`
+	}{}
+
+	if err := json.Unmarshal(body, &info); err != nil {
+		return ""
+	}
+
+	return info.GitVersion
+}
+
+func (o *checkConnectionOptions) printTable(statuses []connectionStatus) error {
+	table := &metav1beta1.Table{
+		ColumnDefinitions: []metav1.TableColumnDefinition{
+			{Name: "Garden", Type: "string"},
+			{Name: "Server", Type: "string"},
+			{Name: "Version", Type: "string"},
+			{Name: "Latency", Type: "string"},
+			{Name: "Auth", Type: "string"},
+			{Name: "Error", Type: "string"},
+		},
+		Rows: []metav1.TableRow{},
+	}
+
+	for _, s := range statuses {
+		table.Rows = append(table.Rows, metav1.TableRow{
+			Cells: []interface{}{s.Garden, s.Server, s.Version, s.Latency, s.Auth, s.Error},
+		})
+	}
+
+	printer := printers.NewTablePrinter(printers.PrintOptions{})
+	if err := printer.PrintObj(table, o.IOStreams.Out); err != nil {
+		return fmt.Errorf("failed to output connection status: %w", err)
+	}
+
+	return nil
+}
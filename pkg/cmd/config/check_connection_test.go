@@ -0,0 +1,158 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+
+	cmdconfig "github.com/gardener/gardenctl-v2/pkg/cmd/config"
+	"github.com/gardener/gardenctl-v2/pkg/config"
+)
+
+var _ = Describe("Config Subcommand CheckConnection", func() {
+	Describe("Instance", func() {
+		var cmd *cobra.Command
+
+		BeforeEach(func() {
+			cmd = cmdconfig.NewCmdConfigCheckConnection(factory, streams)
+		})
+
+		It("should have Use and Flags", func() {
+			Expect(cmd.Use).To(Equal("check-connection"))
+			assertAllFlagNames(cmd.Flags(), "output", "timeout")
+		})
+	})
+
+	Describe("Options", func() {
+		var options *cmdconfig.CheckConnectionOptions
+
+		BeforeEach(func() {
+			options = cmdconfig.NewCheckConnectionOptions()
+			options.IOStreams = streams
+		})
+
+		Describe("Complete", func() {
+			It("should complete the garden name", func() {
+				factory.EXPECT().Manager().Return(manager, nil)
+				manager.EXPECT().Configuration().Return(cfg)
+				Expect(options.Complete(factory, nil, []string{gardenIdentity1})).To(Succeed())
+				Expect(options.Configuration).To(BeIdenticalTo(cfg))
+				Expect(options.Name).To(Equal(gardenIdentity1))
+			})
+		})
+
+		Describe("Validate", func() {
+			It("should fail for a non-positive timeout", func() {
+				options.Timeout = 0
+				Expect(options.Validate()).To(MatchError("--timeout must be a positive duration"))
+			})
+		})
+
+		Describe("Run", func() {
+			var (
+				server      *httptest.Server
+				unreachable string
+			)
+
+			kubeconfigData := func(server, token string) string {
+				data, err := clientcmd.Write(clientcmdapi.Config{
+					Clusters:       map[string]*clientcmdapi.Cluster{"garden": {Server: server, InsecureSkipTLSVerify: true}},
+					AuthInfos:      map[string]*clientcmdapi.AuthInfo{"garden": {Token: token}},
+					Contexts:       map[string]*clientcmdapi.Context{"garden": {Cluster: "garden", AuthInfo: "garden"}},
+					CurrentContext: "garden",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				return string(data)
+			}
+
+			BeforeEach(func() {
+				// client-go only sends the credentials of a kubeconfig to servers with TLS
+				server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Set("Content-Type", "application/json")
+
+					switch {
+					case r.URL.Path == "/version":
+						_ = json.NewEncoder(w).Encode(map[string]string{"gitVersion": "v1.24.3"})
+					case r.Header.Get("Authorization") != "Bearer valid":
+						w.WriteHeader(http.StatusUnauthorized)
+						_ = json.NewEncoder(w).Encode(map[string]interface{}{
+							"kind": "Status", "apiVersion": "v1", "status": "Failure", "reason": "Unauthorized", "code": http.StatusUnauthorized,
+						})
+					default:
+						w.WriteHeader(http.StatusCreated)
+						_ = json.NewEncoder(w).Encode(map[string]interface{}{
+							"kind": "SelfSubjectAccessReview", "apiVersion": "authorization.k8s.io/v1", "status": map[string]interface{}{"allowed": true},
+						})
+					}
+				}))
+
+				closed := httptest.NewTLSServer(http.NotFoundHandler())
+				unreachable = closed.URL
+				closed.Close()
+
+				cfg.Gardens = []config.Garden{
+					{Name: gardenIdentity1, KubeconfigData: kubeconfigData(server.URL, "valid")},
+					{Name: gardenIdentity2, KubeconfigData: kubeconfigData(server.URL, "invalid")},
+					{Name: gardenIdentity3, KubeconfigData: kubeconfigData(unreachable, "valid")},
+				}
+				options.Configuration = cfg
+
+				factory.EXPECT().Context().Return(context.Background()).AnyTimes()
+			})
+
+			AfterEach(func() {
+				server.Close()
+			})
+
+			It("should report the connection status of all gardens", func() {
+				options.Output = "json"
+				Expect(options.Run(factory)).To(MatchError("the connection to 2 of 3 gardens failed"))
+
+				var statuses []map[string]string
+				Expect(json.Unmarshal([]byte(out.String()), &statuses)).To(Succeed())
+				Expect(statuses).To(HaveLen(3))
+
+				Expect(statuses[0]).To(HaveKeyWithValue("garden", gardenIdentity1))
+				Expect(statuses[0]).To(HaveKeyWithValue("server", server.URL))
+				Expect(statuses[0]).To(HaveKeyWithValue("version", "v1.24.3"))
+				Expect(statuses[0]).To(HaveKey("latency"))
+				Expect(statuses[0]).To(HaveKeyWithValue("auth", "authenticated"))
+				Expect(statuses[0]).NotTo(HaveKey("error"))
+
+				Expect(statuses[1]).To(HaveKeyWithValue("garden", gardenIdentity2))
+				Expect(statuses[1]).To(HaveKeyWithValue("auth", "unauthorized"))
+				Expect(statuses[1]).NotTo(HaveKey("error"))
+
+				Expect(statuses[2]).To(HaveKeyWithValue("garden", gardenIdentity3))
+				Expect(statuses[2]).To(HaveKeyWithValue("auth", "unknown"))
+				Expect(statuses[2]).To(HaveKeyWithValue("error", ContainSubstring("garden is not reachable")))
+			})
+
+			It("should check a single garden and print a table", func() {
+				options.Name = gardenIdentity1
+				Expect(options.Run(factory)).To(Succeed())
+				Expect(out.String()).To(MatchRegexp(`GARDEN\s+SERVER\s+VERSION\s+LATENCY\s+AUTH\s+ERROR\n`))
+				Expect(out.String()).To(MatchRegexp(gardenIdentity1 + `\s+` + server.URL + `\s+v1\.24\.3\s+\S+\s+authenticated\s*\n$`))
+			})
+
+			It("should fail for an unknown garden", func() {
+				options.Name = "unknown"
+				Expect(options.Run(factory)).To(MatchError(`garden "unknown" is not defined in gardenctl configuration`))
+			})
+		})
+	})
+})
@@ -25,13 +25,27 @@ func NewCmdConfig(f util.Factory, ioStreams util.IOStreams) *cobra.Command {
 
 The loading order follows these rules:
 1. If the --config flag is set, then only that file is loaded.
-2. If $GCTL_HOME environment variable is set, then it is used as primary search path for the config file. The secondary search path of the home directory is ${HOME}/.garden/.
-3. If $GCTL_CONFIG_NAME environment variable is set, then it is used as config filename. Otherwise, the config filename will default to gardenctl-v2. The config name must not include the file extension`,
+2. If $GCTL_HOME environment variable is set, then it is used as primary search path for the config file. The secondary search path is the default gardenctl home directory, which is $XDG_CONFIG_HOME/gardenctl (default ${HOME}/.config/gardenctl) on Linux unless ${HOME}/.garden/ exists and ${HOME}/.garden/ on other operating systems. Use "gardenctl config migrate-directories" to move ${HOME}/.garden/ to the XDG base directories.
+3. If $GCTL_CONFIG_NAME environment variable is set, then it is used as config filename. Otherwise, the config filename will default to gardenctl-v2. The config name must not include the file extension
+4. Unless the --config flag is set, the files listed in $GCTL_CONFIG_SOURCES (separated by the OS specific path list separator) are merged into the config file. If the environment variable is not set, the system-wide config file /etc/gardenctl/gardenctl-v2.yaml is merged. The config file takes precedence over these sources and only the config file is written by gardenctl
+5. The files fetched from the sync sources of the config file with "gardenctl config sync" are merged after the config file and before the sources of the previous rule`,
 	}
 
 	cmd.AddCommand(NewCmdConfigView(f, ioStreams))
+	cmd.AddCommand(NewCmdConfigGet(f, ioStreams))
+	cmd.AddCommand(NewCmdConfigSet(f, ioStreams))
 	cmd.AddCommand(NewCmdConfigSetGarden(f, ioStreams))
+	cmd.AddCommand(NewCmdConfigAddGarden(f, ioStreams))
+	cmd.AddCommand(NewCmdConfigSetDefaultGarden(f, ioStreams))
 	cmd.AddCommand(NewCmdConfigDeleteGarden(f, ioStreams))
+	cmd.AddCommand(NewCmdConfigRenameGarden(f, ioStreams))
+	cmd.AddCommand(NewCmdConfigAlias(f, ioStreams))
+	cmd.AddCommand(NewCmdConfigValidate(f, ioStreams))
+	cmd.AddCommand(NewCmdConfigDoctor(f, ioStreams))
+	cmd.AddCommand(NewCmdConfigCheckConnection(f, ioStreams))
+	cmd.AddCommand(NewCmdConfigMigrateDirectories(f, ioStreams))
+	cmd.AddCommand(NewCmdConfigSync(f, ioStreams))
+	cmd.AddCommand(NewCmdConfigTestPattern(f, ioStreams))
 
 	return cmd
 }
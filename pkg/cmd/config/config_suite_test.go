@@ -38,6 +38,7 @@ var (
 	factory       *utilmocks.MockFactory
 	manager       *targetmocks.MockManager
 	streams       util.IOStreams
+	in            *util.SafeBytesBuffer
 	out           *util.SafeBytesBuffer
 	errOut        *util.SafeBytesBuffer
 	patterns      []string
@@ -80,7 +81,7 @@ var _ = BeforeEach(func() {
 			}},
 	}
 
-	streams, _, out, errOut = util.NewTestIOStreams()
+	streams, in, out, errOut = util.NewTestIOStreams()
 	ctrl = gomock.NewController(GinkgoT())
 	factory = utilmocks.NewMockFactory(ctrl)
 	manager = targetmocks.NewMockManager(ctrl)
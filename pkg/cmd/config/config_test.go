@@ -16,6 +16,7 @@ import (
 
 	cmdconfig "github.com/gardener/gardenctl-v2/pkg/cmd/config"
 	"github.com/gardener/gardenctl-v2/pkg/config"
+	"github.com/gardener/gardenctl-v2/pkg/target"
 )
 
 var _ = Describe("Config Command", func() {
@@ -26,13 +27,13 @@ var _ = Describe("Config Command", func() {
 			cmd = cmdconfig.NewCmdConfig(factory, streams)
 		})
 
-		It("should have 3 subcommands", func() {
+		It("should have the config subcommands", func() {
 			Expect(cmd.Use).To(Equal("config"))
 			subCommands := []string{}
 			for _, c := range cmd.Commands() {
 				subCommands = append(subCommands, c.Name())
 			}
-			Expect(subCommands).To(Equal([]string{"delete-garden", "set-garden", "view"}))
+			Expect(subCommands).To(Equal([]string{"add-garden", "alias", "check-connection", "delete-garden", "doctor", "get", "migrate-directories", "rename-garden", "set", "set-default-garden", "set-garden", "sync", "test-pattern", "validate", "view"}))
 		})
 
 		Describe("Execute Subcommands", func() {
@@ -42,6 +43,7 @@ var _ = Describe("Config Command", func() {
 			})
 
 			It("should successfully run subcommand view", func() {
+				manager.EXPECT().TargetFlags().Return(target.NewTargetFlags("", "", "", "", false))
 				cmd.SetArgs([]string{
 					"view",
 				})
@@ -80,9 +82,11 @@ var _ = Describe("Config Command", func() {
 			})
 
 			It("should successfully run subcommand delete-garden", func() {
+				manager.EXPECT().CurrentTarget().Return(target.NewTarget(gardenIdentity1, "", "", ""), nil)
 				cmd.SetArgs([]string{
 					"delete-garden",
 					gardenIdentity2,
+					"--force",
 				})
 				Expect(cmd.Execute()).To(Succeed())
 
@@ -12,6 +12,7 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
 	"github.com/gardener/gardenctl-v2/internal/util"
 	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
@@ -28,12 +29,20 @@ func NewCmdConfigDeleteGarden(f util.Factory, ioStreams util.IOStreams) *cobra.C
 	cmd := &cobra.Command{
 		Use:   "delete-garden",
 		Short: "Delete the specified Garden from the gardenctl configuration",
+		Long: `Delete the specified Garden from the gardenctl configuration.
+The Garden can be specified by its identity or by one of its aliases. You will be asked for confirmation unless the --force flag is set.
+A Garden that is currently targeted cannot be deleted.`,
 		Example: `# delete my-garden
-gardenctl config delete-garden my-garden`,
+gardenctl config delete-garden my-garden
+
+# delete my-garden without confirmation
+gardenctl config delete-garden my-garden --force`,
 		ValidArgsFunction: validGardenArgsFunctionWrapper(f, ioStreams),
 		RunE:              base.WrapRunE(o, f),
 	}
 
+	o.AddFlags(cmd.Flags())
+
 	return cmd
 }
 
@@ -41,18 +50,32 @@ type deleteGardenOptions struct {
 	base.Options
 	// Configuration is the gardenctl configuration
 	Configuration *config.Config
-	// Name is a unique name of this Garden that can be used to target this Garden
+	// Name is a unique name or an alias of this Garden that can be used to target this Garden
 	Name string
+	// TargetedGarden is the name of the currently targeted Garden
+	TargetedGarden string
+	// Force skips the confirmation prompt
+	Force bool
 }
 
 // Complete adapts from the command line args to the data required.
 func (o *deleteGardenOptions) Complete(f util.Factory, cmd *cobra.Command, args []string) error {
-	config, err := getConfiguration(f)
+	manager, err := f.Manager()
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to get target manager: %w", err)
 	}
 
-	o.Configuration = config
+	o.Configuration = manager.Configuration()
+	if o.Configuration == nil {
+		return errors.New("failed to get configuration")
+	}
+
+	currentTarget, err := manager.CurrentTarget()
+	if err != nil {
+		return fmt.Errorf("failed to get current target: %w", err)
+	}
+
+	o.TargetedGarden = currentTarget.GardenName()
 
 	if len(args) > 0 {
 		o.Name = strings.TrimSpace(args[0])
@@ -70,21 +93,51 @@ func (o *deleteGardenOptions) Validate() error {
 	return nil
 }
 
+// AddFlags adds flags to adjust the output to a cobra command
+func (o *deleteGardenOptions) AddFlags(flags *pflag.FlagSet) {
+	flags.BoolVarP(&o.Force, "force", "f", false, "delete the garden without asking for confirmation")
+}
+
 // Run executes the command
 func (o *deleteGardenOptions) Run(_ util.Factory) error {
-	i, ok := o.Configuration.IndexOfGarden(o.Name)
-	if !ok {
-		return fmt.Errorf("garden %q is not defined in gardenctl configuration", o.Name)
+	garden, err := o.Configuration.Garden(o.Name)
+	if err != nil {
+		return err
+	}
+
+	name := garden.Name
+
+	if name == o.TargetedGarden {
+		return fmt.Errorf("garden %q is currently targeted, unset the target garden before deleting it", name)
 	}
 
-	o.Configuration.Gardens = append(o.Configuration.Gardens[:i], o.Configuration.Gardens[i+1:]...)
+	if !o.Force {
+		confirmed, err := util.Confirm(o.IOStreams, fmt.Sprintf("Do you really want to delete garden %q from the gardenctl configuration?", name))
+		if err != nil {
+			return err
+		}
+
+		if !confirmed {
+			fmt.Fprintln(o.IOStreams.Out, "Aborted")
+			return nil
+		}
+	}
 
-	err := o.Configuration.Save()
+	if _, err := o.Configuration.DeleteGarden(name); err != nil {
+		return err
+	}
+
+	err = o.Configuration.Save()
 	if err != nil {
 		return fmt.Errorf("failed to delete garden from configuration: %w", err)
 	}
 
-	fmt.Fprintf(o.IOStreams.Out, "Successfully deleted garden %q\n", o.Name)
+	// in dry-run mode the changes have only been printed
+	if o.Configuration.IsDryRun() {
+		return nil
+	}
+
+	fmt.Fprintf(o.IOStreams.Out, "Successfully deleted garden %q\n", name)
 
 	return nil
 }
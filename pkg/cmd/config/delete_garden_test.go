@@ -7,6 +7,9 @@ SPDX-License-Identifier: Apache-2.0
 package config_test
 
 import (
+	"errors"
+	"fmt"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
@@ -14,6 +17,7 @@ import (
 	"github.com/spf13/cobra"
 
 	cmdconfig "github.com/gardener/gardenctl-v2/pkg/cmd/config"
+	"github.com/gardener/gardenctl-v2/pkg/target"
 )
 
 var _ = Describe("Config Subcommand DeleteGarden", func() {
@@ -24,11 +28,11 @@ var _ = Describe("Config Subcommand DeleteGarden", func() {
 			cmd = cmdconfig.NewCmdConfigDeleteGarden(factory, streams)
 		})
 
-		It("should have Use, ValidArgsFunction and no Flags", func() {
+		It("should have Use, ValidArgsFunction and Flags", func() {
 			Expect(cmd.Use).To(Equal("delete-garden"))
 			Expect(cmd.ValidArgsFunction).NotTo(BeNil())
 			Expect(cmd.ValidArgs).To(BeNil())
-			assertAllFlagNames(cmd.Flags())
+			assertAllFlagNames(cmd.Flags(), "force")
 		})
 	})
 
@@ -49,13 +53,24 @@ var _ = Describe("Config Subcommand DeleteGarden", func() {
 				})
 			})
 
+			Context("when getting the current target fails", func() {
+				It("should fail", func() {
+					factory.EXPECT().Manager().Return(manager, nil)
+					manager.EXPECT().Configuration().Return(cfg)
+					manager.EXPECT().CurrentTarget().Return(nil, errors.New("error"))
+					Expect(options.Complete(factory, nil, nil)).To(MatchError("failed to get current target: error"))
+				})
+			})
+
 			Context("when getting configuration succeeds", func() {
 				It("should succeed", func() {
 					factory.EXPECT().Manager().Return(manager, nil)
 					manager.EXPECT().Configuration().Return(cfg)
+					manager.EXPECT().CurrentTarget().Return(target.NewTarget(gardenIdentity2, "", "", ""), nil)
 					Expect(options.Complete(factory, nil, []string{" garden "})).To(Succeed())
 					Expect(options.Configuration).To(BeIdenticalTo(cfg))
 					Expect(options.Name).To(Equal("garden"))
+					Expect(options.TargetedGarden).To(Equal(gardenIdentity2))
 				})
 			})
 		})
@@ -79,6 +94,7 @@ var _ = Describe("Config Subcommand DeleteGarden", func() {
 
 			It("should delete garden from configuration", func() {
 				options.Name = gardenIdentity1
+				options.Force = true
 				Expect(options.Run(nil)).To(Succeed())
 
 				assertGardenNames(cfg, gardenIdentity2)
@@ -86,6 +102,55 @@ var _ = Describe("Config Subcommand DeleteGarden", func() {
 				Expect(out.String()).To(MatchRegexp("^Successfully deleted garden"))
 			})
 
+			It("should delete garden by alias", func() {
+				cfg.Gardens[0].Aliases = []string{"foo"}
+				options.Name = "foo"
+				options.Force = true
+				Expect(options.Run(nil)).To(Succeed())
+
+				assertGardenNames(cfg, gardenIdentity2)
+				Expect(out.String()).To(Equal(fmt.Sprintf("Successfully deleted garden %q\n", gardenIdentity1)))
+			})
+
+			It("should unset the default garden", func() {
+				cfg.Gardens[0].Aliases = []string{"foo"}
+				cfg.DefaultGarden = "foo"
+				options.Name = gardenIdentity1
+				options.Force = true
+				Expect(options.Run(nil)).To(Succeed())
+
+				Expect(cfg.DefaultGarden).To(BeEmpty())
+				assertConfigHasBeenSaved(cfg)
+			})
+
+			It("should delete garden after confirmation", func() {
+				options.Name = gardenIdentity1
+				_, err := in.Write([]byte("y\n"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(options.Run(nil)).To(Succeed())
+
+				assertGardenNames(cfg, gardenIdentity2)
+				Expect(out.String()).To(ContainSubstring("[y/N]: Successfully deleted garden"))
+			})
+
+			It("should not delete garden when confirmation is denied", func() {
+				options.Name = gardenIdentity1
+				_, err := in.Write([]byte("n\n"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(options.Run(nil)).To(Succeed())
+
+				assertGardenNames(cfg, gardenIdentity1, gardenIdentity2)
+				Expect(out.String()).To(HaveSuffix("Aborted\n"))
+			})
+
+			It("should fail when the garden is currently targeted", func() {
+				options.Name = gardenIdentity1
+				options.TargetedGarden = gardenIdentity1
+				options.Force = true
+				Expect(options.Run(nil)).To(MatchError(MatchRegexp(`^garden ".*" is currently targeted`)))
+				assertGardenNames(cfg, gardenIdentity1, gardenIdentity2)
+			})
+
 			It("should fail when the garden does not exist", func() {
 				options.Name = gardenIdentity3
 				Expect(options.Run(nil)).To(MatchError(MatchRegexp(`^garden ".*" is not defined`)))
@@ -93,6 +158,7 @@ var _ = Describe("Config Subcommand DeleteGarden", func() {
 
 			It("should fail when the filename is invalid", func() {
 				options.Name = gardenIdentity1
+				options.Force = true
 				options.Configuration.Filename = string([]byte{0})
 				Expect(options.Run(nil)).To(MatchError(MatchRegexp("^failed to delete garden")))
 			})
@@ -0,0 +1,393 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1beta1 "k8s.io/apimachinery/pkg/apis/meta/v1beta1"
+	"k8s.io/cli-runtime/pkg/printers"
+
+	"github.com/gardener/gardenctl-v2/internal/gardenclient"
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
+	"github.com/gardener/gardenctl-v2/pkg/config"
+	"github.com/gardener/gardenctl-v2/pkg/target"
+)
+
+const (
+	clusterIdentityNamespace = "kube-system"
+	clusterIdentityName      = "cluster-identity"
+)
+
+// NewCmdConfigDoctor returns a new (config) doctor command.
+func NewCmdConfigDoctor(f util.Factory, ioStreams util.IOStreams) *cobra.Command {
+	o := &doctorOptions{
+		Options: base.Options{
+			IOStreams: ioStreams,
+		},
+		Timeout: 10 * time.Second,
+	}
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the configured gardens for stale entries",
+		Long: `Check the configured gardens for stale entries and print suggestions how to fix them.
+Each garden is probed for expired client certificates in its kubeconfig, for reachability of the garden cluster and
+for an identity that does not match the cluster-identity ConfigMap in the kube-system namespace of the garden cluster.
+With the --fix flag, mismatching identities are corrected and unreachable gardens are deleted from the gardenctl configuration
+after confirmation. The previous identity of a renamed garden is kept as alias. The currently targeted garden is never deleted.
+The command fails if at least one problem with severity error remains.`,
+		Example: `# check all gardens
+gardenctl config doctor
+
+# check all gardens and fix the problems after confirmation
+gardenctl config doctor --fix`,
+		RunE: base.WrapRunE(o, f),
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// problem is an issue of a configured garden found by the doctor command
+type problem struct {
+	// Garden is the identity of the garden the problem relates to
+	Garden string `yaml:"garden" json:"garden"`
+	// Severity is the severity of the problem
+	Severity config.Severity `yaml:"severity" json:"severity"`
+	// Message describes the problem
+	Message string `yaml:"message" json:"message"`
+	// Suggestion describes how to fix the problem
+	Suggestion string `yaml:"suggestion" json:"suggestion"`
+	// Fix describes the change that is applied by the --fix flag, the problem is not fixed automatically if empty
+	Fix string `yaml:"fix,omitempty" json:"fix,omitempty"`
+	// apply fixes the problem in the given configuration
+	apply func(cfg *config.Config) error
+}
+
+type doctorOptions struct {
+	base.Options
+	// Manager is the target manager used to create the garden clients
+	Manager target.Manager
+	// Configuration is the gardenctl configuration
+	Configuration *config.Config
+	// TargetedGarden is the name of the currently targeted Garden, it is only set if Fix is true
+	TargetedGarden string
+	// Fix corrects identities and deletes unreachable gardens after confirmation
+	Fix bool
+	// Timeout is the maximum duration to wait for a garden cluster to respond
+	Timeout time.Duration
+}
+
+// Complete adapts from the command line args to the data required.
+func (o *doctorOptions) Complete(f util.Factory, cmd *cobra.Command, args []string) error {
+	manager, err := f.Manager()
+	if err != nil {
+		return fmt.Errorf("failed to get target manager: %w", err)
+	}
+
+	o.Manager = manager
+
+	o.Configuration = manager.Configuration()
+	if o.Configuration == nil {
+		return errors.New("failed to get configuration")
+	}
+
+	if o.Fix {
+		currentTarget, err := manager.CurrentTarget()
+		if err != nil {
+			return fmt.Errorf("failed to get current target: %w", err)
+		}
+
+		o.TargetedGarden = currentTarget.GardenName()
+	}
+
+	return nil
+}
+
+// Validate validates the provided options
+func (o *doctorOptions) Validate() error {
+	if o.Timeout <= 0 {
+		return errors.New("--timeout must be a positive duration")
+	}
+
+	return o.Options.Validate()
+}
+
+// AddFlags adds flags to adjust the output to a cobra command
+func (o *doctorOptions) AddFlags(flags *pflag.FlagSet) {
+	o.Options.AddFlags(flags)
+	flags.BoolVar(&o.Fix, "fix", false, "correct mismatching identities and delete unreachable gardens after confirmation")
+	flags.DurationVar(&o.Timeout, "timeout", o.Timeout, "maximum duration to wait for a garden cluster to respond")
+}
+
+// Run executes the command
+func (o *doctorOptions) Run(f util.Factory) error {
+	now := f.Clock().Now()
+	problems := []problem{}
+
+	for _, g := range o.Configuration.Gardens {
+		problems = append(problems, o.examine(f.Context(), now, g)...)
+	}
+
+	if o.Output == "" {
+		if err := o.printTable(problems); err != nil {
+			return err
+		}
+	} else if err := o.PrintObject(problems); err != nil {
+		return err
+	}
+
+	fixed := false
+
+	if o.Fix {
+		var err error
+		if fixed, err = o.applyFixes(problems); err != nil {
+			return err
+		}
+	}
+
+	for _, p := range problems {
+		if p.Severity == config.SeverityError && !(fixed && p.apply != nil) {
+			return errors.New("the gardenctl configuration contains stale gardens")
+		}
+	}
+
+	return nil
+}
+
+// examine probes a single garden and returns the problems that have been found
+func (o *doctorOptions) examine(ctx context.Context, now time.Time, g config.Garden) []problem {
+	name := g.Name
+	problems := []problem{}
+
+	expiry, err := clientCertificateExpiry(o.Configuration, name)
+	if err != nil {
+		return append(problems, problem{
+			Garden:     name,
+			Severity:   config.SeverityError,
+			Message:    fmt.Sprintf("kubeconfig cannot be loaded: %v", err),
+			Suggestion: fmt.Sprintf("configure a valid kubeconfig with \"gardenctl config set-garden %s --kubeconfig PATH\"", name),
+		})
+	}
+
+	if expiry != nil && expiry.Before(now) {
+		problems = append(problems, problem{
+			Garden:     name,
+			Severity:   config.SeverityError,
+			Message:    fmt.Sprintf("client certificate expired at %s", expiry.UTC().Format(time.RFC3339)),
+			Suggestion: "renew the client certificate in the kubeconfig of the garden",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, o.Timeout)
+	defer cancel()
+
+	identity, err := o.clusterIdentity(ctx, name)
+	if err == nil {
+		if identity != "" && identity != name {
+			problems = append(problems, problem{
+				Garden:     name,
+				Severity:   config.SeverityError,
+				Message:    fmt.Sprintf("garden identity does not match the cluster identity %q", identity),
+				Suggestion: fmt.Sprintf("rename the garden with \"gardenctl config rename-garden %s %s\"", name, identity),
+				Fix:        fmt.Sprintf("rename garden %q to %q", name, identity),
+				apply: func(cfg *config.Config) error {
+					return cfg.RenameGarden(name, identity, true)
+				},
+			})
+		}
+
+		return problems
+	}
+
+	if apierrors.IsNotFound(err) {
+		return append(problems, problem{
+			Garden:     name,
+			Severity:   config.SeverityWarning,
+			Message:    fmt.Sprintf("ConfigMap %s/%s not found", clusterIdentityNamespace, clusterIdentityName),
+			Suggestion: "verify that the kubeconfig points to a garden cluster",
+		})
+	}
+
+	p := problem{
+		Garden:     name,
+		Severity:   config.SeverityError,
+		Message:    fmt.Sprintf("garden is not reachable: %v", err),
+		Suggestion: fmt.Sprintf("check the network connection and the kubeconfig or delete the garden with \"gardenctl config delete-garden %s\"", name),
+	}
+
+	if name != o.TargetedGarden {
+		p.Fix = fmt.Sprintf("delete garden %q", name)
+		p.apply = func(cfg *config.Config) error {
+			_, err := cfg.DeleteGarden(name)
+			return err
+		}
+	}
+
+	return append(problems, p)
+}
+
+// clusterIdentity reads the identity of the garden cluster from the cluster-identity ConfigMap
+func (o *doctorOptions) clusterIdentity(ctx context.Context, name string) (string, error) {
+	client, err := o.Manager.GardenClient(name)
+	if err != nil {
+		return "", err
+	}
+
+	return getClusterIdentity(ctx, client)
+}
+
+// getClusterIdentity reads the cluster identity from the cluster-identity ConfigMap in the kube-system namespace
+func getClusterIdentity(ctx context.Context, client gardenclient.Client) (string, error) {
+	cm, err := client.GetConfigMap(ctx, clusterIdentityNamespace, clusterIdentityName)
+	if err != nil {
+		return "", err
+	}
+
+	return cm.Data[clusterIdentityName], nil
+}
+
+// applyFixes asks for confirmation, applies all fixable problems and saves the configuration.
+// It returns true if the problems have been fixed.
+func (o *doctorOptions) applyFixes(problems []problem) (bool, error) {
+	fixable := []problem{}
+
+	for _, p := range problems {
+		if p.apply != nil {
+			fixable = append(fixable, p)
+		}
+	}
+
+	if len(fixable) == 0 {
+		fmt.Fprintln(o.IOStreams.Out, "There are no problems that can be fixed automatically")
+		return false, nil
+	}
+
+	fmt.Fprintln(o.IOStreams.Out, "The following changes will be applied:")
+
+	for _, p := range fixable {
+		fmt.Fprintf(o.IOStreams.Out, "  - %s\n", p.Fix)
+	}
+
+	confirmed, err := util.Confirm(o.IOStreams, "Do you want to apply these changes to the gardenctl configuration?")
+	if err != nil {
+		return false, err
+	}
+
+	if !confirmed {
+		fmt.Fprintln(o.IOStreams.Out, "Aborted")
+		return false, nil
+	}
+
+	for _, p := range fixable {
+		if err := p.apply(o.Configuration); err != nil {
+			return false, fmt.Errorf("failed to %s: %w", p.Fix, err)
+		}
+	}
+
+	if err := o.Configuration.Save(); err != nil {
+		return false, fmt.Errorf("failed to save configuration: %w", err)
+	}
+
+	// in dry-run mode the changes have only been printed
+	if o.Configuration.IsDryRun() {
+		return true, nil
+	}
+
+	fmt.Fprintf(o.IOStreams.Out, "Successfully applied %d changes\n", len(fixable))
+
+	return true, nil
+}
+
+func (o *doctorOptions) printTable(problems []problem) error {
+	if len(problems) == 0 {
+		fmt.Fprintf(o.IOStreams.Out, "All %d gardens are healthy\n", len(o.Configuration.Gardens))
+		return nil
+	}
+
+	table := &metav1beta1.Table{
+		ColumnDefinitions: []metav1.TableColumnDefinition{
+			{Name: "Garden", Type: "string"},
+			{Name: "Severity", Type: "string"},
+			{Name: "Problem", Type: "string"},
+			{Name: "Suggestion", Type: "string"},
+		},
+		Rows: []metav1.TableRow{},
+	}
+
+	for _, p := range problems {
+		table.Rows = append(table.Rows, metav1.TableRow{
+			Cells: []interface{}{p.Garden, p.Severity, p.Message, p.Suggestion},
+		})
+	}
+
+	printer := printers.NewTablePrinter(printers.PrintOptions{})
+	if err := printer.PrintObj(table, o.IOStreams.Out); err != nil {
+		return fmt.Errorf("failed to output doctor result: %w", err)
+	}
+
+	return nil
+}
+
+// clientCertificateExpiry returns the expiry date of the client certificate that is used for the
+// current context of the garden kubeconfig. It returns nil if no client certificate is used.
+func clientCertificateExpiry(cfg *config.Config, name string) (*time.Time, error) {
+	clientConfig, err := cfg.DirectClientConfig(name)
+	if err != nil {
+		return nil, err
+	}
+
+	rawConfig, err := clientConfig.RawConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	context, ok := rawConfig.Contexts[rawConfig.CurrentContext]
+	if !ok {
+		return nil, nil
+	}
+
+	authInfo, ok := rawConfig.AuthInfos[context.AuthInfo]
+	if !ok {
+		return nil, nil
+	}
+
+	data := authInfo.ClientCertificateData
+	if len(data) == 0 && authInfo.ClientCertificate != "" {
+		if data, err = os.ReadFile(authInfo.ClientCertificate); err != nil {
+			return nil, fmt.Errorf("failed to read client certificate: %w", err)
+		}
+	}
+
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("client certificate is not PEM encoded")
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse client certificate: %w", err)
+	}
+
+	return &cert.NotAfter, nil
+}
@@ -0,0 +1,202 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+
+	gardenclientmocks "github.com/gardener/gardenctl-v2/internal/gardenclient/mocks"
+	"github.com/gardener/gardenctl-v2/internal/util"
+	cmdconfig "github.com/gardener/gardenctl-v2/pkg/cmd/config"
+	"github.com/gardener/gardenctl-v2/pkg/target"
+)
+
+var _ = Describe("Config Subcommand Doctor", func() {
+	Describe("Instance", func() {
+		var cmd *cobra.Command
+
+		BeforeEach(func() {
+			cmd = cmdconfig.NewCmdConfigDoctor(factory, streams)
+		})
+
+		It("should have Use and Flags", func() {
+			Expect(cmd.Use).To(Equal("doctor"))
+			assertAllFlagNames(cmd.Flags(), "fix", "output", "timeout")
+		})
+	})
+
+	Describe("Options", func() {
+		var options *cmdconfig.DoctorOptions
+
+		BeforeEach(func() {
+			options = cmdconfig.NewDoctorOptions()
+			options.IOStreams = streams
+		})
+
+		Describe("Complete", func() {
+			It("should fail when getting configuration fails", func() {
+				factory.EXPECT().Manager().Return(manager, nil)
+				manager.EXPECT().Configuration().Return(nil)
+				Expect(options.Complete(factory, nil, nil)).To(MatchError("failed to get configuration"))
+			})
+
+			It("should get the targeted garden only if problems are fixed", func() {
+				factory.EXPECT().Manager().Return(manager, nil).Times(2)
+				manager.EXPECT().Configuration().Return(cfg).Times(2)
+				Expect(options.Complete(factory, nil, nil)).To(Succeed())
+				Expect(options.Configuration).To(BeIdenticalTo(cfg))
+				Expect(options.TargetedGarden).To(BeEmpty())
+
+				options.Fix = true
+				manager.EXPECT().CurrentTarget().Return(target.NewTarget(gardenIdentity1, "", "", ""), nil)
+				Expect(options.Complete(factory, nil, nil)).To(Succeed())
+				Expect(options.TargetedGarden).To(Equal(gardenIdentity1))
+			})
+		})
+
+		Describe("Validate", func() {
+			It("should fail for a non-positive timeout", func() {
+				options.Timeout = 0
+				Expect(options.Validate()).To(MatchError("--timeout must be a positive duration"))
+			})
+		})
+
+		Describe("Run", func() {
+			var (
+				client1 *gardenclientmocks.MockClient
+				client2 *gardenclientmocks.MockClient
+			)
+
+			BeforeEach(func() {
+				kubeconfigFile := filepath.Join(gardenHomeDir, "doctor-kubeconfig.yaml")
+				writeKubeconfig(kubeconfigFile, time.Now().Add(-time.Hour))
+				cfg.Gardens[0].Kubeconfig = kubeconfigFile
+				cfg.Gardens[1].Kubeconfig = kubeconfigFile
+
+				client1 = gardenclientmocks.NewMockClient(ctrl)
+				client2 = gardenclientmocks.NewMockClient(ctrl)
+
+				options.Manager = manager
+				options.Configuration = cfg
+
+				factory.EXPECT().Context().Return(context.Background()).AnyTimes()
+				factory.EXPECT().Clock().Return(&util.RealClock{})
+				manager.EXPECT().GardenClient(gardenIdentity1).Return(client1, nil)
+				manager.EXPECT().GardenClient(gardenIdentity2).Return(client2, nil)
+			})
+
+			It("should report that all gardens are healthy", func() {
+				cfg.Gardens[0].Context = "token"
+				client1.EXPECT().GetConfigMap(gomock.Any(), "kube-system", "cluster-identity").Return(clusterIdentityConfigMap(gardenIdentity1), nil)
+				client2.EXPECT().GetConfigMap(gomock.Any(), "kube-system", "cluster-identity").Return(clusterIdentityConfigMap(gardenIdentity2), nil)
+				Expect(options.Run(factory)).To(Succeed())
+				Expect(out.String()).To(Equal("All 2 gardens are healthy\n"))
+			})
+
+			It("should report expired certificates, identity mismatches and unreachable gardens", func() {
+				client1.EXPECT().GetConfigMap(gomock.Any(), "kube-system", "cluster-identity").Return(clusterIdentityConfigMap("landscape-dev"), nil)
+				client2.EXPECT().GetConfigMap(gomock.Any(), "kube-system", "cluster-identity").Return(nil, errors.New("connection refused"))
+				Expect(options.Run(factory)).To(MatchError("the gardenctl configuration contains stale gardens"))
+				Expect(out.String()).To(MatchRegexp(`fooGarden\s+error\s+client certificate expired at .+\s+renew the client certificate`))
+				Expect(out.String()).To(MatchRegexp(`fooGarden\s+error\s+garden identity does not match the cluster identity "landscape-dev"\s+rename the garden with "gardenctl config rename-garden fooGarden landscape-dev"`))
+				Expect(out.String()).To(MatchRegexp(`barGarden\s+error\s+garden is not reachable: connection refused\s+check the network connection`))
+			})
+
+			It("should warn if the cluster-identity ConfigMap does not exist", func() {
+				cfg.Gardens[0].Context = "token"
+				options.Output = "json"
+				notFound := apierrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, "cluster-identity")
+				client1.EXPECT().GetConfigMap(gomock.Any(), "kube-system", "cluster-identity").Return(nil, fmt.Errorf("failed to get configmap: %w", notFound))
+				client2.EXPECT().GetConfigMap(gomock.Any(), "kube-system", "cluster-identity").Return(clusterIdentityConfigMap(gardenIdentity2), nil)
+				Expect(options.Run(factory)).To(Succeed())
+				Expect(out.String()).To(ContainSubstring(`"severity": "warning"`))
+				Expect(out.String()).To(ContainSubstring(`"message": "ConfigMap kube-system/cluster-identity not found"`))
+			})
+
+			It("should fix identities and delete unreachable gardens after confirmation", func() {
+				cfg.Gardens[0].Context = "token"
+				options.Fix = true
+				in.Write([]byte("y\n"))
+				client1.EXPECT().GetConfigMap(gomock.Any(), "kube-system", "cluster-identity").Return(clusterIdentityConfigMap("landscape-dev"), nil)
+				client2.EXPECT().GetConfigMap(gomock.Any(), "kube-system", "cluster-identity").Return(nil, errors.New("connection refused"))
+				Expect(options.Run(factory)).To(Succeed())
+				Expect(out.String()).To(ContainSubstring(`  - rename garden "fooGarden" to "landscape-dev"` + "\n" + `  - delete garden "barGarden"` + "\n"))
+				Expect(out.String()).To(HaveSuffix("Successfully applied 2 changes\n"))
+				assertGardenNames(cfg, "landscape-dev")
+				Expect(cfg.Gardens[0].Aliases).To(Equal([]string{gardenIdentity1}))
+				assertConfigHasBeenSaved(cfg)
+			})
+
+			It("should not fix anything without confirmation and never delete the targeted garden", func() {
+				cfg.Gardens[0].Context = "token"
+				options.Fix = true
+				options.TargetedGarden = gardenIdentity2
+				in.Write([]byte("n\n"))
+				client1.EXPECT().GetConfigMap(gomock.Any(), "kube-system", "cluster-identity").Return(clusterIdentityConfigMap("landscape-dev"), nil)
+				client2.EXPECT().GetConfigMap(gomock.Any(), "kube-system", "cluster-identity").Return(nil, errors.New("connection refused"))
+				Expect(options.Run(factory)).To(MatchError("the gardenctl configuration contains stale gardens"))
+				Expect(out.String()).NotTo(ContainSubstring(`delete garden`))
+				Expect(out.String()).To(HaveSuffix("Aborted\n"))
+				assertGardenNames(cfg, gardenIdentity1, gardenIdentity2)
+			})
+		})
+	})
+})
+
+func clusterIdentityConfigMap(identity string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{Data: map[string]string{"cluster-identity": identity}}
+}
+
+// writeKubeconfig writes a kubeconfig with the context my-context that uses a client certificate expiring
+// at notAfter and the context token that uses a token
+func writeKubeconfig(filename string, notAfter time.Time) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).NotTo(HaveOccurred())
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "user"},
+		NotBefore:    notAfter.Add(-24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	Expect(err).NotTo(HaveOccurred())
+
+	kubeconfig := clientcmdapi.NewConfig()
+	kubeconfig.Clusters["garden"] = &clientcmdapi.Cluster{Server: "https://api.garden.example.com"}
+	kubeconfig.AuthInfos["cert"] = &clientcmdapi.AuthInfo{
+		ClientCertificateData: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		ClientKeyData:         []byte("key"),
+	}
+	kubeconfig.AuthInfos["token"] = &clientcmdapi.AuthInfo{Token: "token"}
+	kubeconfig.Contexts[gardenContext1] = &clientcmdapi.Context{Cluster: "garden", AuthInfo: "cert"}
+	kubeconfig.Contexts["token"] = &clientcmdapi.Context{Cluster: "garden", AuthInfo: "token"}
+	kubeconfig.CurrentContext = "token"
+
+	Expect(clientcmd.WriteToFile(*kubeconfig, filename)).To(Succeed())
+}
@@ -7,12 +7,15 @@ SPDX-License-Identifier: Apache-2.0
 package config
 
 import (
+	"time"
+
 	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
 )
 
 var (
-	ValidGardenArgsFunctionWrapper = validGardenArgsFunctionWrapper
-	ValidatePatterns               = validatePatterns
+	ValidGardenArgsFunctionWrapper      = validGardenArgsFunctionWrapper
+	ValidGardenAliasArgsFunctionWrapper = validGardenAliasArgsFunctionWrapper
+	ValidatePatterns                    = validatePatterns
 )
 
 type CobraValidArgsFunction cobraValidArgsFunction
@@ -52,3 +55,151 @@ func NewDeleteGardenOptions() *DeleteGardenOptions {
 		},
 	}
 }
+
+type ValidateOptions struct {
+	validateOptions
+}
+
+func NewValidateOptions() *ValidateOptions {
+	return &ValidateOptions{
+		validateOptions: validateOptions{
+			Options: base.Options{},
+		},
+	}
+}
+
+type RenameGardenOptions struct {
+	renameGardenOptions
+}
+
+func NewRenameGardenOptions() *RenameGardenOptions {
+	return &RenameGardenOptions{
+		renameGardenOptions: renameGardenOptions{
+			Options: base.Options{},
+		},
+	}
+}
+
+type GetOptions struct {
+	getOptions
+}
+
+func NewGetOptions() *GetOptions {
+	return &GetOptions{
+		getOptions: getOptions{
+			Options: base.Options{},
+		},
+	}
+}
+
+type SetOptions struct {
+	setOptions
+}
+
+func NewSetOptions() *SetOptions {
+	return &SetOptions{
+		setOptions: setOptions{
+			Options: base.Options{},
+		},
+	}
+}
+
+type SetDefaultGardenOptions struct {
+	setDefaultGardenOptions
+}
+
+func NewSetDefaultGardenOptions() *SetDefaultGardenOptions {
+	return &SetDefaultGardenOptions{
+		setDefaultGardenOptions: setDefaultGardenOptions{
+			Options: base.Options{},
+		},
+	}
+}
+
+type SyncOptions struct {
+	syncOptions
+}
+
+func NewSyncOptions() *SyncOptions {
+	return &SyncOptions{
+		syncOptions: syncOptions{
+			Options: base.Options{},
+		},
+	}
+}
+
+type DoctorOptions struct {
+	doctorOptions
+}
+
+func NewDoctorOptions() *DoctorOptions {
+	return &DoctorOptions{
+		doctorOptions: doctorOptions{
+			Options: base.Options{},
+			Timeout: time.Second,
+		},
+	}
+}
+
+type TestPatternOptions struct {
+	testPatternOptions
+}
+
+func NewTestPatternOptions() *TestPatternOptions {
+	return &TestPatternOptions{
+		testPatternOptions: testPatternOptions{
+			Options: base.Options{},
+		},
+	}
+}
+
+type AliasOptions struct {
+	aliasOptions
+}
+
+func NewAliasOptions(remove bool) *AliasOptions {
+	return &AliasOptions{
+		aliasOptions: aliasOptions{
+			Options: base.Options{},
+			Remove:  remove,
+		},
+	}
+}
+
+type AddGardenOptions struct {
+	addGardenOptions
+}
+
+func NewAddGardenOptions() *AddGardenOptions {
+	return &AddGardenOptions{
+		addGardenOptions: addGardenOptions{
+			Options: base.Options{},
+			Timeout: time.Second,
+		},
+	}
+}
+
+type CheckConnectionOptions struct {
+	checkConnectionOptions
+}
+
+func NewCheckConnectionOptions() *CheckConnectionOptions {
+	return &CheckConnectionOptions{
+		checkConnectionOptions: checkConnectionOptions{
+			Options: base.Options{},
+			Timeout: time.Second,
+		},
+	}
+}
+
+type MigrateDirectoriesOptions struct {
+	migrateDirectoriesOptions
+}
+
+func NewMigrateDirectoriesOptions() *MigrateDirectoriesOptions {
+	return &MigrateDirectoriesOptions{
+		migrateDirectoriesOptions: migrateDirectoriesOptions{
+			Options: base.Options{},
+		},
+	}
+}
@@ -0,0 +1,110 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
+	"github.com/gardener/gardenctl-v2/pkg/config"
+)
+
+// NewCmdConfigGet returns a new (config) get command.
+func NewCmdConfigGet(f util.Factory, ioStreams util.IOStreams) *cobra.Command {
+	o := &getOptions{
+		Options: base.Options{
+			IOStreams: ioStreams,
+		},
+	}
+	cmd := &cobra.Command{
+		Use:   "get PATH",
+		Short: "Print a single value of the gardenctl configuration",
+		Long: `Print a single value of the gardenctl configuration.
+The value is addressed by a path of field names separated by dots, e.g. "gardens[my-garden].context".
+Gardens are selected by identity or alias, all other lists by index. Lists and objects are printed as yaml unless another output format is requested.`,
+		Example: `# print the context of garden my-garden
+gardenctl config get "gardens[my-garden].context"
+
+# print the first pattern of garden my-garden
+gardenctl config get "gardens[my-garden].patterns[0]"
+
+# print the global match patterns as json
+gardenctl config get matchPatterns -o json`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: cobra.NoFileCompletions,
+		RunE:              base.WrapRunE(o, f),
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+type getOptions struct {
+	base.Options
+	// Configuration is the gardenctl configuration
+	Configuration *config.Config
+	// Path addresses the configuration value
+	Path string
+}
+
+// Complete adapts from the command line args to the data required.
+func (o *getOptions) Complete(f util.Factory, cmd *cobra.Command, args []string) error {
+	config, err := getConfiguration(f)
+	if err != nil {
+		return err
+	}
+
+	o.Configuration = config
+
+	if len(args) > 0 {
+		o.Path = strings.TrimSpace(args[0])
+	}
+
+	return nil
+}
+
+// Validate validates the provided options
+func (o *getOptions) Validate() error {
+	if o.Path == "" {
+		return errors.New("path is required")
+	}
+
+	return o.Options.Validate()
+}
+
+// Run executes the command
+func (o *getOptions) Run(_ util.Factory) error {
+	value, err := o.Configuration.Get(o.Path)
+	if err != nil {
+		return err
+	}
+
+	if o.Output == "" {
+		if value == nil {
+			fmt.Fprintln(o.IOStreams.Out)
+			return nil
+		}
+
+		v := reflect.Indirect(reflect.ValueOf(value))
+		switch v.Kind() {
+		case reflect.String, reflect.Bool, reflect.Int:
+			fmt.Fprintln(o.IOStreams.Out, v.Interface())
+			return nil
+		}
+
+		o.Output = "yaml"
+	}
+
+	return o.PrintObject(value)
+}
@@ -0,0 +1,101 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	cmdconfig "github.com/gardener/gardenctl-v2/pkg/cmd/config"
+)
+
+var _ = Describe("Config Subcommand Get", func() {
+	Describe("Instance", func() {
+		var cmd *cobra.Command
+
+		BeforeEach(func() {
+			cmd = cmdconfig.NewCmdConfigGet(factory, streams)
+		})
+
+		It("should have Use and Flags", func() {
+			Expect(cmd.Use).To(Equal("get PATH"))
+			assertAllFlagNames(cmd.Flags(), "output")
+		})
+	})
+
+	Describe("Options", func() {
+		var options *cmdconfig.GetOptions
+
+		BeforeEach(func() {
+			options = cmdconfig.NewGetOptions()
+			options.IOStreams = streams
+		})
+
+		Describe("Complete", func() {
+			It("should fail when getting configuration fails", func() {
+				factory.EXPECT().Manager().Return(manager, nil)
+				manager.EXPECT().Configuration().Return(nil)
+				Expect(options.Complete(factory, nil, nil)).To(MatchError("failed to get configuration"))
+			})
+
+			It("should succeed", func() {
+				factory.EXPECT().Manager().Return(manager, nil)
+				manager.EXPECT().Configuration().Return(cfg)
+				Expect(options.Complete(factory, nil, []string{" gardens[fooGarden].context "})).To(Succeed())
+				Expect(options.Configuration).To(BeIdenticalTo(cfg))
+				Expect(options.Path).To(Equal("gardens[fooGarden].context"))
+			})
+		})
+
+		Describe("Validate", func() {
+			It("should fail without a path", func() {
+				Expect(options.Validate()).To(MatchError("path is required"))
+			})
+
+			It("should fail with an invalid output format", func() {
+				options.Path = "gardens"
+				options.Output = "csv"
+				Expect(options.Validate()).NotTo(Succeed())
+			})
+		})
+
+		Describe("Run", func() {
+			BeforeEach(func() {
+				options.Configuration = cfg
+			})
+
+			It("should print a single value", func() {
+				options.Path = "gardens[" + gardenIdentity1 + "].context"
+				Expect(options.Run(nil)).To(Succeed())
+				Expect(out.String()).To(Equal(gardenContext1 + "\n"))
+			})
+
+			It("should print a list as yaml", func() {
+				options.Path = "gardens[" + gardenIdentity2 + "].patterns"
+				Expect(options.Run(nil)).To(Succeed())
+
+				var list []string
+				Expect(yaml.Unmarshal([]byte(out.String()), &list)).To(Succeed())
+				Expect(list).To(Equal(patterns))
+			})
+
+			It("should print a single value as json", func() {
+				options.Path = "linkKubeconfig"
+				options.Output = "json"
+				Expect(options.Run(nil)).To(Succeed())
+				Expect(out.String()).To(Equal("false\n"))
+			})
+
+			It("should fail for an unknown garden", func() {
+				options.Path = "gardens[" + gardenIdentity3 + "].context"
+				Expect(options.Run(nil)).To(MatchError(ContainSubstring(`garden "bazGarden" is not defined`)))
+			})
+		})
+	})
+})
@@ -0,0 +1,167 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
+)
+
+// NewCmdConfigMigrateDirectories returns a new (config) migrate-directories command.
+func NewCmdConfigMigrateDirectories(f util.Factory, ioStreams util.IOStreams) *cobra.Command {
+	o := &migrateDirectoriesOptions{
+		Options: base.Options{
+			IOStreams: ioStreams,
+		},
+	}
+	cmd := &cobra.Command{
+		Use:   "migrate-directories",
+		Short: "Move the contents of ~/.garden to the XDG base directories",
+		Long: `Move the contents of ~/.garden to the XDG base directories, which gardenctl uses on Linux once ~/.garden does not exist anymore.
+The configuration file and the templates are moved to $XDG_CONFIG_HOME/gardenctl (default ~/.config/gardenctl),
+the cache is moved to $XDG_CACHE_HOME/gardenctl (default ~/.cache/gardenctl) and the cached tokens are moved
+to $XDG_STATE_HOME/gardenctl/tokens (default ~/.local/state/gardenctl/tokens). Nothing is moved if one of the files already exists.
+
+The directories can also be overridden with the GCTL_HOME, GCTL_CACHE_DIR, GCTL_TOKEN_DIR and GCTL_SESSIONS_DIR environment variables
+and with the directories.cache, directories.tokens and directories.sessions keys of the gardenctl configuration.`,
+		Example: `# move the contents of ~/.garden to the XDG base directories
+gardenctl config migrate-directories`,
+		Args: cobra.NoArgs,
+		RunE: base.WrapRunE(o, f),
+	}
+
+	return cmd
+}
+
+type migrateDirectoriesOptions struct {
+	base.Options
+	// GOOS is the operating system gardenctl is running on
+	GOOS string
+	// Source are the directories of the ~/.garden layout
+	Source util.Directories
+	// Target are the directories of the XDG base directory layout
+	Target util.Directories
+}
+
+// Complete adapts from the command line args to the data required.
+func (o *migrateDirectoriesOptions) Complete(_ util.Factory, _ *cobra.Command, _ []string) error {
+	var err error
+
+	o.GOOS = runtime.GOOS
+
+	if o.Source, err = util.LegacyDirectories(); err != nil {
+		return err
+	}
+
+	if o.Target, err = util.XDGDirectories(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Validate validates the provided options
+func (o *migrateDirectoriesOptions) Validate() error {
+	if o.GOOS != "linux" {
+		return fmt.Errorf("the XDG base directories are only used on Linux, %s is used on %s", o.Source.Home, o.GOOS)
+	}
+
+	return nil
+}
+
+// Run executes the command
+func (o *migrateDirectoriesOptions) Run(_ util.Factory) error {
+	if _, err := os.Stat(o.Source.Home); errors.Is(err, os.ErrNotExist) {
+		fmt.Fprintf(o.IOStreams.Out, "Nothing to migrate, %s does not exist\n", o.Source.Home)
+		return nil
+	}
+
+	moves, err := o.moves()
+	if err != nil {
+		return err
+	}
+
+	// check all destinations before anything is moved, so that a conflict does not leave the files scattered across both layouts
+	for _, m := range moves {
+		if _, err := os.Lstat(m.to); err == nil {
+			return fmt.Errorf("%s already exists, move or delete it before migrating %s", m.to, m.from)
+		}
+	}
+
+	for _, m := range moves {
+		if err := os.MkdirAll(filepath.Dir(m.to), 0700); err != nil {
+			return fmt.Errorf("failed to create directory: %w", err)
+		}
+
+		if err := os.Rename(m.from, m.to); err != nil {
+			return fmt.Errorf("failed to move %s to %s: %w", m.from, m.to, err)
+		}
+
+		fmt.Fprintf(o.IOStreams.Out, "Moved %s to %s\n", m.from, m.to)
+	}
+
+	for _, dir := range []string{o.Source.Cache, o.Source.Home} {
+		if err := os.Remove(dir); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove %s: %w", dir, err)
+		}
+	}
+
+	fmt.Fprintf(o.IOStreams.Out, "Successfully migrated %s to the XDG base directories\n", o.Source.Home)
+
+	if home, ok := os.LookupEnv(util.EnvGardenHomeDir); ok {
+		fmt.Fprintf(o.IOStreams.ErrOut, "The environment variable %s is set, gardenctl keeps using %s as home directory\n", util.EnvGardenHomeDir, home)
+	}
+
+	return nil
+}
+
+// move is a file or directory that is moved by the migrate-directories command
+type move struct {
+	from string
+	to   string
+}
+
+// moves returns the files and directories of the ~/.garden layout and their destinations in the XDG base directories.
+// The entries of the cache directory are moved individually, as the XDG cache directory may already contain other files.
+func (o *migrateDirectoriesOptions) moves() ([]move, error) {
+	entries, err := os.ReadDir(o.Source.Home)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", o.Source.Home, err)
+	}
+
+	var moves []move
+
+	for _, entry := range entries {
+		from := filepath.Join(o.Source.Home, entry.Name())
+
+		switch from {
+		case o.Source.Cache:
+			cacheEntries, err := os.ReadDir(from)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", from, err)
+			}
+
+			for _, cacheEntry := range cacheEntries {
+				moves = append(moves, move{from: filepath.Join(from, cacheEntry.Name()), to: filepath.Join(o.Target.Cache, cacheEntry.Name())})
+			}
+		case o.Source.Tokens:
+			moves = append(moves, move{from: from, to: o.Target.Tokens})
+		default:
+			moves = append(moves, move{from: from, to: filepath.Join(o.Target.Home, entry.Name())})
+		}
+	}
+
+	return moves, nil
+}
@@ -0,0 +1,104 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+
+	"github.com/gardener/gardenctl-v2/internal/util"
+	cmdconfig "github.com/gardener/gardenctl-v2/pkg/cmd/config"
+)
+
+var _ = Describe("Config Subcommand MigrateDirectories", func() {
+	Describe("Instance", func() {
+		var cmd *cobra.Command
+
+		BeforeEach(func() {
+			cmd = cmdconfig.NewCmdConfigMigrateDirectories(factory, streams)
+		})
+
+		It("should have Use and Flags", func() {
+			Expect(cmd.Use).To(Equal("migrate-directories"))
+			Expect(cmd.Flags().HasFlags()).To(BeFalse())
+		})
+	})
+
+	Describe("Options", func() {
+		var (
+			options *cmdconfig.MigrateDirectoriesOptions
+			dir     string
+		)
+
+		writeFile := func(path string) {
+			Expect(os.MkdirAll(filepath.Dir(path), 0700)).To(Succeed())
+			Expect(os.WriteFile(path, []byte(path), 0600)).To(Succeed())
+		}
+
+		BeforeEach(func() {
+			var err error
+			dir, err = os.MkdirTemp("", "gctlv2-migrate-*")
+			Expect(err).NotTo(HaveOccurred())
+
+			options = cmdconfig.NewMigrateDirectoriesOptions()
+			options.IOStreams = streams
+			options.GOOS = "linux"
+			options.Source = util.HomeDirectories(filepath.Join(dir, ".garden"))
+			options.Target = util.Directories{
+				Home:   filepath.Join(dir, ".config", "gardenctl"),
+				Cache:  filepath.Join(dir, ".cache", "gardenctl"),
+				Tokens: filepath.Join(dir, ".local", "state", "gardenctl", "tokens"),
+			}
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(dir)).To(Succeed())
+		})
+
+		It("should fail on other operating systems than Linux", func() {
+			options.GOOS = "darwin"
+			Expect(options.Validate()).To(MatchError(ContainSubstring("the XDG base directories are only used on Linux")))
+		})
+
+		It("should do nothing if ~/.garden does not exist", func() {
+			Expect(options.Run(factory)).To(Succeed())
+			Expect(out.String()).To(Equal("Nothing to migrate, " + options.Source.Home + " does not exist\n"))
+		})
+
+		It("should move the contents of ~/.garden to the XDG base directories", func() {
+			writeFile(filepath.Join(options.Source.Home, "gardenctl-v2.yaml"))
+			writeFile(filepath.Join(options.Source.Home, "templates", "aws.tmpl"))
+			writeFile(filepath.Join(options.Source.Cache, "completion-garden.json"))
+			writeFile(filepath.Join(options.Source.Tokens, "garden.json"))
+			writeFile(filepath.Join(options.Target.Cache, "other.json"))
+
+			Expect(options.Run(factory)).To(Succeed())
+
+			Expect(filepath.Join(options.Target.Home, "gardenctl-v2.yaml")).To(BeARegularFile())
+			Expect(filepath.Join(options.Target.Home, "templates", "aws.tmpl")).To(BeARegularFile())
+			Expect(filepath.Join(options.Target.Cache, "completion-garden.json")).To(BeARegularFile())
+			Expect(filepath.Join(options.Target.Cache, "other.json")).To(BeARegularFile())
+			Expect(filepath.Join(options.Target.Tokens, "garden.json")).To(BeARegularFile())
+			Expect(options.Source.Home).NotTo(BeADirectory())
+			Expect(out.String()).To(HaveSuffix("Successfully migrated " + options.Source.Home + " to the XDG base directories\n"))
+		})
+
+		It("should not move anything if a file already exists", func() {
+			writeFile(filepath.Join(options.Source.Home, "gardenctl-v2.yaml"))
+			writeFile(filepath.Join(options.Source.Cache, "completion-garden.json"))
+			writeFile(filepath.Join(options.Target.Home, "gardenctl-v2.yaml"))
+
+			Expect(options.Run(factory)).To(MatchError(ContainSubstring("gardenctl-v2.yaml already exists")))
+			Expect(filepath.Join(options.Source.Home, "gardenctl-v2.yaml")).To(BeARegularFile())
+			Expect(filepath.Join(options.Source.Cache, "completion-garden.json")).To(BeARegularFile())
+		})
+	})
+})
@@ -0,0 +1,181 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
+	"github.com/gardener/gardenctl-v2/pkg/config"
+	"github.com/gardener/gardenctl-v2/pkg/target"
+)
+
+// NewCmdConfigRenameGarden returns a new (config) rename-garden command.
+func NewCmdConfigRenameGarden(f util.Factory, ioStreams util.IOStreams) *cobra.Command {
+	o := &renameGardenOptions{
+		Options: base.Options{
+			IOStreams: ioStreams,
+		},
+	}
+	cmd := &cobra.Command{
+		Use:   "rename-garden",
+		Short: "Rename the specified Garden in the gardenctl configuration",
+		Long: `Rename the specified Garden in the gardenctl configuration.
+The Garden can be specified by its identity or by one of its aliases. If the --keep-alias flag is set, the previous identity is kept as an alias.
+Targets and target histories of all gardenctl sessions that refer to the previous identity are updated.`,
+		Example: `# rename my-garden to landscape-dev
+gardenctl config rename-garden my-garden landscape-dev
+
+# rename my-garden to landscape-dev and keep my-garden as alias
+gardenctl config rename-garden my-garden landscape-dev --keep-alias`,
+		ValidArgsFunction: validGardenArgsFunctionWrapper(f, ioStreams),
+		RunE:              base.WrapRunE(o, f),
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+type renameGardenOptions struct {
+	base.Options
+	// Configuration is the gardenctl configuration
+	Configuration *config.Config
+	// SessionDir is the session directory of the current gardenctl session
+	SessionDir string
+	// Name is the current identity or an alias of the Garden
+	Name string
+	// NewName is the new identity of the Garden
+	NewName string
+	// KeepAlias adds the previous identity to the aliases of the Garden
+	KeepAlias bool
+}
+
+// Complete adapts from the command line args to the data required.
+func (o *renameGardenOptions) Complete(f util.Factory, cmd *cobra.Command, args []string) error {
+	manager, err := f.Manager()
+	if err != nil {
+		return fmt.Errorf("failed to get target manager: %w", err)
+	}
+
+	o.Configuration = manager.Configuration()
+	if o.Configuration == nil {
+		return errors.New("failed to get configuration")
+	}
+
+	o.SessionDir = manager.SessionDir()
+
+	if len(args) > 0 {
+		o.Name = strings.TrimSpace(args[0])
+	}
+
+	if len(args) > 1 {
+		o.NewName = strings.TrimSpace(args[1])
+	}
+
+	return nil
+}
+
+// Validate validates the provided options
+func (o *renameGardenOptions) Validate() error {
+	if o.Name == "" {
+		return errors.New("garden identity is required")
+	}
+
+	if o.NewName == "" {
+		return errors.New("new garden identity is required")
+	}
+
+	if o.Name == o.NewName {
+		return errors.New("new garden identity must differ from the current one")
+	}
+
+	return nil
+}
+
+// AddFlags adds flags to adjust the output to a cobra command
+func (o *renameGardenOptions) AddFlags(flags *pflag.FlagSet) {
+	flags.BoolVar(&o.KeepAlias, "keep-alias", false, "keep the previous identity as an alias of the garden")
+}
+
+// Run executes the command
+func (o *renameGardenOptions) Run(_ util.Factory) error {
+	garden, err := o.Configuration.Garden(o.Name)
+	if err != nil {
+		return err
+	}
+
+	oldName := garden.Name
+
+	if err := o.Configuration.RenameGarden(oldName, o.NewName, o.KeepAlias); err != nil {
+		return err
+	}
+
+	if err := o.Configuration.Save(); err != nil {
+		return fmt.Errorf("failed to rename garden in configuration: %w", err)
+	}
+
+	// in dry-run mode the changes have only been printed
+	if o.Configuration.IsDryRun() {
+		return nil
+	}
+
+	fmt.Fprintf(o.IOStreams.Out, "Successfully renamed garden %q to %q\n", oldName, o.NewName)
+
+	if o.SessionDir != "" {
+		o.renameGardenInTargets(filepath.Dir(o.SessionDir), oldName, o.NewName)
+	}
+
+	return nil
+}
+
+// renameGardenInTargets updates the target files and the target histories of all sessions that refer to the old garden identity.
+// Target files that cannot be updated are reported, as the configuration has already been saved.
+func (o *renameGardenOptions) renameGardenInTargets(sessionsDir, oldName, newName string) {
+	files, err := filepath.Glob(filepath.Join(sessionsDir, "*", "target.yaml"))
+	if err != nil {
+		fmt.Fprintf(o.IOStreams.ErrOut, "Failed to find target files: %v\n", err)
+		return
+	}
+
+	for _, file := range files {
+		provider := target.NewTargetProvider(file, nil)
+
+		t, err := provider.Read()
+		if err != nil {
+			fmt.Fprintf(o.IOStreams.ErrOut, "Failed to read target file %q: %v\n", file, err)
+			continue
+		}
+
+		if t.GardenName() != oldName {
+			continue
+		}
+
+		if err := provider.Write(t.WithGardenName(newName)); err != nil {
+			fmt.Fprintf(o.IOStreams.ErrOut, "Failed to update target file %q: %v\n", file, err)
+		}
+	}
+
+	historyFiles, err := filepath.Glob(filepath.Join(sessionsDir, "*", target.TargetHistoryFilename))
+	if err != nil {
+		fmt.Fprintf(o.IOStreams.ErrOut, "Failed to find target history files: %v\n", err)
+		return
+	}
+
+	for _, file := range historyFiles {
+		if err := target.RenameGardenInTargetHistory(filepath.Dir(file), oldName, newName); err != nil {
+			fmt.Fprintf(o.IOStreams.ErrOut, "Failed to update target history file %q: %v\n", file, err)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/types"
+	"github.com/spf13/cobra"
+
+	cmdconfig "github.com/gardener/gardenctl-v2/pkg/cmd/config"
+	"github.com/gardener/gardenctl-v2/pkg/target"
+)
+
+var _ = Describe("Config Subcommand RenameGarden", func() {
+	Describe("Instance", func() {
+		var cmd *cobra.Command
+
+		BeforeEach(func() {
+			cmd = cmdconfig.NewCmdConfigRenameGarden(factory, streams)
+		})
+
+		It("should have Use, ValidArgsFunction and Flags", func() {
+			Expect(cmd.Use).To(Equal("rename-garden"))
+			Expect(cmd.ValidArgsFunction).NotTo(BeNil())
+			assertAllFlagNames(cmd.Flags(), "keep-alias")
+		})
+	})
+
+	Describe("Options", func() {
+		var options *cmdconfig.RenameGardenOptions
+
+		BeforeEach(func() {
+			options = cmdconfig.NewRenameGardenOptions()
+			options.IOStreams = streams
+		})
+
+		Describe("Complete", func() {
+			It("should fail when getting configuration fails", func() {
+				factory.EXPECT().Manager().Return(manager, nil)
+				manager.EXPECT().Configuration().Return(nil)
+				Expect(options.Complete(factory, nil, nil)).To(MatchError("failed to get configuration"))
+			})
+
+			It("should succeed", func() {
+				factory.EXPECT().Manager().Return(manager, nil)
+				manager.EXPECT().Configuration().Return(cfg)
+				manager.EXPECT().SessionDir().Return(gardenHomeDir)
+				Expect(options.Complete(factory, nil, []string{" old ", " new "})).To(Succeed())
+				Expect(options.Configuration).To(BeIdenticalTo(cfg))
+				Expect(options.SessionDir).To(Equal(gardenHomeDir))
+				Expect(options.Name).To(Equal("old"))
+				Expect(options.NewName).To(Equal("new"))
+			})
+		})
+
+		Describe("Validate", func() {
+			DescribeTable("Validating Name Arguments",
+				func(name, newName string, matcher types.GomegaMatcher) {
+					o := cmdconfig.NewRenameGardenOptions()
+					o.Name = name
+					o.NewName = newName
+					Expect(o.Validate()).To(matcher)
+				},
+				Entry("when both names are set", "foo", "bar", Succeed()),
+				Entry("when garden is empty", "", "bar", MatchError("garden identity is required")),
+				Entry("when new garden is empty", "foo", "", MatchError("new garden identity is required")),
+				Entry("when both names are equal", "foo", "foo", MatchError("new garden identity must differ from the current one")),
+			)
+		})
+
+		Describe("Run", func() {
+			var sessionsDir string
+
+			writeTarget := func(session string, t target.Target) string {
+				dir := filepath.Join(sessionsDir, session)
+				Expect(os.MkdirAll(dir, 0700)).To(Succeed())
+				file := filepath.Join(dir, "target.yaml")
+				Expect(target.NewTargetProvider(file, nil).Write(t)).To(Succeed())
+
+				return file
+			}
+
+			readTarget := func(file string) target.Target {
+				t, err := target.NewTargetProvider(file, nil).Read()
+				Expect(err).NotTo(HaveOccurred())
+
+				return t
+			}
+
+			BeforeEach(func() {
+				var err error
+				sessionsDir, err = os.MkdirTemp(gardenHomeDir, "sessions-*")
+				Expect(err).NotTo(HaveOccurred())
+
+				options.Configuration = cfg
+				options.SessionDir = filepath.Join(sessionsDir, "current")
+			})
+
+			It("should rename the garden and update the targets", func() {
+				current := writeTarget("current", target.NewTarget(gardenIdentity1, "prj1", "", "shoot1"))
+				other := writeTarget("other", target.NewTarget(gardenIdentity2, "", "", ""))
+
+				options.Name = gardenIdentity1
+				options.NewName = gardenIdentity3
+				Expect(options.Run(nil)).To(Succeed())
+
+				assertGardenNames(cfg, gardenIdentity3, gardenIdentity2)
+				Expect(cfg.Gardens[0].Aliases).To(BeNil())
+				assertConfigHasBeenSaved(cfg)
+				Expect(out.String()).To(Equal("Successfully renamed garden \"fooGarden\" to \"bazGarden\"\n"))
+
+				Expect(readTarget(current)).To(Equal(target.NewTarget(gardenIdentity3, "prj1", "", "shoot1")))
+				Expect(readTarget(other)).To(Equal(target.NewTarget(gardenIdentity2, "", "", "")))
+			})
+
+			It("should update the target histories", func() {
+				dir := filepath.Join(sessionsDir, "current")
+				Expect(os.MkdirAll(dir, 0700)).To(Succeed())
+				history := filepath.Join(dir, target.TargetHistoryFilename)
+				Expect(os.WriteFile(history, []byte("- garden: fooGarden\n  project: prj1\n- garden: barGarden\n"), 0600)).To(Succeed())
+
+				options.Name = gardenIdentity1
+				options.NewName = gardenIdentity3
+				Expect(options.Run(nil)).To(Succeed())
+
+				data, err := os.ReadFile(history)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(data)).To(Equal("- garden: bazGarden\n  project: prj1\n- garden: barGarden\n"))
+			})
+
+			It("should keep the previous identity as alias", func() {
+				options.Name = gardenIdentity1
+				options.NewName = gardenIdentity3
+				options.KeepAlias = true
+				Expect(options.Run(nil)).To(Succeed())
+
+				Expect(cfg.Gardens[0].Aliases).To(Equal([]string{gardenIdentity1}))
+				assertConfigHasBeenSaved(cfg)
+			})
+
+			It("should fail when the new name is already used", func() {
+				options.Name = gardenIdentity1
+				options.NewName = gardenIdentity2
+				Expect(options.Run(nil)).To(MatchError(`garden "barGarden" is already defined in gardenctl configuration`))
+			})
+
+			It("should fail when the garden does not exist", func() {
+				options.Name = gardenIdentity3
+				options.NewName = "foo"
+				Expect(options.Run(nil)).To(MatchError(MatchRegexp(`^garden ".*" is not defined`)))
+			})
+		})
+	})
+})
@@ -0,0 +1,118 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
+	"github.com/gardener/gardenctl-v2/pkg/config"
+)
+
+// NewCmdConfigSet returns a new (config) set command.
+func NewCmdConfigSet(f util.Factory, ioStreams util.IOStreams) *cobra.Command {
+	o := &setOptions{
+		Options: base.Options{
+			IOStreams: ioStreams,
+		},
+	}
+	cmd := &cobra.Command{
+		Use:   "set PATH VALUE",
+		Short: "Set a single value of the gardenctl configuration",
+		Long: `Set a single value of the gardenctl configuration.
+The value is addressed by a path of field names separated by dots, e.g. "gardens[my-garden].context".
+Gardens are selected by identity or alias, all other lists by index. Use the index after the last element to append to a list.
+Lists of strings are set from a comma separated value and an empty value clears the field.
+Gardens cannot be added or renamed with this command, use "gardenctl config set-garden" and "gardenctl config rename-garden" instead.`,
+		Example: `# set the context of garden my-garden
+gardenctl config set "gardens[my-garden].context" garden-context
+
+# replace the aliases of garden my-garden
+gardenctl config set "gardens[my-garden].aliases" dev,landscape-dev
+
+# append a global match pattern
+gardenctl config set "matchPatterns[1]" "^(?P<garden>[^/]+)/(?P<shoot>[^/]+)$"
+
+# keep three backups of the configuration file
+gardenctl config set backups 3`,
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: cobra.NoFileCompletions,
+		RunE:              base.WrapRunE(o, f),
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+type setOptions struct {
+	base.Options
+	// Configuration is the gardenctl configuration
+	Configuration *config.Config
+	// Path addresses the configuration value
+	Path string
+	// Value is the new configuration value
+	Value string
+}
+
+// Complete adapts from the command line args to the data required.
+func (o *setOptions) Complete(f util.Factory, cmd *cobra.Command, args []string) error {
+	config, err := getConfiguration(f)
+	if err != nil {
+		return err
+	}
+
+	o.Configuration = config
+
+	if len(args) > 0 {
+		o.Path = strings.TrimSpace(args[0])
+	}
+
+	if len(args) > 1 {
+		o.Value = args[1]
+	}
+
+	return nil
+}
+
+// Validate validates the provided options
+func (o *setOptions) Validate() error {
+	if o.Path == "" {
+		return errors.New("path is required")
+	}
+
+	return nil
+}
+
+// AddFlags adds flags to adjust the output to a cobra command
+func (o *setOptions) AddFlags(_ *pflag.FlagSet) {}
+
+// Run executes the command
+func (o *setOptions) Run(_ util.Factory) error {
+	if err := o.Configuration.Set(o.Path, o.Value); err != nil {
+		return err
+	}
+
+	if err := o.Configuration.Save(); err != nil {
+		return fmt.Errorf("failed to configure %s: %w", o.Path, err)
+	}
+
+	// in dry-run mode the changes have only been printed
+	if o.Configuration.IsDryRun() {
+		return nil
+	}
+
+	fmt.Fprintf(o.IOStreams.Out, "Successfully set %s\n", o.Path)
+
+	return nil
+}
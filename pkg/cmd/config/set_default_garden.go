@@ -0,0 +1,104 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
+	"github.com/gardener/gardenctl-v2/pkg/config"
+)
+
+// NewCmdConfigSetDefaultGarden returns a new (config) set-default-garden command.
+func NewCmdConfigSetDefaultGarden(f util.Factory, ioStreams util.IOStreams) *cobra.Command {
+	o := &setDefaultGardenOptions{
+		Options: base.Options{
+			IOStreams: ioStreams,
+		},
+	}
+	cmd := &cobra.Command{
+		Use:   "set-default-garden",
+		Short: "Set the default Garden of the gardenctl configuration",
+		Long: `Set the default Garden of the gardenctl configuration.
+The default Garden is used if a project, seed or shoot is targeted while no Garden is targeted.
+The Garden can be specified by its identity or by one of its aliases.
+Use "gardenctl config set defaultGarden ''" to remove the default Garden.`,
+		Example: `# use my-garden if no garden is targeted
+gardenctl config set-default-garden my-garden`,
+		ValidArgsFunction: validGardenArgsFunctionWrapper(f, ioStreams),
+		RunE:              base.WrapRunE(o, f),
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+type setDefaultGardenOptions struct {
+	base.Options
+	// Configuration is the gardenctl configuration
+	Configuration *config.Config
+	// Name is the identity or an alias of the Garden that should be used by default
+	Name string
+}
+
+// Complete adapts from the command line args to the data required.
+func (o *setDefaultGardenOptions) Complete(f util.Factory, cmd *cobra.Command, args []string) error {
+	config, err := getConfiguration(f)
+	if err != nil {
+		return err
+	}
+
+	o.Configuration = config
+
+	if len(args) > 0 {
+		o.Name = strings.TrimSpace(args[0])
+	}
+
+	return nil
+}
+
+// Validate validates the provided options
+func (o *setDefaultGardenOptions) Validate() error {
+	if o.Name == "" {
+		return errors.New("garden identity is required")
+	}
+
+	return nil
+}
+
+// AddFlags adds flags to adjust the output to a cobra command
+func (o *setDefaultGardenOptions) AddFlags(_ *pflag.FlagSet) {}
+
+// Run executes the command
+func (o *setDefaultGardenOptions) Run(_ util.Factory) error {
+	garden, err := o.Configuration.Garden(o.Name)
+	if err != nil {
+		return err
+	}
+
+	o.Configuration.DefaultGarden = garden.Name
+
+	if err := o.Configuration.Save(); err != nil {
+		return fmt.Errorf("failed to set default garden: %w", err)
+	}
+
+	// in dry-run mode the changes have only been printed
+	if o.Configuration.IsDryRun() {
+		return nil
+	}
+
+	fmt.Fprintf(o.IOStreams.Out, "Successfully set default garden %q\n", garden.Name)
+
+	return nil
+}
@@ -0,0 +1,83 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+
+	cmdconfig "github.com/gardener/gardenctl-v2/pkg/cmd/config"
+)
+
+var _ = Describe("Config Subcommand SetDefaultGarden", func() {
+	Describe("Instance", func() {
+		var cmd *cobra.Command
+
+		BeforeEach(func() {
+			cmd = cmdconfig.NewCmdConfigSetDefaultGarden(factory, streams)
+		})
+
+		It("should have Use, ValidArgsFunction and no Flags", func() {
+			Expect(cmd.Use).To(Equal("set-default-garden"))
+			Expect(cmd.ValidArgsFunction).NotTo(BeNil())
+			assertAllFlagNames(cmd.Flags())
+		})
+	})
+
+	Describe("Options", func() {
+		var options *cmdconfig.SetDefaultGardenOptions
+
+		BeforeEach(func() {
+			options = cmdconfig.NewSetDefaultGardenOptions()
+			options.IOStreams = streams
+		})
+
+		Describe("Complete", func() {
+			It("should fail when getting configuration fails", func() {
+				factory.EXPECT().Manager().Return(manager, nil)
+				manager.EXPECT().Configuration().Return(nil)
+				Expect(options.Complete(factory, nil, nil)).To(MatchError("failed to get configuration"))
+			})
+
+			It("should succeed", func() {
+				factory.EXPECT().Manager().Return(manager, nil)
+				manager.EXPECT().Configuration().Return(cfg)
+				Expect(options.Complete(factory, nil, []string{" " + gardenIdentity2 + " "})).To(Succeed())
+				Expect(options.Configuration).To(BeIdenticalTo(cfg))
+				Expect(options.Name).To(Equal(gardenIdentity2))
+			})
+		})
+
+		Describe("Validate", func() {
+			It("should fail without a garden", func() {
+				Expect(options.Validate()).To(MatchError("garden identity is required"))
+			})
+		})
+
+		Describe("Run", func() {
+			BeforeEach(func() {
+				options.Configuration = cfg
+			})
+
+			It("should set the default garden by alias", func() {
+				cfg.Gardens[1].Aliases = []string{"bar"}
+				options.Name = "bar"
+				Expect(options.Run(nil)).To(Succeed())
+				Expect(cfg.DefaultGarden).To(Equal(gardenIdentity2))
+				assertConfigHasBeenSaved(cfg)
+				Expect(out.String()).To(Equal("Successfully set default garden \"barGarden\"\n"))
+			})
+
+			It("should fail for an unknown garden", func() {
+				options.Name = gardenIdentity3
+				Expect(options.Run(nil)).To(MatchError(`garden "bazGarden" is not defined in gardenctl configuration`))
+				Expect(cfg.DefaultGarden).To(BeEmpty())
+			})
+		})
+	})
+})
@@ -9,11 +9,11 @@ package config
 import (
 	"errors"
 	"fmt"
-	"regexp"
 	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/component-base/cli/flag"
 
 	"github.com/gardener/gardenctl-v2/internal/util"
@@ -33,7 +33,8 @@ func NewCmdConfigSetGarden(f util.Factory, ioStreams util.IOStreams) *cobra.Comm
 		Short: "Modify or add a Garden to the gardenctl configuration",
 		Long: `Modify or add a Garden to the gardenctl configuration.
 A valid Garden configuration consists of a name (required), kubeconfig path (required), a context as well as any number of patterns.
-In order to share the configuration with gardenlogin, you need to set the name to the cluster identity.`,
+In order to share the configuration with gardenlogin, you need to set the name to the cluster identity.
+Fields of an existing Garden can be removed with an empty value, e.g. --context "", or with the --unset flag.`,
 		Example: `# add new Garden my-garden with no additional values
 gardenctl config set-garden my-garden
 
@@ -42,6 +43,9 @@ export KUBECONFIG=~/path/to/garden-cluster/kubeconfig.yaml
 CLUSTER_IDENTITY=$(kubectl -n kube-system get configmap cluster-identity -ojsonpath={.data.cluster-identity})
 gardenctl config set-garden $CLUSTER_IDENTITY --kubeconfig $KUBECONFIG
 
+# remove the context and the aliases of my-garden
+gardenctl config set-garden my-garden --unset context,aliases
+
 # configure my-garden with a context and patterns
 gardenctl config set-garden my-garden --context garden-context --pattern "^(?:landscape-dev/)?shoot--(?P<project>.+)--(?P<shoot>.+)$" --pattern "https://dashboard\.gardener\.cloud/namespace/(?P<namespace>[^/]+)/shoots/(?P<shoot>[^/]+)`,
 		ValidArgsFunction: validGardenArgsFunctionWrapper(f, ioStreams),
@@ -66,11 +70,17 @@ type setGardenOptions struct {
 	ContextFlag flag.StringFlag
 	// Patterns is a list of regex patterns that can be defined to use custom input formats for targeting
 	// Use named capturing groups to match target values.
-	// Supported capturing groups: project, namespace, shoot
+	// Supported capturing groups: project, namespace, seed, shoot
 	// +optional
 	Patterns []string
+	// Unset is a list of fields that are removed from an existing Garden
+	// +optional
+	Unset []string
 }
 
+// unsettableGardenFields are the fields of a Garden that can be removed with the --unset flag
+var unsettableGardenFields = []string{"kubeconfig", "kubeconfigData", "kubeconfigExec", "context", "aliases", "patterns", "labels"}
+
 // Complete adapts from the command line args to the data required.
 func (o *setGardenOptions) Complete(f util.Factory, cmd *cobra.Command, args []string) error {
 	config, err := getConfiguration(f)
@@ -97,6 +107,18 @@ func (o *setGardenOptions) Validate() error {
 		return err
 	}
 
+	for _, field := range o.Unset {
+		if !sets.NewString(unsettableGardenFields...).Has(field) {
+			return fmt.Errorf("--unset contains the invalid field %q, must be one of %s", field, strings.Join(unsettableGardenFields, ", "))
+		}
+
+		if field == "kubeconfig" && o.KubeconfigFlag.Provided() ||
+			field == "context" && o.ContextFlag.Provided() ||
+			field == "patterns" && o.Patterns != nil {
+			return fmt.Errorf("field %q must not be set and unset at the same time", field)
+		}
+	}
+
 	return nil
 }
 
@@ -106,9 +128,10 @@ func (o *setGardenOptions) AddFlags(flags *pflag.FlagSet) {
 	flags.Var(&o.ContextFlag, "context", "override the current-context of the garden cluster kubeconfig")
 	flags.StringArrayVar(&o.Patterns, "pattern", nil, `define regex match patterns for this garden for custom input formats for targeting.
 Use named capturing groups to match target values.
-Supported capturing groups: project, namespace, shoot.
+Supported capturing groups: project, namespace, seed, shoot.
 Note that if you set this flag it will overwrite the pattern list in the config file.
 You may specify any number of extra patterns.`)
+	flags.StringSliceVar(&o.Unset, "unset", nil, fmt.Sprintf("comma separated list of fields that are removed from an existing garden, supported fields: %s", strings.Join(unsettableGardenFields, ", ")))
 }
 
 // Run executes the command
@@ -131,6 +154,8 @@ func (o *setGardenOptions) Run(_ util.Factory) error {
 				garden.Patterns = nil
 			}
 		}
+
+		unsetGardenFields(garden, o.Unset)
 	} else {
 		o.Configuration.Gardens = append(o.Configuration.Gardens, config.Garden{
 			Name:       o.Name,
@@ -145,11 +170,38 @@ func (o *setGardenOptions) Run(_ util.Factory) error {
 		return fmt.Errorf("failed to configure garden: %w", err)
 	}
 
+	// in dry-run mode the changes have only been printed
+	if o.Configuration.IsDryRun() {
+		return nil
+	}
+
 	fmt.Fprintf(o.IOStreams.Out, "Successfully configured garden %q\n", o.Name)
 
 	return nil
 }
 
+// unsetGardenFields removes the given fields from the garden
+func unsetGardenFields(garden *config.Garden, fields []string) {
+	for _, field := range fields {
+		switch field {
+		case "kubeconfig":
+			garden.Kubeconfig = ""
+		case "kubeconfigData":
+			garden.KubeconfigData = ""
+		case "kubeconfigExec":
+			garden.KubeconfigExec = nil
+		case "context":
+			garden.Context = ""
+		case "aliases":
+			garden.Aliases = nil
+		case "patterns":
+			garden.Patterns = nil
+		case "labels":
+			garden.Labels = nil
+		}
+	}
+}
+
 func validatePatterns(patterns []string) error {
 	if patterns == nil || patterns[0] == "" && len(patterns) == 1 {
 		return nil
@@ -160,16 +212,8 @@ func validatePatterns(patterns []string) error {
 			return fmt.Errorf("pattern[%d] must not be empty", i)
 		}
 
-		re, err := regexp.Compile(p)
-		if err != nil {
-			return fmt.Errorf("pattern[%d] is not a valid regular expression: %w", i, err)
-		}
-
-		names := re.SubexpNames()
-		for _, name := range names[1:] {
-			if name != "project" && name != "namespace" && name != "shoot" {
-				return fmt.Errorf("pattern[%d] contains an invalid subexpression %q", i, name)
-			}
+		if err := config.ValidatePattern(p); err != nil {
+			return fmt.Errorf("pattern[%d] %w", i, err)
 		}
 	}
 
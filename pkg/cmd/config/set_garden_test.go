@@ -29,7 +29,7 @@ var _ = Describe("Config Subcommand SetGarden", func() {
 			Expect(cmd.Use).To(Equal("set-garden"))
 			Expect(cmd.ValidArgsFunction).NotTo(BeNil())
 			Expect(cmd.ValidArgs).To(BeNil())
-			assertAllFlagNames(cmd.Flags(), "context", "kubeconfig", "pattern")
+			assertAllFlagNames(cmd.Flags(), "context", "kubeconfig", "pattern", "unset")
 		})
 	})
 
@@ -86,6 +86,19 @@ var _ = Describe("Config Subcommand SetGarden", func() {
 				Entry("when a pattern is not a valid regular expression", []string{"("}, MatchError(MatchRegexp(`^pattern\[0\] is not a valid regular expression`))),
 				Entry("when a pattern has an invalid subexpression name", []string{"^shoot--(?P<cluster>.+)$`"}, MatchError("pattern[0] contains an invalid subexpression \"cluster\"")),
 			)
+
+			It("should fail for unknown fields to unset", func() {
+				options.Name = "foo"
+				options.Unset = []string{"context", "identity"}
+				Expect(options.Validate()).To(MatchError(`--unset contains the invalid field "identity", must be one of kubeconfig, kubeconfigData, kubeconfigExec, context, aliases, patterns, labels`))
+			})
+
+			It("should fail if a field is set and unset", func() {
+				options.Name = "foo"
+				options.Unset = []string{"context"}
+				Expect(options.ContextFlag.Set("foo")).To(Succeed())
+				Expect(options.Validate()).To(MatchError(`field "context" must not be set and unset at the same time`))
+			})
 		})
 
 		Describe("Run", func() {
@@ -143,6 +156,20 @@ var _ = Describe("Config Subcommand SetGarden", func() {
 				Expect(out.String()).To(MatchRegexp("^Successfully configured garden"))
 			})
 
+			It("should unset fields of an existing garden", func() {
+				cfg.Gardens[0].Aliases = []string{"foo"}
+				cfg.Gardens[0].Labels = map[string]string{"env": "dev"}
+				options.Name = gardenIdentity1
+				options.Unset = []string{"context", "aliases", "labels"}
+				Expect(options.Run(nil)).To(Succeed())
+
+				assertGarden(cfg, &config.Garden{
+					Name:       gardenIdentity1,
+					Kubeconfig: kubeconfig,
+				})
+				assertConfigHasBeenSaved(cfg)
+			})
+
 			It("should fail when the filename is invalid", func() {
 				options.Configuration.Filename = string([]byte{0})
 				Expect(options.Run(nil)).To(MatchError(MatchRegexp("^failed to configure garden")))
@@ -0,0 +1,89 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+
+	cmdconfig "github.com/gardener/gardenctl-v2/pkg/cmd/config"
+)
+
+var _ = Describe("Config Subcommand Set", func() {
+	Describe("Instance", func() {
+		var cmd *cobra.Command
+
+		BeforeEach(func() {
+			cmd = cmdconfig.NewCmdConfigSet(factory, streams)
+		})
+
+		It("should have Use and no Flags", func() {
+			Expect(cmd.Use).To(Equal("set PATH VALUE"))
+			assertAllFlagNames(cmd.Flags())
+		})
+	})
+
+	Describe("Options", func() {
+		var options *cmdconfig.SetOptions
+
+		BeforeEach(func() {
+			options = cmdconfig.NewSetOptions()
+			options.IOStreams = streams
+		})
+
+		Describe("Complete", func() {
+			It("should fail when getting configuration fails", func() {
+				factory.EXPECT().Manager().Return(manager, nil)
+				manager.EXPECT().Configuration().Return(nil)
+				Expect(options.Complete(factory, nil, nil)).To(MatchError("failed to get configuration"))
+			})
+
+			It("should succeed", func() {
+				factory.EXPECT().Manager().Return(manager, nil)
+				manager.EXPECT().Configuration().Return(cfg)
+				Expect(options.Complete(factory, nil, []string{" backups ", " 3 "})).To(Succeed())
+				Expect(options.Configuration).To(BeIdenticalTo(cfg))
+				Expect(options.Path).To(Equal("backups"))
+				Expect(options.Value).To(Equal(" 3 "))
+			})
+		})
+
+		Describe("Validate", func() {
+			It("should fail without a path", func() {
+				Expect(options.Validate()).To(MatchError("path is required"))
+			})
+		})
+
+		Describe("Run", func() {
+			BeforeEach(func() {
+				options.Configuration = cfg
+			})
+
+			It("should set the value and save the configuration", func() {
+				options.Path = "gardens[" + gardenIdentity1 + "].aliases"
+				options.Value = "foo,bar"
+				Expect(options.Run(nil)).To(Succeed())
+				Expect(cfg.Gardens[0].Aliases).To(Equal([]string{"foo", "bar"}))
+				assertConfigHasBeenSaved(cfg)
+				Expect(out.String()).To(Equal("Successfully set gardens[fooGarden].aliases\n"))
+			})
+
+			It("should fail to set an invalid value", func() {
+				options.Path = "backups"
+				options.Value = "many"
+				Expect(options.Run(nil)).To(MatchError(`failed to set backups: "many" is not a number`))
+			})
+
+			It("should fail to save invalid patterns", func() {
+				options.Path = "matchPatterns[0]"
+				options.Value = "^(?P<cluster>.+)$"
+				Expect(options.Run(nil)).To(MatchError(HavePrefix("failed to configure matchPatterns[0]: invalid patterns")))
+			})
+		})
+	})
+})
@@ -0,0 +1,156 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
+	"github.com/gardener/gardenctl-v2/pkg/config"
+)
+
+// NewCmdConfigSync returns a new (config) sync command.
+func NewCmdConfigSync(f util.Factory, ioStreams util.IOStreams) *cobra.Command {
+	o := &syncOptions{
+		Options: base.Options{
+			IOStreams: ioStreams,
+		},
+	}
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Fetch the centrally managed configuration files of the sync sources",
+		Long: `Fetch the centrally managed configuration files of the sync sources and report the gardens that have been added or removed.
+Sync sources are configured in the syncSources section of the gardenctl configuration. Each sync source has a name and a URL, that is either
+an https:// URL, an OCI artifact (oci://registry/repository:tag) or a Git repository (git+https:// or git+ssh://).
+The fetched files are stored in the sync directory next to the configuration file and merged into the configuration whenever it is loaded.
+Settings of the configuration file take precedence, e.g. to use a different context or additional aliases for a synced garden.
+If no names are given, all sync sources are fetched.`,
+		Example: `# add a sync source
+gardenctl config set "syncSources[0].name" team
+gardenctl config set "syncSources[0].url" https://config.example.com/gardenctl-v2.yaml
+
+# fetch all sync sources
+gardenctl config sync
+
+# fetch the sync source team only
+gardenctl config sync team`,
+		ValidArgsFunction: validSyncSourceArgsFunctionWrapper(f, ioStreams),
+		RunE:              base.WrapRunE(o, f),
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+type syncOptions struct {
+	base.Options
+	// Configuration is the gardenctl configuration
+	Configuration *config.Config
+	// Names are the names of the sync sources to fetch. All sync sources are fetched if empty
+	Names []string
+}
+
+// Complete adapts from the command line args to the data required.
+func (o *syncOptions) Complete(f util.Factory, cmd *cobra.Command, args []string) error {
+	config, err := getConfiguration(f)
+	if err != nil {
+		return err
+	}
+
+	o.Configuration = config
+	o.Names = args
+
+	return nil
+}
+
+// Validate validates the provided options
+func (o *syncOptions) Validate() error {
+	if len(o.Configuration.SyncSources) == 0 {
+		return errors.New("no sync sources are defined in gardenctl configuration")
+	}
+
+	for _, name := range o.Names {
+		if _, err := o.Configuration.SyncSource(name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AddFlags adds flags to adjust the output to a cobra command
+func (o *syncOptions) AddFlags(_ *pflag.FlagSet) {}
+
+// Run executes the command
+func (o *syncOptions) Run(f util.Factory) error {
+	sources := o.Configuration.SyncSources
+
+	if len(o.Names) > 0 {
+		sources = []config.SyncSource{}
+
+		for _, name := range o.Names {
+			source, err := o.Configuration.SyncSource(name)
+			if err != nil {
+				return err
+			}
+
+			sources = append(sources, *source)
+		}
+	}
+
+	for _, source := range sources {
+		result, err := o.Configuration.Sync(f.Context(), source)
+		if err != nil {
+			return fmt.Errorf("failed to sync %q: %w", source.Name, err)
+		}
+
+		// in dry-run mode the changes have only been printed
+		if o.Configuration.IsDryRun() {
+			continue
+		}
+
+		fmt.Fprintf(o.IOStreams.Out, "Successfully synced %q\n", source.Name)
+
+		for _, name := range result.Added {
+			fmt.Fprintf(o.IOStreams.Out, "  + %s\n", name)
+		}
+
+		for _, name := range result.Removed {
+			fmt.Fprintf(o.IOStreams.Out, "  - %s\n", name)
+		}
+	}
+
+	return nil
+}
+
+func validSyncSourceArgsFunctionWrapper(f util.Factory, ioStreams util.IOStreams) cobraValidArgsFunction {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		config, err := getConfiguration(f)
+		if err != nil {
+			fmt.Fprintln(ioStreams.ErrOut, err.Error())
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		given := sets.NewString(args...)
+		names := []string{}
+
+		for _, s := range config.SyncSources {
+			if !given.Has(s.Name) {
+				names = append(names, s.Name)
+			}
+		}
+
+		return util.FilterStringsByPrefix(toComplete, names), cobra.ShellCompDirectiveNoFileComp
+	}
+}
@@ -0,0 +1,93 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+
+	cmdconfig "github.com/gardener/gardenctl-v2/pkg/cmd/config"
+	"github.com/gardener/gardenctl-v2/pkg/config"
+)
+
+var _ = Describe("Config Subcommand Sync", func() {
+	Describe("Instance", func() {
+		var cmd *cobra.Command
+
+		BeforeEach(func() {
+			cmd = cmdconfig.NewCmdConfigSync(factory, streams)
+		})
+
+		It("should have Use, ValidArgsFunction and no Flags", func() {
+			Expect(cmd.Use).To(Equal("sync"))
+			Expect(cmd.ValidArgsFunction).NotTo(BeNil())
+			assertAllFlagNames(cmd.Flags())
+		})
+
+		It("should complete the names of the sync sources that have not been given", func() {
+			cfg.SyncSources = []config.SyncSource{{Name: "team"}, {Name: "other"}}
+			factory.EXPECT().Manager().Return(manager, nil)
+			manager.EXPECT().Configuration().Return(cfg)
+			values, directive := cmd.ValidArgsFunction(cmd, []string{"other"}, "")
+			Expect(values).To(Equal([]string{"team"}))
+			Expect(directive).To(Equal(cobra.ShellCompDirectiveNoFileComp))
+		})
+	})
+
+	Describe("Options", func() {
+		var options *cmdconfig.SyncOptions
+
+		BeforeEach(func() {
+			options = cmdconfig.NewSyncOptions()
+			options.IOStreams = streams
+		})
+
+		Describe("Complete", func() {
+			It("should fail when getting configuration fails", func() {
+				factory.EXPECT().Manager().Return(manager, nil)
+				manager.EXPECT().Configuration().Return(nil)
+				Expect(options.Complete(factory, nil, nil)).To(MatchError("failed to get configuration"))
+			})
+
+			It("should succeed", func() {
+				factory.EXPECT().Manager().Return(manager, nil)
+				manager.EXPECT().Configuration().Return(cfg)
+				Expect(options.Complete(factory, nil, []string{"team"})).To(Succeed())
+				Expect(options.Configuration).To(BeIdenticalTo(cfg))
+				Expect(options.Names).To(Equal([]string{"team"}))
+			})
+		})
+
+		Describe("Validate", func() {
+			BeforeEach(func() {
+				options.Configuration = cfg
+			})
+
+			It("should fail without sync sources", func() {
+				Expect(options.Validate()).To(MatchError("no sync sources are defined in gardenctl configuration"))
+			})
+
+			It("should fail for an unknown sync source", func() {
+				cfg.SyncSources = []config.SyncSource{{Name: "team", URL: "https://example.com/gardenctl-v2.yaml"}}
+				options.Names = []string{"other"}
+				Expect(options.Validate()).To(MatchError(`sync source "other" is not defined in gardenctl configuration`))
+			})
+		})
+
+		Describe("Run", func() {
+			It("should fail for an invalid sync source", func() {
+				cfg.SyncSources = []config.SyncSource{{Name: "team", URL: "ftp://example.com/gardenctl-v2.yaml"}}
+				options.Configuration = cfg
+				factory.EXPECT().Context().Return(context.Background())
+				Expect(options.Run(factory)).To(MatchError(HavePrefix(`failed to sync "team": invalid sync source: url "ftp://example.com/gardenctl-v2.yaml" is not supported`)))
+			})
+		})
+	})
+})
@@ -0,0 +1,187 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
+	"github.com/gardener/gardenctl-v2/pkg/config"
+)
+
+// NewCmdConfigTestPattern returns a new (config) test-pattern command.
+func NewCmdConfigTestPattern(f util.Factory, ioStreams util.IOStreams) *cobra.Command {
+	o := &testPatternOptions{
+		Options: base.Options{
+			IOStreams: ioStreams,
+		},
+	}
+	cmd := &cobra.Command{
+		Use:   "test-pattern VALUE",
+		Short: "Print which pattern matches a value and the extracted target",
+		Long: `Print which pattern of the gardenctl configuration matches a value and the garden, project, namespace, seed and shoot
+that are extracted from it. The value is matched in the same way as the value of "gardenctl target", but nothing is targeted.
+This helps to debug the patterns of the gardens and the global matchPatterns.`,
+		Example: `# test which pattern matches a shoot namespace
+gardenctl config test-pattern shoot--my-project--my-shoot
+
+# test a pattern with the garden my-garden being preferred
+gardenctl config test-pattern shoot--my-project--my-shoot --garden my-garden
+
+# test a dashboard URL and print the result as json
+gardenctl config test-pattern https://dashboard.gardener.cloud/namespace/garden-my-project/shoots/my-shoot -o json`,
+		Args: cobra.ExactArgs(1),
+		RunE: base.WrapRunE(o, f),
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// patternTestResult is the result of testing a value against the configured patterns
+type patternTestResult struct {
+	// Source is the location of the matching pattern in the gardenctl configuration
+	Source string `yaml:"source" json:"source"`
+	// Pattern is the regular expression that matched the value
+	Pattern string `yaml:"pattern" json:"pattern"`
+	// Garden is the matched garden
+	Garden string `yaml:"garden" json:"garden"`
+	// Project is the matched project
+	Project string `yaml:"project,omitempty" json:"project,omitempty"`
+	// Namespace is the matched namespace
+	Namespace string `yaml:"namespace,omitempty" json:"namespace,omitempty"`
+	// Seed is the matched seed
+	Seed string `yaml:"seed,omitempty" json:"seed,omitempty"`
+	// Shoot is the matched shoot
+	Shoot string `yaml:"shoot,omitempty" json:"shoot,omitempty"`
+}
+
+type testPatternOptions struct {
+	base.Options
+	// Configuration is the gardenctl configuration
+	Configuration *config.Config
+	// PreferredGarden is the name of the currently targeted garden, its patterns are matched first
+	PreferredGarden string
+	// Value is the value that is matched against the configured patterns
+	Value string
+}
+
+// Complete adapts from the command line args to the data required.
+func (o *testPatternOptions) Complete(f util.Factory, cmd *cobra.Command, args []string) error {
+	manager, err := f.Manager()
+	if err != nil {
+		return fmt.Errorf("failed to get target manager: %w", err)
+	}
+
+	o.Configuration = manager.Configuration()
+	if o.Configuration == nil {
+		return errors.New("failed to get configuration")
+	}
+
+	// the current target already takes the --garden flag into account
+	currentTarget, err := manager.CurrentTarget()
+	if err != nil {
+		return fmt.Errorf("failed to get current target: %w", err)
+	}
+
+	o.PreferredGarden = currentTarget.GardenName()
+
+	if len(args) > 0 {
+		o.Value = args[0]
+	}
+
+	return nil
+}
+
+// Validate validates the provided options
+func (o *testPatternOptions) Validate() error {
+	if o.Value == "" {
+		return errors.New("value must not be empty")
+	}
+
+	return o.Options.Validate()
+}
+
+// AddFlags adds flags to adjust the output to a cobra command
+func (o *testPatternOptions) AddFlags(flags *pflag.FlagSet) {
+	o.Options.AddFlags(flags)
+}
+
+// Run executes the command
+func (o *testPatternOptions) Run(_ util.Factory) error {
+	match, err := o.Configuration.MatchPattern(o.PreferredGarden, o.Value)
+	if err != nil {
+		return err
+	}
+
+	result := &patternTestResult{
+		Source:    patternSource(o.Configuration, match),
+		Pattern:   match.Pattern,
+		Garden:    match.Garden,
+		Project:   match.Project,
+		Namespace: match.Namespace,
+		Seed:      match.Seed,
+		Shoot:     match.Shoot,
+	}
+
+	if o.Output != "" {
+		return o.PrintObject(result)
+	}
+
+	fmt.Fprintf(o.IOStreams.Out, "Pattern:   %s\n", result.Pattern)
+	fmt.Fprintf(o.IOStreams.Out, "Source:    %s\n", result.Source)
+	fmt.Fprintf(o.IOStreams.Out, "Garden:    %s\n", result.Garden)
+	fmt.Fprintf(o.IOStreams.Out, "Project:   %s\n", result.Project)
+	fmt.Fprintf(o.IOStreams.Out, "Namespace: %s\n", result.Namespace)
+	fmt.Fprintf(o.IOStreams.Out, "Seed:      %s\n", result.Seed)
+	fmt.Fprintf(o.IOStreams.Out, "Shoot:     %s\n", result.Shoot)
+
+	return nil
+}
+
+// patternSource returns the location of the matching pattern in the gardenctl configuration.
+// Garden scoped patterns are matched before the global match patterns and are therefore looked up first.
+func patternSource(cfg *config.Config, match *config.PatternMatch) string {
+	for i, g := range cfg.Gardens {
+		if g.Name != match.Garden {
+			continue
+		}
+
+		for j, p := range g.Patterns {
+			if p == match.Pattern {
+				return fmt.Sprintf("gardens[%d].patterns[%d]", i, j)
+			}
+		}
+	}
+
+	for j, p := range cfg.MatchPatterns {
+		if p == match.Pattern {
+			return fmt.Sprintf("matchPatterns[%d]", j)
+		}
+	}
+
+	for j, name := range cfg.PatternPresets {
+		patterns, err := config.PatternPreset(name)
+		if err != nil {
+			continue
+		}
+
+		for _, p := range patterns {
+			if p == match.Pattern {
+				return fmt.Sprintf("patternPresets[%d] (%s)", j, name)
+			}
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,104 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+
+	cmdconfig "github.com/gardener/gardenctl-v2/pkg/cmd/config"
+	"github.com/gardener/gardenctl-v2/pkg/target"
+)
+
+var _ = Describe("Config Subcommand TestPattern", func() {
+	Describe("Instance", func() {
+		var cmd *cobra.Command
+
+		BeforeEach(func() {
+			cmd = cmdconfig.NewCmdConfigTestPattern(factory, streams)
+		})
+
+		It("should have Use and Flags", func() {
+			Expect(cmd.Use).To(Equal("test-pattern VALUE"))
+			assertAllFlagNames(cmd.Flags(), "output")
+		})
+	})
+
+	Describe("Options", func() {
+		var options *cmdconfig.TestPatternOptions
+
+		BeforeEach(func() {
+			options = cmdconfig.NewTestPatternOptions()
+			options.IOStreams = streams
+		})
+
+		Describe("Complete", func() {
+			It("should use the targeted garden as preferred garden", func() {
+				factory.EXPECT().Manager().Return(manager, nil)
+				manager.EXPECT().Configuration().Return(cfg)
+				manager.EXPECT().CurrentTarget().Return(target.NewTarget(gardenIdentity2, "", "", ""), nil)
+				Expect(options.Complete(factory, nil, []string{"shoot--foo--bar"})).To(Succeed())
+				Expect(options.Configuration).To(BeIdenticalTo(cfg))
+				Expect(options.PreferredGarden).To(Equal(gardenIdentity2))
+				Expect(options.Value).To(Equal("shoot--foo--bar"))
+			})
+		})
+
+		Describe("Validate", func() {
+			It("should fail for an empty value", func() {
+				Expect(options.Validate()).To(MatchError("value must not be empty"))
+			})
+		})
+
+		Describe("Run", func() {
+			BeforeEach(func() {
+				options.Configuration = cfg
+			})
+
+			It("should print the matching garden pattern and the extracted values", func() {
+				options.Value = "shoot--foo--bar"
+				Expect(options.Run(factory)).To(Succeed())
+				Expect(out.String()).To(Equal("Pattern:   ^shoot--(?P<project>.+)--(?P<shoot>.+)$\n" +
+					"Source:    gardens[1].patterns[0]\n" +
+					"Garden:    barGarden\n" +
+					"Project:   foo\n" +
+					"Namespace: \n" +
+					"Seed:      \n" +
+					"Shoot:     bar\n"))
+			})
+
+			It("should print the matching global match pattern as json", func() {
+				cfg.MatchPatterns = []string{"^(?P<garden>[^/]+)/(?P<namespace>[^/]+)/(?P<shoot>[^/]+)$"}
+				options.Value = "fooGarden/garden-foo/bar"
+				options.Output = "json"
+				Expect(options.Run(factory)).To(Succeed())
+				Expect(out.String()).To(MatchJSON(`{
+  "source": "matchPatterns[0]",
+  "pattern": "^(?P<garden>[^/]+)/(?P<namespace>[^/]+)/(?P<shoot>[^/]+)$",
+  "garden": "fooGarden",
+  "namespace": "garden-foo",
+  "shoot": "bar"
+}`))
+			})
+
+			It("should print the pattern preset of the matching pattern", func() {
+				cfg.PatternPresets = []string{"dashboard", "shorthand"}
+				options.Value = "fooGarden/foo/bar"
+				options.Output = "yaml"
+				Expect(options.Run(factory)).To(Succeed())
+				Expect(out.String()).To(ContainSubstring("source: patternPresets[1] (shorthand)\n"))
+				Expect(out.String()).To(ContainSubstring("garden: fooGarden\nproject: foo\nshoot: bar\n"))
+			})
+
+			It("should fail if no pattern matches", func() {
+				options.Value = "foo"
+				Expect(options.Run(factory)).To(MatchError("the provided value does not match any pattern"))
+			})
+		})
+	})
+})
@@ -0,0 +1,114 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1beta1 "k8s.io/apimachinery/pkg/apis/meta/v1beta1"
+	"k8s.io/cli-runtime/pkg/printers"
+
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
+	"github.com/gardener/gardenctl-v2/pkg/config"
+)
+
+// NewCmdConfigValidate returns a new (config) validate command.
+func NewCmdConfigValidate(f util.Factory, ioStreams util.IOStreams) *cobra.Command {
+	o := &validateOptions{
+		Options: base.Options{
+			IOStreams: ioStreams,
+		},
+	}
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Validate the gardenctl configuration",
+		Long: `Validate the gardenctl configuration and print all issues that have been found.
+The configuration is checked for duplicate garden identities, colliding aliases, unreadable kubeconfig files
+and invalid patterns. The command fails if at least one issue with severity error has been found.`,
+		Example: `# validate the current configuration
+gardenctl config validate
+
+# print the validation result as json
+gardenctl config validate -o json`,
+		RunE: base.WrapRunE(o, f),
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+type validateOptions struct {
+	base.Options
+	// Configuration is the gardenctl configuration
+	Configuration *config.Config
+}
+
+// Complete adapts from the command line args to the data required.
+func (o *validateOptions) Complete(f util.Factory, cmd *cobra.Command, args []string) error {
+	config, err := getConfiguration(f)
+	if err != nil {
+		return err
+	}
+
+	o.Configuration = config
+
+	return nil
+}
+
+// Run executes the command
+func (o *validateOptions) Run(_ util.Factory) error {
+	diagnostics := o.Configuration.Validate()
+
+	if o.Output == "" {
+		if err := o.printTable(diagnostics); err != nil {
+			return err
+		}
+	} else if err := o.PrintObject(diagnostics); err != nil {
+		return err
+	}
+
+	if diagnostics.HasErrors() {
+		return errors.New("the gardenctl configuration is invalid")
+	}
+
+	return nil
+}
+
+func (o *validateOptions) printTable(diagnostics config.Diagnostics) error {
+	if len(diagnostics) == 0 {
+		fmt.Fprintf(o.IOStreams.Out, "Configuration %q is valid\n", o.Configuration.Filename)
+		return nil
+	}
+
+	table := &metav1beta1.Table{
+		ColumnDefinitions: []metav1.TableColumnDefinition{
+			{Name: "Severity", Type: "string"},
+			{Name: "Garden", Type: "string"},
+			{Name: "Field", Type: "string"},
+			{Name: "Message", Type: "string"},
+		},
+		Rows: []metav1.TableRow{},
+	}
+
+	for _, d := range diagnostics {
+		table.Rows = append(table.Rows, metav1.TableRow{
+			Cells: []interface{}{d.Severity, d.Garden, d.Field, d.Message},
+		})
+	}
+
+	printer := printers.NewTablePrinter(printers.PrintOptions{})
+	if err := printer.PrintObj(table, o.IOStreams.Out); err != nil {
+		return fmt.Errorf("failed to output validation result: %w", err)
+	}
+
+	return nil
+}
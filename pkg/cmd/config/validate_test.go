@@ -0,0 +1,101 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+
+	cmdconfig "github.com/gardener/gardenctl-v2/pkg/cmd/config"
+	"github.com/gardener/gardenctl-v2/pkg/config"
+)
+
+var _ = Describe("Config Subcommand Validate", func() {
+	Describe("Instance", func() {
+		var cmd *cobra.Command
+
+		BeforeEach(func() {
+			cmd = cmdconfig.NewCmdConfigValidate(factory, streams)
+		})
+
+		It("should have Use and Flags", func() {
+			Expect(cmd.Use).To(Equal("validate"))
+			assertAllFlagNames(cmd.Flags(), "output")
+		})
+	})
+
+	Describe("Options", func() {
+		var options *cmdconfig.ValidateOptions
+
+		BeforeEach(func() {
+			options = cmdconfig.NewValidateOptions()
+			options.IOStreams = streams
+		})
+
+		Describe("Complete", func() {
+			It("should fail when getting configuration fails", func() {
+				factory.EXPECT().Manager().Return(manager, nil)
+				manager.EXPECT().Configuration().Return(nil)
+				Expect(options.Complete(factory, nil, nil)).To(MatchError("failed to get configuration"))
+			})
+
+			It("should succeed", func() {
+				factory.EXPECT().Manager().Return(manager, nil)
+				manager.EXPECT().Configuration().Return(cfg)
+				Expect(options.Complete(factory, nil, nil)).To(Succeed())
+				Expect(options.Configuration).To(BeIdenticalTo(cfg))
+			})
+		})
+
+		Describe("Run", func() {
+			BeforeEach(func() {
+				options.Configuration = cfg
+			})
+
+			Context("when the configuration is valid", func() {
+				BeforeEach(func() {
+					filename := filepath.Join(gardenHomeDir, "kubeconfig.yaml")
+					Expect(os.WriteFile(filename, []byte("apiVersion: v1\nkind: Config\n"), 0600)).To(Succeed())
+
+					for i := range cfg.Gardens {
+						cfg.Gardens[i].Kubeconfig = filename
+					}
+				})
+
+				It("should print a success message", func() {
+					Expect(options.Run(nil)).To(Succeed())
+					Expect(out.String()).To(Equal("Configuration \"" + cfg.Filename + "\" is valid\n"))
+				})
+			})
+
+			Context("when the configuration is invalid", func() {
+				It("should print a table and fail", func() {
+					Expect(options.Run(nil)).To(MatchError("the gardenctl configuration is invalid"))
+					Expect(out.String()).To(HavePrefix("SEVERITY"))
+					Expect(out.String()).To(ContainSubstring("gardens[0].kubeconfig"))
+					Expect(out.String()).To(ContainSubstring("gardens[1].kubeconfig"))
+				})
+
+				It("should print the diagnostics as json and fail", func() {
+					options.Output = "json"
+					Expect(options.Run(nil)).To(MatchError("the gardenctl configuration is invalid"))
+
+					var diagnostics config.Diagnostics
+					Expect(json.Unmarshal([]byte(out.String()), &diagnostics)).To(Succeed())
+					Expect(diagnostics).To(HaveLen(2))
+					Expect(diagnostics[0].Garden).To(Equal(gardenIdentity1))
+					Expect(diagnostics[0].Severity).To(Equal(config.SeverityError))
+				})
+			})
+		})
+	})
+})
@@ -7,7 +7,16 @@ SPDX-License-Identifier: Apache-2.0
 package config
 
 import (
+	"errors"
+	"fmt"
+	"strings"
+
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1beta1 "k8s.io/apimachinery/pkg/apis/meta/v1beta1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/cli-runtime/pkg/printers"
 
 	"github.com/gardener/gardenctl-v2/internal/util"
 	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
@@ -20,12 +29,25 @@ func NewCmdConfigView(f util.Factory, ioStreams util.IOStreams) *cobra.Command {
 		Options: base.Options{
 			IOStreams: ioStreams,
 		},
+		ShowKubeconfigPaths: true,
 	}
 	cmd := &cobra.Command{
 		Use:   "view",
 		Short: "Print the gardenctl configuration",
 		Example: `# view current configuration
-gardenctl config view`,
+gardenctl config view
+
+# view the configuration of a single garden
+gardenctl config view --garden my-garden
+
+# view the configuration of the targeted garden only
+gardenctl config view --minify
+
+# view the configuration of all gardens with the label env=prod
+gardenctl config view --selector env=prod
+
+# print a table of all gardens without the kubeconfig paths
+gardenctl config view -o table --show-kubeconfig-paths=false`,
 		RunE: base.WrapRunE(o, f),
 	}
 
@@ -38,16 +60,40 @@ type viewOptions struct {
 	base.Options
 	// Configuration is the gardenctl configuration
 	Configuration *config.Config
+	// Garden is the name or alias of the garden that should be printed, it is taken from the global --garden flag
+	Garden string
+	// Minify restricts the output to the targeted garden
+	Minify bool
+	// TargetedGarden is the name of the currently targeted garden
+	TargetedGarden string
+	// Selector is a label selector that restricts the output to the matching gardens
+	Selector string
+	// ShowKubeconfigPaths controls whether the kubeconfig paths of the gardens are printed
+	ShowKubeconfigPaths bool
 }
 
 // Complete adapts from the command line args to the data required.
 func (o *viewOptions) Complete(f util.Factory, cmd *cobra.Command, args []string) error {
-	config, err := getConfiguration(f)
+	manager, err := f.Manager()
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to get target manager: %w", err)
+	}
+
+	o.Configuration = manager.Configuration()
+	if o.Configuration == nil {
+		return errors.New("failed to get configuration")
 	}
 
-	o.Configuration = config
+	o.Garden = manager.TargetFlags().GardenName()
+
+	if o.Minify {
+		currentTarget, err := manager.CurrentTarget()
+		if err != nil {
+			return fmt.Errorf("failed to get current target: %w", err)
+		}
+
+		o.TargetedGarden = currentTarget.GardenName()
+	}
 
 	if o.Output == "" {
 		o.Output = "yaml"
@@ -56,7 +102,118 @@ func (o *viewOptions) Complete(f util.Factory, cmd *cobra.Command, args []string
 	return nil
 }
 
+// Validate validates the provided options
+func (o *viewOptions) Validate() error {
+	return base.ValidateOutput(o.Output)
+}
+
+// AddFlags adds flags to adjust the output to a cobra command
+func (o *viewOptions) AddFlags(flags *pflag.FlagSet) {
+	flags.StringVarP(&o.Output, "output", "o", o.Output, base.OutputFormatsUsage()+" Defaults to 'yaml'.")
+	flags.BoolVar(&o.Minify, "minify", false, "print only the currently targeted garden")
+	flags.StringVarP(&o.Selector, "selector", "l", "", "print only the gardens matching the label selector, e.g. env=prod")
+	flags.BoolVar(&o.ShowKubeconfigPaths, "show-kubeconfig-paths", o.ShowKubeconfigPaths, "print the kubeconfig paths of the gardens")
+}
+
 // Run executes the command
 func (o *viewOptions) Run(_ util.Factory) error {
-	return o.PrintObject(o.Configuration)
+	cfg, err := o.filteredConfiguration()
+	if err != nil {
+		return err
+	}
+
+	if o.Output == base.OutputTable {
+		return o.printTable(cfg)
+	}
+
+	return o.PrintObject(cfg)
+}
+
+// filteredConfiguration returns a copy of the configuration that only contains
+// the selected gardens. The loaded configuration is not modified.
+func (o *viewOptions) filteredConfiguration() (*config.Config, error) {
+	cfg := *o.Configuration
+	cfg.Gardens = append([]config.Garden{}, o.Configuration.Gardens...)
+
+	name := o.Garden
+
+	// the current target already takes the --garden flag into account
+	if o.Minify {
+		if o.TargetedGarden == "" {
+			return nil, errors.New("no garden targeted")
+		}
+
+		name = o.TargetedGarden
+	}
+
+	if name != "" {
+		garden, err := o.Configuration.Garden(name)
+		if err != nil {
+			return nil, err
+		}
+
+		cfg.Gardens = []config.Garden{*garden}
+	}
+
+	if o.Selector != "" {
+		gardens, err := cfg.SelectGardens(o.Selector)
+		if err != nil {
+			return nil, err
+		}
+
+		cfg.Gardens = gardens
+	}
+
+	if !o.ShowKubeconfigPaths {
+		for i := range cfg.Gardens {
+			cfg.Gardens[i].Kubeconfig = ""
+		}
+	}
+
+	return &cfg, nil
+}
+
+func (o *viewOptions) printTable(cfg *config.Config) error {
+	table := &metav1beta1.Table{
+		ColumnDefinitions: []metav1.TableColumnDefinition{
+			{Name: "Identity", Type: "string"},
+			{Name: "Aliases", Type: "string"},
+			{Name: "Context", Type: "string"},
+			{Name: "Labels", Type: "string"},
+		},
+		Rows: []metav1.TableRow{},
+	}
+
+	if o.ShowKubeconfigPaths {
+		table.ColumnDefinitions = append(table.ColumnDefinitions, metav1.TableColumnDefinition{Name: "Kubeconfig", Type: "string"})
+	}
+
+	for _, g := range cfg.Gardens {
+		cells := []interface{}{g.Name, strings.Join(g.Aliases, ","), g.Context, labels.Set(g.Labels).String()}
+
+		if o.ShowKubeconfigPaths {
+			cells = append(cells, kubeconfigSource(g))
+		}
+
+		table.Rows = append(table.Rows, metav1.TableRow{Cells: cells})
+	}
+
+	printer := printers.NewTablePrinter(printers.PrintOptions{})
+	if err := printer.PrintObj(table, o.IOStreams.Out); err != nil {
+		return fmt.Errorf("failed to output configuration: %w", err)
+	}
+
+	return nil
+}
+
+// kubeconfigSource returns a short description of where the kubeconfig of the garden is taken from
+func kubeconfigSource(g config.Garden) string {
+	switch {
+	case g.KubeconfigExec != nil:
+		return "<exec: " + g.KubeconfigExec.Command + ">"
+	case g.KubeconfigData != "":
+		return "<inline>"
+	default:
+		return g.Kubeconfig
+	}
 }
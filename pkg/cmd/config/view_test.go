@@ -18,6 +18,7 @@ import (
 
 	cmdconfig "github.com/gardener/gardenctl-v2/pkg/cmd/config"
 	"github.com/gardener/gardenctl-v2/pkg/config"
+	"github.com/gardener/gardenctl-v2/pkg/target"
 )
 
 var _ = Describe("Config Subcommand View", func() {
@@ -30,7 +31,7 @@ var _ = Describe("Config Subcommand View", func() {
 
 		It("should have Use and Flags", func() {
 			Expect(cmd.Use).To(Equal("view"))
-			assertAllFlagNames(cmd.Flags(), "output")
+			assertAllFlagNames(cmd.Flags(), "minify", "output", "selector", "show-kubeconfig-paths")
 		})
 	})
 
@@ -55,10 +56,22 @@ var _ = Describe("Config Subcommand View", func() {
 				It("should succeed", func() {
 					factory.EXPECT().Manager().Return(manager, nil)
 					manager.EXPECT().Configuration().Return(cfg)
+					manager.EXPECT().TargetFlags().Return(target.NewTargetFlags(gardenIdentity1, "", "", "", false))
 					Expect(options.Complete(factory, nil, nil)).To(Succeed())
 					Expect(options.Configuration).To(BeIdenticalTo(cfg))
+					Expect(options.Garden).To(Equal(gardenIdentity1))
 					Expect(options.Output).To(Equal("yaml"))
 				})
+
+				It("should get the targeted garden when minify is set", func() {
+					options.Minify = true
+					factory.EXPECT().Manager().Return(manager, nil)
+					manager.EXPECT().Configuration().Return(cfg)
+					manager.EXPECT().TargetFlags().Return(target.NewTargetFlags("", "", "", "", false))
+					manager.EXPECT().CurrentTarget().Return(target.NewTarget(gardenIdentity2, "", "", ""), nil)
+					Expect(options.Complete(factory, nil, nil)).To(Succeed())
+					Expect(options.TargetedGarden).To(Equal(gardenIdentity2))
+				})
 			})
 		})
 
@@ -70,20 +83,96 @@ var _ = Describe("Config Subcommand View", func() {
 				},
 				Entry("when output is yaml", "yaml", Succeed()),
 				Entry("when output is json", "json", Succeed()),
+				Entry("when output is table", "table", Succeed()),
 				Entry("when output is empty", "", Succeed()),
 				Entry("when output is invalid", "invalid", Not(Succeed())),
 			)
 		})
 
 		Describe("Run", func() {
-			It("should print configuration in json format", func() {
+			BeforeEach(func() {
 				options.Configuration = cfg
+				options.ShowKubeconfigPaths = true
+			})
+
+			printedConfig := func() *config.Config {
+				c := &config.Config{Filename: cfg.Filename}
+				ExpectWithOffset(1, json.Unmarshal([]byte(out.String()), c)).To(Succeed())
+
+				return c
+			}
+
+			It("should print configuration in json format", func() {
 				options.Output = "json"
 				Expect(options.Run(nil)).To(Succeed())
+				Expect(printedConfig()).To(BeEquivalentTo(cfg))
+			})
 
-				c := &config.Config{Filename: cfg.Filename}
-				Expect(json.Unmarshal([]byte(out.String()), c)).To(Succeed())
-				Expect(c).To(BeEquivalentTo(cfg))
+			It("should only print the selected garden", func() {
+				options.Output = "json"
+				options.Garden = gardenIdentity2
+				Expect(options.Run(nil)).To(Succeed())
+				assertGardenNames(printedConfig(), gardenIdentity2)
+				assertGardenNames(cfg, gardenIdentity1, gardenIdentity2)
+			})
+
+			It("should fail if the selected garden does not exist", func() {
+				options.Garden = gardenIdentity3
+				Expect(options.Run(nil)).To(MatchError(ContainSubstring("is not defined in gardenctl configuration")))
+			})
+
+			It("should only print the targeted garden", func() {
+				options.Output = "json"
+				options.Minify = true
+				options.TargetedGarden = gardenIdentity1
+				Expect(options.Run(nil)).To(Succeed())
+				assertGardenNames(printedConfig(), gardenIdentity1)
+			})
+
+			It("should only print the gardens matching the selector", func() {
+				cfg.Gardens[0].Labels = map[string]string{"env": "prod"}
+				cfg.Gardens[1].Labels = map[string]string{"env": "canary"}
+				options.Output = "json"
+				options.Selector = "env in (prod,dev)"
+				Expect(options.Run(nil)).To(Succeed())
+				assertGardenNames(printedConfig(), gardenIdentity1)
+			})
+
+			It("should fail for an invalid selector", func() {
+				options.Selector = "env in ("
+				Expect(options.Run(nil)).To(MatchError(HavePrefix(`invalid label selector "env in ("`)))
+			})
+
+			It("should fail to minify if no garden is targeted", func() {
+				options.Minify = true
+				Expect(options.Run(nil)).To(MatchError("no garden targeted"))
+			})
+
+			It("should hide the kubeconfig paths", func() {
+				options.Output = "json"
+				options.ShowKubeconfigPaths = false
+				Expect(options.Run(nil)).To(Succeed())
+
+				for _, g := range printedConfig().Gardens {
+					Expect(g.Kubeconfig).To(BeEmpty())
+				}
+
+				Expect(cfg.Gardens[0].Kubeconfig).To(Equal(kubeconfig))
+			})
+
+			It("should print the gardens as table", func() {
+				options.Output = "table"
+				Expect(options.Run(nil)).To(Succeed())
+				Expect(out.String()).To(MatchRegexp(`^IDENTITY\s+ALIASES\s+CONTEXT\s+LABELS\s+KUBECONFIG\n`))
+				Expect(out.String()).To(MatchRegexp(gardenIdentity1 + `\s+` + gardenContext1 + `\s+` + kubeconfig))
+			})
+
+			It("should print the gardens as table without kubeconfig paths", func() {
+				options.Output = "table"
+				options.ShowKubeconfigPaths = false
+				Expect(options.Run(nil)).To(Succeed())
+				Expect(out.String()).NotTo(ContainSubstring("KUBECONFIG"))
+				Expect(out.String()).NotTo(ContainSubstring(kubeconfig))
 			})
 		})
 
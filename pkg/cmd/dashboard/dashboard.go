@@ -0,0 +1,124 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dashboard
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/term"
+
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
+)
+
+// runGardenctl runs gardenctl with the given args as child process that uses the in/out streams
+var runGardenctl = func(args []string, ioStreams util.IOStreams) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to determine the gardenctl executable: %w", err)
+	}
+
+	cmd := exec.Command(executable, args...)
+	cmd.Stdin = ioStreams.In
+	cmd.Stdout = ioStreams.Out
+	cmd.Stderr = util.UnwrapQuietWriter(ioStreams.ErrOut)
+
+	return cmd.Run()
+}
+
+// NewCmdDashboard returns a new dashboard command.
+func NewCmdDashboard(f util.Factory, o *DashboardOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dashboard",
+		Short: "Browse the gardens, projects and shoots in an interactive full-screen dashboard",
+		Long: `Browse the configured gardens, their projects and shoots in an interactive full-screen dashboard.
+The dashboard shows one pane for the gardens, the projects of the opened garden and the shoots of the opened project,
+and the status of the selected shoot. The shoots are refreshed periodically, see --refresh.
+
+Keys:
+  up/down      select a garden, project or shoot
+  right/enter  open the selected garden or project, target the selected shoot
+  left         go back to the previous pane
+  t            target the selected garden, project or shoot
+  s            ssh to the nodes of the selected shoot
+  k            print the kubeconfig of the selected shoot
+  h            hibernate or wake up the selected shoot, after confirmation
+  r            refresh the projects and shoots
+  q            quit the dashboard
+
+The ssh, kubeconfig and hibernation actions run the corresponding gardenctl command for the selected shoot,
+the dashboard is resumed when the command has finished.`,
+		Example: `# open the dashboard and refresh the shoots every 10 seconds
+gardenctl dashboard --refresh 10s`,
+		Args: cobra.NoArgs,
+		RunE: base.WrapRunE(o, f),
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// DashboardOptions is a struct to support dashboard command
+type DashboardOptions struct {
+	base.Options
+
+	// Refresh is the interval in which the projects and shoots are refreshed
+	Refresh time.Duration
+}
+
+// NewDashboardOptions returns initialized DashboardOptions
+func NewDashboardOptions(ioStreams util.IOStreams) *DashboardOptions {
+	return &DashboardOptions{
+		Options: base.Options{
+			IOStreams: ioStreams,
+		},
+		Refresh: 30 * time.Second,
+	}
+}
+
+// AddFlags binds the command options to a given flagset
+func (o *DashboardOptions) AddFlags(flags *pflag.FlagSet) {
+	flags.DurationVar(&o.Refresh, "refresh", o.Refresh, "Interval in which the projects and shoots are refreshed.")
+}
+
+// Validate validates the provided options
+func (o *DashboardOptions) Validate() error {
+	if o.Refresh <= 0 {
+		return errors.New("refresh must be positive")
+	}
+
+	return nil
+}
+
+// Run executes the command
+func (o *DashboardOptions) Run(f util.Factory) error {
+	in, ok := o.IOStreams.In.(*os.File)
+	if !ok || !term.IsTerminal(int(in.Fd())) {
+		return errors.New("the dashboard requires an interactive terminal")
+	}
+
+	manager, err := f.Manager()
+	if err != nil {
+		return err
+	}
+
+	currentTarget, err := manager.CurrentTarget()
+	if err != nil {
+		return fmt.Errorf("failed to get current target: %w", err)
+	}
+
+	d := newDashboard(f.Context(), manager, currentTarget)
+
+	return runTerminal(int(in.Fd()), d, o.IOStreams, o.Refresh)
+}
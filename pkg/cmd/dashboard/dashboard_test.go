@@ -0,0 +1,193 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dashboard_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"time"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	corev1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	internalfake "github.com/gardener/gardenctl-v2/internal/fake"
+	"github.com/gardener/gardenctl-v2/internal/gardenclient"
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/dashboard"
+	"github.com/gardener/gardenctl-v2/pkg/config"
+	"github.com/gardener/gardenctl-v2/pkg/target"
+	targetmocks "github.com/gardener/gardenctl-v2/pkg/target/mocks"
+)
+
+// ansiRegexp matches the escape sequences that style the dashboard
+var ansiRegexp = regexp.MustCompile(`\x1b\[[0-9;?]*[a-zA-Z]`)
+
+var _ = Describe("Dashboard Command", func() {
+	const gardenName = "mygarden"
+
+	var (
+		ctx     context.Context
+		ctrl    *gomock.Controller
+		manager *targetmocks.MockManager
+	)
+
+	newProject := func(name string) *gardencorev1beta1.Project {
+		return &gardencorev1beta1.Project{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       gardencorev1beta1.ProjectSpec{Namespace: pointer.String("garden-" + name)},
+		}
+	}
+
+	newShoot := func(namespace, name, status string, hibernated bool) *gardencorev1beta1.Shoot {
+		return &gardencorev1beta1.Shoot{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels:    map[string]string{corev1beta1constants.ShootStatus: status},
+			},
+			Spec: gardencorev1beta1.ShootSpec{
+				Hibernation: &gardencorev1beta1.Hibernation{Enabled: pointer.Bool(hibernated)},
+				Kubernetes:  gardencorev1beta1.Kubernetes{Version: "1.22.2"},
+				Provider:    gardencorev1beta1.Provider{Type: "aws"},
+				Region:      "eu-west-1",
+				SeedName:    pointer.String("myseed"),
+			},
+			Status: gardencorev1beta1.ShootStatus{
+				LastOperation: &gardencorev1beta1.LastOperation{
+					Type:     gardencorev1beta1.LastOperationTypeReconcile,
+					State:    gardencorev1beta1.LastOperationStateProcessing,
+					Progress: 45,
+				},
+			},
+		}
+	}
+
+	screen := func(d *dashboard.Dashboard) string {
+		return ansiRegexp.ReplaceAllString(d.Render(120, 30), "")
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		ctrl = gomock.NewController(GinkgoT())
+		manager = targetmocks.NewMockManager(ctrl)
+
+		cfg := &config.Config{Gardens: []config.Garden{{Name: "another"}, {Name: gardenName}}}
+		gardenClient := internalfake.NewClientWithObjects(
+			newProject("prod1"),
+			newProject("dev"),
+			newShoot("garden-prod1", "web", "healthy", false),
+			newShoot("garden-prod1", "batch", "unhealthy", true),
+		)
+
+		manager.EXPECT().Configuration().Return(cfg).AnyTimes()
+		manager.EXPECT().GardenClient(gardenName).Return(gardenclient.NewGardenClient(gardenClient), nil).AnyTimes()
+		manager.EXPECT().GardenClient("another").Return(nil, errors.New("connection refused")).AnyTimes()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("should open the garden and project of the current target and select the targeted shoot", func() {
+		d := dashboard.NewDashboard(ctx, manager, target.NewTarget(gardenName, "prod1", "", "web"))
+
+		s := screen(d)
+		Expect(s).To(ContainSubstring(`Target: garden:"mygarden", project:"prod1", shoot:"web"`))
+		Expect(s).To(MatchRegexp(`  another\s+  dev\s+  batch\s+unhealthy \(hibernated\)`))
+		Expect(s).To(MatchRegexp(`> mygarden\s+> prod1\s+> web\s+healthy`))
+		Expect(s).To(ContainSubstring("Name:        web"))
+		Expect(s).To(ContainSubstring("Operation:   Reconcile Processing (45%)"))
+	})
+
+	It("should navigate through the panes and target the selected shoot", func() {
+		d := dashboard.NewDashboard(ctx, manager, target.NewTarget("", "", "", ""))
+		Expect(screen(d)).To(ContainSubstring("Target: <empty>"))
+
+		Expect(d.HandleKey("down")).To(BeNil())
+		Expect(d.HandleKey("enter")).To(BeNil())
+		Expect(d.HandleKey("down")).To(BeNil())
+		Expect(d.HandleKey("right")).To(BeNil())
+		Expect(screen(d)).To(MatchRegexp(`> batch\s+unhealthy \(hibernated\)`))
+
+		manager.EXPECT().ReplaceTarget(ctx, target.NewTarget(gardenName, "prod1", "", "batch")).Return(nil)
+		Expect(d.HandleKey("enter")).To(BeNil())
+		Expect(screen(d)).To(ContainSubstring(`Successfully targeted garden:"mygarden", project:"prod1", shoot:"batch"`))
+
+		Expect(d.HandleKey("left")).To(BeNil())
+		manager.EXPECT().ReplaceTarget(ctx, target.NewTarget(gardenName, "prod1", "", "")).Return(nil)
+		Expect(d.HandleKey("t")).To(BeNil())
+	})
+
+	It("should show the error if a garden cannot be opened", func() {
+		d := dashboard.NewDashboard(ctx, manager, target.NewTarget("", "", "", ""))
+		Expect(d.HandleKey("enter")).To(BeNil())
+		Expect(screen(d)).To(ContainSubstring("Error: failed to list the projects of garden another: connection refused"))
+	})
+
+	It("should return the gardenctl commands of the actions for the selected shoot", func() {
+		d := dashboard.NewDashboard(ctx, manager, target.NewTarget(gardenName, "prod1", "", "web"))
+		shootFlags := []string{"--garden", gardenName, "--project", "prod1", "--shoot", "web"}
+
+		Expect(d.HandleKey("s")).To(Equal(append([]string{"ssh"}, shootFlags...)))
+		Expect(d.HandleKey("k")).To(Equal(append([]string{"kubeconfig"}, shootFlags...)))
+
+		Expect(d.HandleKey("h")).To(BeNil())
+		Expect(screen(d)).To(ContainSubstring("Do you really want to hibernate shoot web? [y/N]"))
+		Expect(d.HandleKey("n")).To(BeNil())
+
+		Expect(d.HandleKey("h")).To(BeNil())
+		Expect(d.HandleKey("y")).To(Equal(append([]string{"shoot", "hibernate"}, shootFlags...)))
+
+		Expect(d.HandleKey("up")).To(BeNil())
+		Expect(d.HandleKey("h")).To(BeNil())
+		Expect(d.HandleKey("y")).To(Equal([]string{"shoot", "wake-up", "--garden", gardenName, "--project", "prod1", "--shoot", "batch"}))
+	})
+
+	It("should require a selected shoot for the actions", func() {
+		d := dashboard.NewDashboard(ctx, manager, target.NewTarget(gardenName, "", "", ""))
+		Expect(d.HandleKey("s")).To(BeNil())
+		Expect(screen(d)).To(ContainSubstring("Select a shoot in the shoots pane first"))
+	})
+
+	It("should quit", func() {
+		d := dashboard.NewDashboard(ctx, manager, target.NewTarget("", "", "", ""))
+		Expect(d.HandleKey("q")).To(BeNil())
+		Expect(d.Quit()).To(BeTrue())
+	})
+
+	It("should decode the keys", func() {
+		Expect(dashboard.DecodeKey([]byte("\x1b[A"))).To(Equal("up"))
+		Expect(dashboard.DecodeKey([]byte("\x1bOB"))).To(Equal("down"))
+		Expect(dashboard.DecodeKey([]byte("\r"))).To(Equal("enter"))
+		Expect(dashboard.DecodeKey([]byte("\x03"))).To(Equal("quit"))
+		Expect(dashboard.DecodeKey([]byte("T"))).To(Equal("t"))
+	})
+
+	Describe("Options", func() {
+		It("should require a positive refresh interval", func() {
+			streams, _, _, _ := util.NewTestIOStreams()
+			o := dashboard.NewDashboardOptions(streams)
+			o.Refresh = 0
+			Expect(o.Validate()).To(MatchError("refresh must be positive"))
+
+			o.Refresh = time.Second
+			Expect(o.Validate()).To(Succeed())
+		})
+
+		It("should require an interactive terminal", func() {
+			streams, _, _, _ := util.NewTestIOStreams()
+			cmd := dashboard.NewCmdDashboard(internalfake.NewFakeFactory(nil, nil, nil, nil), dashboard.NewDashboardOptions(streams))
+			Expect(cmd.RunE(cmd, nil)).To(MatchError("the dashboard requires an interactive terminal"))
+		})
+	})
+})
@@ -0,0 +1,35 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dashboard
+
+import (
+	"context"
+
+	"github.com/gardener/gardenctl-v2/pkg/target"
+)
+
+type Dashboard = dashboard
+
+func NewDashboard(ctx context.Context, manager target.Manager, currentTarget target.Target) *Dashboard {
+	return newDashboard(ctx, manager, currentTarget)
+}
+
+func (d *dashboard) HandleKey(k string) []string {
+	return d.handleKey(key(k))
+}
+
+func (d *dashboard) Render(width, height int) string {
+	return d.render(width, height)
+}
+
+func (d *dashboard) Quit() bool {
+	return d.quit
+}
+
+func DecodeKey(input []byte) string {
+	return string(decodeKey(input))
+}
@@ -0,0 +1,508 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dashboard
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	corev1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/target"
+)
+
+// pane is one of the lists of the dashboard
+type pane int
+
+const (
+	gardensPane pane = iota
+	projectsPane
+	shootsPane
+)
+
+// key is a key press, printable keys are represented by themselves, e.g. t
+type key string
+
+const (
+	keyUp    key = "up"
+	keyDown  key = "down"
+	keyLeft  key = "left"
+	keyRight key = "right"
+	keyEnter key = "enter"
+	keyQuit  key = "quit"
+)
+
+const (
+	reverseVideo = "\x1b[7m"
+	bold         = "\x1b[1m"
+	resetStyle   = "\x1b[0m"
+)
+
+// help is the last line of the dashboard
+const help = "up/down select  right/enter open  left back  t target  s ssh  k kubeconfig  h hibernate/wake up  r refresh  q quit"
+
+// dashboard is the state of the dashboard. It handles the key presses and renders the screen, the terminal
+// handling is done by runTerminal.
+type dashboard struct {
+	ctx     context.Context
+	manager target.Manager
+	target  target.Target
+
+	gardens []string
+	// garden is the name of the garden whose projects are listed
+	garden   string
+	projects []gardencorev1beta1.Project
+	// project is the name of the project whose shoots are listed
+	project string
+	shoots  []gardencorev1beta1.Shoot
+
+	pane    pane
+	cursors [3]int
+	message string
+	// confirm are the args of the gardenctl command that is run if the question in the message is answered with y
+	confirm []string
+	quit    bool
+}
+
+// newDashboard returns a dashboard for the configured gardens. The garden and project of the current target are opened
+// and the targeted shoot is selected.
+func newDashboard(ctx context.Context, manager target.Manager, currentTarget target.Target) *dashboard {
+	d := &dashboard{
+		ctx:     ctx,
+		manager: manager,
+		target:  currentTarget,
+	}
+
+	for _, garden := range manager.Configuration().Gardens {
+		d.gardens = append(d.gardens, garden.Name)
+	}
+
+	if !d.selectGarden(currentTarget.GardenName()) {
+		return d
+	}
+
+	d.openGarden()
+
+	for i, project := range d.projects {
+		if project.Name == currentTarget.ProjectName() {
+			d.cursors[projectsPane] = i
+			d.openProject()
+
+			break
+		}
+	}
+
+	for i, shoot := range d.shoots {
+		if shoot.Name == currentTarget.ShootName() {
+			d.cursors[shootsPane] = i
+		}
+	}
+
+	return d
+}
+
+func (d *dashboard) selectGarden(name string) bool {
+	for i, garden := range d.gardens {
+		if garden == name {
+			d.cursors[gardensPane] = i
+			return true
+		}
+	}
+
+	return false
+}
+
+// handleKey updates the dashboard for the key press. It returns the args of a gardenctl command
+// if the key triggers an action that has to be run outside of the dashboard.
+func (d *dashboard) handleKey(k key) []string {
+	if d.confirm != nil {
+		args := d.confirm
+		d.confirm = nil
+		d.message = ""
+
+		if k == "y" {
+			return args
+		}
+
+		return nil
+	}
+
+	d.message = ""
+
+	switch k {
+	case keyQuit, "q":
+		d.quit = true
+	case keyUp:
+		if d.cursors[d.pane] > 0 {
+			d.cursors[d.pane]--
+		}
+	case keyDown:
+		if d.cursors[d.pane] < d.length(d.pane)-1 {
+			d.cursors[d.pane]++
+		}
+	case keyLeft:
+		if d.pane > gardensPane {
+			d.pane--
+		}
+	case keyRight, keyEnter:
+		switch d.pane {
+		case gardensPane:
+			d.openGarden()
+		case projectsPane:
+			d.openProject()
+		case shootsPane:
+			if k == keyEnter {
+				d.targetSelection()
+			}
+		}
+	case "t":
+		d.targetSelection()
+	case "r":
+		d.refresh()
+	case "s":
+		return d.shootCommand("ssh")
+	case "k":
+		return d.shootCommand("kubeconfig")
+	case "h":
+		shoot := d.selectedShoot()
+		if shoot == nil {
+			d.message = "Select a shoot in the shoots pane first"
+			return nil
+		}
+
+		action := "hibernate"
+		if util.IsHibernated(*shoot) {
+			action = "wake-up"
+		}
+
+		d.confirm = d.shootCommand("shoot", action)
+		d.message = fmt.Sprintf("Do you really want to %s shoot %s? [y/N]", action, shoot.Name)
+	}
+
+	return nil
+}
+
+// length returns the number of entries of the pane
+func (d *dashboard) length(p pane) int {
+	switch p {
+	case gardensPane:
+		return len(d.gardens)
+	case projectsPane:
+		return len(d.projects)
+	default:
+		return len(d.shoots)
+	}
+}
+
+// selectedShoot returns the selected shoot if the shoots pane is active
+func (d *dashboard) selectedShoot() *gardencorev1beta1.Shoot {
+	if d.pane != shootsPane || len(d.shoots) == 0 {
+		return nil
+	}
+
+	return &d.shoots[d.cursors[shootsPane]]
+}
+
+// shootCommand returns the args of the gardenctl command for the selected shoot
+func (d *dashboard) shootCommand(args ...string) []string {
+	shoot := d.selectedShoot()
+	if shoot == nil {
+		d.message = "Select a shoot in the shoots pane first"
+		return nil
+	}
+
+	return append(args, "--garden", d.garden, "--project", d.project, "--shoot", shoot.Name)
+}
+
+// openGarden lists the projects of the selected garden and activates the projects pane
+func (d *dashboard) openGarden() {
+	if len(d.gardens) == 0 {
+		return
+	}
+
+	d.garden = d.gardens[d.cursors[gardensPane]]
+	d.project = ""
+	d.projects = nil
+	d.shoots = nil
+	d.cursors[projectsPane] = 0
+	d.cursors[shootsPane] = 0
+
+	if err := d.loadProjects(); err != nil {
+		d.message = fmt.Sprintf("Error: failed to list the projects of garden %s: %v", d.garden, err)
+		return
+	}
+
+	d.pane = projectsPane
+}
+
+// openProject lists the shoots of the selected project and activates the shoots pane
+func (d *dashboard) openProject() {
+	if len(d.projects) == 0 {
+		return
+	}
+
+	d.project = d.projects[d.cursors[projectsPane]].Name
+	d.shoots = nil
+	d.cursors[shootsPane] = 0
+
+	if err := d.loadShoots(); err != nil {
+		d.message = fmt.Sprintf("Error: failed to list the shoots of project %s: %v", d.project, err)
+		return
+	}
+
+	d.pane = shootsPane
+}
+
+// refresh lists the projects of the opened garden and the shoots of the opened project again
+func (d *dashboard) refresh() {
+	if d.garden != "" {
+		if err := d.loadProjects(); err != nil {
+			d.message = fmt.Sprintf("Error: failed to list the projects of garden %s: %v", d.garden, err)
+			return
+		}
+	}
+
+	if d.project != "" {
+		if err := d.loadShoots(); err != nil {
+			d.message = fmt.Sprintf("Error: failed to list the shoots of project %s: %v", d.project, err)
+		}
+	}
+}
+
+func (d *dashboard) loadProjects() error {
+	gardenClient, err := d.manager.GardenClient(d.garden)
+	if err != nil {
+		return err
+	}
+
+	projectList, err := gardenClient.ListProjects(d.ctx)
+	if err != nil {
+		return err
+	}
+
+	d.projects = projectList.Items
+	sort.Slice(d.projects, func(i, j int) bool {
+		return d.projects[i].Name < d.projects[j].Name
+	})
+	d.clampCursor(projectsPane)
+
+	return nil
+}
+
+func (d *dashboard) loadShoots() error {
+	gardenClient, err := d.manager.GardenClient(d.garden)
+	if err != nil {
+		return err
+	}
+
+	var namespace string
+
+	for _, project := range d.projects {
+		if project.Name == d.project && project.Spec.Namespace != nil {
+			namespace = *project.Spec.Namespace
+		}
+	}
+
+	// the namespace of a new project may not yet be set
+	if namespace == "" {
+		d.shoots = nil
+		return nil
+	}
+
+	shootList, err := gardenClient.ListShoots(d.ctx, client.InNamespace(namespace))
+	if err != nil {
+		return err
+	}
+
+	d.shoots = shootList.Items
+	sort.Slice(d.shoots, func(i, j int) bool {
+		return d.shoots[i].Name < d.shoots[j].Name
+	})
+	d.clampCursor(shootsPane)
+
+	return nil
+}
+
+// clampCursor keeps the cursor of the pane within its entries, e.g. after a refresh
+func (d *dashboard) clampCursor(p pane) {
+	if d.cursors[p] >= d.length(p) {
+		d.cursors[p] = d.length(p) - 1
+	}
+
+	if d.cursors[p] < 0 {
+		d.cursors[p] = 0
+	}
+}
+
+// targetSelection targets the selected garden, project or shoot of the active pane
+func (d *dashboard) targetSelection() {
+	var t target.Target
+
+	switch d.pane {
+	case gardensPane:
+		if len(d.gardens) == 0 {
+			return
+		}
+
+		t = target.NewTarget(d.gardens[d.cursors[gardensPane]], "", "", "")
+	case projectsPane:
+		if len(d.projects) == 0 {
+			return
+		}
+
+		t = target.NewTarget(d.garden, d.projects[d.cursors[projectsPane]].Name, "", "")
+	case shootsPane:
+		if len(d.shoots) == 0 {
+			return
+		}
+
+		t = target.NewTarget(d.garden, d.project, "", d.shoots[d.cursors[shootsPane]].Name)
+	}
+
+	if err := d.manager.ReplaceTarget(d.ctx, t); err != nil {
+		d.message = fmt.Sprintf("Error: failed to target %s: %v", t, err)
+		return
+	}
+
+	d.target = t
+	d.message = fmt.Sprintf("Successfully targeted %s", t)
+}
+
+// render returns the screen of the dashboard for a terminal of the given size, the lines are separated by \r\n
+// because the terminal is in raw mode
+func (d *dashboard) render(width, height int) string {
+	lines := []string{
+		bold + cell("gardenctl dashboard", width) + resetStyle,
+		cell(fmt.Sprintf("Target: %s", d.target), width),
+		"",
+	}
+
+	gardensWidth := width / 5
+	projectsWidth := width / 4
+	shootsWidth := width - gardensWidth - projectsWidth
+
+	lines = append(lines,
+		bold+cell("GARDENS", gardensWidth)+cell(fmt.Sprintf("PROJECTS %s", d.garden), projectsWidth)+cell(fmt.Sprintf("SHOOTS %s", d.project), shootsWidth)+resetStyle,
+	)
+
+	// the header, pane titles, shoot details, message and help use 14 lines
+	listHeight := height - 14
+	if listHeight < 3 {
+		listHeight = 3
+	}
+
+	gardens := d.paneLines(gardensPane, d.gardens, gardensWidth, listHeight)
+
+	projectNames := make([]string, len(d.projects))
+	for i, project := range d.projects {
+		projectNames[i] = project.Name
+	}
+
+	projects := d.paneLines(projectsPane, projectNames, projectsWidth, listHeight)
+
+	shootRows := make([]string, len(d.shoots))
+	for i, shoot := range d.shoots {
+		shootRows[i] = fmt.Sprintf("%-20s %s", shoot.Name, util.ShootStatus(shoot))
+	}
+
+	shoots := d.paneLines(shootsPane, shootRows, shootsWidth, listHeight)
+
+	for i := 0; i < listHeight; i++ {
+		lines = append(lines, gardens[i]+projects[i]+shoots[i])
+	}
+
+	lines = append(lines, "", bold+cell("SHOOT", width)+resetStyle)
+	lines = append(lines, d.shootDetails(width)...)
+	lines = append(lines, "", cell(d.message, width), cell(help, width))
+
+	return strings.Join(lines, "\r\n")
+}
+
+// paneLines returns the lines of a pane, the list is scrolled so that the cursor is visible
+func (d *dashboard) paneLines(p pane, entries []string, width, height int) []string {
+	cursor := d.cursors[p]
+
+	offset := 0
+	if cursor >= height {
+		offset = cursor - height + 1
+	}
+
+	lines := make([]string, height)
+
+	for i := range lines {
+		j := offset + i
+		if j >= len(entries) {
+			lines[i] = cell("", width)
+			continue
+		}
+
+		if j != cursor {
+			lines[i] = cell("  "+entries[j], width)
+			continue
+		}
+
+		lines[i] = cell("> "+entries[j], width)
+		if p == d.pane {
+			lines[i] = reverseVideo + lines[i] + resetStyle
+		}
+	}
+
+	return lines
+}
+
+// shootDetails returns the status lines of the selected shoot
+func (d *dashboard) shootDetails(width int) []string {
+	details := make([]string, 7)
+
+	if len(d.shoots) > 0 {
+		shoot := d.shoots[d.cursors[shootsPane]]
+
+		seed := "<none>"
+		if shoot.Spec.SeedName != nil {
+			seed = *shoot.Spec.SeedName
+		}
+
+		details = []string{
+			fmt.Sprintf("Name:        %s", shoot.Name),
+			fmt.Sprintf("Seed:        %s", seed),
+			fmt.Sprintf("Provider:    %s %s", shoot.Spec.Provider.Type, shoot.Spec.Region),
+			fmt.Sprintf("Version:     %s", shoot.Spec.Kubernetes.Version),
+			fmt.Sprintf("Status:      %s", util.ValueOrNone(shoot.Labels[corev1beta1constants.ShootStatus])),
+			fmt.Sprintf("Operation:   %s", lastOperation(shoot)),
+			fmt.Sprintf("Hibernated:  %t", util.IsHibernated(shoot)),
+		}
+	}
+
+	for i := range details {
+		details[i] = cell(details[i], width)
+	}
+
+	return details
+}
+
+func lastOperation(shoot gardencorev1beta1.Shoot) string {
+	if op := shoot.Status.LastOperation; op != nil {
+		return fmt.Sprintf("%s %s (%d%%)", op.Type, op.State, op.Progress)
+	}
+
+	return "<none>"
+}
+
+// cell truncates or pads the value to the given width
+func cell(value string, width int) string {
+	runes := []rune(value)
+	if len(runes) > width {
+		return string(runes[:width])
+	}
+
+	return value + strings.Repeat(" ", width-len(runes))
+}
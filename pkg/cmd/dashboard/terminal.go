@@ -0,0 +1,160 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dashboard
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+
+	"github.com/gardener/gardenctl-v2/internal/util"
+)
+
+const (
+	// enterFullScreen switches to the alternate screen of the terminal and hides the cursor
+	enterFullScreen = "\x1b[?1049h\x1b[?25l"
+	// leaveFullScreen shows the cursor and switches back to the normal screen of the terminal
+	leaveFullScreen = "\x1b[?25h\x1b[?1049l"
+	// clearScreen moves the cursor to the top left corner and clears the screen
+	clearScreen = "\x1b[H\x1b[2J"
+)
+
+// runTerminal shows the dashboard full-screen until it is quit. The terminal is put into raw mode, so that the
+// keys are read one by one. The dashboard is refreshed in the given interval.
+func runTerminal(fd int, d *dashboard, ioStreams util.IOStreams, refresh time.Duration) error {
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return fmt.Errorf("failed to put the terminal into raw mode: %w", err)
+	}
+
+	fmt.Fprint(ioStreams.Out, enterFullScreen)
+
+	defer func() {
+		fmt.Fprint(ioStreams.Out, leaveFullScreen)
+		_ = term.Restore(fd, state)
+	}()
+
+	// a key is only read on request, so that no input is taken away from the commands run by the dashboard
+	requests := make(chan struct{})
+	keys := make(chan key, 1)
+	errs := make(chan error, 1)
+
+	defer close(requests)
+
+	go readKeys(ioStreams.In, requests, keys, errs)
+
+	ticker := time.NewTicker(refresh)
+	defer ticker.Stop()
+
+	requests <- struct{}{}
+
+	for {
+		// the size is unknown for some pseudo terminals, e.g. in CI
+		width, height, err := term.GetSize(fd)
+		if err != nil || width <= 0 || height <= 0 {
+			width, height = 80, 24
+		}
+
+		fmt.Fprint(ioStreams.Out, clearScreen+d.render(width, height))
+
+		select {
+		case <-d.ctx.Done():
+			return nil
+		case err := <-errs:
+			return err
+		case <-ticker.C:
+			d.refresh()
+		case k := <-keys:
+			args := d.handleKey(k)
+			if d.quit {
+				return nil
+			}
+
+			if args != nil {
+				if state, err = runOutsideDashboard(fd, state, args, ioStreams, requests, keys, errs); err != nil {
+					return err
+				}
+
+				d.refresh()
+			}
+
+			requests <- struct{}{}
+		}
+	}
+}
+
+// runOutsideDashboard restores the terminal, runs gardenctl with the given args and waits for enter before the
+// dashboard is shown again. It returns the state of the terminal before it was put into raw mode again.
+func runOutsideDashboard(fd int, state *term.State, args []string, ioStreams util.IOStreams, requests chan<- struct{}, keys <-chan key, errs <-chan error) (*term.State, error) {
+	fmt.Fprint(ioStreams.Out, leaveFullScreen)
+
+	if err := term.Restore(fd, state); err != nil {
+		return nil, fmt.Errorf("failed to restore the terminal: %w", err)
+	}
+
+	fmt.Fprintf(ioStreams.Out, "$ gardenctl %s\n", strings.Join(args, " "))
+
+	if err := runGardenctl(args, ioStreams); err != nil {
+		fmt.Fprintf(ioStreams.ErrOut, "Error: %v\n", err)
+	}
+
+	fmt.Fprint(ioStreams.Out, "\nPress enter to return to the dashboard")
+
+	requests <- struct{}{}
+	select {
+	case <-keys:
+	case err := <-errs:
+		return nil, err
+	}
+
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to put the terminal into raw mode: %w", err)
+	}
+
+	fmt.Fprint(ioStreams.Out, enterFullScreen)
+
+	return state, nil
+}
+
+// readKeys reads a key for every request until the requests channel is closed
+func readKeys(in io.Reader, requests <-chan struct{}, keys chan<- key, errs chan<- error) {
+	buf := make([]byte, 16)
+
+	for range requests {
+		n, err := in.Read(buf)
+		if err != nil {
+			errs <- err
+			return
+		}
+
+		keys <- decodeKey(buf[:n])
+	}
+}
+
+// decodeKey returns the key of the input, arrow keys are sent as escape sequences
+func decodeKey(input []byte) key {
+	switch string(input) {
+	case "\x1b[A", "\x1bOA":
+		return keyUp
+	case "\x1b[B", "\x1bOB":
+		return keyDown
+	case "\x1b[C", "\x1bOC":
+		return keyRight
+	case "\x1b[D", "\x1bOD":
+		return keyLeft
+	case "\r", "\n", "\r\n":
+		return keyEnter
+	case "\x03", "\x04":
+		return keyQuit
+	}
+
+	return key(strings.ToLower(string(input)))
+}
@@ -0,0 +1,25 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package describe
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/gardener/gardenctl-v2/internal/util"
+)
+
+// NewCmdDescribe returns a new describe command.
+func NewCmdDescribe(f util.Factory, ioStreams util.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "describe",
+		Short: "Show details of a Gardener resource",
+	}
+
+	cmd.AddCommand(NewCmdDescribeProject(f, NewProjectOptions(ioStreams)))
+
+	return cmd
+}
@@ -0,0 +1,364 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package describe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	corev1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/gardener/gardenctl-v2/internal/gardenclient"
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
+	"github.com/gardener/gardenctl-v2/pkg/target"
+)
+
+// NewCmdDescribeProject returns a new describe project command.
+func NewCmdDescribeProject(f util.Factory, o *ProjectOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "project [NAME]",
+		Short: "Show details of a project",
+		Long: `Show the namespace, owner, members, quotas and number of shoots of a project of the targeted garden.
+If no project name is given, the targeted project is described.`,
+		Example: `# describe the targeted project
+gardenctl describe project
+
+# describe the project my-project as yaml, e.g. for reporting scripts
+gardenctl describe project my-project -o yaml`,
+		Args: cobra.MaximumNArgs(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) != 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+
+			manager, err := f.Manager()
+			if err != nil {
+				fmt.Fprintln(o.IOStreams.ErrOut, err.Error())
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+
+			names, err := util.ProjectNamesForTarget(f.Context(), manager)
+			if err != nil {
+				fmt.Fprintln(o.IOStreams.ErrOut, err.Error())
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+
+			return util.FilterStringsByPrefix(toComplete, names), cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: base.WrapRunE(o, f),
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// ProjectOptions is a struct to support describe project command
+type ProjectOptions struct {
+	base.Options
+
+	// ProjectName is the name of the project that is described
+	ProjectName string
+}
+
+// NewProjectOptions returns initialized ProjectOptions
+func NewProjectOptions(ioStreams util.IOStreams) *ProjectOptions {
+	return &ProjectOptions{
+		Options: base.Options{
+			IOStreams: ioStreams,
+		},
+	}
+}
+
+// ProjectDescription is the machine-readable output of describe project
+type ProjectDescription struct {
+	// Name is the name of the project
+	Name string `json:"name" yaml:"name"`
+	// Namespace is the namespace of the project
+	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	// Owner is the name of the owner of the project
+	Owner string `json:"owner,omitempty" yaml:"owner,omitempty"`
+	// CreatedBy is the name of the user that created the project
+	CreatedBy string `json:"createdBy,omitempty" yaml:"createdBy,omitempty"`
+	// Description is the description of the project
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	// Purpose is the purpose of the project
+	Purpose string `json:"purpose,omitempty" yaml:"purpose,omitempty"`
+	// CreationTimestamp is the time the project was created
+	CreationTimestamp metav1.Time `json:"creationTimestamp" yaml:"creationTimestamp"`
+	// Members are the members of the project
+	Members []ProjectMember `json:"members" yaml:"members"`
+	// Quotas are the quotas referenced by the secret bindings of the project
+	Quotas []ProjectQuota `json:"quotas" yaml:"quotas"`
+	// Shoots are the number of shoots of the project
+	Shoots ShootCounts `json:"shoots" yaml:"shoots"`
+}
+
+// ProjectMember is a member of a project
+type ProjectMember struct {
+	// Name is the name of the member
+	Name string `json:"name" yaml:"name"`
+	// Kind is the kind of the member, i.e. User, Group or ServiceAccount
+	Kind string `json:"kind" yaml:"kind"`
+	// Roles are the roles of the member
+	Roles []string `json:"roles" yaml:"roles"`
+}
+
+// ProjectQuota is a quota that limits the resources of a project
+type ProjectQuota struct {
+	// Name is the name of the quota
+	Name string `json:"name" yaml:"name"`
+	// Namespace is the namespace of the quota
+	Namespace string `json:"namespace" yaml:"namespace"`
+	// ClusterLifetimeDays is the lifetime of the shoots in days
+	ClusterLifetimeDays *int32 `json:"clusterLifetimeDays,omitempty" yaml:"clusterLifetimeDays,omitempty"`
+	// Metrics are the limited resources and their limits
+	Metrics map[string]string `json:"metrics,omitempty" yaml:"metrics,omitempty"`
+}
+
+// ShootCounts are the number of shoots of a project
+type ShootCounts struct {
+	// Total is the number of all shoots
+	Total int `json:"total" yaml:"total"`
+	// Hibernated is the number of hibernated shoots
+	Hibernated int `json:"hibernated" yaml:"hibernated"`
+	// Unhealthy is the number of shoots with the status label unhealthy
+	Unhealthy int `json:"unhealthy" yaml:"unhealthy"`
+}
+
+// Complete adapts from the command line args to the data required.
+func (o *ProjectOptions) Complete(f util.Factory, cmd *cobra.Command, args []string) error {
+	if len(args) > 0 {
+		o.ProjectName = strings.TrimSpace(args[0])
+	}
+
+	return nil
+}
+
+// Run executes the command
+func (o *ProjectOptions) Run(f util.Factory) error {
+	manager, err := f.Manager()
+	if err != nil {
+		return err
+	}
+
+	currentTarget, err := manager.CurrentTarget()
+	if err != nil {
+		return fmt.Errorf("failed to get current target: %w", err)
+	}
+
+	if currentTarget.GardenName() == "" {
+		return target.ErrNoGardenTargeted
+	}
+
+	projectName := o.ProjectName
+	if projectName == "" {
+		projectName = currentTarget.ProjectName()
+	}
+
+	if projectName == "" {
+		return errors.New("no project name given and no project targeted")
+	}
+
+	gardenClient, err := manager.GardenClient(currentTarget.GardenName())
+	if err != nil {
+		return fmt.Errorf("failed to create garden cluster client: %w", err)
+	}
+
+	description, err := describeProject(f.Context(), gardenClient, projectName)
+	if err != nil {
+		return err
+	}
+
+	if o.Output != "" {
+		return o.PrintObject(description)
+	}
+
+	printProjectDescription(o.IOStreams.Out, description)
+
+	return nil
+}
+
+// describeProject collects the details of the project with the given name
+func describeProject(ctx context.Context, gardenClient gardenclient.Client, name string) (*ProjectDescription, error) {
+	project, err := gardenClient.GetProject(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	description := &ProjectDescription{
+		Name:              project.Name,
+		CreationTimestamp: project.CreationTimestamp,
+		Members:           []ProjectMember{},
+		Quotas:            []ProjectQuota{},
+	}
+
+	if project.Spec.Owner != nil {
+		description.Owner = project.Spec.Owner.Name
+	}
+
+	if project.Spec.CreatedBy != nil {
+		description.CreatedBy = project.Spec.CreatedBy.Name
+	}
+
+	if project.Spec.Description != nil {
+		description.Description = *project.Spec.Description
+	}
+
+	if project.Spec.Purpose != nil {
+		description.Purpose = *project.Spec.Purpose
+	}
+
+	for _, member := range project.Spec.Members {
+		roles := []string{}
+		if member.Role != "" {
+			roles = append(roles, member.Role)
+		}
+
+		roles = append(roles, member.Roles...)
+
+		description.Members = append(description.Members, ProjectMember{
+			Name:  member.Name,
+			Kind:  member.Kind,
+			Roles: roles,
+		})
+	}
+
+	if project.Spec.Namespace == nil {
+		return description, nil
+	}
+
+	description.Namespace = *project.Spec.Namespace
+
+	shootList, err := gardenClient.ListShoots(ctx, client.InNamespace(description.Namespace))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, shoot := range shootList.Items {
+		description.Shoots.Total++
+
+		if shoot.Spec.Hibernation != nil && shoot.Spec.Hibernation.Enabled != nil && *shoot.Spec.Hibernation.Enabled {
+			description.Shoots.Hibernated++
+		}
+
+		if shoot.Labels[corev1beta1constants.ShootStatus] == "unhealthy" {
+			description.Shoots.Unhealthy++
+		}
+	}
+
+	description.Quotas, err = projectQuotas(ctx, gardenClient.RuntimeClient(), description.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return description, nil
+}
+
+// projectQuotas returns the quotas that are referenced by the secret bindings in the given namespace
+func projectQuotas(ctx context.Context, c client.Client, namespace string) ([]ProjectQuota, error) {
+	secretBindingList := &gardencorev1beta1.SecretBindingList{}
+	if err := c.List(ctx, secretBindingList, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list secret bindings in namespace %s: %w", namespace, err)
+	}
+
+	keys := map[types.NamespacedName]bool{}
+
+	for _, secretBinding := range secretBindingList.Items {
+		for _, ref := range secretBinding.Quotas {
+			key := types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}
+			if key.Namespace == "" {
+				key.Namespace = namespace
+			}
+
+			keys[key] = true
+		}
+	}
+
+	quotas := []ProjectQuota{}
+
+	for key := range keys {
+		quota := &gardencorev1beta1.Quota{}
+		if err := c.Get(ctx, key, quota); err != nil {
+			return nil, fmt.Errorf("failed to get quota %v: %w", key, err)
+		}
+
+		metrics := map[string]string{}
+		for resourceName, quantity := range quota.Spec.Metrics {
+			metrics[string(resourceName)] = quantity.String()
+		}
+
+		quotas = append(quotas, ProjectQuota{
+			Name:                quota.Name,
+			Namespace:           quota.Namespace,
+			ClusterLifetimeDays: quota.Spec.ClusterLifetimeDays,
+			Metrics:             metrics,
+		})
+	}
+
+	sort.Slice(quotas, func(i, j int) bool {
+		if quotas[i].Namespace != quotas[j].Namespace {
+			return quotas[i].Namespace < quotas[j].Namespace
+		}
+
+		return quotas[i].Name < quotas[j].Name
+	})
+
+	return quotas, nil
+}
+
+// printProjectDescription prints the project description in a human-readable format
+func printProjectDescription(out io.Writer, d *ProjectDescription) {
+	fmt.Fprintf(out, "Name:         %s\n", d.Name)
+	fmt.Fprintf(out, "Namespace:    %s\n", util.ValueOrNone(d.Namespace))
+	fmt.Fprintf(out, "Owner:        %s\n", util.ValueOrNone(d.Owner))
+	fmt.Fprintf(out, "Created By:   %s\n", util.ValueOrNone(d.CreatedBy))
+	fmt.Fprintf(out, "Description:  %s\n", util.ValueOrNone(d.Description))
+	fmt.Fprintf(out, "Purpose:      %s\n", util.ValueOrNone(d.Purpose))
+	fmt.Fprintf(out, "Shoots:       %d (%d hibernated, %d unhealthy)\n", d.Shoots.Total, d.Shoots.Hibernated, d.Shoots.Unhealthy)
+
+	fmt.Fprintln(out, "Members:")
+
+	if len(d.Members) == 0 {
+		fmt.Fprintln(out, "  <none>")
+	}
+
+	for _, member := range d.Members {
+		fmt.Fprintf(out, "  %s (%s): %s\n", member.Name, member.Kind, strings.Join(member.Roles, ", "))
+	}
+
+	fmt.Fprintln(out, "Quotas:")
+
+	if len(d.Quotas) == 0 {
+		fmt.Fprintln(out, "  <none>")
+	}
+
+	for _, quota := range d.Quotas {
+		limits := []string{}
+		for name, value := range quota.Metrics {
+			limits = append(limits, fmt.Sprintf("%s=%s", name, value))
+		}
+
+		sort.Strings(limits)
+
+		if quota.ClusterLifetimeDays != nil {
+			limits = append(limits, fmt.Sprintf("clusterLifetimeDays=%d", *quota.ClusterLifetimeDays))
+		}
+
+		fmt.Fprintf(out, "  %s/%s: %s\n", quota.Namespace, quota.Name, strings.Join(limits, ", "))
+	}
+}
@@ -0,0 +1,151 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package describe_test
+
+import (
+	"context"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	corev1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	internalfake "github.com/gardener/gardenctl-v2/internal/fake"
+	"github.com/gardener/gardenctl-v2/internal/util"
+	cmddescribe "github.com/gardener/gardenctl-v2/pkg/cmd/describe"
+	"github.com/gardener/gardenctl-v2/pkg/config"
+	"github.com/gardener/gardenctl-v2/pkg/target"
+	targetmocks "github.com/gardener/gardenctl-v2/pkg/target/mocks"
+)
+
+var _ = Describe("Describe Project Command", func() {
+	const gardenName = "mygarden"
+
+	var (
+		ctrl           *gomock.Controller
+		streams        util.IOStreams
+		out            *util.SafeBytesBuffer
+		factory        *internalfake.Factory
+		targetProvider *internalfake.TargetProvider
+		options        *cmddescribe.ProjectOptions
+	)
+
+	BeforeEach(func() {
+		cfg := &config.Config{
+			LinkKubeconfig: pointer.Bool(false),
+			Gardens: []config.Garden{{
+				Name:       gardenName,
+				Kubeconfig: "/not/a/real/kubeconfig",
+			}},
+		}
+
+		project := &gardencorev1beta1.Project{
+			ObjectMeta: metav1.ObjectMeta{Name: "prod1"},
+			Spec: gardencorev1beta1.ProjectSpec{
+				Namespace:   pointer.String("garden-prod1"),
+				Owner:       &rbacv1.Subject{Kind: rbacv1.UserKind, Name: "alice"},
+				Description: pointer.String("Production clusters"),
+				Members: []gardencorev1beta1.ProjectMember{
+					{Subject: rbacv1.Subject{Kind: rbacv1.UserKind, Name: "alice"}, Role: "admin", Roles: []string{"owner"}},
+					{Subject: rbacv1.Subject{Kind: rbacv1.GroupKind, Name: "operators"}, Role: "viewer"},
+				},
+			},
+		}
+
+		secretBinding := &gardencorev1beta1.SecretBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "aws", Namespace: "garden-prod1"},
+			Quotas:     []corev1.ObjectReference{{Name: "trial", Namespace: "garden"}},
+		}
+
+		quota := &gardencorev1beta1.Quota{
+			ObjectMeta: metav1.ObjectMeta{Name: "trial", Namespace: "garden"},
+			Spec: gardencorev1beta1.QuotaSpec{
+				ClusterLifetimeDays: pointer.Int32(14),
+				Metrics:             corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("200")},
+			},
+		}
+
+		hibernated := &gardencorev1beta1.Shoot{
+			ObjectMeta: metav1.ObjectMeta{Name: "batch", Namespace: "garden-prod1"},
+			Spec: gardencorev1beta1.ShootSpec{
+				Hibernation: &gardencorev1beta1.Hibernation{Enabled: pointer.Bool(true)},
+			},
+		}
+
+		unhealthy := &gardencorev1beta1.Shoot{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "web",
+				Namespace: "garden-prod1",
+				Labels:    map[string]string{corev1beta1constants.ShootStatus: "unhealthy"},
+			},
+		}
+
+		gardenClient := internalfake.NewClientWithObjects(project, secretBinding, quota, hibernated, unhealthy)
+
+		streams, _, out, _ = util.NewTestIOStreams()
+
+		ctrl = gomock.NewController(GinkgoT())
+
+		clientProvider := targetmocks.NewMockClientProvider(ctrl)
+		clientConfig, err := cfg.ClientConfig(gardenName)
+		Expect(err).ToNot(HaveOccurred())
+		clientProvider.EXPECT().FromClientConfig(gomock.Eq(clientConfig)).Return(gardenClient, nil).AnyTimes()
+
+		targetProvider = internalfake.NewFakeTargetProvider(target.NewTarget(gardenName, "prod1", "", ""))
+
+		factory = internalfake.NewFakeFactory(cfg, nil, clientProvider, targetProvider)
+		factory.ContextImpl = context.Background()
+
+		options = cmddescribe.NewProjectOptions(streams)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("should describe the targeted project", func() {
+		cmd := cmddescribe.NewCmdDescribeProject(factory, options)
+		Expect(cmd.RunE(cmd, nil)).To(Succeed())
+		Expect(out.String()).To(Equal(`Name:         prod1
+Namespace:    garden-prod1
+Owner:        alice
+Created By:   <none>
+Description:  Production clusters
+Purpose:      <none>
+Shoots:       2 (1 hibernated, 1 unhealthy)
+Members:
+  alice (User): admin, owner
+  operators (Group): viewer
+Quotas:
+  garden/trial: cpu=200, clusterLifetimeDays=14
+`))
+	})
+
+	It("should describe the given project as json", func() {
+		targetProvider.Target = target.NewTarget(gardenName, "", "", "")
+		options.Output = "json"
+		cmd := cmddescribe.NewCmdDescribeProject(factory, options)
+		Expect(cmd.RunE(cmd, []string{"prod1"})).To(Succeed())
+		Expect(out.String()).To(ContainSubstring(`"shoots": {
+    "total": 2,
+    "hibernated": 1,
+    "unhealthy": 1
+  }`))
+	})
+
+	It("should fail if no project is given or targeted", func() {
+		targetProvider.Target = target.NewTarget(gardenName, "", "", "")
+		cmd := cmddescribe.NewCmdDescribeProject(factory, options)
+		Expect(cmd.RunE(cmd, nil)).To(MatchError("no project name given and no project targeted"))
+	})
+})
@@ -34,7 +34,16 @@ The generated script sets the environment variables for the cloud provider CLI o
 In addition, the Azure CLI requires to sign in with a service principal and the gcloud CLI requires to activate a service-account.
 Thereby the configuration location of the corresponding cloud provider CLI is pointed to a temporary folder in the
 session directory, so that the standard configuration files in the user's home folder are not affected.
-By using the --unset flag you can force a logout or revoke the service-account.
+The gcloud configuration folder is created per targeted shoot, as it also holds the activated service-account.
+By using the --unset flag you can force a logout or revoke the service-account. The gcloud configuration folder
+of the targeted shoot is removed as well.
+
+If the shoot references a workload identity instead of static credentials, a short-lived token is requested from the
+Gardener API and stored in the session directory. The cloud provider CLI exchanges the token for temporary credentials.
+Workload identities are supported for aws, azure and gcp. The token expires after one hour, run the command again to renew it.
+
+For openstack shoots the --output-format clouds-yaml flag writes a clouds.yaml file for the targeted shoot to the
+session directory and sets the OS_CLIENT_CONFIG_FILE and OS_CLOUD environment variables instead of the credentials.
 
 The CLI of a corresponding cloud provider must be installed.
 Please refer to the installation instructions of the respective provider:
@@ -46,7 +55,7 @@ Please refer to the installation instructions of the respective provider:
 * Hetzner cloud (hcloud) - https://community.hetzner.com/tutorials/howto-hcloud-cli
 
 To overwrite the default templates or add support for custom (out of tree) cloud providers place a template
-for the respective provider in the "templates" folder of the gardenctl home directory ($GCTL_HOME or $HOME/.garden).
+for the respective provider in the "templates" folder of the gardenctl home directory ($GCTL_HOME, $XDG_CONFIG_HOME/gardenctl on Linux or $HOME/.garden).
 Please refer to the templates of the already supported cloud providers which can be found
 here https://github.com/gardener/gardenctl-v2/tree/master/pkg/cmd/env/templates.`,
 		Aliases: []string{"p-env", "cloud-env"},
@@ -84,6 +93,7 @@ func NewCmdKubectlEnv(f util.Factory, ioStreams util.IOStreams) *cobra.Command {
 See each sub-command's help for details on how to use the generated script.
 
 The generated script points the KUBECONFIG environment variable to the currently targeted shoot, seed or garden cluster.
+With the --unset flag, the generated script unsets the KUBECONFIG environment variable, which does not require a target.
 `,
 		Aliases: []string{"k-env", "cluster-env"},
 	}
@@ -9,15 +9,18 @@ package env
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path"
 	"path/filepath"
 	"runtime"
+	"time"
 
 	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
 	corev1 "k8s.io/api/core/v1"
 
 	"github.com/gardener/gardenctl-v2/internal/gardenclient"
@@ -47,8 +50,17 @@ type options struct {
 	Template Template
 	// Symlink indicates if KUBECONFIG environment variable should point to the session stable symlink
 	Symlink bool
+	// OutputFormat is the format of the cloud provider CLI configuration, either env or clouds-yaml
+	OutputFormat string
 }
 
+const (
+	// outputFormatEnv configures the cloud provider CLI with environment variables
+	outputFormatEnv = "env"
+	// outputFormatCloudsYAML writes a clouds.yaml file for the openstack CLI and configures it with the OS_CLOUD environment variable
+	outputFormatCloudsYAML = "clouds-yaml"
+)
+
 // Complete adapts from the command line args to the data required.
 func (o *options) Complete(f util.Factory, cmd *cobra.Command, args []string) error {
 	o.Shell = cmd.Name()
@@ -86,6 +98,12 @@ func (o *options) Validate() error {
 		return err
 	}
 
+	switch o.OutputFormat {
+	case "", outputFormatEnv, outputFormatCloudsYAML:
+	default:
+		return fmt.Errorf("invalid output format %q, must be one of %s, %s", o.OutputFormat, outputFormatEnv, outputFormatCloudsYAML)
+	}
+
 	return nil
 }
 
@@ -102,6 +120,10 @@ func (o *options) AddFlags(flags *pflag.FlagSet) {
 
 	usage := fmt.Sprintf("Generate the script to unset %s for %s", text, o.Shell)
 	flags.BoolVarP(&o.Unset, "unset", "u", o.Unset, usage)
+
+	if o.ProviderType != "kubernetes" {
+		flags.StringVar(&o.OutputFormat, "output-format", outputFormatEnv, fmt.Sprintf("Format of the cloud provider CLI configuration. One of %s, %s. The %s format is only supported for openstack", outputFormatEnv, outputFormatCloudsYAML, outputFormatCloudsYAML))
+	}
 }
 
 // Run does the actual work of the command.
@@ -118,7 +140,8 @@ func (o *options) Run(f util.Factory) error {
 
 	switch o.ProviderType {
 	case "kubernetes":
-		if !o.Symlink && o.CurrentTarget.GardenName() == "" {
+		// unsetting KUBECONFIG does not require a target
+		if !o.Unset && !o.Symlink && o.CurrentTarget.GardenName() == "" {
 			return target.ErrNoGardenTargeted
 		}
 
@@ -183,6 +206,15 @@ func (o *options) run(ctx context.Context, client gardenclient.Client) error {
 		return err
 	}
 
+	if o.Unset {
+		// unsetting the cloud provider CLI configuration does not require the credentials of the shoot
+		return execTmpl(o, shoot, &corev1.Secret{}, nil)
+	}
+
+	if shoot.Spec.SecretBindingName == "" {
+		return o.runWithCredentialsBinding(ctx, client, shoot)
+	}
+
 	secretBinding, err := client.GetSecretBinding(ctx, shoot.Namespace, shoot.Spec.SecretBindingName)
 	if err != nil {
 		return err
@@ -193,6 +225,49 @@ func (o *options) run(ctx context.Context, client gardenclient.Client) error {
 		return err
 	}
 
+	return o.runWithSecret(ctx, client, shoot, secret)
+}
+
+// runWithCredentialsBinding configures the cloud provider CLI with the credentials referenced by the CredentialsBinding of the shoot.
+// The CredentialsBinding either references a Secret with static credentials or a WorkloadIdentity.
+func (o *options) runWithCredentialsBinding(ctx context.Context, client gardenclient.Client, shoot *gardencorev1beta1.Shoot) error {
+	credentialsBinding, err := client.GetShootCredentialsBinding(ctx, shoot.Namespace, shoot.Name)
+	if err != nil {
+		return err
+	}
+
+	if credentialsBinding == nil {
+		return fmt.Errorf("shoot %q references neither a SecretBinding nor a CredentialsBinding", shoot.Name)
+	}
+
+	ref := credentialsBinding.CredentialsRef
+
+	switch ref.Kind {
+	case "Secret":
+		secret, err := client.GetSecret(ctx, ref.Namespace, ref.Name)
+		if err != nil {
+			return err
+		}
+
+		return o.runWithSecret(ctx, client, shoot, secret)
+	case "WorkloadIdentity":
+		workloadIdentity, err := client.GetWorkloadIdentity(ctx, ref.Namespace, ref.Name)
+		if err != nil {
+			return err
+		}
+
+		token, err := client.CreateWorkloadIdentityToken(ctx, ref.Namespace, ref.Name, workloadIdentityTokenExpiration)
+		if err != nil {
+			return err
+		}
+
+		return execWorkloadIdentityTmpl(o, shoot, workloadIdentity, token)
+	default:
+		return fmt.Errorf("credentials of kind %q referenced by CredentialsBinding %q are not supported", ref.Kind, credentialsBinding.Name)
+	}
+}
+
+func (o *options) runWithSecret(ctx context.Context, client gardenclient.Client, shoot *gardencorev1beta1.Shoot, secret *corev1.Secret) error {
 	cloudProfile, err := client.GetCloudProfile(ctx, shoot.Spec.CloudProfileName)
 	if err != nil {
 		return err
@@ -213,10 +288,22 @@ func execTmpl(o *options, shoot *gardencorev1beta1.Shoot, secret *corev1.Secret,
 		data[key] = string(value)
 	}
 
+	if o.OutputFormat == outputFormatCloudsYAML && o.ProviderType != "openstack" {
+		return fmt.Errorf("output format %s is not supported for provider type %q", outputFormatCloudsYAML, o.ProviderType)
+	}
+
+	if !o.Unset {
+		for _, key := range requiredSecretKeys[o.ProviderType] {
+			if len(secret.Data[key]) == 0 {
+				return fmt.Errorf("no %q data in Secret %q", key, secret.Name)
+			}
+		}
+	}
+
 	switch o.ProviderType {
 	case "azure":
 		if !o.Unset {
-			configDir, err := createProviderConfigDir(o.SessionDir, o.ProviderType)
+			configDir, err := createProviderConfigDir(o, shoot)
 			if err != nil {
 				return err
 			}
@@ -224,33 +311,114 @@ func execTmpl(o *options, shoot *gardencorev1beta1.Shoot, secret *corev1.Secret,
 			data["configDir"] = configDir
 		}
 	case "gcp":
-		credentials := make(map[string]interface{})
+		if !o.Unset {
+			credentials := make(map[string]interface{})
 
-		serviceaccountJSON, err := parseGCPCredentials(secret, &credentials)
-		if err != nil {
-			return err
-		}
+			serviceaccountJSON, err := parseGCPCredentials(secret, &credentials)
+			if err != nil {
+				return err
+			}
 
-		if !o.Unset {
-			configDir, err := createProviderConfigDir(o.SessionDir, o.ProviderType)
+			configDir, err := createProviderConfigDir(o, shoot)
 			if err != nil {
 				return err
 			}
 
 			data["configDir"] = configDir
+			data["credentials"] = credentials
+			data["serviceaccount.json"] = string(serviceaccountJSON)
+		} else {
+			// the configuration directory of the target is removed after revoking the credentials
+			data["configDir"] = providerConfigDir(o, shoot)
 		}
-
-		data["credentials"] = credentials
-		data["serviceaccount.json"] = string(serviceaccountJSON)
 	case "openstack":
-		authURL, err := getKeyStoneURL(cloudProfile, shoot.Spec.Region)
+		if !o.Unset {
+			authURL, err := getKeyStoneURL(cloudProfile, shoot.Spec.Region)
+			if err != nil {
+				return err
+			}
+
+			data["authURL"] = authURL
+
+			if o.OutputFormat == outputFormatCloudsYAML {
+				cloudsYAML, err := writeOpenstackCloudsYAML(o, shoot, data)
+				if err != nil {
+					return err
+				}
+
+				data["cloudsYAML"] = cloudsYAML
+			}
+		} else {
+			// the clouds.yaml contains the credentials of the target, hence it is removed when resetting the configuration
+			filename := filepath.Join(providerConfigDir(o, shoot), "clouds.yaml")
+			if err := os.Remove(filename); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to remove clouds.yaml: %w", err)
+			}
+		}
+	}
+
+	return execProviderTmpl(o, data)
+}
+
+// execWorkloadIdentityTmpl writes the token of the workload identity to the configuration directory of the cloud provider CLI
+// and renders the template, which configures the cloud provider CLI to exchange the token for short-lived credentials.
+func execWorkloadIdentityTmpl(o *options, shoot *gardencorev1beta1.Shoot, workloadIdentity *gardenclient.WorkloadIdentity, token *gardenclient.WorkloadIdentityToken) error {
+	o.ProviderType = shoot.Spec.Provider.Type
+
+	requiredKeys, ok := requiredWorkloadIdentityKeys[o.ProviderType]
+	if !ok {
+		return fmt.Errorf("workload identities are not supported for provider type %q", o.ProviderType)
+	}
+
+	if workloadIdentity.Spec.TargetSystem.Type != o.ProviderType {
+		return fmt.Errorf("WorkloadIdentity %q has target system %q, but shoot %q has provider type %q", workloadIdentity.Name, workloadIdentity.Spec.TargetSystem.Type, shoot.Name, o.ProviderType)
+	}
+
+	config := make(map[string]interface{})
+
+	if providerConfig := workloadIdentity.Spec.TargetSystem.ProviderConfig; providerConfig != nil && len(providerConfig.Raw) > 0 {
+		if err := json.Unmarshal(providerConfig.Raw, &config); err != nil {
+			return fmt.Errorf("failed to parse provider config of WorkloadIdentity %q: %w", workloadIdentity.Name, err)
+		}
+	}
+
+	for _, key := range requiredKeys {
+		if config[key] == nil {
+			return fmt.Errorf("no %q in provider config of WorkloadIdentity %q", key, workloadIdentity.Name)
+		}
+	}
+
+	configDir, err := createProviderConfigDir(o, shoot)
+	if err != nil {
+		return err
+	}
+
+	tokenFile := filepath.Join(configDir, "token")
+	if err := os.WriteFile(tokenFile, []byte(token.Token), 0600); err != nil {
+		return fmt.Errorf("failed to write workload identity token: %w", err)
+	}
+
+	data := map[string]interface{}{
+		"__meta":           generateMetadata(o),
+		"region":           shoot.Spec.Region,
+		"configDir":        configDir,
+		"tokenFile":        tokenFile,
+		"workloadIdentity": config,
+	}
+
+	if o.ProviderType == "gcp" {
+		credentialsFile, err := writeGCPExternalAccountCredentials(configDir, tokenFile, config["credentialsConfig"])
 		if err != nil {
 			return err
 		}
 
-		data["authURL"] = authURL
+		data["credentialsFile"] = credentialsFile
 	}
 
+	return execProviderTmpl(o, data)
+}
+
+func execProviderTmpl(o *options, data map[string]interface{}) error {
 	filename := filepath.Join(o.GardenDir, "templates", o.ProviderType+".tmpl")
 	if err := o.Template.ParseFiles(filename); err != nil {
 		return fmt.Errorf("failed to generate the cloud provider CLI configuration script: %w", err)
@@ -259,6 +427,28 @@ func execTmpl(o *options, shoot *gardencorev1beta1.Shoot, secret *corev1.Secret,
 	return o.Template.ExecuteTemplate(o.IOStreams.Out, o.Shell, data)
 }
 
+// requiredSecretKeys are the keys of the cloud provider secret that are required to configure the cloud provider CLI.
+// The gcp credentials are validated when parsing the service account.
+var requiredSecretKeys = map[string][]string{
+	"alicloud":  {"accessKeyID", "accessKeySecret"},
+	"aws":       {"accessKeyID", "secretAccessKey"},
+	"azure":     {"clientID", "clientSecret", "tenantID", "subscriptionID"},
+	"hcloud":    {"hcloudToken"},
+	"openstack": {"domainName", "tenantName", "username", "password"},
+}
+
+// workloadIdentityTokenExpiration is the lifetime of the token requested for a workload identity.
+// The cloud provider CLI has to be configured again after the token expired.
+const workloadIdentityTokenExpiration = time.Hour
+
+// requiredWorkloadIdentityKeys are the keys of the provider config of a workload identity that are required to configure the cloud provider CLI.
+// Workload identities are only supported for the listed provider types.
+var requiredWorkloadIdentityKeys = map[string][]string{
+	"aws":   {"roleARN"},
+	"azure": {"clientID", "tenantID", "subscriptionID"},
+	"gcp":   {"projectID", "credentialsConfig"},
+}
+
 func generateMetadata(o *options) map[string]interface{} {
 	metadata := make(map[string]interface{})
 	metadata["unset"] = o.Unset
@@ -326,9 +516,118 @@ func parseGCPCredentials(secret *corev1.Secret, credentials interface{}) ([]byte
 	return json.Marshal(credentials)
 }
 
-func createProviderConfigDir(sessionDir string, providerType string) (string, error) {
-	cli := getProviderCLI(providerType)
-	configDir := filepath.Join(sessionDir, ".config", cli)
+// openstackCloud is an entry of the clouds.yaml file of the openstack CLI
+type openstackCloud struct {
+	Auth               openstackCloudAuth `yaml:"auth"`
+	RegionName         string             `yaml:"region_name"`
+	IdentityAPIVersion int                `yaml:"identity_api_version"`
+	AuthType           string             `yaml:"auth_type"`
+	Interface          string             `yaml:"interface"`
+}
+
+type openstackCloudAuth struct {
+	AuthURL           string `yaml:"auth_url"`
+	Username          string `yaml:"username"`
+	Password          string `yaml:"password"`
+	ProjectName       string `yaml:"project_name"`
+	ProjectDomainName string `yaml:"project_domain_name"`
+	UserDomainName    string `yaml:"user_domain_name"`
+}
+
+// writeOpenstackCloudsYAML writes a clouds.yaml file with a single cloud for the targeted shoot
+// to the configuration directory and returns the filename and the name of the cloud.
+func writeOpenstackCloudsYAML(o *options, shoot *gardencorev1beta1.Shoot, data map[string]interface{}) (map[string]interface{}, error) {
+	configDir, err := createProviderConfigDir(o, shoot)
+	if err != nil {
+		return nil, err
+	}
+
+	cloudName := shootConfigName(o, shoot)
+	cloud := openstackCloud{
+		Auth: openstackCloudAuth{
+			AuthURL:           data["authURL"].(string),
+			Username:          data["username"].(string),
+			Password:          data["password"].(string),
+			ProjectName:       data["tenantName"].(string),
+			ProjectDomainName: data["domainName"].(string),
+			UserDomainName:    data["domainName"].(string),
+		},
+		RegionName:         shoot.Spec.Region,
+		IdentityAPIVersion: 3,
+		AuthType:           "password",
+		Interface:          "public",
+	}
+
+	content, err := yaml.Marshal(map[string]interface{}{
+		"clouds": map[string]openstackCloud{
+			cloudName: cloud,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode clouds.yaml: %w", err)
+	}
+
+	filename := filepath.Join(configDir, "clouds.yaml")
+	if err := os.WriteFile(filename, content, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write clouds.yaml: %w", err)
+	}
+
+	return map[string]interface{}{
+		"file":  filename,
+		"cloud": cloudName,
+	}, nil
+}
+
+// writeGCPExternalAccountCredentials writes the external account credentials configuration of a gcp workload identity
+// to the configuration directory. The credential source is replaced with the file that contains the workload identity token.
+func writeGCPExternalAccountCredentials(configDir, tokenFile string, credentialsConfig interface{}) (string, error) {
+	credentials, ok := credentialsConfig.(map[string]interface{})
+	if !ok {
+		return "", errors.New("credentialsConfig of gcp workload identity must be an object")
+	}
+
+	credentials["credential_source"] = map[string]interface{}{
+		"file": tokenFile,
+		"format": map[string]interface{}{
+			"type": "text",
+		},
+	}
+
+	data, err := json.Marshal(credentials)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode gcp credentials configuration: %w", err)
+	}
+
+	filename := filepath.Join(configDir, "credentials.json")
+	if err := os.WriteFile(filename, data, 0600); err != nil {
+		return "", fmt.Errorf("failed to write gcp credentials configuration: %w", err)
+	}
+
+	return filename, nil
+}
+
+// providerConfigDir returns the configuration directory of the cloud provider CLI in the session directory.
+// gcloud stores the activated accounts and the active configuration in its configuration directory and
+// the openstack clouds.yaml contains the credentials of the shoot, hence a separate directory is used per targeted shoot.
+func providerConfigDir(o *options, shoot *gardencorev1beta1.Shoot) string {
+	configDir := filepath.Join(o.SessionDir, ".config", getProviderCLI(o.ProviderType))
+
+	switch o.ProviderType {
+	case "gcp", "openstack":
+		configDir = filepath.Join(configDir, shootConfigName(o, shoot))
+	}
+
+	return configDir
+}
+
+// shootConfigName returns a name that identifies the targeted shoot in the configuration of the cloud provider CLI
+func shootConfigName(o *options, shoot *gardencorev1beta1.Shoot) string {
+	return fmt.Sprintf("%s--%s--%s", o.CurrentTarget.GardenName(), shoot.Namespace, shoot.Name)
+}
+
+func createProviderConfigDir(o *options, shoot *gardencorev1beta1.Shoot) (string, error) {
+	cli := getProviderCLI(o.ProviderType)
+	configDir := providerConfigDir(o, shoot)
 
 	err := os.MkdirAll(configDir, 0700)
 	if err != nil {
@@ -11,8 +11,10 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"os"
 	"path/filepath"
 	"regexp"
+	"time"
 
 	openstackv1alpha1 "github.com/gardener/gardener-extension-provider-openstack/pkg/apis/openstack/v1alpha1"
 	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
@@ -28,6 +30,7 @@ import (
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/utils/pointer"
 
+	"github.com/gardener/gardenctl-v2/internal/gardenclient"
 	gardenclientmocks "github.com/gardener/gardenctl-v2/internal/gardenclient/mocks"
 	utilmocks "github.com/gardener/gardenctl-v2/internal/util/mocks"
 	"github.com/gardener/gardenctl-v2/pkg/cmd/env"
@@ -163,6 +166,12 @@ var _ = Describe("Env Commands - Options", func() {
 				options.Shell = "cmd"
 				Expect(options.Validate()).To(MatchError(fmt.Sprintf("invalid shell given, must be one of %v", env.ValidShells)))
 			})
+
+			It("should return an error when the output format is invalid", func() {
+				options.Shell = "bash"
+				options.OutputFormat = "yaml"
+				Expect(options.Validate()).To(MatchError(`invalid output format "yaml", must be one of env, clouds-yaml`))
+			})
 		})
 
 		Describe("adding the command flags", func() {
@@ -170,6 +179,7 @@ var _ = Describe("Env Commands - Options", func() {
 				cmd := &cobra.Command{}
 				options.AddFlags(cmd.Flags())
 				Expect(cmd.Flag("unset")).NotTo(BeNil())
+				Expect(cmd.Flag("output-format")).NotTo(BeNil())
 			})
 		})
 
@@ -219,6 +229,21 @@ var _ = Describe("Env Commands - Options", func() {
 						Expect(options.Run(factory)).To(Succeed())
 					})
 				})
+
+				Context("and the KUBECONFIG environment variable is unset", func() {
+					It("does not require a target", func() {
+						options.Unset = true
+						manager.EXPECT().CurrentTarget().Return(target.NewTarget("", "", "", ""), nil)
+						mockTemplate.EXPECT().ExecuteTemplate(options.IOStreams.Out, shell, gomock.Any()).
+							Do(func(_ io.Writer, _ string, data map[string]interface{}) {
+								Expect(data).NotTo(HaveKey("filename"))
+								metadata, ok := data["__meta"].(map[string]interface{})
+								Expect(ok).To(BeTrue())
+								Expect(metadata["unset"]).To(BeTrue())
+							}).Return(nil)
+						Expect(options.Run(factory)).To(Succeed())
+					})
+				})
 			})
 
 			Context("when an error occurs", func() {
@@ -299,6 +324,7 @@ var _ = Describe("Env Commands - Options", func() {
 					Name:      "secret",
 				}
 				shell = "bash"
+				unset = false
 				options.SessionDir = sessionDir
 			})
 
@@ -328,7 +354,7 @@ var _ = Describe("Env Commands - Options", func() {
 						Name:      secretRef.Name,
 					},
 					Data: map[string][]byte{
-						"serviceaccount.json": []byte(readTestFile(filepath.Join(provider.Type, "serviceaccount.json"))),
+						"serviceaccount.json": []byte(readTestFile("gcp/serviceaccount.json")),
 					},
 				}
 				cloudProfile = &gardencorev1beta1.CloudProfile{
@@ -353,6 +379,10 @@ var _ = Describe("Env Commands - Options", func() {
 				})
 
 				JustBeforeEach(func() {
+					if unset {
+						return
+					}
+
 					client.EXPECT().GetSecretBinding(ctx, shoot.Namespace, shoot.Spec.SecretBindingName).Return(secretBinding, nil)
 					client.EXPECT().GetSecret(ctx, secretBinding.SecretRef.Namespace, secretBinding.SecretRef.Name).Return(secret, nil)
 					client.EXPECT().GetCloudProfile(ctx, shoot.Spec.CloudProfileName).Return(cloudProfile, nil)
@@ -370,11 +400,16 @@ var _ = Describe("Env Commands - Options", func() {
 						Expect(options.String()).To(Equal(fmt.Sprintf(readTestFile("gcp/export.bash"), sessionDir)))
 					})
 
-					It("should print how to reset configuration for powershell", func() {
-						options.Unset = true
-						options.Shell = "powershell"
-						Expect(options.Run(factory)).To(Succeed())
-						Expect(options.String()).To(Equal(readTestFile("gcp/unset.pwsh")))
+					Context("and the configuration is reset", func() {
+						BeforeEach(func() {
+							unset = true
+							shell = "powershell"
+						})
+
+						It("should print how to reset configuration for powershell without reading the credentials", func() {
+							Expect(options.Run(factory)).To(Succeed())
+							Expect(options.String()).To(Equal(fmt.Sprintf(readTestFile("gcp/unset.pwsh"), sessionDir)))
+						})
 					})
 				})
 
@@ -392,6 +427,117 @@ var _ = Describe("Env Commands - Options", func() {
 				})
 			})
 
+			Context("when the shoot references a CredentialsBinding", func() {
+				var (
+					credentialsBinding *gardenclient.CredentialsBinding
+					workloadIdentity   *gardenclient.WorkloadIdentity
+					providerConfig     string
+				)
+
+				BeforeEach(func() {
+					secretBindingName = ""
+					provider.Type = "aws"
+					providerConfig = `{"roleARN": "arn:aws:iam::123456789012:role/gardener"}`
+					credentialsBinding = &gardenclient.CredentialsBinding{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "credentials-binding",
+							Namespace: "garden-project",
+						},
+						CredentialsRef: corev1.ObjectReference{
+							Kind:      "WorkloadIdentity",
+							Namespace: "garden-project",
+							Name:      "workload-identity",
+						},
+					}
+					factory.EXPECT().Manager().Return(manager, nil)
+					manager.EXPECT().GardenClient(t.GardenName()).Return(client, nil)
+					factory.EXPECT().Context().Return(ctx)
+				})
+
+				JustBeforeEach(func() {
+					workloadIdentity = &gardenclient.WorkloadIdentity{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "workload-identity",
+							Namespace: "garden-project",
+						},
+						Spec: gardenclient.WorkloadIdentitySpec{
+							TargetSystem: gardenclient.TargetSystem{
+								Type:           provider.Type,
+								ProviderConfig: &runtime.RawExtension{Raw: []byte(providerConfig)},
+							},
+						},
+					}
+					currentTarget := t.WithSeedName("")
+					manager.EXPECT().CurrentTarget().Return(currentTarget, nil)
+					client.EXPECT().FindShoot(ctx, currentTarget.AsListOption()).Return(shoot, nil)
+					client.EXPECT().GetShootCredentialsBinding(ctx, shoot.Namespace, shoot.Name).Return(credentialsBinding, nil)
+				})
+
+				Context("and the credentials are a workload identity", func() {
+					JustBeforeEach(func() {
+						client.EXPECT().GetWorkloadIdentity(ctx, "garden-project", "workload-identity").Return(workloadIdentity, nil)
+						client.EXPECT().CreateWorkloadIdentityToken(ctx, "garden-project", "workload-identity", time.Hour).Return(&gardenclient.WorkloadIdentityToken{Token: "token"}, nil)
+					})
+
+					It("should configure the aws CLI with a token of the workload identity", func() {
+						Expect(options.Run(factory)).To(Succeed())
+						Expect(options.String()).To(Equal(fmt.Sprintf(readTestFile("aws/export.workload-identity.bash"), sessionDir)))
+						Expect(os.ReadFile(filepath.Join(sessionDir, ".config", "aws", "token"))).To(Equal([]byte("token")))
+					})
+
+					Context("and the provider is gcp", func() {
+						BeforeEach(func() {
+							provider.Type = "gcp"
+							providerConfig = `{"projectID": "test", "credentialsConfig": {"type": "external_account", "credential_source": {"file": "/var/run/secrets/token"}}}`
+						})
+
+						It("should write the external account credentials with the token file as credential source", func() {
+							configDir := filepath.Join(sessionDir, ".config", "gcloud", "test--garden-project--shoot")
+							Expect(options.Run(factory)).To(Succeed())
+							Expect(options.String()).To(ContainSubstring(fmt.Sprintf("export GOOGLE_APPLICATION_CREDENTIALS='%s/credentials.json';", configDir)))
+							data, err := os.ReadFile(filepath.Join(configDir, "credentials.json"))
+							Expect(err).NotTo(HaveOccurred())
+							Expect(string(data)).To(MatchJSON(fmt.Sprintf(`{"type": "external_account", "credential_source": {"file": "%s/token", "format": {"type": "text"}}}`, configDir)))
+						})
+					})
+
+					Context("and the provider does not support workload identities", func() {
+						BeforeEach(func() {
+							provider.Type = "hcloud"
+						})
+
+						It("should fail", func() {
+							Expect(options.Run(factory)).To(MatchError(`workload identities are not supported for provider type "hcloud"`))
+						})
+					})
+
+					Context("and the provider config is incomplete", func() {
+						BeforeEach(func() {
+							providerConfig = `{}`
+						})
+
+						It("should fail", func() {
+							Expect(options.Run(factory)).To(MatchError(`no "roleARN" in provider config of WorkloadIdentity "workload-identity"`))
+						})
+					})
+				})
+
+				Context("and the credentials are a secret", func() {
+					BeforeEach(func() {
+						provider.Type = "gcp"
+						credentialsBinding.CredentialsRef.Kind = "Secret"
+						credentialsBinding.CredentialsRef.Name = "secret"
+					})
+
+					It("should configure the cloud provider CLI with the secret", func() {
+						client.EXPECT().GetSecret(ctx, "garden-project", "secret").Return(secret, nil)
+						client.EXPECT().GetCloudProfile(ctx, cloudProfileName).Return(cloudProfile, nil)
+						Expect(options.Run(factory)).To(Succeed())
+						Expect(options.String()).To(Equal(fmt.Sprintf(readTestFile("gcp/export.bash"), sessionDir)))
+					})
+				})
+			})
+
 			Context("when an error occurs before running the command", func() {
 				err := errors.New("error")
 
@@ -489,7 +635,7 @@ var _ = Describe("Env Commands - Options", func() {
 			BeforeEach(func() {
 				shell = "bash"
 				unset = false
-				namespace = "garden-test"
+				namespace = "garden-project"
 				shootName = "shoot"
 				secretName = "secret"
 				cloudProfileName = "cloud-profile"
@@ -560,7 +706,7 @@ var _ = Describe("Env Commands - Options", func() {
 
 				It("should render the template successfully", func() {
 					Expect(options.ExecTmpl(shoot, secret, cloudProfile)).To(Succeed())
-					Expect(options.String()).To(Equal(readTestFile("gcp/unset.pwsh")))
+					Expect(options.String()).To(Equal(fmt.Sprintf(readTestFile("gcp/unset.pwsh"), sessionDir)))
 				})
 			})
 
@@ -670,6 +816,49 @@ var _ = Describe("Env Commands - Options", func() {
 					cloudProfile.Spec.ProviderConfig = nil
 					Expect(options.ExecTmpl(shoot, secret, cloudProfile)).To(MatchError(MatchRegexp("^failed to get openstack provider config:")))
 				})
+
+				Context("and the output format is clouds-yaml", func() {
+					var filename string
+
+					BeforeEach(func() {
+						options.OutputFormat = "clouds-yaml"
+						filename = filepath.Join(sessionDir, ".config", "openstack", "test--garden-project--shoot", "clouds.yaml")
+					})
+
+					It("should write the clouds.yaml and render the template successfully", func() {
+						Expect(options.ExecTmpl(shoot, secret, cloudProfile)).To(Succeed())
+						Expect(options.String()).To(HavePrefix(fmt.Sprintf("export OS_CLIENT_CONFIG_FILE='%s';\nexport OS_CLOUD='test--garden-project--shoot';\n", filename)))
+						data, err := os.ReadFile(filename)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(string(data)).To(MatchYAML(`clouds:
+  test--garden-project--shoot:
+    auth:
+      auth_url: keyStoneURL
+      username: user
+      password: secret
+      project_name: tenant
+      project_domain_name: domain
+      user_domain_name: domain
+    region_name: europe
+    identity_api_version: 3
+    auth_type: password
+    interface: public
+`))
+					})
+
+					It("should remove the clouds.yaml when resetting the configuration", func() {
+						Expect(options.ExecTmpl(shoot, secret, cloudProfile)).To(Succeed())
+						Expect(filename).To(BeAnExistingFile())
+						options.Unset = true
+						Expect(options.ExecTmpl(shoot, &corev1.Secret{}, nil)).To(Succeed())
+						Expect(filename).NotTo(BeAnExistingFile())
+					})
+
+					It("should fail for other provider types", func() {
+						shoot.Spec.Provider.Type = "aws"
+						Expect(options.ExecTmpl(shoot, secret, cloudProfile)).To(MatchError(`output format clouds-yaml is not supported for provider type "aws"`))
+					})
+				})
 			})
 
 			Context("when the cloudprovider is azure", func() {
@@ -701,6 +890,11 @@ var _ = Describe("Env Commands - Options", func() {
 					options.SessionDir = string([]byte{0})
 					Expect(options.ExecTmpl(shoot, secret, cloudProfile)).To(MatchError(MatchRegexp("^failed to create az configuration directory:")))
 				})
+
+				It("should fail if a credential is missing", func() {
+					delete(secret.Data, "clientSecret")
+					Expect(options.ExecTmpl(shoot, secret, cloudProfile)).To(MatchError(`no "clientSecret" data in Secret "secret"`))
+				})
 			})
 		})
 
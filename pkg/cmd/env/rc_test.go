@@ -72,6 +72,14 @@ alias gtc-='gardenctl target unset control-plane'
 alias gk='eval $(gardenctl kubectl-env bash)'
 alias gp='eval $(gardenctl provider-env bash)'
 alias gcv='gardenctl config view -o yaml'
+gtarget() {
+  gardenctl target "$@" && gk
+}
+gps1() {
+  local target
+  target=$(gardenctl target view -o prompt 2>/dev/null)
+  [ -n "$target" ] && printf '[%s]' "$target"
+}
 source <(gardenctl completion bash)
 complete -o default -F __start_gardenctl g
 gk
@@ -91,6 +99,14 @@ alias gtc-='gardenctl target unset control-plane'
 alias gk='eval $(gardenctl kubectl-env zsh)'
 alias gp='eval $(gardenctl provider-env zsh)'
 alias gcv='gardenctl config view -o yaml'
+gtarget() {
+  gardenctl target "$@" && gk
+}
+gps1() {
+  local target
+  target=$(gardenctl target view -o prompt 2>/dev/null)
+  [ -n "$target" ] && printf '[%s]' "$target"
+}
 if (( $+commands[gardenctl] )); then
   if [ -d "$ZSH_CACHE_DIR/completions" ] && (($fpath[(Ie)$ZSH_CACHE_DIR/completions])); then
     GCTL_COMPLETION_FILE="$ZSH_CACHE_DIR/completions/_gardenctl"
@@ -122,6 +138,13 @@ alias gtc-='gardenctl target unset control-plane'
 alias gk='eval (gardenctl kubectl-env fish)'
 alias gp='eval (gardenctl provider-env fish)'
 alias gcv='gardenctl config view -o yaml'
+function gtarget
+  gardenctl target $argv; and gk
+end
+function gps1
+  set -l target (gardenctl target view -o prompt 2>/dev/null)
+  test -n "$target"; and printf '[%s]' "$target"
+end
 gardenctl completion fish | source
 complete -c g -w gardenctl
 gk
@@ -159,6 +182,16 @@ function Gardenctl-Config-View {
   gardenctl config view -o yaml
 }
 Set-Alias -Name gcv -Value Gardenctl-Config-View -Option AllScope -Force
+function Gardenctl-Target {
+  gardenctl target @args
+  if ($?) { Gardenctl-KubectlEnv }
+}
+Set-Alias -Name gtarget -Value Gardenctl-Target -Option AllScope -Force
+function Gardenctl-Prompt {
+  $target = (gardenctl target view -o prompt 2>$null)
+  if ($target) { "[$target]" }
+}
+Set-Alias -Name gps1 -Value Gardenctl-Prompt -Option AllScope -Force
 function Gardenctl-Completion-Powershell {
   $s = (gardenctl completion powershell)
   @(
@@ -194,6 +227,8 @@ gk
 			cmd.SetArgs([]string{shell, "--no-kubeconfig"})
 			Expect(cmd.Execute()).To(Succeed())
 			Expect(out.String()).NotTo(MatchRegexp(`(?m)^gk$`))
+			Expect(out.String()).NotTo(MatchRegexp(`(?m)gardenctl target .*(gk|Gardenctl-KubectlEnv)`))
+			Expect(out.String()).To(MatchRegexp(`(?m)^  gardenctl target (\$argv|"\$@"|@args)$`))
 		},
 			Entry("when subcommand is bash", "bash"),
 			Entry("when subcommand is zsh", "zsh"),
@@ -142,9 +142,11 @@ printf 'Run the following command to revoke access credentials:\n$ eval $(garden
 		const unsetFormat = `gcloud auth revoke $GOOGLE_CREDENTIALS_ACCOUNT --verbosity=error;
 unset GOOGLE_CREDENTIALS;
 unset GOOGLE_CREDENTIALS_ACCOUNT;
+unset GOOGLE_APPLICATION_CREDENTIALS;
 unset CLOUDSDK_CORE_PROJECT;
 unset CLOUDSDK_COMPUTE_REGION;
 unset CLOUDSDK_CONFIG;
+rm -rf '%[5]s';
 
 # Run this command to reset the gcloud configuration for your shell:
 # eval $(gardenctl provider-env -u %[1]s)
@@ -197,6 +199,7 @@ printf 'Run the following command to log out and remove access to Azure subscrip
 		const unsetFormat = `az logout --username "$Env:AZURE_CLIENT_ID";
 Remove-Item -ErrorAction SilentlyContinue Env:\AZURE_CLIENT_ID;
 Remove-Item -ErrorAction SilentlyContinue Env:\AZURE_CLIENT_SECRET;
+Remove-Item -ErrorAction SilentlyContinue Env:\AZURE_FEDERATED_TOKEN_FILE;
 Remove-Item -ErrorAction SilentlyContinue Env:\AZURE_TENANT_ID;
 Remove-Item -ErrorAction SilentlyContinue Env:\AZURE_SUBSCRIPTION_ID;
 Remove-Item -ErrorAction SilentlyContinue Env:\AZURE_CONFIG_DIR;
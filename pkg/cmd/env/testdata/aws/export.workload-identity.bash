@@ -0,0 +1,6 @@
+export AWS_ROLE_ARN='arn:aws:iam::123456789012:role/gardener';
+export AWS_WEB_IDENTITY_TOKEN_FILE='%[1]s/.config/aws/token';
+export AWS_DEFAULT_REGION='europe';
+
+# Run this command to configure aws for your shell:
+# eval $(gardenctl provider-env bash)
@@ -2,5 +2,5 @@ package testdata
 
 import "embed"
 
-//go:embed templates azure gcp openstack test
+//go:embed templates aws azure gcp openstack test
 var FS embed.FS
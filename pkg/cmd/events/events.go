@@ -0,0 +1,302 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1beta1 "k8s.io/apimachinery/pkg/apis/meta/v1beta1"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/cli-runtime/pkg/printers"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
+	"github.com/gardener/gardenctl-v2/pkg/target"
+)
+
+// Sources of the events
+const (
+	// SourceGarden are the events of the shoot resource in the project namespace of the garden cluster
+	SourceGarden = "garden"
+	// SourceSeed are the events in the control plane namespace of the shoot on its seed
+	SourceSeed = "seed"
+	// SourceShoot are the events in all namespaces of the shoot cluster
+	SourceShoot = "shoot"
+)
+
+// NewCmdEvents returns a new events command.
+func NewCmdEvents(f util.Factory, o *EventsOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "events",
+		Short: "Show the events of the targeted shoot from the garden, its seed and the shoot itself",
+		Long: `Show the events of the targeted shoot in one time-ordered view, to find the root cause of a problem faster.
+The events are collected from the following sources, the SOURCE column shows where an event comes from:
+  garden  events of the shoot resource in its project namespace of the garden cluster
+  seed    events in the control plane namespace of the shoot on its seed, usually only allowed for Gardener operators
+  shoot   events in all namespaces of the shoot cluster, only if --include-shoot is given
+
+Sources that cannot be read, e.g. because of missing permissions or an unreachable cluster, are skipped with a warning.`,
+		Example: `# show the events of the targeted shoot of the last hour
+gardenctl events
+
+# show the events of the last day, including the events of the shoot cluster
+gardenctl events --since 24h --include-shoot
+
+# show the events as json
+gardenctl events -o json`,
+		Args: cobra.NoArgs,
+		RunE: base.WrapRunE(o, f),
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// EventsOptions is a struct to support events command
+type EventsOptions struct {
+	base.Options
+
+	// Since only shows the events that occurred within the given duration, zero shows all events
+	Since time.Duration
+	// IncludeShoot also shows the events of the shoot cluster
+	IncludeShoot bool
+}
+
+// NewEventsOptions returns initialized EventsOptions
+func NewEventsOptions(ioStreams util.IOStreams) *EventsOptions {
+	return &EventsOptions{
+		Options: base.Options{
+			IOStreams: ioStreams,
+		},
+		Since: time.Hour,
+	}
+}
+
+// Event is the machine-readable output of events
+type Event struct {
+	// Source is the cluster of the event, one of garden, seed or shoot
+	Source string `json:"source" yaml:"source"`
+	// Namespace is the namespace of the event
+	Namespace string `json:"namespace" yaml:"namespace"`
+	// Object is the kind and name of the object the event is about, e.g. Pod/kube-apiserver-abcde
+	Object string `json:"object" yaml:"object"`
+	// Type is the type of the event, Normal or Warning
+	Type string `json:"type" yaml:"type"`
+	// Reason is the short reason of the event
+	Reason string `json:"reason" yaml:"reason"`
+	// Message is the description of the event
+	Message string `json:"message" yaml:"message"`
+	// Count is the number of times the event has occurred
+	Count int32 `json:"count" yaml:"count"`
+	// LastTimestamp is the time the event has occurred the last time
+	LastTimestamp metav1.Time `json:"lastTimestamp" yaml:"lastTimestamp"`
+}
+
+// AddFlags binds the command options to a given flagset
+func (o *EventsOptions) AddFlags(flags *pflag.FlagSet) {
+	flags.DurationVar(&o.Since, "since", o.Since, "Only show the events that occurred within the given duration, e.g. 30m or 24h. Zero shows all events.")
+	flags.BoolVar(&o.IncludeShoot, "include-shoot", o.IncludeShoot, "Also show the events of all namespaces of the shoot cluster.")
+	o.Options.AddFlags(flags)
+}
+
+// Validate validates the provided options
+func (o *EventsOptions) Validate() error {
+	if o.Since < 0 {
+		return errors.New("since must not be negative")
+	}
+
+	return o.Options.Validate()
+}
+
+// Run executes the command
+func (o *EventsOptions) Run(f util.Factory) error {
+	manager, err := f.Manager()
+	if err != nil {
+		return err
+	}
+
+	currentTarget, err := manager.CurrentTarget()
+	if err != nil {
+		return fmt.Errorf("failed to get current target: %w", err)
+	}
+
+	if currentTarget.ShootName() == "" {
+		return errors.New("no Shoot cluster targeted")
+	}
+
+	gardenClient, err := manager.GardenClient(currentTarget.GardenName())
+	if err != nil {
+		return fmt.Errorf("failed to create garden cluster client: %w", err)
+	}
+
+	ctx := f.Context()
+
+	shoot, err := util.ShootForTarget(ctx, gardenClient, currentTarget)
+	if err != nil {
+		return err
+	}
+
+	eventList := &corev1.EventList{}
+	if err := gardenClient.RuntimeClient().List(ctx, eventList, client.InNamespace(shoot.Namespace)); err != nil {
+		return fmt.Errorf("failed to list events in namespace %s: %w", shoot.Namespace, err)
+	}
+
+	var events []Event
+
+	for _, event := range eventList.Items {
+		if event.InvolvedObject.Kind == "Shoot" && event.InvolvedObject.Name == shoot.Name {
+			events = append(events, newEvent(SourceGarden, event))
+		}
+	}
+
+	// the client configuration of the control plane points to the control plane namespace on the seed
+	seedEvents, err := listEvents(ctx, f, manager, currentTarget.WithControlPlane(true), true)
+	if err != nil {
+		fmt.Fprintf(o.IOStreams.ErrOut, "Warning: skipping the events of the seed: %v\n", err)
+	}
+
+	for _, event := range seedEvents {
+		events = append(events, newEvent(SourceSeed, event))
+	}
+
+	if o.IncludeShoot {
+		shootEvents, err := listEvents(ctx, f, manager, currentTarget.WithControlPlane(false), false)
+		if err != nil {
+			fmt.Fprintf(o.IOStreams.ErrOut, "Warning: skipping the events of the shoot: %v\n", err)
+		}
+
+		for _, event := range shootEvents {
+			events = append(events, newEvent(SourceShoot, event))
+		}
+	}
+
+	now := f.Clock().Now()
+
+	filtered := []Event{}
+
+	for _, event := range events {
+		if o.Since == 0 || !event.LastTimestamp.Time.Before(now.Add(-o.Since)) {
+			filtered = append(filtered, event)
+		}
+	}
+
+	sort.SliceStable(filtered, func(i, j int) bool {
+		return filtered[i].LastTimestamp.Before(&filtered[j].LastTimestamp)
+	})
+
+	if o.Output != "" {
+		return o.PrintObject(filtered)
+	}
+
+	if len(filtered) == 0 {
+		fmt.Fprintf(o.IOStreams.ErrOut, "No events found for shoot %s\n", shoot.Name)
+		return nil
+	}
+
+	table := &metav1beta1.Table{
+		ColumnDefinitions: []metav1.TableColumnDefinition{
+			{Name: "Last Seen", Type: "string"},
+			{Name: "Source", Type: "string"},
+			{Name: "Type", Type: "string"},
+			{Name: "Reason", Type: "string"},
+			{Name: "Object", Type: "string"},
+			{Name: "Message", Type: "string"},
+		},
+		Rows: make([]metav1.TableRow, len(filtered)),
+	}
+
+	for i, event := range filtered {
+		table.Rows[i] = metav1.TableRow{
+			Cells: []interface{}{
+				duration.HumanDuration(now.Sub(event.LastTimestamp.Time)),
+				event.Source,
+				event.Type,
+				event.Reason,
+				event.Object,
+				event.Message,
+			},
+		}
+	}
+
+	printer := printers.NewTablePrinter(printers.PrintOptions{})
+	if err := printer.PrintObj(table, o.IOStreams.Out); err != nil {
+		return fmt.Errorf("failed to output event table: %w", err)
+	}
+
+	return nil
+}
+
+// listEvents lists the events of the cluster of the target. If namespaced is true, only the events in the namespace
+// of the client configuration are listed, otherwise the events of all namespaces.
+func listEvents(ctx context.Context, f util.Factory, manager target.Manager, t target.Target, namespaced bool) ([]corev1.Event, error) {
+	clientConfig, err := manager.ClientConfig(ctx, t)
+	if err != nil {
+		return nil, err
+	}
+
+	namespace := metav1.NamespaceAll
+
+	if namespaced {
+		namespace, _, err = clientConfig.Namespace()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	clientset, err := f.Clientset(clientConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	eventList, err := clientset.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+
+	return eventList.Items, nil
+}
+
+// newEvent returns the summary of the event
+func newEvent(source string, event corev1.Event) Event {
+	return Event{
+		Source:        source,
+		Namespace:     event.Namespace,
+		Object:        event.InvolvedObject.Kind + "/" + event.InvolvedObject.Name,
+		Type:          event.Type,
+		Reason:        event.Reason,
+		Message:       event.Message,
+		Count:         event.Count,
+		LastTimestamp: eventTime(event),
+	}
+}
+
+// eventTime returns the time the event has occurred the last time, the timestamps are
+// set depending on the API version and the client that has created the event
+func eventTime(event corev1.Event) metav1.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp
+	case event.Series != nil && !event.Series.LastObservedTime.IsZero():
+		return metav1.Time{Time: event.Series.LastObservedTime.Time}
+	case !event.EventTime.IsZero():
+		return metav1.Time{Time: event.EventTime.Time}
+	case !event.FirstTimestamp.IsZero():
+		return event.FirstTimestamp
+	}
+
+	return event.CreationTimestamp
+}
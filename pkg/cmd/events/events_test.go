@@ -0,0 +1,173 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package events_test
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/utils/pointer"
+
+	internalfake "github.com/gardener/gardenctl-v2/internal/fake"
+	"github.com/gardener/gardenctl-v2/internal/gardenclient"
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/events"
+	"github.com/gardener/gardenctl-v2/pkg/target"
+	targetmocks "github.com/gardener/gardenctl-v2/pkg/target/mocks"
+)
+
+var _ = Describe("Events Command", func() {
+	const (
+		gardenName  = "mygarden"
+		namespace   = "garden-prod1"
+		technicalID = "shoot--prod1--my-shoot"
+	)
+
+	var (
+		ctrl              *gomock.Controller
+		manager           *targetmocks.MockManager
+		factory           *internalfake.Factory
+		streams           util.IOStreams
+		out               *util.SafeBytesBuffer
+		errOut            *util.SafeBytesBuffer
+		currentTarget     target.Target
+		seedClientConfig  clientcmd.ClientConfig
+		shootClientConfig clientcmd.ClientConfig
+		now               time.Time
+	)
+
+	newEvent := func(namespace, name, kind, object, reason string, age time.Duration) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: namespace},
+			InvolvedObject: corev1.ObjectReference{Kind: kind, Name: object},
+			Type:           corev1.EventTypeWarning,
+			Reason:         reason,
+			Message:        reason + " message",
+			Count:          1,
+			LastTimestamp:  metav1.NewTime(now.Add(-age)),
+		}
+	}
+
+	BeforeEach(func() {
+		now = time.Now().Truncate(time.Second)
+
+		ctrl = gomock.NewController(GinkgoT())
+		manager = targetmocks.NewMockManager(ctrl)
+		factory = internalfake.NewFakeFactory(nil, nil, nil, nil)
+		factory.ManagerImpl = manager
+		streams, _, out, errOut = util.NewTestIOStreams()
+		currentTarget = target.NewTarget(gardenName, "prod1", "", "my-shoot")
+
+		gardenClient := internalfake.NewClientWithObjects(
+			&gardencorev1beta1.Project{
+				ObjectMeta: metav1.ObjectMeta{Name: "prod1"},
+				Spec:       gardencorev1beta1.ProjectSpec{Namespace: pointer.String(namespace)},
+			},
+			&gardencorev1beta1.Shoot{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-shoot", Namespace: namespace},
+			},
+			newEvent(namespace, "my-shoot.1", "Shoot", "my-shoot", "Reconciling", 10*time.Minute),
+			newEvent(namespace, "other-shoot.1", "Shoot", "other-shoot", "Other", 5*time.Minute),
+			newEvent(namespace, "my-shoot.2", "Shoot", "my-shoot", "Outdated", 2*time.Hour),
+		)
+
+		seedClientConfig = internalfake.NewClientConfig("seed", technicalID)
+		shootClientConfig = internalfake.NewClientConfig("shoot", "default")
+
+		seedClientset := fake.NewSimpleClientset(
+			newEvent(technicalID, "kube-apiserver.1", "Pod", "kube-apiserver-0", "BackOff", 5*time.Minute),
+			newEvent("other-namespace", "pod.1", "Pod", "other", "Other", 5*time.Minute),
+		)
+		shootClientset := fake.NewSimpleClientset(
+			newEvent("kube-system", "coredns.1", "Pod", "coredns-0", "FailedScheduling", 1*time.Minute),
+		)
+
+		factory.ClientsetImpl = func(c clientcmd.ClientConfig) (kubernetes.Interface, error) {
+			if c == seedClientConfig {
+				return seedClientset, nil
+			}
+
+			return shootClientset, nil
+		}
+
+		manager.EXPECT().CurrentTarget().Return(currentTarget, nil)
+		manager.EXPECT().GardenClient(gardenName).Return(gardenclient.NewGardenClient(gardenClient), nil)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("should merge the events of the garden and the seed ordered by time", func() {
+		manager.EXPECT().ClientConfig(gomock.Any(), currentTarget.WithControlPlane(true)).Return(seedClientConfig, nil)
+
+		cmd := events.NewCmdEvents(factory, events.NewEventsOptions(streams))
+		cmd.SetArgs([]string{"-o", "json"})
+		Expect(cmd.Execute()).To(Succeed())
+
+		var result []events.Event
+		Expect(json.Unmarshal([]byte(out.String()), &result)).To(Succeed())
+		Expect(result).To(Equal([]events.Event{
+			{
+				Source:        events.SourceGarden,
+				Namespace:     namespace,
+				Object:        "Shoot/my-shoot",
+				Type:          corev1.EventTypeWarning,
+				Reason:        "Reconciling",
+				Message:       "Reconciling message",
+				Count:         1,
+				LastTimestamp: metav1.NewTime(now.Add(-10 * time.Minute)),
+			},
+			{
+				Source:        events.SourceSeed,
+				Namespace:     technicalID,
+				Object:        "Pod/kube-apiserver-0",
+				Type:          corev1.EventTypeWarning,
+				Reason:        "BackOff",
+				Message:       "BackOff message",
+				Count:         1,
+				LastTimestamp: metav1.NewTime(now.Add(-5 * time.Minute)),
+			},
+		}))
+	})
+
+	It("should include the events of the shoot and print a table", func() {
+		manager.EXPECT().ClientConfig(gomock.Any(), currentTarget.WithControlPlane(true)).Return(seedClientConfig, nil)
+		manager.EXPECT().ClientConfig(gomock.Any(), currentTarget.WithControlPlane(false)).Return(shootClientConfig, nil)
+
+		cmd := events.NewCmdEvents(factory, events.NewEventsOptions(streams))
+		cmd.SetArgs([]string{"--include-shoot", "--since", "0"})
+		Expect(cmd.Execute()).To(Succeed())
+
+		lines := out.String()
+		Expect(lines).To(MatchRegexp(`(?s)LAST SEEN.*SOURCE.*Outdated.*Reconciling.*BackOff.*FailedScheduling`))
+		Expect(lines).To(MatchRegexp(`shoot\s+Warning\s+FailedScheduling\s+Pod/coredns-0`))
+		Expect(lines).NotTo(ContainSubstring("Other"))
+	})
+
+	It("should skip the events of the seed if they cannot be read", func() {
+		manager.EXPECT().ClientConfig(gomock.Any(), currentTarget.WithControlPlane(true)).Return(nil, errors.New("forbidden"))
+
+		cmd := events.NewCmdEvents(factory, events.NewEventsOptions(streams))
+		cmd.SetArgs([]string{})
+		Expect(cmd.Execute()).To(Succeed())
+
+		Expect(errOut.String()).To(Equal("Warning: skipping the events of the seed: forbidden\n"))
+		Expect(out.String()).To(ContainSubstring("Reconciling"))
+		Expect(out.String()).NotTo(ContainSubstring("BackOff"))
+	})
+})
@@ -9,6 +9,7 @@ package cmd
 const (
 	EnvGardenHomeDir = envGardenHomeDir
 	EnvSessionID     = envPrefix + "_SESSION_ID"
+	EnvConfigName    = envConfigName
 	ConfigName       = configName
 )
 
@@ -18,4 +19,8 @@ var (
 	ProjectFlagCompletionFunc = projectFlagCompletionFunc
 	GardenFlagCompletionFunc  = gardenFlagCompletionFunc
 	CompletionWrapper         = completionWrapper
+	IsKubectlPlugin           = isKubectlPlugin
+	AdaptToKubectlPlugin      = adaptToKubectlPlugin
 )
+
+var HandleError = handleError
@@ -0,0 +1,18 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package get
+
+import (
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+var CompleteShootColumns = completeShootColumns
+
+func SetNewDynamicClient(f func(clientConfig clientcmd.ClientConfig) (dynamic.Interface, error)) {
+	newDynamicClient = f
+}
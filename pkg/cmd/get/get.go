@@ -0,0 +1,29 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package get
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/gardener/gardenctl-v2/internal/util"
+)
+
+// NewCmdGet returns a new get command.
+func NewCmdGet(f util.Factory, ioStreams util.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "get",
+		Aliases: []string{"list"},
+		Short:   "Display Gardener resources of the current target",
+	}
+
+	cmd.AddCommand(NewCmdGetShoots(f, NewShootsOptions(ioStreams)))
+	cmd.AddCommand(NewCmdGetProjects(f, NewProjectsOptions(ioStreams)))
+	cmd.AddCommand(NewCmdGetSeeds(f, NewSeedsOptions(ioStreams)))
+	cmd.AddCommand(NewCmdGetNodes(f, NewNodesOptions(ioStreams)))
+
+	return cmd
+}
@@ -0,0 +1,315 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package get
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	metav1beta1 "k8s.io/apimachinery/pkg/apis/meta/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
+	"github.com/gardener/gardenctl-v2/pkg/target"
+)
+
+// machineGVR is the resource of the machines of the machine-controller-manager in the control plane namespace of a shoot
+var machineGVR = schema.GroupVersionResource{Group: "machine.sapcloud.io", Version: "v1alpha1", Resource: "machines"}
+
+// newDynamicClient returns a dynamic client for the seed cluster of the client configuration
+var newDynamicClient = func(clientConfig clientcmd.ClientConfig) (dynamic.Interface, error) {
+	restConfig, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return dynamic.NewForConfig(restConfig)
+}
+
+// NewCmdGetNodes returns a new get nodes command.
+func NewCmdGetNodes(f util.Factory, o *NodesOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "nodes",
+		Aliases: []string{"node"},
+		Short:   "List the nodes of the targeted shoot together with their machines",
+		Long: `List the nodes of the targeted shoot together with the machines of the machine-controller-manager in the control plane
+of the shoot on its seed. Nodes and machines are matched by the node name in the machine status, or by the provider ID.
+Each row shows the machine name, the cloud instance ID, the machine state and the conditions of the node. The STATUS
+column is Ready if the node is ready, otherwise NotReady, followed by the pressure and network conditions that are true.
+
+Machines without node, e.g. machines that are still being created, and nodes without machine are listed as well.
+Reading the machines usually requires Gardener operator permissions. If they cannot be read, only the nodes are listed
+with a warning.`,
+		Example: `# list the nodes and machines of the targeted shoot
+gardenctl list nodes
+
+# list the nodes and machines as json
+gardenctl get nodes -o json`,
+		Args: cobra.NoArgs,
+		RunE: base.WrapRunE(o, f),
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// NodesOptions is a struct to support get nodes command
+type NodesOptions struct {
+	base.Options
+}
+
+// NewNodesOptions returns initialized NodesOptions
+func NewNodesOptions(ioStreams util.IOStreams) *NodesOptions {
+	return &NodesOptions{
+		Options: base.Options{
+			IOStreams: ioStreams,
+		},
+	}
+}
+
+// NodeSummary is the machine-readable output of get nodes
+type NodeSummary struct {
+	// Node is the name of the node, it is empty for a machine without node
+	Node string `json:"node,omitempty" yaml:"node,omitempty"`
+	// Machine is the name of the machine, it is empty for a node without machine
+	Machine string `json:"machine,omitempty" yaml:"machine,omitempty"`
+	// InstanceID is the ID of the instance of the cloud provider, i.e. the last segment of the provider ID
+	InstanceID string `json:"instanceID,omitempty" yaml:"instanceID,omitempty"`
+	// MachineState is the phase of the machine, e.g. Running or Pending
+	MachineState string `json:"machineState,omitempty" yaml:"machineState,omitempty"`
+	// Conditions maps the condition types of the node to their status
+	Conditions map[string]string `json:"conditions,omitempty" yaml:"conditions,omitempty"`
+}
+
+// Run executes the command
+func (o *NodesOptions) Run(f util.Factory) error {
+	manager, err := f.Manager()
+	if err != nil {
+		return err
+	}
+
+	currentTarget, err := manager.CurrentTarget()
+	if err != nil {
+		return fmt.Errorf("failed to get current target: %w", err)
+	}
+
+	if currentTarget.ShootName() == "" {
+		return errors.New("no Shoot cluster targeted")
+	}
+
+	ctx := f.Context()
+
+	nodes, err := listNodes(ctx, f, manager, currentTarget.WithControlPlane(false))
+	if err != nil {
+		return err
+	}
+
+	// the client configuration of the control plane points to the control plane namespace on the seed
+	machines, err := listMachines(ctx, manager, currentTarget.WithControlPlane(true))
+	if err != nil {
+		fmt.Fprintf(o.IOStreams.ErrOut, "Warning: listing the nodes without their machines: %v\n", err)
+	}
+
+	summaries := joinNodesAndMachines(nodes, machines)
+
+	if o.Output != "" {
+		return o.PrintObject(summaries)
+	}
+
+	if len(summaries) == 0 {
+		fmt.Fprintf(o.IOStreams.ErrOut, "No nodes found for shoot %s\n", currentTarget.ShootName())
+		return nil
+	}
+
+	table := &metav1beta1.Table{
+		ColumnDefinitions: []metav1.TableColumnDefinition{
+			{Name: "Node", Type: "string"},
+			{Name: "Machine", Type: "string"},
+			{Name: "Instance ID", Type: "string"},
+			{Name: "Machine State", Type: "string"},
+			{Name: "Status", Type: "string"},
+		},
+		Rows: make([]metav1.TableRow, len(summaries)),
+	}
+
+	for i, summary := range summaries {
+		table.Rows[i] = metav1.TableRow{
+			Cells: []interface{}{
+				util.ValueOrNone(summary.Node),
+				util.ValueOrNone(summary.Machine),
+				util.ValueOrNone(summary.InstanceID),
+				util.ValueOrNone(summary.MachineState),
+				nodeStatus(summary),
+			},
+		}
+	}
+
+	printer := printers.NewTablePrinter(printers.PrintOptions{})
+	if err := printer.PrintObj(table, o.IOStreams.Out); err != nil {
+		return fmt.Errorf("failed to output node table: %w", err)
+	}
+
+	return nil
+}
+
+// listNodes lists the nodes of the shoot cluster of the target
+func listNodes(ctx context.Context, f util.Factory, manager target.Manager, t target.Target) ([]corev1.Node, error) {
+	clientConfig, err := manager.ClientConfig(ctx, t)
+	if err != nil {
+		return nil, err
+	}
+
+	clientset, err := f.Clientset(clientConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	nodeList, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	return nodeList.Items, nil
+}
+
+// listMachines lists the machines in the control plane namespace of the target
+func listMachines(ctx context.Context, manager target.Manager, t target.Target) ([]unstructured.Unstructured, error) {
+	clientConfig, err := manager.ClientConfig(ctx, t)
+	if err != nil {
+		return nil, err
+	}
+
+	namespace, _, err := clientConfig.Namespace()
+	if err != nil {
+		return nil, err
+	}
+
+	dynamicClient, err := newDynamicClient(clientConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	machineList, err := dynamicClient.Resource(machineGVR).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list machines: %w", err)
+	}
+
+	return machineList.Items, nil
+}
+
+// joinNodesAndMachines matches the nodes and machines by the node name in the machine status or by the provider ID.
+// The result is sorted by node name, machines without node are sorted by machine name after the nodes.
+func joinNodesAndMachines(nodes []corev1.Node, machines []unstructured.Unstructured) []NodeSummary {
+	summaries := []NodeSummary{}
+	byNodeName := map[string]int{}
+	byProviderID := map[string]int{}
+
+	for _, node := range nodes {
+		summary := NodeSummary{
+			Node:       node.Name,
+			InstanceID: instanceID(node.Spec.ProviderID),
+			Conditions: map[string]string{},
+		}
+
+		for _, condition := range node.Status.Conditions {
+			summary.Conditions[string(condition.Type)] = string(condition.Status)
+		}
+
+		byNodeName[node.Name] = len(summaries)
+		if node.Spec.ProviderID != "" {
+			byProviderID[node.Spec.ProviderID] = len(summaries)
+		}
+
+		summaries = append(summaries, summary)
+	}
+
+	var orphans []NodeSummary
+
+	for _, machine := range machines {
+		nodeName, _, _ := unstructured.NestedString(machine.Object, "status", "node")
+		providerID, _, _ := unstructured.NestedString(machine.Object, "spec", "providerID")
+		phase, _, _ := unstructured.NestedString(machine.Object, "status", "currentStatus", "phase")
+
+		i, ok := byNodeName[nodeName]
+		if !ok {
+			i, ok = byProviderID[providerID]
+		}
+
+		if !ok {
+			orphans = append(orphans, NodeSummary{
+				Machine:      machine.GetName(),
+				InstanceID:   instanceID(providerID),
+				MachineState: phase,
+			})
+
+			continue
+		}
+
+		summaries[i].Machine = machine.GetName()
+		summaries[i].MachineState = phase
+
+		if summaries[i].InstanceID == "" {
+			summaries[i].InstanceID = instanceID(providerID)
+		}
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Node < summaries[j].Node
+	})
+
+	sort.Slice(orphans, func(i, j int) bool {
+		return orphans[i].Machine < orphans[j].Machine
+	})
+
+	return append(summaries, orphans...)
+}
+
+// instanceID returns the ID of the cloud instance, which is the last segment of the provider ID,
+// e.g. i-0123456789abcdef0 for aws:///eu-west-1a/i-0123456789abcdef0
+func instanceID(providerID string) string {
+	if i := strings.LastIndex(providerID, "/"); i >= 0 {
+		return providerID[i+1:]
+	}
+
+	return providerID
+}
+
+// nodeStatus returns Ready or NotReady and the node conditions other than Ready that are true, e.g. MemoryPressure
+func nodeStatus(summary NodeSummary) string {
+	if summary.Node == "" {
+		return "<none>"
+	}
+
+	status := []string{"NotReady"}
+	if summary.Conditions[string(corev1.NodeReady)] == string(corev1.ConditionTrue) {
+		status[0] = "Ready"
+	}
+
+	var problems []string
+
+	for conditionType, conditionStatus := range summary.Conditions {
+		if conditionType != string(corev1.NodeReady) && conditionStatus == string(corev1.ConditionTrue) {
+			problems = append(problems, conditionType)
+		}
+	}
+
+	sort.Strings(problems)
+
+	return strings.Join(append(status, problems...), ",")
+}
@@ -0,0 +1,183 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package get_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/clientcmd"
+
+	internalfake "github.com/gardener/gardenctl-v2/internal/fake"
+	"github.com/gardener/gardenctl-v2/internal/util"
+	cmdget "github.com/gardener/gardenctl-v2/pkg/cmd/get"
+	"github.com/gardener/gardenctl-v2/pkg/target"
+	targetmocks "github.com/gardener/gardenctl-v2/pkg/target/mocks"
+)
+
+var _ = Describe("Get Nodes Command", func() {
+	const technicalID = "shoot--prod1--my-shoot"
+
+	var (
+		ctrl              *gomock.Controller
+		manager           *targetmocks.MockManager
+		factory           *internalfake.Factory
+		out               *util.SafeBytesBuffer
+		errOut            *util.SafeBytesBuffer
+		options           *cmdget.NodesOptions
+		currentTarget     target.Target
+		seedClientConfig  clientcmd.ClientConfig
+		shootClientConfig clientcmd.ClientConfig
+	)
+
+	newNode := func(name, providerID string, ready corev1.ConditionStatus, memoryPressure corev1.ConditionStatus) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       corev1.NodeSpec{ProviderID: providerID},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{
+					{Type: corev1.NodeReady, Status: ready},
+					{Type: corev1.NodeMemoryPressure, Status: memoryPressure},
+				},
+			},
+		}
+	}
+
+	newMachine := func(namespace, name, providerID, nodeName, phase string) *unstructured.Unstructured {
+		machine := &unstructured.Unstructured{}
+		machine.SetAPIVersion("machine.sapcloud.io/v1alpha1")
+		machine.SetKind("Machine")
+		machine.SetNamespace(namespace)
+		machine.SetName(name)
+
+		if providerID != "" {
+			Expect(unstructured.SetNestedField(machine.Object, providerID, "spec", "providerID")).To(Succeed())
+		}
+
+		if nodeName != "" {
+			Expect(unstructured.SetNestedField(machine.Object, nodeName, "status", "node")).To(Succeed())
+		}
+
+		Expect(unstructured.SetNestedField(machine.Object, phase, "status", "currentStatus", "phase")).To(Succeed())
+
+		return machine
+	}
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		manager = targetmocks.NewMockManager(ctrl)
+		factory = internalfake.NewFakeFactory(nil, nil, nil, nil)
+		factory.ManagerImpl = manager
+		factory.ContextImpl = context.Background()
+
+		var streams util.IOStreams
+		streams, _, out, errOut = util.NewTestIOStreams()
+		options = cmdget.NewNodesOptions(streams)
+
+		currentTarget = target.NewTarget("mygarden", "prod1", "", "my-shoot")
+		seedClientConfig = internalfake.NewClientConfig("seed", technicalID)
+		shootClientConfig = internalfake.NewClientConfig("shoot", "default")
+
+		shootClientset := fake.NewSimpleClientset(
+			newNode("node-a", "aws:///eu-west-1a/i-aaa", corev1.ConditionTrue, corev1.ConditionFalse),
+			newNode("node-b", "aws:///eu-west-1b/i-bbb", corev1.ConditionFalse, corev1.ConditionTrue),
+			newNode("node-c", "aws:///eu-west-1c/i-ccc", corev1.ConditionTrue, corev1.ConditionFalse),
+		)
+
+		scheme := runtime.NewScheme()
+		machineGVR := schema.GroupVersionResource{Group: "machine.sapcloud.io", Version: "v1alpha1", Resource: "machines"}
+		seedDynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme,
+			map[schema.GroupVersionResource]string{machineGVR: "MachineList"},
+			newMachine(technicalID, "machine-a", "aws:///eu-west-1a/i-aaa", "node-a", "Running"),
+			// the node is not yet set in the status, the machine is matched by the provider ID
+			newMachine(technicalID, "machine-b", "aws:///eu-west-1b/i-bbb", "", "Unknown"),
+			newMachine(technicalID, "machine-new", "", "", "Pending"),
+			newMachine("shoot--other--shoot", "machine-other", "aws:///eu-west-1c/i-ccc", "node-c", "Running"),
+		)
+
+		factory.ClientsetImpl = func(clientcmd.ClientConfig) (kubernetes.Interface, error) {
+			return shootClientset, nil
+		}
+		cmdget.SetNewDynamicClient(func(clientcmd.ClientConfig) (dynamic.Interface, error) {
+			return seedDynamicClient, nil
+		})
+
+		manager.EXPECT().CurrentTarget().DoAndReturn(func() (target.Target, error) {
+			return currentTarget, nil
+		})
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	run := func() {
+		manager.EXPECT().ClientConfig(gomock.Any(), currentTarget.WithControlPlane(false)).Return(shootClientConfig, nil)
+
+		cmd := cmdget.NewCmdGetNodes(factory, options)
+		ExpectWithOffset(1, cmd.RunE(cmd, nil)).To(Succeed())
+	}
+
+	It("should join the nodes of the shoot with the machines of its control plane", func() {
+		manager.EXPECT().ClientConfig(gomock.Any(), currentTarget.WithControlPlane(true)).Return(seedClientConfig, nil)
+
+		run()
+		Expect(out.String()).To(Equal(`NODE     MACHINE       INSTANCE ID   MACHINE STATE   STATUS
+node-a   machine-a     i-aaa         Running         Ready
+node-b   machine-b     i-bbb         Unknown         NotReady,MemoryPressure
+node-c   <none>        i-ccc         <none>          Ready
+<none>   machine-new   <none>        Pending         <none>
+`))
+		Expect(errOut.String()).To(BeEmpty())
+	})
+
+	It("should print the nodes and machines as json", func() {
+		manager.EXPECT().ClientConfig(gomock.Any(), currentTarget.WithControlPlane(true)).Return(seedClientConfig, nil)
+
+		options.Output = "json"
+		run()
+
+		var summaries []cmdget.NodeSummary
+		Expect(json.Unmarshal([]byte(out.String()), &summaries)).To(Succeed())
+		Expect(summaries).To(HaveLen(4))
+		Expect(summaries[0]).To(Equal(cmdget.NodeSummary{
+			Node:         "node-a",
+			Machine:      "machine-a",
+			InstanceID:   "i-aaa",
+			MachineState: "Running",
+			Conditions:   map[string]string{"Ready": "True", "MemoryPressure": "False"},
+		}))
+	})
+
+	It("should list the nodes without their machines if the seed cannot be read", func() {
+		manager.EXPECT().ClientConfig(gomock.Any(), currentTarget.WithControlPlane(true)).Return(nil, errors.New("forbidden"))
+
+		run()
+		Expect(out.String()).To(ContainSubstring("node-b   <none>    i-bbb         <none>          NotReady,MemoryPressure"))
+		Expect(out.String()).NotTo(ContainSubstring("machine-new"))
+		Expect(errOut.String()).To(Equal("Warning: listing the nodes without their machines: forbidden\n"))
+	})
+
+	It("should fail if no shoot is targeted", func() {
+		currentTarget = target.NewTarget("mygarden", "prod1", "", "")
+		cmd := cmdget.NewCmdGetNodes(factory, options)
+		Expect(cmd.RunE(cmd, nil)).To(MatchError("no Shoot cluster targeted"))
+	})
+})
@@ -0,0 +1,180 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package get
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1beta1 "k8s.io/apimachinery/pkg/apis/meta/v1beta1"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/cli-runtime/pkg/printers"
+
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
+	"github.com/gardener/gardenctl-v2/pkg/target"
+)
+
+// NewCmdGetProjects returns a new get projects command.
+func NewCmdGetProjects(f util.Factory, o *ProjectsOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "projects",
+		Aliases: []string{"project"},
+		Short:   "List the projects of the targeted garden",
+		Long:    "List the projects of the targeted garden with their namespace, owner, number of members and number of shoots.",
+		Example: `# list the projects of the targeted garden
+gardenctl get projects
+
+# list the projects as json, e.g. for reporting scripts
+gardenctl get projects -o json`,
+		Args: cobra.NoArgs,
+		RunE: base.WrapRunE(o, f),
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// ProjectsOptions is a struct to support get projects command
+type ProjectsOptions struct {
+	base.Options
+}
+
+// NewProjectsOptions returns initialized ProjectsOptions
+func NewProjectsOptions(ioStreams util.IOStreams) *ProjectsOptions {
+	return &ProjectsOptions{
+		Options: base.Options{
+			IOStreams: ioStreams,
+		},
+	}
+}
+
+// ProjectSummary is the machine-readable output of get projects
+type ProjectSummary struct {
+	// Name is the name of the project
+	Name string `json:"name" yaml:"name"`
+	// Namespace is the namespace of the project
+	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	// Owner is the name of the owner of the project
+	Owner string `json:"owner,omitempty" yaml:"owner,omitempty"`
+	// Members is the number of members of the project
+	Members int `json:"members" yaml:"members"`
+	// Shoots is the number of shoots of the project
+	Shoots int `json:"shoots" yaml:"shoots"`
+	// CreationTimestamp is the time the project was created
+	CreationTimestamp metav1.Time `json:"creationTimestamp" yaml:"creationTimestamp"`
+}
+
+// Run executes the command
+func (o *ProjectsOptions) Run(f util.Factory) error {
+	manager, err := f.Manager()
+	if err != nil {
+		return err
+	}
+
+	currentTarget, err := manager.CurrentTarget()
+	if err != nil {
+		return fmt.Errorf("failed to get current target: %w", err)
+	}
+
+	if currentTarget.GardenName() == "" {
+		return target.ErrNoGardenTargeted
+	}
+
+	gardenClient, err := manager.GardenClient(currentTarget.GardenName())
+	if err != nil {
+		return fmt.Errorf("failed to create garden cluster client: %w", err)
+	}
+
+	ctx := f.Context()
+
+	projectList, err := gardenClient.ListProjects(ctx)
+	if err != nil {
+		return err
+	}
+
+	shootList, err := gardenClient.ListShoots(ctx)
+	if err != nil {
+		return err
+	}
+
+	shootCounts := map[string]int{}
+	for _, shoot := range shootList.Items {
+		shootCounts[shoot.Namespace]++
+	}
+
+	projects := make([]ProjectSummary, len(projectList.Items))
+
+	for i, project := range projectList.Items {
+		summary := ProjectSummary{
+			Name:              project.Name,
+			Members:           len(project.Spec.Members),
+			CreationTimestamp: project.CreationTimestamp,
+		}
+
+		if project.Spec.Namespace != nil {
+			summary.Namespace = *project.Spec.Namespace
+			summary.Shoots = shootCounts[summary.Namespace]
+		}
+
+		if project.Spec.Owner != nil {
+			summary.Owner = project.Spec.Owner.Name
+		}
+
+		projects[i] = summary
+	}
+
+	sort.Slice(projects, func(i, j int) bool {
+		return projects[i].Name < projects[j].Name
+	})
+
+	if o.Output != "" {
+		return o.PrintObject(projects)
+	}
+
+	if len(projects) == 0 {
+		fmt.Fprintf(o.IOStreams.ErrOut, "No projects found in garden %s\n", currentTarget.GardenName())
+		return nil
+	}
+
+	now := f.Clock().Now()
+
+	table := &metav1beta1.Table{
+		ColumnDefinitions: []metav1.TableColumnDefinition{
+			{Name: "Name", Type: "string", Format: "name"},
+			{Name: "Namespace", Type: "string"},
+			{Name: "Owner", Type: "string"},
+			{Name: "Members", Type: "integer"},
+			{Name: "Shoots", Type: "integer"},
+			{Name: "Age", Type: "string"},
+		},
+		Rows: make([]metav1.TableRow, len(projects)),
+	}
+
+	for i, project := range projects {
+		table.Rows[i] = metav1.TableRow{
+			Cells: []interface{}{
+				project.Name,
+				project.Namespace,
+				project.Owner,
+				project.Members,
+				project.Shoots,
+				duration.HumanDuration(now.Sub(project.CreationTimestamp.Time)),
+			},
+		}
+	}
+
+	printer := printers.NewTablePrinter(printers.PrintOptions{})
+	if err := printer.PrintObj(table, o.IOStreams.Out); err != nil {
+		return fmt.Errorf("failed to output project table: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,123 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package get_test
+
+import (
+	"context"
+	"time"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	internalfake "github.com/gardener/gardenctl-v2/internal/fake"
+	"github.com/gardener/gardenctl-v2/internal/util"
+	cmdget "github.com/gardener/gardenctl-v2/pkg/cmd/get"
+	"github.com/gardener/gardenctl-v2/pkg/config"
+	"github.com/gardener/gardenctl-v2/pkg/target"
+	targetmocks "github.com/gardener/gardenctl-v2/pkg/target/mocks"
+)
+
+var _ = Describe("Get Projects Command", func() {
+	const gardenName = "mygarden"
+
+	var (
+		ctrl    *gomock.Controller
+		streams util.IOStreams
+		out     *util.SafeBytesBuffer
+		factory *internalfake.Factory
+		options *cmdget.ProjectsOptions
+	)
+
+	BeforeEach(func() {
+		now := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+
+		cfg := &config.Config{
+			LinkKubeconfig: pointer.Bool(false),
+			Gardens: []config.Garden{{
+				Name:       gardenName,
+				Kubeconfig: "/not/a/real/kubeconfig",
+			}},
+		}
+
+		newProject := func(name string, members int) *gardencorev1beta1.Project {
+			project := &gardencorev1beta1.Project{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              name,
+					CreationTimestamp: metav1.NewTime(now.Add(-72 * time.Hour)),
+				},
+				Spec: gardencorev1beta1.ProjectSpec{
+					Namespace: pointer.String("garden-" + name),
+					Owner:     &rbacv1.Subject{Kind: rbacv1.UserKind, Name: "alice"},
+				},
+			}
+
+			for i := 0; i < members; i++ {
+				project.Spec.Members = append(project.Spec.Members, gardencorev1beta1.ProjectMember{Role: "viewer"})
+			}
+
+			return project
+		}
+
+		newShoot := func(name, namespace string) *gardencorev1beta1.Shoot {
+			return &gardencorev1beta1.Shoot{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+		}
+
+		gardenClient := internalfake.NewClientWithObjects(
+			newProject("prod1", 2),
+			newProject("dev", 1),
+			newShoot("web", "garden-prod1"),
+			newShoot("db", "garden-prod1"),
+			newShoot("test", "garden-dev"),
+		)
+
+		streams, _, out, _ = util.NewTestIOStreams()
+
+		ctrl = gomock.NewController(GinkgoT())
+
+		clientProvider := targetmocks.NewMockClientProvider(ctrl)
+		clientConfig, err := cfg.ClientConfig(gardenName)
+		Expect(err).ToNot(HaveOccurred())
+		clientProvider.EXPECT().FromClientConfig(gomock.Eq(clientConfig)).Return(gardenClient, nil).AnyTimes()
+
+		targetProvider := internalfake.NewFakeTargetProvider(target.NewTarget(gardenName, "", "", ""))
+
+		factory = internalfake.NewFakeFactory(cfg, fixedClock(now), clientProvider, targetProvider)
+		factory.ContextImpl = context.Background()
+
+		options = cmdget.NewProjectsOptions(streams)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("should list the projects of the targeted garden", func() {
+		cmd := cmdget.NewCmdGetProjects(factory, options)
+		Expect(cmd.RunE(cmd, nil)).To(Succeed())
+		Expect(out.String()).To(Equal(`NAME    NAMESPACE      OWNER   MEMBERS   SHOOTS   AGE
+dev     garden-dev     alice   1         1        3d
+prod1   garden-prod1   alice   2         2        3d
+`))
+	})
+
+	It("should print the projects as yaml", func() {
+		options.Output = "yaml"
+		cmd := cmdget.NewCmdGetProjects(factory, options)
+		Expect(cmd.RunE(cmd, nil)).To(Succeed())
+		Expect(out.String()).To(ContainSubstring(`- name: prod1
+  namespace: garden-prod1
+  owner: alice
+  members: 2
+  shoots: 2
+`))
+	})
+})
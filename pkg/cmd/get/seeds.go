@@ -0,0 +1,237 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package get
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1beta1 "k8s.io/apimachinery/pkg/apis/meta/v1beta1"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/cli-runtime/pkg/printers"
+
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
+	"github.com/gardener/gardenctl-v2/pkg/target"
+)
+
+// NewCmdGetSeeds returns a new get seeds command.
+func NewCmdGetSeeds(f util.Factory, o *SeedsOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "seeds",
+		Aliases: []string{"seed"},
+		Short:   "List the seeds of the targeted garden",
+		Long: `List the seeds of the targeted garden with their provider, region, visibility and conditions.
+The SHOOTS column shows the number of shoots scheduled to the seed and the number of allocatable shoots, if the seed
+limits it. The STATUS column is Ready if all conditions are true, otherwise it lists the conditions that are not true.`,
+		Example: `# list the seeds of the targeted garden
+gardenctl get seeds
+
+# list the seeds as json, e.g. for reporting scripts
+gardenctl get seeds -o json`,
+		Args: cobra.NoArgs,
+		RunE: base.WrapRunE(o, f),
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// SeedsOptions is a struct to support get seeds command
+type SeedsOptions struct {
+	base.Options
+}
+
+// NewSeedsOptions returns initialized SeedsOptions
+func NewSeedsOptions(ioStreams util.IOStreams) *SeedsOptions {
+	return &SeedsOptions{
+		Options: base.Options{
+			IOStreams: ioStreams,
+		},
+	}
+}
+
+// SeedSummary is the machine-readable output of get seeds
+type SeedSummary struct {
+	// Name is the name of the seed
+	Name string `json:"name" yaml:"name"`
+	// Provider is the provider type of the seed
+	Provider string `json:"provider" yaml:"provider"`
+	// Region is the region of the seed
+	Region string `json:"region" yaml:"region"`
+	// Visible is false if the scheduler does not consider the seed for new shoots
+	Visible bool `json:"visible" yaml:"visible"`
+	// Conditions maps the condition types of the seed to their status
+	Conditions map[string]string `json:"conditions,omitempty" yaml:"conditions,omitempty"`
+	// Shoots is the number of shoots scheduled to the seed
+	Shoots int64 `json:"shoots" yaml:"shoots"`
+	// AllocatableShoots is the number of shoots that can be scheduled to the seed, nil if unlimited
+	AllocatableShoots *int64 `json:"allocatableShoots,omitempty" yaml:"allocatableShoots,omitempty"`
+	// CreationTimestamp is the time the seed was created
+	CreationTimestamp metav1.Time `json:"creationTimestamp" yaml:"creationTimestamp"`
+}
+
+// Run executes the command
+func (o *SeedsOptions) Run(f util.Factory) error {
+	manager, err := f.Manager()
+	if err != nil {
+		return err
+	}
+
+	currentTarget, err := manager.CurrentTarget()
+	if err != nil {
+		return fmt.Errorf("failed to get current target: %w", err)
+	}
+
+	if currentTarget.GardenName() == "" {
+		return target.ErrNoGardenTargeted
+	}
+
+	gardenClient, err := manager.GardenClient(currentTarget.GardenName())
+	if err != nil {
+		return fmt.Errorf("failed to create garden cluster client: %w", err)
+	}
+
+	ctx := f.Context()
+
+	seedList, err := gardenClient.ListSeeds(ctx)
+	if err != nil {
+		return err
+	}
+
+	shootList, err := gardenClient.ListShoots(ctx)
+	if err != nil {
+		return err
+	}
+
+	shootCounts := map[string]int64{}
+
+	for _, shoot := range shootList.Items {
+		if shoot.Spec.SeedName != nil {
+			shootCounts[*shoot.Spec.SeedName]++
+		}
+	}
+
+	seeds := make([]SeedSummary, len(seedList.Items))
+	for i, seed := range seedList.Items {
+		seeds[i] = summarizeSeed(seed, shootCounts[seed.Name])
+	}
+
+	sort.Slice(seeds, func(i, j int) bool {
+		return seeds[i].Name < seeds[j].Name
+	})
+
+	if o.Output != "" {
+		return o.PrintObject(seeds)
+	}
+
+	if len(seeds) == 0 {
+		fmt.Fprintf(o.IOStreams.ErrOut, "No seeds found in garden %s\n", currentTarget.GardenName())
+		return nil
+	}
+
+	now := f.Clock().Now()
+
+	table := &metav1beta1.Table{
+		ColumnDefinitions: []metav1.TableColumnDefinition{
+			{Name: "Name", Type: "string", Format: "name"},
+			{Name: "Provider", Type: "string"},
+			{Name: "Region", Type: "string"},
+			{Name: "Visible", Type: "string"},
+			{Name: "Status", Type: "string"},
+			{Name: "Shoots", Type: "string"},
+			{Name: "Age", Type: "string"},
+		},
+		Rows: make([]metav1.TableRow, len(seeds)),
+	}
+
+	for i, seed := range seeds {
+		shoots := fmt.Sprintf("%d", seed.Shoots)
+		if seed.AllocatableShoots != nil {
+			shoots = fmt.Sprintf("%d/%d", seed.Shoots, *seed.AllocatableShoots)
+		}
+
+		table.Rows[i] = metav1.TableRow{
+			Cells: []interface{}{
+				seed.Name,
+				seed.Provider,
+				seed.Region,
+				fmt.Sprintf("%t", seed.Visible),
+				seedStatus(seed),
+				shoots,
+				duration.HumanDuration(now.Sub(seed.CreationTimestamp.Time)),
+			},
+		}
+	}
+
+	printer := printers.NewTablePrinter(printers.PrintOptions{})
+	if err := printer.PrintObj(table, o.IOStreams.Out); err != nil {
+		return fmt.Errorf("failed to output seed table: %w", err)
+	}
+
+	return nil
+}
+
+func summarizeSeed(seed gardencorev1beta1.Seed, shoots int64) SeedSummary {
+	summary := SeedSummary{
+		Name:              seed.Name,
+		Provider:          seed.Spec.Provider.Type,
+		Region:            seed.Spec.Provider.Region,
+		Visible:           true,
+		Conditions:        map[string]string{},
+		Shoots:            shoots,
+		CreationTimestamp: seed.CreationTimestamp,
+	}
+
+	if settings := seed.Spec.Settings; settings != nil && settings.Scheduling != nil {
+		summary.Visible = settings.Scheduling.Visible
+	}
+
+	for _, condition := range seed.Status.Conditions {
+		summary.Conditions[string(condition.Type)] = string(condition.Status)
+	}
+
+	allocatable, ok := seed.Status.Allocatable[gardencorev1beta1.ResourceShoots]
+	if !ok {
+		allocatable, ok = seed.Status.Capacity[gardencorev1beta1.ResourceShoots]
+	}
+
+	if ok {
+		value := allocatable.Value()
+		summary.AllocatableShoots = &value
+	}
+
+	return summary
+}
+
+// seedStatus returns Ready if all conditions of the seed are true, otherwise the conditions that are not true
+func seedStatus(seed SeedSummary) string {
+	if len(seed.Conditions) == 0 {
+		return "Unknown"
+	}
+
+	notReady := []string{}
+
+	for conditionType, status := range seed.Conditions {
+		if status != string(gardencorev1beta1.ConditionTrue) {
+			notReady = append(notReady, fmt.Sprintf("%s=%s", conditionType, status))
+		}
+	}
+
+	if len(notReady) == 0 {
+		return "Ready"
+	}
+
+	sort.Strings(notReady)
+
+	return strings.Join(notReady, ",")
+}
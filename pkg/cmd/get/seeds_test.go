@@ -0,0 +1,134 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package get_test
+
+import (
+	"context"
+	"time"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	internalfake "github.com/gardener/gardenctl-v2/internal/fake"
+	"github.com/gardener/gardenctl-v2/internal/util"
+	cmdget "github.com/gardener/gardenctl-v2/pkg/cmd/get"
+	"github.com/gardener/gardenctl-v2/pkg/config"
+	"github.com/gardener/gardenctl-v2/pkg/target"
+	targetmocks "github.com/gardener/gardenctl-v2/pkg/target/mocks"
+)
+
+var _ = Describe("Get Seeds Command", func() {
+	const gardenName = "mygarden"
+
+	var (
+		ctrl    *gomock.Controller
+		streams util.IOStreams
+		out     *util.SafeBytesBuffer
+		factory *internalfake.Factory
+		options *cmdget.SeedsOptions
+	)
+
+	BeforeEach(func() {
+		now := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+
+		cfg := &config.Config{
+			LinkKubeconfig: pointer.Bool(false),
+			Gardens: []config.Garden{{
+				Name:       gardenName,
+				Kubeconfig: "/not/a/real/kubeconfig",
+			}},
+		}
+
+		ready := &gardencorev1beta1.Seed{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "aws-eu1",
+				CreationTimestamp: metav1.NewTime(now.Add(-24 * time.Hour)),
+			},
+			Spec: gardencorev1beta1.SeedSpec{
+				Provider: gardencorev1beta1.SeedProvider{Type: "aws", Region: "eu-west-1"},
+			},
+			Status: gardencorev1beta1.SeedStatus{
+				Conditions: []gardencorev1beta1.Condition{
+					{Type: "GardenletReady", Status: gardencorev1beta1.ConditionTrue},
+					{Type: "Bootstrapped", Status: gardencorev1beta1.ConditionTrue},
+				},
+				Allocatable: corev1.ResourceList{gardencorev1beta1.ResourceShoots: resource.MustParse("3")},
+			},
+		}
+
+		invisible := &gardencorev1beta1.Seed{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "gcp-us1",
+				CreationTimestamp: metav1.NewTime(now.Add(-24 * time.Hour)),
+			},
+			Spec: gardencorev1beta1.SeedSpec{
+				Provider: gardencorev1beta1.SeedProvider{Type: "gcp", Region: "us-east1"},
+				Settings: &gardencorev1beta1.SeedSettings{
+					Scheduling: &gardencorev1beta1.SeedSettingScheduling{Visible: false},
+				},
+			},
+			Status: gardencorev1beta1.SeedStatus{
+				Conditions: []gardencorev1beta1.Condition{
+					{Type: "GardenletReady", Status: gardencorev1beta1.ConditionUnknown},
+					{Type: "Bootstrapped", Status: gardencorev1beta1.ConditionTrue},
+				},
+			},
+		}
+
+		newShoot := func(name, seedName string) *gardencorev1beta1.Shoot {
+			return &gardencorev1beta1.Shoot{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "garden-prod1"},
+				Spec:       gardencorev1beta1.ShootSpec{SeedName: pointer.String(seedName)},
+			}
+		}
+
+		gardenClient := internalfake.NewClientWithObjects(ready, invisible, newShoot("web", "aws-eu1"), newShoot("db", "aws-eu1"))
+
+		streams, _, out, _ = util.NewTestIOStreams()
+
+		ctrl = gomock.NewController(GinkgoT())
+
+		clientProvider := targetmocks.NewMockClientProvider(ctrl)
+		clientConfig, err := cfg.ClientConfig(gardenName)
+		Expect(err).ToNot(HaveOccurred())
+		clientProvider.EXPECT().FromClientConfig(gomock.Eq(clientConfig)).Return(gardenClient, nil).AnyTimes()
+
+		targetProvider := internalfake.NewFakeTargetProvider(target.NewTarget(gardenName, "", "", ""))
+
+		factory = internalfake.NewFakeFactory(cfg, fixedClock(now), clientProvider, targetProvider)
+		factory.ContextImpl = context.Background()
+
+		options = cmdget.NewSeedsOptions(streams)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("should list the seeds with their status and shoot capacity", func() {
+		cmd := cmdget.NewCmdGetSeeds(factory, options)
+		Expect(cmd.RunE(cmd, nil)).To(Succeed())
+		Expect(out.String()).To(Equal(`NAME      PROVIDER   REGION      VISIBLE   STATUS                   SHOOTS   AGE
+aws-eu1   aws        eu-west-1   true      Ready                    2/3      24h
+gcp-us1   gcp        us-east1    false     GardenletReady=Unknown   0        24h
+`))
+	})
+
+	It("should print the seeds as json", func() {
+		options.Output = "json"
+		cmd := cmdget.NewCmdGetSeeds(factory, options)
+		Expect(cmd.RunE(cmd, nil)).To(Succeed())
+		Expect(out.String()).To(ContainSubstring(`"shoots": 2,
+    "allocatableShoots": 3,`))
+	})
+})
@@ -0,0 +1,487 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package get
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	corev1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1beta1 "k8s.io/apimachinery/pkg/apis/meta/v1beta1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/util/duration"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/cli-runtime/pkg/printers"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/gardener/gardenctl-v2/internal/gardenclient"
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
+	"github.com/gardener/gardenctl-v2/pkg/target"
+)
+
+// outputWide is the output format that prints additional columns
+const outputWide = "wide"
+
+// gardenShoot is a shoot together with the name of the garden it belongs to
+type gardenShoot struct {
+	// Garden is the name of the garden of the shoot
+	Garden string `json:"garden"`
+
+	gardencorev1beta1.Shoot
+}
+
+// shootColumn is a column of the shoot table
+type shootColumn struct {
+	// name is used to select the column with the --columns flag
+	name string
+	// wide columns are only printed with --output wide
+	wide bool
+	// value returns the value of the column for the given shoot
+	value func(shoot gardenShoot, now time.Time) string
+}
+
+// gardenColumn is the first column of the shoot table if the shoots of all gardens are listed
+var gardenColumn = shootColumn{name: "garden", value: func(shoot gardenShoot, _ time.Time) string {
+	return shoot.Garden
+}}
+
+// shootColumns are all columns that can be printed, in the order they are printed
+var shootColumns = []shootColumn{
+	{name: "name", value: func(shoot gardenShoot, _ time.Time) string {
+		return shoot.Name
+	}},
+	{name: "namespace", value: func(shoot gardenShoot, _ time.Time) string {
+		return shoot.Namespace
+	}},
+	{name: "seed", value: func(shoot gardenShoot, _ time.Time) string {
+		if shoot.Spec.SeedName == nil {
+			return "<none>"
+		}
+
+		return *shoot.Spec.SeedName
+	}},
+	{name: "provider", value: func(shoot gardenShoot, _ time.Time) string {
+		return shoot.Spec.Provider.Type
+	}},
+	{name: "region", value: func(shoot gardenShoot, _ time.Time) string {
+		return shoot.Spec.Region
+	}},
+	{name: "version", value: func(shoot gardenShoot, _ time.Time) string {
+		return shoot.Spec.Kubernetes.Version
+	}},
+	{name: "hibernated", value: func(shoot gardenShoot, _ time.Time) string {
+		return fmt.Sprintf("%t", util.IsHibernated(shoot.Shoot))
+	}},
+	{name: "status", value: func(shoot gardenShoot, _ time.Time) string {
+		if status, ok := shoot.Labels[corev1beta1constants.ShootStatus]; ok {
+			return status
+		}
+
+		return "unknown"
+	}},
+	{name: "operation", value: func(shoot gardenShoot, _ time.Time) string {
+		if op := shoot.Status.LastOperation; op != nil {
+			return fmt.Sprintf("%s %s (%d%%)", op.Type, op.State, op.Progress)
+		}
+
+		return "<none>"
+	}},
+	{name: "age", value: func(shoot gardenShoot, now time.Time) string {
+		return duration.HumanDuration(now.Sub(shoot.CreationTimestamp.Time))
+	}},
+	{name: "cloudprofile", wide: true, value: func(shoot gardenShoot, _ time.Time) string {
+		return shoot.Spec.CloudProfileName
+	}},
+	{name: "purpose", wide: true, value: func(shoot gardenShoot, _ time.Time) string {
+		if shoot.Spec.Purpose == nil {
+			return "<none>"
+		}
+
+		return string(*shoot.Spec.Purpose)
+	}},
+	{name: "technical-id", wide: true, value: func(shoot gardenShoot, _ time.Time) string {
+		return shoot.Status.TechnicalID
+	}},
+	{name: "created-by", wide: true, value: func(shoot gardenShoot, _ time.Time) string {
+		return shoot.Annotations[corev1beta1constants.GardenCreatedBy]
+	}},
+}
+
+// NewCmdGetShoots returns a new get shoots command.
+func NewCmdGetShoots(f util.Factory, o *ShootsOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "shoots",
+		Aliases: []string{"shoot"},
+		Short:   "List the shoots of the current target",
+		Long: `List the shoots of the current target, i.e. of the targeted project or seed, or of all projects of the targeted garden.
+The label selector, --unhealthy and --provider are evaluated by the garden cluster. As there is no label
+for hibernated shoots, --hibernated is evaluated by gardenctl.`,
+		Example: `# list the shoots of the targeted project
+gardenctl get shoots
+
+# list the unhealthy aws shoots of all projects
+gardenctl get shoots --all-namespaces --unhealthy --provider aws
+
+# list the shoots with the given label and print only some columns
+gardenctl get shoots -l env=dev --columns name,namespace,status
+
+# list the shoots with additional columns
+gardenctl get shoots -o wide
+
+# list the hibernated shoots of all configured gardens
+gardenctl get shoots --all-gardens --hibernated`,
+		Args: cobra.NoArgs,
+		RunE: base.WrapRunE(o, f),
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	utilruntime.Must(cmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return util.FilterStringsByPrefix(toComplete, []string{base.OutputJSON, base.OutputName, base.OutputTable, outputWide, base.OutputYAML}), cobra.ShellCompDirectiveNoFileComp
+	}))
+	utilruntime.Must(cmd.RegisterFlagCompletionFunc("columns", completeShootColumns))
+
+	return cmd
+}
+
+// completeShootColumns completes the last column of the comma separated --columns value
+func completeShootColumns(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	head, last := "", toComplete
+	if i := strings.LastIndex(toComplete, ","); i >= 0 {
+		head, last = toComplete[:i+1], toComplete[i+1:]
+	}
+
+	var suggestions []string
+	for _, name := range util.FilterStringsByPrefix(last, shootColumnNames()) {
+		suggestions = append(suggestions, head+name)
+	}
+
+	return suggestions, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+}
+
+// ShootsOptions is a struct to support get shoots command
+type ShootsOptions struct {
+	base.Options
+
+	// Selector is a label selector to filter the shoots
+	Selector string
+
+	// AllNamespaces lists the shoots of all projects instead of the targeted one
+	AllNamespaces bool
+
+	// AllGardens lists the shoots of all projects of all configured gardens
+	AllGardens bool
+
+	// Unhealthy lists only the shoots whose status label is unhealthy
+	Unhealthy bool
+
+	// Hibernated lists only the hibernated shoots
+	Hibernated bool
+
+	// Provider lists only the shoots of the given provider type, e.g. aws
+	Provider string
+
+	// Columns are the names of the columns that are printed
+	Columns []string
+}
+
+// NewShootsOptions returns initialized ShootsOptions
+func NewShootsOptions(ioStreams util.IOStreams) *ShootsOptions {
+	return &ShootsOptions{
+		Options: base.Options{
+			IOStreams: ioStreams,
+		},
+	}
+}
+
+// AddFlags binds the command options to a given flagset
+func (o *ShootsOptions) AddFlags(flags *pflag.FlagSet) {
+	flags.StringVarP(&o.Output, "output", "o", o.Output, base.OutputFormatsUsage(outputWide))
+	flags.StringVarP(&o.Selector, "selector", "l", o.Selector, "Label selector to filter the shoots, e.g. env=dev")
+	flags.BoolVarP(&o.AllNamespaces, "all-namespaces", "A", o.AllNamespaces, "List the shoots of all projects of the targeted garden.")
+	flags.BoolVar(&o.AllGardens, "all-gardens", o.AllGardens, "List the shoots of all projects of all configured gardens. The gardens are queried concurrently.")
+	flags.BoolVar(&o.Unhealthy, "unhealthy", o.Unhealthy, "List only the unhealthy shoots.")
+	flags.BoolVar(&o.Hibernated, "hibernated", o.Hibernated, "List only the hibernated shoots.")
+	flags.StringVar(&o.Provider, "provider", o.Provider, "List only the shoots of the given provider type, e.g. aws")
+	flags.StringSliceVar(&o.Columns, "columns", o.Columns, fmt.Sprintf("Comma separated list of columns to print, any of %s.", strings.Join(shootColumnNames(), ", ")))
+}
+
+// Validate validates the provided ShootsOptions
+func (o *ShootsOptions) Validate() error {
+	if err := base.ValidateOutput(o.Output, outputWide); err != nil {
+		return err
+	}
+
+	if o.Output == base.OutputTable {
+		o.Output = ""
+	}
+
+	if o.Output != "" && o.Output != outputWide && len(o.Columns) > 0 {
+		return errors.New("--columns can only be used together with the table or wide output")
+	}
+
+	for _, name := range o.Columns {
+		if findShootColumn(name) == nil {
+			return fmt.Errorf("column %q is invalid, must be any of %s", name, strings.Join(shootColumnNames(), ", "))
+		}
+	}
+
+	if _, err := labels.Parse(o.Selector); err != nil {
+		return fmt.Errorf("invalid label selector: %w", err)
+	}
+
+	return nil
+}
+
+// Run executes the command
+func (o *ShootsOptions) Run(f util.Factory) error {
+	manager, err := f.Manager()
+	if err != nil {
+		return err
+	}
+
+	selector, err := o.labelSelector()
+	if err != nil {
+		return err
+	}
+
+	var (
+		shoots      []gardenShoot
+		description string
+	)
+
+	if o.AllGardens {
+		description = "any garden"
+		shoots, err = o.listShootsOfAllGardens(f, manager, selector)
+	} else {
+		var currentTarget target.Target
+
+		currentTarget, err = manager.CurrentTarget()
+		if err != nil {
+			return fmt.Errorf("failed to get current target: %w", err)
+		}
+
+		description = fmt.Sprintf("target %s", currentTarget)
+		shoots, err = o.listShoots(f, manager, currentTarget, selector)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(shoots, func(i, j int) bool {
+		if shoots[i].Garden != shoots[j].Garden {
+			return shoots[i].Garden < shoots[j].Garden
+		}
+
+		if shoots[i].Namespace != shoots[j].Namespace {
+			return shoots[i].Namespace < shoots[j].Namespace
+		}
+
+		return shoots[i].Name < shoots[j].Name
+	})
+
+	if o.Output != "" && o.Output != outputWide {
+		if o.AllGardens {
+			return o.PrintObject(shoots)
+		}
+
+		plainShoots := make([]gardencorev1beta1.Shoot, len(shoots))
+		for i, shoot := range shoots {
+			plainShoots[i] = shoot.Shoot
+		}
+
+		return o.PrintObject(plainShoots)
+	}
+
+	if len(shoots) == 0 {
+		fmt.Fprintf(o.IOStreams.ErrOut, "No shoots found for %s\n", description)
+		return nil
+	}
+
+	return o.printTable(shoots, f.Clock().Now())
+}
+
+// listShoots returns the shoots of the current target
+func (o *ShootsOptions) listShoots(f util.Factory, manager target.Manager, currentTarget target.Target, selector labels.Selector) ([]gardenShoot, error) {
+	if currentTarget.GardenName() == "" {
+		return nil, target.ErrNoGardenTargeted
+	}
+
+	gardenClient, err := manager.GardenClient(currentTarget.GardenName())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create garden cluster client: %w", err)
+	}
+
+	opts := []client.ListOption{client.MatchingLabelsSelector{Selector: selector}}
+	if !o.AllNamespaces {
+		opts = append(opts, currentTarget.WithShootName("").AsListOption())
+	}
+
+	shootList, err := gardenClient.ListShoots(f.Context(), opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return o.filterShoots(currentTarget.GardenName(), shootList.Items), nil
+}
+
+// listShootsOfAllGardens returns the shoots of all configured gardens. A garden that cannot be
+// queried is reported as warning, it only fails the command if no garden can be queried at all.
+func (o *ShootsOptions) listShootsOfAllGardens(f util.Factory, manager target.Manager, selector labels.Selector) ([]gardenShoot, error) {
+	var (
+		shootsLock sync.Mutex
+		shoots     []gardenShoot
+	)
+
+	gardenErrs := manager.ForEachGarden(f.Context(), func(ctx context.Context, gardenName string, gardenClient gardenclient.Client) error {
+		shootList, err := gardenClient.ListShoots(ctx, client.MatchingLabelsSelector{Selector: selector})
+		if err != nil {
+			return err
+		}
+
+		shootsLock.Lock()
+		defer shootsLock.Unlock()
+
+		shoots = append(shoots, o.filterShoots(gardenName, shootList.Items)...)
+
+		return nil
+	})
+
+	gardens := manager.Configuration().Gardens
+	if len(gardens) > 0 && len(gardenErrs) == len(gardens) {
+		return nil, fmt.Errorf("failed to list shoots of all gardens: %w", gardenErrs[0])
+	}
+
+	for _, gardenErr := range gardenErrs {
+		if target.IsDuplicateGarden(gardenErr) {
+			fmt.Fprintf(o.IOStreams.ErrOut, "Warning: garden %s is %v\n", gardenErr.Garden, gardenErr.Err)
+			continue
+		}
+
+		fmt.Fprintf(o.IOStreams.ErrOut, "Warning: failed to list shoots of garden %s: %v\n", gardenErr.Garden, gardenErr.Err)
+	}
+
+	return shoots, nil
+}
+
+// filterShoots returns the shoots that match the filters which are not evaluated by the garden cluster
+func (o *ShootsOptions) filterShoots(gardenName string, items []gardencorev1beta1.Shoot) []gardenShoot {
+	shoots := []gardenShoot{}
+
+	for _, shoot := range items {
+		if !o.Hibernated || util.IsHibernated(shoot) {
+			shoots = append(shoots, gardenShoot{Garden: gardenName, Shoot: shoot})
+		}
+	}
+
+	return shoots
+}
+
+// labelSelector returns the label selector that is evaluated by the garden cluster
+func (o *ShootsOptions) labelSelector() (labels.Selector, error) {
+	selector, err := labels.Parse(o.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid label selector: %w", err)
+	}
+
+	if o.Unhealthy {
+		requirement, err := labels.NewRequirement(corev1beta1constants.ShootStatus, selection.Equals, []string{"unhealthy"})
+		if err != nil {
+			return nil, err
+		}
+
+		selector = selector.Add(*requirement)
+	}
+
+	if o.Provider != "" {
+		requirement, err := labels.NewRequirement(corev1beta1constants.LabelShootProvider, selection.Equals, []string{o.Provider})
+		if err != nil {
+			return nil, fmt.Errorf("invalid provider: %w", err)
+		}
+
+		selector = selector.Add(*requirement)
+	}
+
+	return selector, nil
+}
+
+// printTable prints the selected columns of the shoots as table
+func (o *ShootsOptions) printTable(shoots []gardenShoot, now time.Time) error {
+	columns := []shootColumn{}
+
+	if o.AllGardens {
+		columns = append(columns, gardenColumn)
+	}
+
+	if len(o.Columns) > 0 {
+		for _, name := range o.Columns {
+			columns = append(columns, *findShootColumn(name))
+		}
+	} else {
+		for _, column := range shootColumns {
+			if !column.wide || o.Output == outputWide {
+				columns = append(columns, column)
+			}
+		}
+	}
+
+	table := &metav1beta1.Table{
+		ColumnDefinitions: make([]metav1.TableColumnDefinition, len(columns)),
+		Rows:              make([]metav1.TableRow, len(shoots)),
+	}
+
+	for i, column := range columns {
+		table.ColumnDefinitions[i] = metav1.TableColumnDefinition{Name: column.name, Type: "string"}
+	}
+
+	for i, shoot := range shoots {
+		cells := make([]interface{}, len(columns))
+		for j, column := range columns {
+			cells[j] = column.value(shoot, now)
+		}
+
+		table.Rows[i] = metav1.TableRow{Cells: cells}
+	}
+
+	printer := printers.NewTablePrinter(printers.PrintOptions{})
+	if err := printer.PrintObj(table, o.IOStreams.Out); err != nil {
+		return fmt.Errorf("failed to output shoot table: %w", err)
+	}
+
+	return nil
+}
+
+func findShootColumn(name string) *shootColumn {
+	for i := range shootColumns {
+		if shootColumns[i].name == name {
+			return &shootColumns[i]
+		}
+	}
+
+	return nil
+}
+
+func shootColumnNames() []string {
+	names := make([]string, len(shootColumns))
+	for i, column := range shootColumns {
+		names[i] = column.name
+	}
+
+	return names
+}
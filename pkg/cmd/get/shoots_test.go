@@ -0,0 +1,243 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package get_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	corev1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	internalfake "github.com/gardener/gardenctl-v2/internal/fake"
+	"github.com/gardener/gardenctl-v2/internal/util"
+	cmdget "github.com/gardener/gardenctl-v2/pkg/cmd/get"
+	"github.com/gardener/gardenctl-v2/pkg/config"
+	"github.com/gardener/gardenctl-v2/pkg/target"
+	targetmocks "github.com/gardener/gardenctl-v2/pkg/target/mocks"
+)
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time {
+	return time.Time(c)
+}
+
+var _ = Describe("Get Shoots Command", func() {
+	const (
+		gardenName       = "mygarden"
+		brokenGardenName = "brokengarden"
+	)
+
+	var (
+		ctrl           *gomock.Controller
+		streams        util.IOStreams
+		out            *util.SafeBytesBuffer
+		errOut         *util.SafeBytesBuffer
+		factory        *internalfake.Factory
+		targetProvider *internalfake.TargetProvider
+		options        *cmdget.ShootsOptions
+		now            time.Time
+	)
+
+	newShoot := func(name, namespace, provider, status string, hibernated bool) *gardencorev1beta1.Shoot {
+		return &gardencorev1beta1.Shoot{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         namespace,
+				CreationTimestamp: metav1.NewTime(now.Add(-48 * time.Hour)),
+				Labels: map[string]string{
+					corev1beta1constants.ShootStatus:        status,
+					corev1beta1constants.LabelShootProvider: provider,
+				},
+			},
+			Spec: gardencorev1beta1.ShootSpec{
+				CloudProfileName: provider,
+				Hibernation:      &gardencorev1beta1.Hibernation{Enabled: pointer.Bool(hibernated)},
+				Kubernetes:       gardencorev1beta1.Kubernetes{Version: "1.22.2"},
+				Provider:         gardencorev1beta1.Provider{Type: provider},
+				Region:           "eu-west-1",
+				SeedName:         pointer.String("myseed"),
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		now = time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+
+		cfg := &config.Config{
+			LinkKubeconfig: pointer.Bool(false),
+			Gardens: []config.Garden{{
+				Name:       gardenName,
+				Kubeconfig: "/not/a/real/kubeconfig",
+			}, {
+				Name:       brokenGardenName,
+				Kubeconfig: "/not/a/real/broken/kubeconfig",
+			}},
+		}
+
+		project := &gardencorev1beta1.Project{
+			ObjectMeta: metav1.ObjectMeta{Name: "prod1"},
+			Spec:       gardencorev1beta1.ProjectSpec{Namespace: pointer.String("garden-prod1")},
+		}
+
+		gardenClient := internalfake.NewClientWithObjects(
+			project,
+			newShoot("web", "garden-prod1", "aws", "healthy", false),
+			newShoot("db", "garden-prod1", "gcp", "unhealthy", false),
+			newShoot("batch", "garden-prod1", "aws", "healthy", true),
+			newShoot("other", "garden-prod2", "aws", "unhealthy", false),
+		)
+
+		streams, _, out, errOut = util.NewTestIOStreams()
+
+		ctrl = gomock.NewController(GinkgoT())
+
+		clientProvider := targetmocks.NewMockClientProvider(ctrl)
+		clientConfig, err := cfg.ClientConfig(gardenName)
+		Expect(err).ToNot(HaveOccurred())
+		clientProvider.EXPECT().FromClientConfig(gomock.Eq(clientConfig)).Return(gardenClient, nil).AnyTimes()
+		brokenClientConfig, err := cfg.ClientConfig(brokenGardenName)
+		Expect(err).ToNot(HaveOccurred())
+		clientProvider.EXPECT().FromClientConfig(gomock.Eq(brokenClientConfig)).Return(nil, errors.New("connection refused")).AnyTimes()
+
+		targetProvider = internalfake.NewFakeTargetProvider(target.NewTarget(gardenName, "prod1", "", ""))
+
+		factory = internalfake.NewFakeFactory(cfg, fixedClock(now), clientProvider, targetProvider)
+		factory.ContextImpl = context.Background()
+
+		options = cmdget.NewShootsOptions(streams)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	run := func() {
+		cmd := cmdget.NewCmdGetShoots(factory, options)
+		Expect(cmd.RunE(cmd, nil)).To(Succeed())
+	}
+
+	It("should list the shoots of the targeted project", func() {
+		run()
+		Expect(out.String()).To(Equal(`NAME    NAMESPACE      SEED     PROVIDER   REGION      VERSION   HIBERNATED   STATUS      OPERATION   AGE
+batch   garden-prod1   myseed   aws        eu-west-1   1.22.2    true         healthy     <none>      2d
+db      garden-prod1   myseed   gcp        eu-west-1   1.22.2    false        unhealthy   <none>      2d
+web     garden-prod1   myseed   aws        eu-west-1   1.22.2    false        healthy     <none>      2d
+`))
+	})
+
+	It("should list the unhealthy shoots of a provider in all namespaces", func() {
+		options.AllNamespaces = true
+		options.Unhealthy = true
+		options.Provider = "aws"
+		options.Columns = []string{"name", "namespace"}
+		run()
+		Expect(out.String()).To(Equal("NAME    NAMESPACE\nother   garden-prod2\n"))
+	})
+
+	It("should list the hibernated shoots matching the label selector", func() {
+		options.Selector = corev1beta1constants.LabelShootProvider + "=aws"
+		options.Hibernated = true
+		options.Columns = []string{"name"}
+		run()
+		Expect(out.String()).To(Equal("NAME\nbatch\n"))
+	})
+
+	It("should print additional columns with wide output", func() {
+		options.Output = "wide"
+		options.Selector = corev1beta1constants.LabelShootProvider + "=gcp"
+		run()
+		Expect(out.String()).To(MatchRegexp(`AGE\s+CLOUDPROFILE\s+PURPOSE\s+TECHNICAL-ID\s+CREATED-BY\n`))
+		Expect(out.String()).To(MatchRegexp(`\ndb\s+garden-prod1\s+.*\s+2d\s+gcp\s+evaluation\s+`))
+	})
+
+	It("should print the shoots as json", func() {
+		options.Output = "json"
+		options.Unhealthy = true
+		run()
+		Expect(out.String()).To(HavePrefix("[\n  {\n"))
+		Expect(out.String()).To(ContainSubstring(`"name": "db"`))
+		Expect(out.String()).NotTo(ContainSubstring(`"name": "web"`))
+	})
+
+	It("should print the names of the shoots", func() {
+		options.Output = "name"
+		run()
+		Expect(out.String()).To(Equal("batch\ndb\nweb\n"))
+	})
+
+	It("should print the result of a jsonpath expression", func() {
+		options.Output = "jsonpath={[*].spec.region}"
+		run()
+		Expect(out.String()).To(Equal("eu-west-1 eu-west-1 eu-west-1\n"))
+	})
+
+	It("should report if no shoots are found", func() {
+		options.Provider = "azure"
+		run()
+		Expect(out.String()).To(BeEmpty())
+		Expect(errOut.String()).To(HavePrefix("No shoots found for target"))
+	})
+
+	It("should list the shoots of all gardens and report the gardens that cannot be queried", func() {
+		options.AllGardens = true
+		options.Unhealthy = true
+		options.Columns = []string{"name", "namespace"}
+		targetProvider.Target = target.NewTarget("", "", "", "")
+		run()
+		Expect(out.String()).To(Equal(`GARDEN     NAME    NAMESPACE
+mygarden   db      garden-prod1
+mygarden   other   garden-prod2
+`))
+		Expect(errOut.String()).To(Equal("Warning: failed to list shoots of garden brokengarden: failed to create garden cluster client: connection refused\n"))
+	})
+
+	It("should print the garden of the shoots of all gardens", func() {
+		options.AllGardens = true
+		options.Output = "jsonpath={[*].garden}/{[*].metadata.name}"
+		options.Provider = "gcp"
+		run()
+		Expect(out.String()).To(Equal("mygarden/db\n"))
+	})
+
+	It("should fail if no garden is targeted", func() {
+		targetProvider.Target = target.NewTarget("", "", "", "")
+		cmd := cmdget.NewCmdGetShoots(factory, options)
+		Expect(cmd.RunE(cmd, nil)).To(MatchError(target.ErrNoGardenTargeted))
+	})
+
+	Describe("Validate", func() {
+		It("should reject invalid output formats and columns", func() {
+			options.Output = "csv"
+			Expect(options.Validate()).To(MatchError("--output must be one of 'table', 'wide', 'yaml', 'json', 'name', 'jsonpath=<expr>' or 'go-template=<template>'"))
+
+			options.Output = "yaml"
+			options.Columns = []string{"name"}
+			Expect(options.Validate()).To(MatchError("--columns can only be used together with the table or wide output"))
+
+			options.Output = ""
+			options.Columns = []string{"foo"}
+			Expect(options.Validate()).To(MatchError(HavePrefix(`column "foo" is invalid, must be any of name, namespace`)))
+		})
+	})
+
+	Describe("Completion", func() {
+		It("should complete the last column of the columns flag", func() {
+			values, directive := cmdget.CompleteShootColumns(nil, nil, "name,na")
+			Expect(values).To(Equal([]string{"name,name", "name,namespace"}))
+			Expect(directive).To(Equal(cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace))
+		})
+	})
+})
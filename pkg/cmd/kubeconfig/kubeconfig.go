@@ -0,0 +1,244 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kubeconfig
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	clientcmdlatest "k8s.io/client-go/tools/clientcmd/api/latest"
+
+	"github.com/gardener/gardenctl-v2/internal/audit"
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
+	"github.com/gardener/gardenctl-v2/pkg/target"
+)
+
+// defaultRequestedKubeconfigExpiration is the validity of admin or viewer credentials if no expiration is given
+const defaultRequestedKubeconfigExpiration = time.Hour
+
+// NewCmdKubeconfig returns a new kubeconfig command.
+func NewCmdKubeconfig(f util.Factory, o *KubeconfigOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "kubeconfig",
+		Short: "Print the kubeconfig for the current target",
+		Long: `Print the kubeconfig for the currently targeted garden, project, seed, shoot or control plane.
+As with "kubectl config view", certificate data and tokens are redacted unless the --raw flag is given.
+With --admin, a kubeconfig with short-lived admin credentials is requested for the targeted shoot.
+With --viewer, a kubeconfig with short-lived read-only credentials is requested instead, which cannot be used to modify the shoot.
+Requested kubeconfigs are cached in the session directory and requested again shortly before the credentials expire
+or if a shorter --expiration is requested.
+With --merge, the kubeconfig is not printed but merged into the kubeconfig file given by --kubeconfig, ~/.kube/config by default.
+The context, cluster and user are named after the target, e.g. my-garden--my-project--my-shoot, with the suffix --admin or --viewer
+for requested credentials. Existing entries with the same name are only replaced if the --overwrite flag is given.`,
+		Example: `# print the kubeconfig for the current target
+gardenctl kubeconfig
+
+# write the kubeconfig of shoot my-shoot to a file, including all credentials
+gardenctl kubeconfig --raw --minify --flatten --garden my-garden --project my-project --shoot my-shoot > my-shoot.yaml
+
+# print a kubeconfig with admin credentials for the targeted shoot that expire after 30 minutes
+gardenctl kubeconfig --raw --admin --expiration 30m
+
+# inspect the pods of the targeted shoot with read-only credentials
+gardenctl kubeconfig --raw --viewer > viewer.yaml && kubectl --kubeconfig viewer.yaml get pods -A
+
+# print the kubeconfig of the current target as JSON
+gardenctl kubeconfig --raw -o json
+
+# merge the kubeconfig of the current target into ~/.kube/config and replace a previously merged context
+gardenctl kubeconfig --merge --overwrite`,
+		RunE: base.WrapRunE(o, f),
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// KubeconfigOptions is a struct to support kubeconfig command
+// nolint
+type KubeconfigOptions struct {
+	base.Options
+
+	// Raw prints the certificate data and tokens instead of redacting them
+	Raw bool
+	// Minify removes everything that is not referenced by the current context
+	Minify bool
+	// Flatten embeds the content of referenced files into the kubeconfig
+	Flatten bool
+	// Admin requests a kubeconfig with short-lived admin credentials for the targeted shoot
+	Admin bool
+	// Viewer requests a kubeconfig with short-lived read-only credentials for the targeted shoot
+	Viewer bool
+	// Expiration is the validity of the admin or viewer credentials
+	Expiration time.Duration
+	// Merge merges the current context into the kubeconfig file instead of printing the kubeconfig
+	Merge bool
+	// Kubeconfig is the kubeconfig file the current context is merged into, ~/.kube/config if empty
+	Kubeconfig string
+	// Overwrite replaces a context, cluster or user with the same name when merging
+	Overwrite bool
+}
+
+// NewKubeconfigOptions returns initialized KubeconfigOptions
+func NewKubeconfigOptions(ioStreams util.IOStreams) *KubeconfigOptions {
+	return &KubeconfigOptions{
+		Options: base.Options{
+			IOStreams: ioStreams,
+		},
+	}
+}
+
+// AddFlags adds flags to adjust the output to a cobra command
+func (o *KubeconfigOptions) AddFlags(flags *pflag.FlagSet) {
+	flags.BoolVar(&o.Raw, "raw", o.Raw, "Print certificate data and tokens instead of redacting them")
+	flags.BoolVar(&o.Minify, "minify", o.Minify, "Remove all information not used by the current context")
+	flags.BoolVar(&o.Flatten, "flatten", o.Flatten, "Embed the content of referenced files into the kubeconfig")
+	flags.BoolVar(&o.Admin, "admin", o.Admin, "Request a kubeconfig with short-lived admin credentials for the targeted shoot")
+	flags.BoolVar(&o.Viewer, "viewer", o.Viewer, "Request a kubeconfig with short-lived read-only credentials for the targeted shoot")
+	flags.DurationVar(&o.Expiration, "expiration", o.Expiration, "Validity of the admin or viewer credentials, use together with --admin or --viewer (default 1h)")
+	flags.BoolVar(&o.Merge, "merge", o.Merge, "Merge the current context into a kubeconfig file instead of printing the kubeconfig")
+	flags.StringVar(&o.Kubeconfig, "kubeconfig", o.Kubeconfig, "Kubeconfig file to merge the current context into, use together with --merge (default ~/.kube/config)")
+	flags.BoolVar(&o.Overwrite, "overwrite", o.Overwrite, "Replace a context, cluster or user with the same name, use together with --merge")
+	o.Options.AddFlags(flags)
+}
+
+// Validate validates the provided options
+func (o *KubeconfigOptions) Validate() error {
+	if o.Admin && o.Viewer {
+		return errors.New("--admin and --viewer must not be used together")
+	}
+
+	if o.Expiration != 0 && !o.Admin && !o.Viewer {
+		return errors.New("--expiration must be used together with --admin or --viewer")
+	}
+
+	if o.Expiration < 0 {
+		return errors.New("--expiration must be positive")
+	}
+
+	if !o.Merge && (o.Kubeconfig != "" || o.Overwrite) {
+		return errors.New("--kubeconfig and --overwrite must be used together with --merge")
+	}
+
+	if o.Merge && o.Output != "" {
+		return errors.New("--output must not be used together with --merge")
+	}
+
+	return o.Options.Validate()
+}
+
+// Run executes the command
+func (o *KubeconfigOptions) Run(f util.Factory) error {
+	manager, err := f.Manager()
+	if err != nil {
+		return err
+	}
+
+	currentTarget, err := manager.CurrentTarget()
+	if err != nil {
+		return fmt.Errorf("failed to get current target: %w", err)
+	}
+
+	// in dry-run mode, the changes of the merged kubeconfig file are printed instead
+	var (
+		dryRunOut io.Writer
+		auditLog  *audit.Logger
+	)
+
+	if o.Merge {
+		cfg := manager.Configuration()
+		if cfg.EphemeralKubeconfigs() {
+			return target.ErrEphemeralKubeconfigs
+		}
+
+		auditLog = cfg.Audit
+
+		if cfg.IsDryRun() {
+			dryRunOut = cfg.DryRun.Out
+		}
+	}
+
+	var clientConfig clientcmd.ClientConfig
+
+	expiration := o.Expiration
+	if expiration == 0 {
+		expiration = defaultRequestedKubeconfigExpiration
+	}
+
+	switch {
+	case o.Admin:
+		clientConfig, err = manager.AdminKubeconfig(f.Context(), currentTarget, expiration)
+	case o.Viewer:
+		clientConfig, err = manager.ViewerKubeconfig(f.Context(), currentTarget, expiration)
+	default:
+		clientConfig, err = manager.ClientConfig(f.Context(), currentTarget)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	rawConfig, err := clientConfig.RawConfig()
+	if err != nil {
+		return fmt.Errorf("failed to get raw client configuration: %w", err)
+	}
+
+	if o.Merge {
+		if o.Kubeconfig == "" {
+			o.Kubeconfig = clientcmd.RecommendedHomeFile
+		}
+
+		err := o.merge(currentTarget, rawConfig, dryRunOut)
+		if dryRunOut == nil {
+			auditLog.Log(audit.Entry{Action: "merge", Resource: o.Kubeconfig}, err)
+		}
+
+		return err
+	}
+
+	if o.Minify {
+		if err := clientcmdapi.MinifyConfig(&rawConfig); err != nil {
+			return fmt.Errorf("failed to minify kubeconfig: %w", err)
+		}
+	}
+
+	if o.Flatten {
+		if err := clientcmdapi.FlattenConfig(&rawConfig); err != nil {
+			return fmt.Errorf("failed to flatten kubeconfig: %w", err)
+		}
+	}
+
+	if !o.Raw {
+		clientcmdapi.ShortenConfig(&rawConfig)
+	}
+
+	if o.Output != "" && o.Output != base.OutputYAML {
+		config, err := clientcmdlatest.Scheme.ConvertToVersion(&rawConfig, clientcmdlatest.ExternalVersion)
+		if err != nil {
+			return fmt.Errorf("failed to convert kubeconfig: %w", err)
+		}
+
+		return o.PrintObject(config)
+	}
+
+	data, err := clientcmd.Write(rawConfig)
+	if err != nil {
+		return fmt.Errorf("failed to encode kubeconfig as YAML: %w", err)
+	}
+
+	_, err = o.IOStreams.Out.Write(data)
+
+	return err
+}
@@ -0,0 +1,257 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kubeconfig_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/utils/pointer"
+
+	internalfake "github.com/gardener/gardenctl-v2/internal/fake"
+	"github.com/gardener/gardenctl-v2/internal/util"
+	. "github.com/gardener/gardenctl-v2/pkg/cmd/kubeconfig"
+	"github.com/gardener/gardenctl-v2/pkg/config"
+	"github.com/gardener/gardenctl-v2/pkg/target"
+	targetmocks "github.com/gardener/gardenctl-v2/pkg/target/mocks"
+)
+
+var _ = Describe("Kubeconfig Command", func() {
+	var (
+		ctrl          *gomock.Controller
+		manager       *targetmocks.MockManager
+		factory       *internalfake.Factory
+		streams       util.IOStreams
+		out           *util.SafeBytesBuffer
+		o             *KubeconfigOptions
+		currentTarget target.Target
+		clientConfig  clientcmd.ClientConfig
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		manager = targetmocks.NewMockManager(ctrl)
+		factory = internalfake.NewFakeFactory(nil, nil, nil, nil)
+		factory.ManagerImpl = manager
+		streams, _, out, _ = util.NewTestIOStreams()
+		o = NewKubeconfigOptions(streams)
+		currentTarget = target.NewTarget("garden", "project", "", "shoot")
+
+		config := clientcmdapi.NewConfig()
+		config.Clusters["shoot"] = &clientcmdapi.Cluster{
+			Server:                   "https://api.shoot.example.com",
+			CertificateAuthorityData: []byte("ca"),
+		}
+		config.Clusters["unused"] = &clientcmdapi.Cluster{
+			Server: "https://unused.example.com",
+		}
+		config.AuthInfos["shoot"] = &clientcmdapi.AuthInfo{
+			Token: "secret",
+		}
+		config.Contexts["shoot"] = &clientcmdapi.Context{
+			Cluster:  "shoot",
+			AuthInfo: "shoot",
+		}
+		config.CurrentContext = "shoot"
+
+		clientConfig = clientcmd.NewDefaultClientConfig(*config, nil)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Context("when the kubeconfig of the target is printed", func() {
+		BeforeEach(func() {
+			manager.EXPECT().CurrentTarget().Return(currentTarget, nil)
+			manager.EXPECT().ClientConfig(context.Background(), currentTarget).Return(clientConfig, nil)
+		})
+
+		It("should print the kubeconfig with redacted credentials", func() {
+			cmd := NewCmdKubeconfig(factory, o)
+			Expect(cmd.RunE(cmd, nil)).To(Succeed())
+
+			config, err := clientcmd.Load([]byte(out.String()))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config.CurrentContext).To(Equal("shoot"))
+			Expect(config.AuthInfos["shoot"].Token).To(Equal("REDACTED"))
+			Expect(config.Clusters).To(HaveKey("unused"))
+		})
+
+		It("should print the minified kubeconfig with credentials", func() {
+			cmd := NewCmdKubeconfig(factory, o)
+			Expect(cmd.Flags().Set("raw", "true")).To(Succeed())
+			Expect(cmd.Flags().Set("minify", "true")).To(Succeed())
+			Expect(cmd.RunE(cmd, nil)).To(Succeed())
+
+			config, err := clientcmd.Load([]byte(out.String()))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config.AuthInfos["shoot"].Token).To(Equal("secret"))
+			Expect(config.Clusters["shoot"].CertificateAuthorityData).To(Equal([]byte("ca")))
+			Expect(config.Clusters).NotTo(HaveKey("unused"))
+		})
+
+		It("should print the kubeconfig as JSON", func() {
+			cmd := NewCmdKubeconfig(factory, o)
+			Expect(cmd.Flags().Set("output", "json")).To(Succeed())
+			Expect(cmd.RunE(cmd, nil)).To(Succeed())
+
+			var config map[string]interface{}
+			Expect(json.Unmarshal([]byte(out.String()), &config)).To(Succeed())
+			Expect(config).To(HaveKeyWithValue("current-context", "shoot"))
+			Expect(config).To(HaveKeyWithValue("kind", "Config"))
+		})
+	})
+
+	Context("when the kubeconfig is merged", func() {
+		var (
+			dir      string
+			filename string
+			cfg      *config.Config
+		)
+
+		BeforeEach(func() {
+			var err error
+			dir, err = os.MkdirTemp("", "kubeconfig-*")
+			Expect(err).NotTo(HaveOccurred())
+
+			filename = filepath.Join(dir, "kube", "config")
+			cfg = &config.Config{}
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(dir)).To(Succeed())
+		})
+
+		It("should merge the current context into a new kubeconfig file", func() {
+			manager.EXPECT().CurrentTarget().Return(currentTarget, nil)
+			manager.EXPECT().Configuration().Return(cfg)
+			manager.EXPECT().ClientConfig(context.Background(), currentTarget).Return(clientConfig, nil)
+
+			cmd := NewCmdKubeconfig(factory, o)
+			Expect(cmd.Flags().Set("merge", "true")).To(Succeed())
+			Expect(cmd.Flags().Set("kubeconfig", filename)).To(Succeed())
+			Expect(cmd.RunE(cmd, nil)).To(Succeed())
+			Expect(out.String()).To(Equal(fmt.Sprintf("Successfully merged context %q into kubeconfig %s\n", "garden--project--shoot", filename)))
+
+			merged, err := clientcmd.LoadFromFile(filename)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(merged.CurrentContext).To(Equal("garden--project--shoot"))
+			Expect(merged.Contexts).To(HaveLen(1))
+			Expect(merged.Contexts["garden--project--shoot"].Cluster).To(Equal("garden--project--shoot"))
+			Expect(merged.Contexts["garden--project--shoot"].AuthInfo).To(Equal("garden--project--shoot"))
+			Expect(merged.Clusters).To(HaveLen(1))
+			Expect(merged.Clusters["garden--project--shoot"].CertificateAuthorityData).To(Equal([]byte("ca")))
+			Expect(merged.AuthInfos["garden--project--shoot"].Token).To(Equal("secret"))
+		})
+
+		Context("when the kubeconfig file already contains the context", func() {
+			BeforeEach(func() {
+				existing := clientcmdapi.NewConfig()
+				existing.Clusters["other"] = &clientcmdapi.Cluster{Server: "https://other.example.com"}
+				existing.AuthInfos["other"] = &clientcmdapi.AuthInfo{Token: "other"}
+				existing.Contexts["other"] = &clientcmdapi.Context{Cluster: "other", AuthInfo: "other"}
+				existing.Clusters["garden--project--shoot--viewer"] = &clientcmdapi.Cluster{Server: "https://outdated.example.com"}
+				existing.AuthInfos["garden--project--shoot--viewer"] = &clientcmdapi.AuthInfo{Token: "outdated"}
+				existing.Contexts["garden--project--shoot--viewer"] = &clientcmdapi.Context{Cluster: "garden--project--shoot--viewer", AuthInfo: "garden--project--shoot--viewer"}
+				existing.CurrentContext = "other"
+				Expect(clientcmd.WriteToFile(*existing, filename)).To(Succeed())
+
+				manager.EXPECT().CurrentTarget().Return(currentTarget, nil)
+				manager.EXPECT().Configuration().Return(cfg)
+				manager.EXPECT().ViewerKubeconfig(context.Background(), currentTarget, time.Hour).Return(clientConfig, nil)
+			})
+
+			It("should fail without --overwrite", func() {
+				cmd := NewCmdKubeconfig(factory, o)
+				Expect(cmd.Flags().Set("merge", "true")).To(Succeed())
+				Expect(cmd.Flags().Set("viewer", "true")).To(Succeed())
+				Expect(cmd.Flags().Set("kubeconfig", filename)).To(Succeed())
+				Expect(cmd.RunE(cmd, nil)).To(MatchError(fmt.Sprintf("context %q already exists in kubeconfig %s, use --overwrite to replace it", "garden--project--shoot--viewer", filename)))
+			})
+
+			It("should replace the context with --overwrite", func() {
+				cmd := NewCmdKubeconfig(factory, o)
+				Expect(cmd.Flags().Set("merge", "true")).To(Succeed())
+				Expect(cmd.Flags().Set("viewer", "true")).To(Succeed())
+				Expect(cmd.Flags().Set("overwrite", "true")).To(Succeed())
+				Expect(cmd.Flags().Set("kubeconfig", filename)).To(Succeed())
+				Expect(cmd.RunE(cmd, nil)).To(Succeed())
+
+				merged, err := clientcmd.LoadFromFile(filename)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(merged.CurrentContext).To(Equal("other"))
+				Expect(merged.Contexts).To(HaveKey("other"))
+				Expect(merged.Clusters["garden--project--shoot--viewer"].Server).To(Equal("https://api.shoot.example.com"))
+				Expect(merged.AuthInfos["garden--project--shoot--viewer"].Token).To(Equal("secret"))
+			})
+		})
+
+		It("should fail if kubeconfigs must not be written to disk", func() {
+			cfg.Security = &config.Security{EphemeralKubeconfigs: pointer.Bool(true)}
+			manager.EXPECT().CurrentTarget().Return(currentTarget, nil)
+			manager.EXPECT().Configuration().Return(cfg)
+
+			cmd := NewCmdKubeconfig(factory, o)
+			Expect(cmd.Flags().Set("merge", "true")).To(Succeed())
+			Expect(cmd.RunE(cmd, nil)).To(MatchError(target.ErrEphemeralKubeconfigs))
+		})
+
+		It("should fail if --overwrite is given without --merge", func() {
+			cmd := NewCmdKubeconfig(factory, o)
+			Expect(cmd.Flags().Set("overwrite", "true")).To(Succeed())
+			Expect(cmd.RunE(cmd, nil)).To(MatchError("--kubeconfig and --overwrite must be used together with --merge"))
+		})
+	})
+
+	Context("when an admin kubeconfig is requested", func() {
+		It("should request the admin kubeconfig with the default expiration", func() {
+			manager.EXPECT().CurrentTarget().Return(currentTarget, nil)
+			manager.EXPECT().AdminKubeconfig(context.Background(), currentTarget, time.Hour).Return(clientConfig, nil)
+
+			cmd := NewCmdKubeconfig(factory, o)
+			Expect(cmd.Flags().Set("admin", "true")).To(Succeed())
+			Expect(cmd.RunE(cmd, nil)).To(Succeed())
+			Expect(out.String()).To(ContainSubstring("current-context: shoot"))
+		})
+
+		It("should fail if the expiration is given without --admin", func() {
+			cmd := NewCmdKubeconfig(factory, o)
+			Expect(cmd.Flags().Set("expiration", "30m")).To(Succeed())
+			Expect(cmd.RunE(cmd, nil)).To(MatchError("--expiration must be used together with --admin or --viewer"))
+		})
+
+		It("should fail if both --admin and --viewer are given", func() {
+			cmd := NewCmdKubeconfig(factory, o)
+			Expect(cmd.Flags().Set("admin", "true")).To(Succeed())
+			Expect(cmd.Flags().Set("viewer", "true")).To(Succeed())
+			Expect(cmd.RunE(cmd, nil)).To(MatchError("--admin and --viewer must not be used together"))
+		})
+	})
+
+	Context("when a viewer kubeconfig is requested", func() {
+		It("should request the viewer kubeconfig with the given expiration", func() {
+			manager.EXPECT().CurrentTarget().Return(currentTarget, nil)
+			manager.EXPECT().ViewerKubeconfig(context.Background(), currentTarget, 30*time.Minute).Return(clientConfig, nil)
+
+			cmd := NewCmdKubeconfig(factory, o)
+			Expect(cmd.Flags().Set("viewer", "true")).To(Succeed())
+			Expect(cmd.Flags().Set("expiration", "30m")).To(Succeed())
+			Expect(cmd.RunE(cmd, nil)).To(Succeed())
+			Expect(out.String()).To(ContainSubstring("current-context: shoot"))
+		})
+	})
+})
@@ -0,0 +1,157 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kubeconfig
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/gardener/gardenctl-v2/internal/diff"
+	"github.com/gardener/gardenctl-v2/pkg/target"
+)
+
+// merge adds the current context of the kubeconfig to the kubeconfig file of the options.
+// The context, its cluster and its user are renamed to the name returned by contextName, so that
+// merging the kubeconfig of the same target again replaces the previously merged entries.
+// If dryRunOut is set, the changes to the kubeconfig file are printed to it instead of being written.
+func (o *KubeconfigOptions) merge(t target.Target, rawConfig clientcmdapi.Config, dryRunOut io.Writer) error {
+	// the merged kubeconfig must not refer to files in the session directory, which is removed eventually
+	if err := clientcmdapi.MinifyConfig(&rawConfig); err != nil {
+		return fmt.Errorf("failed to minify kubeconfig: %w", err)
+	}
+
+	if err := clientcmdapi.FlattenConfig(&rawConfig); err != nil {
+		return fmt.Errorf("failed to flatten kubeconfig: %w", err)
+	}
+
+	kubeContext := rawConfig.Contexts[rawConfig.CurrentContext]
+	name := o.contextName(t)
+
+	filename := o.Kubeconfig
+
+	if dryRunOut == nil {
+		if err := os.MkdirAll(filepath.Dir(filename), 0700); err != nil {
+			return fmt.Errorf("failed to create directory: %w", err)
+		}
+
+		unlock, err := lockKubeconfig(filename)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+	}
+
+	// the previous content is encoded like the merged one, so that the dry-run diff only contains the merged entries
+	var previous []byte
+
+	config, err := clientcmd.LoadFromFile(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		config = clientcmdapi.NewConfig()
+	} else if err != nil {
+		return fmt.Errorf("failed to load kubeconfig %s: %w", filename, err)
+	} else if previous, err = clientcmd.Write(*config); err != nil {
+		return fmt.Errorf("failed to encode kubeconfig %s: %w", filename, err)
+	}
+
+	if !o.Overwrite {
+		if _, ok := config.Contexts[name]; ok {
+			return fmt.Errorf("context %q already exists in kubeconfig %s, use --overwrite to replace it", name, filename)
+		}
+
+		if _, ok := config.Clusters[name]; ok {
+			return fmt.Errorf("cluster %q already exists in kubeconfig %s, use --overwrite to replace it", name, filename)
+		}
+
+		if _, ok := config.AuthInfos[name]; ok {
+			return fmt.Errorf("user %q already exists in kubeconfig %s, use --overwrite to replace it", name, filename)
+		}
+	}
+
+	config.Clusters[name] = rawConfig.Clusters[kubeContext.Cluster]
+	config.AuthInfos[name] = rawConfig.AuthInfos[kubeContext.AuthInfo]
+	config.Contexts[name] = &clientcmdapi.Context{
+		Cluster:    name,
+		AuthInfo:   name,
+		Namespace:  kubeContext.Namespace,
+		Extensions: kubeContext.Extensions,
+	}
+
+	if config.CurrentContext == "" {
+		config.CurrentContext = name
+	}
+
+	if dryRunOut != nil {
+		data, err := clientcmd.Write(*config)
+		if err != nil {
+			return fmt.Errorf("failed to encode kubeconfig %s: %w", filename, err)
+		}
+
+		return diff.Print(dryRunOut, filename, previous, data)
+	}
+
+	if err := clientcmd.WriteToFile(*config, filename); err != nil {
+		return fmt.Errorf("failed to write kubeconfig %s: %w", filename, err)
+	}
+
+	fmt.Fprintf(o.IOStreams.Out, "Successfully merged context %q into kubeconfig %s\n", name, filename)
+
+	return nil
+}
+
+// lockKubeconfig locks the kubeconfig file like kubectl does when it modifies a kubeconfig, so that
+// gardenctl and kubectl do not overwrite each other's changes. The lock file is removed by the returned function.
+func lockKubeconfig(filename string) (func(), error) {
+	lockFilename := filename + ".lock"
+
+	f, err := os.OpenFile(lockFilename, os.O_CREATE|os.O_EXCL, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock kubeconfig %s: %w", filename, err)
+	}
+
+	f.Close()
+
+	return func() {
+		_ = os.Remove(lockFilename)
+	}, nil
+}
+
+// contextName returns the name of the merged context, which is derived from the target,
+// e.g. my-garden--my-project--my-shoot for a shoot or my-garden--my-project--my-shoot--control-plane for its control plane.
+// The names of contexts with requested admin or viewer credentials end with --admin or --viewer respectively.
+func (o *KubeconfigOptions) contextName(t target.Target) string {
+	parts := []string{t.GardenName()}
+
+	if t.ProjectName() != "" {
+		parts = append(parts, t.ProjectName())
+	} else if t.SeedName() != "" {
+		parts = append(parts, "seed-"+t.SeedName())
+	}
+
+	if t.ShootName() != "" {
+		parts = append(parts, t.ShootName())
+	}
+
+	if t.ControlPlane() {
+		parts = append(parts, "control-plane")
+	}
+
+	switch {
+	case o.Admin:
+		parts = append(parts, "admin")
+	case o.Viewer:
+		parts = append(parts, "viewer")
+	}
+
+	return strings.Join(parts, "--")
+}
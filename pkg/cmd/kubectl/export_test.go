@@ -0,0 +1,23 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kubectl
+
+import (
+	"context"
+	"os"
+)
+
+// DefaultExecCommand is the execCommand that runs the kubectl binary
+var DefaultExecCommand = execCommand
+
+func SetExecCommand(f func(ctx context.Context, args []string, env []string, extraFiles []*os.File, o *KubectlOptions) error) {
+	execCommand = f
+}
+
+func MutatingVerb(args []string) string {
+	return mutatingVerb(args)
+}
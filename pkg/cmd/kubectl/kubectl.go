@@ -0,0 +1,254 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kubectl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/gardener/gardenctl-v2/internal/audit"
+	"github.com/gardener/gardenctl-v2/internal/memfile"
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
+	"github.com/gardener/gardenctl-v2/pkg/config"
+)
+
+// execCommand executes kubectl with the given args, environment and extra files, using the in/out streams
+// from the KubectlOptions. The function returns an error if kubectl fails.
+var execCommand = func(ctx context.Context, args []string, env []string, extraFiles []*os.File, o *KubectlOptions) error {
+	cmd := exec.CommandContext(ctx, "kubectl", args...)
+	cmd.Env = env
+	cmd.ExtraFiles = extraFiles
+	cmd.Stdin = o.IOStreams.In
+	cmd.Stdout = o.IOStreams.Out
+	cmd.Stderr = util.UnwrapQuietWriter(o.IOStreams.ErrOut)
+
+	return cmd.Run()
+}
+
+// NewCmdKubectl returns a new kubectl command.
+func NewCmdKubectl(f util.Factory, o *KubectlOptions) *cobra.Command {
+	runE := base.WrapRunE(o, f)
+
+	cmd := &cobra.Command{
+		Use:     "kubectl -- [ARGS...]",
+		Aliases: []string{"k"},
+		Short:   "Run kubectl against the currently targeted cluster",
+		Long: `Run kubectl against the currently targeted garden, project, seed, shoot or control plane.
+The KUBECONFIG environment variable of kubectl is set to the kubeconfig of the current target, the environment of your shell
+is not modified. Arguments after -- are passed to kubectl unchanged, stdin and the terminal are passed through, e.g. for
+kubectl exec or kubectl edit. If kubectl fails, gardenctl exits with exit code 7, the exit code of kubectl is part of the
+error printed with --error-format json.
+If security.ephemeralKubeconfigs is enabled in the gardenctl configuration, the kubeconfig is not written to disk
+but passed to kubectl in memory. If the targeted garden is protected, kubectl commands that change resources or run
+commands in containers, e.g. apply, delete, edit or exec, require a confirmation. In dry-run mode, the --dry-run flag of kubectl is added with the same
+strategy to the kubectl commands that change resources, kubectl commands that do not support it fail.`,
+		Example: `# list the pods of the targeted shoot
+gardenctl kubectl -- get pods -A
+
+# open a shell in a pod of the targeted shoot
+gardenctl k -- exec -it my-pod -- sh
+
+# list the pods of the control plane of shoot my-shoot
+gardenctl k --project my-project --shoot my-shoot --control-plane -- get pods`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := runE(cmd, args)
+
+			// kubectl already printed its error, gardenctl only exits with the exit code of failed commands
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				cmd.SilenceErrors = true
+			}
+
+			return err
+		},
+	}
+
+	return cmd
+}
+
+// KubectlOptions is a struct to support kubectl command
+// nolint
+type KubectlOptions struct {
+	base.Options
+
+	// Args are the arguments that are passed to kubectl
+	Args []string
+}
+
+// NewKubectlOptions returns initialized KubectlOptions
+func NewKubectlOptions(ioStreams util.IOStreams) *KubectlOptions {
+	return &KubectlOptions{
+		Options: base.Options{
+			IOStreams: ioStreams,
+		},
+	}
+}
+
+// Complete adapts from the command line args to the data required.
+func (o *KubectlOptions) Complete(f util.Factory, cmd *cobra.Command, args []string) error {
+	o.Args = args
+
+	return nil
+}
+
+// Validate validates the provided KubectlOptions
+func (o *KubectlOptions) Validate() error {
+	if len(o.Args) == 0 {
+		return errors.New("no kubectl arguments given, pass them after --, e.g. gardenctl kubectl -- get pods")
+	}
+
+	return nil
+}
+
+// Run executes the command
+func (o *KubectlOptions) Run(f util.Factory) error {
+	manager, err := f.Manager()
+	if err != nil {
+		return err
+	}
+
+	currentTarget, err := manager.CurrentTarget()
+	if err != nil {
+		return fmt.Errorf("failed to get current target: %w", err)
+	}
+
+	if currentTarget.IsEmpty() {
+		return errors.New("no cluster targeted")
+	}
+
+	cfg := manager.Configuration()
+	args := o.Args
+	verb := mutatingVerb(o.Args)
+
+	if verb != "" {
+		if err := cfg.ConfirmMutation(currentTarget.GardenName(), "run kubectl "+verb); err != nil {
+			return err
+		}
+
+		if cfg.IsDryRun() {
+			args = withDryRun(o.Args, cfg.DryRun.Strategy)
+		}
+	}
+
+	ctx := f.Context()
+
+	config, err := manager.ClientConfig(ctx, currentTarget)
+	if err != nil {
+		return err
+	}
+
+	var (
+		env        []string
+		extraFiles []*os.File
+	)
+
+	if cfg.EphemeralKubeconfigs() {
+		kubeconfig, err := ephemeralKubeconfig(config)
+		if err != nil {
+			return err
+		}
+		defer kubeconfig.Close()
+
+		env = append(os.Environ(), "KUBECONFIG="+memfile.ChildPath(0))
+		extraFiles = []*os.File{kubeconfig}
+	} else {
+		filename, err := manager.WriteClientConfig(config)
+		if err != nil {
+			return err
+		}
+
+		env = append(os.Environ(), "KUBECONFIG="+filename)
+	}
+
+	err = execCommand(ctx, args, env, extraFiles, o)
+
+	// the requests of kubectl are not sent by gardenctl, hence the kubectl commands that change resources are recorded
+	// as a whole. Nothing is recorded in dry-run mode.
+	if verb != "" && !cfg.IsDryRun() {
+		cfg.Audit.Log(audit.Entry{Action: verb, Args: o.Args}, err)
+	}
+
+	return err
+}
+
+// mutatingVerbs are the kubectl commands that change resources or run commands in containers, they require a
+// confirmation for protected gardens
+var mutatingVerbs = sets.NewString(
+	"annotate", "apply", "attach", "autoscale", "certificate", "cordon", "cp", "create", "debug", "delete", "drain",
+	"edit", "exec", "expose", "label", "patch", "replace", "rollout", "run", "scale", "set", "taint", "uncordon",
+)
+
+// globalFlagsWithValue are the global flags of kubectl that take a value as separate argument, e.g. -n kube-system
+var globalFlagsWithValue = sets.NewString(
+	"-n", "--namespace", "-s", "--server", "-v", "--v", "--as", "--as-group", "--as-uid", "--cache-dir",
+	"--certificate-authority", "--client-certificate", "--client-key", "--cluster", "--context", "--kubeconfig",
+	"--log-dir", "--log-file", "--profile", "--profile-output", "--request-timeout", "--tls-server-name", "--token",
+	"--user", "--username", "--password", "--vmodule",
+)
+
+// mutatingVerb returns the kubectl command, i.e. the first argument that is neither a global flag nor its value,
+// if it changes resources. Otherwise, e.g. for kubectl get configmap delete, an empty string is returned.
+func mutatingVerb(args []string) string {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		switch {
+		case arg == "--":
+			return ""
+		case globalFlagsWithValue.Has(arg):
+			i++
+		case strings.HasPrefix(arg, "-"):
+		case mutatingVerbs.Has(arg):
+			return arg
+		default:
+			return ""
+		}
+	}
+
+	return ""
+}
+
+// withDryRun returns the arguments with the --dry-run flag of kubectl for the given strategy. The flag is added before
+// the arguments of the command executed by kubectl, if any. kubectl commands that do not support --dry-run fail.
+func withDryRun(args []string, strategy config.DryRunStrategy) []string {
+	flag := "--dry-run=" + string(strategy)
+
+	for i, arg := range args {
+		if arg == "--" {
+			result := append([]string{}, args[:i]...)
+			result = append(result, flag)
+
+			return append(result, args[i:]...)
+		}
+	}
+
+	return append(append([]string{}, args...), flag)
+}
+
+// ephemeralKubeconfig returns a file in memory that holds the kubeconfig and is passed to kubectl as first extra file
+func ephemeralKubeconfig(config clientcmd.ClientConfig) (*os.File, error) {
+	rawConfig, err := config.RawConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get raw client configuration: %w", err)
+	}
+
+	data, err := clientcmd.Write(rawConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize kubeconfig: %w", err)
+	}
+
+	return memfile.New("kubeconfig", data)
+}
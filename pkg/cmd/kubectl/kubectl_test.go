@@ -0,0 +1,302 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kubectl_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/utils/pointer"
+
+	"github.com/gardener/gardenctl-v2/internal/audit"
+	internalfake "github.com/gardener/gardenctl-v2/internal/fake"
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/kubectl"
+	"github.com/gardener/gardenctl-v2/pkg/config"
+	"github.com/gardener/gardenctl-v2/pkg/target"
+	targetmocks "github.com/gardener/gardenctl-v2/pkg/target/mocks"
+)
+
+var _ = Describe("Kubectl Command", func() {
+	var (
+		ctrl          *gomock.Controller
+		manager       *targetmocks.MockManager
+		factory       *internalfake.Factory
+		streams       util.IOStreams
+		o             *kubectl.KubectlOptions
+		currentTarget target.Target
+		clientConfig  clientcmd.ClientConfig
+		cfg           *config.Config
+		executedArgs  []string
+		executedEnv   []string
+		executedFile  string
+		execErr       error
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		manager = targetmocks.NewMockManager(ctrl)
+		factory = internalfake.NewFakeFactory(nil, nil, nil, nil)
+		factory.ManagerImpl = manager
+		streams, _, _, _ = util.NewTestIOStreams()
+		o = kubectl.NewKubectlOptions(streams)
+		currentTarget = target.NewTarget("garden", "project", "", "shoot")
+		clientConfig = clientcmd.NewDefaultClientConfig(*clientcmdapi.NewConfig(), nil)
+		cfg = &config.Config{}
+		manager.EXPECT().Configuration().Return(cfg).AnyTimes()
+
+		executedArgs, executedEnv, executedFile, execErr = nil, nil, "", nil
+
+		kubectl.SetExecCommand(func(_ context.Context, args []string, env []string, extraFiles []*os.File, _ *kubectl.KubectlOptions) error {
+			executedArgs = args
+			executedEnv = env
+
+			if len(extraFiles) > 0 {
+				data, err := io.ReadAll(extraFiles[0])
+				Expect(err).NotTo(HaveOccurred())
+				executedFile = string(data)
+			}
+
+			return execErr
+		})
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Context("when a cluster is targeted", func() {
+		BeforeEach(func() {
+			manager.EXPECT().CurrentTarget().Return(currentTarget, nil)
+			manager.EXPECT().ClientConfig(context.Background(), currentTarget).Return(clientConfig, nil)
+			manager.EXPECT().WriteClientConfig(clientConfig).Return("/tmp/session/kubeconfig.abc.yaml", nil)
+		})
+
+		It("should run kubectl with the kubeconfig of the current target", func() {
+			cmd := kubectl.NewCmdKubectl(factory, o)
+			Expect(cmd.RunE(cmd, []string{"get", "pods", "-A"})).To(Succeed())
+			Expect(executedArgs).To(Equal([]string{"get", "pods", "-A"}))
+			Expect(executedEnv[len(executedEnv)-1]).To(Equal("KUBECONFIG=/tmp/session/kubeconfig.abc.yaml"))
+		})
+
+		It("should silence the error if kubectl fails", func() {
+			execErr = &exec.ExitError{}
+
+			cmd := kubectl.NewCmdKubectl(factory, o)
+			Expect(cmd.RunE(cmd, []string{"get", "pods"})).To(MatchError(execErr))
+			Expect(cmd.SilenceErrors).To(BeTrue())
+		})
+	})
+
+	Context("in quiet mode", func() {
+		var (
+			errOut *util.SafeBytesBuffer
+			path   string
+		)
+
+		BeforeEach(func() {
+			dir, err := os.MkdirTemp("", "gctlv2-kubectl-")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(os.WriteFile(filepath.Join(dir, "kubectl"), []byte("#!/bin/sh\necho 'error: pods is forbidden' >&2\nexit 1\n"), 0700)).To(Succeed())
+
+			path = os.Getenv("PATH")
+			Expect(os.Setenv("PATH", dir+string(os.PathListSeparator)+path)).To(Succeed())
+
+			streams, _, _, errOut = util.NewTestIOStreams()
+			streams.ErrOut = util.NewQuietWriter(streams.ErrOut, func() bool { return true })
+			o = kubectl.NewKubectlOptions(streams)
+			kubectl.SetExecCommand(kubectl.DefaultExecCommand)
+
+			manager.EXPECT().CurrentTarget().Return(currentTarget, nil)
+			manager.EXPECT().ClientConfig(context.Background(), currentTarget).Return(clientConfig, nil)
+			manager.EXPECT().WriteClientConfig(clientConfig).Return(filepath.Join(dir, "kubeconfig.yaml"), nil)
+		})
+
+		AfterEach(func() {
+			dir := filepath.SplitList(os.Getenv("PATH"))[0]
+			Expect(os.Setenv("PATH", path)).To(Succeed())
+			Expect(os.RemoveAll(dir)).To(Succeed())
+		})
+
+		It("should still print the errors of kubectl", func() {
+			cmd := kubectl.NewCmdKubectl(factory, o)
+			Expect(cmd.RunE(cmd, []string{"get", "pods"})).To(HaveOccurred())
+			Expect(errOut.String()).To(Equal("error: pods is forbidden\n"))
+		})
+	})
+
+	Context("in dry-run mode", func() {
+		BeforeEach(func() {
+			cfg.DryRun = &config.DryRun{Strategy: config.DryRunServer}
+			manager.EXPECT().CurrentTarget().Return(currentTarget, nil)
+			manager.EXPECT().ClientConfig(context.Background(), currentTarget).Return(clientConfig, nil)
+			manager.EXPECT().WriteClientConfig(clientConfig).Return("/tmp/session/kubeconfig.abc.yaml", nil)
+		})
+
+		It("should run kubectl commands that change resources with the dry-run strategy", func() {
+			cmd := kubectl.NewCmdKubectl(factory, o)
+			Expect(cmd.RunE(cmd, []string{"delete", "pod", "foo"})).To(Succeed())
+			Expect(executedArgs).To(Equal([]string{"delete", "pod", "foo", "--dry-run=server"}))
+		})
+
+		It("should add the dry-run flag before the arguments of the executed command", func() {
+			cmd := kubectl.NewCmdKubectl(factory, o)
+			Expect(cmd.RunE(cmd, []string{"run", "debug", "--image", "busybox", "--", "sh"})).To(Succeed())
+			Expect(executedArgs).To(Equal([]string{"run", "debug", "--image", "busybox", "--dry-run=server", "--", "sh"}))
+		})
+
+		It("should run kubectl commands that do not change resources unchanged", func() {
+			cmd := kubectl.NewCmdKubectl(factory, o)
+			Expect(cmd.RunE(cmd, []string{"get", "pods"})).To(Succeed())
+			Expect(executedArgs).To(Equal([]string{"get", "pods"}))
+		})
+	})
+
+	Context("when the audit log is enabled", func() {
+		var dir string
+
+		BeforeEach(func() {
+			var err error
+
+			dir, err = os.MkdirTemp("", "audit-")
+			Expect(err).NotTo(HaveOccurred())
+
+			cfg.Audit = &audit.Logger{
+				Filename: filepath.Join(dir, "audit.log"),
+				Command:  "gardenctl kubectl",
+				User:     "jdoe",
+				Now: func() time.Time {
+					return time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+				},
+			}
+
+			manager.EXPECT().CurrentTarget().Return(currentTarget, nil)
+			manager.EXPECT().ClientConfig(context.Background(), currentTarget).Return(clientConfig, nil)
+			manager.EXPECT().WriteClientConfig(clientConfig).Return("/tmp/session/kubeconfig.abc.yaml", nil)
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(dir)).To(Succeed())
+		})
+
+		It("should record the kubectl commands that change resources and their result", func() {
+			execErr = errors.New("exit status 1")
+
+			cmd := kubectl.NewCmdKubectl(factory, o)
+			Expect(cmd.RunE(cmd, []string{"-n", "default", "delete", "pod", "foo"})).To(HaveOccurred())
+
+			data, err := os.ReadFile(cfg.Audit.Filename)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(Equal(`{"timestamp":"2022-06-01T12:00:00Z","user":"jdoe","command":"gardenctl kubectl","action":"delete","args":["-n","default","delete","pod","foo"],"result":"failure","error":"exit status 1"}` + "\n"))
+		})
+
+		It("should not record the kubectl commands that do not change resources", func() {
+			cmd := kubectl.NewCmdKubectl(factory, o)
+			Expect(cmd.RunE(cmd, []string{"get", "pods"})).To(Succeed())
+			Expect(filepath.Join(dir, "audit.log")).NotTo(BeAnExistingFile())
+		})
+
+		It("should not record the kubectl commands in dry-run mode", func() {
+			cfg.DryRun = &config.DryRun{Strategy: config.DryRunClient}
+
+			cmd := kubectl.NewCmdKubectl(factory, o)
+			Expect(cmd.RunE(cmd, []string{"delete", "pod", "foo"})).To(Succeed())
+			Expect(filepath.Join(dir, "audit.log")).NotTo(BeAnExistingFile())
+		})
+	})
+
+	It("should pass the kubeconfig in memory if ephemeral kubeconfigs are enabled", func() {
+		cfg.Security = &config.Security{EphemeralKubeconfigs: pointer.Bool(true)}
+		manager.EXPECT().CurrentTarget().Return(currentTarget, nil)
+		manager.EXPECT().ClientConfig(context.Background(), currentTarget).Return(clientConfig, nil)
+
+		cmd := kubectl.NewCmdKubectl(factory, o)
+		Expect(cmd.RunE(cmd, []string{"get", "pods"})).To(Succeed())
+		Expect(executedEnv[len(executedEnv)-1]).To(Equal("KUBECONFIG=/dev/fd/3"))
+		Expect(executedFile).To(ContainSubstring("kind: Config"))
+	})
+
+	It("should fail if no cluster is targeted", func() {
+		manager.EXPECT().CurrentTarget().Return(target.NewTarget("", "", "", ""), nil)
+
+		cmd := kubectl.NewCmdKubectl(factory, o)
+		Expect(cmd.RunE(cmd, []string{"get", "pods"})).To(MatchError("no cluster targeted"))
+		Expect(executedArgs).To(BeNil())
+	})
+
+	Context("when the garden is protected", func() {
+		var questions []string
+
+		BeforeEach(func() {
+			questions = nil
+			cfg.Gardens = []config.Garden{{Name: "garden", Protected: true}}
+			cfg.Confirmation = &config.Confirmation{Confirm: func(question string) (bool, error) {
+				questions = append(questions, question)
+				return false, nil
+			}}
+			manager.EXPECT().CurrentTarget().Return(currentTarget, nil)
+		})
+
+		It("should run kubectl without confirmation if no resources are changed", func() {
+			manager.EXPECT().ClientConfig(context.Background(), currentTarget).Return(clientConfig, nil)
+			manager.EXPECT().WriteClientConfig(clientConfig).Return("/tmp/session/kubeconfig.abc.yaml", nil)
+
+			cmd := kubectl.NewCmdKubectl(factory, o)
+			Expect(cmd.RunE(cmd, []string{"get", "pods", "--", "delete"})).To(Succeed())
+			Expect(questions).To(BeEmpty())
+		})
+
+		It("should not run kubectl if the change has not been confirmed", func() {
+			cmd := kubectl.NewCmdKubectl(factory, o)
+			err := cmd.RunE(cmd, []string{"-n", "kube-system", "delete", "pod", "foo"})
+			Expect(config.IsMutationDenied(err)).To(BeTrue())
+			Expect(questions).To(Equal([]string{`Garden "garden" is protected. Do you really want to run kubectl delete?`}))
+			Expect(executedArgs).To(BeNil())
+		})
+
+		It("should not run kubectl if changes are denied", func() {
+			cfg.Gardens[0].DenyMutations = true
+
+			cmd := kubectl.NewCmdKubectl(factory, o)
+			Expect(cmd.RunE(cmd, []string{"apply", "-f", "-"})).To(MatchError(`run kubectl apply denied for garden "garden": changes are disabled by denyMutations in the gardenctl configuration`))
+			Expect(questions).To(BeEmpty())
+			Expect(executedArgs).To(BeNil())
+		})
+	})
+
+	DescribeTable("detecting kubectl commands that change resources",
+		func(args []string, verb string) {
+			Expect(kubectl.MutatingVerb(args)).To(Equal(verb))
+		},
+		Entry("delete", []string{"delete", "pod", "foo"}, "delete"),
+		Entry("global flags before the command", []string{"-n", "kube-system", "--context=shoot", "-v", "6", "apply", "-f", "-"}, "apply"),
+		Entry("exec", []string{"exec", "-it", "foo", "--", "sh"}, "exec"),
+		Entry("cp", []string{"cp", "foo:/tmp/a", "a"}, "cp"),
+		Entry("attach", []string{"attach", "foo"}, "attach"),
+		Entry("debug", []string{"debug", "node/foo", "--image", "busybox"}, "debug"),
+		Entry("verb as resource name", []string{"get", "configmap", "delete"}, ""),
+		Entry("verb as value of a global flag", []string{"--namespace", "delete", "get", "pods"}, ""),
+		Entry("verb in the executed command", []string{"get", "pods", "--", "delete"}, ""),
+		Entry("no command", []string{"--help"}, ""),
+	)
+
+	It("should fail without kubectl arguments", func() {
+		cmd := kubectl.NewCmdKubectl(factory, o)
+		Expect(cmd.RunE(cmd, nil)).To(MatchError(ContainSubstring("no kubectl arguments given")))
+	})
+})
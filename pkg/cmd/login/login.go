@@ -0,0 +1,176 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package login
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/gardener/gardenctl-v2/internal/oidc"
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
+)
+
+// NewCmdLogin returns a new login command.
+func NewCmdLogin(f util.Factory, o *LoginOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "login [GARDEN]",
+		Short: "Log in to a garden with its OIDC provider",
+		Long: `Log in to a garden with the OIDC provider that is configured in the oidc section of the garden in the gardenctl configuration.
+The authorization code flow with PKCE is started in your browser and the authorization response is received by gardenctl on
+http://127.0.0.1:<callbackPort>/callback. The ID and refresh tokens are cached per cluster identity of the garden in the tokens
+directory of the gardenctl home directory, so that they are kept if the garden is renamed. The cached ID token is used as credentials for the garden cluster and is refreshed automatically once it expires.
+If no garden is given, the currently targeted garden is used.`,
+		Example: `# log in to the currently targeted garden
+gardenctl login
+
+# log in to the garden my-garden without opening a browser
+gardenctl login my-garden --no-browser`,
+		Args: cobra.MaximumNArgs(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) != 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+
+			manager, err := f.Manager()
+			if err != nil {
+				fmt.Fprintln(o.IOStreams.ErrOut, err.Error())
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+
+			names, err := util.GardenNames(manager)
+			if err != nil {
+				fmt.Fprintln(o.IOStreams.ErrOut, err.Error())
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+
+			return util.FilterStringsByPrefix(toComplete, names), cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: base.WrapRunE(o, f),
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// LoginOptions is a struct to support login command
+type LoginOptions struct {
+	base.Options
+
+	// Garden is the identity or alias of the garden to log in to
+	Garden string
+	// NoBrowser only prints the authorization URL instead of opening it in the browser
+	NoBrowser bool
+	// Timeout is the maximum duration to wait for the login to complete
+	Timeout time.Duration
+	// OpenBrowser opens the authorization URL in the browser
+	OpenBrowser func(url string) error
+}
+
+// NewLoginOptions returns initialized LoginOptions
+func NewLoginOptions(ioStreams util.IOStreams) *LoginOptions {
+	return &LoginOptions{
+		Options: base.Options{
+			IOStreams: ioStreams,
+		},
+		Timeout:     5 * time.Minute,
+		OpenBrowser: oidc.OpenBrowser,
+	}
+}
+
+// AddFlags binds the command options to a given flagset
+func (o *LoginOptions) AddFlags(flags *pflag.FlagSet) {
+	flags.BoolVar(&o.NoBrowser, "no-browser", o.NoBrowser, "Print the URL to log in instead of opening it in the browser.")
+	flags.DurationVar(&o.Timeout, "timeout", o.Timeout, "Maximum duration to wait for the login to complete.")
+}
+
+// Complete adapts from the command line args to the data required.
+func (o *LoginOptions) Complete(f util.Factory, cmd *cobra.Command, args []string) error {
+	if len(args) > 0 {
+		o.Garden = args[0]
+	}
+
+	return nil
+}
+
+// Validate validates the provided options
+func (o *LoginOptions) Validate() error {
+	if o.Timeout <= 0 {
+		return errors.New("the timeout must be positive")
+	}
+
+	return nil
+}
+
+// Run executes the command
+func (o *LoginOptions) Run(f util.Factory) error {
+	manager, err := f.Manager()
+	if err != nil {
+		return err
+	}
+
+	name := o.Garden
+	if name == "" {
+		currentTarget, err := manager.CurrentTarget()
+		if err != nil {
+			return fmt.Errorf("failed to get current target: %w", err)
+		}
+
+		name = currentTarget.GardenName()
+	}
+
+	if name == "" {
+		return errors.New("no garden targeted, specify the garden to log in to")
+	}
+
+	cfg := manager.Configuration()
+
+	garden, err := cfg.Garden(name)
+	if err != nil {
+		return err
+	}
+
+	if garden.OIDC == nil {
+		return fmt.Errorf("garden %q has no oidc configuration, see the oidc section of the gardens in the gardenctl configuration", garden.Name)
+	}
+
+	openBrowser := o.OpenBrowser
+	if o.NoBrowser {
+		openBrowser = nil
+	}
+
+	ctx, cancel := context.WithTimeout(f.Context(), o.Timeout)
+	defer cancel()
+
+	token, err := oidc.Login(ctx, garden.OIDC.Provider(), oidc.LoginOptions{
+		CallbackPort: garden.OIDC.GetCallbackPort(),
+		OpenBrowser:  openBrowser,
+		Out:          o.IOStreams.ErrOut,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to log in to garden %s: %w", garden.Name, err)
+	}
+
+	key, err := cfg.TokenCacheKey(garden.Name)
+	if err != nil {
+		return err
+	}
+
+	if err := cfg.TokenCache().Save(key, token); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(o.IOStreams.Out, "Successfully logged in to garden %s, the token expires at %s\n", garden.Name, token.Expiry.Local().Format(time.RFC3339))
+
+	return nil
+}
@@ -0,0 +1,102 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package login_test
+
+import (
+	"net"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	internalfake "github.com/gardener/gardenctl-v2/internal/fake"
+	"github.com/gardener/gardenctl-v2/internal/util"
+	cmdlogin "github.com/gardener/gardenctl-v2/pkg/cmd/login"
+	"github.com/gardener/gardenctl-v2/pkg/config"
+	"github.com/gardener/gardenctl-v2/pkg/target"
+)
+
+var _ = Describe("Login Command", func() {
+	var (
+		idp     *internalfake.OIDCProvider
+		cfg     *config.Config
+		streams util.IOStreams
+		out     *util.SafeBytesBuffer
+		errOut  *util.SafeBytesBuffer
+		factory *internalfake.Factory
+	)
+
+	BeforeEach(func() {
+		idp = internalfake.NewOIDCProvider("gardenctl")
+
+		// reserve a free port for the callback listener
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+		port := listener.Addr().(*net.TCPAddr).Port
+		Expect(listener.Close()).To(Succeed())
+
+		tokenDir, err := os.MkdirTemp("", "gctlv2-tokens-")
+		Expect(err).NotTo(HaveOccurred())
+
+		cfg = &config.Config{
+			TokenDir: tokenDir,
+			Gardens: []config.Garden{
+				{
+					Name:            "garden",
+					ClusterIdentity: "landscape",
+					Aliases:         []string{"g"},
+					OIDC:            &config.OIDC{IssuerURL: idp.URL, ClientID: "gardenctl", CallbackPort: port},
+				},
+				{Name: "other"},
+			},
+		}
+		streams, _, out, errOut = util.NewTestIOStreams()
+		factory = internalfake.NewFakeFactory(cfg, nil, nil, internalfake.NewFakeTargetProvider(target.NewTarget("garden", "", "", "")))
+	})
+
+	AfterEach(func() {
+		idp.Close()
+		Expect(os.RemoveAll(cfg.TokenDir)).To(Succeed())
+	})
+
+	newOptions := func() *cmdlogin.LoginOptions {
+		o := cmdlogin.NewLoginOptions(streams)
+		o.OpenBrowser = idp.Browser
+
+		return o
+	}
+
+	It("should log in to the targeted garden and cache the token", func() {
+		cmd := cmdlogin.NewCmdLogin(factory, newOptions())
+		Expect(cmd.RunE(cmd, nil)).To(Succeed())
+		Expect(out.String()).To(HavePrefix("Successfully logged in to garden garden"))
+		Expect(errOut.String()).To(ContainSubstring("Your browser has been opened to log in"))
+		Expect(idp.Issued()).To(Equal(1))
+
+		token, err := cfg.TokenCache().Load("landscape")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(token.RefreshToken).To(Equal(idp.RefreshToken()))
+	})
+
+	It("should log in to a garden given by its alias", func() {
+		factory.TargetProviderImpl = internalfake.NewFakeTargetProvider(target.NewTarget("", "", "", ""))
+		cmd := cmdlogin.NewCmdLogin(factory, newOptions())
+		Expect(cmd.RunE(cmd, []string{"g"})).To(Succeed())
+		Expect(cfg.TokenCache().Filename("landscape")).To(BeARegularFile())
+	})
+
+	It("should fail if the garden has no oidc configuration", func() {
+		cmd := cmdlogin.NewCmdLogin(factory, newOptions())
+		Expect(cmd.RunE(cmd, []string{"other"})).To(MatchError(ContainSubstring(`garden "other" has no oidc configuration`)))
+	})
+
+	It("should fail if no garden is targeted", func() {
+		factory.TargetProviderImpl = internalfake.NewFakeTargetProvider(target.NewTarget("", "", "", ""))
+		cmd := cmdlogin.NewCmdLogin(factory, newOptions())
+		Expect(cmd.RunE(cmd, nil)).To(MatchError("no garden targeted, specify the garden to log in to"))
+	})
+})
@@ -0,0 +1,274 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package logs
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/pointer"
+
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
+)
+
+// component identifies the pods and the container of a control plane component in the control plane namespace of a shoot
+type component struct {
+	selector  labels.Set
+	container string
+}
+
+// components are the control plane components whose logs can be printed
+var components = map[string]component{
+	"kube-apiserver": {
+		selector:  labels.Set{"app": "kubernetes", "role": "apiserver"},
+		container: "kube-apiserver",
+	},
+	"kube-controller-manager": {
+		selector:  labels.Set{"app": "kubernetes", "role": "controller-manager"},
+		container: "kube-controller-manager",
+	},
+	"etcd": {
+		selector:  labels.Set{"app": "etcd-statefulset", "role": "main"},
+		container: "etcd",
+	},
+	"machine-controller-manager": {
+		selector:  labels.Set{"app": "kubernetes", "role": "machine-controller-manager"},
+		container: "machine-controller-manager",
+	},
+}
+
+// componentNames returns the sorted names of the components
+func componentNames() []string {
+	names := make([]string, 0, len(components))
+	for name := range components {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// NewCmdLogs returns a new logs command.
+func NewCmdLogs(f util.Factory, o *LogsOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "logs COMPONENT",
+		Short: "Print the logs of a control plane component of the targeted shoot",
+		Long: fmt.Sprintf(`Print the logs of a control plane component of the targeted shoot.
+The logs are read from the pods of the component in the control plane namespace of the shoot on its seed, the seed does not need to be targeted.
+If the component has multiple pods, e.g. multiple replicas of the kube-apiserver, the lines are prefixed with the name of the pod.
+Reading the logs requires access to the seed and is usually only allowed for Gardener operators.
+
+Supported components: %s`, strings.Join(componentNames(), ", ")),
+		Example: `# print the logs of the kube-apiserver of the last hour
+gardenctl logs kube-apiserver --since 1h
+
+# follow the logs of the etcd of the shoot my-shoot
+gardenctl logs etcd --shoot my-shoot --follow`,
+		Args:      cobra.ExactValidArgs(1),
+		ValidArgs: componentNames(),
+		RunE:      base.WrapRunE(o, f),
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// LogsOptions is a struct to support logs command
+type LogsOptions struct {
+	base.Options
+
+	// Component is the name of the control plane component
+	Component string
+	// Since only returns the logs that are newer than the given duration
+	Since time.Duration
+	// Follow streams the logs until the command is interrupted
+	Follow bool
+	// Tail is the number of lines of the end of the logs to print, all lines are printed if it is negative
+	Tail int64
+}
+
+// NewLogsOptions returns initialized LogsOptions
+func NewLogsOptions(ioStreams util.IOStreams) *LogsOptions {
+	return &LogsOptions{
+		Options: base.Options{
+			IOStreams: ioStreams,
+		},
+		Tail: -1,
+	}
+}
+
+// AddFlags binds the command options to a given flagset
+func (o *LogsOptions) AddFlags(flags *pflag.FlagSet) {
+	flags.DurationVar(&o.Since, "since", o.Since, "Only print the logs that are newer than the given duration, e.g. 5m or 1h. Defaults to all logs.")
+	flags.BoolVarP(&o.Follow, "follow", "f", o.Follow, "Stream the logs until the command is interrupted.")
+	flags.Int64Var(&o.Tail, "tail", o.Tail, "Number of lines of the end of the logs to print of each pod. Defaults to all lines.")
+}
+
+// Complete adapts from the command line args to the data required.
+func (o *LogsOptions) Complete(f util.Factory, cmd *cobra.Command, args []string) error {
+	if len(args) > 0 {
+		o.Component = args[0]
+	}
+
+	return nil
+}
+
+// Validate validates the provided options
+func (o *LogsOptions) Validate() error {
+	if _, ok := components[o.Component]; !ok {
+		return fmt.Errorf("invalid component %q, must be one of %s", o.Component, strings.Join(componentNames(), ", "))
+	}
+
+	if o.Since < 0 {
+		return errors.New("since must not be negative")
+	}
+
+	return nil
+}
+
+// Run executes the command
+func (o *LogsOptions) Run(f util.Factory) error {
+	manager, err := f.Manager()
+	if err != nil {
+		return err
+	}
+
+	currentTarget, err := manager.CurrentTarget()
+	if err != nil {
+		return err
+	}
+
+	if currentTarget.ShootName() == "" {
+		return errors.New("no Shoot cluster targeted")
+	}
+
+	ctx := f.Context()
+
+	// the client configuration of the control plane points to the control plane namespace on the seed
+	clientConfig, err := manager.ClientConfig(ctx, currentTarget.WithControlPlane(true))
+	if err != nil {
+		return err
+	}
+
+	namespace, _, err := clientConfig.Namespace()
+	if err != nil {
+		return err
+	}
+
+	clientset, err := f.Clientset(clientConfig)
+	if err != nil {
+		return err
+	}
+
+	c := components[o.Component]
+
+	podList, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: c.selector.String()})
+	if err != nil {
+		return fmt.Errorf("failed to list pods of %s: %w", o.Component, err)
+	}
+
+	if len(podList.Items) == 0 {
+		return fmt.Errorf("no pods of %s found in namespace %s", o.Component, namespace)
+	}
+
+	pods := podList.Items
+	sort.Slice(pods, func(i, j int) bool {
+		return pods[i].Name < pods[j].Name
+	})
+
+	logOptions := &corev1.PodLogOptions{
+		Container: c.container,
+		Follow:    o.Follow,
+	}
+
+	if o.Since > 0 {
+		logOptions.SinceSeconds = pointer.Int64(int64(o.Since.Seconds()))
+	}
+
+	if o.Tail >= 0 {
+		logOptions.TailLines = pointer.Int64(o.Tail)
+	}
+
+	if len(pods) == 1 {
+		return o.streamLogs(ctx, clientset, pods[0], logOptions, "", &sync.Mutex{})
+	}
+
+	// the logs of all pods are streamed concurrently, which is required to follow them
+	var (
+		wg   sync.WaitGroup
+		lock sync.Mutex
+		errs = make([]error, len(pods))
+	)
+
+	for i := range pods {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			errs[i] = o.streamLogs(ctx, clientset, pods[i], logOptions, fmt.Sprintf("[%s] ", pods[i].Name), &lock)
+		}(i)
+	}
+
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// streamLogs writes the log lines of the pod with the given prefix to the output, the lock serializes the lines of concurrent streams
+func (o *LogsOptions) streamLogs(ctx context.Context, clientset kubernetes.Interface, pod corev1.Pod, logOptions *corev1.PodLogOptions, prefix string, lock sync.Locker) error {
+	stream, err := clientset.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, logOptions).Stream(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get logs of pod %s: %w", pod.Name, err)
+	}
+	defer stream.Close()
+
+	reader := bufio.NewReader(stream)
+
+	for {
+		line, err := reader.ReadString('\n')
+		if len(line) > 0 {
+			if !strings.HasSuffix(line, "\n") {
+				line += "\n"
+			}
+
+			lock.Lock()
+			fmt.Fprint(o.IOStreams.Out, prefix+line)
+			lock.Unlock()
+		}
+
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to read logs of pod %s: %w", pod.Name, err)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package logs_test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/utils/pointer"
+
+	internalfake "github.com/gardener/gardenctl-v2/internal/fake"
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/logs"
+	"github.com/gardener/gardenctl-v2/pkg/target"
+	targetmocks "github.com/gardener/gardenctl-v2/pkg/target/mocks"
+)
+
+var _ = Describe("Logs Command", func() {
+	const technicalID = "shoot--prod1--my-shoot"
+
+	var (
+		ctrl          *gomock.Controller
+		manager       *targetmocks.MockManager
+		factory       *internalfake.Factory
+		streams       util.IOStreams
+		out           *util.SafeBytesBuffer
+		currentTarget target.Target
+		clientConfig  clientcmd.ClientConfig
+		clientset     *fake.Clientset
+	)
+
+	newPod := func(name string, labels map[string]string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: technicalID, Labels: labels},
+		}
+	}
+
+	logOptions := func() []*corev1.PodLogOptions {
+		var options []*corev1.PodLogOptions
+
+		for _, action := range clientset.Actions() {
+			if action.GetSubresource() == "log" {
+				options = append(options, action.(k8stesting.GenericAction).GetValue().(*corev1.PodLogOptions))
+			}
+		}
+
+		return options
+	}
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		manager = targetmocks.NewMockManager(ctrl)
+		factory = internalfake.NewFakeFactory(nil, nil, nil, nil)
+		factory.ManagerImpl = manager
+		streams, _, out, _ = util.NewTestIOStreams()
+		currentTarget = target.NewTarget("garden", "prod1", "", "my-shoot")
+
+		config := clientcmdapi.NewConfig()
+		config.Clusters["seed"] = &clientcmdapi.Cluster{Server: "https://api.seed.example.com"}
+		config.AuthInfos["seed"] = &clientcmdapi.AuthInfo{Token: "token"}
+		config.Contexts["seed"] = &clientcmdapi.Context{Cluster: "seed", AuthInfo: "seed", Namespace: technicalID}
+		config.CurrentContext = "seed"
+		clientConfig = clientcmd.NewDefaultClientConfig(*config, nil)
+
+		clientset = fake.NewSimpleClientset(
+			newPod("kube-apiserver-0", map[string]string{"app": "kubernetes", "role": "apiserver"}),
+			newPod("kube-apiserver-1", map[string]string{"app": "kubernetes", "role": "apiserver"}),
+			newPod("etcd-main-0", map[string]string{"app": "etcd-statefulset", "role": "main"}),
+		)
+
+		factory.ClientsetImpl = func(c clientcmd.ClientConfig) (kubernetes.Interface, error) {
+			Expect(c).To(BeIdenticalTo(clientConfig))
+			return clientset, nil
+		}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Context("when a shoot is targeted", func() {
+		BeforeEach(func() {
+			manager.EXPECT().CurrentTarget().Return(currentTarget, nil)
+			manager.EXPECT().ClientConfig(context.Background(), currentTarget.WithControlPlane(true)).Return(clientConfig, nil)
+		})
+
+		It("should print the logs of the pod of the component", func() {
+			cmd := logs.NewCmdLogs(factory, logs.NewLogsOptions(streams))
+			cmd.SetArgs([]string{"etcd", "--since", "1h", "--tail", "10"})
+			Expect(cmd.Execute()).To(Succeed())
+
+			Expect(out.String()).To(Equal("fake logs\n"))
+			Expect(logOptions()).To(ConsistOf(&corev1.PodLogOptions{
+				Container:    "etcd",
+				SinceSeconds: pointer.Int64(3600),
+				TailLines:    pointer.Int64(10),
+			}))
+		})
+
+		It("should prefix the logs of multiple pods with the pod name", func() {
+			cmd := logs.NewCmdLogs(factory, logs.NewLogsOptions(streams))
+			cmd.SetArgs([]string{"kube-apiserver", "--follow"})
+			Expect(cmd.Execute()).To(Succeed())
+
+			Expect(out.String()).To(ContainSubstring("[kube-apiserver-0] fake logs\n"))
+			Expect(out.String()).To(ContainSubstring("[kube-apiserver-1] fake logs\n"))
+			Expect(logOptions()).To(HaveLen(2))
+			Expect(logOptions()[0]).To(Equal(&corev1.PodLogOptions{Container: "kube-apiserver", Follow: true}))
+		})
+
+		It("should fail if the component has no pods", func() {
+			cmd := logs.NewCmdLogs(factory, logs.NewLogsOptions(streams))
+			cmd.SetArgs([]string{"machine-controller-manager"})
+			Expect(cmd.Execute()).To(MatchError("no pods of machine-controller-manager found in namespace " + technicalID))
+		})
+
+		It("should fail if the pods cannot be listed", func() {
+			clientset.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				return true, nil, errors.New("forbidden")
+			})
+
+			cmd := logs.NewCmdLogs(factory, logs.NewLogsOptions(streams))
+			cmd.SetArgs([]string{"etcd"})
+			Expect(cmd.Execute()).To(MatchError("failed to list pods of etcd: forbidden"))
+		})
+	})
+
+	It("should fail without a targeted shoot", func() {
+		manager.EXPECT().CurrentTarget().Return(target.NewTarget("garden", "prod1", "", ""), nil)
+
+		cmd := logs.NewCmdLogs(factory, logs.NewLogsOptions(streams))
+		cmd.SetArgs([]string{"etcd"})
+		Expect(cmd.Execute()).To(MatchError("no Shoot cluster targeted"))
+	})
+
+	It("should fail for an unknown component", func() {
+		cmd := logs.NewCmdLogs(factory, logs.NewLogsOptions(streams))
+		cmd.SetArgs([]string{"kube-scheduler"})
+		Expect(cmd.Execute()).To(MatchError(ContainSubstring(`invalid argument "kube-scheduler"`)))
+	})
+})
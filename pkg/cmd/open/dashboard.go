@@ -0,0 +1,142 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package open
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
+	"github.com/gardener/gardenctl-v2/pkg/config"
+	"github.com/gardener/gardenctl-v2/pkg/target"
+)
+
+// NewCmdDashboard returns a new open dashboard command.
+func NewCmdDashboard(f util.Factory, o *DashboardOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dashboard",
+		Short: "Open the Gardener dashboard of the current target in the browser",
+		Long: `Open the page of the current target in the Gardener dashboard, i.e. the shoot, the shoot list of the project
+or the dashboard of the garden. The URL of the dashboard is taken from the dashboardURL of the garden in the gardenctl
+configuration. If it is not set, the URL is derived from the API server of the garden by the convention that the
+dashboard is served on the dashboard subdomain of the garden domain, e.g. https://dashboard.garden.example.com for
+https://api.garden.example.com. Use --print-url on headless systems to print the URL instead of opening it.`,
+		Example: `# open the targeted shoot in the Gardener dashboard
+gardenctl open dashboard
+
+# print the dashboard URL of the shoot my-shoot
+gardenctl open dashboard --shoot my-shoot --print-url`,
+		Args: cobra.NoArgs,
+		RunE: base.WrapRunE(o, f),
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// DashboardOptions is a struct to support open dashboard command
+type DashboardOptions struct {
+	OpenOptions
+}
+
+// NewDashboardOptions returns initialized DashboardOptions
+func NewDashboardOptions(ioStreams util.IOStreams) *DashboardOptions {
+	return &DashboardOptions{
+		OpenOptions: newOpenOptions(ioStreams),
+	}
+}
+
+// Run executes the command
+func (o *DashboardOptions) Run(f util.Factory) error {
+	manager, err := f.Manager()
+	if err != nil {
+		return err
+	}
+
+	currentTarget, err := manager.CurrentTarget()
+	if err != nil {
+		return fmt.Errorf("failed to get current target: %w", err)
+	}
+
+	if currentTarget.GardenName() == "" {
+		return target.ErrNoGardenTargeted
+	}
+
+	cfg := manager.Configuration()
+
+	garden, err := cfg.Garden(currentTarget.GardenName())
+	if err != nil {
+		return err
+	}
+
+	baseURL, err := dashboardURL(cfg, garden)
+	if err != nil {
+		return err
+	}
+
+	if currentTarget.ShootName() == "" && currentTarget.ProjectName() == "" {
+		return o.open(baseURL + "/")
+	}
+
+	gardenClient, err := manager.GardenClient(garden.Name)
+	if err != nil {
+		return fmt.Errorf("failed to create garden cluster client: %w", err)
+	}
+
+	ctx := f.Context()
+
+	if currentTarget.ShootName() != "" {
+		shoot, err := util.ShootForTarget(ctx, gardenClient, currentTarget)
+		if err != nil {
+			return err
+		}
+
+		return o.open(fmt.Sprintf("%s/namespace/%s/shoots/%s", baseURL, shoot.Namespace, shoot.Name))
+	}
+
+	project, err := gardenClient.GetProject(ctx, currentTarget.ProjectName())
+	if err != nil {
+		return err
+	}
+
+	if project.Spec.Namespace == nil {
+		return fmt.Errorf("project %s has no namespace", project.Name)
+	}
+
+	return o.open(fmt.Sprintf("%s/namespace/%s/shoots", baseURL, *project.Spec.Namespace))
+}
+
+// dashboardURL returns the URL of the Gardener dashboard of the garden without a trailing slash. It is either the
+// configured dashboardURL of the garden or it is derived from the host of the garden API server, whose api subdomain
+// is replaced with dashboard.
+func dashboardURL(cfg *config.Config, garden *config.Garden) (string, error) {
+	if garden.DashboardURL != "" {
+		return strings.TrimSuffix(garden.DashboardURL, "/"), nil
+	}
+
+	clientConfig, err := cfg.ClientConfig(garden.Name)
+	if err != nil {
+		return "", err
+	}
+
+	restConfig, err := clientConfig.ClientConfig()
+	if err != nil {
+		return "", fmt.Errorf("failed to load the client configuration of garden %s: %w", garden.Name, err)
+	}
+
+	server, err := url.Parse(restConfig.Host)
+	if err != nil || !strings.HasPrefix(server.Hostname(), "api.") {
+		return "", fmt.Errorf("cannot derive the dashboard URL from the API server %s of garden %s, set the dashboardURL of the garden in the gardenctl configuration", restConfig.Host, garden.Name)
+	}
+
+	return "https://dashboard." + strings.TrimPrefix(server.Hostname(), "api."), nil
+}
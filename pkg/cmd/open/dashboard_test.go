@@ -0,0 +1,144 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package open_test
+
+import (
+	"errors"
+	"strings"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	internalfake "github.com/gardener/gardenctl-v2/internal/fake"
+	"github.com/gardener/gardenctl-v2/internal/util"
+	cmdopen "github.com/gardener/gardenctl-v2/pkg/cmd/open"
+	"github.com/gardener/gardenctl-v2/pkg/config"
+	"github.com/gardener/gardenctl-v2/pkg/target"
+	targetmocks "github.com/gardener/gardenctl-v2/pkg/target/mocks"
+)
+
+const gardenKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: garden
+  cluster:
+    server: https://api.garden.example.com
+contexts:
+- name: garden
+  context:
+    cluster: garden
+    user: garden
+current-context: garden
+users:
+- name: garden
+  user:
+    token: token
+`
+
+var _ = Describe("Open Dashboard Command", func() {
+	var (
+		ctrl    *gomock.Controller
+		cfg     *config.Config
+		streams util.IOStreams
+		out     *util.SafeBytesBuffer
+		errOut  *util.SafeBytesBuffer
+		factory *internalfake.Factory
+		opened  []string
+	)
+
+	BeforeEach(func() {
+		cfg = &config.Config{
+			LinkKubeconfig: pointer.Bool(false),
+			Gardens: []config.Garden{{
+				Name:           "garden",
+				KubeconfigData: gardenKubeconfig,
+			}},
+		}
+
+		project := &gardencorev1beta1.Project{
+			ObjectMeta: metav1.ObjectMeta{Name: "prod1"},
+			Spec:       gardencorev1beta1.ProjectSpec{Namespace: pointer.String("garden-prod1")},
+		}
+
+		shoot := &gardencorev1beta1.Shoot{
+			ObjectMeta: metav1.ObjectMeta{Name: "myshoot", Namespace: "garden-prod1"},
+		}
+
+		ctrl = gomock.NewController(GinkgoT())
+		clientProvider := targetmocks.NewMockClientProvider(ctrl)
+		clientProvider.EXPECT().FromClientConfig(gomock.Any()).Return(internalfake.NewClientWithObjects(project, shoot), nil).AnyTimes()
+
+		streams, _, out, errOut = util.NewTestIOStreams()
+		factory = internalfake.NewFakeFactory(cfg, nil, clientProvider, internalfake.NewFakeTargetProvider(target.NewTarget("garden", "prod1", "", "myshoot")))
+		opened = nil
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	newOptions := func() *cmdopen.DashboardOptions {
+		o := cmdopen.NewDashboardOptions(streams)
+		o.OpenBrowser = func(url string) error {
+			opened = append(opened, url)
+			return nil
+		}
+
+		return o
+	}
+
+	It("should open the targeted shoot in the dashboard derived from the garden API server", func() {
+		cmd := cmdopen.NewCmdDashboard(factory, newOptions())
+		Expect(cmd.RunE(cmd, nil)).To(Succeed())
+		Expect(opened).To(ConsistOf("https://dashboard.garden.example.com/namespace/garden-prod1/shoots/myshoot"))
+		Expect(out.String()).To(BeEmpty())
+		Expect(errOut.String()).To(ContainSubstring("Opened https://dashboard.garden.example.com/namespace/garden-prod1/shoots/myshoot in the browser"))
+	})
+
+	It("should print the shoot list of the targeted project with the configured dashboard URL", func() {
+		cfg.Gardens[0].DashboardURL = "https://gardener.example.com/"
+		factory.TargetProviderImpl = internalfake.NewFakeTargetProvider(target.NewTarget("garden", "prod1", "", ""))
+
+		o := newOptions()
+		o.PrintURL = true
+		cmd := cmdopen.NewCmdDashboard(factory, o)
+		Expect(cmd.RunE(cmd, nil)).To(Succeed())
+		Expect(opened).To(BeEmpty())
+		Expect(out.String()).To(Equal("https://gardener.example.com/namespace/garden-prod1/shoots\n"))
+	})
+
+	It("should print the URL if the browser cannot be opened", func() {
+		factory.TargetProviderImpl = internalfake.NewFakeTargetProvider(target.NewTarget("garden", "", "", ""))
+
+		o := newOptions()
+		o.OpenBrowser = func(string) error {
+			return errors.New("no browser")
+		}
+		cmd := cmdopen.NewCmdDashboard(factory, o)
+		Expect(cmd.RunE(cmd, nil)).To(Succeed())
+		Expect(out.String()).To(Equal("https://dashboard.garden.example.com/\n"))
+		Expect(errOut.String()).To(ContainSubstring("Failed to open the browser: no browser"))
+	})
+
+	It("should fail if the dashboard URL cannot be derived", func() {
+		cfg.Gardens[0].KubeconfigData = strings.Replace(gardenKubeconfig, "api.garden", "garden", 1)
+
+		cmd := cmdopen.NewCmdDashboard(factory, newOptions())
+		Expect(cmd.RunE(cmd, nil)).To(MatchError(ContainSubstring("set the dashboardURL of the garden in the gardenctl configuration")))
+	})
+
+	It("should fail if no garden is targeted", func() {
+		factory.TargetProviderImpl = internalfake.NewFakeTargetProvider(target.NewTarget("", "", "", ""))
+
+		cmd := cmdopen.NewCmdDashboard(factory, newOptions())
+		Expect(cmd.RunE(cmd, nil)).To(MatchError(target.ErrNoGardenTargeted))
+	})
+})
@@ -0,0 +1,166 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package open
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/spf13/cobra"
+
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
+)
+
+const (
+	appGrafana    = "grafana"
+	appPlutono    = "plutono"
+	appPrometheus = "prometheus"
+
+	// technicalIDPrefix is the prefix of the technical ID of a shoot, which is omitted in the ingress hosts of its monitoring
+	technicalIDPrefix = "shoot--"
+)
+
+// ingressPrefixes are the prefixes of the ingress hosts of the monitoring applications of a shoot on its seed.
+// Plutono is the fork of Grafana that replaces it in newer Gardener versions and is served on the same host.
+var ingressPrefixes = map[string]string{
+	appGrafana:    "gu",
+	appPlutono:    "gu",
+	appPrometheus: "p",
+}
+
+// titles are the names of the monitoring applications in the help texts
+var titles = map[string]string{
+	appGrafana:    "Grafana",
+	appPlutono:    "Plutono",
+	appPrometheus: "Prometheus",
+}
+
+// NewCmdMonitoring returns a new open command for a monitoring application of the targeted shoot.
+func NewCmdMonitoring(f util.Factory, o *MonitoringOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   o.App,
+		Short: fmt.Sprintf("Open the %s of the targeted shoot in the browser", o.title()),
+		Long: fmt.Sprintf(`Open the %[1]s of the targeted shoot in the browser. Following the Gardener conventions, the %[1]s is
+served by the ingress of the seed of the shoot on the host <prefix>-<project>--<shoot>.<seed ingress domain>.
+The credentials for the monitoring of the shoot are stored in the secret <shoot>.monitoring in the namespace of its project.
+Use --print-url on headless systems to print the URL instead of opening it.`, o.title()),
+		Example: fmt.Sprintf(`# open the %[1]s of the targeted shoot
+gardenctl open %[1]s
+
+# print the URL of the %[1]s of the shoot my-shoot
+gardenctl open %[1]s --shoot my-shoot --print-url`, o.App),
+		Args: cobra.NoArgs,
+		RunE: base.WrapRunE(o, f),
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// MonitoringOptions is a struct to support the open commands of the monitoring applications
+type MonitoringOptions struct {
+	OpenOptions
+
+	// App is the monitoring application to open, e.g. plutono or prometheus
+	App string
+}
+
+// NewMonitoringOptions returns initialized MonitoringOptions for the given monitoring application
+func NewMonitoringOptions(ioStreams util.IOStreams, app string) *MonitoringOptions {
+	return &MonitoringOptions{
+		OpenOptions: newOpenOptions(ioStreams),
+		App:         app,
+	}
+}
+
+// title returns the name of the monitoring application for the help texts
+func (o *MonitoringOptions) title() string {
+	if title, ok := titles[o.App]; ok {
+		return title
+	}
+
+	return o.App
+}
+
+// Validate validates the provided options
+func (o *MonitoringOptions) Validate() error {
+	if _, ok := ingressPrefixes[o.App]; !ok {
+		return fmt.Errorf("unsupported monitoring application %q", o.App)
+	}
+
+	return nil
+}
+
+// Run executes the command
+func (o *MonitoringOptions) Run(f util.Factory) error {
+	manager, err := f.Manager()
+	if err != nil {
+		return err
+	}
+
+	currentTarget, err := manager.CurrentTarget()
+	if err != nil {
+		return fmt.Errorf("failed to get current target: %w", err)
+	}
+
+	if currentTarget.ShootName() == "" {
+		return errors.New("no Shoot cluster targeted")
+	}
+
+	gardenClient, err := manager.GardenClient(currentTarget.GardenName())
+	if err != nil {
+		return fmt.Errorf("failed to create garden cluster client: %w", err)
+	}
+
+	ctx := f.Context()
+
+	shoot, err := util.ShootForTarget(ctx, gardenClient, currentTarget)
+	if err != nil {
+		return err
+	}
+
+	if shoot.Spec.SeedName == nil {
+		return fmt.Errorf("shoot %s/%s is not scheduled to a seed yet", shoot.Namespace, shoot.Name)
+	}
+
+	if shoot.Status.TechnicalID == "" {
+		return fmt.Errorf("shoot %s/%s has no technical ID yet", shoot.Namespace, shoot.Name)
+	}
+
+	seed, err := gardenClient.GetSeed(ctx, *shoot.Spec.SeedName)
+	if err != nil {
+		return err
+	}
+
+	domain := seedIngressDomain(seed)
+	if domain == "" {
+		return fmt.Errorf("seed %s has no ingress domain", seed.Name)
+	}
+
+	shortID := strings.TrimPrefix(shoot.Status.TechnicalID, technicalIDPrefix)
+
+	fmt.Fprintf(o.IOStreams.ErrOut, "The credentials are stored in the secret %s/%s.monitoring of the garden %s\n", shoot.Namespace, shoot.Name, currentTarget.GardenName())
+
+	return o.open(fmt.Sprintf("https://%s-%s.%s", ingressPrefixes[o.App], shortID, domain))
+}
+
+// seedIngressDomain returns the ingress domain of the seed, the deprecated dns.ingressDomain takes precedence like in Gardener
+func seedIngressDomain(seed *gardencorev1beta1.Seed) string {
+	if seed.Spec.DNS.IngressDomain != nil {
+		return *seed.Spec.DNS.IngressDomain
+	}
+
+	if seed.Spec.Ingress != nil {
+		return seed.Spec.Ingress.Domain
+	}
+
+	return ""
+}
@@ -0,0 +1,117 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package open_test
+
+import (
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	internalfake "github.com/gardener/gardenctl-v2/internal/fake"
+	"github.com/gardener/gardenctl-v2/internal/util"
+	cmdopen "github.com/gardener/gardenctl-v2/pkg/cmd/open"
+	"github.com/gardener/gardenctl-v2/pkg/config"
+	"github.com/gardener/gardenctl-v2/pkg/target"
+	targetmocks "github.com/gardener/gardenctl-v2/pkg/target/mocks"
+)
+
+var _ = Describe("Open Monitoring Command", func() {
+	var (
+		ctrl    *gomock.Controller
+		streams util.IOStreams
+		out     *util.SafeBytesBuffer
+		errOut  *util.SafeBytesBuffer
+		factory *internalfake.Factory
+		shoot   *gardencorev1beta1.Shoot
+		seed    *gardencorev1beta1.Seed
+	)
+
+	BeforeEach(func() {
+		cfg := &config.Config{
+			LinkKubeconfig: pointer.Bool(false),
+			Gardens: []config.Garden{{
+				Name:           "garden",
+				KubeconfigData: gardenKubeconfig,
+			}},
+		}
+
+		project := &gardencorev1beta1.Project{
+			ObjectMeta: metav1.ObjectMeta{Name: "prod1"},
+			Spec:       gardencorev1beta1.ProjectSpec{Namespace: pointer.String("garden-prod1")},
+		}
+
+		shoot = &gardencorev1beta1.Shoot{
+			ObjectMeta: metav1.ObjectMeta{Name: "myshoot", Namespace: "garden-prod1"},
+			Spec:       gardencorev1beta1.ShootSpec{SeedName: pointer.String("myseed")},
+			Status:     gardencorev1beta1.ShootStatus{TechnicalID: "shoot--prod1--myshoot"},
+		}
+
+		seed = &gardencorev1beta1.Seed{
+			ObjectMeta: metav1.ObjectMeta{Name: "myseed"},
+			Spec: gardencorev1beta1.SeedSpec{
+				Ingress: &gardencorev1beta1.Ingress{Domain: "ingress.myseed.example.com"},
+			},
+		}
+
+		ctrl = gomock.NewController(GinkgoT())
+		clientProvider := targetmocks.NewMockClientProvider(ctrl)
+		clientProvider.EXPECT().FromClientConfig(gomock.Any()).DoAndReturn(func(interface{}) (client.Client, error) {
+			return internalfake.NewClientWithObjects(project, shoot, seed), nil
+		}).AnyTimes()
+
+		streams, _, out, errOut = util.NewTestIOStreams()
+		factory = internalfake.NewFakeFactory(cfg, nil, clientProvider, internalfake.NewFakeTargetProvider(target.NewTarget("garden", "prod1", "", "myshoot")))
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	DescribeTable("should print the URL of the monitoring application",
+		func(app, url string) {
+			o := cmdopen.NewMonitoringOptions(streams, app)
+			o.PrintURL = true
+			cmd := cmdopen.NewCmdMonitoring(factory, o)
+			Expect(cmd.Use).To(Equal(app))
+			Expect(cmd.RunE(cmd, nil)).To(Succeed())
+			Expect(out.String()).To(Equal(url + "\n"))
+			Expect(errOut.String()).To(ContainSubstring("The credentials are stored in the secret garden-prod1/myshoot.monitoring of the garden garden"))
+		},
+		Entry("grafana", "grafana", "https://gu-prod1--myshoot.ingress.myseed.example.com"),
+		Entry("plutono", "plutono", "https://gu-prod1--myshoot.ingress.myseed.example.com"),
+		Entry("prometheus", "prometheus", "https://p-prod1--myshoot.ingress.myseed.example.com"),
+	)
+
+	It("should prefer the deprecated ingress domain of the seed", func() {
+		seed.Spec.DNS.IngressDomain = pointer.String("old.myseed.example.com")
+
+		o := cmdopen.NewMonitoringOptions(streams, "prometheus")
+		o.PrintURL = true
+		cmd := cmdopen.NewCmdMonitoring(factory, o)
+		Expect(cmd.RunE(cmd, nil)).To(Succeed())
+		Expect(out.String()).To(Equal("https://p-prod1--myshoot.old.myseed.example.com\n"))
+	})
+
+	It("should fail if the shoot is not scheduled", func() {
+		shoot.Spec.SeedName = nil
+
+		cmd := cmdopen.NewCmdMonitoring(factory, cmdopen.NewMonitoringOptions(streams, "plutono"))
+		Expect(cmd.RunE(cmd, nil)).To(MatchError("shoot garden-prod1/myshoot is not scheduled to a seed yet"))
+	})
+
+	It("should fail if no shoot is targeted", func() {
+		factory.TargetProviderImpl = internalfake.NewFakeTargetProvider(target.NewTarget("garden", "prod1", "", ""))
+
+		cmd := cmdopen.NewCmdMonitoring(factory, cmdopen.NewMonitoringOptions(streams, "plutono"))
+		Expect(cmd.RunE(cmd, nil)).To(MatchError("no Shoot cluster targeted"))
+	})
+})
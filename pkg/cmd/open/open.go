@@ -0,0 +1,82 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package open
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/gardener/gardenctl-v2/internal/oidc"
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
+)
+
+// NewCmdOpen returns a new open command.
+func NewCmdOpen(f util.Factory, ioStreams util.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "open",
+		Short: "Open the Gardener dashboard or the monitoring of the current target in the browser",
+	}
+
+	cmd.AddCommand(NewCmdDashboard(f, NewDashboardOptions(ioStreams)))
+	cmd.AddCommand(NewCmdMonitoring(f, NewMonitoringOptions(ioStreams, appGrafana)))
+	cmd.AddCommand(NewCmdMonitoring(f, NewMonitoringOptions(ioStreams, appPlutono)))
+	cmd.AddCommand(NewCmdMonitoring(f, NewMonitoringOptions(ioStreams, appPrometheus)))
+
+	return cmd
+}
+
+// OpenOptions contains the options that are shared by all open commands
+type OpenOptions struct {
+	base.Options
+
+	// PrintURL only prints the URL instead of opening it in the browser
+	PrintURL bool
+	// OpenBrowser opens the URL in the browser
+	OpenBrowser func(url string) error
+}
+
+// newOpenOptions returns initialized OpenOptions
+func newOpenOptions(ioStreams util.IOStreams) OpenOptions {
+	return OpenOptions{
+		Options: base.Options{
+			IOStreams: ioStreams,
+		},
+		OpenBrowser: oidc.OpenBrowser,
+	}
+}
+
+// AddFlags binds the command options to a given flagset
+func (o *OpenOptions) AddFlags(flags *pflag.FlagSet) {
+	flags.BoolVar(&o.PrintURL, "print-url", o.PrintURL, "Print the URL instead of opening it in the browser, e.g. on a headless system.")
+}
+
+// Validate validates the provided options
+func (o *OpenOptions) Validate() error {
+	return nil
+}
+
+// open opens the URL in the browser or prints it if --print-url is set. The URL is also printed if the browser cannot be opened.
+func (o *OpenOptions) open(url string) error {
+	if o.PrintURL || o.OpenBrowser == nil {
+		fmt.Fprintln(o.IOStreams.Out, url)
+		return nil
+	}
+
+	if err := o.OpenBrowser(url); err != nil {
+		fmt.Fprintf(o.IOStreams.ErrOut, "Failed to open the browser: %v\n", err)
+		fmt.Fprintln(o.IOStreams.Out, url)
+
+		return nil
+	}
+
+	fmt.Fprintf(o.IOStreams.ErrOut, "Opened %s in the browser\n", url)
+
+	return nil
+}
@@ -0,0 +1,60 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cmd
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// kubectlPluginBinary is the name of the binary if gardenctl is installed as kubectl plugin, e.g. with krew
+	kubectlPluginBinary = "kubectl-garden"
+	// kubectlPluginCommand is the command that is used to invoke gardenctl as kubectl plugin
+	kubectlPluginCommand = "kubectl garden"
+)
+
+// exampleRegexp matches the gardenctl invocations at the beginning of the lines of examples
+var exampleRegexp = regexp.MustCompile(`(?m)^(\s*)gardenctl `)
+
+// isKubectlPlugin returns true if the binary has been invoked as kubectl plugin,
+// i.e. if the name of the binary is kubectl-garden
+func isKubectlPlugin(arg0 string) bool {
+	return strings.TrimSuffix(filepath.Base(arg0), ".exe") == kubectlPluginBinary
+}
+
+// adaptToKubectlPlugin changes the help texts of the gardenctl command and its subcommands
+// so that they refer to kubectl garden instead of gardenctl
+func adaptToKubectlPlugin(cmd *cobra.Command) {
+	// cobra uses the first word of Use as name of the command, the non-breaking space
+	// lets the usage and help texts show kubectl garden as name of the root command
+	cmd.Use = strings.Replace(kubectlPluginCommand, " ", "\u00a0", 1)
+
+	var names []string
+	for _, c := range cmd.Commands() {
+		names = append(names, regexp.QuoteMeta(c.Name()))
+	}
+
+	// only invocations of subcommands are replaced, other mentions like gardenctl configuration are kept
+	invocationRegexp := regexp.MustCompile(`\bgardenctl (` + strings.Join(names, "|") + `)\b`)
+
+	var adapt func(c *cobra.Command)
+
+	adapt = func(c *cobra.Command) {
+		c.Example = exampleRegexp.ReplaceAllString(c.Example, "${1}"+kubectlPluginCommand+" ")
+		c.Long = invocationRegexp.ReplaceAllString(c.Long, kubectlPluginCommand+" $1")
+
+		for _, sub := range c.Commands() {
+			adapt(sub)
+		}
+	}
+
+	adapt(cmd)
+}
@@ -0,0 +1,18 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package portforward
+
+import (
+	"context"
+	"io"
+
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+func SetForwardPorts(f func(ctx context.Context, clientConfig clientcmd.ClientConfig, namespace, podName string, addresses, ports []string, out, errOut io.Writer) error) {
+	forwardPorts = f
+}
@@ -0,0 +1,400 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package portforward
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+
+	"github.com/gardener/gardenctl-v2/internal/audit"
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
+)
+
+// errLostConnection is returned by the port forwarding if the connection to the pod has been lost
+var errLostConnection = errors.New("lost connection to pod")
+
+var (
+	// forwardPorts forwards the local ports to the pod until the context is cancelled or the connection to the pod is lost
+	forwardPorts = func(ctx context.Context, clientConfig clientcmd.ClientConfig, namespace, podName string, addresses, ports []string, out, errOut io.Writer) error {
+		restConfig, err := clientConfig.ClientConfig()
+		if err != nil {
+			return err
+		}
+
+		transport, upgrader, err := spdy.RoundTripperFor(restConfig)
+		if err != nil {
+			return err
+		}
+
+		clientset, err := kubernetes.NewForConfig(restConfig)
+		if err != nil {
+			return err
+		}
+
+		url := clientset.CoreV1().RESTClient().Post().Resource("pods").Namespace(namespace).Name(podName).SubResource("portforward").URL()
+		dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, url)
+
+		stopChan := make(chan struct{})
+		done := make(chan struct{})
+
+		defer close(done)
+
+		go func() {
+			select {
+			case <-ctx.Done():
+				close(stopChan)
+			case <-done:
+			}
+		}()
+
+		forwarder, err := portforward.NewOnAddresses(dialer, addresses, ports, stopChan, nil, out, errOut)
+		if err != nil {
+			return err
+		}
+
+		return forwarder.ForwardPorts()
+	}
+)
+
+// NewCmdPortForward returns a new port-forward command.
+func NewCmdPortForward(f util.Factory, o *PortForwardOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "port-forward TYPE/NAME [LOCAL_PORT:]REMOTE_PORT [...[LOCAL_PORT_N:]REMOTE_PORT_N]",
+		Short: "Forward local ports to a pod or service of the targeted shoot or its control plane",
+		Long: `Forward one or more local ports to a pod or a service in the targeted shoot cluster, or in the control plane
+namespace of the shoot on its seed if the control plane is targeted. Services are resolved to one of their running pods
+and the service ports are mapped to the target ports of the pod, like kubectl port-forward does.
+
+If the connection to the pod is lost, e.g. because the pod has been restarted or the network dropped, gardenctl
+reconnects automatically after --reconnect-delay. Services are resolved again on every reconnect, so that another
+running pod of the service is used. Use --reconnect=false to exit instead.`,
+		Example: `# forward the local port 8080 to the port 80 of the pod my-pod in the default namespace of the targeted shoot
+gardenctl port-forward my-pod 8080:80
+
+# forward the ports of the prometheus service in the control plane namespace of the shoot
+gardenctl port-forward svc/prometheus-web 9090:80 --control-plane
+
+# forward multiple ports of a service in the namespace monitoring on all addresses
+gardenctl port-forward service/grafana 3000 9100:9100 -n monitoring --address 0.0.0.0`,
+		Args: cobra.MinimumNArgs(2),
+		RunE: base.WrapRunE(o, f),
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// PortForwardOptions is a struct to support port-forward command
+type PortForwardOptions struct {
+	base.Options
+
+	// Kind is the kind of the resource to forward to, either pod or service
+	Kind string
+	// Name is the name of the pod or service
+	Name string
+	// Ports are the ports to forward in the format [LOCAL_PORT:]REMOTE_PORT
+	Ports []string
+	// Namespace is the namespace of the pod or service, defaults to the namespace of the targeted cluster
+	Namespace string
+	// Addresses are the local addresses to listen on
+	Addresses []string
+	// Reconnect reconnects automatically if the connection to the pod is lost
+	Reconnect bool
+	// ReconnectDelay is the time to wait before reconnecting
+	ReconnectDelay time.Duration
+}
+
+// NewPortForwardOptions returns initialized PortForwardOptions
+func NewPortForwardOptions(ioStreams util.IOStreams) *PortForwardOptions {
+	return &PortForwardOptions{
+		Options: base.Options{
+			IOStreams: ioStreams,
+		},
+		Addresses:      []string{"localhost"},
+		Reconnect:      true,
+		ReconnectDelay: time.Second,
+	}
+}
+
+// AddFlags binds the command options to a given flagset
+func (o *PortForwardOptions) AddFlags(flags *pflag.FlagSet) {
+	flags.StringVarP(&o.Namespace, "namespace", "n", o.Namespace, "Namespace of the pod or service. Defaults to the default namespace of the shoot or the control plane namespace of the shoot.")
+	flags.StringSliceVar(&o.Addresses, "address", o.Addresses, "Addresses to listen on (comma separated). Only accepts IP addresses or localhost as a value.")
+	flags.BoolVar(&o.Reconnect, "reconnect", o.Reconnect, "Reconnect automatically if the connection to the pod is lost.")
+	flags.DurationVar(&o.ReconnectDelay, "reconnect-delay", o.ReconnectDelay, "Time to wait before reconnecting to the pod.")
+}
+
+// Complete adapts from the command line args to the data required.
+func (o *PortForwardOptions) Complete(f util.Factory, cmd *cobra.Command, args []string) error {
+	if len(args) < 2 {
+		return errors.New("TYPE/NAME and a list of ports are required")
+	}
+
+	kind, name, err := parseResource(args[0])
+	if err != nil {
+		return err
+	}
+
+	o.Kind = kind
+	o.Name = name
+	o.Ports = args[1:]
+
+	return nil
+}
+
+// Validate validates the provided options
+func (o *PortForwardOptions) Validate() error {
+	for _, port := range o.Ports {
+		if _, _, err := parsePort(port); err != nil {
+			return err
+		}
+	}
+
+	if len(o.Addresses) == 0 {
+		return errors.New("at least one address is required")
+	}
+
+	if o.ReconnectDelay < 0 {
+		return errors.New("the reconnect delay must not be negative")
+	}
+
+	return nil
+}
+
+// parseResource parses a resource in the format TYPE/NAME or NAME, which refers to a pod
+func parseResource(resource string) (string, string, error) {
+	kind, name := "pod", resource
+
+	if i := strings.Index(resource, "/"); i >= 0 {
+		kind, name = resource[:i], resource[i+1:]
+	}
+
+	if name == "" {
+		return "", "", fmt.Errorf("invalid resource %q, the name must not be empty", resource)
+	}
+
+	switch kind {
+	case "pod", "pods", "po":
+		return "pod", name, nil
+	case "service", "services", "svc":
+		return "service", name, nil
+	default:
+		return "", "", fmt.Errorf("invalid resource type %q, must be one of pod or service", kind)
+	}
+}
+
+// parsePort parses a port in the format [LOCAL_PORT:]REMOTE_PORT, an empty local port selects a random port
+func parsePort(port string) (string, int32, error) {
+	local, remote := "", port
+
+	if i := strings.Index(port, ":"); i >= 0 {
+		local, remote = port[:i], port[i+1:]
+	} else {
+		local = remote
+	}
+
+	if local != "" {
+		if _, err := strconv.ParseUint(local, 10, 16); err != nil {
+			return "", 0, fmt.Errorf("invalid local port in %q", port)
+		}
+	}
+
+	remotePort, err := strconv.ParseUint(remote, 10, 16)
+	if err != nil || remotePort == 0 {
+		return "", 0, fmt.Errorf("invalid remote port in %q", port)
+	}
+
+	return local, int32(remotePort), nil
+}
+
+// Run executes the command
+func (o *PortForwardOptions) Run(f util.Factory) error {
+	manager, err := f.Manager()
+	if err != nil {
+		return err
+	}
+
+	currentTarget, err := manager.CurrentTarget()
+	if err != nil {
+		return fmt.Errorf("failed to get current target: %w", err)
+	}
+
+	if currentTarget.ShootName() == "" {
+		return errors.New("no Shoot cluster targeted")
+	}
+
+	ctx, stop := signal.NotifyContext(f.Context(), os.Interrupt)
+	defer stop()
+
+	clientConfig, err := manager.ClientConfig(ctx, currentTarget)
+	if err != nil {
+		return err
+	}
+
+	namespace := o.Namespace
+	if namespace == "" {
+		if namespace, _, err = clientConfig.Namespace(); err != nil {
+			return err
+		}
+	}
+
+	clientset, err := f.Clientset(clientConfig)
+	if err != nil {
+		return err
+	}
+
+	// connected is set once a connection has been lost, errors before are not caused by connection drops,
+	// e.g. the pod does not exist or the local port is already in use
+	connected := false
+
+	for {
+		podName, ports, err := o.resolve(ctx, clientset, namespace)
+		if err == nil {
+			fmt.Fprintf(o.IOStreams.ErrOut, "Forwarding to pod %s/%s\n", namespace, podName)
+
+			err = forwardPorts(ctx, clientConfig, namespace, podName, o.Addresses, ports, o.IOStreams.Out, o.IOStreams.ErrOut)
+
+			// the forwarding opens access to the pod, it is recorded like a change in the audit log
+			manager.Configuration().Audit.Log(audit.Entry{
+				Action:   "port-forward",
+				Resource: fmt.Sprintf("Pod %s/%s", namespace, podName),
+				Args:     ports,
+			}, err)
+
+			if err == nil {
+				connected = true
+				err = errLostConnection
+			}
+		}
+
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if !o.Reconnect || !connected {
+			return err
+		}
+
+		fmt.Fprintf(o.IOStreams.ErrOut, "%v, reconnecting in %s\n", err, o.ReconnectDelay)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(o.ReconnectDelay):
+		}
+	}
+}
+
+// resolve returns the name of the pod to forward to and the ports in the format LOCAL_PORT:POD_PORT.
+// A service is resolved to its first running pod and its ports are mapped to the target ports of the pod.
+func (o *PortForwardOptions) resolve(ctx context.Context, clientset kubernetes.Interface, namespace string) (string, []string, error) {
+	if o.Kind == "pod" {
+		pod, err := clientset.CoreV1().Pods(namespace).Get(ctx, o.Name, metav1.GetOptions{})
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to get pod %s/%s: %w", namespace, o.Name, err)
+		}
+
+		if pod.Status.Phase != corev1.PodRunning {
+			return "", nil, fmt.Errorf("pod %s/%s is not running", namespace, o.Name)
+		}
+
+		return pod.Name, o.Ports, nil
+	}
+
+	service, err := clientset.CoreV1().Services(namespace).Get(ctx, o.Name, metav1.GetOptions{})
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get service %s/%s: %w", namespace, o.Name, err)
+	}
+
+	if len(service.Spec.Selector) == 0 {
+		return "", nil, fmt.Errorf("service %s/%s has no selector", namespace, o.Name)
+	}
+
+	podList, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: labels.SelectorFromSet(service.Spec.Selector).String()})
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to list pods of service %s/%s: %w", namespace, o.Name, err)
+	}
+
+	pods := podList.Items
+	sort.Slice(pods, func(i, j int) bool {
+		return pods[i].Name < pods[j].Name
+	})
+
+	for i := range pods {
+		if pods[i].Status.Phase != corev1.PodRunning || pods[i].DeletionTimestamp != nil {
+			continue
+		}
+
+		ports := make([]string, 0, len(o.Ports))
+
+		for _, port := range o.Ports {
+			local, remote, _ := parsePort(port)
+
+			podPort, err := servicePortToPodPort(service, &pods[i], remote)
+			if err != nil {
+				return "", nil, err
+			}
+
+			ports = append(ports, fmt.Sprintf("%s:%d", local, podPort))
+		}
+
+		return pods[i].Name, ports, nil
+	}
+
+	return "", nil, fmt.Errorf("no running pod of service %s/%s found", namespace, o.Name)
+}
+
+// servicePortToPodPort returns the port of the pod the given service port is routed to
+func servicePortToPodPort(service *corev1.Service, pod *corev1.Pod, port int32) (int32, error) {
+	for _, servicePort := range service.Spec.Ports {
+		if servicePort.Port != port {
+			continue
+		}
+
+		if servicePort.TargetPort.Type == intstr.Int {
+			if servicePort.TargetPort.IntVal == 0 {
+				return port, nil
+			}
+
+			return servicePort.TargetPort.IntVal, nil
+		}
+
+		for _, container := range pod.Spec.Containers {
+			for _, containerPort := range container.Ports {
+				if containerPort.Name == servicePort.TargetPort.StrVal {
+					return containerPort.ContainerPort, nil
+				}
+			}
+		}
+
+		return 0, fmt.Errorf("pod %s has no container port named %s", pod.Name, servicePort.TargetPort.StrVal)
+	}
+
+	return 0, fmt.Errorf("service %s/%s has no port %d", service.Namespace, service.Name, port)
+}
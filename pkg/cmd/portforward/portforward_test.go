@@ -0,0 +1,241 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package portforward_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/gardener/gardenctl-v2/internal/audit"
+	internalfake "github.com/gardener/gardenctl-v2/internal/fake"
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/portforward"
+	"github.com/gardener/gardenctl-v2/pkg/config"
+	"github.com/gardener/gardenctl-v2/pkg/target"
+	targetmocks "github.com/gardener/gardenctl-v2/pkg/target/mocks"
+)
+
+var _ = Describe("Port-Forward Command", func() {
+	type forwarding struct {
+		namespace string
+		pod       string
+		addresses []string
+		ports     []string
+	}
+
+	var (
+		ctrl          *gomock.Controller
+		manager       *targetmocks.MockManager
+		factory       *internalfake.Factory
+		streams       util.IOStreams
+		errOut        *util.SafeBytesBuffer
+		currentTarget target.Target
+		clientConfig  clientcmd.ClientConfig
+		clientset     *fake.Clientset
+		ctx           context.Context
+		cancel        context.CancelFunc
+		forwardings   []forwarding
+		forwardErrs   []error
+		cfg           *config.Config
+	)
+
+	newPod := func(name string, phase corev1.PodPhase) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"app": "web"}},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{
+					Name:  "web",
+					Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: 8080}},
+				}},
+			},
+			Status: corev1.PodStatus{Phase: phase},
+		}
+	}
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		manager = targetmocks.NewMockManager(ctrl)
+		ctx, cancel = context.WithCancel(context.Background())
+		factory = internalfake.NewFakeFactory(nil, nil, nil, nil)
+		factory.ManagerImpl = manager
+		factory.ContextImpl = ctx
+		streams, _, _, errOut = util.NewTestIOStreams()
+		currentTarget = target.NewTarget("garden", "prod1", "", "my-shoot")
+		cfg = &config.Config{}
+		manager.EXPECT().Configuration().Return(cfg).AnyTimes()
+
+		config := clientcmdapi.NewConfig()
+		config.Clusters["shoot"] = &clientcmdapi.Cluster{Server: "https://api.my-shoot.example.com"}
+		config.AuthInfos["shoot"] = &clientcmdapi.AuthInfo{Token: "token"}
+		config.Contexts["shoot"] = &clientcmdapi.Context{Cluster: "shoot", AuthInfo: "shoot"}
+		config.CurrentContext = "shoot"
+		clientConfig = clientcmd.NewDefaultClientConfig(*config, nil)
+
+		clientset = fake.NewSimpleClientset(
+			newPod("web-0", corev1.PodPending),
+			newPod("web-1", corev1.PodRunning),
+			&corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+				Spec: corev1.ServiceSpec{
+					Selector: map[string]string{"app": "web"},
+					Ports: []corev1.ServicePort{
+						{Port: 80, TargetPort: intstr.FromString("http")},
+						{Port: 9090, TargetPort: intstr.FromInt(9091)},
+					},
+				},
+			},
+		)
+
+		factory.ClientsetImpl = func(c clientcmd.ClientConfig) (kubernetes.Interface, error) {
+			Expect(c).To(BeIdenticalTo(clientConfig))
+			return clientset, nil
+		}
+
+		// every call records the forwarding and returns the next error, the context is cancelled after the last error
+		forwardings = nil
+		forwardErrs = nil
+		portforward.SetForwardPorts(func(_ context.Context, _ clientcmd.ClientConfig, namespace, podName string, addresses, ports []string, _, _ io.Writer) error {
+			forwardings = append(forwardings, forwarding{namespace: namespace, pod: podName, addresses: addresses, ports: ports})
+
+			if len(forwardErrs) == 0 {
+				cancel()
+				return nil
+			}
+
+			err := forwardErrs[0]
+			forwardErrs = forwardErrs[1:]
+
+			return err
+		})
+	})
+
+	AfterEach(func() {
+		cancel()
+		ctrl.Finish()
+	})
+
+	Context("when a shoot is targeted", func() {
+		BeforeEach(func() {
+			manager.EXPECT().CurrentTarget().Return(currentTarget, nil)
+			manager.EXPECT().ClientConfig(gomock.Any(), currentTarget).Return(clientConfig, nil)
+		})
+
+		It("should forward the ports to the pod", func() {
+			cmd := portforward.NewCmdPortForward(factory, portforward.NewPortForwardOptions(streams))
+			cmd.SetArgs([]string{"web-1", "8080:80", "9000"})
+			Expect(cmd.Execute()).To(Succeed())
+			Expect(forwardings).To(ConsistOf(forwarding{namespace: "default", pod: "web-1", addresses: []string{"localhost"}, ports: []string{"8080:80", "9000"}}))
+		})
+
+		It("should map the service ports to the ports of a running pod", func() {
+			cmd := portforward.NewCmdPortForward(factory, portforward.NewPortForwardOptions(streams))
+			cmd.SetArgs([]string{"svc/web", "8000:80", ":9090", "--address", "0.0.0.0"})
+			Expect(cmd.Execute()).To(Succeed())
+			Expect(forwardings).To(ConsistOf(forwarding{namespace: "default", pod: "web-1", addresses: []string{"0.0.0.0"}, ports: []string{"8000:8080", ":9091"}}))
+		})
+
+		It("should reconnect if the connection is lost", func() {
+			forwardErrs = []error{nil}
+
+			o := portforward.NewPortForwardOptions(streams)
+			o.ReconnectDelay = 0
+			cmd := portforward.NewCmdPortForward(factory, o)
+			cmd.SetArgs([]string{"service/web", "8000:80"})
+			Expect(cmd.Execute()).To(Succeed())
+			Expect(forwardings).To(HaveLen(2))
+			Expect(errOut.String()).To(ContainSubstring("lost connection to pod, reconnecting in 0s"))
+		})
+
+		It("should exit if the connection is lost and reconnecting is disabled", func() {
+			forwardErrs = []error{nil}
+
+			cmd := portforward.NewCmdPortForward(factory, portforward.NewPortForwardOptions(streams))
+			cmd.SetArgs([]string{"web-1", "8080", "--reconnect=false"})
+			Expect(cmd.Execute()).To(MatchError("lost connection to pod"))
+			Expect(forwardings).To(HaveLen(1))
+		})
+
+		It("should not reconnect if the first connection fails", func() {
+			forwardErrs = []error{errors.New("address already in use")}
+
+			cmd := portforward.NewCmdPortForward(factory, portforward.NewPortForwardOptions(streams))
+			cmd.SetArgs([]string{"web-1", "8080"})
+			Expect(cmd.Execute()).To(MatchError("address already in use"))
+		})
+
+		It("should record the port forwardings in the audit log", func() {
+			dir, err := os.MkdirTemp("", "audit-")
+			Expect(err).NotTo(HaveOccurred())
+
+			defer os.RemoveAll(dir)
+
+			cfg.Audit = &audit.Logger{
+				Filename: filepath.Join(dir, "audit.log"),
+				Command:  "gardenctl port-forward",
+				User:     "jdoe",
+				Now: func() time.Time {
+					return time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+				},
+			}
+			forwardErrs = []error{errors.New("address already in use")}
+
+			cmd := portforward.NewCmdPortForward(factory, portforward.NewPortForwardOptions(streams))
+			cmd.SetArgs([]string{"web-1", "8080:80"})
+			Expect(cmd.Execute()).To(MatchError("address already in use"))
+
+			data, err := os.ReadFile(cfg.Audit.Filename)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(Equal(`{"timestamp":"2022-06-01T12:00:00Z","user":"jdoe","command":"gardenctl port-forward","action":"port-forward","resource":"Pod default/web-1","args":["8080:80"],"result":"failure","error":"address already in use"}` + "\n"))
+		})
+
+		It("should fail if the pod is not running", func() {
+			cmd := portforward.NewCmdPortForward(factory, portforward.NewPortForwardOptions(streams))
+			cmd.SetArgs([]string{"pod/web-0", "8080"})
+			Expect(cmd.Execute()).To(MatchError("pod default/web-0 is not running"))
+			Expect(forwardings).To(BeEmpty())
+		})
+
+		It("should fail if the service has no such port", func() {
+			cmd := portforward.NewCmdPortForward(factory, portforward.NewPortForwardOptions(streams))
+			cmd.SetArgs([]string{"svc/web", "8080:443"})
+			Expect(cmd.Execute()).To(MatchError("service default/web has no port 443"))
+		})
+	})
+
+	It("should fail without a targeted shoot", func() {
+		manager.EXPECT().CurrentTarget().Return(target.NewTarget("garden", "prod1", "", ""), nil)
+
+		cmd := portforward.NewCmdPortForward(factory, portforward.NewPortForwardOptions(streams))
+		cmd.SetArgs([]string{"web-1", "8080"})
+		Expect(cmd.Execute()).To(MatchError("no Shoot cluster targeted"))
+	})
+
+	It("should fail for invalid resources and ports", func() {
+		cmd := portforward.NewCmdPortForward(factory, portforward.NewPortForwardOptions(streams))
+		cmd.SetArgs([]string{"deployment/web", "8080"})
+		Expect(cmd.Execute()).To(MatchError(ContainSubstring(`invalid resource type "deployment", must be one of pod or service`)))
+
+		cmd = portforward.NewCmdPortForward(factory, portforward.NewPortForwardOptions(streams))
+		cmd.SetArgs([]string{"web-1", "8080:http"})
+		Expect(cmd.Execute()).To(MatchError(`invalid remote port in "8080:http"`))
+	})
+})
@@ -0,0 +1,280 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package report
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	corev1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/gardener/gardenctl-v2/internal/gardenclient"
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
+	"github.com/gardener/gardenctl-v2/pkg/target"
+)
+
+// inventoryColumns are the columns of the table and csv output of report inventory
+var inventoryColumns = []string{"GARDEN", "PROJECT", "NAME", "PROVIDER", "REGION", "KUBERNETES VERSION", "HIBERNATED", "CREATED BY"}
+
+// NewCmdInventory returns a new report inventory command.
+func NewCmdInventory(f util.Factory, o *InventoryOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "inventory",
+		Short: "Report a flat inventory of the shoots of the targeted garden or of all configured gardens",
+		Long: `Report one row per shoot with its garden, project, name, provider type, region, Kubernetes version, hibernation state
+and the user who created it. By default the shoots of the targeted garden are reported. With --all-gardens the shoots of
+all configured gardens are reported, the gardens are queried concurrently and a garden that cannot be reached is reported
+as warning.
+
+Use -o csv to export the inventory for spreadsheets, -o json or -o yaml prints the list of shoots with the same fields.`,
+		Example: `# report the shoots of the targeted garden
+gardenctl report inventory
+
+# export the shoots of all configured gardens as csv
+gardenctl report inventory --all-gardens -o csv > inventory.csv`,
+		Args: cobra.NoArgs,
+		RunE: base.WrapRunE(o, f),
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// InventoryOptions is a struct to support report inventory command
+type InventoryOptions struct {
+	base.Options
+
+	// AllGardens reports the shoots of all configured gardens instead of the targeted one
+	AllGardens bool
+}
+
+// NewInventoryOptions returns initialized InventoryOptions
+func NewInventoryOptions(ioStreams util.IOStreams) *InventoryOptions {
+	return &InventoryOptions{
+		Options: base.Options{
+			IOStreams: ioStreams,
+		},
+	}
+}
+
+// InventoryItem is a shoot of the inventory
+type InventoryItem struct {
+	// Garden is the name of the garden of the shoot
+	Garden string `json:"garden" yaml:"garden"`
+	// Project is the name of the project of the shoot
+	Project string `json:"project" yaml:"project"`
+	// Name is the name of the shoot
+	Name string `json:"name" yaml:"name"`
+	// Provider is the provider type of the shoot, e.g. aws
+	Provider string `json:"provider" yaml:"provider"`
+	// Region is the region of the shoot
+	Region string `json:"region" yaml:"region"`
+	// KubernetesVersion is the Kubernetes version of the shoot
+	KubernetesVersion string `json:"kubernetesVersion" yaml:"kubernetesVersion"`
+	// Hibernated is true if the hibernation of the shoot is enabled
+	Hibernated bool `json:"hibernated" yaml:"hibernated"`
+	// CreatedBy is the user who created the shoot, it is empty if unknown
+	CreatedBy string `json:"createdBy,omitempty" yaml:"createdBy,omitempty"`
+}
+
+// AddFlags binds the command options to a given flagset
+func (o *InventoryOptions) AddFlags(flags *pflag.FlagSet) {
+	flags.StringVarP(&o.Output, "output", "o", o.Output, base.OutputFormatsUsage(outputCSV))
+	flags.BoolVar(&o.AllGardens, "all-gardens", o.AllGardens, "Report the shoots of all configured gardens. The gardens are queried concurrently.")
+}
+
+// Validate validates the provided InventoryOptions
+func (o *InventoryOptions) Validate() error {
+	if err := base.ValidateOutput(o.Output, outputCSV); err != nil {
+		return err
+	}
+
+	if o.Output == base.OutputTable {
+		o.Output = ""
+	}
+
+	return nil
+}
+
+// Run executes the command
+func (o *InventoryOptions) Run(f util.Factory) error {
+	manager, err := f.Manager()
+	if err != nil {
+		return err
+	}
+
+	var (
+		items       []InventoryItem
+		description string
+	)
+
+	if o.AllGardens {
+		description = "any garden"
+		items, err = o.inventoryOfAllGardens(f, manager)
+	} else {
+		var currentTarget target.Target
+
+		currentTarget, err = manager.CurrentTarget()
+		if err != nil {
+			return fmt.Errorf("failed to get current target: %w", err)
+		}
+
+		if currentTarget.GardenName() == "" {
+			return target.ErrNoGardenTargeted
+		}
+
+		description = fmt.Sprintf("garden %s", currentTarget.GardenName())
+		items, err = o.inventoryOfGarden(f, manager, currentTarget.GardenName())
+	}
+
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Garden != items[j].Garden {
+			return items[i].Garden < items[j].Garden
+		}
+
+		if items[i].Project != items[j].Project {
+			return items[i].Project < items[j].Project
+		}
+
+		return items[i].Name < items[j].Name
+	})
+
+	if o.Output != "" && o.Output != outputCSV {
+		return o.PrintObject(items)
+	}
+
+	rows := make([][]string, len(items))
+	for i, item := range items {
+		rows[i] = []string{
+			item.Garden,
+			item.Project,
+			item.Name,
+			item.Provider,
+			item.Region,
+			item.KubernetesVersion,
+			fmt.Sprintf("%t", item.Hibernated),
+			item.CreatedBy,
+		}
+	}
+
+	if o.Output == outputCSV {
+		return writeCSV(o.IOStreams.Out, inventoryColumns, rows)
+	}
+
+	if len(rows) == 0 {
+		fmt.Fprintf(o.IOStreams.ErrOut, "No shoots found in %s\n", description)
+		return nil
+	}
+
+	for _, row := range rows {
+		row[len(row)-1] = util.ValueOrNone(row[len(row)-1])
+	}
+
+	if err := printTable(o.IOStreams.Out, inventoryColumns, rows); err != nil {
+		return fmt.Errorf("failed to output inventory table: %w", err)
+	}
+
+	return nil
+}
+
+// inventoryOfGarden returns the inventory of the garden with the given name
+func (o *InventoryOptions) inventoryOfGarden(f util.Factory, manager target.Manager, gardenName string) ([]InventoryItem, error) {
+	gardenClient, err := manager.GardenClient(gardenName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create garden cluster client: %w", err)
+	}
+
+	return newInventory(f.Context(), gardenName, gardenClient)
+}
+
+// inventoryOfAllGardens returns the inventory of all configured gardens. A garden that cannot be
+// queried is reported as warning, it only fails the command if no garden can be queried at all.
+func (o *InventoryOptions) inventoryOfAllGardens(f util.Factory, manager target.Manager) ([]InventoryItem, error) {
+	var (
+		itemsLock sync.Mutex
+		items     []InventoryItem
+	)
+
+	gardenErrs := manager.ForEachGarden(f.Context(), func(ctx context.Context, gardenName string, gardenClient gardenclient.Client) error {
+		gardenItems, err := newInventory(ctx, gardenName, gardenClient)
+		if err != nil {
+			return err
+		}
+
+		itemsLock.Lock()
+		defer itemsLock.Unlock()
+
+		items = append(items, gardenItems...)
+
+		return nil
+	})
+
+	gardens := manager.Configuration().Gardens
+	if len(gardens) > 0 && len(gardenErrs) == len(gardens) {
+		return nil, fmt.Errorf("failed to report the inventory of all gardens: %w", gardenErrs[0])
+	}
+
+	for _, gardenErr := range gardenErrs {
+		fmt.Fprintf(o.IOStreams.ErrOut, "Warning: failed to report the inventory of garden %s: %v\n", gardenErr.Garden, gardenErr.Err)
+	}
+
+	return items, nil
+}
+
+// newInventory lists the shoots of a garden. The project of a shoot is looked up by the namespace of the shoot,
+// the namespace is reported if no project is found for it.
+func newInventory(ctx context.Context, gardenName string, gardenClient gardenclient.Client) ([]InventoryItem, error) {
+	projectList, err := gardenClient.ListProjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	shootList, err := gardenClient.ListShoots(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	projectNames := map[string]string{}
+
+	for _, project := range projectList.Items {
+		if project.Spec.Namespace != nil {
+			projectNames[*project.Spec.Namespace] = project.Name
+		}
+	}
+
+	items := make([]InventoryItem, 0, len(shootList.Items))
+
+	for _, shoot := range shootList.Items {
+		projectName, ok := projectNames[shoot.Namespace]
+		if !ok {
+			projectName = shoot.Namespace
+		}
+
+		items = append(items, InventoryItem{
+			Garden:            gardenName,
+			Project:           projectName,
+			Name:              shoot.Name,
+			Provider:          shoot.Spec.Provider.Type,
+			Region:            shoot.Spec.Region,
+			KubernetesVersion: shoot.Spec.Kubernetes.Version,
+			Hibernated:        util.IsHibernated(shoot),
+			CreatedBy:         shoot.Annotations[corev1beta1constants.GardenCreatedBy],
+		})
+	}
+
+	return items, nil
+}
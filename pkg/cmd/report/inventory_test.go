@@ -0,0 +1,175 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package report_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	corev1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	internalfake "github.com/gardener/gardenctl-v2/internal/fake"
+	"github.com/gardener/gardenctl-v2/internal/util"
+	cmdreport "github.com/gardener/gardenctl-v2/pkg/cmd/report"
+	"github.com/gardener/gardenctl-v2/pkg/config"
+	"github.com/gardener/gardenctl-v2/pkg/target"
+	targetmocks "github.com/gardener/gardenctl-v2/pkg/target/mocks"
+)
+
+var _ = Describe("Report Inventory Command", func() {
+	const (
+		gardenName       = "mygarden"
+		otherGardenName  = "othergarden"
+		brokenGardenName = "brokengarden"
+	)
+
+	var (
+		ctrl           *gomock.Controller
+		cfg            *config.Config
+		clientProvider *targetmocks.MockClientProvider
+		targetProvider *internalfake.TargetProvider
+		factory        *internalfake.Factory
+		out            *util.SafeBytesBuffer
+		errOut         *util.SafeBytesBuffer
+		options        *cmdreport.InventoryOptions
+	)
+
+	newShoot := func(namespace, name, provider, createdBy string, hibernated bool) *gardencorev1beta1.Shoot {
+		shoot := &gardencorev1beta1.Shoot{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec: gardencorev1beta1.ShootSpec{
+				Hibernation: &gardencorev1beta1.Hibernation{Enabled: pointer.Bool(hibernated)},
+				Kubernetes:  gardencorev1beta1.Kubernetes{Version: "1.22.2"},
+				Provider:    gardencorev1beta1.Provider{Type: provider},
+				Region:      "eu-west-1",
+			},
+		}
+
+		if createdBy != "" {
+			shoot.Annotations = map[string]string{corev1beta1constants.GardenCreatedBy: createdBy}
+		}
+
+		return shoot
+	}
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+
+		cfg = &config.Config{
+			LinkKubeconfig: pointer.Bool(false),
+			Gardens: []config.Garden{
+				{Name: gardenName, Kubeconfig: "/not/a/real/kubeconfig"},
+				{Name: otherGardenName, Kubeconfig: "/not/a/real/other/kubeconfig"},
+				{Name: brokenGardenName, Kubeconfig: "/not/a/real/broken/kubeconfig"},
+			},
+		}
+
+		gardenClient := internalfake.NewClientWithObjects(
+			&gardencorev1beta1.Project{
+				ObjectMeta: metav1.ObjectMeta{Name: "prod1"},
+				Spec:       gardencorev1beta1.ProjectSpec{Namespace: pointer.String("garden-prod1")},
+			},
+			newShoot("garden-prod1", "web", "aws", "alice@example.com", false),
+			newShoot("garden-prod1", "batch", "aws", "", true),
+		)
+		otherGardenClient := internalfake.NewClientWithObjects(
+			&gardencorev1beta1.Project{
+				ObjectMeta: metav1.ObjectMeta{Name: "dev"},
+				Spec:       gardencorev1beta1.ProjectSpec{Namespace: pointer.String("garden-dev")},
+			},
+			newShoot("garden-dev", "test", "gcp", "bob@example.com", false),
+		)
+
+		clientProvider = targetmocks.NewMockClientProvider(ctrl)
+
+		for name, c := range map[string]client.Client{gardenName: gardenClient, otherGardenName: otherGardenClient} {
+			clientConfig, err := cfg.ClientConfig(name)
+			Expect(err).ToNot(HaveOccurred())
+			clientProvider.EXPECT().FromClientConfig(gomock.Eq(clientConfig)).Return(c, nil).AnyTimes()
+		}
+
+		brokenClientConfig, err := cfg.ClientConfig(brokenGardenName)
+		Expect(err).ToNot(HaveOccurred())
+		clientProvider.EXPECT().FromClientConfig(gomock.Eq(brokenClientConfig)).Return(nil, errors.New("connection refused")).AnyTimes()
+
+		targetProvider = internalfake.NewFakeTargetProvider(target.NewTarget(gardenName, "", "", ""))
+		factory = internalfake.NewFakeFactory(cfg, nil, clientProvider, targetProvider)
+		factory.ContextImpl = context.Background()
+
+		var streams util.IOStreams
+		streams, _, out, errOut = util.NewTestIOStreams()
+		options = cmdreport.NewInventoryOptions(streams)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	run := func() {
+		cmd := cmdreport.NewCmdInventory(factory, options)
+		ExpectWithOffset(1, cmd.RunE(cmd, nil)).To(Succeed())
+	}
+
+	It("should report the shoots of the targeted garden", func() {
+		run()
+		Expect(out.String()).To(Equal(`GARDEN     PROJECT   NAME    PROVIDER   REGION      KUBERNETES VERSION   HIBERNATED   CREATED BY
+mygarden   prod1     batch   aws        eu-west-1   1.22.2               true         <none>
+mygarden   prod1     web     aws        eu-west-1   1.22.2               false        alice@example.com
+`))
+		Expect(errOut.String()).To(BeEmpty())
+	})
+
+	It("should export the shoots of all gardens as csv and warn about the gardens that cannot be queried", func() {
+		options.AllGardens = true
+		options.Output = "csv"
+		run()
+		Expect(out.String()).To(Equal(`garden,project,name,provider,region,kubernetes_version,hibernated,created_by
+mygarden,prod1,batch,aws,eu-west-1,1.22.2,true,
+mygarden,prod1,web,aws,eu-west-1,1.22.2,false,alice@example.com
+othergarden,dev,test,gcp,eu-west-1,1.22.2,false,bob@example.com
+`))
+		Expect(errOut.String()).To(Equal("Warning: failed to report the inventory of garden brokengarden: failed to create garden cluster client: connection refused\n"))
+	})
+
+	It("should print the shoots of all gardens as json", func() {
+		options.AllGardens = true
+		options.Output = "json"
+		run()
+
+		var items []cmdreport.InventoryItem
+		Expect(json.Unmarshal([]byte(out.String()), &items)).To(Succeed())
+		Expect(items).To(HaveLen(3))
+		Expect(items[2]).To(Equal(cmdreport.InventoryItem{
+			Garden:            otherGardenName,
+			Project:           "dev",
+			Name:              "test",
+			Provider:          "gcp",
+			Region:            "eu-west-1",
+			KubernetesVersion: "1.22.2",
+			CreatedBy:         "bob@example.com",
+		}))
+	})
+
+	It("should fail if no garden is targeted", func() {
+		targetProvider.Target = target.NewTarget("", "", "", "")
+		cmd := cmdreport.NewCmdInventory(factory, options)
+		Expect(cmd.RunE(cmd, nil)).To(MatchError(target.ErrNoGardenTargeted))
+	})
+
+	It("should reject an invalid output format", func() {
+		options.Output = "wide"
+		Expect(options.Validate()).To(MatchError(HavePrefix("--output must be one of")))
+	})
+})
@@ -0,0 +1,590 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package report
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/gardener/gardenctl-v2/internal/gardenclient"
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
+	"github.com/gardener/gardenctl-v2/pkg/target"
+)
+
+const (
+	outputCSV = "csv"
+
+	byProject = "project"
+	byQuota   = "quota"
+
+	// storagePrefix is the prefix of the quota metrics that limit the volume size of a volume class, e.g. storage.standard
+	storagePrefix = "storage."
+)
+
+// NewCmdQuota returns a new report quota command.
+func NewCmdQuota(f util.Factory, o *QuotaOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "quota",
+		Short: "Report the resource usage of projects against their quotas",
+		Long: `Report the number of shoots, worker pools and nodes and the machine capacity requested by the shoots of the
+projects of the targeted garden, together with the usage of the quotas that are referenced by their secret bindings.
+Like Gardener does when it enforces the quotas, the requested capacity is calculated from the maximum number of nodes
+of each worker pool and the CPU, GPU, memory and volume size of its machine type according to the cloud profile.
+If a project is targeted, only this project is reported unless --all-projects is set.
+
+With --by quota, one row per quota and resource is printed instead of one row per project. Use -o csv to export the
+rows for capacity planning, -o json or -o yaml prints the complete report.`,
+		Example: `# report the resource usage of all projects of the targeted garden
+gardenctl report quota --all-projects
+
+# export the usage of all quotas of the targeted garden as csv
+gardenctl report quota -A --by quota -o csv > quotas.csv`,
+		Args: cobra.NoArgs,
+		RunE: base.WrapRunE(o, f),
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// QuotaOptions is a struct to support report quota command
+type QuotaOptions struct {
+	base.Options
+
+	// AllProjects reports all projects of the targeted garden, even if a project is targeted
+	AllProjects bool
+	// By is either project or quota and determines the rows of the table and csv output
+	By string
+}
+
+// NewQuotaOptions returns initialized QuotaOptions
+func NewQuotaOptions(ioStreams util.IOStreams) *QuotaOptions {
+	return &QuotaOptions{
+		Options: base.Options{
+			IOStreams: ioStreams,
+		},
+		By: byProject,
+	}
+}
+
+// QuotaReport is the machine-readable output of report quota
+type QuotaReport struct {
+	// Garden is the name of the garden
+	Garden string `json:"garden" yaml:"garden"`
+	// Projects is the resource usage of the reported projects
+	Projects []ProjectUsage `json:"projects" yaml:"projects"`
+	// Quotas is the usage of the quotas that are referenced by the secret bindings of the reported projects
+	Quotas []QuotaUsage `json:"quotas" yaml:"quotas"`
+}
+
+// ProjectUsage is the resource usage of a project
+type ProjectUsage struct {
+	// Name is the name of the project
+	Name string `json:"name" yaml:"name"`
+	// Namespace is the namespace of the project
+	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	// Shoots is the number of shoots of the project
+	Shoots int `json:"shoots" yaml:"shoots"`
+	// WorkerPools is the number of worker pools of all shoots of the project
+	WorkerPools int `json:"workerPools" yaml:"workerPools"`
+	// Nodes is the maximum number of nodes of all shoots of the project
+	Nodes int64 `json:"nodes" yaml:"nodes"`
+	// Resources is the machine capacity requested by all shoots of the project, e.g. cpu, gpu, memory and storage.standard
+	Resources map[string]string `json:"resources" yaml:"resources"`
+	// Quotas are the quotas referenced by the secret bindings of the project in the format namespace/name
+	Quotas []string `json:"quotas" yaml:"quotas"`
+}
+
+// QuotaUsage is the usage of a quota
+type QuotaUsage struct {
+	// Name is the name of the quota
+	Name string `json:"name" yaml:"name"`
+	// Namespace is the namespace of the quota
+	Namespace string `json:"namespace" yaml:"namespace"`
+	// Projects are the projects with secret bindings that reference the quota
+	Projects []string `json:"projects" yaml:"projects"`
+	// Resources are the limited resources of the quota
+	Resources []ResourceUsage `json:"resources" yaml:"resources"`
+}
+
+// ResourceUsage is the usage of a resource that is limited by a quota
+type ResourceUsage struct {
+	// Name is the name of the resource, e.g. cpu
+	Name string `json:"name" yaml:"name"`
+	// Limit is the limit of the quota
+	Limit string `json:"limit" yaml:"limit"`
+	// Used is the amount requested by all shoots that use the quota, it is not set if it cannot be calculated, e.g. for load balancers
+	Used string `json:"used,omitempty" yaml:"used,omitempty"`
+	// Percent is the used amount in percent of the limit
+	Percent *int64 `json:"percent,omitempty" yaml:"percent,omitempty"`
+}
+
+// AddFlags binds the command options to a given flagset
+func (o *QuotaOptions) AddFlags(flags *pflag.FlagSet) {
+	flags.StringVarP(&o.Output, "output", "o", o.Output, base.OutputFormatsUsage(outputCSV))
+	flags.BoolVarP(&o.AllProjects, "all-projects", "A", o.AllProjects, "Report all projects of the targeted garden, even if a project is targeted.")
+	flags.StringVar(&o.By, "by", o.By, "Print one row per project or one row per quota and resource, one of 'project' or 'quota'.")
+}
+
+// Validate validates the provided QuotaOptions
+func (o *QuotaOptions) Validate() error {
+	if err := base.ValidateOutput(o.Output, outputCSV); err != nil {
+		return err
+	}
+
+	if o.Output == base.OutputTable {
+		o.Output = ""
+	}
+
+	if o.By != byProject && o.By != byQuota {
+		return fmt.Errorf("--by must be one of '%s' or '%s'", byProject, byQuota)
+	}
+
+	return nil
+}
+
+// Run executes the command
+func (o *QuotaOptions) Run(f util.Factory) error {
+	manager, err := f.Manager()
+	if err != nil {
+		return err
+	}
+
+	currentTarget, err := manager.CurrentTarget()
+	if err != nil {
+		return fmt.Errorf("failed to get current target: %w", err)
+	}
+
+	if currentTarget.GardenName() == "" {
+		return target.ErrNoGardenTargeted
+	}
+
+	gardenClient, err := manager.GardenClient(currentTarget.GardenName())
+	if err != nil {
+		return fmt.Errorf("failed to create garden cluster client: %w", err)
+	}
+
+	projectName := currentTarget.ProjectName()
+	if o.AllProjects {
+		projectName = ""
+	}
+
+	report, err := newQuotaReport(f.Context(), o.IOStreams.ErrOut, gardenClient, projectName)
+	if err != nil {
+		return err
+	}
+
+	report.Garden = currentTarget.GardenName()
+
+	if o.Output != "" && o.Output != outputCSV {
+		return o.PrintObject(report)
+	}
+
+	var (
+		columns []string
+		rows    [][]string
+	)
+
+	if o.By == byQuota {
+		columns, rows = quotaRows(report)
+	} else {
+		columns, rows = projectRows(report)
+	}
+
+	if o.Output == outputCSV {
+		return writeCSV(o.IOStreams.Out, columns, rows)
+	}
+
+	if len(rows) == 0 {
+		if o.By == byQuota {
+			fmt.Fprintf(o.IOStreams.ErrOut, "No quotas found in garden %s\n", report.Garden)
+		} else {
+			fmt.Fprintf(o.IOStreams.ErrOut, "No projects found in garden %s\n", report.Garden)
+		}
+
+		return nil
+	}
+
+	if err := printTable(o.IOStreams.Out, columns, rows); err != nil {
+		return fmt.Errorf("failed to output quota table: %w", err)
+	}
+
+	return nil
+}
+
+// newQuotaReport collects the resource usage of the project with the given name, or of all projects if the name is empty,
+// and the usage of the quotas that are referenced by their secret bindings. Warnings are written to errOut.
+func newQuotaReport(ctx context.Context, errOut io.Writer, gardenClient gardenclient.Client, projectName string) (*QuotaReport, error) {
+	projectList, err := gardenClient.ListProjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// the usage of a quota depends on all shoots that reference it, also on those of other projects
+	shootList, err := gardenClient.ListShoots(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	secretBindingList, err := gardenClient.ListSecretBindings(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	cloudProfileList, err := gardenClient.ListCloudProfiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cloudProfiles := map[string]*gardencorev1beta1.CloudProfile{}
+	for i := range cloudProfileList.Items {
+		cloudProfiles[cloudProfileList.Items[i].Name] = &cloudProfileList.Items[i]
+	}
+
+	projectNames := map[string]string{}
+	usages := map[string]*ProjectUsage{}
+	requested := map[string]corev1.ResourceList{}
+
+	for _, project := range projectList.Items {
+		if project.Spec.Namespace == nil {
+			continue
+		}
+
+		namespace := *project.Spec.Namespace
+		projectNames[namespace] = project.Name
+
+		if projectName == "" || project.Name == projectName {
+			usages[namespace] = &ProjectUsage{Name: project.Name, Namespace: namespace, Quotas: []string{}}
+			requested[namespace] = corev1.ResourceList{}
+		}
+	}
+
+	if projectName != "" && len(usages) == 0 {
+		return nil, fmt.Errorf("project %q not found", projectName)
+	}
+
+	bindingQuotas := map[types.NamespacedName][]types.NamespacedName{}
+	quotaProjects := map[types.NamespacedName]map[string]bool{}
+	reportedQuotas := map[types.NamespacedName]bool{}
+
+	for _, secretBinding := range secretBindingList.Items {
+		for _, ref := range secretBinding.Quotas {
+			key := types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}
+			if key.Namespace == "" {
+				key.Namespace = secretBinding.Namespace
+			}
+
+			bindingKey := types.NamespacedName{Namespace: secretBinding.Namespace, Name: secretBinding.Name}
+			bindingQuotas[bindingKey] = append(bindingQuotas[bindingKey], key)
+
+			if quotaProjects[key] == nil {
+				quotaProjects[key] = map[string]bool{}
+			}
+
+			if name, ok := projectNames[secretBinding.Namespace]; ok {
+				quotaProjects[key][name] = true
+			}
+
+			if usage, ok := usages[secretBinding.Namespace]; ok {
+				reportedQuotas[key] = true
+
+				if !util.ContainsString(usage.Quotas, key.String()) {
+					usage.Quotas = append(usage.Quotas, key.String())
+				}
+			}
+		}
+	}
+
+	quotaUsed := map[types.NamespacedName]corev1.ResourceList{}
+
+	for i := range shootList.Items {
+		shoot := &shootList.Items[i]
+		usage := usages[shoot.Namespace]
+
+		var quotas []types.NamespacedName
+
+		for _, key := range bindingQuotas[types.NamespacedName{Namespace: shoot.Namespace, Name: shoot.Spec.SecretBindingName}] {
+			if reportedQuotas[key] {
+				quotas = append(quotas, key)
+			}
+		}
+
+		if usage == nil && len(quotas) == 0 {
+			continue
+		}
+
+		resources := requestedResources(errOut, shoot, cloudProfiles[shoot.Spec.CloudProfileName])
+
+		if usage != nil {
+			usage.Shoots++
+			usage.WorkerPools += len(shoot.Spec.Provider.Workers)
+
+			for _, worker := range shoot.Spec.Provider.Workers {
+				usage.Nodes += int64(worker.Maximum)
+			}
+
+			addResources(requested[shoot.Namespace], resources)
+		}
+
+		for _, key := range quotas {
+			if quotaUsed[key] == nil {
+				quotaUsed[key] = corev1.ResourceList{}
+			}
+
+			addResources(quotaUsed[key], resources)
+		}
+	}
+
+	report := &QuotaReport{Projects: []ProjectUsage{}, Quotas: []QuotaUsage{}}
+
+	for namespace, usage := range usages {
+		usage.Resources = map[string]string{}
+		for name, quantity := range requested[namespace] {
+			usage.Resources[string(name)] = quantity.String()
+		}
+
+		sort.Strings(usage.Quotas)
+		report.Projects = append(report.Projects, *usage)
+	}
+
+	sort.Slice(report.Projects, func(i, j int) bool {
+		return report.Projects[i].Name < report.Projects[j].Name
+	})
+
+	for key := range reportedQuotas {
+		quota := &gardencorev1beta1.Quota{}
+		if err := gardenClient.RuntimeClient().Get(ctx, key, quota); err != nil {
+			return nil, fmt.Errorf("failed to get quota %v: %w", key, err)
+		}
+
+		report.Quotas = append(report.Quotas, quotaUsage(quota, quotaProjects[key], quotaUsed[key]))
+	}
+
+	sort.Slice(report.Quotas, func(i, j int) bool {
+		if report.Quotas[i].Namespace != report.Quotas[j].Namespace {
+			return report.Quotas[i].Namespace < report.Quotas[j].Namespace
+		}
+
+		return report.Quotas[i].Name < report.Quotas[j].Name
+	})
+
+	return report, nil
+}
+
+// requestedResources returns the cpu, gpu, memory and storage requested by the maximum number of nodes of the worker
+// pools of the shoot. Worker pools whose machine type or volume type is not found in the cloud profile are skipped.
+func requestedResources(errOut io.Writer, shoot *gardencorev1beta1.Shoot, cloudProfile *gardencorev1beta1.CloudProfile) corev1.ResourceList {
+	resources := corev1.ResourceList{}
+
+	if cloudProfile == nil {
+		fmt.Fprintf(errOut, "Warning: cloud profile %s of shoot %s/%s not found, its capacity is not included\n", shoot.Spec.CloudProfileName, shoot.Namespace, shoot.Name)
+		return resources
+	}
+
+	for _, worker := range shoot.Spec.Provider.Workers {
+		machineType := findMachineType(cloudProfile, worker.Machine.Type)
+		if machineType == nil {
+			fmt.Fprintf(errOut, "Warning: machine type %q of shoot %s/%s is not offered by cloud profile %s, its capacity is not included\n", worker.Machine.Type, shoot.Namespace, shoot.Name, cloudProfile.Name)
+			continue
+		}
+
+		nodes := int64(worker.Maximum)
+
+		addResources(resources, corev1.ResourceList{
+			corev1.ResourceCPU:    multiply(machineType.CPU, nodes),
+			"gpu":                 multiply(machineType.GPU, nodes),
+			corev1.ResourceMemory: multiply(machineType.Memory, nodes),
+		})
+
+		switch {
+		case worker.Volume != nil:
+			size, err := resource.ParseQuantity(worker.Volume.VolumeSize)
+			if err != nil {
+				fmt.Fprintf(errOut, "Warning: invalid volume size %q of shoot %s/%s, its storage is not included\n", worker.Volume.VolumeSize, shoot.Namespace, shoot.Name)
+				continue
+			}
+
+			class := ""
+
+			for _, volumeType := range cloudProfile.Spec.VolumeTypes {
+				if worker.Volume.Type != nil && volumeType.Name == *worker.Volume.Type {
+					class = volumeType.Class
+				}
+			}
+
+			if class == "" {
+				fmt.Fprintf(errOut, "Warning: volume type of worker pool %q of shoot %s/%s is not offered by cloud profile %s, its storage is not included\n", worker.Name, shoot.Namespace, shoot.Name, cloudProfile.Name)
+				continue
+			}
+
+			addResources(resources, corev1.ResourceList{corev1.ResourceName(storagePrefix + class): multiply(size, nodes)})
+		case machineType.Storage != nil && machineType.Storage.StorageSize != nil:
+			addResources(resources, corev1.ResourceList{
+				corev1.ResourceName(storagePrefix + machineType.Storage.Class): multiply(*machineType.Storage.StorageSize, nodes),
+			})
+		}
+	}
+
+	return resources
+}
+
+// quotaUsage compares the limits of the quota with the resources used by the shoots that reference it
+func quotaUsage(quota *gardencorev1beta1.Quota, projects map[string]bool, used corev1.ResourceList) QuotaUsage {
+	usage := QuotaUsage{
+		Name:      quota.Name,
+		Namespace: quota.Namespace,
+		Projects:  []string{},
+		Resources: []ResourceUsage{},
+	}
+
+	for name := range projects {
+		usage.Projects = append(usage.Projects, name)
+	}
+
+	sort.Strings(usage.Projects)
+
+	for name, limit := range quota.Spec.Metrics {
+		resourceUsage := ResourceUsage{
+			Name:  string(name),
+			Limit: limit.String(),
+		}
+
+		if isCalculated(name) {
+			quantity := used[name]
+			resourceUsage.Used = quantity.String()
+
+			if limit.Sign() > 0 {
+				percent := int64(math.Round(float64(quantity.MilliValue()) / float64(limit.MilliValue()) * 100))
+				resourceUsage.Percent = &percent
+			}
+		}
+
+		usage.Resources = append(usage.Resources, resourceUsage)
+	}
+
+	sort.Slice(usage.Resources, func(i, j int) bool {
+		return usage.Resources[i].Name < usage.Resources[j].Name
+	})
+
+	return usage
+}
+
+// isCalculated returns true if the usage of the quota metric can be calculated from the worker pools of the shoots
+func isCalculated(name corev1.ResourceName) bool {
+	return name == corev1.ResourceCPU || name == "gpu" || name == corev1.ResourceMemory || strings.HasPrefix(string(name), storagePrefix)
+}
+
+// projectRows returns the columns and rows of the report with one row per project
+func projectRows(report *QuotaReport) ([]string, [][]string) {
+	columns := []string{"Project", "Shoots", "Worker Pools", "Nodes", "CPU", "GPU", "Memory", "Storage", "Quotas"}
+	rows := make([][]string, len(report.Projects))
+
+	for i, project := range report.Projects {
+		storage := []string{}
+
+		for name, value := range project.Resources {
+			if strings.HasPrefix(name, storagePrefix) {
+				storage = append(storage, fmt.Sprintf("%s=%s", strings.TrimPrefix(name, storagePrefix), value))
+			}
+		}
+
+		sort.Strings(storage)
+
+		rows[i] = []string{
+			project.Name,
+			fmt.Sprintf("%d", project.Shoots),
+			fmt.Sprintf("%d", project.WorkerPools),
+			fmt.Sprintf("%d", project.Nodes),
+			valueOrZero(project.Resources[string(corev1.ResourceCPU)]),
+			valueOrZero(project.Resources["gpu"]),
+			valueOrZero(project.Resources[string(corev1.ResourceMemory)]),
+			util.ValueOrNone(strings.Join(storage, ",")),
+			util.ValueOrNone(strings.Join(project.Quotas, ",")),
+		}
+	}
+
+	return columns, rows
+}
+
+// quotaRows returns the columns and rows of the report with one row per quota and resource
+func quotaRows(report *QuotaReport) ([]string, [][]string) {
+	columns := []string{"Quota", "Projects", "Resource", "Limit", "Used", "Usage"}
+	rows := [][]string{}
+
+	for _, quota := range report.Quotas {
+		for _, r := range quota.Resources {
+			usage := "<unknown>"
+			if r.Percent != nil {
+				usage = fmt.Sprintf("%d%%", *r.Percent)
+			}
+
+			used := r.Used
+			if used == "" {
+				used = "<unknown>"
+			}
+
+			rows = append(rows, []string{
+				quota.Namespace + "/" + quota.Name,
+				util.ValueOrNone(strings.Join(quota.Projects, ",")),
+				r.Name,
+				r.Limit,
+				used,
+				usage,
+			})
+		}
+	}
+
+	return columns, rows
+}
+
+func addResources(resources corev1.ResourceList, add corev1.ResourceList) {
+	for name, quantity := range add {
+		sum := resources[name]
+		sum.Add(quantity)
+		resources[name] = sum
+	}
+}
+
+func multiply(quantity resource.Quantity, n int64) resource.Quantity {
+	format := quantity.Format
+	if format == "" {
+		format = resource.DecimalSI
+	}
+
+	return *resource.NewMilliQuantity(quantity.MilliValue()*n, format)
+}
+
+func findMachineType(cloudProfile *gardencorev1beta1.CloudProfile, name string) *gardencorev1beta1.MachineType {
+	for i := range cloudProfile.Spec.MachineTypes {
+		if cloudProfile.Spec.MachineTypes[i].Name == name {
+			return &cloudProfile.Spec.MachineTypes[i]
+		}
+	}
+
+	return nil
+}
+
+func valueOrZero(value string) string {
+	if value == "" {
+		return "0"
+	}
+
+	return value
+}
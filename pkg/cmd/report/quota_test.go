@@ -0,0 +1,225 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package report_test
+
+import (
+	"context"
+	"encoding/json"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	internalfake "github.com/gardener/gardenctl-v2/internal/fake"
+	"github.com/gardener/gardenctl-v2/internal/util"
+	cmdreport "github.com/gardener/gardenctl-v2/pkg/cmd/report"
+	"github.com/gardener/gardenctl-v2/pkg/config"
+	"github.com/gardener/gardenctl-v2/pkg/target"
+	targetmocks "github.com/gardener/gardenctl-v2/pkg/target/mocks"
+)
+
+var _ = Describe("Report Quota Command", func() {
+	const gardenName = "mygarden"
+
+	var (
+		ctrl           *gomock.Controller
+		cfg            *config.Config
+		clientProvider *targetmocks.MockClientProvider
+		targetProvider *internalfake.TargetProvider
+		factory        *internalfake.Factory
+		streams        util.IOStreams
+		out            *util.SafeBytesBuffer
+		errOut         *util.SafeBytesBuffer
+		options        *cmdreport.QuotaOptions
+		objects        []client.Object
+	)
+
+	newProject := func(name string) *gardencorev1beta1.Project {
+		return &gardencorev1beta1.Project{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       gardencorev1beta1.ProjectSpec{Namespace: pointer.String("garden-" + name)},
+		}
+	}
+
+	newSecretBinding := func(namespace string, quotas ...corev1.ObjectReference) *gardencorev1beta1.SecretBinding {
+		return &gardencorev1beta1.SecretBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "aws", Namespace: namespace},
+			Quotas:     quotas,
+		}
+	}
+
+	newShoot := func(namespace, name string, workers ...gardencorev1beta1.Worker) *gardencorev1beta1.Shoot {
+		return &gardencorev1beta1.Shoot{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec: gardencorev1beta1.ShootSpec{
+				CloudProfileName:  "aws",
+				SecretBindingName: "aws",
+				Provider:          gardencorev1beta1.Provider{Workers: workers},
+			},
+		}
+	}
+
+	newWorker := func(machineType string, maximum int32, volume *gardencorev1beta1.Volume) gardencorev1beta1.Worker {
+		return gardencorev1beta1.Worker{
+			Name:    "worker",
+			Machine: gardencorev1beta1.Machine{Type: machineType},
+			Maximum: maximum,
+			Volume:  volume,
+		}
+	}
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+
+		cfg = &config.Config{
+			LinkKubeconfig: pointer.Bool(false),
+			Gardens: []config.Garden{{
+				Name:       gardenName,
+				Kubeconfig: "/not/a/real/kubeconfig",
+			}},
+		}
+
+		cloudProfile := &gardencorev1beta1.CloudProfile{
+			ObjectMeta: metav1.ObjectMeta{Name: "aws"},
+			Spec: gardencorev1beta1.CloudProfileSpec{
+				MachineTypes: []gardencorev1beta1.MachineType{
+					{Name: "m5.large", CPU: resource.MustParse("2"), GPU: resource.MustParse("0"), Memory: resource.MustParse("8Gi")},
+					{Name: "p3.2xlarge", CPU: resource.MustParse("8"), GPU: resource.MustParse("1"), Memory: resource.MustParse("61Gi")},
+				},
+				VolumeTypes: []gardencorev1beta1.VolumeType{{Name: "gp2", Class: "standard"}},
+			},
+		}
+
+		prodQuota := &gardencorev1beta1.Quota{
+			ObjectMeta: metav1.ObjectMeta{Name: "prod-quota", Namespace: "garden-prod1"},
+			Spec: gardencorev1beta1.QuotaSpec{
+				Metrics: corev1.ResourceList{
+					"cpu":              resource.MustParse("20"),
+					"memory":           resource.MustParse("100Gi"),
+					"storage.standard": resource.MustParse("1Ti"),
+					"loadbalancer":     resource.MustParse("5"),
+				},
+			},
+		}
+
+		trialQuota := &gardencorev1beta1.Quota{
+			ObjectMeta: metav1.ObjectMeta{Name: "trial", Namespace: "garden"},
+			Spec: gardencorev1beta1.QuotaSpec{
+				ClusterLifetimeDays: pointer.Int32(7),
+				Metrics:             corev1.ResourceList{"cpu": resource.MustParse("16")},
+			},
+		}
+
+		objects = []client.Object{
+			cloudProfile,
+			prodQuota,
+			trialQuota,
+			newProject("prod1"),
+			newProject("dev"),
+			newProject("other"),
+			newSecretBinding("garden-prod1", corev1.ObjectReference{Name: "prod-quota"}),
+			newSecretBinding("garden-dev", corev1.ObjectReference{Name: "trial", Namespace: "garden"}),
+			newSecretBinding("garden-other", corev1.ObjectReference{Name: "trial", Namespace: "garden"}),
+			newShoot("garden-prod1", "web", newWorker("m5.large", 3, &gardencorev1beta1.Volume{Type: pointer.String("gp2"), VolumeSize: "50Gi"})),
+			newShoot("garden-dev", "ml", newWorker("p3.2xlarge", 1, nil)),
+			newShoot("garden-other", "db", newWorker("m5.large", 2, nil)),
+		}
+
+		clientProvider = targetmocks.NewMockClientProvider(ctrl)
+		clientConfig, err := cfg.ClientConfig(gardenName)
+		Expect(err).ToNot(HaveOccurred())
+		clientProvider.EXPECT().FromClientConfig(gomock.Eq(clientConfig)).DoAndReturn(func(interface{}) (client.Client, error) {
+			return internalfake.NewClientWithObjects(objects...), nil
+		}).AnyTimes()
+
+		targetProvider = internalfake.NewFakeTargetProvider(target.NewTarget(gardenName, "prod1", "", ""))
+		factory = internalfake.NewFakeFactory(cfg, nil, clientProvider, targetProvider)
+		factory.ContextImpl = context.Background()
+
+		streams, _, out, errOut = util.NewTestIOStreams()
+		options = cmdreport.NewQuotaOptions(streams)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	run := func() {
+		cmd := cmdreport.NewCmdQuota(factory, options)
+		ExpectWithOffset(1, cmd.RunE(cmd, nil)).To(Succeed())
+	}
+
+	It("should report the usage of the targeted project", func() {
+		run()
+		Expect(out.String()).To(Equal(`PROJECT   SHOOTS   WORKER POOLS   NODES   CPU   GPU   MEMORY   STORAGE          QUOTAS
+prod1     1        1              3       6     0     24Gi     standard=150Gi   garden-prod1/prod-quota
+`))
+	})
+
+	It("should report the usage of all projects", func() {
+		options.AllProjects = true
+		run()
+		Expect(out.String()).To(Equal(`PROJECT   SHOOTS   WORKER POOLS   NODES   CPU   GPU   MEMORY   STORAGE          QUOTAS
+dev       1        1              1       8     1     61Gi     <none>           garden/trial
+other     1        1              2       4     0     16Gi     <none>           garden/trial
+prod1     1        1              3       6     0     24Gi     standard=150Gi   garden-prod1/prod-quota
+`))
+	})
+
+	It("should report the usage of the quotas as csv", func() {
+		options.AllProjects = true
+		options.By = "quota"
+		options.Output = "csv"
+		run()
+		Expect(out.String()).To(Equal(`quota,projects,resource,limit,used,usage
+garden/trial,"dev,other",cpu,16,12,75%
+garden-prod1/prod-quota,prod1,cpu,20,6,30%
+garden-prod1/prod-quota,prod1,loadbalancer,5,<unknown>,<unknown>
+garden-prod1/prod-quota,prod1,memory,100Gi,24Gi,24%
+garden-prod1/prod-quota,prod1,storage.standard,1Ti,150Gi,15%
+`))
+	})
+
+	It("should include the usage of shoots of other projects that share the quota", func() {
+		targetProvider.Target = target.NewTarget(gardenName, "dev", "", "")
+		options.Output = "json"
+		run()
+
+		report := &cmdreport.QuotaReport{}
+		Expect(json.Unmarshal([]byte(out.String()), report)).To(Succeed())
+		Expect(report.Garden).To(Equal(gardenName))
+		Expect(report.Projects).To(HaveLen(1))
+		Expect(report.Projects[0].Resources).To(Equal(map[string]string{"cpu": "8", "gpu": "1", "memory": "61Gi"}))
+		Expect(report.Quotas).To(HaveLen(1))
+		Expect(report.Quotas[0].Projects).To(Equal([]string{"dev", "other"}))
+		Expect(report.Quotas[0].Resources).To(ConsistOf(cmdreport.ResourceUsage{Name: "cpu", Limit: "16", Used: "12", Percent: pointer.Int64(75)}))
+	})
+
+	It("should warn about machine types that are not in the cloud profile", func() {
+		objects = append(objects, newShoot("garden-prod1", "legacy", newWorker("m4.large", 1, nil)))
+		run()
+		Expect(errOut.String()).To(Equal("Warning: machine type \"m4.large\" of shoot garden-prod1/legacy is not offered by cloud profile aws, its capacity is not included\n"))
+		Expect(out.String()).To(ContainSubstring("prod1     2        2              4       6"))
+	})
+
+	It("should fail if the targeted project does not exist", func() {
+		targetProvider.Target = target.NewTarget(gardenName, "foo", "", "")
+		cmd := cmdreport.NewCmdQuota(factory, options)
+		Expect(cmd.RunE(cmd, nil)).To(MatchError(`project "foo" not found`))
+	})
+
+	It("should reject an invalid --by value", func() {
+		options.By = "shoot"
+		Expect(options.Validate()).To(MatchError("--by must be one of 'project' or 'quota'"))
+	})
+})
@@ -0,0 +1,79 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package report
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1beta1 "k8s.io/apimachinery/pkg/apis/meta/v1beta1"
+	"k8s.io/cli-runtime/pkg/printers"
+
+	"github.com/gardener/gardenctl-v2/internal/util"
+)
+
+// NewCmdReport returns a new report command.
+func NewCmdReport(f util.Factory, ioStreams util.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "Generate reports about the resources of the targeted garden",
+	}
+
+	cmd.AddCommand(NewCmdQuota(f, NewQuotaOptions(ioStreams)))
+	cmd.AddCommand(NewCmdInventory(f, NewInventoryOptions(ioStreams)))
+
+	return cmd
+}
+
+// printTable prints the rows as table with the given column names
+func printTable(out io.Writer, columns []string, rows [][]string) error {
+	table := &metav1beta1.Table{
+		ColumnDefinitions: make([]metav1.TableColumnDefinition, len(columns)),
+		Rows:              make([]metav1.TableRow, len(rows)),
+	}
+
+	for i, column := range columns {
+		table.ColumnDefinitions[i] = metav1.TableColumnDefinition{Name: column, Type: "string"}
+	}
+
+	for i, row := range rows {
+		cells := make([]interface{}, len(row))
+		for j, cell := range row {
+			cells[j] = cell
+		}
+
+		table.Rows[i] = metav1.TableRow{Cells: cells}
+	}
+
+	printer := printers.NewTablePrinter(printers.PrintOptions{})
+
+	return printer.PrintObj(table, out)
+}
+
+// writeCSV writes the rows as csv with a header line of the lower case column names, e.g. worker_pools
+func writeCSV(out io.Writer, columns []string, rows [][]string) error {
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = strings.ReplaceAll(strings.ToLower(column), " ", "_")
+	}
+
+	w := csv.NewWriter(out)
+
+	if err := w.Write(header); err != nil {
+		return fmt.Errorf("failed to write csv: %w", err)
+	}
+
+	if err := w.WriteAll(rows); err != nil {
+		return fmt.Errorf("failed to write csv: %w", err)
+	}
+
+	return nil
+}
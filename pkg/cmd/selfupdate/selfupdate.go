@@ -0,0 +1,168 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package selfupdate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/component-base/version"
+
+	"github.com/gardener/gardenctl-v2/internal/release"
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
+)
+
+// NewCmdSelfUpdate returns a new self-update command.
+func NewCmdSelfUpdate(f util.Factory, o *SelfUpdateOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "self-update",
+		Short: "Update gardenctl to the latest release",
+		Long: `Update gardenctl to the latest release of the given channel. The binary for the current operating system and
+architecture is downloaded from the GitHub release, verified with the SHA256 checksum and, if this build of gardenctl
+contains a release signing key, with the signature that are attached to the release. The running binary is then replaced
+atomically. Binaries that have been installed with a package manager like Homebrew must be updated with the package manager.`,
+		Example: `# update gardenctl to the latest stable release
+gardenctl self-update
+
+# update gardenctl to the latest release including pre-releases
+gardenctl self-update --channel latest`,
+		Args: cobra.NoArgs,
+		RunE: base.WrapRunE(o, f),
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// SelfUpdateOptions is a struct to support self-update command
+type SelfUpdateOptions struct {
+	base.Options
+
+	// Channel is either release.ChannelStable or release.ChannelLatest
+	Channel string
+	// Timeout is the maximum duration to download the release
+	Timeout time.Duration
+	// ReleasesURL is the GitHub API endpoint that lists the releases of gardenctl
+	ReleasesURL string
+	// CurrentVersion is the version of the running gardenctl binary
+	CurrentVersion string
+	// Executable is the path of the binary that is replaced, it defaults to the running binary
+	Executable string
+}
+
+// NewSelfUpdateOptions returns initialized SelfUpdateOptions
+func NewSelfUpdateOptions(ioStreams util.IOStreams) *SelfUpdateOptions {
+	return &SelfUpdateOptions{
+		Options: base.Options{
+			IOStreams: ioStreams,
+		},
+		Channel:        release.ChannelStable,
+		Timeout:        5 * time.Minute,
+		ReleasesURL:    release.DefaultReleasesURL,
+		CurrentVersion: version.Get().GitVersion,
+	}
+}
+
+// AddFlags binds the command options to a given flagset
+func (o *SelfUpdateOptions) AddFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&o.Channel, "channel", o.Channel, fmt.Sprintf("Release channel to update from, one of %s or %s. The %s channel includes pre-releases.", release.ChannelStable, release.ChannelLatest, release.ChannelLatest))
+	flags.DurationVar(&o.Timeout, "timeout", o.Timeout, "Maximum duration to download the release.")
+}
+
+// Complete adapts from the command line args to the data required.
+func (o *SelfUpdateOptions) Complete(f util.Factory, cmd *cobra.Command, args []string) error {
+	if o.Executable != "" {
+		return nil
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to determine the path of the gardenctl binary: %w", err)
+	}
+
+	executable, err = filepath.EvalSymlinks(executable)
+	if err != nil {
+		return fmt.Errorf("failed to determine the path of the gardenctl binary: %w", err)
+	}
+
+	o.Executable = executable
+
+	return nil
+}
+
+// Validate validates the provided options
+func (o *SelfUpdateOptions) Validate() error {
+	if o.Channel != release.ChannelStable && o.Channel != release.ChannelLatest {
+		return fmt.Errorf("invalid channel %q, must be one of %s or %s", o.Channel, release.ChannelStable, release.ChannelLatest)
+	}
+
+	if o.Timeout <= 0 {
+		return errors.New("the timeout must be positive")
+	}
+
+	return nil
+}
+
+// Run executes the command
+func (o *SelfUpdateOptions) Run(f util.Factory) error {
+	if release.ManagedByPackageManager(o.Executable) {
+		return fmt.Errorf("gardenctl at %s has been installed with a package manager, update it with the package manager instead", o.Executable)
+	}
+
+	current, err := release.ParseVersion(o.CurrentVersion)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(f.Context(), o.Timeout)
+	defer cancel()
+
+	releases, err := release.List(ctx, o.ReleasesURL)
+	if err != nil {
+		return err
+	}
+
+	latest, err := release.LatestOfChannel(releases, o.Channel)
+	if err != nil {
+		return err
+	}
+
+	latestVersion, err := release.ParseVersion(latest.TagName)
+	if err != nil {
+		return err
+	}
+
+	if !current.LessThan(latestVersion) {
+		fmt.Fprintf(o.IOStreams.Out, "gardenctl %s is up to date, the latest release of channel %s is %s\n", o.CurrentVersion, o.Channel, latest.TagName)
+		return nil
+	}
+
+	if !release.SigningKeyConfigured() {
+		fmt.Fprintln(o.IOStreams.ErrOut, "Warning: this build of gardenctl contains no release signing key, only the checksum of the new binary is verified")
+	}
+
+	binary, err := release.DownloadBinary(ctx, latest)
+	if err != nil {
+		return err
+	}
+
+	if err := release.ReplaceBinary(o.Executable, binary); err != nil {
+		return fmt.Errorf("failed to update gardenctl: %w", err)
+	}
+
+	fmt.Fprintf(o.IOStreams.Out, "Successfully updated gardenctl from %s to %s\n", o.CurrentVersion, latest.TagName)
+
+	return nil
+}
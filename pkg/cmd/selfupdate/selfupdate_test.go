@@ -0,0 +1,109 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package selfupdate_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/gardener/gardenctl-v2/internal/fake"
+	"github.com/gardener/gardenctl-v2/internal/release"
+	"github.com/gardener/gardenctl-v2/internal/util"
+	. "github.com/gardener/gardenctl-v2/pkg/cmd/selfupdate"
+)
+
+var _ = Describe("Self-Update Command", func() {
+	var (
+		streams  util.IOStreams
+		out      *util.SafeBytesBuffer
+		factory  util.Factory
+		o        *SelfUpdateOptions
+		releases *fake.GitHubReleases
+		dir      string
+	)
+
+	BeforeEach(func() {
+		var err error
+
+		dir, err = ioutil.TempDir("", "gctlv2-*")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ioutil.WriteFile(filepath.Join(dir, "gardenctl"), []byte("v2.0.0"), 0755)).To(Succeed())
+
+		releases = fake.NewGitHubReleases()
+		releases.AddRelease("v2.0.0", false, []byte("v2.0.0"))
+		releases.AddRelease("v2.1.0", false, []byte("v2.1.0"))
+		releases.AddRelease("v2.2.0-rc.1", true, []byte("v2.2.0-rc.1"))
+
+		streams, _, out, _ = util.NewTestIOStreams()
+		factory = &util.FactoryImpl{}
+		o = NewSelfUpdateOptions(streams)
+		o.ReleasesURL = releases.ReleasesURL()
+		o.CurrentVersion = "v2.0.0"
+		o.Executable = filepath.Join(dir, "gardenctl")
+	})
+
+	AfterEach(func() {
+		releases.Close()
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("should update to the latest stable release", func() {
+		cmd := NewCmdSelfUpdate(factory, o)
+		cmd.SetArgs([]string{})
+		Expect(cmd.Execute()).To(Succeed())
+
+		Expect(out.String()).To(Equal("Successfully updated gardenctl from v2.0.0 to v2.1.0\n"))
+		Expect(ioutil.ReadFile(o.Executable)).To(Equal([]byte("v2.1.0")))
+	})
+
+	It("should update to the latest release including pre-releases", func() {
+		cmd := NewCmdSelfUpdate(factory, o)
+		cmd.SetArgs([]string{"--channel", "latest"})
+		Expect(cmd.Execute()).To(Succeed())
+
+		Expect(ioutil.ReadFile(o.Executable)).To(Equal([]byte("v2.2.0-rc.1")))
+	})
+
+	It("should not update if the current version is up to date", func() {
+		o.CurrentVersion = "v2.1.0"
+
+		cmd := NewCmdSelfUpdate(factory, o)
+		cmd.SetArgs([]string{})
+		Expect(cmd.Execute()).To(Succeed())
+
+		Expect(out.String()).To(Equal("gardenctl v2.1.0 is up to date, the latest release of channel stable is v2.1.0\n"))
+		Expect(ioutil.ReadFile(o.Executable)).To(Equal([]byte("v2.0.0")))
+	})
+
+	It("should not replace the binary if the checksum does not match", func() {
+		releases.SetAsset("v2.1.0", release.AssetName(runtime.GOOS, runtime.GOARCH)+".sha256", []byte("0000"))
+
+		cmd := NewCmdSelfUpdate(factory, o)
+		cmd.SetArgs([]string{})
+		Expect(cmd.Execute()).To(MatchError(ContainSubstring("checksum mismatch")))
+
+		Expect(ioutil.ReadFile(o.Executable)).To(Equal([]byte("v2.0.0")))
+	})
+
+	It("should refuse to update binaries of package managers", func() {
+		o.Executable = "/usr/local/Cellar/gardenctl-v2/2.0.0/bin/gardenctl"
+
+		cmd := NewCmdSelfUpdate(factory, o)
+		cmd.SetArgs([]string{})
+		Expect(cmd.Execute()).To(MatchError(ContainSubstring("has been installed with a package manager")))
+	})
+
+	It("should validate the channel", func() {
+		o.Channel = "beta"
+		Expect(o.Validate()).To(MatchError(`invalid channel "beta", must be one of stable or latest`))
+	})
+})
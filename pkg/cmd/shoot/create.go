@@ -0,0 +1,212 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shoot
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	"github.com/gardener/gardenctl-v2/internal/shoottemplate"
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
+	"github.com/gardener/gardenctl-v2/pkg/target"
+)
+
+// NewCmdCreate returns a new shoot create command.
+func NewCmdCreate(f util.Factory, o *CreateOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create NAME --template TEMPLATE",
+		Short: "Create a shoot in the targeted project from a template",
+		Long: `Create a shoot in the targeted project from a template of the template library.
+The library is configured in the templates section of the gardenctl configuration. The template <name> is read from
+the file <name>.yaml of the template directory or pulled from the OCI repository <registry>/<name>, the name may contain
+a tag or digest, e.g. aws:v1. Templates can also be read from a file, e.g. --template ./my-shoot.yaml.
+
+Templates are Go templates with the sprig functions, which are rendered with the following data:
+  .Name       the name of the shoot
+  .Project    the name of the targeted project
+  .Namespace  the namespace of the targeted project
+  .Values     the values of the --values files and --set flags
+Use {{ required "description" .Values.key }} to fail if a value is missing.
+
+The rendered shoot is validated by the garden cluster with a dry-run before it is created. With --dry-run, the validated
+shoot is printed but not created. With --wait, the progress of the creation is printed until it has finished.`,
+		Example: `# create the shoot my-shoot from the template aws of the template library
+gardenctl shoot create my-shoot --template aws --set region=eu-west-1 --set workers.maximum=5
+
+# validate and print the shoot rendered from a template file with values from a file
+gardenctl shoot create my-shoot --template ./shoot.yaml --values values.yaml --dry-run`,
+		Args: cobra.ExactArgs(1),
+		RunE: base.WrapRunE(o, f),
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// CreateOptions is a struct to support shoot create command
+type CreateOptions struct {
+	base.Options
+	WaitOptions
+
+	// Name is the name of the shoot
+	Name string
+	// Template is the name of the template or the path of a template file
+	Template string
+	// ValuesFiles are YAML files with values for the template
+	ValuesFiles []string
+	// Set are key=value pairs of values for the template, which take precedence over the values files
+	Set []string
+}
+
+// NewCreateOptions returns initialized CreateOptions
+func NewCreateOptions(ioStreams util.IOStreams) *CreateOptions {
+	return &CreateOptions{
+		Options: base.Options{
+			IOStreams: ioStreams,
+		},
+	}
+}
+
+// AddFlags binds the command options to a given flagset
+func (o *CreateOptions) AddFlags(flags *pflag.FlagSet) {
+	o.WaitOptions.AddFlags(flags)
+	flags.StringVar(&o.Template, "template", o.Template, "Name of the template of the template library or path of a template file.")
+	flags.StringArrayVar(&o.ValuesFiles, "values", o.ValuesFiles, "YAML file with values for the template, can be repeated.")
+	flags.StringArrayVar(&o.Set, "set", o.Set, "Value for the template in the format key=value, nested keys are separated by dots. Can be repeated.")
+}
+
+// Complete adapts from the command line args to the data required.
+func (o *CreateOptions) Complete(f util.Factory, cmd *cobra.Command, args []string) error {
+	if len(args) > 0 {
+		o.Name = args[0]
+	}
+
+	return nil
+}
+
+// Validate validates the provided CreateOptions
+func (o *CreateOptions) Validate() error {
+	if o.Name == "" {
+		return errors.New("the shoot name must not be empty")
+	}
+
+	if o.Template == "" {
+		return errors.New("--template is required")
+	}
+
+	return o.WaitOptions.Validate()
+}
+
+// Run executes the command
+func (o *CreateOptions) Run(f util.Factory) error {
+	manager, err := f.Manager()
+	if err != nil {
+		return err
+	}
+
+	currentTarget, err := manager.CurrentTarget()
+	if err != nil {
+		return fmt.Errorf("failed to get current target: %w", err)
+	}
+
+	if currentTarget.ProjectName() == "" {
+		return target.ErrNoProjectTargeted
+	}
+
+	var files [][]byte
+
+	for _, filename := range o.ValuesFiles {
+		data, err := os.ReadFile(filename)
+		if err != nil {
+			return fmt.Errorf("failed to read values file: %w", err)
+		}
+
+		files = append(files, data)
+	}
+
+	values, err := shoottemplate.ParseValues(files, o.Set)
+	if err != nil {
+		return err
+	}
+
+	gardenClient, err := manager.GardenClient(currentTarget.GardenName())
+	if err != nil {
+		return fmt.Errorf("failed to create garden cluster client: %w", err)
+	}
+
+	ctx := f.Context()
+
+	project, err := gardenClient.GetProject(ctx, currentTarget.ProjectName())
+	if err != nil {
+		return err
+	}
+
+	if project.Spec.Namespace == nil || *project.Spec.Namespace == "" {
+		return fmt.Errorf("project %q has no namespace", project.Name)
+	}
+
+	cfg := manager.Configuration()
+
+	dir, err := cfg.TemplateDirectory()
+	if err != nil {
+		return err
+	}
+
+	library := &shoottemplate.Library{Directory: dir, Registry: cfg.TemplateRegistry()}
+
+	text, err := library.Load(ctx, o.Template)
+	if err != nil {
+		return err
+	}
+
+	shoot, err := shoottemplate.Render(o.Template, text, shoottemplate.Data{
+		Name:      o.Name,
+		Project:   project.Name,
+		Namespace: *project.Spec.Namespace,
+		Values:    values,
+	})
+	if err != nil {
+		return err
+	}
+
+	key := client.ObjectKeyFromObject(shoot)
+
+	// the dry-run lets the garden cluster validate and default the shoot, e.g. if the
+	// referenced cloud profile supports the kubernetes version, without persisting it
+	validated := shoot.DeepCopy()
+	if err := gardenClient.RuntimeClient().Create(ctx, validated, client.DryRunAll); err != nil {
+		return fmt.Errorf("the shoot %s rendered from template %q is invalid: %w", key, o.Template, err)
+	}
+
+	// the validated shoot is printed as is, so that the output can be used as a template
+	if cfg.IsDryRun() {
+		data, err := yaml.Marshal(validated)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprint(o.IOStreams.Out, string(data))
+
+		return nil
+	}
+
+	if err := gardenClient.RuntimeClient().Create(ctx, shoot); err != nil {
+		return fmt.Errorf("failed to create shoot %s: %w", key, err)
+	}
+
+	fmt.Fprintf(o.IOStreams.Out, "Created shoot %s from template %q\n", key, o.Template)
+
+	return o.wait(f, o.IOStreams.Out, gardenClient, key)
+}
@@ -0,0 +1,133 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shoot_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	internalfake "github.com/gardener/gardenctl-v2/internal/fake"
+	"github.com/gardener/gardenctl-v2/internal/util"
+	cmdshoot "github.com/gardener/gardenctl-v2/pkg/cmd/shoot"
+	"github.com/gardener/gardenctl-v2/pkg/config"
+	"github.com/gardener/gardenctl-v2/pkg/target"
+	targetmocks "github.com/gardener/gardenctl-v2/pkg/target/mocks"
+)
+
+var _ = Describe("Shoot Create Command", func() {
+	const (
+		gardenName  = "mygarden"
+		projectName = "prod1"
+		template    = `kind: Shoot
+spec:
+  region: {{ required "region" .Values.region }}
+  kubernetes:
+    version: "1.23.1"
+  provider:
+    type: aws
+`
+	)
+
+	var (
+		ctrl         *gomock.Controller
+		streams      util.IOStreams
+		out          *util.SafeBytesBuffer
+		factory      *internalfake.Factory
+		gardenClient client.Client
+		dir          string
+	)
+
+	BeforeEach(func() {
+		var err error
+
+		dir, err = os.MkdirTemp("", "shoottemplates-")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.WriteFile(filepath.Join(dir, "aws.yaml"), []byte(template), 0o600)).To(Succeed())
+
+		cfg := &config.Config{
+			LinkKubeconfig: pointer.Bool(false),
+			Gardens: []config.Garden{{
+				Name:       gardenName,
+				Kubeconfig: "/not/a/real/kubeconfig",
+			}},
+			Templates: &config.Templates{Directory: dir},
+		}
+
+		project := &gardencorev1beta1.Project{
+			ObjectMeta: metav1.ObjectMeta{Name: projectName},
+			Spec:       gardencorev1beta1.ProjectSpec{Namespace: pointer.String("garden-prod1")},
+		}
+
+		gardenClient = internalfake.NewClientWithObjects(project)
+
+		streams, _, out, _ = util.NewTestIOStreams()
+
+		ctrl = gomock.NewController(GinkgoT())
+
+		clientProvider := targetmocks.NewMockClientProvider(ctrl)
+		clientConfig, err := cfg.ClientConfig(gardenName)
+		Expect(err).ToNot(HaveOccurred())
+		clientProvider.EXPECT().FromClientConfig(gomock.Eq(clientConfig)).Return(gardenClient, nil).AnyTimes()
+
+		targetProvider := internalfake.NewFakeTargetProvider(target.NewTarget(gardenName, projectName, "", ""))
+
+		factory = internalfake.NewFakeFactory(cfg, nil, clientProvider, targetProvider)
+		factory.ContextImpl = context.Background()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("should create the shoot from the template", func() {
+		cmd := cmdshoot.NewCmdCreate(factory, cmdshoot.NewCreateOptions(streams))
+		Expect(cmd.Flags().Set("template", "aws")).To(Succeed())
+		Expect(cmd.Flags().Set("set", "region=eu-west-1")).To(Succeed())
+		Expect(cmd.RunE(cmd, []string{"myshoot"})).To(Succeed())
+		Expect(out.String()).To(Equal("Created shoot garden-prod1/myshoot from template \"aws\"\n"))
+
+		shoot := &gardencorev1beta1.Shoot{}
+		Expect(gardenClient.Get(context.Background(), client.ObjectKey{Namespace: "garden-prod1", Name: "myshoot"}, shoot)).To(Succeed())
+		Expect(shoot.Spec.Region).To(Equal("eu-west-1"))
+	})
+
+	It("should print the shoot without creating it in dry-run mode", func() {
+		factory.Config.DryRun = &config.DryRun{Strategy: config.DryRunClient, Out: out}
+
+		cmd := cmdshoot.NewCmdCreate(factory, cmdshoot.NewCreateOptions(streams))
+		Expect(cmd.Flags().Set("template", "aws")).To(Succeed())
+		Expect(cmd.Flags().Set("set", "region=eu-west-1")).To(Succeed())
+		Expect(cmd.RunE(cmd, []string{"myshoot"})).To(Succeed())
+		Expect(out.String()).To(ContainSubstring("name: myshoot\n  namespace: garden-prod1\n"))
+		Expect(out.String()).To(ContainSubstring("region: eu-west-1\n"))
+
+		err := gardenClient.Get(context.Background(), client.ObjectKey{Namespace: "garden-prod1", Name: "myshoot"}, &gardencorev1beta1.Shoot{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should fail if a required value is missing", func() {
+		cmd := cmdshoot.NewCmdCreate(factory, cmdshoot.NewCreateOptions(streams))
+		Expect(cmd.Flags().Set("template", "aws")).To(Succeed())
+		Expect(cmd.RunE(cmd, []string{"myshoot"})).To(MatchError(ContainSubstring("missing required value: region")))
+	})
+
+	It("should fail without a template", func() {
+		cmd := cmdshoot.NewCmdCreate(factory, cmdshoot.NewCreateOptions(streams))
+		Expect(cmd.RunE(cmd, []string{"myshoot"})).To(MatchError("--template is required"))
+	})
+})
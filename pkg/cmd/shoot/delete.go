@@ -0,0 +1,179 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shoot
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/gardener/gardenctl-v2/internal/gardenclient"
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/status"
+)
+
+const (
+	// ConfirmationDeletionAnnotation must be set to "true" on a shoot before Gardener accepts its deletion
+	ConfirmationDeletionAnnotation = "confirmation.gardener.cloud/deletion"
+	// DeletionProtectionLabel protects a shoot from being deleted with gardenctl if its value is "true"
+	DeletionProtectionLabel = "gardenctl.gardener.cloud/deletion-protection"
+)
+
+// NewCmdDelete returns a new shoot delete command.
+func NewCmdDelete(f util.Factory, o *DeleteOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete the targeted shoot",
+		Long: `Delete the targeted shoot. The confirmation.gardener.cloud/deletion annotation, which Gardener requires
+before a shoot can be deleted, is set automatically.
+You will be asked for confirmation and to type the name of the shoot unless the --force flag is set.
+Shoots with purpose production are only deleted if the --yes-i-really-mean-it flag is set. Shoots with the label
+gardenctl.gardener.cloud/deletion-protection=true are never deleted, remove the label first.
+With --wait, the progress of the deletion is printed until the shoot is gone.
+With --dry-run=server, the deletion is only sent to the garden cluster if the shoot already has the annotation, because
+the annotation is not persisted in dry-run mode.`,
+		Example: `# delete the targeted shoot and wait until it has been deleted
+gardenctl shoot delete --wait --timeout 1h`,
+		Args: cobra.NoArgs,
+		RunE: base.WrapRunE(o, f),
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// DeleteOptions is a struct to support shoot delete command
+type DeleteOptions struct {
+	base.Options
+	WaitOptions
+
+	// Force skips the confirmation prompts
+	Force bool
+
+	// YesIReallyMeanIt allows to delete shoots with purpose production
+	YesIReallyMeanIt bool
+}
+
+// NewDeleteOptions returns initialized DeleteOptions
+func NewDeleteOptions(ioStreams util.IOStreams) *DeleteOptions {
+	return &DeleteOptions{
+		Options: base.Options{
+			IOStreams: ioStreams,
+		},
+	}
+}
+
+// AddFlags binds the command options to a given flagset
+func (o *DeleteOptions) AddFlags(flags *pflag.FlagSet) {
+	o.WaitOptions.AddFlags(flags)
+	flags.BoolVarP(&o.Force, "force", "f", o.Force, "Delete the shoot without asking for confirmation.")
+	flags.BoolVar(&o.YesIReallyMeanIt, "yes-i-really-mean-it", o.YesIReallyMeanIt, "Allow to act on shoots with purpose production.")
+}
+
+// Validate validates the provided DeleteOptions
+func (o *DeleteOptions) Validate() error {
+	return o.WaitOptions.Validate()
+}
+
+// Run executes the command
+func (o *DeleteOptions) Run(f util.Factory) error {
+	currentTarget, gardenClient, shoot, err := targetedShoot(f)
+	if err != nil {
+		return err
+	}
+
+	key := client.ObjectKeyFromObject(shoot)
+
+	if shoot.Labels[DeletionProtectionLabel] == "true" {
+		return fmt.Errorf("shoot %s is protected by the label %s=true, remove the label to delete it", key, DeletionProtectionLabel)
+	}
+
+	if shoot.Spec.Purpose != nil && *shoot.Spec.Purpose == gardencorev1beta1.ShootPurposeProduction && !o.YesIReallyMeanIt {
+		return fmt.Errorf("shoot %s has purpose production, set --yes-i-really-mean-it to delete it", key)
+	}
+
+	if shoot.DeletionTimestamp != nil {
+		fmt.Fprintf(o.IOStreams.Out, "Shoot %s is already being deleted\n", key)
+		return o.waitForDeletion(f, gardenClient, key)
+	}
+
+	if !o.Force {
+		prompter := util.NewPrompter(o.IOStreams)
+
+		confirmed, err := prompter.Confirm(fmt.Sprintf("Do you really want to delete shoot %s in garden %s? All its resources will be deleted.", key, currentTarget.GardenName()))
+		if err != nil {
+			return err
+		}
+
+		if !confirmed {
+			fmt.Fprintln(o.IOStreams.Out, "Aborted")
+			return nil
+		}
+
+		name, err := prompter.Input("Type the name of the shoot to confirm the deletion", "", nil)
+		if err != nil {
+			return err
+		}
+
+		if strings.TrimSpace(name) != shoot.Name {
+			return errors.New("the name does not match the shoot name, the shoot has not been deleted")
+		}
+	}
+
+	ctx := f.Context()
+
+	confirmed := shoot.Annotations[ConfirmationDeletionAnnotation] == "true"
+
+	if !confirmed {
+		patch := client.MergeFrom(shoot.DeepCopy())
+
+		if shoot.Annotations == nil {
+			shoot.Annotations = map[string]string{}
+		}
+
+		shoot.Annotations[ConfirmationDeletionAnnotation] = "true"
+
+		if err := gardenClient.RuntimeClient().Patch(ctx, shoot, patch); err != nil {
+			return fmt.Errorf("failed to confirm the deletion of shoot %s: %w", key, err)
+		}
+	}
+
+	// the annotation is not persisted by a dry-run request, Gardener would reject the dry-run deletion without it
+	if !confirmed && isServerDryRun(f) {
+		fmt.Fprintf(o.IOStreams.ErrOut, "Warning: the deletion of shoot %s is not sent to the garden cluster in dry-run mode, because it requires the %s annotation to be persisted\n", key, ConfirmationDeletionAnnotation)
+		return nil
+	}
+
+	if err := gardenClient.RuntimeClient().Delete(ctx, shoot); err != nil {
+		return fmt.Errorf("failed to delete shoot %s: %w", key, err)
+	}
+
+	// in dry-run mode the change has already been printed by the client, there is nothing to wait for
+	if isDryRun(f) {
+		return nil
+	}
+
+	fmt.Fprintf(o.IOStreams.Out, "Triggered the deletion of shoot %s\n", key)
+
+	return o.waitForDeletion(f, gardenClient, key)
+}
+
+// waitForDeletion prints the progress of the deletion of the shoot until it is gone, if Wait is set
+func (o *DeleteOptions) waitForDeletion(f util.Factory, gardenClient gardenclient.Client, key client.ObjectKey) error {
+	if !o.Wait {
+		return nil
+	}
+
+	return status.WatchDeletion(f.Context(), o.IOStreams.Out, f.Clock(), gardenClient, key, o.Timeout)
+}
@@ -0,0 +1,187 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shoot_test
+
+import (
+	"context"
+	"errors"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	internalfake "github.com/gardener/gardenctl-v2/internal/fake"
+	"github.com/gardener/gardenctl-v2/internal/util"
+	cmdshoot "github.com/gardener/gardenctl-v2/pkg/cmd/shoot"
+	"github.com/gardener/gardenctl-v2/pkg/config"
+)
+
+// confirmationClient rejects the deletion of shoots without the confirmation annotation like Gardener. Dry-run
+// deletions are not persisted, which the fake client does not support.
+type confirmationClient struct {
+	client.Client
+}
+
+func (c confirmationClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	current := &gardencorev1beta1.Shoot{}
+	if err := c.Client.Get(ctx, client.ObjectKeyFromObject(obj), current); err != nil {
+		return err
+	}
+
+	if current.Annotations[cmdshoot.ConfirmationDeletionAnnotation] != "true" {
+		return apierrors.NewForbidden(gardencorev1beta1.Resource("shoots"), obj.GetName(), errors.New("the deletion has not been confirmed"))
+	}
+
+	if (&client.DeleteOptions{}).ApplyOptions(opts).DryRun != nil {
+		return nil
+	}
+
+	return c.Client.Delete(ctx, obj, opts...)
+}
+
+var _ = Describe("Shoot Delete Command", func() {
+	var (
+		ctrl         *gomock.Controller
+		streams      util.IOStreams
+		in           *util.SafeBytesBuffer
+		out          *util.SafeBytesBuffer
+		errOut       *util.SafeBytesBuffer
+		factory      *internalfake.Factory
+		gardenClient func() client.Client
+		shoot        *gardencorev1beta1.Shoot
+	)
+
+	BeforeEach(func() {
+		shoot = &gardencorev1beta1.Shoot{
+			ObjectMeta: metav1.ObjectMeta{Name: shootName, Namespace: "garden-prod1"},
+		}
+
+		streams, in, out, errOut = util.NewTestIOStreams()
+
+		ctrl = gomock.NewController(GinkgoT())
+
+		factory, gardenClient = newShootFactory(ctrl, shoot, func(c client.Client) client.Client {
+			return confirmationClient{Client: c}
+		})
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	getShoot := func() (*gardencorev1beta1.Shoot, error) {
+		current := &gardencorev1beta1.Shoot{}
+		err := gardenClient().Get(context.Background(), client.ObjectKeyFromObject(shoot), current)
+
+		return current, err
+	}
+
+	It("should confirm and delete the shoot after the name has been typed", func() {
+		// the finalizer keeps the shoot, so that the confirmation annotation can be checked
+		shoot.Finalizers = []string{"gardener"}
+
+		in.Write([]byte("y\nmyshoot\n"))
+
+		cmd := cmdshoot.NewCmdDelete(factory, cmdshoot.NewDeleteOptions(streams))
+		Expect(cmd.RunE(cmd, nil)).To(Succeed())
+		Expect(out.String()).To(Equal("Do you really want to delete shoot garden-prod1/myshoot in garden mygarden? All its resources will be deleted. [y/N]: " +
+			"Type the name of the shoot to confirm the deletion: " +
+			"Triggered the deletion of shoot garden-prod1/myshoot\n"))
+
+		current, err := getShoot()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(current.Annotations).To(HaveKeyWithValue("confirmation.gardener.cloud/deletion", "true"))
+		Expect(current.DeletionTimestamp).NotTo(BeNil())
+	})
+
+	It("should not delete the shoot if the typed name does not match", func() {
+		in.Write([]byte("y\nothershoot\n"))
+
+		cmd := cmdshoot.NewCmdDelete(factory, cmdshoot.NewDeleteOptions(streams))
+		Expect(cmd.RunE(cmd, nil)).To(MatchError("the name does not match the shoot name, the shoot has not been deleted"))
+
+		current, err := getShoot()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(current.Annotations).NotTo(HaveKey("confirmation.gardener.cloud/deletion"))
+	})
+
+	It("should not delete the shoot if the confirmation is denied", func() {
+		in.Write([]byte("n\n"))
+
+		cmd := cmdshoot.NewCmdDelete(factory, cmdshoot.NewDeleteOptions(streams))
+		Expect(cmd.RunE(cmd, nil)).To(Succeed())
+		Expect(out.String()).To(HaveSuffix("Aborted\n"))
+		Expect(getShoot()).NotTo(BeNil())
+	})
+
+	It("should delete the shoot without confirmation and wait until it is gone", func() {
+		cmd := cmdshoot.NewCmdDelete(factory, cmdshoot.NewDeleteOptions(streams))
+		Expect(cmd.Flags().Set("force", "true")).To(Succeed())
+		Expect(cmd.Flags().Set("wait", "true")).To(Succeed())
+		Expect(cmd.RunE(cmd, nil)).To(Succeed())
+		Expect(out.String()).To(HavePrefix("Triggered the deletion of shoot garden-prod1/myshoot\n"))
+		Expect(out.String()).To(HaveSuffix("Shoot garden-prod1/myshoot has been deleted\n"))
+
+		_, err := getShoot()
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	Context("in server dry-run mode", func() {
+		BeforeEach(func() {
+			factory.Config.DryRun = &config.DryRun{Strategy: config.DryRunServer, Out: out}
+		})
+
+		It("should not send the deletion if the confirmation annotation is not persisted", func() {
+			cmd := cmdshoot.NewCmdDelete(factory, cmdshoot.NewDeleteOptions(streams))
+			Expect(cmd.Flags().Set("force", "true")).To(Succeed())
+			Expect(cmd.RunE(cmd, nil)).To(Succeed())
+			Expect(out.String()).To(ContainSubstring("confirmation.gardener.cloud/deletion: \"true\""))
+			Expect(out.String()).NotTo(ContainSubstring("deleted (server dry run)"))
+			Expect(errOut.String()).To(Equal("Warning: the deletion of shoot garden-prod1/myshoot is not sent to the garden cluster in dry-run mode, because it requires the confirmation.gardener.cloud/deletion annotation to be persisted\n"))
+
+			current, err := getShoot()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(current.Annotations).NotTo(HaveKey("confirmation.gardener.cloud/deletion"))
+		})
+
+		It("should send the deletion as dry-run request if the shoot has the confirmation annotation", func() {
+			shoot.Annotations = map[string]string{"confirmation.gardener.cloud/deletion": "true"}
+
+			cmd := cmdshoot.NewCmdDelete(factory, cmdshoot.NewDeleteOptions(streams))
+			Expect(cmd.Flags().Set("force", "true")).To(Succeed())
+			Expect(cmd.RunE(cmd, nil)).To(Succeed())
+			Expect(out.String()).To(ContainSubstring("deleted (server dry run)"))
+			Expect(errOut.String()).To(BeEmpty())
+
+			current, err := getShoot()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(current.DeletionTimestamp).To(BeNil())
+		})
+	})
+
+	It("should refuse to delete a protected shoot", func() {
+		shoot.Labels = map[string]string{"gardenctl.gardener.cloud/deletion-protection": "true"}
+
+		cmd := cmdshoot.NewCmdDelete(factory, cmdshoot.NewDeleteOptions(streams))
+		Expect(cmd.Flags().Set("force", "true")).To(Succeed())
+		Expect(cmd.RunE(cmd, nil)).To(MatchError("shoot garden-prod1/myshoot is protected by the label gardenctl.gardener.cloud/deletion-protection=true, remove the label to delete it"))
+		Expect(getShoot()).NotTo(BeNil())
+	})
+
+	It("should refuse to delete a production shoot without --yes-i-really-mean-it", func() {
+		purpose := gardencorev1beta1.ShootPurposeProduction
+		shoot.Spec.Purpose = &purpose
+
+		cmd := cmdshoot.NewCmdDelete(factory, cmdshoot.NewDeleteOptions(streams))
+		Expect(cmd.Flags().Set("force", "true")).To(Succeed())
+		Expect(cmd.RunE(cmd, nil)).To(MatchError("shoot garden-prod1/myshoot has purpose production, set --yes-i-really-mean-it to delete it"))
+	})
+})
@@ -0,0 +1,368 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shoot
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+
+	"github.com/fatih/color"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
+)
+
+// NewCmdDiff returns a new shoot diff command.
+func NewCmdDiff(f util.Factory, o *DiffOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Show the differences between the spec of the targeted shoot and its last applied configuration or another shoot",
+		Long: `Show a structural diff of the spec of the targeted shoot, e.g. to review the changes before reconciling it.
+Added fields are prefixed with +, removed fields with - and changed fields with ~. Entries of lists whose items
+have a name, like the workers, are matched by their name.
+
+Gardener does not keep the specs of previous generations of a shoot. By default, the spec is compared with the
+last applied configuration of the shoot, which is stored by kubectl apply in the kubectl.kubernetes.io/last-applied-configuration
+annotation. Only the fields that are set in the last applied configuration are compared, so that the defaults of Gardener are
+not shown as changes, but e.g. updates by the maintenance or manual edits are. With --against, the whole spec is compared with
+another shoot of the same project.`,
+		Example: `# show the changes of the targeted shoot since it was applied the last time
+gardenctl shoot diff
+
+# compare the targeted shoot with the shoot my-other-shoot of the same project
+gardenctl shoot diff --against my-other-shoot`,
+		Args: cobra.NoArgs,
+		RunE: base.WrapRunE(o, f),
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// DiffOptions is a struct to support shoot diff command
+type DiffOptions struct {
+	base.Options
+
+	// Against is the name of a shoot of the same project the targeted shoot is compared with
+	Against string
+}
+
+// NewDiffOptions returns initialized DiffOptions
+func NewDiffOptions(ioStreams util.IOStreams) *DiffOptions {
+	return &DiffOptions{
+		Options: base.Options{
+			IOStreams: ioStreams,
+		},
+	}
+}
+
+// AddFlags binds the command options to a given flagset
+func (o *DiffOptions) AddFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&o.Against, "against", o.Against, "Name of a shoot of the same project to compare the targeted shoot with.")
+}
+
+// Run executes the command
+func (o *DiffOptions) Run(f util.Factory) error {
+	_, gardenClient, shoot, err := targetedShoot(f)
+	if err != nil {
+		return err
+	}
+
+	newSpec, err := genericValue(shoot.Spec)
+	if err != nil {
+		return err
+	}
+
+	var (
+		oldSpec  interface{}
+		oldLabel string
+	)
+
+	if o.Against != "" {
+		other, err := gardenClient.GetShoot(f.Context(), shoot.Namespace, o.Against)
+		if err != nil {
+			return err
+		}
+
+		oldSpec, err = genericValue(other.Spec)
+		if err != nil {
+			return err
+		}
+
+		oldLabel = fmt.Sprintf("%s/%s", other.Namespace, other.Name)
+	} else {
+		oldSpec, err = lastAppliedSpec(shoot)
+		if err != nil {
+			return err
+		}
+
+		// fields that are not set in the last applied configuration, e.g. defaults, are ignored
+		newSpec = prune(newSpec, oldSpec)
+		oldLabel = fmt.Sprintf("%s/%s (last applied configuration)", shoot.Namespace, shoot.Name)
+	}
+
+	if shoot.Status.ObservedGeneration < shoot.Generation {
+		fmt.Fprintf(o.IOStreams.ErrOut, "The current spec (generation %d) of shoot %s has not been reconciled yet, the last reconciled generation is %d\n",
+			shoot.Generation, shoot.Name, shoot.Status.ObservedGeneration)
+	}
+
+	var changes []change
+
+	diffValues("spec", oldSpec, newSpec, &changes)
+
+	if len(changes) == 0 {
+		fmt.Fprintf(o.IOStreams.ErrOut, "No differences between %s and %s/%s\n", oldLabel, shoot.Namespace, shoot.Name)
+		return nil
+	}
+
+	fmt.Fprintf(o.IOStreams.Out, "--- %s\n", oldLabel)
+	fmt.Fprintf(o.IOStreams.Out, "+++ %s/%s (generation %d)\n", shoot.Namespace, shoot.Name, shoot.Generation)
+	printChanges(o.IOStreams.Out, changes)
+
+	return nil
+}
+
+// lastAppliedSpec returns the generic representation of the spec of the last applied configuration annotation.
+// Unlike a decoded shoot, it contains only the fields that have been applied.
+func lastAppliedSpec(shoot *gardencorev1beta1.Shoot) (interface{}, error) {
+	lastApplied, ok := shoot.Annotations[corev1.LastAppliedConfigAnnotation]
+	if !ok {
+		return nil, fmt.Errorf("shoot %s has no %s annotation, use --against to compare it with another shoot", shoot.Name, corev1.LastAppliedConfigAnnotation)
+	}
+
+	var obj struct {
+		Spec interface{} `json:"spec"`
+	}
+
+	if err := json.Unmarshal([]byte(lastApplied), &obj); err != nil {
+		return nil, fmt.Errorf("failed to parse the last applied configuration of shoot %s: %w", shoot.Name, err)
+	}
+
+	return obj.Spec, nil
+}
+
+// changeType is the kind of a change, it is used as prefix of the printed change
+type changeType string
+
+const (
+	changeAdded   changeType = "+"
+	changeRemoved changeType = "-"
+	changeUpdated changeType = "~"
+)
+
+// change is a difference of a field between two specs
+type change struct {
+	Type     changeType
+	Path     string
+	OldValue interface{}
+	NewValue interface{}
+}
+
+// genericValue returns the generic representation of the JSON encoding of obj
+func genericValue(obj interface{}) (interface{}, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+// prune removes the fields from value that are not set in reference. Items of lists are matched like in diffLists,
+// items that are not in reference are kept completely.
+func prune(value, reference interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		ref, ok := reference.(map[string]interface{})
+		if !ok {
+			return value
+		}
+
+		pruned := make(map[string]interface{}, len(ref))
+
+		for key, refValue := range ref {
+			if fieldValue, ok := v[key]; ok {
+				pruned[key] = prune(fieldValue, refValue)
+			}
+		}
+
+		return pruned
+
+	case []interface{}:
+		ref, ok := reference.([]interface{})
+		if !ok {
+			return value
+		}
+
+		_, namedOK := itemsByName(v)
+		refNamed, refNamedOK := itemsByName(ref)
+		pruned := make([]interface{}, len(v))
+
+		for i, item := range v {
+			switch {
+			case namedOK && refNamedOK:
+				if refItem, ok := refNamed[item.(map[string]interface{})["name"].(string)]; ok {
+					item = prune(item, refItem)
+				}
+			case i < len(ref):
+				item = prune(item, ref[i])
+			}
+
+			pruned[i] = item
+		}
+
+		return pruned
+	}
+
+	return value
+}
+
+// diffValues appends the changes between the old and the new value at path to changes
+func diffValues(path string, oldValue, newValue interface{}, changes *[]change) {
+	if reflect.DeepEqual(oldValue, newValue) {
+		return
+	}
+
+	switch {
+	case oldValue == nil:
+		*changes = append(*changes, change{Type: changeAdded, Path: path, NewValue: newValue})
+		return
+	case newValue == nil:
+		*changes = append(*changes, change{Type: changeRemoved, Path: path, OldValue: oldValue})
+		return
+	}
+
+	oldMap, oldIsMap := oldValue.(map[string]interface{})
+	newMap, newIsMap := newValue.(map[string]interface{})
+
+	if oldIsMap && newIsMap {
+		for _, key := range sortedKeys(oldMap, newMap) {
+			diffValues(path+"."+key, oldMap[key], newMap[key], changes)
+		}
+
+		return
+	}
+
+	oldList, oldIsList := oldValue.([]interface{})
+	newList, newIsList := newValue.([]interface{})
+
+	if oldIsList && newIsList {
+		diffLists(path, oldList, newList, changes)
+		return
+	}
+
+	*changes = append(*changes, change{Type: changeUpdated, Path: path, OldValue: oldValue, NewValue: newValue})
+}
+
+// diffLists matches the items of lists by their name if all items have one, otherwise by their index
+func diffLists(path string, oldList, newList []interface{}, changes *[]change) {
+	oldNamed, oldOK := itemsByName(oldList)
+	newNamed, newOK := itemsByName(newList)
+
+	if oldOK && newOK {
+		for _, name := range sortedKeys(oldNamed, newNamed) {
+			diffValues(fmt.Sprintf("%s[name=%s]", path, name), oldNamed[name], newNamed[name], changes)
+		}
+
+		return
+	}
+
+	for i := 0; i < len(oldList) || i < len(newList); i++ {
+		var oldItem, newItem interface{}
+
+		if i < len(oldList) {
+			oldItem = oldList[i]
+		}
+
+		if i < len(newList) {
+			newItem = newList[i]
+		}
+
+		diffValues(fmt.Sprintf("%s[%d]", path, i), oldItem, newItem, changes)
+	}
+}
+
+// itemsByName returns the items of the list by their name, false is returned if an item has no unique name
+func itemsByName(list []interface{}) (map[string]interface{}, bool) {
+	items := make(map[string]interface{}, len(list))
+
+	for _, item := range list {
+		fields, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+
+		name, ok := fields["name"].(string)
+		if !ok {
+			return nil, false
+		}
+
+		if _, exists := items[name]; exists {
+			return nil, false
+		}
+
+		items[name] = item
+	}
+
+	return items, true
+}
+
+func sortedKeys(maps ...map[string]interface{}) []string {
+	var keys []string
+
+	seen := map[string]bool{}
+
+	for _, m := range maps {
+		for key := range m {
+			if !seen[key] {
+				seen[key] = true
+
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// printChanges prints the changes colored by their type, values are printed as compact JSON
+func printChanges(out io.Writer, changes []change) {
+	for _, c := range changes {
+		switch c.Type {
+		case changeAdded:
+			fmt.Fprintln(out, color.GreenString("%s %s: %s", c.Type, c.Path, jsonString(c.NewValue)))
+		case changeRemoved:
+			fmt.Fprintln(out, color.RedString("%s %s: %s", c.Type, c.Path, jsonString(c.OldValue)))
+		case changeUpdated:
+			fmt.Fprintln(out, color.YellowString("%s %s: %s -> %s", c.Type, c.Path, jsonString(c.OldValue), jsonString(c.NewValue)))
+		}
+	}
+}
+
+func jsonString(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+
+	return string(data)
+}
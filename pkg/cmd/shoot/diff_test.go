@@ -0,0 +1,159 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shoot_test
+
+import (
+	"context"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	internalfake "github.com/gardener/gardenctl-v2/internal/fake"
+	"github.com/gardener/gardenctl-v2/internal/util"
+	cmdshoot "github.com/gardener/gardenctl-v2/pkg/cmd/shoot"
+	"github.com/gardener/gardenctl-v2/pkg/config"
+	"github.com/gardener/gardenctl-v2/pkg/target"
+	targetmocks "github.com/gardener/gardenctl-v2/pkg/target/mocks"
+)
+
+var _ = Describe("Shoot Diff Command", func() {
+	const (
+		gardenName  = "mygarden"
+		projectName = "prod1"
+		shootName   = "myshoot"
+	)
+
+	var (
+		ctrl       *gomock.Controller
+		streams    util.IOStreams
+		out        *util.SafeBytesBuffer
+		errOut     *util.SafeBytesBuffer
+		factory    *internalfake.Factory
+		shoot      *gardencorev1beta1.Shoot
+		otherShoot *gardencorev1beta1.Shoot
+	)
+
+	newShoot := func(name, version string, workers ...gardencorev1beta1.Worker) *gardencorev1beta1.Shoot {
+		return &gardencorev1beta1.Shoot{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "core.gardener.cloud/v1beta1", Kind: "Shoot"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "garden-prod1"},
+			Spec: gardencorev1beta1.ShootSpec{
+				Kubernetes: gardencorev1beta1.Kubernetes{Version: version},
+				Provider:   gardencorev1beta1.Provider{Type: "aws", Workers: workers},
+				Maintenance: &gardencorev1beta1.Maintenance{
+					TimeWindow: &gardencorev1beta1.MaintenanceTimeWindow{Begin: "220000+0000", End: "230000+0000"},
+				},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		cfg := &config.Config{
+			LinkKubeconfig: pointer.Bool(false),
+			Gardens: []config.Garden{{
+				Name:       gardenName,
+				Kubeconfig: "/not/a/real/kubeconfig",
+			}},
+		}
+
+		project := &gardencorev1beta1.Project{
+			ObjectMeta: metav1.ObjectMeta{Name: projectName},
+			Spec:       gardencorev1beta1.ProjectSpec{Namespace: pointer.String("garden-prod1")},
+		}
+
+		shoot = newShoot(shootName, "1.23.1",
+			gardencorev1beta1.Worker{Name: "worker-a", Minimum: 1, Maximum: 5},
+			gardencorev1beta1.Worker{Name: "worker-b", Minimum: 1, Maximum: 2},
+		)
+		shoot.Generation = 3
+		shoot.Status.ObservedGeneration = 3
+
+		otherShoot = newShoot("othershoot", "1.22.2",
+			gardencorev1beta1.Worker{Name: "worker-a", Minimum: 1, Maximum: 3},
+		)
+
+		streams, _, out, errOut = util.NewTestIOStreams()
+
+		ctrl = gomock.NewController(GinkgoT())
+
+		clientProvider := targetmocks.NewMockClientProvider(ctrl)
+		clientConfig, err := cfg.ClientConfig(gardenName)
+		Expect(err).ToNot(HaveOccurred())
+		clientProvider.EXPECT().FromClientConfig(gomock.Eq(clientConfig)).DoAndReturn(func(interface{}) (interface{}, error) {
+			return internalfake.NewClientWithObjects(project, shoot, otherShoot), nil
+		}).AnyTimes()
+
+		targetProvider := internalfake.NewFakeTargetProvider(target.NewTarget(gardenName, projectName, "", shootName))
+
+		factory = internalfake.NewFakeFactory(cfg, nil, clientProvider, targetProvider)
+		factory.ContextImpl = context.Background()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("should compare the shoot with another shoot", func() {
+		cmd := cmdshoot.NewCmdDiff(factory, cmdshoot.NewDiffOptions(streams))
+		Expect(cmd.Flags().Set("against", "othershoot")).To(Succeed())
+		Expect(cmd.RunE(cmd, nil)).To(Succeed())
+		Expect(out.String()).To(Equal(`--- garden-prod1/othershoot
++++ garden-prod1/myshoot (generation 3)
+~ spec.kubernetes.version: "1.22.2" -> "1.23.1"
+~ spec.provider.workers[name=worker-a].maximum: 3 -> 5
++ spec.provider.workers[name=worker-b]: {"cri":{"name":"containerd"},"machine":{"type":""},"maxSurge":1,"maxUnavailable":0,"maximum":2,"minimum":1,"name":"worker-b","systemComponents":{"allow":true}}
+`))
+	})
+
+	Context("when the shoot has a last applied configuration", func() {
+		BeforeEach(func() {
+			lastApplied := `{
+  "apiVersion": "core.gardener.cloud/v1beta1",
+  "kind": "Shoot",
+  "metadata": {"name": "myshoot", "namespace": "garden-prod1"},
+  "spec": {
+    "kubernetes": {"version": "1.22.2"},
+    "provider": {"type": "aws", "workers": [{"name": "worker-a", "minimum": 1, "maximum": 5}]}
+  }
+}`
+
+			// fields that are not in the last applied configuration, like defaults, are not compared
+			shoot.Spec.Networking.Type = "calico"
+			shoot.Generation = 4
+			shoot.Annotations = map[string]string{corev1.LastAppliedConfigAnnotation: lastApplied}
+		})
+
+		It("should only show the changes since the last apply", func() {
+			cmd := cmdshoot.NewCmdDiff(factory, cmdshoot.NewDiffOptions(streams))
+			Expect(cmd.RunE(cmd, nil)).To(Succeed())
+			Expect(out.String()).To(Equal(`--- garden-prod1/myshoot (last applied configuration)
++++ garden-prod1/myshoot (generation 4)
+~ spec.kubernetes.version: "1.22.2" -> "1.23.1"
++ spec.provider.workers[name=worker-b]: {"cri":{"name":"containerd"},"machine":{"type":""},"maxSurge":1,"maxUnavailable":0,"maximum":2,"minimum":1,"name":"worker-b","systemComponents":{"allow":true}}
+`))
+			Expect(errOut.String()).To(Equal("The current spec (generation 4) of shoot myshoot has not been reconciled yet, the last reconciled generation is 3\n"))
+		})
+	})
+
+	It("should fail without a last applied configuration", func() {
+		cmd := cmdshoot.NewCmdDiff(factory, cmdshoot.NewDiffOptions(streams))
+		Expect(cmd.RunE(cmd, nil)).To(MatchError("shoot myshoot has no kubectl.kubernetes.io/last-applied-configuration annotation, use --against to compare it with another shoot"))
+	})
+
+	It("should report that there are no differences", func() {
+		cmd := cmdshoot.NewCmdDiff(factory, cmdshoot.NewDiffOptions(streams))
+		Expect(cmd.Flags().Set("against", shootName)).To(Succeed())
+		Expect(cmd.RunE(cmd, nil)).To(Succeed())
+		Expect(out.String()).To(BeEmpty())
+		Expect(errOut.String()).To(Equal("No differences between garden-prod1/myshoot and garden-prod1/myshoot\n"))
+	})
+})
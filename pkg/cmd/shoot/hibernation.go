@@ -0,0 +1,154 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shoot
+
+import (
+	"fmt"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
+)
+
+// NewCmdHibernate returns a new shoot hibernate command.
+func NewCmdHibernate(f util.Factory, o *HibernationOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "hibernate",
+		Short: "Hibernate the targeted shoot",
+		Long: `Hibernate the targeted shoot by enabling its hibernation. The worker nodes and the control plane are scaled down.
+Shoots with purpose production are only hibernated if the --yes-i-really-mean-it flag is set.
+With --wait, the progress of the hibernation is printed until it has finished.`,
+		Example: `# hibernate the targeted shoot and wait until it is hibernated
+gardenctl shoot hibernate --wait`,
+		Args: cobra.NoArgs,
+		RunE: base.WrapRunE(o, f),
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// NewCmdWakeUp returns a new shoot wake-up command.
+func NewCmdWakeUp(f util.Factory, o *HibernationOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "wake-up",
+		Short: "Wake up the targeted hibernated shoot",
+		Long: `Wake up the targeted hibernated shoot by disabling its hibernation.
+Shoots with purpose production are only woken up if the --yes-i-really-mean-it flag is set.
+With --wait, the progress of the wake-up is printed until it has finished.`,
+		Example: `# wake up the targeted shoot and wait up to 20 minutes until it is running
+gardenctl shoot wake-up --wait --timeout 20m`,
+		Args: cobra.NoArgs,
+		RunE: base.WrapRunE(o, f),
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// HibernationOptions is a struct to support shoot hibernate and wake-up commands
+type HibernationOptions struct {
+	base.Options
+	WaitOptions
+
+	// Hibernate is true to hibernate the shoot and false to wake it up
+	Hibernate bool
+
+	// YesIReallyMeanIt allows to hibernate or wake up shoots with purpose production
+	YesIReallyMeanIt bool
+}
+
+// NewHibernateOptions returns initialized HibernationOptions for the shoot hibernate command
+func NewHibernateOptions(ioStreams util.IOStreams) *HibernationOptions {
+	return &HibernationOptions{
+		Options: base.Options{
+			IOStreams: ioStreams,
+		},
+		Hibernate: true,
+	}
+}
+
+// NewWakeUpOptions returns initialized HibernationOptions for the shoot wake-up command
+func NewWakeUpOptions(ioStreams util.IOStreams) *HibernationOptions {
+	return &HibernationOptions{
+		Options: base.Options{
+			IOStreams: ioStreams,
+		},
+		Hibernate: false,
+	}
+}
+
+// AddFlags binds the command options to a given flagset
+func (o *HibernationOptions) AddFlags(flags *pflag.FlagSet) {
+	o.WaitOptions.AddFlags(flags)
+	flags.BoolVar(&o.YesIReallyMeanIt, "yes-i-really-mean-it", o.YesIReallyMeanIt, "Allow to act on shoots with purpose production.")
+}
+
+// Validate validates the provided HibernationOptions
+func (o *HibernationOptions) Validate() error {
+	return o.WaitOptions.Validate()
+}
+
+// Run executes the command
+func (o *HibernationOptions) Run(f util.Factory) error {
+	_, gardenClient, shoot, err := targetedShoot(f)
+	if err != nil {
+		return err
+	}
+
+	key := client.ObjectKeyFromObject(shoot)
+
+	verb, state := "wake up", "awake"
+	if o.Hibernate {
+		verb, state = "hibernate", "hibernated"
+	}
+
+	if isHibernationEnabled(shoot) == o.Hibernate {
+		fmt.Fprintf(o.IOStreams.Out, "Shoot %s is already %s\n", key, state)
+		return o.wait(f, o.IOStreams.Out, gardenClient, key)
+	}
+
+	if shoot.Spec.Purpose != nil && *shoot.Spec.Purpose == gardencorev1beta1.ShootPurposeProduction && !o.YesIReallyMeanIt {
+		return fmt.Errorf("shoot %s has purpose production, set --yes-i-really-mean-it to %s it", key, verb)
+	}
+
+	patch := client.MergeFrom(shoot.DeepCopy())
+
+	if shoot.Spec.Hibernation == nil {
+		shoot.Spec.Hibernation = &gardencorev1beta1.Hibernation{}
+	}
+
+	shoot.Spec.Hibernation.Enabled = pointer.Bool(o.Hibernate)
+
+	if err := gardenClient.RuntimeClient().Patch(f.Context(), shoot, patch); err != nil {
+		return fmt.Errorf("failed to %s shoot %s: %w", verb, key, err)
+	}
+
+	// in dry-run mode the change has already been printed by the client, there is nothing to wait for
+	if isDryRun(f) {
+		return nil
+	}
+
+	if o.Hibernate {
+		fmt.Fprintf(o.IOStreams.Out, "Triggered the hibernation of shoot %s\n", key)
+	} else {
+		fmt.Fprintf(o.IOStreams.Out, "Triggered the wake-up of shoot %s\n", key)
+	}
+
+	return o.wait(f, o.IOStreams.Out, gardenClient, key)
+}
+
+func isHibernationEnabled(shoot *gardencorev1beta1.Shoot) bool {
+	return shoot.Spec.Hibernation != nil && shoot.Spec.Hibernation.Enabled != nil && *shoot.Spec.Hibernation.Enabled
+}
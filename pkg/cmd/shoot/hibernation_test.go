@@ -0,0 +1,111 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shoot_test
+
+import (
+	"context"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	internalfake "github.com/gardener/gardenctl-v2/internal/fake"
+	"github.com/gardener/gardenctl-v2/internal/util"
+	cmdshoot "github.com/gardener/gardenctl-v2/pkg/cmd/shoot"
+)
+
+var _ = Describe("Shoot Hibernation Commands", func() {
+	var (
+		ctrl         *gomock.Controller
+		streams      util.IOStreams
+		out          *util.SafeBytesBuffer
+		factory      *internalfake.Factory
+		gardenClient func() client.Client
+		shoot        *gardencorev1beta1.Shoot
+	)
+
+	purposeDevelopment := gardencorev1beta1.ShootPurposeDevelopment
+	purposeProduction := gardencorev1beta1.ShootPurposeProduction
+
+	BeforeEach(func() {
+		shoot = &gardencorev1beta1.Shoot{
+			ObjectMeta: metav1.ObjectMeta{Name: shootName, Namespace: "garden-prod1"},
+			Spec: gardencorev1beta1.ShootSpec{
+				Purpose: &purposeDevelopment,
+			},
+		}
+
+		streams, _, out, _ = util.NewTestIOStreams()
+
+		ctrl = gomock.NewController(GinkgoT())
+
+		factory, gardenClient = newShootFactory(ctrl, shoot, nil)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	hibernationEnabled := func() *bool {
+		current := &gardencorev1beta1.Shoot{}
+		Expect(gardenClient().Get(context.Background(), client.ObjectKeyFromObject(shoot), current)).To(Succeed())
+
+		if current.Spec.Hibernation == nil {
+			return nil
+		}
+
+		return current.Spec.Hibernation.Enabled
+	}
+
+	It("should hibernate the shoot", func() {
+		cmd := cmdshoot.NewCmdHibernate(factory, cmdshoot.NewHibernateOptions(streams))
+		Expect(cmd.RunE(cmd, nil)).To(Succeed())
+		Expect(out.String()).To(Equal("Triggered the hibernation of shoot garden-prod1/myshoot\n"))
+		Expect(hibernationEnabled()).To(PointTo(BeTrue()))
+	})
+
+	It("should wake up the shoot", func() {
+		shoot.Spec.Hibernation = &gardencorev1beta1.Hibernation{Enabled: pointer.Bool(true)}
+
+		cmd := cmdshoot.NewCmdWakeUp(factory, cmdshoot.NewWakeUpOptions(streams))
+		Expect(cmd.RunE(cmd, nil)).To(Succeed())
+		Expect(out.String()).To(Equal("Triggered the wake-up of shoot garden-prod1/myshoot\n"))
+		Expect(hibernationEnabled()).To(PointTo(BeFalse()))
+	})
+
+	It("should not patch a shoot that is already hibernated", func() {
+		shoot.Spec.Hibernation = &gardencorev1beta1.Hibernation{Enabled: pointer.Bool(true)}
+
+		cmd := cmdshoot.NewCmdHibernate(factory, cmdshoot.NewHibernateOptions(streams))
+		Expect(cmd.RunE(cmd, nil)).To(Succeed())
+		Expect(out.String()).To(Equal("Shoot garden-prod1/myshoot is already hibernated\n"))
+	})
+
+	Context("when the shoot has purpose production", func() {
+		BeforeEach(func() {
+			shoot.Spec.Purpose = &purposeProduction
+		})
+
+		It("should refuse to hibernate the shoot", func() {
+			cmd := cmdshoot.NewCmdHibernate(factory, cmdshoot.NewHibernateOptions(streams))
+			Expect(cmd.RunE(cmd, nil)).To(MatchError("shoot garden-prod1/myshoot has purpose production, set --yes-i-really-mean-it to hibernate it"))
+			Expect(hibernationEnabled()).To(BeNil())
+		})
+
+		It("should hibernate the shoot with --yes-i-really-mean-it", func() {
+			cmd := cmdshoot.NewCmdHibernate(factory, cmdshoot.NewHibernateOptions(streams))
+			Expect(cmd.Flags().Set("yes-i-really-mean-it", "true")).To(Succeed())
+			Expect(cmd.RunE(cmd, nil)).To(Succeed())
+			Expect(hibernationEnabled()).To(PointTo(BeTrue()))
+		})
+	})
+})
@@ -0,0 +1,218 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shoot
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	corev1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
+	"github.com/gardener/gardener/pkg/utils/timewindow"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
+)
+
+// NewCmdMaintain returns a new shoot maintain command.
+func NewCmdMaintain(f util.Factory, o *MaintainOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "maintain",
+		Short: "Show the maintenance settings of the targeted shoot or trigger its maintenance",
+		Long: `Show the maintenance time window and the auto update settings of the targeted shoot.
+With --trigger, the maintenance of the shoot is triggered immediately by annotating it with gardener.cloud/operation=maintain.
+You will be asked for confirmation unless the --force flag is set. With --wait, the progress of the maintenance is printed
+until it has finished.`,
+		Example: `# show the maintenance settings of the targeted shoot
+gardenctl shoot maintain
+
+# trigger the maintenance of the targeted shoot and wait until it has finished
+gardenctl shoot maintain --trigger --wait`,
+		Args: cobra.NoArgs,
+		RunE: base.WrapRunE(o, f),
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// MaintainOptions is a struct to support shoot maintain command
+type MaintainOptions struct {
+	OperationOptions
+
+	// Trigger triggers the maintenance instead of showing the maintenance settings
+	Trigger bool
+}
+
+// NewMaintainOptions returns initialized MaintainOptions
+func NewMaintainOptions(ioStreams util.IOStreams) *MaintainOptions {
+	return &MaintainOptions{
+		OperationOptions: OperationOptions{
+			Options: base.Options{
+				IOStreams: ioStreams,
+			},
+			Operation:   corev1beta1constants.ShootOperationMaintain,
+			description: "maintenance",
+		},
+	}
+}
+
+// AddFlags binds the command options to a given flagset
+func (o *MaintainOptions) AddFlags(flags *pflag.FlagSet) {
+	o.OperationOptions.AddFlags(flags)
+	flags.BoolVar(&o.Trigger, "trigger", o.Trigger, "Trigger the maintenance of the shoot immediately.")
+}
+
+// Validate validates the provided MaintainOptions
+func (o *MaintainOptions) Validate() error {
+	if !o.Trigger && (o.Force || o.Wait) {
+		return errors.New("--force and --wait can only be used together with --trigger")
+	}
+
+	return o.OperationOptions.Validate()
+}
+
+// Run executes the command
+func (o *MaintainOptions) Run(f util.Factory) error {
+	if o.Trigger {
+		return o.OperationOptions.Run(f)
+	}
+
+	_, _, shoot, err := targetedShoot(f)
+	if err != nil {
+		return err
+	}
+
+	printMaintenance(o.IOStreams.Out, shoot)
+
+	return nil
+}
+
+// printMaintenance prints the maintenance settings of the shoot
+func printMaintenance(out io.Writer, shoot *gardencorev1beta1.Shoot) {
+	maintenance := shoot.Spec.Maintenance
+	if maintenance == nil {
+		maintenance = &gardencorev1beta1.Maintenance{}
+	}
+
+	fmt.Fprintf(out, "Shoot:                  %s/%s\n", shoot.Namespace, shoot.Name)
+
+	if maintenance.TimeWindow != nil {
+		fmt.Fprintf(out, "Time Window:            %s - %s\n", maintenance.TimeWindow.Begin, maintenance.TimeWindow.End)
+	} else {
+		fmt.Fprintln(out, "Time Window:            <none>")
+	}
+
+	if maintenance.AutoUpdate != nil {
+		fmt.Fprintf(out, "Kubernetes Auto Update: %t\n", maintenance.AutoUpdate.KubernetesVersion)
+		fmt.Fprintf(out, "Image Auto Update:      %t\n", maintenance.AutoUpdate.MachineImageVersion)
+	}
+
+	confineSpecUpdateRollout := maintenance.ConfineSpecUpdateRollout != nil && *maintenance.ConfineSpecUpdateRollout
+	fmt.Fprintf(out, "Confine Spec Updates:   %t\n", confineSpecUpdateRollout)
+}
+
+// NewCmdSetMaintenanceWindow returns a new shoot set-maintenance-window command.
+func NewCmdSetMaintenanceWindow(f util.Factory, o *SetMaintenanceWindowOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set-maintenance-window BEGIN END",
+		Short: "Set the maintenance time window of the targeted shoot",
+		Long: `Set the maintenance time window of the targeted shoot.
+BEGIN and END have the format HHMMSS+ZONE, e.g. 220000+0100 for 10pm in UTC+1. The time window must be between
+30 minutes and 6 hours long and may span midnight.`,
+		Example: `# set the maintenance time window of the targeted shoot to 10pm - 1am UTC
+gardenctl shoot set-maintenance-window 220000+0000 010000+0000`,
+		Args: cobra.ExactArgs(2),
+		RunE: base.WrapRunE(o, f),
+	}
+
+	return cmd
+}
+
+// SetMaintenanceWindowOptions is a struct to support shoot set-maintenance-window command
+type SetMaintenanceWindowOptions struct {
+	base.Options
+
+	// Begin is the begin of the maintenance time window, e.g. 220000+0100
+	Begin string
+
+	// End is the end of the maintenance time window, e.g. 230000+0100
+	End string
+}
+
+// NewSetMaintenanceWindowOptions returns initialized SetMaintenanceWindowOptions
+func NewSetMaintenanceWindowOptions(ioStreams util.IOStreams) *SetMaintenanceWindowOptions {
+	return &SetMaintenanceWindowOptions{
+		Options: base.Options{
+			IOStreams: ioStreams,
+		},
+	}
+}
+
+// Complete adapts from the command line args to the data required.
+func (o *SetMaintenanceWindowOptions) Complete(f util.Factory, cmd *cobra.Command, args []string) error {
+	if len(args) == 2 {
+		o.Begin = strings.TrimSpace(args[0])
+		o.End = strings.TrimSpace(args[1])
+	}
+
+	return nil
+}
+
+// Validate validates the provided SetMaintenanceWindowOptions
+func (o *SetMaintenanceWindowOptions) Validate() error {
+	window, err := timewindow.ParseMaintenanceTimeWindow(o.Begin, o.End)
+	if err != nil {
+		return fmt.Errorf("invalid maintenance time window, begin and end must have the format HHMMSS+ZONE, e.g. 220000+0100: %w", err)
+	}
+
+	if duration := window.Duration(); duration < gardencorev1beta1.MaintenanceTimeWindowDurationMinimum || duration > gardencorev1beta1.MaintenanceTimeWindowDurationMaximum {
+		return fmt.Errorf("invalid maintenance time window, the duration %s must be between %s and %s",
+			duration, gardencorev1beta1.MaintenanceTimeWindowDurationMinimum, gardencorev1beta1.MaintenanceTimeWindowDurationMaximum)
+	}
+
+	return nil
+}
+
+// Run executes the command
+func (o *SetMaintenanceWindowOptions) Run(f util.Factory) error {
+	_, gardenClient, shoot, err := targetedShoot(f)
+	if err != nil {
+		return err
+	}
+
+	key := client.ObjectKeyFromObject(shoot)
+	patch := client.MergeFrom(shoot.DeepCopy())
+
+	if shoot.Spec.Maintenance == nil {
+		shoot.Spec.Maintenance = &gardencorev1beta1.Maintenance{}
+	}
+
+	shoot.Spec.Maintenance.TimeWindow = &gardencorev1beta1.MaintenanceTimeWindow{
+		Begin: o.Begin,
+		End:   o.End,
+	}
+
+	if err := gardenClient.RuntimeClient().Patch(f.Context(), shoot, patch); err != nil {
+		return fmt.Errorf("failed to set the maintenance time window of shoot %s: %w", key, err)
+	}
+
+	// in dry-run mode the change has already been printed by the client
+	if isDryRun(f) {
+		return nil
+	}
+
+	fmt.Fprintf(o.IOStreams.Out, "Set the maintenance time window of shoot %s to %s - %s\n", key, o.Begin, o.End)
+
+	return nil
+}
@@ -0,0 +1,112 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shoot_test
+
+import (
+	"context"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	internalfake "github.com/gardener/gardenctl-v2/internal/fake"
+	"github.com/gardener/gardenctl-v2/internal/util"
+	cmdshoot "github.com/gardener/gardenctl-v2/pkg/cmd/shoot"
+)
+
+var _ = Describe("Shoot Maintenance Commands", func() {
+	var (
+		ctrl         *gomock.Controller
+		streams      util.IOStreams
+		out          *util.SafeBytesBuffer
+		factory      *internalfake.Factory
+		gardenClient func() client.Client
+		shoot        *gardencorev1beta1.Shoot
+	)
+
+	BeforeEach(func() {
+		shoot = &gardencorev1beta1.Shoot{
+			ObjectMeta: metav1.ObjectMeta{Name: shootName, Namespace: "garden-prod1"},
+			Spec: gardencorev1beta1.ShootSpec{
+				Maintenance: &gardencorev1beta1.Maintenance{
+					TimeWindow: &gardencorev1beta1.MaintenanceTimeWindow{Begin: "220000+0000", End: "230000+0000"},
+					AutoUpdate: &gardencorev1beta1.MaintenanceAutoUpdate{KubernetesVersion: true, MachineImageVersion: false},
+				},
+			},
+		}
+
+		streams, _, out, _ = util.NewTestIOStreams()
+
+		ctrl = gomock.NewController(GinkgoT())
+
+		factory, gardenClient = newShootFactory(ctrl, shoot, nil)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	currentShoot := func() *gardencorev1beta1.Shoot {
+		current := &gardencorev1beta1.Shoot{}
+		Expect(gardenClient().Get(context.Background(), client.ObjectKeyFromObject(shoot), current)).To(Succeed())
+
+		return current
+	}
+
+	Describe("maintain", func() {
+		It("should print the maintenance settings", func() {
+			cmd := cmdshoot.NewCmdMaintain(factory, cmdshoot.NewMaintainOptions(streams))
+			Expect(cmd.RunE(cmd, nil)).To(Succeed())
+			Expect(out.String()).To(Equal(`Shoot:                  garden-prod1/myshoot
+Time Window:            220000+0000 - 230000+0000
+Kubernetes Auto Update: true
+Image Auto Update:      false
+Confine Spec Updates:   false
+`))
+		})
+
+		It("should trigger the maintenance", func() {
+			cmd := cmdshoot.NewCmdMaintain(factory, cmdshoot.NewMaintainOptions(streams))
+			Expect(cmd.Flags().Set("trigger", "true")).To(Succeed())
+			Expect(cmd.Flags().Set("force", "true")).To(Succeed())
+			Expect(cmd.RunE(cmd, nil)).To(Succeed())
+			Expect(out.String()).To(Equal("Triggered the maintenance of shoot garden-prod1/myshoot\n"))
+			Expect(currentShoot().Annotations).To(HaveKeyWithValue("gardener.cloud/operation", "maintain"))
+		})
+
+		It("should reject --wait without --trigger", func() {
+			cmd := cmdshoot.NewCmdMaintain(factory, cmdshoot.NewMaintainOptions(streams))
+			Expect(cmd.Flags().Set("wait", "true")).To(Succeed())
+			Expect(cmd.RunE(cmd, nil)).To(MatchError("--force and --wait can only be used together with --trigger"))
+		})
+	})
+
+	Describe("set-maintenance-window", func() {
+		It("should set the maintenance time window", func() {
+			cmd := cmdshoot.NewCmdSetMaintenanceWindow(factory, cmdshoot.NewSetMaintenanceWindowOptions(streams))
+			Expect(cmd.RunE(cmd, []string{"230000+0100", "010000+0100"})).To(Succeed())
+			Expect(out.String()).To(Equal("Set the maintenance time window of shoot garden-prod1/myshoot to 230000+0100 - 010000+0100\n"))
+
+			maintenance := currentShoot().Spec.Maintenance
+			Expect(maintenance.TimeWindow).To(Equal(&gardencorev1beta1.MaintenanceTimeWindow{Begin: "230000+0100", End: "010000+0100"}))
+			Expect(maintenance.AutoUpdate.KubernetesVersion).To(BeTrue())
+		})
+
+		It("should reject an invalid format", func() {
+			cmd := cmdshoot.NewCmdSetMaintenanceWindow(factory, cmdshoot.NewSetMaintenanceWindowOptions(streams))
+			Expect(cmd.RunE(cmd, []string{"22:00", "23:00"})).To(MatchError(ContainSubstring("begin and end must have the format HHMMSS+ZONE")))
+		})
+
+		It("should reject a time window that is too short", func() {
+			cmd := cmdshoot.NewCmdSetMaintenanceWindow(factory, cmdshoot.NewSetMaintenanceWindowOptions(streams))
+			Expect(cmd.RunE(cmd, []string{"220000+0000", "221000+0000"})).To(MatchError("invalid maintenance time window, the duration 10m0s must be between 30m0s and 6h0m0s"))
+		})
+	})
+})
@@ -0,0 +1,260 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shoot
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	corev1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/gardener/gardenctl-v2/internal/gardenclient"
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/status"
+	"github.com/gardener/gardenctl-v2/pkg/config"
+	"github.com/gardener/gardenctl-v2/pkg/target"
+)
+
+// NewCmdReconcile returns a new shoot reconcile command.
+func NewCmdReconcile(f util.Factory, o *OperationOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reconcile",
+		Short: "Trigger the reconciliation of the targeted shoot",
+		Long: `Trigger the reconciliation of the targeted shoot by annotating it with gardener.cloud/operation=reconcile.
+You will be asked for confirmation unless the --force flag is set. With --wait, the progress of the reconciliation is printed
+until it has finished.`,
+		Example: `# trigger the reconciliation of the targeted shoot
+gardenctl shoot reconcile
+
+# trigger the reconciliation without confirmation and wait up to 30 minutes until it has finished
+gardenctl shoot reconcile --force --wait --timeout 30m`,
+		Args: cobra.NoArgs,
+		RunE: base.WrapRunE(o, f),
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// NewCmdRetry returns a new shoot retry command.
+func NewCmdRetry(f util.Factory, o *OperationOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "retry",
+		Short: "Retry the failed last operation of the targeted shoot",
+		Long: `Retry the failed last operation of the targeted shoot by annotating it with gardener.cloud/operation=retry.
+You will be asked for confirmation unless the --force flag is set. With --wait, the progress of the operation is printed
+until it has finished.`,
+		Example: `# retry the failed last operation of the targeted shoot
+gardenctl shoot retry
+
+# retry without confirmation and wait until the operation has finished
+gardenctl shoot retry --force --wait`,
+		Args: cobra.NoArgs,
+		RunE: base.WrapRunE(o, f),
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// OperationOptions is a struct to support the commands that trigger an operation on the targeted shoot
+type OperationOptions struct {
+	base.Options
+	WaitOptions
+
+	// Operation is the value of the gardener.cloud/operation annotation
+	Operation string
+
+	// Force skips the confirmation prompt
+	Force bool
+
+	// description is used in the confirmation prompt and the output, e.g. "reconciliation"
+	description string
+
+	// precondition returns an error if the operation cannot be triggered for the shoot
+	precondition func(shoot *gardencorev1beta1.Shoot) error
+}
+
+// NewReconcileOptions returns initialized OperationOptions for the shoot reconcile command
+func NewReconcileOptions(ioStreams util.IOStreams) *OperationOptions {
+	return &OperationOptions{
+		Options: base.Options{
+			IOStreams: ioStreams,
+		},
+		Operation:   corev1beta1constants.GardenerOperationReconcile,
+		description: "reconciliation",
+	}
+}
+
+// NewRetryOptions returns initialized OperationOptions for the shoot retry command
+func NewRetryOptions(ioStreams util.IOStreams) *OperationOptions {
+	return &OperationOptions{
+		Options: base.Options{
+			IOStreams: ioStreams,
+		},
+		Operation:   corev1beta1constants.ShootOperationRetry,
+		description: "retry of the failed last operation",
+		precondition: func(shoot *gardencorev1beta1.Shoot) error {
+			op := shoot.Status.LastOperation
+			if op == nil || op.State != gardencorev1beta1.LastOperationStateFailed {
+				return fmt.Errorf("the last operation of shoot %s/%s has not failed, only failed operations can be retried", shoot.Namespace, shoot.Name)
+			}
+
+			return nil
+		},
+	}
+}
+
+// AddFlags binds the command options to a given flagset
+func (o *OperationOptions) AddFlags(flags *pflag.FlagSet) {
+	o.WaitOptions.AddFlags(flags)
+	flags.BoolVarP(&o.Force, "force", "f", o.Force, "Trigger the operation without asking for confirmation.")
+}
+
+// Validate validates the provided OperationOptions
+func (o *OperationOptions) Validate() error {
+	return o.WaitOptions.Validate()
+}
+
+// Run executes the command
+func (o *OperationOptions) Run(f util.Factory) error {
+	ctx := f.Context()
+
+	currentTarget, gardenClient, shoot, err := targetedShoot(f)
+	if err != nil {
+		return err
+	}
+
+	key := client.ObjectKeyFromObject(shoot)
+
+	if o.precondition != nil {
+		if err := o.precondition(shoot); err != nil {
+			return err
+		}
+	}
+
+	if !o.Force {
+		confirmed, err := util.Confirm(o.IOStreams, fmt.Sprintf("Do you really want to trigger the %s of shoot %s in garden %s?", o.description, key, currentTarget.GardenName()))
+		if err != nil {
+			return err
+		}
+
+		if !confirmed {
+			fmt.Fprintln(o.IOStreams.Out, "Aborted")
+			return nil
+		}
+	}
+
+	patch := client.MergeFrom(shoot.DeepCopy())
+	metav1.SetMetaDataAnnotation(&shoot.ObjectMeta, corev1beta1constants.GardenerOperation, o.Operation)
+
+	if err := gardenClient.RuntimeClient().Patch(ctx, shoot, patch); err != nil {
+		return fmt.Errorf("failed to annotate shoot %s with %s=%s: %w", key, corev1beta1constants.GardenerOperation, o.Operation, err)
+	}
+
+	// in dry-run mode the change has already been printed by the client, there is nothing to wait for
+	if isDryRun(f) {
+		return nil
+	}
+
+	fmt.Fprintf(o.IOStreams.Out, "Triggered the %s of shoot %s\n", o.description, key)
+
+	return o.wait(f, o.IOStreams.Out, gardenClient, key)
+}
+
+// WaitOptions are the options to wait for an operation of the targeted shoot
+type WaitOptions struct {
+	// Wait prints the progress of the operation until it has finished
+	Wait bool
+
+	// Timeout is the maximum duration to wait for the operation, zero means no timeout
+	Timeout time.Duration
+}
+
+// AddFlags binds the wait options to a given flagset
+func (o *WaitOptions) AddFlags(flags *pflag.FlagSet) {
+	flags.BoolVarP(&o.Wait, "wait", "w", o.Wait, "Print the progress of the operation until it has finished.")
+	flags.DurationVar(&o.Timeout, "timeout", o.Timeout, "Maximum duration to wait for the operation, e.g. 30m. Zero means no timeout.")
+}
+
+// Validate validates the provided WaitOptions
+func (o *WaitOptions) Validate() error {
+	if o.Timeout < 0 {
+		return errors.New("the timeout must not be negative")
+	}
+
+	if o.Timeout > 0 && !o.Wait {
+		return errors.New("--timeout can only be used together with --wait")
+	}
+
+	return nil
+}
+
+// wait prints the progress of the operation of the shoot until it has finished, if Wait is set
+func (o *WaitOptions) wait(f util.Factory, out io.Writer, gardenClient gardenclient.Client, key client.ObjectKey) error {
+	if !o.Wait {
+		return nil
+	}
+
+	return status.WatchOperation(f.Context(), out, f.Clock(), gardenClient, key, o.Timeout)
+}
+
+// isDryRun returns true if the changes to the shoot are only printed
+func isDryRun(f util.Factory) bool {
+	manager, err := f.Manager()
+
+	return err == nil && manager.Configuration().IsDryRun()
+}
+
+// isServerDryRun returns true if the changes to the shoot are sent to the garden cluster as dry-run requests
+func isServerDryRun(f util.Factory) bool {
+	manager, err := f.Manager()
+	if err != nil || !manager.Configuration().IsDryRun() {
+		return false
+	}
+
+	return manager.Configuration().DryRun.Strategy == config.DryRunServer
+}
+
+// targetedShoot returns the current target, the client of the targeted garden and the targeted shoot
+func targetedShoot(f util.Factory) (target.Target, gardenclient.Client, *gardencorev1beta1.Shoot, error) {
+	manager, err := f.Manager()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	currentTarget, err := manager.CurrentTarget()
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to get current target: %w", err)
+	}
+
+	if currentTarget.ShootName() == "" {
+		return nil, nil, nil, errors.New("no Shoot cluster targeted")
+	}
+
+	gardenClient, err := manager.GardenClient(currentTarget.GardenName())
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to create garden cluster client: %w", err)
+	}
+
+	shoot, err := util.ShootForTarget(f.Context(), gardenClient, currentTarget)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return currentTarget, gardenClient, shoot, nil
+}
@@ -0,0 +1,133 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shoot_test
+
+import (
+	"context"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	corev1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	internalfake "github.com/gardener/gardenctl-v2/internal/fake"
+	"github.com/gardener/gardenctl-v2/internal/util"
+	cmdshoot "github.com/gardener/gardenctl-v2/pkg/cmd/shoot"
+)
+
+var _ = Describe("Shoot Operation Commands", func() {
+	var (
+		ctrl         *gomock.Controller
+		streams      util.IOStreams
+		in           *util.SafeBytesBuffer
+		out          *util.SafeBytesBuffer
+		factory      *internalfake.Factory
+		gardenClient func() client.Client
+		shoot        *gardencorev1beta1.Shoot
+	)
+
+	BeforeEach(func() {
+		shoot = &gardencorev1beta1.Shoot{
+			ObjectMeta: metav1.ObjectMeta{Name: shootName, Namespace: "garden-prod1"},
+			Status: gardencorev1beta1.ShootStatus{
+				LastOperation: &gardencorev1beta1.LastOperation{
+					Type:  gardencorev1beta1.LastOperationTypeReconcile,
+					State: gardencorev1beta1.LastOperationStateSucceeded,
+				},
+			},
+		}
+
+		streams, in, out, _ = util.NewTestIOStreams()
+
+		ctrl = gomock.NewController(GinkgoT())
+
+		factory, gardenClient = newShootFactory(ctrl, shoot, nil)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	operationAnnotation := func() string {
+		current := &gardencorev1beta1.Shoot{}
+		Expect(gardenClient().Get(context.Background(), client.ObjectKeyFromObject(shoot), current)).To(Succeed())
+
+		return current.Annotations[corev1beta1constants.GardenerOperation]
+	}
+
+	Describe("reconcile", func() {
+		It("should annotate the shoot after confirmation", func() {
+			in.Write([]byte("y\n"))
+
+			cmd := cmdshoot.NewCmdReconcile(factory, cmdshoot.NewReconcileOptions(streams))
+			Expect(cmd.RunE(cmd, nil)).To(Succeed())
+			Expect(out.String()).To(Equal("Do you really want to trigger the reconciliation of shoot garden-prod1/myshoot in garden mygarden? [y/N]: " +
+				"Triggered the reconciliation of shoot garden-prod1/myshoot\n"))
+			Expect(operationAnnotation()).To(Equal("reconcile"))
+		})
+
+		It("should not annotate the shoot if the confirmation is denied", func() {
+			in.Write([]byte("n\n"))
+
+			cmd := cmdshoot.NewCmdReconcile(factory, cmdshoot.NewReconcileOptions(streams))
+			Expect(cmd.RunE(cmd, nil)).To(Succeed())
+			Expect(out.String()).To(HaveSuffix("Aborted\n"))
+			Expect(operationAnnotation()).To(BeEmpty())
+		})
+
+		It("should reject a timeout without wait", func() {
+			cmd := cmdshoot.NewCmdReconcile(factory, cmdshoot.NewReconcileOptions(streams))
+			Expect(cmd.Flags().Set("timeout", "10m")).To(Succeed())
+			Expect(cmd.RunE(cmd, nil)).To(MatchError("--timeout can only be used together with --wait"))
+		})
+	})
+
+	Describe("retry", func() {
+		It("should annotate a shoot whose last operation failed", func() {
+			shoot.Status.LastOperation.State = gardencorev1beta1.LastOperationStateFailed
+
+			cmd := cmdshoot.NewCmdRetry(factory, cmdshoot.NewRetryOptions(streams))
+			Expect(cmd.Flags().Set("force", "true")).To(Succeed())
+			Expect(cmd.RunE(cmd, nil)).To(Succeed())
+			Expect(out.String()).To(Equal("Triggered the retry of the failed last operation of shoot garden-prod1/myshoot\n"))
+			Expect(operationAnnotation()).To(Equal("retry"))
+		})
+
+		It("should fail if the last operation has not failed", func() {
+			cmd := cmdshoot.NewCmdRetry(factory, cmdshoot.NewRetryOptions(streams))
+			Expect(cmd.Flags().Set("force", "true")).To(Succeed())
+			Expect(cmd.RunE(cmd, nil)).To(MatchError(ContainSubstring("only failed operations can be retried")))
+			Expect(operationAnnotation()).To(BeEmpty())
+		})
+	})
+
+	Describe("rotate-credentials", func() {
+		DescribeTable("should annotate the shoot with the operation of the kind",
+			func(kind, operation string) {
+				cmd := cmdshoot.NewCmdRotateCredentials(factory, cmdshoot.NewRotateCredentialsOptions(streams))
+				Expect(cmd.Flags().Set("force", "true")).To(Succeed())
+				Expect(cmd.Flags().Set("kind", kind)).To(Succeed())
+				Expect(cmd.RunE(cmd, nil)).To(Succeed())
+				Expect(operationAnnotation()).To(Equal(operation))
+			},
+			Entry("all", "all", "rotate-credentials-start"),
+			Entry("ca", "ca", "rotate-ca-start"),
+			Entry("observability", "observability", "rotate-observability-credentials"),
+			Entry("ssh-keypair", "ssh-keypair", "rotate-ssh-keypair"),
+		)
+
+		It("should reject an invalid kind", func() {
+			cmd := cmdshoot.NewCmdRotateCredentials(factory, cmdshoot.NewRotateCredentialsOptions(streams))
+			Expect(cmd.Flags().Set("kind", "etcd")).To(Succeed())
+			Expect(cmd.RunE(cmd, nil)).To(MatchError(`kind "etcd" is invalid, must be one of all, ca, observability, ssh-keypair`))
+		})
+	})
+})
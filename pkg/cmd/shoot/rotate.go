@@ -0,0 +1,127 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shoot
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
+)
+
+const (
+	// CredentialsKindAll rotates all credentials of the shoot
+	CredentialsKindAll = "all"
+	// CredentialsKindCA rotates the certificate authorities of the shoot
+	CredentialsKindCA = "ca"
+	// CredentialsKindObservability rotates the credentials of the observability components of the shoot
+	CredentialsKindObservability = "observability"
+	// CredentialsKindSSHKeypair rotates the SSH keypair of the shoot nodes
+	CredentialsKindSSHKeypair = "ssh-keypair"
+)
+
+// rotation is the operation that rotates a kind of credentials
+type rotation struct {
+	// operation is the value of the gardener.cloud/operation annotation
+	operation string
+	// description is used in the confirmation prompt and the output
+	description string
+}
+
+// rotations maps the credentials kinds to their rotation. The operations for all credentials, the CAs and the
+// observability credentials are not part of the Gardener API version gardenctl is built with, hence they are defined here.
+var rotations = map[string]rotation{
+	CredentialsKindAll:           {"rotate-credentials-start", "rotation of all credentials"},
+	CredentialsKindCA:            {"rotate-ca-start", "rotation of the certificate authorities"},
+	CredentialsKindObservability: {"rotate-observability-credentials", "rotation of the observability credentials"},
+	CredentialsKindSSHKeypair:    {corev1beta1constants.ShootOperationRotateSSHKeypair, "rotation of the SSH keypair"},
+}
+
+// NewCmdRotateCredentials returns a new shoot rotate-credentials command.
+func NewCmdRotateCredentials(f util.Factory, o *RotateCredentialsOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rotate-credentials",
+		Short: "Rotate credentials of the targeted shoot",
+		Long: `Rotate credentials of the targeted shoot by annotating it with the corresponding gardener.cloud/operation.
+The rotation of all credentials and of the certificate authorities is only started, it has to be completed with a
+further operation after all clients have been updated, see the Gardener documentation on credentials rotation.
+You will be asked for confirmation unless the --force flag is set. With --wait, the progress of the operation is printed
+until it has finished.`,
+		Example: `# start the rotation of all credentials of the targeted shoot
+gardenctl shoot rotate-credentials
+
+# rotate the SSH keypair of the shoot nodes without confirmation and wait until it has finished
+gardenctl shoot rotate-credentials --kind ssh-keypair --force --wait`,
+		Args: cobra.NoArgs,
+		RunE: base.WrapRunE(o, f),
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// RotateCredentialsOptions is a struct to support shoot rotate-credentials command
+type RotateCredentialsOptions struct {
+	OperationOptions
+
+	// Kind is the kind of credentials that are rotated
+	Kind string
+}
+
+// NewRotateCredentialsOptions returns initialized RotateCredentialsOptions
+func NewRotateCredentialsOptions(ioStreams util.IOStreams) *RotateCredentialsOptions {
+	return &RotateCredentialsOptions{
+		OperationOptions: OperationOptions{
+			Options: base.Options{
+				IOStreams: ioStreams,
+			},
+		},
+		Kind: CredentialsKindAll,
+	}
+}
+
+// AddFlags binds the command options to a given flagset
+func (o *RotateCredentialsOptions) AddFlags(flags *pflag.FlagSet) {
+	o.OperationOptions.AddFlags(flags)
+	flags.StringVar(&o.Kind, "kind", o.Kind, fmt.Sprintf("Kind of credentials to rotate, one of %s.", strings.Join(credentialsKinds(), ", ")))
+}
+
+// Complete adapts from the command line args to the data required.
+func (o *RotateCredentialsOptions) Complete(f util.Factory, cmd *cobra.Command, args []string) error {
+	r := rotations[o.Kind]
+	o.Operation = r.operation
+	o.description = r.description
+
+	return nil
+}
+
+// Validate validates the provided RotateCredentialsOptions
+func (o *RotateCredentialsOptions) Validate() error {
+	if _, ok := rotations[o.Kind]; !ok {
+		return fmt.Errorf("kind %q is invalid, must be one of %s", o.Kind, strings.Join(credentialsKinds(), ", "))
+	}
+
+	return o.OperationOptions.Validate()
+}
+
+func credentialsKinds() []string {
+	kinds := make([]string, 0, len(rotations))
+	for kind := range rotations {
+		kinds = append(kinds, kind)
+	}
+
+	sort.Strings(kinds)
+
+	return kinds
+}
@@ -0,0 +1,257 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shoot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/gardener/gardenctl-v2/internal/gardenclient"
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
+)
+
+// NewCmdScaleWorker returns a new shoot scale-worker command.
+func NewCmdScaleWorker(f util.Factory, o *ScaleWorkerOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "scale-worker POOL",
+		Short: "Scale a worker pool of the targeted shoot",
+		Long: `Scale a worker pool of the targeted shoot by setting its minimum and maximum number of nodes.
+Use --replicas to set both to the same value for a fixed size. Before the shoot is patched, the machine type of the
+worker pool is checked against the cloud profile, i.e. it must be usable and available in all zones of the worker pool.
+With --wait, the progress of the reconciliation is printed until it has finished.`,
+		Example: `# allow the autoscaler to scale the worker pool between 3 and 10 nodes
+gardenctl shoot scale-worker worker-1 --min 3 --max 10
+
+# scale the worker pool to exactly 5 nodes and wait until the shoot has been reconciled
+gardenctl shoot scale-worker worker-1 --replicas 5 --wait`,
+		Args: cobra.ExactArgs(1),
+		RunE: base.WrapRunE(o, f),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) > 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+
+			return workerPoolNames(f), cobra.ShellCompDirectiveNoFileComp
+		},
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// ScaleWorkerOptions is a struct to support shoot scale-worker command
+type ScaleWorkerOptions struct {
+	base.Options
+	WaitOptions
+
+	// Pool is the name of the worker pool
+	Pool string
+
+	// Min is the minimum number of nodes, a negative value keeps the current minimum
+	Min int32
+	// Max is the maximum number of nodes, a negative value keeps the current maximum
+	Max int32
+	// Replicas sets the minimum and the maximum to the same value, a negative value means it is not set
+	Replicas int32
+}
+
+// NewScaleWorkerOptions returns initialized ScaleWorkerOptions
+func NewScaleWorkerOptions(ioStreams util.IOStreams) *ScaleWorkerOptions {
+	return &ScaleWorkerOptions{
+		Options: base.Options{
+			IOStreams: ioStreams,
+		},
+		Min:      -1,
+		Max:      -1,
+		Replicas: -1,
+	}
+}
+
+// AddFlags binds the command options to a given flagset
+func (o *ScaleWorkerOptions) AddFlags(flags *pflag.FlagSet) {
+	o.WaitOptions.AddFlags(flags)
+	flags.Int32Var(&o.Min, "min", o.Min, "Minimum number of nodes of the worker pool.")
+	flags.Int32Var(&o.Max, "max", o.Max, "Maximum number of nodes of the worker pool.")
+	flags.Int32Var(&o.Replicas, "replicas", o.Replicas, "Fixed number of nodes of the worker pool, sets the minimum and the maximum.")
+}
+
+// Complete adapts from the command line args to the data required.
+func (o *ScaleWorkerOptions) Complete(f util.Factory, cmd *cobra.Command, args []string) error {
+	if len(args) > 0 {
+		o.Pool = args[0]
+	}
+
+	return nil
+}
+
+// Validate validates the provided ScaleWorkerOptions
+func (o *ScaleWorkerOptions) Validate() error {
+	if o.Pool == "" {
+		return errors.New("the worker pool name must not be empty")
+	}
+
+	if o.Replicas >= 0 && (o.Min >= 0 || o.Max >= 0) {
+		return errors.New("--replicas cannot be used together with --min or --max")
+	}
+
+	if o.Min < 0 && o.Max < 0 && o.Replicas < 0 {
+		return errors.New("at least one of --min, --max or --replicas must be set")
+	}
+
+	if o.Min >= 0 && o.Max >= 0 && o.Min > o.Max {
+		return fmt.Errorf("the minimum %d must not be greater than the maximum %d", o.Min, o.Max)
+	}
+
+	return o.WaitOptions.Validate()
+}
+
+// Run executes the command
+func (o *ScaleWorkerOptions) Run(f util.Factory) error {
+	_, gardenClient, shoot, err := targetedShoot(f)
+	if err != nil {
+		return err
+	}
+
+	key := client.ObjectKeyFromObject(shoot)
+	patch := client.MergeFrom(shoot.DeepCopy())
+
+	worker := findWorker(shoot, o.Pool)
+	if worker == nil {
+		return fmt.Errorf("shoot %s has no worker pool %q, available worker pools: %s", key, o.Pool, strings.Join(workerNames(shoot), ", "))
+	}
+
+	oldMin, oldMax := worker.Minimum, worker.Maximum
+
+	if o.Replicas >= 0 {
+		worker.Minimum, worker.Maximum = o.Replicas, o.Replicas
+	}
+
+	if o.Min >= 0 {
+		worker.Minimum = o.Min
+	}
+
+	if o.Max >= 0 {
+		worker.Maximum = o.Max
+	}
+
+	if worker.Minimum > worker.Maximum {
+		return fmt.Errorf("the minimum %d of worker pool %q must not be greater than its maximum %d", worker.Minimum, o.Pool, worker.Maximum)
+	}
+
+	if worker.Minimum == oldMin && worker.Maximum == oldMax {
+		fmt.Fprintf(o.IOStreams.Out, "Worker pool %q of shoot %s already has a minimum of %d and a maximum of %d nodes\n", o.Pool, key, oldMin, oldMax)
+		return nil
+	}
+
+	ctx := f.Context()
+
+	// machines are only created if the maximum is greater than zero
+	if worker.Maximum > 0 {
+		if err := checkMachineType(ctx, gardenClient, shoot, worker); err != nil {
+			return err
+		}
+	}
+
+	if err := gardenClient.RuntimeClient().Patch(ctx, shoot, patch); err != nil {
+		return fmt.Errorf("failed to scale worker pool %q of shoot %s: %w", o.Pool, key, err)
+	}
+
+	// in dry-run mode the change has already been printed by the client, there is nothing to wait for
+	if isDryRun(f) {
+		return nil
+	}
+
+	fmt.Fprintf(o.IOStreams.Out, "Scaled worker pool %q of shoot %s: minimum %d -> %d, maximum %d -> %d\n", o.Pool, key, oldMin, worker.Minimum, oldMax, worker.Maximum)
+
+	return o.wait(f, o.IOStreams.Out, gardenClient, key)
+}
+
+// checkMachineType checks that the machine type of the worker pool is usable and available in its zones
+func checkMachineType(ctx context.Context, gardenClient gardenclient.Client, shoot *gardencorev1beta1.Shoot, worker *gardencorev1beta1.Worker) error {
+	cloudProfile, err := gardenClient.GetCloudProfile(ctx, shoot.Spec.CloudProfileName)
+	if err != nil {
+		return err
+	}
+
+	machineType := worker.Machine.Type
+	found := false
+
+	for _, mt := range cloudProfile.Spec.MachineTypes {
+		if mt.Name != machineType {
+			continue
+		}
+
+		if mt.Usable != nil && !*mt.Usable {
+			return fmt.Errorf("machine type %q of worker pool %q is not usable according to cloud profile %s", machineType, worker.Name, cloudProfile.Name)
+		}
+
+		found = true
+
+		break
+	}
+
+	if !found {
+		return fmt.Errorf("machine type %q of worker pool %q is not offered by cloud profile %s", machineType, worker.Name, cloudProfile.Name)
+	}
+
+	for _, region := range cloudProfile.Spec.Regions {
+		if region.Name != shoot.Spec.Region {
+			continue
+		}
+
+		for _, zone := range region.Zones {
+			if !util.ContainsString(worker.Zones, zone.Name) {
+				continue
+			}
+
+			if util.ContainsString(zone.UnavailableMachineTypes, machineType) {
+				return fmt.Errorf("machine type %q of worker pool %q is not available in zone %s of region %s", machineType, worker.Name, zone.Name, region.Name)
+			}
+		}
+	}
+
+	return nil
+}
+
+func findWorker(shoot *gardencorev1beta1.Shoot, name string) *gardencorev1beta1.Worker {
+	for i := range shoot.Spec.Provider.Workers {
+		if shoot.Spec.Provider.Workers[i].Name == name {
+			return &shoot.Spec.Provider.Workers[i]
+		}
+	}
+
+	return nil
+}
+
+func workerNames(shoot *gardencorev1beta1.Shoot) []string {
+	names := make([]string, 0, len(shoot.Spec.Provider.Workers))
+
+	for _, worker := range shoot.Spec.Provider.Workers {
+		names = append(names, worker.Name)
+	}
+
+	return names
+}
+
+// workerPoolNames returns the names of the worker pools of the targeted shoot for the shell completion
+func workerPoolNames(f util.Factory) []string {
+	_, _, shoot, err := targetedShoot(f)
+	if err != nil {
+		return nil
+	}
+
+	return workerNames(shoot)
+}
@@ -0,0 +1,149 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shoot_test
+
+import (
+	"context"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	internalfake "github.com/gardener/gardenctl-v2/internal/fake"
+	"github.com/gardener/gardenctl-v2/internal/util"
+	cmdshoot "github.com/gardener/gardenctl-v2/pkg/cmd/shoot"
+)
+
+var _ = Describe("Shoot Scale Worker Command", func() {
+	var (
+		ctrl         *gomock.Controller
+		streams      util.IOStreams
+		out          *util.SafeBytesBuffer
+		factory      *internalfake.Factory
+		gardenClient func() client.Client
+		shoot        *gardencorev1beta1.Shoot
+		cloudProfile *gardencorev1beta1.CloudProfile
+	)
+
+	BeforeEach(func() {
+		cloudProfile = &gardencorev1beta1.CloudProfile{
+			ObjectMeta: metav1.ObjectMeta{Name: "aws"},
+			Spec: gardencorev1beta1.CloudProfileSpec{
+				MachineTypes: []gardencorev1beta1.MachineType{
+					{Name: "m5.large"},
+					{Name: "m5.xlarge"},
+				},
+				Regions: []gardencorev1beta1.Region{{
+					Name: "eu-west-1",
+					Zones: []gardencorev1beta1.AvailabilityZone{
+						{Name: "eu-west-1a"},
+						{Name: "eu-west-1b", UnavailableMachineTypes: []string{"m5.xlarge"}},
+					},
+				}},
+			},
+		}
+
+		shoot = &gardencorev1beta1.Shoot{
+			ObjectMeta: metav1.ObjectMeta{Name: shootName, Namespace: "garden-prod1"},
+			Spec: gardencorev1beta1.ShootSpec{
+				CloudProfileName: "aws",
+				Region:           "eu-west-1",
+				Provider: gardencorev1beta1.Provider{
+					Type: "aws",
+					Workers: []gardencorev1beta1.Worker{
+						{Name: "worker-a", Minimum: 1, Maximum: 3, Machine: gardencorev1beta1.Machine{Type: "m5.large"}, Zones: []string{"eu-west-1a", "eu-west-1b"}},
+						{Name: "worker-b", Minimum: 0, Maximum: 0, Machine: gardencorev1beta1.Machine{Type: "m5.xlarge"}, Zones: []string{"eu-west-1b"}},
+					},
+				},
+			},
+		}
+
+		streams, _, out, _ = util.NewTestIOStreams()
+
+		ctrl = gomock.NewController(GinkgoT())
+
+		factory, gardenClient = newShootFactory(ctrl, shoot, nil, cloudProfile)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	getWorker := func(name string) gardencorev1beta1.Worker {
+		current := &gardencorev1beta1.Shoot{}
+		Expect(gardenClient().Get(context.Background(), client.ObjectKeyFromObject(shoot), current)).To(Succeed())
+
+		for _, worker := range current.Spec.Provider.Workers {
+			if worker.Name == name {
+				return worker
+			}
+		}
+
+		Fail("worker pool " + name + " not found")
+
+		return gardencorev1beta1.Worker{}
+	}
+
+	It("should set the minimum and maximum of the worker pool", func() {
+		cmd := cmdshoot.NewCmdScaleWorker(factory, cmdshoot.NewScaleWorkerOptions(streams))
+		Expect(cmd.Flags().Set("max", "10")).To(Succeed())
+		Expect(cmd.RunE(cmd, []string{"worker-a"})).To(Succeed())
+		Expect(out.String()).To(Equal("Scaled worker pool \"worker-a\" of shoot garden-prod1/myshoot: minimum 1 -> 1, maximum 3 -> 10\n"))
+
+		worker := getWorker("worker-a")
+		Expect(worker.Minimum).To(BeEquivalentTo(1))
+		Expect(worker.Maximum).To(BeEquivalentTo(10))
+	})
+
+	It("should set a fixed size with --replicas", func() {
+		cmd := cmdshoot.NewCmdScaleWorker(factory, cmdshoot.NewScaleWorkerOptions(streams))
+		Expect(cmd.Flags().Set("replicas", "5")).To(Succeed())
+		Expect(cmd.RunE(cmd, []string{"worker-a"})).To(Succeed())
+
+		worker := getWorker("worker-a")
+		Expect(worker.Minimum).To(BeEquivalentTo(5))
+		Expect(worker.Maximum).To(BeEquivalentTo(5))
+	})
+
+	It("should reject --replicas together with --min", func() {
+		cmd := cmdshoot.NewCmdScaleWorker(factory, cmdshoot.NewScaleWorkerOptions(streams))
+		Expect(cmd.Flags().Set("replicas", "5")).To(Succeed())
+		Expect(cmd.Flags().Set("min", "2")).To(Succeed())
+		Expect(cmd.RunE(cmd, []string{"worker-a"})).To(MatchError("--replicas cannot be used together with --min or --max"))
+	})
+
+	It("should reject a minimum that is greater than the current maximum", func() {
+		cmd := cmdshoot.NewCmdScaleWorker(factory, cmdshoot.NewScaleWorkerOptions(streams))
+		Expect(cmd.Flags().Set("min", "4")).To(Succeed())
+		Expect(cmd.RunE(cmd, []string{"worker-a"})).To(MatchError("the minimum 4 of worker pool \"worker-a\" must not be greater than its maximum 3"))
+	})
+
+	It("should fail for an unknown worker pool", func() {
+		cmd := cmdshoot.NewCmdScaleWorker(factory, cmdshoot.NewScaleWorkerOptions(streams))
+		Expect(cmd.Flags().Set("max", "5")).To(Succeed())
+		Expect(cmd.RunE(cmd, []string{"worker-c"})).To(MatchError("shoot garden-prod1/myshoot has no worker pool \"worker-c\", available worker pools: worker-a, worker-b"))
+	})
+
+	It("should refuse to scale up a worker pool whose machine type is not available in its zones", func() {
+		cmd := cmdshoot.NewCmdScaleWorker(factory, cmdshoot.NewScaleWorkerOptions(streams))
+		Expect(cmd.Flags().Set("max", "2")).To(Succeed())
+		Expect(cmd.RunE(cmd, []string{"worker-b"})).To(MatchError("machine type \"m5.xlarge\" of worker pool \"worker-b\" is not available in zone eu-west-1b of region eu-west-1"))
+		Expect(getWorker("worker-b").Maximum).To(BeEquivalentTo(0))
+	})
+
+	It("should refuse to scale up a worker pool whose machine type is not usable", func() {
+		cloudProfile.Spec.MachineTypes[0].Usable = pointer.Bool(false)
+
+		cmd := cmdshoot.NewCmdScaleWorker(factory, cmdshoot.NewScaleWorkerOptions(streams))
+		Expect(cmd.Flags().Set("max", "5")).To(Succeed())
+		Expect(cmd.RunE(cmd, []string{"worker-a"})).To(MatchError("machine type \"m5.large\" of worker pool \"worker-a\" is not usable according to cloud profile aws"))
+	})
+})
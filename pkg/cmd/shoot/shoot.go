@@ -0,0 +1,36 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shoot
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/gardener/gardenctl-v2/internal/util"
+)
+
+// NewCmdShoot returns a new shoot command.
+func NewCmdShoot(f util.Factory, ioStreams util.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "shoot",
+		Short: "Trigger Gardener operations on the targeted shoot, e.g. reconcile or hibernate it",
+	}
+
+	cmd.AddCommand(NewCmdReconcile(f, NewReconcileOptions(ioStreams)))
+	cmd.AddCommand(NewCmdRetry(f, NewRetryOptions(ioStreams)))
+	cmd.AddCommand(NewCmdRotateCredentials(f, NewRotateCredentialsOptions(ioStreams)))
+	cmd.AddCommand(NewCmdHibernate(f, NewHibernateOptions(ioStreams)))
+	cmd.AddCommand(NewCmdWakeUp(f, NewWakeUpOptions(ioStreams)))
+	cmd.AddCommand(NewCmdMaintain(f, NewMaintainOptions(ioStreams)))
+	cmd.AddCommand(NewCmdSetMaintenanceWindow(f, NewSetMaintenanceWindowOptions(ioStreams)))
+	cmd.AddCommand(NewCmdDiff(f, NewDiffOptions(ioStreams)))
+	cmd.AddCommand(NewCmdCreate(f, NewCreateOptions(ioStreams)))
+	cmd.AddCommand(NewCmdDelete(f, NewDeleteOptions(ioStreams)))
+	cmd.AddCommand(NewCmdScaleWorker(f, NewScaleWorkerOptions(ioStreams)))
+	cmd.AddCommand(NewCmdUpgrade(f, NewUpgradeOptions(ioStreams)))
+
+	return cmd
+}
@@ -0,0 +1,84 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shoot_test
+
+import (
+	"testing"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	internalfake "github.com/gardener/gardenctl-v2/internal/fake"
+	"github.com/gardener/gardenctl-v2/pkg/config"
+	"github.com/gardener/gardenctl-v2/pkg/target"
+	targetmocks "github.com/gardener/gardenctl-v2/pkg/target/mocks"
+)
+
+const (
+	gardenName  = "mygarden"
+	projectName = "prod1"
+	shootName   = "myshoot"
+)
+
+func init() {
+	utilruntime.Must(gardencorev1beta1.AddToScheme(scheme.Scheme))
+}
+
+func TestCommand(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Shoot Command Test Suite")
+}
+
+// newShootFactory returns a factory that targets the given shoot of project prod1 in garden mygarden and a
+// function that returns the garden client. The garden client is created lazily with the project, the shoot
+// and the given objects, so that the tests can modify them beforehand. If wrap is not nil, the garden client
+// is wrapped with it, e.g. to emulate the behavior of the API server.
+func newShootFactory(ctrl *gomock.Controller, shoot *gardencorev1beta1.Shoot, wrap func(client.Client) client.Client, objs ...client.Object) (*internalfake.Factory, func() client.Client) {
+	cfg := &config.Config{
+		LinkKubeconfig: pointer.Bool(false),
+		Gardens: []config.Garden{{
+			Name:       gardenName,
+			Kubeconfig: "/not/a/real/kubeconfig",
+		}},
+	}
+
+	project := &gardencorev1beta1.Project{
+		ObjectMeta: metav1.ObjectMeta{Name: projectName},
+		Spec:       gardencorev1beta1.ProjectSpec{Namespace: pointer.String("garden-prod1")},
+	}
+
+	var gardenClient client.Client
+
+	getGardenClient := func() client.Client {
+		if gardenClient == nil {
+			gardenClient = internalfake.NewClientWithObjects(append([]client.Object{project, shoot}, objs...)...)
+			if wrap != nil {
+				gardenClient = wrap(gardenClient)
+			}
+		}
+
+		return gardenClient
+	}
+
+	clientProvider := targetmocks.NewMockClientProvider(ctrl)
+	clientConfig, err := cfg.ClientConfig(gardenName)
+	Expect(err).ToNot(HaveOccurred())
+	clientProvider.EXPECT().FromClientConfig(gomock.Eq(clientConfig)).DoAndReturn(func(interface{}) (client.Client, error) {
+		return getGardenClient(), nil
+	}).AnyTimes()
+
+	targetProvider := internalfake.NewFakeTargetProvider(target.NewTarget(gardenName, projectName, "", shootName))
+
+	return internalfake.NewFakeFactory(cfg, nil, clientProvider, targetProvider), getGardenClient
+}
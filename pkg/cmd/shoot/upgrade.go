@@ -0,0 +1,421 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shoot
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
+)
+
+// versionLatest can be passed to --machine-image to select the latest supported version
+const versionLatest = "latest"
+
+// NewCmdUpgrade returns a new shoot upgrade command.
+func NewCmdUpgrade(f util.Factory, o *UpgradeOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "upgrade",
+		Short: "Upgrade the Kubernetes version or the machine images of the targeted shoot",
+		Long: `Upgrade the Kubernetes version of the control plane and the worker pools or the machine image versions
+of the worker pools of the targeted shoot.
+
+A Kubernetes version without patch version, e.g. 1.23, is resolved to the latest supported patch version of the cloud
+profile. Versions are validated against the cloud profile of the shoot: expired versions are rejected, and preview
+versions can only be selected explicitly. Kubernetes can only be upgraded by one minor version at a time and
+downgrades are not supported.
+
+With --control-plane-only, the worker pools keep their current Kubernetes version, with --workers-only, only the worker
+pools are upgraded, e.g. after the control plane has been upgraded.
+With --machine-image, the machine images of all worker pools are upgraded to the given version or to the latest
+supported version of the cloud profile.
+
+You will be asked for confirmation unless the --force flag is set. With --wait, the progress of the reconciliation
+is printed until it has finished.`,
+		Example: `# upgrade the control plane and the worker pools to the latest patch version of Kubernetes 1.23
+gardenctl shoot upgrade --kubernetes 1.23
+
+# upgrade the control plane first and the worker pools later
+gardenctl shoot upgrade --kubernetes 1.23.4 --control-plane-only --wait
+gardenctl shoot upgrade --kubernetes 1.23.4 --workers-only
+
+# upgrade the machine images of all worker pools to the latest supported version
+gardenctl shoot upgrade --machine-image latest`,
+		Args: cobra.NoArgs,
+		RunE: base.WrapRunE(o, f),
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// UpgradeOptions is a struct to support shoot upgrade command
+type UpgradeOptions struct {
+	base.Options
+	WaitOptions
+
+	// Kubernetes is the Kubernetes version to upgrade to, a version without patch version is resolved to its latest patch version
+	Kubernetes string
+	// MachineImage is the machine image version to upgrade the worker pools to, or latest
+	MachineImage string
+	// WorkersOnly only upgrades the Kubernetes version of the worker pools
+	WorkersOnly bool
+	// ControlPlaneOnly only upgrades the Kubernetes version of the control plane
+	ControlPlaneOnly bool
+	// Force skips the confirmation prompt
+	Force bool
+}
+
+// NewUpgradeOptions returns initialized UpgradeOptions
+func NewUpgradeOptions(ioStreams util.IOStreams) *UpgradeOptions {
+	return &UpgradeOptions{
+		Options: base.Options{
+			IOStreams: ioStreams,
+		},
+	}
+}
+
+// AddFlags binds the command options to a given flagset
+func (o *UpgradeOptions) AddFlags(flags *pflag.FlagSet) {
+	o.WaitOptions.AddFlags(flags)
+	flags.StringVar(&o.Kubernetes, "kubernetes", o.Kubernetes, "Kubernetes version to upgrade to, e.g. 1.23 for its latest patch version or 1.23.4.")
+	flags.StringVar(&o.MachineImage, "machine-image", o.MachineImage, "Machine image version to upgrade the worker pools to, or latest.")
+	flags.BoolVar(&o.WorkersOnly, "workers-only", o.WorkersOnly, "Only upgrade the Kubernetes version of the worker pools.")
+	flags.BoolVar(&o.ControlPlaneOnly, "control-plane-only", o.ControlPlaneOnly, "Only upgrade the Kubernetes version of the control plane.")
+	flags.BoolVarP(&o.Force, "force", "f", o.Force, "Upgrade the shoot without asking for confirmation.")
+}
+
+// Validate validates the provided UpgradeOptions
+func (o *UpgradeOptions) Validate() error {
+	if o.Kubernetes == "" && o.MachineImage == "" {
+		return errors.New("at least one of --kubernetes or --machine-image must be set")
+	}
+
+	if o.WorkersOnly && o.ControlPlaneOnly {
+		return errors.New("--workers-only and --control-plane-only cannot be used together")
+	}
+
+	if (o.WorkersOnly || o.ControlPlaneOnly) && o.Kubernetes == "" {
+		return errors.New("--workers-only and --control-plane-only can only be used together with --kubernetes")
+	}
+
+	if o.ControlPlaneOnly && o.MachineImage != "" {
+		return errors.New("--machine-image cannot be used together with --control-plane-only")
+	}
+
+	return o.WaitOptions.Validate()
+}
+
+// Run executes the command
+func (o *UpgradeOptions) Run(f util.Factory) error {
+	currentTarget, gardenClient, shoot, err := targetedShoot(f)
+	if err != nil {
+		return err
+	}
+
+	ctx := f.Context()
+	key := client.ObjectKeyFromObject(shoot)
+	patch := client.MergeFrom(shoot.DeepCopy())
+
+	cloudProfile, err := gardenClient.GetCloudProfile(ctx, shoot.Spec.CloudProfileName)
+	if err != nil {
+		return err
+	}
+
+	now := f.Clock().Now()
+
+	var changes []string
+
+	if o.Kubernetes != "" {
+		targetVersion, err := resolveVersion(cloudProfile.Spec.Kubernetes.Versions, o.Kubernetes, now)
+		if err != nil {
+			return fmt.Errorf("invalid Kubernetes version: %w", err)
+		}
+
+		kubernetesChanges, err := o.upgradeKubernetes(shoot, targetVersion)
+		if err != nil {
+			return err
+		}
+
+		changes = append(changes, kubernetesChanges...)
+	}
+
+	if o.MachineImage != "" {
+		imageChanges, err := upgradeMachineImages(shoot, cloudProfile, o.MachineImage, now)
+		if err != nil {
+			return err
+		}
+
+		changes = append(changes, imageChanges...)
+	}
+
+	if len(changes) == 0 {
+		fmt.Fprintf(o.IOStreams.Out, "Shoot %s is already up to date\n", key)
+		return nil
+	}
+
+	fmt.Fprintf(o.IOStreams.Out, "Upgrade of shoot %s:\n", key)
+
+	for _, change := range changes {
+		fmt.Fprintf(o.IOStreams.Out, "  %s\n", change)
+	}
+
+	if !o.Force {
+		confirmed, err := util.Confirm(o.IOStreams, fmt.Sprintf("Do you really want to upgrade shoot %s in garden %s?", key, currentTarget.GardenName()))
+		if err != nil {
+			return err
+		}
+
+		if !confirmed {
+			fmt.Fprintln(o.IOStreams.Out, "Aborted")
+			return nil
+		}
+	}
+
+	if err := gardenClient.RuntimeClient().Patch(ctx, shoot, patch); err != nil {
+		return fmt.Errorf("failed to upgrade shoot %s: %w", key, err)
+	}
+
+	// in dry-run mode the change has already been printed by the client, there is nothing to wait for
+	if isDryRun(f) {
+		return nil
+	}
+
+	fmt.Fprintf(o.IOStreams.Out, "Triggered the upgrade of shoot %s\n", key)
+
+	return o.wait(f, o.IOStreams.Out, gardenClient, key)
+}
+
+// upgradeKubernetes sets the Kubernetes version of the control plane and the worker pools and returns the changes
+func (o *UpgradeOptions) upgradeKubernetes(shoot *gardencorev1beta1.Shoot, targetVersion string) ([]string, error) {
+	var changes []string
+
+	controlPlaneVersion := shoot.Spec.Kubernetes.Version
+
+	if !o.WorkersOnly && controlPlaneVersion != targetVersion {
+		if err := checkUpgradePath(controlPlaneVersion, targetVersion); err != nil {
+			return nil, fmt.Errorf("cannot upgrade the control plane: %w", err)
+		}
+
+		shoot.Spec.Kubernetes.Version = targetVersion
+		changes = append(changes, fmt.Sprintf("control plane: Kubernetes %s -> %s", controlPlaneVersion, targetVersion))
+	}
+
+	if o.WorkersOnly {
+		newer, err := isNewer(targetVersion, controlPlaneVersion)
+		if err != nil {
+			return nil, err
+		}
+
+		if newer {
+			return nil, fmt.Errorf("the worker pools cannot be upgraded to %s, which is newer than the control plane version %s", targetVersion, controlPlaneVersion)
+		}
+	}
+
+	for i := range shoot.Spec.Provider.Workers {
+		worker := &shoot.Spec.Provider.Workers[i]
+		explicit := worker.Kubernetes != nil && worker.Kubernetes.Version != nil
+
+		if o.ControlPlaneOnly {
+			// worker pools without version follow the control plane, the version is pinned to keep them at the current version
+			if !explicit && controlPlaneVersion != targetVersion {
+				setWorkerKubernetesVersion(worker, controlPlaneVersion)
+				changes = append(changes, fmt.Sprintf("worker pool %s: Kubernetes pinned to %s", worker.Name, controlPlaneVersion))
+			}
+
+			continue
+		}
+
+		if !explicit && !o.WorkersOnly {
+			continue
+		}
+
+		workerVersion := controlPlaneVersion
+		if explicit {
+			workerVersion = *worker.Kubernetes.Version
+		}
+
+		if workerVersion == targetVersion {
+			continue
+		}
+
+		if err := checkUpgradePath(workerVersion, targetVersion); err != nil {
+			return nil, fmt.Errorf("cannot upgrade worker pool %q: %w", worker.Name, err)
+		}
+
+		setWorkerKubernetesVersion(worker, targetVersion)
+		changes = append(changes, fmt.Sprintf("worker pool %s: Kubernetes %s -> %s", worker.Name, workerVersion, targetVersion))
+	}
+
+	return changes, nil
+}
+
+// upgradeMachineImages sets the machine image version of the worker pools and returns the changes
+func upgradeMachineImages(shoot *gardencorev1beta1.Shoot, cloudProfile *gardencorev1beta1.CloudProfile, requested string, now time.Time) ([]string, error) {
+	var changes []string
+
+	for i := range shoot.Spec.Provider.Workers {
+		worker := &shoot.Spec.Provider.Workers[i]
+
+		image := worker.Machine.Image
+		if image == nil {
+			continue
+		}
+
+		var versions []gardencorev1beta1.ExpirableVersion
+
+		for _, machineImage := range cloudProfile.Spec.MachineImages {
+			if machineImage.Name == image.Name {
+				for _, v := range machineImage.Versions {
+					versions = append(versions, v.ExpirableVersion)
+				}
+			}
+		}
+
+		if len(versions) == 0 {
+			return nil, fmt.Errorf("machine image %s of worker pool %q is not offered by cloud profile %s", image.Name, worker.Name, cloudProfile.Name)
+		}
+
+		targetVersion, err := resolveVersion(versions, requested, now)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version of machine image %s of worker pool %q: %w", image.Name, worker.Name, err)
+		}
+
+		currentVersion := pointer.StringDeref(image.Version, "")
+		if currentVersion == targetVersion {
+			continue
+		}
+
+		if currentVersion != "" {
+			newer, err := isNewer(currentVersion, targetVersion)
+			if err != nil {
+				return nil, err
+			}
+
+			if newer {
+				return nil, fmt.Errorf("cannot downgrade machine image %s of worker pool %q from %s to %s", image.Name, worker.Name, currentVersion, targetVersion)
+			}
+		}
+
+		image.Version = pointer.String(targetVersion)
+		changes = append(changes, fmt.Sprintf("worker pool %s: machine image %s %s -> %s", worker.Name, image.Name, currentVersion, targetVersion))
+	}
+
+	return changes, nil
+}
+
+func setWorkerKubernetesVersion(worker *gardencorev1beta1.Worker, v string) {
+	if worker.Kubernetes == nil {
+		worker.Kubernetes = &gardencorev1beta1.WorkerKubernetes{}
+	}
+
+	worker.Kubernetes.Version = pointer.String(v)
+}
+
+// resolveVersion returns the version of the cloud profile that matches the requested version. The requested version
+// can be a full version, a version without patch version, which selects the latest patch version, or latest. Expired
+// versions are never selected and preview versions only if they are requested with their full version.
+func resolveVersion(versions []gardencorev1beta1.ExpirableVersion, requested string, now time.Time) (string, error) {
+	for _, v := range versions {
+		if v.Version != requested {
+			continue
+		}
+
+		if isExpired(v, now) {
+			return "", fmt.Errorf("version %s has expired on %s", v.Version, v.ExpirationDate.UTC().Format("2006-01-02"))
+		}
+
+		return v.Version, nil
+	}
+
+	prefix := requested + "."
+	if requested == versionLatest {
+		prefix = ""
+	} else if strings.Count(requested, ".") != 1 {
+		return "", fmt.Errorf("version %s is not offered by the cloud profile", requested)
+	}
+
+	var (
+		latestName    string
+		latestVersion *version.Version
+	)
+
+	for _, v := range versions {
+		if !strings.HasPrefix(v.Version, prefix) || isExpired(v, now) ||
+			(v.Classification != nil && *v.Classification == gardencorev1beta1.ClassificationPreview) {
+			continue
+		}
+
+		parsed, err := version.ParseGeneric(v.Version)
+		if err != nil {
+			continue
+		}
+
+		if latestVersion == nil || latestVersion.LessThan(parsed) {
+			latestName, latestVersion = v.Version, parsed
+		}
+	}
+
+	if latestName == "" {
+		return "", fmt.Errorf("the cloud profile offers no supported version %s", requested)
+	}
+
+	return latestName, nil
+}
+
+func isExpired(v gardencorev1beta1.ExpirableVersion, now time.Time) bool {
+	return v.ExpirationDate != nil && !now.Before(v.ExpirationDate.Time)
+}
+
+// checkUpgradePath checks that Kubernetes is not downgraded and only upgraded by one minor version at a time
+func checkUpgradePath(from, to string) error {
+	fromVersion, err := version.ParseGeneric(from)
+	if err != nil {
+		return fmt.Errorf("failed to parse version %q: %w", from, err)
+	}
+
+	toVersion, err := version.ParseGeneric(to)
+	if err != nil {
+		return fmt.Errorf("failed to parse version %q: %w", to, err)
+	}
+
+	if toVersion.LessThan(fromVersion) {
+		return fmt.Errorf("downgrading Kubernetes from %s to %s is not supported", from, to)
+	}
+
+	if toVersion.Major() != fromVersion.Major() || toVersion.Minor() > fromVersion.Minor()+1 {
+		return fmt.Errorf("only upgrades by one minor version at a time are supported, upgrade from %s to %d.%d first", from, fromVersion.Major(), fromVersion.Minor()+1)
+	}
+
+	return nil
+}
+
+// isNewer returns true if version a is newer than version b
+func isNewer(a, b string) (bool, error) {
+	aVersion, err := version.ParseGeneric(a)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse version %q: %w", a, err)
+	}
+
+	bVersion, err := version.ParseGeneric(b)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse version %q: %w", b, err)
+	}
+
+	return bVersion.LessThan(aVersion), nil
+}
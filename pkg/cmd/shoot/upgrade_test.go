@@ -0,0 +1,192 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shoot_test
+
+import (
+	"context"
+	"time"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	internalfake "github.com/gardener/gardenctl-v2/internal/fake"
+	"github.com/gardener/gardenctl-v2/internal/util"
+	cmdshoot "github.com/gardener/gardenctl-v2/pkg/cmd/shoot"
+)
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time {
+	return time.Time(c)
+}
+
+var _ = Describe("Shoot Upgrade Command", func() {
+	var (
+		ctrl         *gomock.Controller
+		streams      util.IOStreams
+		in           *util.SafeBytesBuffer
+		out          *util.SafeBytesBuffer
+		factory      *internalfake.Factory
+		gardenClient func() client.Client
+		shoot        *gardencorev1beta1.Shoot
+		cloudProfile *gardencorev1beta1.CloudProfile
+	)
+
+	BeforeEach(func() {
+		now := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+		preview := gardencorev1beta1.ClassificationPreview
+
+		cloudProfile = &gardencorev1beta1.CloudProfile{
+			ObjectMeta: metav1.ObjectMeta{Name: "aws"},
+			Spec: gardencorev1beta1.CloudProfileSpec{
+				Kubernetes: gardencorev1beta1.KubernetesSettings{
+					Versions: []gardencorev1beta1.ExpirableVersion{
+						{Version: "1.21.9"},
+						{Version: "1.22.1", ExpirationDate: &metav1.Time{Time: now.Add(-time.Hour)}},
+						{Version: "1.22.7"},
+						{Version: "1.22.8"},
+						{Version: "1.23.0", Classification: &preview},
+					},
+				},
+				MachineImages: []gardencorev1beta1.MachineImage{{
+					Name: "gardenlinux",
+					Versions: []gardencorev1beta1.MachineImageVersion{
+						{ExpirableVersion: gardencorev1beta1.ExpirableVersion{Version: "318.8.0"}},
+						{ExpirableVersion: gardencorev1beta1.ExpirableVersion{Version: "576.9.0"}},
+						{ExpirableVersion: gardencorev1beta1.ExpirableVersion{Version: "576.10.0"}},
+						{ExpirableVersion: gardencorev1beta1.ExpirableVersion{Version: "600.0.0", Classification: &preview}},
+					},
+				}},
+			},
+		}
+
+		shoot = &gardencorev1beta1.Shoot{
+			ObjectMeta: metav1.ObjectMeta{Name: shootName, Namespace: "garden-prod1"},
+			Spec: gardencorev1beta1.ShootSpec{
+				CloudProfileName: "aws",
+				Kubernetes:       gardencorev1beta1.Kubernetes{Version: "1.21.9"},
+				Provider: gardencorev1beta1.Provider{
+					Type: "aws",
+					Workers: []gardencorev1beta1.Worker{{
+						Name:    "worker-a",
+						Maximum: 2,
+						Machine: gardencorev1beta1.Machine{
+							Type:  "m5.large",
+							Image: &gardencorev1beta1.ShootMachineImage{Name: "gardenlinux", Version: pointer.String("576.9.0")},
+						},
+					}},
+				},
+			},
+		}
+
+		streams, in, out, _ = util.NewTestIOStreams()
+
+		ctrl = gomock.NewController(GinkgoT())
+
+		factory, gardenClient = newShootFactory(ctrl, shoot, nil, cloudProfile)
+		factory.ClockImpl = fixedClock(now)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	getShoot := func() *gardencorev1beta1.Shoot {
+		current := &gardencorev1beta1.Shoot{}
+		Expect(gardenClient().Get(context.Background(), client.ObjectKeyFromObject(shoot), current)).To(Succeed())
+
+		return current
+	}
+
+	It("should upgrade to the latest supported patch version after confirmation", func() {
+		in.Write([]byte("y\n"))
+
+		cmd := cmdshoot.NewCmdUpgrade(factory, cmdshoot.NewUpgradeOptions(streams))
+		Expect(cmd.Flags().Set("kubernetes", "1.22")).To(Succeed())
+		Expect(cmd.RunE(cmd, nil)).To(Succeed())
+		Expect(out.String()).To(Equal(`Upgrade of shoot garden-prod1/myshoot:
+  control plane: Kubernetes 1.21.9 -> 1.22.8
+Do you really want to upgrade shoot garden-prod1/myshoot in garden mygarden? [y/N]: Triggered the upgrade of shoot garden-prod1/myshoot
+`))
+		Expect(getShoot().Spec.Kubernetes.Version).To(Equal("1.22.8"))
+	})
+
+	It("should pin the worker pools with --control-plane-only and upgrade them with --workers-only", func() {
+		cmd := cmdshoot.NewCmdUpgrade(factory, cmdshoot.NewUpgradeOptions(streams))
+		Expect(cmd.Flags().Set("kubernetes", "1.22.7")).To(Succeed())
+		Expect(cmd.Flags().Set("control-plane-only", "true")).To(Succeed())
+		Expect(cmd.Flags().Set("force", "true")).To(Succeed())
+		Expect(cmd.RunE(cmd, nil)).To(Succeed())
+		Expect(out.String()).To(ContainSubstring("  worker pool worker-a: Kubernetes pinned to 1.21.9\n"))
+
+		current := getShoot()
+		Expect(current.Spec.Kubernetes.Version).To(Equal("1.22.7"))
+		Expect(current.Spec.Provider.Workers[0].Kubernetes.Version).To(Equal(pointer.String("1.21.9")))
+
+		cmd = cmdshoot.NewCmdUpgrade(factory, cmdshoot.NewUpgradeOptions(streams))
+		Expect(cmd.Flags().Set("kubernetes", "1.22.7")).To(Succeed())
+		Expect(cmd.Flags().Set("workers-only", "true")).To(Succeed())
+		Expect(cmd.Flags().Set("force", "true")).To(Succeed())
+		Expect(cmd.RunE(cmd, nil)).To(Succeed())
+		Expect(out.String()).To(ContainSubstring("  worker pool worker-a: Kubernetes 1.21.9 -> 1.22.7\n"))
+		Expect(getShoot().Spec.Provider.Workers[0].Kubernetes.Version).To(Equal(pointer.String("1.22.7")))
+	})
+
+	It("should reject expired versions", func() {
+		cmd := cmdshoot.NewCmdUpgrade(factory, cmdshoot.NewUpgradeOptions(streams))
+		Expect(cmd.Flags().Set("kubernetes", "1.22.1")).To(Succeed())
+		Expect(cmd.RunE(cmd, nil)).To(MatchError("invalid Kubernetes version: version 1.22.1 has expired on 2022-03-01"))
+	})
+
+	It("should reject upgrades that skip a minor version", func() {
+		cmd := cmdshoot.NewCmdUpgrade(factory, cmdshoot.NewUpgradeOptions(streams))
+		Expect(cmd.Flags().Set("kubernetes", "1.23.0")).To(Succeed())
+		Expect(cmd.RunE(cmd, nil)).To(MatchError("cannot upgrade the control plane: only upgrades by one minor version at a time are supported, upgrade from 1.21.9 to 1.22 first"))
+	})
+
+	It("should not select preview versions by their minor version", func() {
+		shoot.Spec.Kubernetes.Version = "1.22.8"
+
+		cmd := cmdshoot.NewCmdUpgrade(factory, cmdshoot.NewUpgradeOptions(streams))
+		Expect(cmd.Flags().Set("kubernetes", "1.23")).To(Succeed())
+		Expect(cmd.RunE(cmd, nil)).To(MatchError("invalid Kubernetes version: the cloud profile offers no supported version 1.23"))
+	})
+
+	It("should reject worker versions that are newer than the control plane", func() {
+		cmd := cmdshoot.NewCmdUpgrade(factory, cmdshoot.NewUpgradeOptions(streams))
+		Expect(cmd.Flags().Set("kubernetes", "1.22.8")).To(Succeed())
+		Expect(cmd.Flags().Set("workers-only", "true")).To(Succeed())
+		Expect(cmd.RunE(cmd, nil)).To(MatchError("the worker pools cannot be upgraded to 1.22.8, which is newer than the control plane version 1.21.9"))
+	})
+
+	It("should upgrade the machine images to the latest supported version", func() {
+		cmd := cmdshoot.NewCmdUpgrade(factory, cmdshoot.NewUpgradeOptions(streams))
+		Expect(cmd.Flags().Set("machine-image", "latest")).To(Succeed())
+		Expect(cmd.Flags().Set("force", "true")).To(Succeed())
+		Expect(cmd.RunE(cmd, nil)).To(Succeed())
+		Expect(out.String()).To(ContainSubstring("  worker pool worker-a: machine image gardenlinux 576.9.0 -> 576.10.0\n"))
+		Expect(getShoot().Spec.Provider.Workers[0].Machine.Image.Version).To(Equal(pointer.String("576.10.0")))
+	})
+
+	It("should reject machine image downgrades", func() {
+		cmd := cmdshoot.NewCmdUpgrade(factory, cmdshoot.NewUpgradeOptions(streams))
+		Expect(cmd.Flags().Set("machine-image", "318.8.0")).To(Succeed())
+		Expect(cmd.RunE(cmd, nil)).To(MatchError("cannot downgrade machine image gardenlinux of worker pool \"worker-a\" from 576.9.0 to 318.8.0"))
+	})
+
+	It("should report that the shoot is up to date", func() {
+		cmd := cmdshoot.NewCmdUpgrade(factory, cmdshoot.NewUpgradeOptions(streams))
+		Expect(cmd.Flags().Set("kubernetes", "1.21")).To(Succeed())
+		Expect(cmd.RunE(cmd, nil)).To(Succeed())
+		Expect(out.String()).To(Equal("Shoot garden-prod1/myshoot is already up to date\n"))
+	})
+})
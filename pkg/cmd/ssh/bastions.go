@@ -0,0 +1,342 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ssh
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	operationsv1alpha1 "github.com/gardener/gardener/pkg/apis/operations/v1alpha1"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1beta1 "k8s.io/apimachinery/pkg/apis/meta/v1beta1"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/cli-runtime/pkg/printers"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/gardener/gardenctl-v2/internal/gardenclient"
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
+)
+
+// NewCmdListBastions returns a new list-bastions command.
+func NewCmdListBastions(f util.Factory, o *ListBastionsOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list-bastions",
+		Short: "List the bastions created by gardenctl",
+		Long: `List the bastions that have been created by gardenctl for the current user and host.
+The bastions of the targeted project are listed, or of all projects of the targeted garden if no project is targeted.`,
+		Example: `# list the bastions of the targeted project
+gardenctl ssh list-bastions
+
+# list the bastions of all users
+gardenctl ssh list-bastions --all`,
+		Args: cobra.NoArgs,
+		RunE: base.WrapRunE(o, f),
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// NewCmdCleanup returns a new cleanup command.
+func NewCmdCleanup(f util.Factory, o *CleanupOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cleanup",
+		Short: "Delete stale bastions created by gardenctl",
+		Long: `Delete the bastions that have been created by gardenctl for the current user and host and that
+have not been kept alive for longer than the given TTL, e.g. because gardenctl has been killed.
+This prevents orphaned bastion hosts that cause costs at the cloud provider.`,
+		Example: `# delete the stale bastions of the targeted project
+gardenctl ssh cleanup
+
+# delete the bastions that have not been kept alive for 10 minutes
+gardenctl ssh cleanup --ttl 10m
+
+# only print the stale bastions without deleting them
+gardenctl ssh cleanup --dry-run`,
+		Args: cobra.NoArgs,
+		RunE: base.WrapRunE(o, f),
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// ListBastionsOptions is a struct to support list-bastions command
+type ListBastionsOptions struct {
+	base.Options
+
+	// All includes the bastions created by other users or hosts
+	All bool
+}
+
+// NewListBastionsOptions returns initialized ListBastionsOptions
+func NewListBastionsOptions(ioStreams util.IOStreams) *ListBastionsOptions {
+	return &ListBastionsOptions{
+		Options: base.Options{
+			IOStreams: ioStreams,
+		},
+	}
+}
+
+// AddFlags binds the command options to a given flagset
+func (o *ListBastionsOptions) AddFlags(flags *pflag.FlagSet) {
+	o.Options.AddFlags(flags)
+	flags.BoolVar(&o.All, "all", o.All, "List the bastions created by all users and hosts.")
+}
+
+// Run executes the command
+func (o *ListBastionsOptions) Run(f util.Factory) error {
+	bastions, err := listBastions(f, o.All)
+	if err != nil {
+		return err
+	}
+
+	if o.Output != "" {
+		return o.PrintObject(bastions)
+	}
+
+	if len(bastions) == 0 {
+		fmt.Fprintln(o.IOStreams.Out, "No bastions found")
+		return nil
+	}
+
+	now := f.Clock().Now()
+
+	table := &metav1beta1.Table{
+		ColumnDefinitions: []metav1.TableColumnDefinition{
+			{Name: "Name", Type: "string", Format: "name"},
+			{Name: "Namespace", Type: "string"},
+			{Name: "Shoot", Type: "string"},
+			{Name: "Owner", Type: "string"},
+			{Name: "Age", Type: "string"},
+			{Name: "Last Activity", Type: "string"},
+		},
+		Rows: []metav1.TableRow{},
+	}
+
+	for _, bastion := range bastions {
+		table.Rows = append(table.Rows, metav1.TableRow{
+			Cells: []interface{}{
+				bastion.Name,
+				bastion.Namespace,
+				bastion.Spec.ShootRef.Name,
+				bastion.Annotations[BastionOwnerAnnotation],
+				duration.HumanDuration(now.Sub(bastion.CreationTimestamp.Time)),
+				duration.HumanDuration(now.Sub(lastBastionActivity(bastion))),
+			},
+		})
+	}
+
+	printer := printers.NewTablePrinter(printers.PrintOptions{})
+	if err := printer.PrintObj(table, o.IOStreams.Out); err != nil {
+		return fmt.Errorf("failed to output bastion table: %w", err)
+	}
+
+	return nil
+}
+
+// CleanupOptions is a struct to support cleanup command
+type CleanupOptions struct {
+	base.Options
+
+	// All includes the bastions created by other users or hosts
+	All bool
+
+	// TTL is the duration after the last activity of a bastion, after which it is considered stale
+	TTL time.Duration
+}
+
+// NewCleanupOptions returns initialized CleanupOptions
+func NewCleanupOptions(ioStreams util.IOStreams) *CleanupOptions {
+	return &CleanupOptions{
+		Options: base.Options{
+			IOStreams: ioStreams,
+		},
+		TTL: time.Hour,
+	}
+}
+
+// AddFlags binds the command options to a given flagset
+func (o *CleanupOptions) AddFlags(flags *pflag.FlagSet) {
+	flags.BoolVar(&o.All, "all", o.All, "Delete the stale bastions created by all users and hosts.")
+	flags.DurationVar(&o.TTL, "ttl", o.TTL, "Duration after the last activity of a bastion, after which it is deleted.")
+}
+
+// Validate validates the provided CleanupOptions
+func (o *CleanupOptions) Validate() error {
+	if o.TTL <= 0 {
+		return errors.New("the TTL must be positive")
+	}
+
+	return nil
+}
+
+// Run executes the command
+func (o *CleanupOptions) Run(f util.Factory) error {
+	manager, err := f.Manager()
+	if err != nil {
+		return err
+	}
+
+	currentTarget, err := manager.CurrentTarget()
+	if err != nil {
+		return err
+	}
+
+	gardenClient, err := manager.GardenClient(currentTarget.GardenName())
+	if err != nil {
+		return err
+	}
+
+	bastions, err := listBastions(f, o.All)
+	if err != nil {
+		return err
+	}
+
+	now := f.Clock().Now()
+	deleted := 0
+
+	for i := range bastions {
+		bastion := &bastions[i]
+
+		if now.Sub(lastBastionActivity(*bastion)) < o.TTL {
+			continue
+		}
+
+		deleted++
+
+		if err := gardenClient.RuntimeClient().Delete(f.Context(), bastion); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete bastion %s/%s: %w", bastion.Namespace, bastion.Name, err)
+		}
+
+		// in dry-run mode, the deletion has already been printed by the client
+		if manager.Configuration().IsDryRun() {
+			continue
+		}
+
+		fmt.Fprintf(o.IOStreams.Out, "Deleted bastion %s/%s\n", bastion.Namespace, bastion.Name)
+	}
+
+	if deleted == 0 {
+		fmt.Fprintln(o.IOStreams.Out, "No stale bastions found")
+	}
+
+	return nil
+}
+
+// listBastions returns the bastions created by gardenctl in the targeted project, or in all projects
+// of the targeted garden if no project is targeted. Unless all is true, only the bastions of the
+// current owner are returned.
+func listBastions(f util.Factory, all bool) ([]operationsv1alpha1.Bastion, error) {
+	manager, err := f.Manager()
+	if err != nil {
+		return nil, err
+	}
+
+	currentTarget, err := manager.CurrentTarget()
+	if err != nil {
+		return nil, err
+	}
+
+	if currentTarget.GardenName() == "" {
+		return nil, errors.New("no garden cluster targeted")
+	}
+
+	gardenClient, err := manager.GardenClient(currentTarget.GardenName())
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := f.Context()
+
+	namespaces, err := bastionNamespaces(ctx, gardenClient, currentTarget.ProjectName())
+	if err != nil {
+		return nil, err
+	}
+
+	owner := ""
+
+	if !all {
+		owner, err = bastionOwnerProvider()
+		if err != nil {
+			return nil, fmt.Errorf("failed to determine bastion owner: %w", err)
+		}
+	}
+
+	bastions := []operationsv1alpha1.Bastion{}
+
+	for _, namespace := range namespaces {
+		list, err := gardenClient.ListBastions(ctx, client.InNamespace(namespace), client.MatchingLabels{BastionManagedByLabel: BastionManagedByValue})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, bastion := range list.Items {
+			if owner == "" || bastion.Annotations[BastionOwnerAnnotation] == owner {
+				bastions = append(bastions, bastion)
+			}
+		}
+	}
+
+	sort.Slice(bastions, func(i, j int) bool {
+		if bastions[i].Namespace != bastions[j].Namespace {
+			return bastions[i].Namespace < bastions[j].Namespace
+		}
+
+		return bastions[i].Name < bastions[j].Name
+	})
+
+	return bastions, nil
+}
+
+// bastionNamespaces returns the namespace of the given project, or the namespaces of all projects
+func bastionNamespaces(ctx context.Context, gardenClient gardenclient.Client, projectName string) ([]string, error) {
+	if projectName != "" {
+		project, err := gardenClient.GetProject(ctx, projectName)
+		if err != nil {
+			return nil, err
+		}
+
+		if project.Spec.Namespace == nil {
+			return nil, fmt.Errorf("project %s has no namespace", projectName)
+		}
+
+		return []string{*project.Spec.Namespace}, nil
+	}
+
+	projects, err := gardenClient.ListProjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces := []string{}
+
+	for _, project := range projects.Items {
+		if project.Spec.Namespace != nil {
+			namespaces = append(namespaces, *project.Spec.Namespace)
+		}
+	}
+
+	return namespaces, nil
+}
+
+// lastBastionActivity returns the time the bastion was last kept alive, or its creation time
+func lastBastionActivity(bastion operationsv1alpha1.Bastion) time.Time {
+	if bastion.Status.LastHeartbeatTimestamp != nil {
+		return bastion.Status.LastHeartbeatTimestamp.Time
+	}
+
+	return bastion.CreationTimestamp.Time
+}
@@ -0,0 +1,182 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ssh_test
+
+import (
+	"context"
+	"time"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	operationsv1alpha1 "github.com/gardener/gardener/pkg/apis/operations/v1alpha1"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	internalfake "github.com/gardener/gardenctl-v2/internal/fake"
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/ssh"
+	"github.com/gardener/gardenctl-v2/pkg/config"
+	"github.com/gardener/gardenctl-v2/pkg/target"
+	targetmocks "github.com/gardener/gardenctl-v2/pkg/target/mocks"
+)
+
+var _ = Describe("Bastions Command", func() {
+	const (
+		gardenName = "mygarden"
+		owner      = "alice@laptop"
+	)
+
+	var (
+		ctrl         *gomock.Controller
+		streams      util.IOStreams
+		out          *util.SafeBytesBuffer
+		factory      *internalfake.Factory
+		gardenClient client.Client
+		now          time.Time
+	)
+
+	newBastion := func(name, bastionOwner string, lastActivity time.Time) *operationsv1alpha1.Bastion {
+		return &operationsv1alpha1.Bastion{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "garden-prod1",
+				CreationTimestamp: metav1.NewTime(lastActivity),
+				Labels: map[string]string{
+					ssh.BastionManagedByLabel: ssh.BastionManagedByValue,
+				},
+				Annotations: map[string]string{
+					ssh.BastionOwnerAnnotation: bastionOwner,
+				},
+			},
+			Spec: operationsv1alpha1.BastionSpec{
+				ShootRef: corev1.LocalObjectReference{Name: "test-shoot"},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		now = time.Now()
+
+		ssh.SetBastionOwnerProvider(func() (string, error) {
+			return owner, nil
+		})
+
+		cfg := &config.Config{
+			LinkKubeconfig: pointer.Bool(false),
+			Gardens: []config.Garden{{
+				Name:       gardenName,
+				Kubeconfig: "/not/a/real/kubeconfig",
+			}},
+		}
+
+		project := &gardencorev1beta1.Project{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "prod1",
+			},
+			Spec: gardencorev1beta1.ProjectSpec{
+				Namespace: pointer.String("garden-prod1"),
+			},
+		}
+
+		unmanaged := newBastion("unmanaged", owner, now.Add(-2*time.Hour))
+		unmanaged.Labels = nil
+
+		gardenClient = internalfake.NewClientWithObjects(
+			project,
+			newBastion("cli-stale", owner, now.Add(-2*time.Hour)),
+			newBastion("cli-active", owner, now.Add(-10*time.Minute)),
+			newBastion("cli-other", "bob@desktop", now.Add(-2*time.Hour)),
+			unmanaged,
+		)
+
+		streams, _, out, _ = util.NewTestIOStreams()
+
+		ctrl = gomock.NewController(GinkgoT())
+
+		clientProvider := targetmocks.NewMockClientProvider(ctrl)
+		clientConfig, err := cfg.ClientConfig(gardenName)
+		Expect(err).ToNot(HaveOccurred())
+		clientProvider.EXPECT().FromClientConfig(gomock.Eq(clientConfig)).Return(gardenClient, nil).AnyTimes()
+
+		targetProvider := internalfake.NewFakeTargetProvider(target.NewTarget(gardenName, "prod1", "", ""))
+
+		factory = internalfake.NewFakeFactory(cfg, nil, clientProvider, targetProvider)
+		factory.ContextImpl = context.Background()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("list-bastions", func() {
+		It("should list the bastions of the current owner", func() {
+			cmd := ssh.NewCmdListBastions(factory, ssh.NewListBastionsOptions(streams))
+
+			Expect(cmd.RunE(cmd, nil)).To(Succeed())
+			Expect(out.String()).To(MatchRegexp(`NAME\s+NAMESPACE\s+SHOOT\s+OWNER\s+AGE\s+LAST ACTIVITY\n`))
+			Expect(out.String()).To(MatchRegexp(`cli-active\s+garden-prod1\s+test-shoot\s+alice@laptop\s+10m\s+10m\n`))
+			Expect(out.String()).To(ContainSubstring("cli-stale"))
+			Expect(out.String()).NotTo(ContainSubstring("cli-other"))
+			Expect(out.String()).NotTo(ContainSubstring("unmanaged"))
+		})
+
+		It("should list the bastions of all owners", func() {
+			options := ssh.NewListBastionsOptions(streams)
+			options.All = true
+			cmd := ssh.NewCmdListBastions(factory, options)
+
+			Expect(cmd.RunE(cmd, nil)).To(Succeed())
+			Expect(out.String()).To(ContainSubstring("cli-other"))
+			Expect(out.String()).NotTo(ContainSubstring("unmanaged"))
+		})
+	})
+
+	Describe("cleanup", func() {
+		It("should delete the stale bastions of the current owner", func() {
+			cmd := ssh.NewCmdCleanup(factory, ssh.NewCleanupOptions(streams))
+
+			Expect(cmd.RunE(cmd, nil)).To(Succeed())
+			Expect(out.String()).To(Equal("Deleted bastion garden-prod1/cli-stale\n"))
+
+			list := &operationsv1alpha1.BastionList{}
+			Expect(gardenClient.List(context.Background(), list)).To(Succeed())
+
+			names := []string{}
+			for _, bastion := range list.Items {
+				names = append(names, bastion.Name)
+			}
+
+			Expect(names).To(ConsistOf("cli-active", "cli-other", "unmanaged"))
+		})
+
+		It("should only print the stale bastions in dry-run mode", func() {
+			factory.Config.DryRun = &config.DryRun{Strategy: config.DryRunClient, Out: out}
+
+			options := ssh.NewCleanupOptions(streams)
+			options.All = true
+			cmd := ssh.NewCmdCleanup(factory, options)
+
+			Expect(cmd.RunE(cmd, nil)).To(Succeed())
+			Expect(out.String()).To(Equal("Bastion garden-prod1/cli-other deleted (client dry run)\nBastion garden-prod1/cli-stale deleted (client dry run)\n"))
+
+			list := &operationsv1alpha1.BastionList{}
+			Expect(gardenClient.List(context.Background(), list)).To(Succeed())
+			Expect(list.Items).To(HaveLen(4))
+		})
+
+		It("should reject a non-positive TTL", func() {
+			options := ssh.NewCleanupOptions(streams)
+			options.TTL = 0
+
+			Expect(options.Validate()).To(MatchError("the TTL must be positive"))
+		})
+	})
+})
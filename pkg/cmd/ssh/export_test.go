@@ -8,6 +8,7 @@ package ssh
 
 import (
 	"context"
+	"io"
 	"os"
 	"time"
 )
@@ -42,3 +43,11 @@ func SetKeepAliveInterval(d time.Duration) {
 
 	keepAliveInterval = d
 }
+
+func (o *SSHOptions) CommandOutput() io.Writer {
+	return o.commandOut()
+}
+
+func SetBastionOwnerProvider(f func() (string, error)) {
+	bastionOwnerProvider = f
+}
@@ -20,6 +20,7 @@ import (
 	"os"
 	"os/exec"
 	"os/signal"
+	"os/user"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -59,6 +60,12 @@ const (
 	SSHNodeUsername = "gardener"
 	// SSHPort is the TCP port on a bastion instance that allows incoming SSH.
 	SSHPort = 22
+	// BastionManagedByLabel is the label key that marks bastions created by gardenctl.
+	BastionManagedByLabel = "app.kubernetes.io/managed-by"
+	// BastionManagedByValue is the value of the BastionManagedByLabel of bastions created by gardenctl.
+	BastionManagedByValue = "gardenctl"
+	// BastionOwnerAnnotation is the annotation key that contains the user and host that created the bastion.
+	BastionOwnerAnnotation = "gardenctl.gardener.cloud/owner"
 )
 
 // wrappers used for unit tests only
@@ -130,6 +137,21 @@ var (
 		return fmt.Sprintf("cli-%s", strings.ToLower(bastionID)), nil
 	}
 
+	// bastionOwnerProvider returns the user and host that create bastions, e.g. "user@host".
+	bastionOwnerProvider = func() (string, error) {
+		u, err := user.Current()
+		if err != nil {
+			return "", err
+		}
+
+		hostname, err := os.Hostname()
+		if err != nil {
+			return "", err
+		}
+
+		return fmt.Sprintf("%s@%s", u.Username, hostname), nil
+	}
+
 	// createSignalChannel returns a channel which receives OS signals.
 	createSignalChannel = func() chan os.Signal {
 		signalChan := make(chan os.Signal, 1)
@@ -143,14 +165,17 @@ var (
 	// fails.
 	execCommand = func(ctx context.Context, command string, args []string, o *SSHOptions) error {
 		cmd := exec.CommandContext(ctx, command, args...)
-		cmd.Stdout = o.IOStreams.Out
+		cmd.Stdout = o.commandOut()
 		cmd.Stdin = o.IOStreams.In
-		cmd.Stderr = o.IOStreams.ErrOut
+		cmd.Stderr = util.UnwrapQuietWriter(o.IOStreams.ErrOut)
 
 		return cmd.Run()
 	}
 )
 
+// outputSSHConfig is the output format that prints an ssh_config snippet instead of the SSH command line
+const outputSSHConfig = "ssh-config"
+
 // SSHOptions is a struct to support ssh command
 // nolint
 type SSHOptions struct {
@@ -162,11 +187,34 @@ type SSHOptions struct {
 	// interactive mode, a NodeName must be specified as well.
 	Interactive bool
 
+	// NoConnect only provisions the bastion host and keeps it alive without
+	// opening an SSH connection, even if a NodeName is given.
+	NoConnect bool
+
 	// NodeName is the name of the Shoot cluster node that the user wants to
 	// connect to. If this is left empty, gardenctl will only establish the
 	// bastion host, but leave it up to the user to SSH themselves.
 	NodeName string
 
+	// Command is executed on the node instead of opening an interactive
+	// SSH session. It requires a NodeName.
+	Command []string
+
+	// commandOutput receives the output of the executed Command or the
+	// ssh_config snippet. The progress messages are written to the error
+	// output stream in this case, so that the output can be processed by scripts.
+	commandOutput io.Writer
+
+	// copySource and copyDestination are passed to scp instead of opening
+	// an SSH session. The remote path has the prefix "NODE_NAME:", which is
+	// replaced by the user and hostname of the node.
+	copySource      string
+	copyDestination string
+
+	// SelectNode asks the user to select the node from a list of all nodes
+	// of the Shoot cluster if no NodeName is given.
+	SelectNode bool
+
 	// CIDRs is a list of IP address ranges to be allowed for accessing the
 	// created Bastion host. If not given, gardenctl will attempt to
 	// auto-detect the user's IP and allow only it (i.e. use a /32 netmask).
@@ -212,6 +260,33 @@ func NewSSHOptions(ioStreams util.IOStreams) *SSHOptions {
 
 // Complete adapts from the command line args to the data required.
 func (o *SSHOptions) Complete(f util.Factory, cmd *cobra.Command, args []string) error {
+	if cmd != nil {
+		if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+			o.Command = args[dash:]
+			args = args[:dash]
+		}
+	}
+
+	if o.NoConnect {
+		o.Interactive = false
+	}
+
+	if (len(o.Command) > 0 || o.Output == outputSSHConfig) && o.commandOutput == nil {
+		o.commandOutput = o.IOStreams.Out
+		o.IOStreams.Out = o.IOStreams.ErrOut
+	}
+
+	if len(o.CIDRs) == 0 {
+		manager, err := f.Manager()
+		if err != nil {
+			return err
+		}
+
+		if cfg := manager.Configuration(); cfg != nil && cfg.Bastion != nil {
+			o.CIDRs = cfg.Bastion.IngressCIDRs
+		}
+	}
+
 	if len(o.CIDRs) == 0 {
 		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 		defer cancel()
@@ -262,6 +337,15 @@ func (o *SSHOptions) Complete(f util.Factory, cmd *cobra.Command, args []string)
 	return nil
 }
 
+// commandOut returns the stream that receives the output of executed commands
+func (o *SSHOptions) commandOut() io.Writer {
+	if o.commandOutput != nil {
+		return o.commandOutput
+	}
+
+	return o.IOStreams.Out
+}
+
 func ipToCIDR(address string) string {
 	ip := net.ParseIP(address)
 
@@ -293,14 +377,45 @@ func (o *SSHOptions) Validate() error {
 		return errors.New("the maximum wait duration must be non-zero")
 	}
 
+	if o.SelectNode && o.NodeName != "" {
+		return errors.New("--select-node must not be used together with a node name")
+	}
+
+	if o.Output != "" && o.Output != outputSSHConfig {
+		return fmt.Errorf("--output must be %q", outputSSHConfig)
+	}
+
+	if o.Output == outputSSHConfig && o.Interactive && (o.NodeName != "" || o.SelectNode) {
+		return errors.New("--output=ssh-config requires --no-connect")
+	}
+
+	if len(o.Command) > 0 {
+		if o.NoConnect {
+			return errors.New("--no-connect must not be used together with a command")
+		}
+
+		if o.NodeName == "" && !o.SelectNode {
+			return errors.New("a node name is required to execute a command")
+		}
+
+		if !o.Interactive {
+			return errors.New("--interactive=false must not be used together with a command")
+		}
+	}
+
 	if len(o.CIDRs) == 0 {
 		return errors.New("must at least specify a single CIDR to allow access to the bastion")
 	}
 
 	for _, cidr := range o.CIDRs {
-		if _, _, err := net.ParseCIDR(cidr); err != nil {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
 			return fmt.Errorf("CIDR %q is invalid: %w", cidr, err)
 		}
+
+		if ones, _ := ipNet.Mask.Size(); ones == 0 {
+			fmt.Fprintf(o.IOStreams.ErrOut, "Warning: CIDR %q allows access to the bastion from any address\n", cidr)
+		}
 	}
 
 	content, err := ioutil.ReadFile(o.SSHPublicKeyFile)
@@ -487,10 +602,21 @@ func (o *SSHOptions) Run(f util.Factory) error {
 		return fmt.Errorf("failed to create bastion name: %w", err)
 	}
 
+	bastionOwner, err := bastionOwnerProvider()
+	if err != nil {
+		return fmt.Errorf("failed to determine bastion owner: %w", err)
+	}
+
 	bastion := &operationsv1alpha1.Bastion{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      bastionName,
 			Namespace: shoot.Namespace,
+			Labels: map[string]string{
+				BastionManagedByLabel: BastionManagedByValue,
+			},
+			Annotations: map[string]string{
+				BastionOwnerAnnotation: bastionOwner,
+			},
 		},
 		Spec: operationsv1alpha1.BastionSpec{
 			ShootRef: corev1.LocalObjectReference{
@@ -501,6 +627,17 @@ func (o *SSHOptions) Run(f util.Factory) error {
 		},
 	}
 
+	// in dry-run mode the bastion is only printed, there is nothing to wait for or to connect to
+	if manager.Configuration().IsDryRun() {
+		defer removeKeyFiles(o, nodePrivateKeyFiles)
+
+		if err := gardenClient.RuntimeClient().Create(ctx, bastion); err != nil {
+			return fmt.Errorf("failed to create bastion: %w", err)
+		}
+
+		return nil
+	}
+
 	// allow to cancel at any time, but with us still performing the cleanup
 	signalChan := createSignalChannel()
 
@@ -539,7 +676,11 @@ func (o *SSHOptions) Run(f util.Factory) error {
 	}
 
 	if err != nil {
-		// actual error has already been printed
+		// actual error has already been printed, only the timeout is kept for the exit code
+		if base.IsTimeout(err) {
+			return base.NewTimeoutError(errors.New("precondition failed"))
+		}
+
 		return errors.New("precondition failed")
 	}
 
@@ -556,9 +697,12 @@ func (o *SSHOptions) Run(f util.Factory) error {
 
 	fmt.Fprintf(o.IOStreams.Out, "Bastion host became available at %s.\n", printAddr)
 
-	if node != nil && o.Interactive {
+	switch {
+	case node != nil && o.copySource != "":
+		err = remoteCopy(ctx, o, bastion, nodePrivateKeyFiles, node)
+	case node != nil && o.Interactive:
 		err = remoteShell(ctx, o, bastion, nodePrivateKeyFiles, node)
-	} else {
+	default:
 		err = waitForSignal(ctx, o, shootClient, bastion, nodePrivateKeyFiles, node, ctx.Done())
 	}
 
@@ -622,24 +766,7 @@ func cleanup(ctx context.Context, o *SSHOptions, gardenClient client.Client, bas
 			fmt.Fprintf(o.IOStreams.ErrOut, "Failed to delete bastion: %v", err)
 		}
 
-		if o.generatedSSHKeys {
-			if err := os.Remove(o.SSHPublicKeyFile); err != nil {
-				fmt.Fprintf(o.IOStreams.ErrOut, "Failed to delete SSH public key file %q: %v\n", o.SSHPublicKeyFile, err)
-			}
-
-			if err := os.Remove(o.SSHPrivateKeyFile); err != nil {
-				fmt.Fprintf(o.IOStreams.ErrOut, "Failed to delete SSH private key file %q: %v\n", o.SSHPrivateKeyFile, err)
-			}
-		}
-
-		// though technically not used _on_ the bastion itself, without
-		// these files remaining, the user would not be able to use the SSH
-		// command we provided to connect to the shoot nodes
-		for _, filename := range nodePrivateKeyFiles {
-			if err := os.Remove(filename); err != nil {
-				fmt.Fprintf(o.IOStreams.ErrOut, "Failed to delete node private key %q: %v\n", filename, err)
-			}
-		}
+		removeKeyFiles(o, nodePrivateKeyFiles)
 	} else {
 		fmt.Fprintf(o.IOStreams.Out, "Keeping bastion %s in namespace %s.\n", bastion.Name, bastion.Namespace)
 
@@ -651,6 +778,28 @@ func cleanup(ctx context.Context, o *SSHOptions, gardenClient client.Client, bas
 	}
 }
 
+// removeKeyFiles removes the generated SSH keypair for the bastion and the private keys for the shoot nodes
+func removeKeyFiles(o *SSHOptions, nodePrivateKeyFiles []string) {
+	if o.generatedSSHKeys {
+		if err := os.Remove(o.SSHPublicKeyFile); err != nil {
+			fmt.Fprintf(o.IOStreams.ErrOut, "Failed to delete SSH public key file %q: %v\n", o.SSHPublicKeyFile, err)
+		}
+
+		if err := os.Remove(o.SSHPrivateKeyFile); err != nil {
+			fmt.Fprintf(o.IOStreams.ErrOut, "Failed to delete SSH private key file %q: %v\n", o.SSHPrivateKeyFile, err)
+		}
+	}
+
+	// though technically not used _on_ the bastion itself, without
+	// these files remaining, the user would not be able to use the SSH
+	// command we provided to connect to the shoot nodes
+	for _, filename := range nodePrivateKeyFiles {
+		if err := os.Remove(filename); err != nil {
+			fmt.Fprintf(o.IOStreams.ErrOut, "Failed to delete node private key %q: %v\n", filename, err)
+		}
+	}
+}
+
 func getNodeNamesFromShoot(f util.Factory, prefix string) ([]string, error) {
 	manager, err := f.Manager()
 	if err != nil {
@@ -667,24 +816,36 @@ func getNodeNamesFromShoot(f util.Factory, prefix string) ([]string, error) {
 		return nil, errors.New("no Shoot cluster targeted")
 	}
 
-	// create client for the shoot cluster
-	shootClient, err := manager.ShootClient(f.Context(), currentTarget)
-	if err != nil {
-		return nil, err
-	}
+	allNodeNames, err := util.CachedCompletion(manager, currentTarget, "nodes", func() ([]string, error) {
+		// create client for the shoot cluster
+		shootClient, err := manager.ShootClient(f.Context(), currentTarget)
+		if err != nil {
+			return nil, err
+		}
 
-	// fetch all nodes
-	nodes, err := getNodes(f.Context(), shootClient)
+		// fetch all nodes
+		nodes, err := getNodes(f.Context(), shootClient)
+		if err != nil {
+			return nil, err
+		}
+
+		names := []string{}
+		for _, node := range nodes {
+			names = append(names, node.Name)
+		}
+
+		return names, nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	// collect names, filter by prefix
+	// filter by prefix
 	nodeNames := []string{}
 
-	for _, node := range nodes {
-		if strings.HasPrefix(node.Name, prefix) {
-			nodeNames = append(nodeNames, node.Name)
+	for _, name := range allNodeNames {
+		if strings.HasPrefix(name, prefix) {
+			nodeNames = append(nodeNames, name)
 		}
 	}
 
@@ -742,7 +903,7 @@ func waitForBastion(ctx context.Context, o *SSHOptions, gardenClient client.Clie
 	})
 
 	if waitErr == wait.ErrWaitTimeout {
-		return fmt.Errorf("timed out waiting for the bastion to become ready: %w", lastCheckErr)
+		return base.NewTimeoutError(fmt.Errorf("timed out waiting for the bastion to become ready: %w", lastCheckErr))
 	}
 
 	return waitErr
@@ -750,7 +911,11 @@ func waitForBastion(ctx context.Context, o *SSHOptions, gardenClient client.Clie
 
 func getShootNode(ctx context.Context, o *SSHOptions, shootClient client.Client, currentTarget target.Target) (*corev1.Node, error) {
 	if o.NodeName == "" {
-		return nil, nil
+		if !o.SelectNode {
+			return nil, nil
+		}
+
+		return selectShootNode(ctx, o, shootClient)
 	}
 
 	node := &corev1.Node{}
@@ -761,6 +926,38 @@ func getShootNode(ctx context.Context, o *SSHOptions, shootClient client.Client,
 	return node, nil
 }
 
+// selectShootNode asks the user to select one of the nodes of the shoot cluster
+func selectShootNode(ctx context.Context, o *SSHOptions, shootClient client.Client) (*corev1.Node, error) {
+	nodes, err := getNodes(ctx, shootClient)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(nodes) == 0 {
+		return nil, errors.New("the shoot cluster does not have any nodes")
+	}
+
+	options := make([]string, len(nodes))
+
+	for i, node := range nodes {
+		status := "NotReady"
+		if isNodeReady(node) {
+			status = "Ready"
+		}
+
+		options[i] = fmt.Sprintf("%s (status: %s)", node.Name, status)
+	}
+
+	i, err := util.NewPrompter(o.IOStreams).Filter("Select a node:", options)
+	if err != nil {
+		return nil, err
+	}
+
+	o.NodeName = nodes[i].Name
+
+	return &nodes[i], nil
+}
+
 func remoteShell(ctx context.Context, o *SSHOptions, bastion *operationsv1alpha1.Bastion, nodePrivateKeyFiles []string, node *corev1.Node) error {
 	nodeHostname, err := getNodeHostname(node)
 	if err != nil {
@@ -768,13 +965,47 @@ func remoteShell(ctx context.Context, o *SSHOptions, bastion *operationsv1alpha1
 	}
 
 	bastionAddr := preferredBastionAddress(bastion)
-	connectCmd := sshCommandLine(o, bastionAddr, nodePrivateKeyFiles, nodeHostname)
 
-	fmt.Fprintln(o.IOStreams.Out, "You can open additional SSH sessions using the command below:")
-	fmt.Fprintln(o.IOStreams.Out, "")
-	fmt.Fprintln(o.IOStreams.Out, connectCmd)
-	fmt.Fprintln(o.IOStreams.Out, "")
+	if len(o.Command) == 0 {
+		connectCmd := sshCommandLine(o, bastionAddr, nodePrivateKeyFiles, nodeHostname)
+
+		fmt.Fprintln(o.IOStreams.Out, "You can open additional SSH sessions using the command below:")
+		fmt.Fprintln(o.IOStreams.Out, "")
+		fmt.Fprintln(o.IOStreams.Out, connectCmd)
+		fmt.Fprintln(o.IOStreams.Out, "")
+	}
+
+	args := proxyArgs(o, bastionAddr, nodePrivateKeyFiles)
+	args = append(args, fmt.Sprintf("%s@%s", SSHNodeUsername, nodeHostname))
+	args = append(args, o.Command...)
+
+	return execCommand(ctx, "ssh", args, o)
+}
+
+// remoteCopy copies files from or to the node with scp through the bastion
+func remoteCopy(ctx context.Context, o *SSHOptions, bastion *operationsv1alpha1.Bastion, nodePrivateKeyFiles []string, node *corev1.Node) error {
+	nodeHostname, err := getNodeHostname(node)
+	if err != nil {
+		return err
+	}
+
+	remotePrefix := node.Name + ":"
+	remote := func(path string) string {
+		if strings.HasPrefix(path, remotePrefix) {
+			return fmt.Sprintf("%s@%s:%s", SSHNodeUsername, nodeHostname, strings.TrimPrefix(path, remotePrefix))
+		}
+
+		return path
+	}
+
+	args := proxyArgs(o, preferredBastionAddress(bastion), nodePrivateKeyFiles)
+	args = append(args, remote(o.copySource), remote(o.copyDestination))
 
+	return execCommand(ctx, "scp", args, o)
+}
+
+// proxyArgs returns the options for ssh and scp to connect to a node through the bastion
+func proxyArgs(o *SSHOptions, bastionAddr string, nodePrivateKeyFiles []string) []string {
 	proxyPrivateKeyFlag := ""
 	if o.SSHPrivateKeyFile != "" {
 		proxyPrivateKeyFlag = fmt.Sprintf(" -o IdentitiesOnly=yes -i %s", o.SSHPrivateKeyFile)
@@ -797,9 +1028,7 @@ func remoteShell(ctx context.Context, o *SSHOptions, bastion *operationsv1alpha1
 		args = append(args, "-i", file)
 	}
 
-	args = append(args, fmt.Sprintf("%s@%s", SSHNodeUsername, nodeHostname))
-
-	return execCommand(ctx, "ssh", args, o)
+	return args
 }
 
 // waitForSignal informs the user about their SSHOptions and keeps the
@@ -897,6 +1126,18 @@ func waitForSignal(ctx context.Context, o *SSHOptions, shootClient client.Client
 		fmt.Fprintln(o.IOStreams.Out, "")
 	}
 
+	if o.Output == outputSSHConfig {
+		if err := printSSHConfig(ctx, o, shootClient, bastion, nodePrivateKeyFiles, node); err != nil {
+			return err
+		}
+
+		fmt.Fprintln(o.IOStreams.Out, "Press Ctrl-C to stop gardenctl, after which the bastion will be removed.")
+
+		<-signalChan
+
+		return nil
+	}
+
 	fmt.Fprintln(o.IOStreams.Out, "Connect to shoot nodes by using the bastion as a proxy/jump host, for example:")
 	fmt.Fprintln(o.IOStreams.Out, "")
 	fmt.Fprintln(o.IOStreams.Out, connectCmd)
@@ -919,6 +1160,57 @@ func isNodeReady(node corev1.Node) bool {
 	return false
 }
 
+// printSSHConfig writes an ssh_config snippet for the bastion and the node, or all nodes of the
+// Shoot cluster if no node is given. The nodes use the bastion as jump host and can be
+// accessed by their names, e.g. with "ssh -F FILE NODE_NAME".
+func printSSHConfig(ctx context.Context, o *SSHOptions, shootClient client.Client, bastion *operationsv1alpha1.Bastion, nodePrivateKeyFiles []string, node *corev1.Node) error {
+	nodes := []corev1.Node{}
+
+	if node != nil {
+		nodes = append(nodes, *node)
+	} else {
+		var err error
+
+		nodes, err = getNodes(ctx, shootClient)
+		if err != nil {
+			return fmt.Errorf("failed to list shoot cluster nodes: %w", err)
+		}
+	}
+
+	out := o.commandOut()
+
+	fmt.Fprintf(out, "Host %s\n", bastion.Name)
+	fmt.Fprintf(out, "  HostName %s\n", preferredBastionAddress(bastion))
+	fmt.Fprintf(out, "  User %s\n", SSHBastionUsername)
+	fmt.Fprintln(out, "  StrictHostKeyChecking no")
+
+	if o.SSHPrivateKeyFile != "" {
+		fmt.Fprintln(out, "  IdentitiesOnly yes")
+		fmt.Fprintf(out, "  IdentityFile %s\n", o.SSHPrivateKeyFile)
+	}
+
+	for i := range nodes {
+		nodeHostname, err := getNodeHostname(&nodes[i])
+		if err != nil {
+			return fmt.Errorf("failed to determine hostname for node %s: %w", nodes[i].Name, err)
+		}
+
+		fmt.Fprintln(out, "")
+		fmt.Fprintf(out, "Host %s\n", nodes[i].Name)
+		fmt.Fprintf(out, "  HostName %s\n", nodeHostname)
+		fmt.Fprintf(out, "  User %s\n", SSHNodeUsername)
+		fmt.Fprintf(out, "  ProxyJump %s\n", bastion.Name)
+		fmt.Fprintln(out, "  StrictHostKeyChecking no")
+		fmt.Fprintln(out, "  IdentitiesOnly yes")
+
+		for _, file := range nodePrivateKeyFiles {
+			fmt.Fprintf(out, "  IdentityFile %s\n", file)
+		}
+	}
+
+	return nil
+}
+
 func sshCommandLine(o *SSHOptions, bastionAddr string, nodePrivateKeyFiles []string, nodeName string) string {
 	proxyPrivateKeyFlag := ""
 	if o.SSHPrivateKeyFile != "" {
@@ -0,0 +1,124 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ssh
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
+)
+
+// NewCmdSCP returns a new scp command.
+func NewCmdSCP(f util.Factory, o *SCPOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "scp SOURCE DESTINATION",
+		Short: "Copy files from or to a Shoot cluster's node",
+		Long: `Copy files from or to a Shoot cluster's node through a bastion host.
+Either the source or the destination must be a remote path of the form NODE_NAME:PATH.
+The bastion host is created for the targeted shoot and deleted after the files have been copied.`,
+		Example: `# copy the kubelet logs of a node to the current directory
+gardenctl scp my-node:/var/log/kubelet.log .
+
+# copy a local file to a node
+gardenctl scp ./script.sh my-node:/tmp/script.sh`,
+		Args: cobra.ExactArgs(2),
+		RunE: base.WrapRunE(o, f),
+	}
+
+	cmd.Flags().StringArrayVar(&o.CIDRs, "cidr", nil, "CIDRs to allow access to the bastion host; if not given, the bastion.ingressCIDRs of the gardenctl configuration are used or your system's public IPs (v4 and v6) are auto-detected.")
+	cmd.Flags().StringVar(&o.SSHPublicKeyFile, "public-key-file", "", "Path to the file that contains a public SSH key. If not given, a temporary keypair will be generated.")
+	cmd.Flags().DurationVar(&o.WaitTimeout, "wait-timeout", o.WaitTimeout, "Maximum duration to wait for the bastion to become available.")
+	cmd.Flags().BoolVar(&o.KeepBastion, "keep-bastion", o.KeepBastion, "Do not delete immediately when gardenctl exits (Bastions will be garbage-collected after some time)")
+
+	return cmd
+}
+
+// SCPOptions is a struct to support scp command
+type SCPOptions struct {
+	SSHOptions
+
+	// Source is the path of the file that is copied
+	Source string
+
+	// Destination is the path the file is copied to
+	Destination string
+}
+
+// NewSCPOptions returns initialized SCPOptions
+func NewSCPOptions(ioStreams util.IOStreams) *SCPOptions {
+	return &SCPOptions{
+		SSHOptions: SSHOptions{
+			Options: base.Options{
+				IOStreams: ioStreams,
+			},
+			Interactive: true,
+			WaitTimeout: 10 * time.Minute,
+		},
+	}
+}
+
+// Complete adapts from the command line args to the data required.
+func (o *SCPOptions) Complete(f util.Factory, cmd *cobra.Command, args []string) error {
+	if err := o.SSHOptions.Complete(f, nil, nil); err != nil {
+		return err
+	}
+
+	if len(args) > 0 {
+		o.Source = strings.TrimSpace(args[0])
+	}
+
+	if len(args) > 1 {
+		o.Destination = strings.TrimSpace(args[1])
+	}
+
+	if nodeName, ok := remoteNodeName(o.Source); ok {
+		o.NodeName = nodeName
+	} else if nodeName, ok := remoteNodeName(o.Destination); ok {
+		o.NodeName = nodeName
+	}
+
+	o.copySource = o.Source
+	o.copyDestination = o.Destination
+
+	return nil
+}
+
+// Validate validates the provided SCPOptions
+func (o *SCPOptions) Validate() error {
+	if o.Source == "" || o.Destination == "" {
+		return errors.New("source and destination are required")
+	}
+
+	_, sourceIsRemote := remoteNodeName(o.Source)
+	_, destinationIsRemote := remoteNodeName(o.Destination)
+
+	if sourceIsRemote && destinationIsRemote {
+		return errors.New("copying files between two remote paths is not supported")
+	}
+
+	if !sourceIsRemote && !destinationIsRemote {
+		return errors.New("either the source or the destination must be a remote path of the form NODE_NAME:PATH")
+	}
+
+	return o.SSHOptions.Validate()
+}
+
+// remoteNodeName returns the node name of a remote path of the form NODE_NAME:PATH.
+// Like for scp, a path that contains a slash before the first colon is a local path.
+func remoteNodeName(path string) (string, bool) {
+	i := strings.Index(path, ":")
+	if i <= 0 || strings.Contains(path[:i], "/") {
+		return "", false
+	}
+
+	return path[:i], true
+}
@@ -18,9 +18,26 @@ import (
 // NewCmdSSH returns a new ssh command.
 func NewCmdSSH(f util.Factory, o *SSHOptions) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "ssh [NODE_NAME]",
+		Use:   "ssh [NODE_NAME] [-- COMMAND [ARGS...]]",
 		Short: "Establish an SSH connection to a Shoot cluster's node",
-		Args:  cobra.MaximumNArgs(1),
+		Long: `Establish an SSH connection to a Shoot cluster's node.
+If a COMMAND is given after "--", it is executed on the node instead of opening an interactive shell.
+The output of the command is written to stdout, all other messages are written to stderr.`,
+		Example: `# open an interactive shell on a node
+gardenctl ssh my-node
+
+# execute a command on a node
+gardenctl ssh my-node -- journalctl -u kubelet --since "1 hour ago"
+
+# provision a bastion and write an ssh_config for the nodes, which can be used with ssh -F or included in ~/.ssh/config
+gardenctl ssh --no-connect --output ssh-config > gardener-ssh-config`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if i := cmd.ArgsLenAtDash(); i >= 0 {
+				args = args[:i]
+			}
+
+			return cobra.MaximumNArgs(1)(cmd, args)
+		},
 		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			if len(args) != 0 {
 				return nil, cobra.ShellCompDirectiveNoFileComp
@@ -38,10 +55,16 @@ func NewCmdSSH(f util.Factory, o *SSHOptions) *cobra.Command {
 	}
 
 	cmd.Flags().BoolVar(&o.Interactive, "interactive", o.Interactive, "Open an SSH connection instead of just providing the bastion host (only if NODE_NAME is provided).")
-	cmd.Flags().StringArrayVar(&o.CIDRs, "cidr", nil, "CIDRs to allow access to the bastion host; if not given, your system's public IPs (v4 and v6) are auto-detected.")
+	cmd.Flags().BoolVar(&o.NoConnect, "no-connect", o.NoConnect, "Only provision the bastion host and keep it alive, without opening an SSH connection.")
+	cmd.Flags().StringVarP(&o.Output, "output", "o", o.Output, "Print an ssh_config snippet for the bastion and the nodes to stdout instead of the SSH command line. Must be 'ssh-config'.")
+	cmd.Flags().StringArrayVar(&o.CIDRs, "cidr", nil, "CIDRs to allow access to the bastion host; if not given, the bastion.ingressCIDRs of the gardenctl configuration are used or your system's public IPs (v4 and v6) are auto-detected.")
 	cmd.Flags().StringVar(&o.SSHPublicKeyFile, "public-key-file", "", "Path to the file that contains a public SSH key. If not given, a temporary keypair will be generated.")
 	cmd.Flags().DurationVar(&o.WaitTimeout, "wait-timeout", o.WaitTimeout, "Maximum duration to wait for the bastion to become available.")
+	cmd.Flags().BoolVar(&o.SelectNode, "select-node", o.SelectNode, "Select the node to connect to from a list of all nodes of the Shoot cluster (only if NODE_NAME is not provided).")
 	cmd.Flags().BoolVar(&o.KeepBastion, "keep-bastion", o.KeepBastion, "Do not delete immediately when gardenctl exits (Bastions will be garbage-collected after some time)")
 
+	cmd.AddCommand(NewCmdListBastions(f, NewListBastionsOptions(o.IOStreams)))
+	cmd.AddCommand(NewCmdCleanup(f, NewCleanupOptions(o.IOStreams)))
+
 	return cmd
 }
@@ -12,6 +12,7 @@ import (
 	"io"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"time"
 
 	gardencorev1alpha1 "github.com/gardener/gardener/pkg/apis/core/v1alpha1"
@@ -86,7 +87,9 @@ var _ = Describe("SSH Command", func() {
 		clientProvider      *targetmocks.MockClientProvider
 		cfg                 *config.Config
 		streams             util.IOStreams
+		in                  *util.SafeBytesBuffer
 		out                 *util.SafeBytesBuffer
+		errOut              *util.SafeBytesBuffer
 		factory             *internalfake.Factory
 		ctx                 context.Context
 		cancel              context.CancelFunc
@@ -124,6 +127,10 @@ var _ = Describe("SSH Command", func() {
 			return bastionName, nil
 		})
 
+		ssh.SetBastionOwnerProvider(func() (string, error) {
+			return "alice@laptop", nil
+		})
+
 		// simulate the user immediately exiting via Ctrl-C
 		ssh.SetCreateSignalChannel(func() chan os.Signal {
 			signalChan := make(chan os.Signal, 1)
@@ -209,7 +216,7 @@ var _ = Describe("SSH Command", func() {
 			},
 		}
 
-		streams, _, out, _ = util.NewTestIOStreams()
+		streams, in, out, errOut = util.NewTestIOStreams()
 
 		ctrl = gomock.NewController(GinkgoT())
 
@@ -249,8 +256,12 @@ var _ = Describe("SSH Command", func() {
 		})
 
 		It("should reject bad options", func() {
+			dir, err := os.MkdirTemp("", "gctlv2")
+			Expect(err).NotTo(HaveOccurred())
+			defer os.RemoveAll(dir)
+
 			o := ssh.NewSSHOptions(streams)
-			cmd := ssh.NewCmdSSH(&util.FactoryImpl{}, o)
+			cmd := ssh.NewCmdSSH(&util.FactoryImpl{ConfigFile: filepath.Join(dir, "gardenctl-v2.yaml")}, o)
 
 			Expect(cmd.RunE(cmd, nil)).NotTo(Succeed())
 		})
@@ -294,6 +305,46 @@ var _ = Describe("SSH Command", func() {
 			Expect(err).To(HaveOccurred())
 		})
 
+		It("should print an ssh_config for all nodes and then wait for user interrupt", func() {
+			options := ssh.NewSSHOptions(streams)
+			options.NoConnect = true
+			options.Output = "ssh-config"
+			cmd := ssh.NewCmdSSH(factory, options)
+
+			go waitForBastionThenPatchStatus(ctx, gardenClient, bastionName, *testProject.Spec.Namespace, func(status *operationsv1alpha1.BastionStatus) {
+				status.Ingress = &corev1.LoadBalancerIngress{
+					IP: bastionIP,
+				}
+				status.Conditions = []gardencorev1alpha1.Condition{{
+					Type:   "BastionReady",
+					Status: gardencorev1alpha1.ConditionTrue,
+					Reason: "Testing",
+				}}
+			})
+
+			Expect(cmd.RunE(cmd, nil)).To(Succeed())
+
+			Expect(out.String()).To(Equal(fmt.Sprintf(`Host %s
+  HostName %s
+  User %s
+  StrictHostKeyChecking no
+  IdentitiesOnly yes
+  IdentityFile %s
+
+Host %s
+  HostName %s
+  User %s
+  ProxyJump %s
+  StrictHostKeyChecking no
+  IdentitiesOnly yes
+  IdentityFile %s
+`,
+				bastionName, bastionIP, ssh.SSHBastionUsername, options.SSHPrivateKeyFile,
+				testNode.Name, nodeHostname, ssh.SSHNodeUsername, bastionName, nodePrivateKeyFile,
+			)))
+			Expect(errOut.String()).To(ContainSubstring("Press Ctrl-C to stop gardenctl"))
+		})
+
 		It("should connect to a given node", func() {
 			options := ssh.NewSSHOptions(streams)
 			cmd := ssh.NewCmdSSH(factory, options)
@@ -356,6 +407,111 @@ var _ = Describe("SSH Command", func() {
 			Expect(err).To(HaveOccurred())
 		})
 
+		It("should connect to a node selected by the user", func() {
+			options := ssh.NewSSHOptions(streams)
+			options.SelectNode = true
+			cmd := ssh.NewCmdSSH(factory, options)
+
+			go waitForBastionThenPatchStatus(ctx, gardenClient, bastionName, *testProject.Spec.Namespace, func(status *operationsv1alpha1.BastionStatus) {
+				status.Ingress = &corev1.LoadBalancerIngress{
+					IP: bastionIP,
+				}
+				status.Conditions = []gardencorev1alpha1.Condition{{
+					Type:   "BastionReady",
+					Status: gardencorev1alpha1.ConditionTrue,
+					Reason: "Testing",
+				}}
+			})
+
+			var connectedHost string
+
+			ssh.SetExecCommand(func(ctx context.Context, command string, args []string, o *ssh.SSHOptions) error {
+				connectedHost = args[len(args)-1]
+				return nil
+			})
+
+			_, err := in.Write([]byte("1\n"))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(cmd.RunE(cmd, nil)).To(Succeed())
+			Expect(out.String()).To(ContainSubstring("Select a node:\n  1) node1 (status: NotReady)\n"))
+			Expect(options.NodeName).To(Equal(testNode.Name))
+			Expect(connectedHost).To(Equal(fmt.Sprintf("%s@%s", ssh.SSHNodeUsername, nodeHostname)))
+		})
+
+		It("should execute a command on a given node", func() {
+			options := ssh.NewSSHOptions(streams)
+			cmd := ssh.NewCmdSSH(factory, options)
+
+			go waitForBastionThenPatchStatus(ctx, gardenClient, bastionName, *testProject.Spec.Namespace, func(status *operationsv1alpha1.BastionStatus) {
+				status.Ingress = &corev1.LoadBalancerIngress{
+					IP: bastionIP,
+				}
+				status.Conditions = []gardencorev1alpha1.Condition{{
+					Type:   "BastionReady",
+					Status: gardencorev1alpha1.ConditionTrue,
+					Reason: "Testing",
+				}}
+			})
+
+			var executedArgs []string
+
+			ssh.SetExecCommand(func(ctx context.Context, command string, args []string, o *ssh.SSHOptions) error {
+				Expect(command).To(Equal("ssh"))
+				executedArgs = args
+
+				_, err := fmt.Fprint(o.CommandOutput(), "Linux")
+
+				return err
+			})
+
+			Expect(cmd.Flags().Parse([]string{testNode.Name, "--", "uname", "-a"})).To(Succeed())
+			Expect(cmd.RunE(cmd, cmd.Flags().Args())).To(Succeed())
+
+			Expect(options.NodeName).To(Equal(testNode.Name))
+			Expect(executedArgs[len(executedArgs)-3:]).To(Equal([]string{
+				fmt.Sprintf("%s@%s", ssh.SSHNodeUsername, nodeHostname),
+				"uname",
+				"-a",
+			}))
+			Expect(out.String()).To(Equal("Linux"))
+			Expect(errOut.String()).To(ContainSubstring(bastionIP))
+			Expect(errOut.String()).NotTo(ContainSubstring("You can open additional SSH sessions"))
+		})
+
+		It("should copy a file from a given node", func() {
+			options := ssh.NewSCPOptions(streams)
+			cmd := ssh.NewCmdSCP(factory, options)
+
+			go waitForBastionThenPatchStatus(ctx, gardenClient, bastionName, *testProject.Spec.Namespace, func(status *operationsv1alpha1.BastionStatus) {
+				status.Ingress = &corev1.LoadBalancerIngress{
+					IP: bastionIP,
+				}
+				status.Conditions = []gardencorev1alpha1.Condition{{
+					Type:   "BastionReady",
+					Status: gardencorev1alpha1.ConditionTrue,
+					Reason: "Testing",
+				}}
+			})
+
+			var executedArgs []string
+
+			ssh.SetExecCommand(func(ctx context.Context, command string, args []string, o *ssh.SSHOptions) error {
+				Expect(command).To(Equal("scp"))
+				executedArgs = args
+
+				return nil
+			})
+
+			Expect(cmd.RunE(cmd, []string{testNode.Name + ":/var/log/kubelet.log", "./logs/"})).To(Succeed())
+
+			Expect(options.NodeName).To(Equal(testNode.Name))
+			Expect(executedArgs[len(executedArgs)-2:]).To(Equal([]string{
+				fmt.Sprintf("%s@%s:/var/log/kubelet.log", ssh.SSHNodeUsername, nodeHostname),
+				"./logs/",
+			}))
+		})
+
 		It("should keep the bastion alive", func() {
 			options := ssh.NewSSHOptions(streams)
 			options.KeepBastion = true // we need to assert its annotations later
@@ -407,6 +563,22 @@ var _ = Describe("SSH Command", func() {
 			bastion := &operationsv1alpha1.Bastion{}
 			Expect(gardenClient.Get(ctx, key, bastion)).To(Succeed())
 			Expect(bastion.Annotations).To(HaveKeyWithValue(corev1beta1constants.GardenerOperation, corev1beta1constants.GardenerOperationKeepalive))
+			Expect(bastion.Annotations).To(HaveKeyWithValue(ssh.BastionOwnerAnnotation, "alice@laptop"))
+			Expect(bastion.Labels).To(HaveKeyWithValue(ssh.BastionManagedByLabel, ssh.BastionManagedByValue))
+		})
+	})
+
+	Describe("Complete", func() {
+		It("should use the bastion ingress CIDRs of the configuration", func() {
+			cfg.Bastion = &config.Bastion{IngressCIDRs: []string{"10.0.0.0/8"}}
+
+			options := ssh.NewSSHOptions(streams)
+			Expect(options.Complete(factory, nil, nil)).To(Succeed())
+			defer os.Remove(options.SSHPublicKeyFile)
+			defer os.Remove(options.SSHPrivateKeyFile)
+
+			Expect(options.CIDRs).To(Equal([]string{"10.0.0.0/8"}))
+			Expect(options.AutoDetected).To(BeFalse())
 		})
 	})
 
@@ -481,6 +653,67 @@ var _ = Describe("SSH Options", func() {
 		Expect(o.Validate()).NotTo(Succeed())
 	})
 
+	It("should not allow to select a node if a node name is given", func() {
+		o := ssh.NewSSHOptions(streams)
+		o.CIDRs = []string{"8.8.8.8/32"}
+		o.SSHPublicKeyFile = publicSSHKeyFile
+		o.NodeName = "node1"
+		o.SelectNode = true
+
+		Expect(o.Validate()).To(MatchError("--select-node must not be used together with a node name"))
+	})
+
+	It("should require --no-connect to print an ssh_config for a given node", func() {
+		o := ssh.NewSSHOptions(streams)
+		o.CIDRs = []string{"8.8.8.8/32"}
+		o.SSHPublicKeyFile = publicSSHKeyFile
+		o.NodeName = "node1"
+		o.Output = "ssh-config"
+
+		Expect(o.Validate()).To(MatchError("--output=ssh-config requires --no-connect"))
+
+		o.Interactive = false
+		Expect(o.Validate()).To(Succeed())
+
+		o.Output = "yaml"
+		Expect(o.Validate()).To(MatchError(`--output must be "ssh-config"`))
+	})
+
+	It("should require a node name to execute a command", func() {
+		o := ssh.NewSSHOptions(streams)
+		o.CIDRs = []string{"8.8.8.8/32"}
+		o.SSHPublicKeyFile = publicSSHKeyFile
+		o.Command = []string{"uname"}
+
+		Expect(o.Validate()).To(MatchError("a node name is required to execute a command"))
+	})
+
+	It("should require exactly one remote path to copy files", func() {
+		o := ssh.NewSCPOptions(streams)
+		o.CIDRs = []string{"8.8.8.8/32"}
+		o.SSHPublicKeyFile = publicSSHKeyFile
+
+		o.Source = "./foo"
+		o.Destination = "/tmp/bar"
+		Expect(o.Validate()).To(MatchError("either the source or the destination must be a remote path of the form NODE_NAME:PATH"))
+
+		o.Source = "node1:/foo"
+		o.Destination = "node2:/bar"
+		Expect(o.Validate()).To(MatchError("copying files between two remote paths is not supported"))
+	})
+
+	It("should warn if the bastion is accessible from any address", func() {
+		var errOut *util.SafeBytesBuffer
+		streams, _, _, errOut = util.NewTestIOStreams()
+
+		o := ssh.NewSSHOptions(streams)
+		o.CIDRs = []string{"0.0.0.0/0"}
+		o.SSHPublicKeyFile = publicSSHKeyFile
+
+		Expect(o.Validate()).To(Succeed())
+		Expect(errOut.String()).To(Equal("Warning: CIDR \"0.0.0.0/0\" allows access to the bastion from any address\n"))
+	})
+
 	It("should require a public SSH key file", func() {
 		o := ssh.NewSSHOptions(streams)
 		o.CIDRs = []string{"8.8.8.8/32"}
@@ -0,0 +1,13 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package status
+
+import "time"
+
+func SetPollInterval(d time.Duration) {
+	pollInterval = d
+}
@@ -0,0 +1,329 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package status
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	corev1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/gardener/gardenctl-v2/internal/gardenclient"
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
+)
+
+// pollInterval is the time in-between status checks of the shoot in watch mode
+var pollInterval = 5 * time.Second
+
+// NewCmdStatus returns a new status command.
+func NewCmdStatus(f util.Factory, o *StatusOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show the status of the last operation of the targeted shoot",
+		Long: `Show the status of the last operation of the targeted shoot, e.g. the progress of a reconciliation.
+With --watch, the progress is printed until the operation has finished. The command exits with a non-zero exit code
+if the operation failed or the timeout is exceeded, so it can be used in CI pipelines that create or update shoots.`,
+		Example: `# show the status of the last operation of the targeted shoot
+gardenctl status
+
+# wait up to 30 minutes until the reconciliation of the targeted shoot has finished
+gardenctl status --watch --timeout 30m`,
+		Args: cobra.NoArgs,
+		RunE: base.WrapRunE(o, f),
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// StatusOptions is a struct to support status command
+// nolint
+type StatusOptions struct {
+	base.Options
+
+	// Watch prints the progress of the last operation until it has finished
+	Watch bool
+
+	// Timeout is the maximum duration to watch the last operation, zero means no timeout
+	Timeout time.Duration
+}
+
+// NewStatusOptions returns initialized StatusOptions
+func NewStatusOptions(ioStreams util.IOStreams) *StatusOptions {
+	return &StatusOptions{
+		Options: base.Options{
+			IOStreams: ioStreams,
+		},
+	}
+}
+
+// AddFlags binds the command options to a given flagset
+func (o *StatusOptions) AddFlags(flags *pflag.FlagSet) {
+	flags.BoolVarP(&o.Watch, "watch", "w", o.Watch, "Print the progress of the last operation until it has finished.")
+	flags.DurationVar(&o.Timeout, "timeout", o.Timeout, "Maximum duration to watch the last operation, e.g. 30m. Zero means no timeout.")
+}
+
+// Validate validates the provided StatusOptions
+func (o *StatusOptions) Validate() error {
+	if o.Timeout < 0 {
+		return errors.New("the timeout must not be negative")
+	}
+
+	if o.Timeout > 0 && !o.Watch {
+		return errors.New("--timeout can only be used together with --watch")
+	}
+
+	return nil
+}
+
+// Run executes the command
+func (o *StatusOptions) Run(f util.Factory) error {
+	manager, err := f.Manager()
+	if err != nil {
+		return err
+	}
+
+	currentTarget, err := manager.CurrentTarget()
+	if err != nil {
+		return fmt.Errorf("failed to get current target: %w", err)
+	}
+
+	if currentTarget.ShootName() == "" {
+		return errors.New("no Shoot cluster targeted")
+	}
+
+	gardenClient, err := manager.GardenClient(currentTarget.GardenName())
+	if err != nil {
+		return fmt.Errorf("failed to create garden cluster client: %w", err)
+	}
+
+	ctx := f.Context()
+
+	shoot, err := util.ShootForTarget(ctx, gardenClient, currentTarget)
+	if err != nil {
+		return err
+	}
+
+	if !o.Watch {
+		printStatus(o.IOStreams.Out, shoot)
+		return nil
+	}
+
+	return WatchOperation(ctx, o.IOStreams.Out, f.Clock(), gardenClient, client.ObjectKeyFromObject(shoot), o.Timeout)
+}
+
+// WatchOperation polls the shoot and prints every change of its last operation to out until the operation has finished.
+// An operation that was requested with the gardener.cloud/operation annotation is awaited until Gardener has picked it up.
+// It returns an error if the operation failed or was aborted, or if the timeout is exceeded. Zero means no timeout.
+func WatchOperation(ctx context.Context, out io.Writer, clock util.Clock, gardenClient gardenclient.Client, key client.ObjectKey, timeout time.Duration) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	var (
+		last       string
+		lastErrors = map[string]bool{}
+		result     error
+	)
+
+	err := wait.PollImmediateUntil(pollInterval, func() (bool, error) {
+		shoot, err := gardenClient.GetShoot(ctx, key.Namespace, key.Name)
+		if err != nil {
+			return false, err
+		}
+
+		op := shoot.Status.LastOperation
+		if op == nil {
+			if last == "" {
+				fmt.Fprintf(out, "%s Shoot %s has no last operation yet\n", timestamp(clock), key)
+				last = "<none>"
+			}
+
+			return false, nil
+		}
+
+		if current := operationString(op); current != last {
+			fmt.Fprintf(out, "%s %s\n", timestamp(clock), current)
+			last = current
+		}
+
+		for _, lastError := range shoot.Status.LastErrors {
+			if current := lastErrorString(lastError); !lastErrors[current] {
+				fmt.Fprintf(out, "%s   %s\n", timestamp(clock), current)
+				lastErrors[current] = true
+			}
+		}
+
+		if shoot.Status.ObservedGeneration < shoot.Generation || shoot.Annotations[corev1beta1constants.GardenerOperation] != "" {
+			return false, nil
+		}
+
+		switch op.State {
+		case gardencorev1beta1.LastOperationStateSucceeded:
+			return true, nil
+		case gardencorev1beta1.LastOperationStateFailed, gardencorev1beta1.LastOperationStateAborted:
+			result = fmt.Errorf("%s of shoot %s %s%s", op.Type, key, strings.ToLower(string(op.State)), errorCodesString(shoot.Status.LastErrors))
+			return true, nil
+		}
+
+		return false, nil
+	}, ctx.Done())
+
+	if err == wait.ErrWaitTimeout {
+		return base.NewTimeoutError(fmt.Errorf("timed out waiting for the last operation of shoot %s to finish", key))
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return result
+}
+
+// WatchDeletion polls the shoot and prints every change of its last operation to out until the shoot has been deleted.
+// It returns an error if the deletion failed or was aborted, or if the timeout is exceeded. Zero means no timeout.
+func WatchDeletion(ctx context.Context, out io.Writer, clock util.Clock, gardenClient gardenclient.Client, key client.ObjectKey, timeout time.Duration) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	var (
+		last       string
+		lastErrors = map[string]bool{}
+		result     error
+	)
+
+	err := wait.PollImmediateUntil(pollInterval, func() (bool, error) {
+		shoot, err := gardenClient.GetShoot(ctx, key.Namespace, key.Name)
+		if apierrors.IsNotFound(err) {
+			fmt.Fprintf(out, "%s Shoot %s has been deleted\n", timestamp(clock), key)
+			return true, nil
+		}
+
+		if err != nil {
+			return false, err
+		}
+
+		op := shoot.Status.LastOperation
+		if op == nil || op.Type != gardencorev1beta1.LastOperationTypeDelete {
+			return false, nil
+		}
+
+		if current := operationString(op); current != last {
+			fmt.Fprintf(out, "%s %s\n", timestamp(clock), current)
+			last = current
+		}
+
+		for _, lastError := range shoot.Status.LastErrors {
+			if current := lastErrorString(lastError); !lastErrors[current] {
+				fmt.Fprintf(out, "%s   %s\n", timestamp(clock), current)
+				lastErrors[current] = true
+			}
+		}
+
+		if op.State == gardencorev1beta1.LastOperationStateFailed || op.State == gardencorev1beta1.LastOperationStateAborted {
+			result = fmt.Errorf("%s of shoot %s %s%s", op.Type, key, strings.ToLower(string(op.State)), errorCodesString(shoot.Status.LastErrors))
+			return true, nil
+		}
+
+		return false, nil
+	}, ctx.Done())
+
+	if err == wait.ErrWaitTimeout {
+		return base.NewTimeoutError(fmt.Errorf("timed out waiting for shoot %s to be deleted", key))
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return result
+}
+
+// printStatus prints the last operation and the last errors of the shoot
+func printStatus(out io.Writer, shoot *gardencorev1beta1.Shoot) {
+	fmt.Fprintf(out, "Shoot:      %s/%s\n", shoot.Namespace, shoot.Name)
+
+	op := shoot.Status.LastOperation
+	if op == nil {
+		fmt.Fprintln(out, "Operation:  <none>")
+		return
+	}
+
+	fmt.Fprintf(out, "Operation:  %s %s (%d%%)\n", op.Type, op.State, op.Progress)
+	fmt.Fprintf(out, "Updated:    %s\n", op.LastUpdateTime.UTC().Format(time.RFC3339))
+	fmt.Fprintf(out, "Message:    %s\n", op.Description)
+
+	if len(shoot.Status.LastErrors) > 0 {
+		fmt.Fprintln(out, "Errors:")
+
+		for _, lastError := range shoot.Status.LastErrors {
+			fmt.Fprintf(out, "  %s\n", lastErrorString(lastError))
+		}
+	}
+}
+
+func timestamp(clock util.Clock) string {
+	return clock.Now().Format("15:04:05")
+}
+
+func operationString(op *gardencorev1beta1.LastOperation) string {
+	return fmt.Sprintf("%s %s (%d%%): %s", op.Type, op.State, op.Progress, op.Description)
+}
+
+func lastErrorString(lastError gardencorev1beta1.LastError) string {
+	s := lastError.Description
+
+	if lastError.TaskID != nil {
+		s = fmt.Sprintf("task %s: %s", *lastError.TaskID, s)
+	}
+
+	if len(lastError.Codes) > 0 {
+		codes := make([]string, len(lastError.Codes))
+		for i, code := range lastError.Codes {
+			codes[i] = string(code)
+		}
+
+		s = fmt.Sprintf("[%s] %s", strings.Join(codes, ", "), s)
+	}
+
+	return s
+}
+
+func errorCodesString(lastErrors []gardencorev1beta1.LastError) string {
+	codes := []string{}
+
+	for _, lastError := range lastErrors {
+		for _, code := range lastError.Codes {
+			codes = append(codes, string(code))
+		}
+	}
+
+	if len(codes) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf(" with error codes %s", strings.Join(codes, ", "))
+}
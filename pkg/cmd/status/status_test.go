@@ -0,0 +1,249 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package status_test
+
+import (
+	"context"
+	"time"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	internalfake "github.com/gardener/gardenctl-v2/internal/fake"
+	"github.com/gardener/gardenctl-v2/internal/gardenclient"
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
+	cmdstatus "github.com/gardener/gardenctl-v2/pkg/cmd/status"
+	"github.com/gardener/gardenctl-v2/pkg/config"
+	"github.com/gardener/gardenctl-v2/pkg/target"
+	targetmocks "github.com/gardener/gardenctl-v2/pkg/target/mocks"
+)
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time {
+	return time.Time(c)
+}
+
+var _ = Describe("Status Command", func() {
+	const (
+		gardenName  = "mygarden"
+		projectName = "prod1"
+		shootName   = "myshoot"
+	)
+
+	var (
+		ctrl           *gomock.Controller
+		streams        util.IOStreams
+		out            *util.SafeBytesBuffer
+		factory        *internalfake.Factory
+		targetProvider *internalfake.TargetProvider
+		gardenClient   client.Client
+		shoot          *gardencorev1beta1.Shoot
+		options        *cmdstatus.StatusOptions
+	)
+
+	BeforeEach(func() {
+		now := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+
+		cfg := &config.Config{
+			LinkKubeconfig: pointer.Bool(false),
+			Gardens: []config.Garden{{
+				Name:       gardenName,
+				Kubeconfig: "/not/a/real/kubeconfig",
+			}},
+		}
+
+		project := &gardencorev1beta1.Project{
+			ObjectMeta: metav1.ObjectMeta{Name: projectName},
+			Spec:       gardencorev1beta1.ProjectSpec{Namespace: pointer.String("garden-prod1")},
+		}
+
+		shoot = &gardencorev1beta1.Shoot{
+			ObjectMeta: metav1.ObjectMeta{Name: shootName, Namespace: "garden-prod1", Generation: 2},
+			Status: gardencorev1beta1.ShootStatus{
+				ObservedGeneration: 2,
+				LastOperation: &gardencorev1beta1.LastOperation{
+					Type:           gardencorev1beta1.LastOperationTypeReconcile,
+					State:          gardencorev1beta1.LastOperationStateSucceeded,
+					Progress:       100,
+					Description:    "Shoot cluster has been successfully reconciled.",
+					LastUpdateTime: metav1.NewTime(now.Add(-time.Hour)),
+				},
+			},
+		}
+
+		streams, _, out, _ = util.NewTestIOStreams()
+
+		ctrl = gomock.NewController(GinkgoT())
+
+		targetProvider = internalfake.NewFakeTargetProvider(target.NewTarget(gardenName, projectName, "", shootName))
+
+		// the garden client is created lazily, so that the tests can modify the shoot beforehand
+		gardenClient = nil
+		clientProvider := targetmocks.NewMockClientProvider(ctrl)
+		clientConfig, err := cfg.ClientConfig(gardenName)
+		Expect(err).ToNot(HaveOccurred())
+		clientProvider.EXPECT().FromClientConfig(gomock.Eq(clientConfig)).DoAndReturn(func(interface{}) (client.Client, error) {
+			if gardenClient == nil {
+				gardenClient = internalfake.NewClientWithObjects(project, shoot)
+			}
+
+			return gardenClient, nil
+		}).AnyTimes()
+
+		factory = internalfake.NewFakeFactory(cfg, fixedClock(now), clientProvider, targetProvider)
+		factory.ContextImpl = context.Background()
+
+		options = cmdstatus.NewStatusOptions(streams)
+
+		cmdstatus.SetPollInterval(10 * time.Millisecond)
+	})
+
+	AfterEach(func() {
+		cmdstatus.SetPollInterval(5 * time.Second)
+		ctrl.Finish()
+	})
+
+	It("should print the last operation of the targeted shoot", func() {
+		shoot.Status.LastErrors = []gardencorev1beta1.LastError{{
+			Description: "quota exceeded",
+			TaskID:      pointer.String("deploy-infrastructure"),
+			Codes:       []gardencorev1beta1.ErrorCode{gardencorev1beta1.ErrorInfraQuotaExceeded},
+		}}
+
+		cmd := cmdstatus.NewCmdStatus(factory, options)
+		Expect(cmd.RunE(cmd, nil)).To(Succeed())
+		Expect(out.String()).To(Equal(`Shoot:      garden-prod1/myshoot
+Operation:  Reconcile Succeeded (100%)
+Updated:    2022-03-01T11:00:00Z
+Message:    Shoot cluster has been successfully reconciled.
+Errors:
+  [ERR_INFRA_QUOTA_EXCEEDED] task deploy-infrastructure: quota exceeded
+`))
+	})
+
+	It("should fail if no shoot is targeted", func() {
+		targetProvider.Target = target.NewTarget(gardenName, projectName, "", "")
+
+		cmd := cmdstatus.NewCmdStatus(factory, options)
+		Expect(cmd.RunE(cmd, nil)).To(MatchError("no Shoot cluster targeted"))
+	})
+
+	It("should reject a timeout without watch", func() {
+		options.Timeout = time.Minute
+		Expect(options.Validate()).To(MatchError("--timeout can only be used together with --watch"))
+	})
+
+	Context("when watching", func() {
+		BeforeEach(func() {
+			options.Watch = true
+		})
+
+		It("should return when the operation has succeeded", func() {
+			cmd := cmdstatus.NewCmdStatus(factory, options)
+			Expect(cmd.RunE(cmd, nil)).To(Succeed())
+			Expect(out.String()).To(Equal("12:00:00 Reconcile Succeeded (100%): Shoot cluster has been successfully reconciled.\n"))
+		})
+
+		It("should print the progress until the operation has succeeded", func() {
+			shoot.Status.LastOperation.State = gardencorev1beta1.LastOperationStateProcessing
+			shoot.Status.LastOperation.Progress = 42
+			shoot.Status.LastOperation.Description = "Waiting for worker nodes"
+
+			go func() {
+				defer GinkgoRecover()
+
+				Eventually(out.String).Should(ContainSubstring("(42%)"))
+
+				current := &gardencorev1beta1.Shoot{}
+				Expect(gardenClient.Get(context.Background(), client.ObjectKeyFromObject(shoot), current)).To(Succeed())
+
+				current.Status.LastOperation.State = gardencorev1beta1.LastOperationStateSucceeded
+				current.Status.LastOperation.Progress = 100
+				current.Status.LastOperation.Description = "Shoot cluster has been successfully reconciled."
+				Expect(gardenClient.Status().Update(context.Background(), current)).To(Succeed())
+			}()
+
+			options.Timeout = 10 * time.Second
+			cmd := cmdstatus.NewCmdStatus(factory, options)
+			Expect(cmd.RunE(cmd, nil)).To(Succeed())
+			Expect(out.String()).To(Equal(`12:00:00 Reconcile Processing (42%): Waiting for worker nodes
+12:00:00 Reconcile Succeeded (100%): Shoot cluster has been successfully reconciled.
+`))
+		})
+
+		It("should fail with the error codes when the operation has failed", func() {
+			shoot.Status.LastOperation.State = gardencorev1beta1.LastOperationStateFailed
+			shoot.Status.LastOperation.Description = "Flow failed"
+			shoot.Status.LastErrors = []gardencorev1beta1.LastError{{
+				Description: "invalid credentials",
+				Codes:       []gardencorev1beta1.ErrorCode{gardencorev1beta1.ErrorInfraUnauthorized},
+			}}
+
+			cmd := cmdstatus.NewCmdStatus(factory, options)
+			Expect(cmd.RunE(cmd, nil)).To(MatchError("Reconcile of shoot garden-prod1/myshoot failed with error codes ERR_INFRA_UNAUTHORIZED"))
+			Expect(out.String()).To(ContainSubstring("12:00:00   [ERR_INFRA_UNAUTHORIZED] invalid credentials\n"))
+		})
+
+		It("should wait until a requested operation has been picked up", func() {
+			shoot.Annotations = map[string]string{"gardener.cloud/operation": "reconcile"}
+			options.Timeout = 50 * time.Millisecond
+
+			cmd := cmdstatus.NewCmdStatus(factory, options)
+			err := cmd.RunE(cmd, nil)
+			Expect(err).To(MatchError("timed out waiting for the last operation of shoot garden-prod1/myshoot to finish"))
+			Expect(base.ExitCode(err)).To(Equal(base.ExitCodeTimeout))
+		})
+
+		It("should wait for the new generation to be observed", func() {
+			shoot.Status.ObservedGeneration = 1
+			options.Timeout = 50 * time.Millisecond
+
+			cmd := cmdstatus.NewCmdStatus(factory, options)
+			Expect(cmd.RunE(cmd, nil)).To(MatchError("timed out waiting for the last operation of shoot garden-prod1/myshoot to finish"))
+		})
+	})
+
+	Describe("WatchDeletion", func() {
+		It("should print the progress until the shoot has been deleted", func() {
+			shoot.Status.LastOperation.Type = gardencorev1beta1.LastOperationTypeDelete
+			shoot.Status.LastOperation.State = gardencorev1beta1.LastOperationStateProcessing
+			shoot.Status.LastOperation.Progress = 42
+			shoot.Status.LastOperation.Description = "Deleting worker nodes"
+
+			runtimeClient := internalfake.NewClientWithObjects(shoot)
+
+			go func() {
+				defer GinkgoRecover()
+
+				Eventually(out.String).Should(ContainSubstring("(42%)"))
+				Expect(runtimeClient.Delete(context.Background(), shoot)).To(Succeed())
+			}()
+
+			Expect(cmdstatus.WatchDeletion(context.Background(), out, fixedClock(time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)),
+				gardenclient.NewGardenClient(runtimeClient), client.ObjectKeyFromObject(shoot), 10*time.Second)).To(Succeed())
+			Expect(out.String()).To(Equal(`12:00:00 Delete Processing (42%): Deleting worker nodes
+12:00:00 Shoot garden-prod1/myshoot has been deleted
+`))
+		})
+
+		It("should fail if the deletion has failed", func() {
+			shoot.Status.LastOperation.Type = gardencorev1beta1.LastOperationTypeDelete
+			shoot.Status.LastOperation.State = gardencorev1beta1.LastOperationStateFailed
+
+			Expect(cmdstatus.WatchDeletion(context.Background(), out, fixedClock(time.Now()),
+				gardenclient.NewGardenClient(internalfake.NewClientWithObjects(shoot)), client.ObjectKeyFromObject(shoot), 0)).
+				To(MatchError("Delete of shoot garden-prod1/myshoot failed"))
+		})
+	})
+})
@@ -0,0 +1,220 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package target
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/klog/v2"
+
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/config"
+	"github.com/gardener/gardenctl-v2/pkg/target"
+)
+
+// contextTimeout limits the duration of the cluster identity lookup in the cluster of a kubectl context
+const contextTimeout = 10 * time.Second
+
+var (
+	// loadKubeconfig loads the kubeconfig of kubectl, which is merged from the files in the KUBECONFIG
+	// environment variable or read from ~/.kube/config
+	loadKubeconfig = func() (*clientcmdapi.Config, error) {
+		return clientcmd.NewDefaultClientConfigLoadingRules().Load()
+	}
+)
+
+// targetFromContext returns the target of the garden, seed or shoot of a kubectl context. The cluster of the context is looked up
+// by the server URL and by the cluster identity in the cluster-identity ConfigMap of the cluster:
+// a garden matches if its kubeconfig has the same server or if it has the same cluster identity,
+// a seed or shoot matches if the cluster identity in its status is the same or if one of the advertised
+// addresses of a shoot is the server of the context.
+func (o *TargetOptions) targetFromContext(ctx context.Context, f util.Factory, manager target.Manager) (target.Target, error) {
+	kubeconfig, err := loadKubeconfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+
+	kubeContext, ok := kubeconfig.Contexts[o.FromContext]
+	if !ok {
+		return nil, fmt.Errorf("context %q not found in kubeconfig", o.FromContext)
+	}
+
+	cluster, ok := kubeconfig.Clusters[kubeContext.Cluster]
+	if !ok {
+		return nil, fmt.Errorf("cluster %q of context %q not found in kubeconfig", kubeContext.Cluster, o.FromContext)
+	}
+
+	server := normalizeServer(cluster.Server)
+	cfg := manager.Configuration()
+
+	for _, garden := range cfg.Gardens {
+		if gardenServer(cfg, garden) == server && server != "" {
+			return target.NewTarget(garden.Name, "", "", ""), nil
+		}
+	}
+
+	identity, err := o.contextClusterIdentity(ctx, f, kubeconfig)
+	if err != nil {
+		klog.V(1).Infof("failed to get the cluster identity of context %q: %v", o.FromContext, err)
+	}
+
+	if identity != "" {
+		for _, name := range cfg.GardenNames() {
+			if manager.ClusterIdentity(ctx, name) == identity {
+				return target.NewTarget(name, "", "", ""), nil
+			}
+		}
+	}
+
+	for _, name := range cfg.GardenNames() {
+		// the cluster identity of a shoot ends with the cluster identity of its garden, other gardens are only
+		// searched if the cluster identity is unknown
+		if identity != "" && !strings.HasSuffix(identity, "-"+manager.ClusterIdentity(ctx, name)) {
+			continue
+		}
+
+		t, err := findCluster(ctx, manager, name, server, identity)
+		if err != nil {
+			fmt.Fprintf(o.IOStreams.ErrOut, "Failed to search garden %q: %v\n", name, err)
+			continue
+		}
+
+		if t != nil {
+			return t, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no garden, seed or shoot found for server %s of context %q", cluster.Server, o.FromContext)
+}
+
+// contextClusterIdentity reads the cluster identity from the cluster-identity ConfigMap of the cluster of the kubectl context
+func (o *TargetOptions) contextClusterIdentity(ctx context.Context, f util.Factory, kubeconfig *clientcmdapi.Config) (string, error) {
+	clientset, err := f.Clientset(clientcmd.NewNonInteractiveClientConfig(*kubeconfig, o.FromContext, &clientcmd.ConfigOverrides{}, nil))
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	cm, err := clientset.CoreV1().ConfigMaps("kube-system").Get(ctx, "cluster-identity", metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	return cm.Data["cluster-identity"], nil
+}
+
+// findCluster returns the target of the seed or shoot of the garden with the given server or cluster identity.
+// It returns nil if no cluster has been found.
+func findCluster(ctx context.Context, manager target.Manager, gardenName, server, identity string) (target.Target, error) {
+	gardenClient, err := manager.GardenClient(gardenName)
+	if err != nil {
+		return nil, err
+	}
+
+	if identity != "" {
+		seedList, err := gardenClient.ListSeeds(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, seed := range seedList.Items {
+			if seed.Status.ClusterIdentity != nil && *seed.Status.ClusterIdentity == identity {
+				return target.NewTarget(gardenName, "", seed.Name, ""), nil
+			}
+		}
+	}
+
+	shootList, err := gardenClient.ListShoots(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, shoot := range shootList.Items {
+		if !shootMatches(shoot, server, identity) {
+			continue
+		}
+
+		project, err := gardenClient.GetProjectByNamespace(ctx, shoot.Namespace)
+		if err != nil {
+			return nil, err
+		}
+
+		return target.NewTarget(gardenName, project.Name, "", shoot.Name), nil
+	}
+
+	return nil, nil
+}
+
+// shootMatches returns true if the shoot has the given cluster identity or advertises the given server
+func shootMatches(shoot gardencorev1beta1.Shoot, server, identity string) bool {
+	if identity != "" && shoot.Status.ClusterIdentity != nil && *shoot.Status.ClusterIdentity == identity {
+		return true
+	}
+
+	for _, address := range shoot.Status.AdvertisedAddresses {
+		if normalizeServer(address.URL) == server {
+			return true
+		}
+	}
+
+	return false
+}
+
+// gardenServer returns the normalized server of the kubeconfig of the garden or an empty string if it cannot be read.
+// The raw kubeconfig is used, so that neither credential plugins nor OIDC logins are triggered.
+func gardenServer(cfg *config.Config, garden config.Garden) string {
+	clientConfig, err := cfg.ClientConfig(garden.Name)
+	if err != nil {
+		return ""
+	}
+
+	rawConfig, err := clientConfig.RawConfig()
+	if err != nil {
+		return ""
+	}
+
+	contextName := rawConfig.CurrentContext
+	if garden.Context != "" {
+		contextName = garden.Context
+	}
+
+	kubeContext, ok := rawConfig.Contexts[contextName]
+	if !ok {
+		return ""
+	}
+
+	cluster, ok := rawConfig.Clusters[kubeContext.Cluster]
+	if !ok {
+		return ""
+	}
+
+	return normalizeServer(cluster.Server)
+}
+
+// normalizeServer returns the server URL without trailing slash, in lower case and without the default https port
+func normalizeServer(server string) string {
+	u, err := url.Parse(strings.ToLower(strings.TrimSuffix(server, "/")))
+	if err != nil || u.Host == "" {
+		return strings.ToLower(strings.TrimSuffix(server, "/"))
+	}
+
+	if u.Scheme == "https" && u.Port() == "443" {
+		u.Host = u.Hostname()
+	}
+
+	return u.String()
+}
@@ -6,4 +6,12 @@ SPDX-License-Identifier: Apache-2.0
 
 package target
 
+import (
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
 var ValidTargetArgsFunction = validTargetArgsFunction
+
+func SetLoadKubeconfig(f func() (*clientcmdapi.Config, error)) {
+	loadKubeconfig = f
+}
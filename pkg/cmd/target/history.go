@@ -0,0 +1,78 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package target
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
+)
+
+// NewCmdHistory returns a new target history command.
+func NewCmdHistory(f util.Factory, o *HistoryOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Print the previous targets of the current session",
+		Long: `Print the previous targets of the current session, the most recent one first.
+Use "gardenctl target -" to go back to the most recent one.`,
+		Example: `# print the previous targets
+gardenctl target history
+
+# go back to the previous target
+gardenctl target -`,
+		RunE: base.WrapRunE(o, f),
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// HistoryOptions is a struct to support history command
+type HistoryOptions struct {
+	base.Options
+}
+
+// NewHistoryOptions returns initialized HistoryOptions
+func NewHistoryOptions(ioStreams util.IOStreams) *HistoryOptions {
+	return &HistoryOptions{
+		Options: base.Options{
+			IOStreams: ioStreams,
+		},
+	}
+}
+
+// Run executes the command
+func (o *HistoryOptions) Run(f util.Factory) error {
+	manager, err := f.Manager()
+	if err != nil {
+		return err
+	}
+
+	history, err := manager.TargetHistory()
+	if err != nil {
+		return fmt.Errorf("failed to read target history: %w", err)
+	}
+
+	if o.Output != "" {
+		return o.PrintObject(history)
+	}
+
+	if len(history) == 0 {
+		fmt.Fprintln(o.IOStreams.Out, "target history is empty")
+		return nil
+	}
+
+	for i, t := range history {
+		fmt.Fprintf(o.IOStreams.Out, "%d  %s\n", i+1, t)
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package target_test
+
+import (
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	internalfake "github.com/gardener/gardenctl-v2/internal/fake"
+	"github.com/gardener/gardenctl-v2/internal/util"
+	cmdtarget "github.com/gardener/gardenctl-v2/pkg/cmd/target"
+	"github.com/gardener/gardenctl-v2/pkg/target"
+	targetmocks "github.com/gardener/gardenctl-v2/pkg/target/mocks"
+)
+
+var _ = Describe("Target History Command", func() {
+	var (
+		ctrl    *gomock.Controller
+		manager *targetmocks.MockManager
+		factory *internalfake.Factory
+		streams util.IOStreams
+		out     *util.SafeBytesBuffer
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		manager = targetmocks.NewMockManager(ctrl)
+		factory = internalfake.NewFakeFactory(nil, nil, nil, nil)
+		factory.ManagerImpl = manager
+		streams, _, out, _ = util.NewTestIOStreams()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("should print the previous targets", func() {
+		manager.EXPECT().TargetHistory().Return([]target.Target{
+			target.NewTarget("mygarden", "myproject", "", "myshoot"),
+			target.NewTarget("mygarden", "", "", ""),
+		}, nil)
+
+		cmd := cmdtarget.NewCmdHistory(factory, cmdtarget.NewHistoryOptions(streams))
+		Expect(cmd.RunE(cmd, nil)).To(Succeed())
+		Expect(out.String()).To(Equal("1  garden:\"mygarden\", project:\"myproject\", shoot:\"myshoot\"\n2  garden:\"mygarden\"\n"))
+	})
+
+	It("should print the previous targets as yaml", func() {
+		manager.EXPECT().TargetHistory().Return([]target.Target{target.NewTarget("mygarden", "", "", "")}, nil)
+
+		o := cmdtarget.NewHistoryOptions(streams)
+		o.Output = "yaml"
+		cmd := cmdtarget.NewCmdHistory(factory, o)
+		Expect(cmd.RunE(cmd, nil)).To(Succeed())
+		Expect(out.String()).To(Equal("- garden: mygarden\n"))
+	})
+
+	It("should report an empty history", func() {
+		manager.EXPECT().TargetHistory().Return([]target.Target{}, nil)
+
+		cmd := cmdtarget.NewCmdHistory(factory, cmdtarget.NewHistoryOptions(streams))
+		Expect(cmd.RunE(cmd, nil)).To(Succeed())
+		Expect(out.String()).To(Equal("target history is empty\n"))
+	})
+})
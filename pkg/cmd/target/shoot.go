@@ -7,10 +7,19 @@ SPDX-License-Identifier: Apache-2.0
 package target
 
 import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
 	"github.com/spf13/cobra"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/gardener/gardenctl-v2/internal/gardenclient"
 	"github.com/gardener/gardenctl-v2/internal/util"
 	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
+	"github.com/gardener/gardenctl-v2/pkg/target"
 )
 
 // NewCmdTargetShoot returns a new target shoot command.
@@ -24,10 +33,23 @@ func NewCmdTargetShoot(f util.Factory, ioStreams util.IOStreams) *cobra.Command
 	cmd := &cobra.Command{
 		Use:   "shoot",
 		Short: "Target a shoot",
-		Long:  "Target a shoot to set the scope for the next operations",
+		Long: `Target a shoot to set the scope for the next operations.
+If no shoot name is given, the shoots of the targeted project or seed are listed and the shoot can be selected interactively.
+The list can be narrowed down by entering a filter, e.g. "prd" matches "my-prod-cluster".
+With --search, the shoot is looked up in all projects of the targeted garden, or of all configured gardens with --all-gardens.
+If shoots with this name exist in several projects, the project can be given with --project or the shoot is selected interactively.`,
 		Example: `# target shoot with name my-shoot of currently selected project
 gardenctl target shoot my-shoot
 
+# target the control plane of shoot my-shoot on its seed
+gardenctl target shoot my-shoot --control-plane
+
+# select the shoot interactively from all shoots of the targeted garden
+gardenctl target shoot --all-namespaces
+
+# search shoot my-shoot in all projects of all configured gardens
+gardenctl target shoot my-shoot --search --all-gardens
+
 # target shoot with name my-shoot of project my-project
 gardenctl target shoot my-shoot --garden my-garden --project my-project`,
 		ValidArgsFunction: validTargetFunctionWrapper(f, ioStreams, TargetKindShoot),
@@ -35,6 +57,191 @@ gardenctl target shoot my-shoot --garden my-garden --project my-project`,
 	}
 
 	o.AddFlags(cmd.Flags())
+	cmd.Flags().BoolVarP(&o.AllNamespaces, "all-namespaces", "A", false, "list the shoots of all projects of the targeted garden when selecting the shoot interactively")
+	cmd.Flags().BoolVar(&o.Search, "search", false, "look up the shoot in all projects of the targeted garden")
+	cmd.Flags().BoolVar(&o.AllGardens, "all-gardens", false, "look up the shoot in all configured gardens, use together with --search")
 
 	return cmd
 }
+
+// selectShoot lists the shoots of the current target and lets the user select one of them.
+// If the selected shoot belongs to another project, the project is targeted first.
+func (o *TargetOptions) selectShoot(ctx context.Context, manager target.Manager) (string, error) {
+	currentTarget, err := manager.CurrentTarget()
+	if err != nil {
+		return "", fmt.Errorf("failed to get current target: %w", err)
+	}
+
+	if currentTarget.GardenName() == "" {
+		return "", target.ErrNoGardenTargeted
+	}
+
+	gardenClient, err := manager.GardenClient(currentTarget.GardenName())
+	if err != nil {
+		return "", fmt.Errorf("failed to create garden cluster client: %w", err)
+	}
+
+	opts := []client.ListOption{}
+	if !o.AllNamespaces {
+		opts = append(opts, currentTarget.WithShootName("").AsListOption())
+	}
+
+	shootList, err := gardenClient.ListShoots(ctx, opts...)
+	if err != nil {
+		return "", err
+	}
+
+	shoots := shootList.Items
+	if len(shoots) == 0 {
+		return "", fmt.Errorf("no shoots found for target %s", currentTarget)
+	}
+
+	sort.Slice(shoots, func(i, j int) bool {
+		if shoots[i].Namespace != shoots[j].Namespace {
+			return shoots[i].Namespace < shoots[j].Namespace
+		}
+
+		return shoots[i].Name < shoots[j].Name
+	})
+
+	options := make([]string, len(shoots))
+	for i, shoot := range shoots {
+		options[i] = fmt.Sprintf("%s (namespace: %s, status: %s)", shoot.Name, shoot.Namespace, util.ShootStatus(shoot))
+	}
+
+	i, err := util.NewPrompter(o.IOStreams).Filter("Select a shoot:", options)
+	if err != nil {
+		return "", err
+	}
+
+	shoot := shoots[i]
+
+	if currentTarget.ProjectName() == "" || o.AllNamespaces {
+		project, err := gardenClient.GetProjectByNamespace(ctx, shoot.Namespace)
+		if err != nil {
+			return "", err
+		}
+
+		if project.Name != currentTarget.ProjectName() {
+			if err := manager.TargetProject(ctx, project.Name); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	return shoot.Name, nil
+}
+
+// searchShoot looks up the shoots with the given name in all projects of the targeted garden or of all configured gardens.
+// If more than one shoot is found, the shoot is selected interactively. The whole target is replaced by the found shoot.
+// With --all-gardens, the gardens are searched concurrently and gardens with the same cluster identity are only searched once.
+func (o *TargetOptions) searchShoot(ctx context.Context, manager target.Manager) error {
+	currentTarget, err := manager.CurrentTarget()
+	if err != nil {
+		return fmt.Errorf("failed to get current target: %w", err)
+	}
+
+	gardenNames := []string{currentTarget.GardenName()}
+
+	if o.AllGardens {
+		gardenNames = manager.Configuration().GardenNames()
+	} else if currentTarget.GardenName() == "" {
+		garden, err := manager.Configuration().Garden(manager.Configuration().DefaultGarden)
+		if err != nil {
+			return target.ErrNoGardenTargeted
+		}
+
+		gardenNames = []string{garden.Name}
+	}
+
+	projectName := manager.TargetFlags().ProjectName()
+
+	var (
+		resultsLock sync.Mutex
+		results     = map[string][]searchResult{}
+	)
+
+	search := func(ctx context.Context, gardenName string, gardenClient gardenclient.Client) error {
+		shootList, err := gardenClient.ListShoots(ctx, gardenclient.ShootFilter{"metadata.name": o.TargetName})
+		if err != nil {
+			return err
+		}
+
+		found := []searchResult{}
+
+		for _, shoot := range shootList.Items {
+			project, err := gardenClient.GetProjectByNamespace(ctx, shoot.Namespace)
+			if err != nil {
+				return err
+			}
+
+			if projectName != "" && project.Name != projectName {
+				continue
+			}
+
+			found = append(found, searchResult{
+				target: target.NewTarget(gardenName, project.Name, "", shoot.Name),
+				option: fmt.Sprintf("%s/%s/%s (status: %s)", gardenName, project.Name, shoot.Name, util.ShootStatus(shoot)),
+			})
+		}
+
+		resultsLock.Lock()
+		defer resultsLock.Unlock()
+
+		results[gardenName] = found
+
+		return nil
+	}
+
+	if o.AllGardens {
+		for _, gardenErr := range manager.ForEachGarden(ctx, search) {
+			if target.IsDuplicateGarden(gardenErr) {
+				fmt.Fprintf(o.IOStreams.ErrOut, "Garden %q is %v\n", gardenErr.Garden, gardenErr.Err)
+				continue
+			}
+
+			fmt.Fprintf(o.IOStreams.ErrOut, "Failed to search garden %q: %v\n", gardenErr.Garden, gardenErr.Err)
+		}
+	} else {
+		gardenClient, err := manager.GardenClient(gardenNames[0])
+		if err != nil {
+			return err
+		}
+
+		if err := search(ctx, gardenNames[0], gardenClient); err != nil {
+			return err
+		}
+	}
+
+	// the results are sorted in the order of the gardens in the gardenctl configuration
+	found := []target.Target{}
+	options := []string{}
+
+	for _, gardenName := range gardenNames {
+		for _, result := range results[gardenName] {
+			found = append(found, result.target)
+			options = append(options, result.option)
+		}
+	}
+
+	i := 0
+
+	switch len(found) {
+	case 0:
+		return fmt.Errorf("shoot %q not found in gardens %s", o.TargetName, strings.Join(gardenNames, ", "))
+	case 1:
+		// the only shoot found is targeted
+	default:
+		if i, err = util.NewPrompter(o.IOStreams).Filter(fmt.Sprintf("Found %d shoots named %q, select one:", len(found), o.TargetName), options); err != nil {
+			return err
+		}
+	}
+
+	return manager.ReplaceTarget(ctx, found[i])
+}
+
+// searchResult is a shoot found by searchShoot and its description in the interactive selection
+type searchResult struct {
+	target target.Target
+	option string
+}
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package target
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -17,6 +18,8 @@ import (
 
 	"github.com/gardener/gardenctl-v2/internal/util"
 	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
+	"github.com/gardener/gardenctl-v2/pkg/config"
+	"github.com/gardener/gardenctl-v2/pkg/target"
 )
 
 // NewCmdTarget returns a new target command.
@@ -36,7 +39,19 @@ gardenctl target --garden my-garden --project my-project
 gardenctl target shoot my-shoot
 
 # Target shoot control-plane using values that match a pattern defined for a specific garden
-gardenctl target value/that/matches/pattern --control-plane`,
+gardenctl target value/that/matches/pattern --control-plane
+
+# target the shoot of a Gardener dashboard URL, requires the dashboard pattern preset
+gardenctl target https://dashboard.garden.example.com/namespace/garden-my-project/shoots/my-shoot
+
+# target the garden with the label env=canary
+gardenctl target --garden-selector env=canary
+
+# target the garden, seed or shoot of the kubectl context "my-context"
+gardenctl target --from-context my-context
+
+# go back to the previous target, like "cd -"
+gardenctl target -`,
 		RunE: base.WrapRunE(o, f),
 	}
 
@@ -48,8 +63,11 @@ gardenctl target value/that/matches/pattern --control-plane`,
 
 	cmd.AddCommand(NewCmdUnset(f, NewUnsetOptions(ioStreams)))
 	cmd.AddCommand(NewCmdView(f, NewViewOptions(ioStreams)))
+	cmd.AddCommand(NewCmdHistory(f, NewHistoryOptions(ioStreams)))
 
 	o.AddFlags(cmd.Flags())
+	cmd.Flags().StringVar(&o.GardenSelector, "garden-selector", "", "target the garden matching the label selector, e.g. env=canary")
+	cmd.Flags().StringVar(&o.FromContext, "from-context", "", "target the garden, seed or shoot of the given kubectl context, which is looked up by its server URL and cluster identity")
 
 	return cmd
 }
@@ -116,6 +134,18 @@ type TargetOptions struct {
 	Kind TargetKind
 	// TargetName is the object name of the targeted kind
 	TargetName string
+	// GardenSelector is a label selector that selects the garden to target
+	GardenSelector string
+	// FromContext is the name of a kubectl context whose cluster is targeted
+	FromContext string
+	// Previous targets the most recent target of the target history
+	Previous bool
+	// AllNamespaces lists the shoots of all projects if a shoot is selected interactively
+	AllNamespaces bool
+	// Search looks up the shoot in all projects of the targeted garden
+	Search bool
+	// AllGardens looks up the shoot in all configured gardens
+	AllGardens bool
 }
 
 // NewTargetOptions returns initialized TargetOptions
@@ -129,7 +159,9 @@ func NewTargetOptions(ioStreams util.IOStreams) *TargetOptions {
 
 // Complete adapts from the command line args to the data required.
 func (o *TargetOptions) Complete(f util.Factory, cmd *cobra.Command, args []string) error {
-	if len(args) > 0 {
+	if len(args) > 0 && strings.TrimSpace(args[0]) == "-" {
+		o.Previous = true
+	} else if len(args) > 0 {
 		if o.Kind == "" {
 			o.Kind = TargetKindPattern
 		}
@@ -157,6 +189,28 @@ func (o *TargetOptions) Complete(f util.Factory, cmd *cobra.Command, args []stri
 		}
 	}
 
+	if o.FromContext != "" && (o.Previous || o.Kind != "" || o.GardenSelector != "") {
+		return errors.New("the kubectl context cannot be combined with other targets")
+	}
+
+	if o.Previous && (o.Kind != "" || o.GardenSelector != "") {
+		return errors.New("the previous target cannot be combined with other targets")
+	}
+
+	if o.GardenSelector != "" {
+		if o.Kind != "" {
+			return errors.New("the garden selector cannot be combined with other targets")
+		}
+
+		name, err := selectGarden(manager.Configuration(), o.GardenSelector)
+		if err != nil {
+			return err
+		}
+
+		o.Kind = TargetKindGarden
+		o.TargetName = name
+	}
+
 	if o.TargetName == "" {
 		switch o.Kind {
 		case TargetKindGarden:
@@ -173,11 +227,51 @@ func (o *TargetOptions) Complete(f util.Factory, cmd *cobra.Command, args []stri
 	return nil
 }
 
+// selectGarden returns the name of the only garden matching the given label selector
+func selectGarden(cfg *config.Config, selector string) (string, error) {
+	if cfg == nil {
+		return "", errors.New("failed to get configuration")
+	}
+
+	gardens, err := cfg.SelectGardens(selector)
+	if err != nil {
+		return "", err
+	}
+
+	switch len(gardens) {
+	case 0:
+		return "", fmt.Errorf("no garden matches the label selector %q", selector)
+	case 1:
+		return gardens[0].Name, nil
+	}
+
+	names := make([]string, len(gardens))
+	for i, g := range gardens {
+		names[i] = g.Name
+	}
+
+	return "", fmt.Errorf("the label selector %q matches multiple gardens: %s", selector, strings.Join(names, ", "))
+}
+
 // Validate validates the provided options
 func (o *TargetOptions) Validate() error {
+	if o.Previous || o.FromContext != "" {
+		return nil
+	}
+
+	if o.AllGardens && !o.Search {
+		return errors.New("--all-gardens must be used together with --search")
+	}
+
+	if o.Search && o.TargetName == "" {
+		return errors.New("--search requires a shoot name argument")
+	}
+
 	switch o.Kind {
 	case TargetKindControlPlane:
 		// valid
+	case TargetKindShoot:
+		// the shoot is selected interactively if the name is empty
 	default:
 		if o.TargetName == "" {
 			return fmt.Errorf("target kind %q requires a name argument", o.Kind)
@@ -196,18 +290,33 @@ func (o *TargetOptions) Run(f util.Factory) error {
 
 	ctx := f.Context()
 
-	switch o.Kind {
-	case TargetKindGarden:
+	if o.Kind == TargetKindShoot && o.TargetName == "" && !o.Search {
+		if o.TargetName, err = o.selectShoot(ctx, manager); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case o.Previous:
+		err = manager.TargetPrevious(ctx)
+	case o.FromContext != "":
+		var t target.Target
+		if t, err = o.targetFromContext(ctx, f, manager); err == nil {
+			err = manager.ReplaceTarget(ctx, t)
+		}
+	case o.Search:
+		err = o.searchShoot(ctx, manager)
+	case o.Kind == TargetKindGarden:
 		err = manager.TargetGarden(ctx, o.TargetName)
-	case TargetKindProject:
+	case o.Kind == TargetKindProject:
 		err = manager.TargetProject(ctx, o.TargetName)
-	case TargetKindSeed:
+	case o.Kind == TargetKindSeed:
 		err = manager.TargetSeed(ctx, o.TargetName)
-	case TargetKindShoot:
+	case o.Kind == TargetKindShoot:
 		err = manager.TargetShoot(ctx, o.TargetName)
-	case TargetKindPattern:
+	case o.Kind == TargetKindPattern:
 		err = manager.TargetMatchPattern(ctx, o.TargetName)
-	case TargetKindControlPlane:
+	case o.Kind == TargetKindControlPlane:
 		err = manager.TargetControlPlane(ctx)
 	}
 
@@ -215,13 +324,22 @@ func (o *TargetOptions) Run(f util.Factory) error {
 		return err
 	}
 
+	// in dry-run mode the changes have only been printed
+	if manager.Configuration().IsDryRun() {
+		return nil
+	}
+
 	currentTarget, err := manager.CurrentTarget()
 	if err != nil {
 		return fmt.Errorf("failed to get current target: %w", err)
 	}
 
 	if o.Output == "" {
-		if o.Kind == TargetKindControlPlane {
+		if o.Previous {
+			fmt.Fprintf(o.IOStreams.Out, "Successfully targeted previous target %s\n", currentTarget)
+		} else if o.FromContext != "" {
+			fmt.Fprintf(o.IOStreams.Out, "Successfully targeted %s of context %q\n", currentTarget, o.FromContext)
+		} else if o.Kind == TargetKindControlPlane || (o.Kind == TargetKindShoot && currentTarget.ControlPlane()) {
 			fmt.Fprintf(o.IOStreams.Out, "Successfully targeted control plane of shoot %q\n", currentTarget.ShootName())
 		} else if o.Kind != "" {
 			fmt.Fprintf(o.IOStreams.Out, "Successfully targeted %s %q\n", o.Kind, o.TargetName)
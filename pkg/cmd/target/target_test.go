@@ -7,13 +7,21 @@ SPDX-License-Identifier: Apache-2.0
 package target_test
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"os"
 
 	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -103,7 +111,10 @@ var _ = Describe("Target Command", func() {
 	JustBeforeEach(func() {
 		clientConfig, err := cfg.ClientConfig(gardenName)
 		Expect(err).ToNot(HaveOccurred())
-		clientProvider.EXPECT().FromClientConfig(gomock.Eq(clientConfig)).Return(gardenClient, nil).AnyTimes()
+		// the garden client is returned lazily, so that tests can replace it
+		clientProvider.EXPECT().FromClientConfig(gomock.Eq(clientConfig)).DoAndReturn(func(clientcmd.ClientConfig) (client.Client, error) {
+			return gardenClient, nil
+		}).AnyTimes()
 	})
 
 	AfterEach(func() {
@@ -132,6 +143,155 @@ var _ = Describe("Target Command", func() {
 			Expect(currentTarget.GardenName()).To(Equal(gardenName))
 		})
 
+		It("should only print the changes in dry-run mode", func() {
+			dryRunOut := &bytes.Buffer{}
+			cfg.DryRun = &config.DryRun{Strategy: config.DryRunClient, Out: dryRunOut}
+			cmd := cmdtarget.NewCmdTargetGarden(factory, streams)
+
+			Expect(cmd.RunE(cmd, []string{gardenName})).To(Succeed())
+			Expect(dryRunOut.String()).To(ContainSubstring("+garden: %s", gardenName))
+			Expect(out.String()).NotTo(ContainSubstring("Successfully targeted"))
+			Expect(out.String()).NotTo(ContainSubstring("KUBECONFIG"))
+
+			currentTarget, err := targetProvider.Read()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(currentTarget.GardenName()).To(BeEmpty())
+		})
+
+		It("should be able to target a garden by label selector", func() {
+			cfg.Gardens[0].Labels = map[string]string{"env": "canary"}
+			cfg.Gardens[1].Labels = map[string]string{"env": "prod"}
+			cmd := cmdtarget.NewCmdTarget(factory, streams)
+			Expect(cmd.Flags().Set("garden-selector", "env=canary")).To(Succeed())
+
+			Expect(cmd.RunE(cmd, nil)).To(Succeed())
+			Expect(out.String()).To(ContainSubstring("Successfully targeted garden %q\n", gardenName))
+
+			currentTarget, err := targetProvider.Read()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(currentTarget.GardenName()).To(Equal(gardenName))
+		})
+
+		It("should fail if the label selector matches multiple gardens", func() {
+			cmd := cmdtarget.NewCmdTarget(factory, streams)
+			Expect(cmd.Flags().Set("garden-selector", "!env")).To(Succeed())
+
+			Expect(cmd.RunE(cmd, nil)).To(MatchError(ContainSubstring(`the label selector "!env" matches multiple gardens: mygarden, another-garden`)))
+		})
+
+		It("should fail if no garden matches the label selector", func() {
+			cmd := cmdtarget.NewCmdTarget(factory, streams)
+			Expect(cmd.Flags().Set("garden-selector", "env=dev")).To(Succeed())
+
+			Expect(cmd.RunE(cmd, nil)).To(MatchError(ContainSubstring(`no garden matches the label selector "env=dev"`)))
+		})
+
+		Context("when targeting a kubectl context", func() {
+			const (
+				contextName     = "my-context"
+				shootServer     = "https://api.myshoot.myproject.example.com"
+				shootIdentity   = "shoot--myproject--myshoot-0b6ffa1b-4d5f-4b8e-9d5c-1b0a0e8b1b6a-garden-identity"
+				gardenIdentity  = "garden-identity"
+				contextIdentity = "cluster-identity"
+			)
+
+			var contextClientset *k8sfake.Clientset
+
+			BeforeEach(func() {
+				gardenClient = internalfake.NewClientWithObjects(project, seed, shoot, &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: contextIdentity, Namespace: "kube-system"},
+					Data:       map[string]string{contextIdentity: gardenIdentity},
+				})
+				contextClientset = k8sfake.NewSimpleClientset()
+
+				cmdtarget.SetLoadKubeconfig(func() (*clientcmdapi.Config, error) {
+					return &clientcmdapi.Config{
+						Clusters: map[string]*clientcmdapi.Cluster{"my-cluster": {Server: shootServer + ":443/"}},
+						Contexts: map[string]*clientcmdapi.Context{contextName: {Cluster: "my-cluster"}},
+					}, nil
+				})
+				factory.ClientsetImpl = func(clientConfig clientcmd.ClientConfig) (kubernetes.Interface, error) {
+					return contextClientset, nil
+				}
+			})
+
+			It("should target the shoot with the cluster identity of the context", func() {
+				_, err := contextClientset.CoreV1().ConfigMaps("kube-system").Create(context.Background(), &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: contextIdentity, Namespace: "kube-system"},
+					Data:       map[string]string{contextIdentity: shootIdentity},
+				}, metav1.CreateOptions{})
+				Expect(err).NotTo(HaveOccurred())
+
+				shoot.Status.ClusterIdentity = pointer.String(shootIdentity)
+				gardenClient = internalfake.NewClientWithObjects(project, seed, shoot, &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: contextIdentity, Namespace: "kube-system"},
+					Data:       map[string]string{contextIdentity: gardenIdentity},
+				})
+
+				cmd := cmdtarget.NewCmdTarget(factory, streams)
+				Expect(cmd.Flags().Set("from-context", contextName)).To(Succeed())
+
+				Expect(cmd.RunE(cmd, nil)).To(Succeed())
+				Expect(out.String()).To(ContainSubstring("Successfully targeted garden:%q, project:%q, shoot:%q of context %q\n", gardenName, projectName, shootName, contextName))
+
+				currentTarget, err := targetProvider.Read()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(currentTarget).To(Equal(target.NewTarget(gardenName, projectName, "", shootName)))
+			})
+
+			It("should target the shoot that advertises the server of the context", func() {
+				shoot.Status.AdvertisedAddresses = []gardencorev1beta1.ShootAdvertisedAddress{{Name: "external", URL: shootServer}}
+				gardenClient = internalfake.NewClientWithObjects(project, seed, shoot)
+
+				cmd := cmdtarget.NewCmdTarget(factory, streams)
+				Expect(cmd.Flags().Set("from-context", contextName)).To(Succeed())
+
+				Expect(cmd.RunE(cmd, nil)).To(Succeed())
+
+				currentTarget, err := targetProvider.Read()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(currentTarget).To(Equal(target.NewTarget(gardenName, projectName, "", shootName)))
+			})
+
+			It("should target the garden with the cluster identity of the context", func() {
+				_, err := contextClientset.CoreV1().ConfigMaps("kube-system").Create(context.Background(), &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: contextIdentity, Namespace: "kube-system"},
+					Data:       map[string]string{contextIdentity: gardenIdentity},
+				}, metav1.CreateOptions{})
+				Expect(err).NotTo(HaveOccurred())
+
+				cmd := cmdtarget.NewCmdTarget(factory, streams)
+				Expect(cmd.Flags().Set("from-context", contextName)).To(Succeed())
+
+				Expect(cmd.RunE(cmd, nil)).To(Succeed())
+
+				currentTarget, err := targetProvider.Read()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(currentTarget).To(Equal(target.NewTarget(gardenName, "", "", "")))
+			})
+
+			It("should fail if no cluster matches the context", func() {
+				cmd := cmdtarget.NewCmdTarget(factory, streams)
+				Expect(cmd.Flags().Set("from-context", contextName)).To(Succeed())
+
+				Expect(cmd.RunE(cmd, nil)).To(MatchError(fmt.Sprintf("no garden, seed or shoot found for server %s:443/ of context %q", shootServer, contextName)))
+			})
+
+			It("should fail for an unknown context", func() {
+				cmd := cmdtarget.NewCmdTarget(factory, streams)
+				Expect(cmd.Flags().Set("from-context", "unknown")).To(Succeed())
+
+				Expect(cmd.RunE(cmd, nil)).To(MatchError(`context "unknown" not found in kubeconfig`))
+			})
+
+			It("should not be combined with other targets", func() {
+				cmd := cmdtarget.NewCmdTarget(factory, streams)
+				Expect(cmd.Flags().Set("from-context", contextName)).To(Succeed())
+
+				Expect(cmd.RunE(cmd, []string{"-"})).To(MatchError(ContainSubstring("the kubectl context cannot be combined with other targets")))
+			})
+		})
+
 		It("should be able to target a project", func() {
 			// user has already targeted a garden
 			targetProvider.Target = target.NewTarget(gardenName, "", "", "")
@@ -147,6 +307,93 @@ var _ = Describe("Target Command", func() {
 			Expect(currentTarget.ProjectName()).To(Equal(projectName))
 		})
 
+		It("should be able to go back to the previous target", func() {
+			dir, err := os.MkdirTemp("", "session-*")
+			Expect(err).NotTo(HaveOccurred())
+			defer os.RemoveAll(dir)
+
+			factory.ManagerImpl, err = target.NewManager(cfg, targetProvider, clientProvider, dir)
+			Expect(err).NotTo(HaveOccurred())
+
+			cmd := cmdtarget.NewCmdTarget(factory, streams)
+			Expect(cmd.RunE(cmd, []string{"-"})).To(MatchError("there is no previous target"))
+
+			targetProvider.Target = target.NewTarget(gardenName, "", "", "")
+			projectCmd := cmdtarget.NewCmdTargetProject(factory, streams)
+			Expect(projectCmd.RunE(projectCmd, []string{projectName})).To(Succeed())
+
+			cmd = cmdtarget.NewCmdTarget(factory, streams)
+			Expect(cmd.RunE(cmd, []string{"-"})).To(Succeed())
+			Expect(out.String()).To(HaveSuffix("Successfully targeted previous target garden:%q\n", gardenName))
+
+			currentTarget, err := targetProvider.Read()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(currentTarget).To(Equal(target.NewTarget(gardenName, "", "", "")))
+		})
+
+		It("should select a shoot interactively", func() {
+			var in *util.SafeBytesBuffer
+			streams, in, out, _ = util.NewTestIOStreams()
+			_, err := in.Write([]byte("my\n1\n"))
+			Expect(err).NotTo(HaveOccurred())
+
+			// user has only targeted a garden
+			targetProvider.Target = target.NewTarget(gardenName, "", "", "")
+			cmd := cmdtarget.NewCmdTargetShoot(factory, streams)
+
+			Expect(cmd.RunE(cmd, nil)).To(Succeed())
+			Expect(out.String()).To(ContainSubstring("  1) myshoot (namespace: garden, status: unknown)\n"))
+			Expect(out.String()).To(HaveSuffix("Successfully targeted shoot %q\n", shootName))
+
+			currentTarget, err := targetProvider.Read()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(currentTarget).To(Equal(target.NewTarget(gardenName, projectName, "", shootName)))
+		})
+
+		It("should search a shoot in all projects", func() {
+			// user has only targeted a garden
+			targetProvider.Target = target.NewTarget(gardenName, "", "", "")
+			cmd := cmdtarget.NewCmdTargetShoot(factory, streams)
+			Expect(cmd.Flags().Set("search", "true")).To(Succeed())
+
+			Expect(cmd.RunE(cmd, []string{shootName})).To(Succeed())
+			Expect(out.String()).To(HaveSuffix("Successfully targeted shoot %q\n", shootName))
+
+			currentTarget, err := targetProvider.Read()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(currentTarget).To(Equal(target.NewTarget(gardenName, projectName, "", shootName)))
+		})
+
+		Context("when shoots with the same name exist in several projects", func() {
+			BeforeEach(func() {
+				otherProject := project.DeepCopy()
+				otherProject.Name = "otherproject"
+				otherProject.Spec.Namespace = pointer.String("garden-other")
+				otherShoot := shoot.DeepCopy()
+				otherShoot.Namespace = "garden-other"
+				gardenClient = internalfake.NewClientWithObjects(project, otherProject, seed, shoot, otherShoot)
+			})
+
+			It("should select the shoot interactively", func() {
+				var in *util.SafeBytesBuffer
+				streams, in, out, _ = util.NewTestIOStreams()
+				_, err := in.Write([]byte("other\n1\n"))
+				Expect(err).NotTo(HaveOccurred())
+
+				// user has targeted a shoot of another project
+				targetProvider.Target = target.NewTarget(gardenName, projectName, "", shootName)
+				cmd := cmdtarget.NewCmdTargetShoot(factory, streams)
+				Expect(cmd.Flags().Set("search", "true")).To(Succeed())
+
+				Expect(cmd.RunE(cmd, []string{shootName})).To(Succeed())
+				Expect(out.String()).To(ContainSubstring("  1) mygarden/otherproject/myshoot (status: unknown)\n"))
+
+				currentTarget, err := targetProvider.Read()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(currentTarget).To(Equal(target.NewTarget(gardenName, "otherproject", "", shootName)))
+			})
+		})
+
 		It("should be able to target a seed", func() {
 			// user has already targeted a garden
 			targetProvider.Target = target.NewTarget(gardenName, "", "", "")
@@ -335,4 +582,20 @@ var _ = Describe("Target Options", func() {
 
 		Expect(o.Validate()).To(Succeed())
 	})
+
+	It("should require --search for --all-gardens", func() {
+		streams, _, _, _ := util.NewTestIOStreams()
+		o := cmdtarget.NewTargetOptions(streams)
+		o.Kind = cmdtarget.TargetKindShoot
+		o.TargetName = "foo"
+		o.AllGardens = true
+
+		Expect(o.Validate()).To(MatchError("--all-gardens must be used together with --search"))
+
+		o.Search = true
+		Expect(o.Validate()).To(Succeed())
+
+		o.TargetName = ""
+		Expect(o.Validate()).To(MatchError("--search requires a shoot name argument"))
+	})
 })
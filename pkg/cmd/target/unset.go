@@ -15,6 +15,7 @@ import (
 
 	"github.com/gardener/gardenctl-v2/internal/util"
 	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
+	"github.com/gardener/gardenctl-v2/pkg/target"
 )
 
 // NewCmdUnset returns a new (target) unset command.
@@ -26,7 +27,10 @@ func NewCmdUnset(f util.Factory, o *UnsetOptions) *cobra.Command {
 gardenctl target unset shoot
 
 # Unset garden. This will also unset a targeted project, shoot, seed and control plane
-gardenctl target unset garden`,
+gardenctl target unset garden
+
+# unset the whole target
+gardenctl target unset --all`,
 		ValidArgs: []string{
 			string(TargetKindGarden),
 			string(TargetKindProject),
@@ -37,6 +41,8 @@ gardenctl target unset garden`,
 		RunE: base.WrapRunE(o, f),
 	}
 
+	cmd.Flags().BoolVar(&o.All, "all", false, "unset the whole target, i.e. the garden, project, seed, shoot and control plane")
+
 	return cmd
 }
 
@@ -46,6 +52,8 @@ type UnsetOptions struct {
 
 	// Kind is the target kind, for example "garden" or "seed"
 	Kind TargetKind
+	// All unsets the whole target instead of a single target kind
+	All bool
 }
 
 // NewUnsetOptions returns initialized UnsetOptions
@@ -82,6 +90,14 @@ func ValidateKind(kind TargetKind) error {
 
 // Validate validates the provided options
 func (o *UnsetOptions) Validate() error {
+	if o.All {
+		if o.Kind != "" {
+			return errors.New("a target kind must not be given together with the --all flag")
+		}
+
+		return nil
+	}
+
 	if err := ValidateKind(o.Kind); err != nil {
 		return err
 	}
@@ -97,6 +113,22 @@ func (o *UnsetOptions) Run(f util.Factory) error {
 	}
 
 	ctx := f.Context()
+	dryRun := manager.Configuration().IsDryRun()
+
+	if o.All {
+		if _, err := manager.UnsetTargetGarden(ctx); err != nil && !errors.Is(err, target.ErrNoGardenTargeted) {
+			return err
+		}
+
+		// in dry-run mode the changes have only been printed
+		if dryRun {
+			return nil
+		}
+
+		fmt.Fprintln(o.IOStreams.Out, "Successfully unset target")
+
+		return nil
+	}
 
 	var targetName string
 
@@ -125,6 +157,10 @@ func (o *UnsetOptions) Run(f util.Factory) error {
 		return err
 	}
 
+	if dryRun {
+		return nil
+	}
+
 	if o.Kind == TargetKindControlPlane {
 		fmt.Fprintf(o.IOStreams.Out, "Successfully unset targeted control plane for %q\n", targetName)
 	} else {
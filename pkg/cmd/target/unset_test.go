@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package target_test
 
 import (
+	"bytes"
 	"context"
 
 	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
@@ -112,6 +113,20 @@ var _ = Describe("Target Unset Command", func() {
 		Expect(cmd.RunE(cmd, nil)).NotTo(Succeed())
 	})
 
+	It("should only print the changes in dry-run mode", func() {
+		dryRunOut := &bytes.Buffer{}
+		cfg.DryRun = &config.DryRun{Strategy: config.DryRunClient, Out: dryRunOut}
+		cmd := cmdtarget.NewCmdUnset(factory, cmdtarget.NewUnsetOptions(streams))
+
+		Expect(cmd.RunE(cmd, []string{"garden"})).To(Succeed())
+		Expect(dryRunOut.String()).To(ContainSubstring("-garden: %s", gardenName))
+		Expect(out.String()).NotTo(ContainSubstring("Successfully unset"))
+
+		currentTarget, err := targetProvider.Read()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(currentTarget.GardenName()).To(Equal(gardenName))
+	})
+
 	It("should be able to unset a targeted garden", func() {
 		// user has already targeted a garden
 		cmd := cmdtarget.NewCmdUnset(factory, cmdtarget.NewUnsetOptions(streams))
@@ -188,6 +203,23 @@ var _ = Describe("Target Unset Command", func() {
 		Expect(currentTarget.ShootName()).To(Equal(shootName))
 		Expect(currentTarget.ControlPlane()).To(BeFalse())
 	})
+
+	It("should be able to unset the whole target", func() {
+		targetProvider.Target = currentTarget.WithProjectName(projectName).WithShootName(shootName).WithControlPlane(true)
+		o := cmdtarget.NewUnsetOptions(streams)
+		cmd := cmdtarget.NewCmdUnset(factory, o)
+		Expect(cmd.Flags().Set("all", "true")).To(Succeed())
+
+		Expect(cmd.RunE(cmd, nil)).To(Succeed())
+		Expect(out.String()).To(Equal("Successfully unset target\n"))
+
+		currentTarget, err := targetProvider.Read()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(currentTarget.IsEmpty()).To(BeTrue())
+
+		// unsetting an empty target succeeds as well
+		Expect(cmd.RunE(cmd, nil)).To(Succeed())
+	})
 })
 
 var _ = Describe("Target Unset Options", func() {
@@ -207,4 +239,15 @@ var _ = Describe("Target Unset Options", func() {
 		err := o.Validate()
 		Expect(err).To(MatchError(ContainSubstring("invalid target kind given, must be one of")))
 	})
+
+	It("should reject a kind together with --all", func() {
+		streams, _, _, _ := util.NewTestIOStreams()
+		o := cmdtarget.NewUnsetOptions(streams)
+		o.All = true
+
+		Expect(o.Validate()).To(Succeed())
+
+		o.Kind = cmdtarget.TargetKindShoot
+		Expect(o.Validate()).To(MatchError("a target kind must not be given together with the --all flag"))
+	})
 })
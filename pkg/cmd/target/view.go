@@ -6,12 +6,16 @@ SPDX-License-Identifier: Apache-2.0
 package target
 
 import (
+	"context"
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
 	"github.com/gardener/gardenctl-v2/internal/util"
 	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
+	"github.com/gardener/gardenctl-v2/pkg/target"
 )
 
 // NewCmdView returns a new target view command.
@@ -19,6 +23,19 @@ func NewCmdView(f util.Factory, o *ViewOptions) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "view",
 		Short: "Print the current target",
+		Long: `Print the current target.
+The namespace of a targeted project and the seed of a targeted shoot are resolved using the garden cluster.
+If they cannot be resolved, a warning is printed and only the persisted target is shown.
+In offline mode, the garden cluster is not contacted and only the persisted target is shown.
+With --output prompt, the target is printed in a short form without contacting the garden cluster, e.g. for shell prompts.`,
+		Example: `# print the current target
+gardenctl target view
+
+# print the current target as json, e.g. to use it in scripts
+gardenctl target view -o json
+
+# print the current target in a short form for shell prompts, e.g. mygarden/myproject/myshoot
+gardenctl target view -o prompt`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if err := o.Validate(); err != nil {
 				return err
@@ -44,19 +61,164 @@ func runViewCommand(f util.Factory, opt *ViewOptions) error {
 		return fmt.Errorf("failed to get current target: %v", err)
 	}
 
+	if opt.Output == outputPrompt {
+		_, err = fmt.Fprint(opt.IOStreams.Out, newTargetView(currentTarget).prompt())
+		return err
+	}
+
 	if opt.Output == "" && currentTarget.IsEmpty() {
 		_, err = fmt.Fprintf(opt.IOStreams.Out, "target is empty")
 		return err
 	}
 
-	return opt.PrintObject(currentTarget)
+	view := newTargetView(currentTarget)
+
+	// in offline mode the persisted target is shown, even the client configuration of the garden is not loaded
+	if cfg := m.Configuration(); cfg != nil && cfg.Offline {
+		return opt.PrintObject(view)
+	}
+
+	if err := view.resolve(f.Context(), m, currentTarget); err != nil {
+		fmt.Fprintf(opt.IOStreams.ErrOut, "Warning: failed to resolve target: %v\n", err)
+	}
+
+	return opt.PrintObject(view)
+}
+
+// targetView is the printed representation of a target, including the values resolved using the garden cluster
+type targetView struct {
+	Garden       string `yaml:"garden,omitempty" json:"garden,omitempty"`
+	Project      string `yaml:"project,omitempty" json:"project,omitempty"`
+	Namespace    string `yaml:"namespace,omitempty" json:"namespace,omitempty"`
+	Seed         string `yaml:"seed,omitempty" json:"seed,omitempty"`
+	Shoot        string `yaml:"shoot,omitempty" json:"shoot,omitempty"`
+	ControlPlane bool   `yaml:"controlPlane,omitempty" json:"controlPlane,omitempty"`
+}
+
+func newTargetView(t target.Target) *targetView {
+	return &targetView{
+		Garden:       t.GardenName(),
+		Project:      t.ProjectName(),
+		Seed:         t.SeedName(),
+		Shoot:        t.ShootName(),
+		ControlPlane: t.ControlPlane(),
+	}
+}
+
+// resolve sets the namespace of the targeted project and the seed of the targeted shoot
+func (v *targetView) resolve(ctx context.Context, manager target.Manager, t target.Target) error {
+	if v.Project == "" && v.Shoot == "" {
+		return nil
+	}
+
+	client, err := manager.GardenClient(v.Garden)
+	if err != nil {
+		return fmt.Errorf("failed to create garden cluster client: %w", err)
+	}
+
+	if v.Project != "" {
+		project, err := client.GetProject(ctx, v.Project)
+		if err != nil {
+			return err
+		}
+
+		if project.Spec.Namespace != nil {
+			v.Namespace = *project.Spec.Namespace
+		}
+	}
+
+	if v.Shoot != "" {
+		shoot, err := client.FindShoot(ctx, t.AsListOption())
+		if err != nil {
+			return err
+		}
+
+		v.Namespace = shoot.Namespace
+
+		if v.Seed == "" && shoot.Spec.SeedName != nil {
+			v.Seed = *shoot.Spec.SeedName
+		}
+	}
+
+	return nil
+}
+
+// String returns a readable representation of the target view.
+func (v *targetView) String() string {
+	steps := []string{}
+
+	if v.Garden != "" {
+		steps = append(steps, fmt.Sprintf("garden:%q", v.Garden))
+	}
+
+	if v.Project != "" {
+		steps = append(steps, fmt.Sprintf("project:%q", v.Project))
+	}
+
+	if v.Namespace != "" {
+		steps = append(steps, fmt.Sprintf("namespace:%q", v.Namespace))
+	}
+
+	if v.Seed != "" {
+		steps = append(steps, fmt.Sprintf("seed:%q", v.Seed))
+	}
+
+	if v.Shoot != "" {
+		steps = append(steps, fmt.Sprintf("shoot:%q", v.Shoot))
+	}
+
+	if v.ControlPlane {
+		steps = append(steps, "control plane targeted")
+	}
+
+	return strings.Join(steps, ", ")
 }
 
+// prompt returns a short representation of the target view for shell prompts, e.g. mygarden/myproject/myshoot
+func (v *targetView) prompt() string {
+	steps := []string{}
+
+	for _, step := range []string{v.Garden, v.Project, v.Seed, v.Shoot} {
+		if step != "" {
+			steps = append(steps, step)
+		}
+	}
+
+	prompt := strings.Join(steps, "/")
+
+	if v.ControlPlane {
+		prompt += " (control plane)"
+	}
+
+	return prompt
+}
+
+// outputPrompt is the output format that prints the target in a short form for shell prompts
+const outputPrompt = "prompt"
+
 // ViewOptions is a struct to support view command
 type ViewOptions struct {
 	base.Options
 }
 
+// AddFlags binds the command options to a given flagset
+func (o *ViewOptions) AddFlags(flags *pflag.FlagSet) {
+	flags.StringVarP(&o.Output, "output", "o", o.Output, base.OutputFormatsUsage(outputPrompt))
+}
+
+// Validate validates the provided ViewOptions
+func (o *ViewOptions) Validate() error {
+	if o.Output == outputPrompt {
+		return nil
+	}
+
+	if err := base.ValidateOutput(o.Output, outputPrompt); err != nil {
+		return err
+	}
+
+	return o.Options.Validate()
+}
+
 // NewViewOptions returns initialized ViewOptions
 func NewViewOptions(ioStreams util.IOStreams) *ViewOptions {
 	return &ViewOptions{
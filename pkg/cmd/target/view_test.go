@@ -8,13 +8,19 @@ package target_test
 import (
 	"fmt"
 
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 
 	internalfake "github.com/gardener/gardenctl-v2/internal/fake"
 	"github.com/gardener/gardenctl-v2/internal/util"
 	cmdtarget "github.com/gardener/gardenctl-v2/pkg/cmd/target"
+	"github.com/gardener/gardenctl-v2/pkg/config"
 	"github.com/gardener/gardenctl-v2/pkg/target"
+	targetmocks "github.com/gardener/gardenctl-v2/pkg/target/mocks"
 )
 
 var _ = Describe("Target View Command", func() {
@@ -27,13 +33,14 @@ var _ = Describe("Target View Command", func() {
 	var (
 		streams        util.IOStreams
 		out            *util.SafeBytesBuffer
+		errOut         *util.SafeBytesBuffer
 		factory        *internalfake.Factory
 		targetProvider *internalfake.TargetProvider
 		currentTarget  target.Target
 	)
 
 	BeforeEach(func() {
-		streams, _, out, _ = util.NewTestIOStreams()
+		streams, _, out, errOut = util.NewTestIOStreams()
 		currentTarget = target.NewTarget(gardenName, projectName, "", shootName)
 	})
 
@@ -49,6 +56,113 @@ var _ = Describe("Target View Command", func() {
 
 		Expect(cmd.RunE(cmd, nil)).To(Succeed())
 		Expect(out.String()).To(Equal(fmt.Sprintf("garden:\"%s\", project:\"%s\", shoot:\"%s\"", gardenName, projectName, shootName)))
+		Expect(errOut.String()).To(HavePrefix("Warning: failed to resolve target"))
+	})
+
+	It("should print the resolved namespace and seed", func() {
+		cfg := &config.Config{
+			LinkKubeconfig: pointer.Bool(false),
+			Gardens:        []config.Garden{{Name: gardenName, Kubeconfig: "/not/a/real/file"}},
+		}
+		project := &gardencorev1beta1.Project{
+			ObjectMeta: metav1.ObjectMeta{Name: projectName},
+			Spec:       gardencorev1beta1.ProjectSpec{Namespace: pointer.String("garden-prod1")},
+		}
+		shoot := &gardencorev1beta1.Shoot{
+			ObjectMeta: metav1.ObjectMeta{Name: shootName, Namespace: "garden-prod1"},
+			Spec:       gardencorev1beta1.ShootSpec{SeedName: pointer.String("myseed")},
+		}
+
+		ctrl := gomock.NewController(GinkgoT())
+		defer ctrl.Finish()
+
+		clientProvider := targetmocks.NewMockClientProvider(ctrl)
+		clientProvider.EXPECT().FromClientConfig(gomock.Any()).Return(internalfake.NewClientWithObjects(project, shoot), nil)
+		factory = internalfake.NewFakeFactory(cfg, nil, clientProvider, targetProvider)
+
+		o := cmdtarget.NewViewOptions(streams)
+		o.Output = "json"
+		cmd := cmdtarget.NewCmdView(factory, o)
+
+		Expect(cmd.RunE(cmd, nil)).To(Succeed())
+		Expect(out.String()).To(Equal(`{
+  "garden": "mygarden",
+  "project": "myproject",
+  "namespace": "garden-prod1",
+  "seed": "myseed",
+  "shoot": "myshoot"
+}
+`))
+		Expect(errOut.String()).To(BeEmpty())
+	})
+
+	It("should print the persisted target without contacting the garden in offline mode", func() {
+		cfg := &config.Config{
+			LinkKubeconfig: pointer.Bool(false),
+			Offline:        true,
+			Gardens:        []config.Garden{{Name: gardenName, Kubeconfig: "/not/a/real/file"}},
+		}
+
+		ctrl := gomock.NewController(GinkgoT())
+		defer ctrl.Finish()
+
+		// the mock fails the test if a garden client is created
+		factory = internalfake.NewFakeFactory(cfg, nil, targetmocks.NewMockClientProvider(ctrl), targetProvider)
+
+		o := cmdtarget.NewViewOptions(streams)
+		o.Output = "json"
+		cmd := cmdtarget.NewCmdView(factory, o)
+
+		Expect(cmd.RunE(cmd, nil)).To(Succeed())
+		Expect(out.String()).To(Equal(`{
+  "garden": "mygarden",
+  "project": "myproject",
+  "shoot": "myshoot"
+}
+`))
+		Expect(errOut.String()).To(BeEmpty())
+	})
+
+	Context("when the output is prompt", func() {
+		It("should print the target in a short form without resolving it", func() {
+			o := cmdtarget.NewViewOptions(streams)
+			o.Output = "prompt"
+			cmd := cmdtarget.NewCmdView(factory, o)
+
+			Expect(cmd.RunE(cmd, nil)).To(Succeed())
+			Expect(out.String()).To(Equal("mygarden/myproject/myshoot"))
+			Expect(errOut.String()).To(BeEmpty())
+		})
+
+		Context("and the control plane is targeted", func() {
+			BeforeEach(func() {
+				currentTarget = target.NewTarget(gardenName, "", "myseed", shootName).WithControlPlane(true)
+			})
+
+			It("should print the control plane", func() {
+				o := cmdtarget.NewViewOptions(streams)
+				o.Output = "prompt"
+				cmd := cmdtarget.NewCmdView(factory, o)
+
+				Expect(cmd.RunE(cmd, nil)).To(Succeed())
+				Expect(out.String()).To(Equal("mygarden/myseed/myshoot (control plane)"))
+			})
+		})
+
+		Context("and the target is empty", func() {
+			BeforeEach(func() {
+				currentTarget = target.NewTarget("", "", "", "")
+			})
+
+			It("should print nothing", func() {
+				o := cmdtarget.NewViewOptions(streams)
+				o.Output = "prompt"
+				cmd := cmdtarget.NewCmdView(factory, o)
+
+				Expect(cmd.RunE(cmd, nil)).To(Succeed())
+				Expect(out.String()).To(BeEmpty())
+			})
+		})
 	})
 })
 
@@ -58,4 +172,11 @@ var _ = Describe("Target View Options", func() {
 		o := cmdtarget.NewViewOptions(streams)
 		Expect(o.Validate()).ToNot(HaveOccurred())
 	})
+
+	It("should accept the prompt output", func() {
+		streams, _, _, _ := util.NewTestIOStreams()
+		o := cmdtarget.NewViewOptions(streams)
+		o.Output = "prompt"
+		Expect(o.Validate()).ToNot(HaveOccurred())
+	})
 })
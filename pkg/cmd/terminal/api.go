@@ -0,0 +1,120 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package terminal
+
+import (
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// The types in this file are the subset of the Terminal resource of the terminal-controller-manager
+// (https://github.com/gardener/terminal-controller-manager) that is used by gardenctl. The resource is
+// handled as unstructured object, so that the API of the terminal-controller-manager is not a dependency.
+
+const (
+	// terminalContainerName is the name of the container of the terminal pod that is attached to
+	terminalContainerName = "terminal"
+	// terminalOperationAnnotation is the annotation that is set to keepalive to prevent the garbage collection of a terminal
+	terminalOperationAnnotation = "dashboard.gardener.cloud/operation"
+	// terminalOperationKeepalive is the value of terminalOperationAnnotation that renews the terminal
+	terminalOperationKeepalive = "keepalive"
+)
+
+// terminalGVK is the group, version and kind of the Terminal resource
+var terminalGVK = schema.GroupVersionKind{Group: "dashboard.gardener.cloud", Version: "v1alpha1", Kind: "Terminal"}
+
+type terminal struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   terminalSpec   `json:"spec"`
+	Status terminalStatus `json:"status,omitempty"`
+}
+
+type terminalSpec struct {
+	// Identifier is a unique identifier of the terminal of a user
+	Identifier string `json:"identifier"`
+	// Host is the cluster the terminal pod runs in
+	Host hostCluster `json:"host"`
+	// Target is the cluster the kubeconfig of the terminal pod points to
+	Target targetCluster `json:"target"`
+}
+
+type clusterCredentials struct {
+	// ShootRef references the shoot whose API server is used
+	ShootRef *shootRef `json:"shootRef,omitempty"`
+}
+
+type shootRef struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+type hostCluster struct {
+	Credentials clusterCredentials `json:"credentials"`
+	// Namespace is the namespace of the terminal pod, it is set by the controller for temporary namespaces
+	Namespace          *string     `json:"namespace,omitempty"`
+	TemporaryNamespace bool        `json:"temporaryNamespace,omitempty"`
+	Pod                terminalPod `json:"pod"`
+}
+
+type terminalPod struct {
+	Container   *terminalContainer `json:"container,omitempty"`
+	HostPID     bool               `json:"hostPID,omitempty"`
+	HostNetwork bool               `json:"hostNetwork,omitempty"`
+}
+
+type terminalContainer struct {
+	Image      string `json:"image"`
+	Privileged bool   `json:"privileged,omitempty"`
+}
+
+type targetCluster struct {
+	Credentials                clusterCredentials `json:"credentials"`
+	Namespace                  *string            `json:"namespace,omitempty"`
+	TemporaryNamespace         bool               `json:"temporaryNamespace,omitempty"`
+	KubeconfigContextNamespace string             `json:"kubeconfigContextNamespace"`
+	BindingKind                string             `json:"bindingKind,omitempty"`
+	RoleName                   string             `json:"roleName,omitempty"`
+}
+
+type terminalStatus struct {
+	// AttachServiceAccountName is the name of the service account that is allowed to attach to the terminal pod
+	AttachServiceAccountName string `json:"attachServiceAccountName,omitempty"`
+	// PodName is the name of the terminal pod
+	PodName string `json:"podName,omitempty"`
+	// LastError is the last error that occurred while the controller reconciled the terminal
+	LastError *gardencorev1beta1.LastError `json:"lastError,omitempty"`
+}
+
+// ready returns true if the controller has created the terminal pod
+func (t *terminal) ready() bool {
+	return t.Status.PodName != "" && t.Status.AttachServiceAccountName != "" && t.Spec.Host.Namespace != nil
+}
+
+func (t *terminal) toUnstructured() (*unstructured.Unstructured, error) {
+	t.SetGroupVersionKind(terminalGVK)
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(t)
+	if err != nil {
+		return nil, err
+	}
+
+	return &unstructured.Unstructured{Object: content}, nil
+}
+
+func terminalFromUnstructured(u *unstructured.Unstructured) (*terminal, error) {
+	t := &terminal{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.UnstructuredContent(), t); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
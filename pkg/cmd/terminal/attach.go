@@ -0,0 +1,128 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package terminal
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"golang.org/x/term"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/remotecommand"
+
+	"github.com/gardener/gardenctl-v2/internal/util"
+)
+
+// resizePollInterval is the interval in which the size of the local terminal is checked
+const resizePollInterval = 250 * time.Millisecond
+
+// attachToPod waits until the terminal pod is running and attaches to its container with an interactive TTY.
+// The local terminal is put into raw mode while attached, so that all input is sent to the terminal pod.
+var attachToPod = func(ctx context.Context, clientConfig clientcmd.ClientConfig, namespace, podName string, ioStreams util.IOStreams) error {
+	restConfig, err := clientConfig.ClientConfig()
+	if err != nil {
+		return err
+	}
+
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+
+	err = wait.PollImmediateUntil(pollTerminalStatusInterval, func() (bool, error) {
+		pod, err := clientset.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+
+		if pod.Status.Phase == corev1.PodFailed || pod.Status.Phase == corev1.PodSucceeded {
+			return false, fmt.Errorf("terminal pod %s has terminated", podName)
+		}
+
+		return pod.Status.Phase == corev1.PodRunning, nil
+	}, ctx.Done())
+	if err != nil {
+		return fmt.Errorf("failed to wait for terminal pod %s: %w", podName, err)
+	}
+
+	req := clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(podName).
+		SubResource("attach").
+		VersionedParams(&corev1.PodAttachOptions{
+			Container: terminalContainerName,
+			Stdin:     true,
+			Stdout:    true,
+			TTY:       true,
+		}, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(restConfig, "POST", req.URL())
+	if err != nil {
+		return fmt.Errorf("failed to attach to terminal pod %s: %w", podName, err)
+	}
+
+	options := remotecommand.StreamOptions{
+		Stdin:  ioStreams.In,
+		Stdout: ioStreams.Out,
+		Tty:    true,
+	}
+
+	if in, ok := ioStreams.In.(*os.File); ok && term.IsTerminal(int(in.Fd())) {
+		fd := int(in.Fd())
+
+		state, err := term.MakeRaw(fd)
+		if err != nil {
+			return fmt.Errorf("failed to put the terminal into raw mode: %w", err)
+		}
+		defer func() {
+			_ = term.Restore(fd, state)
+		}()
+
+		done := make(chan struct{})
+		defer close(done)
+
+		options.TerminalSizeQueue = &terminalSizeQueue{fd: fd, done: done}
+	}
+
+	return executor.Stream(options)
+}
+
+// terminalSizeQueue reports the size of the local terminal whenever it changes
+type terminalSizeQueue struct {
+	fd   int
+	done <-chan struct{}
+	last remotecommand.TerminalSize
+}
+
+var _ remotecommand.TerminalSizeQueue = &terminalSizeQueue{}
+
+// Next blocks until the size of the local terminal changes and returns the new size, nil is returned once the stream has ended
+func (q *terminalSizeQueue) Next() *remotecommand.TerminalSize {
+	for {
+		if width, height, err := term.GetSize(q.fd); err == nil {
+			size := remotecommand.TerminalSize{Width: uint16(width), Height: uint16(height)}
+			if size != q.last {
+				q.last = size
+				return &size
+			}
+		}
+
+		select {
+		case <-q.done:
+			return nil
+		case <-time.After(resizePollInterval):
+		}
+	}
+}
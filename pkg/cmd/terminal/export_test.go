@@ -0,0 +1,28 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package terminal
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/gardener/gardenctl-v2/internal/util"
+)
+
+func SetAttachToPod(f func(ctx context.Context, clientConfig clientcmd.ClientConfig, namespace, podName string, ioStreams util.IOStreams) error) {
+	attachToPod = f
+}
+
+func SetPollTerminalStatusInterval(d time.Duration) {
+	pollTerminalStatusInterval = d
+}
+
+func SetIdentifierProvider(f func() (string, error)) {
+	identifierProvider = f
+}
@@ -0,0 +1,338 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package terminal
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	corev1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
+)
+
+// DefaultImage is the image of the terminal container, it contains the tools of the Gardener ops toolbelt
+const DefaultImage = "eu.gcr.io/gardener-project/gardener/ops-toolbelt:latest"
+
+var (
+	// pollTerminalStatusInterval is the interval in which the status of the terminal is checked
+	pollTerminalStatusInterval = 2 * time.Second
+	// keepAliveInterval is the interval in which the terminal is renewed while it is attached
+	keepAliveInterval = 2 * time.Minute
+	// identifierProvider returns the unique identifier of a new terminal
+	identifierProvider = func() (string, error) {
+		b := make([]byte, 4)
+		if _, err := rand.Read(b); err != nil {
+			return "", err
+		}
+
+		return "gardenctl-" + hex.EncodeToString(b), nil
+	}
+)
+
+// NewCmdTerminal returns a new terminal command.
+func NewCmdTerminal(f util.Factory, o *TerminalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "terminal",
+		Short: "Open a terminal in the targeted shoot or its control plane",
+		Long: `Open a terminal in the targeted shoot or its control plane, like the web terminal of the Gardener dashboard.
+A Terminal resource is created in the garden cluster, which is reconciled by the terminal-controller-manager of the Gardener
+dashboard. Once the terminal pod is running, an interactive TTY is attached to it. The kubeconfig of the terminal pod points to
+the targeted cluster. The terminal is deleted when the TTY is detached, e.g. by exiting the shell, unless --keep-terminal is given.
+
+If the control plane of the shoot is targeted, the terminal pod runs in the control plane namespace of the shoot on its seed.
+Terminals for control planes require the seed to be a managed seed and are usually only allowed for Gardener operators.`,
+		Example: `# open a terminal in the targeted shoot
+gardenctl terminal
+
+# open a terminal in the control plane of the shoot my-shoot
+gardenctl terminal --shoot my-shoot --control-plane
+
+# open a terminal with another image
+gardenctl terminal --image busybox`,
+		Args: cobra.NoArgs,
+		RunE: base.WrapRunE(o, f),
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// TerminalOptions is a struct to support terminal command
+type TerminalOptions struct {
+	base.Options
+
+	// Image is the image of the terminal container
+	Image string
+	// Privileged runs the terminal container privileged in the host PID and network namespaces
+	Privileged bool
+	// KeepTerminal does not delete the terminal when gardenctl exits
+	KeepTerminal bool
+	// WaitTimeout is the maximum duration to wait for the terminal pod
+	WaitTimeout time.Duration
+}
+
+// NewTerminalOptions returns initialized TerminalOptions
+func NewTerminalOptions(ioStreams util.IOStreams) *TerminalOptions {
+	return &TerminalOptions{
+		Options: base.Options{
+			IOStreams: ioStreams,
+		},
+		Image:       DefaultImage,
+		WaitTimeout: 5 * time.Minute,
+	}
+}
+
+// AddFlags binds the command options to a given flagset
+func (o *TerminalOptions) AddFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&o.Image, "image", o.Image, "Image of the terminal container.")
+	flags.BoolVar(&o.Privileged, "privileged", o.Privileged, "Run the terminal container privileged in the host PID and network namespaces of its node.")
+	flags.BoolVar(&o.KeepTerminal, "keep-terminal", o.KeepTerminal, "Do not delete the terminal when gardenctl exits (terminals are garbage-collected after some time).")
+	flags.DurationVar(&o.WaitTimeout, "wait-timeout", o.WaitTimeout, "Maximum duration to wait for the terminal to become ready.")
+}
+
+// Validate validates the provided options
+func (o *TerminalOptions) Validate() error {
+	if o.Image == "" {
+		return errors.New("the image must not be empty")
+	}
+
+	if o.WaitTimeout <= 0 {
+		return errors.New("the wait timeout must be positive")
+	}
+
+	return nil
+}
+
+// Run executes the command
+func (o *TerminalOptions) Run(f util.Factory) error {
+	manager, err := f.Manager()
+	if err != nil {
+		return err
+	}
+
+	currentTarget, err := manager.CurrentTarget()
+	if err != nil {
+		return err
+	}
+
+	if currentTarget.ShootName() == "" {
+		return errors.New("no Shoot cluster targeted")
+	}
+
+	gardenClient, err := manager.GardenClient(currentTarget.GardenName())
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(f.Context())
+	defer cancel()
+
+	shoot, err := util.ShootForTarget(ctx, gardenClient, currentTarget)
+	if err != nil {
+		return err
+	}
+
+	identifier, err := identifierProvider()
+	if err != nil {
+		return fmt.Errorf("failed to create terminal identifier: %w", err)
+	}
+
+	t, err := o.newTerminal(shoot, identifier, currentTarget.ControlPlane())
+	if err != nil {
+		return err
+	}
+
+	obj, err := t.toUnstructured()
+	if err != nil {
+		return err
+	}
+
+	runtimeClient := gardenClient.RuntimeClient()
+
+	if err := runtimeClient.Create(ctx, obj); err != nil {
+		return fmt.Errorf("failed to create terminal: %w", err)
+	}
+
+	// in dry-run mode the terminal is only printed, there is nothing to wait for or to attach to
+	if manager.Configuration().IsDryRun() {
+		return nil
+	}
+
+	fmt.Fprintf(o.IOStreams.ErrOut, "Created terminal %s in namespace %s\n", obj.GetName(), obj.GetNamespace())
+
+	// do not use ctx, as it might be cancelled already when running the cleanup
+	defer o.cleanup(f.Context(), runtimeClient, obj)
+
+	go keepTerminalAlive(ctx, runtimeClient, obj.DeepCopy(), o.IOStreams.ErrOut)
+
+	fmt.Fprintf(o.IOStreams.ErrOut, "Waiting up to %v for the terminal to be ready…\n", o.WaitTimeout)
+
+	t, err = waitForTerminal(ctx, runtimeClient, obj, o.WaitTimeout)
+	if err != nil {
+		return err
+	}
+
+	// the terminal pod runs in the shoot, or in the seed for control plane terminals,
+	// which is the cluster of the client configuration of the current target
+	clientConfig, err := manager.ClientConfig(ctx, currentTarget)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(o.IOStreams.ErrOut, "Attaching to terminal pod %s, exit the shell to detach…\n", t.Status.PodName)
+
+	return attachToPod(ctx, clientConfig, *t.Spec.Host.Namespace, t.Status.PodName, o.IOStreams)
+}
+
+// newTerminal returns the terminal for the shoot or, if controlPlane is true, for the control plane of the shoot
+func (o *TerminalOptions) newTerminal(shoot *gardencorev1beta1.Shoot, identifier string, controlPlane bool) (*terminal, error) {
+	t := &terminal{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "term-",
+			Namespace:    shoot.Namespace,
+		},
+		Spec: terminalSpec{
+			Identifier: identifier,
+			Host: hostCluster{
+				Pod: terminalPod{
+					Container: &terminalContainer{
+						Image:      o.Image,
+						Privileged: o.Privileged,
+					},
+					HostPID:     o.Privileged,
+					HostNetwork: o.Privileged,
+				},
+			},
+		},
+	}
+
+	if !controlPlane {
+		credentials := clusterCredentials{ShootRef: &shootRef{Namespace: shoot.Namespace, Name: shoot.Name}}
+
+		t.Spec.Host.Credentials = credentials
+		t.Spec.Host.TemporaryNamespace = true
+		t.Spec.Target = targetCluster{
+			Credentials:                credentials,
+			TemporaryNamespace:         true,
+			KubeconfigContextNamespace: "default",
+			BindingKind:                "ClusterRoleBinding",
+			RoleName:                   "cluster-admin",
+		}
+
+		return t, nil
+	}
+
+	if shoot.Spec.SeedName == nil || *shoot.Spec.SeedName == "" {
+		return nil, fmt.Errorf("shoot %q has not yet been assigned to a seed", shoot.Name)
+	}
+
+	if shoot.Status.TechnicalID == "" {
+		return nil, fmt.Errorf("no technicalID has been assigned to the shoot %q yet", shoot.Name)
+	}
+
+	// the seed is referenced as the shoot of the managed seed in the garden namespace
+	credentials := clusterCredentials{ShootRef: &shootRef{Namespace: corev1beta1constants.GardenNamespace, Name: *shoot.Spec.SeedName}}
+
+	t.Spec.Host.Credentials = credentials
+	t.Spec.Host.Namespace = pointer.String(shoot.Status.TechnicalID)
+	t.Spec.Target = targetCluster{
+		Credentials:                credentials,
+		Namespace:                  pointer.String(shoot.Status.TechnicalID),
+		KubeconfigContextNamespace: shoot.Status.TechnicalID,
+		BindingKind:                "RoleBinding",
+		RoleName:                   "admin",
+	}
+
+	return t, nil
+}
+
+// waitForTerminal waits until the terminal-controller-manager has created the terminal pod
+func waitForTerminal(ctx context.Context, c client.Client, obj *unstructured.Unstructured, timeout time.Duration) (*terminal, error) {
+	var t *terminal
+
+	err := wait.PollImmediate(pollTerminalStatusInterval, timeout, func() (bool, error) {
+		if err := c.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+			return false, err
+		}
+
+		var err error
+
+		t, err = terminalFromUnstructured(obj)
+		if err != nil {
+			return false, err
+		}
+
+		if t.Status.LastError != nil {
+			return false, fmt.Errorf("failed to create terminal: %s", t.Status.LastError.Description)
+		}
+
+		return t.ready(), nil
+	})
+
+	if err == wait.ErrWaitTimeout {
+		return nil, base.NewTimeoutError(errors.New("timed out waiting for the terminal to be ready, is the terminal-controller-manager of the Gardener dashboard installed?"))
+	}
+
+	return t, err
+}
+
+// keepTerminalAlive renews the terminal, so that it is not garbage collected while it is attached
+func keepTerminalAlive(ctx context.Context, c client.Client, obj *unstructured.Unstructured, stderr io.Writer) {
+	ticker := time.NewTicker(keepAliveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			patch := client.MergeFrom(obj.DeepCopy())
+
+			annotations := obj.GetAnnotations()
+			if annotations == nil {
+				annotations = map[string]string{}
+			}
+
+			annotations[terminalOperationAnnotation] = terminalOperationKeepalive
+			obj.SetAnnotations(annotations)
+
+			if err := c.Patch(ctx, obj, patch); err != nil {
+				fmt.Fprintf(stderr, "Failed to keep terminal alive: %v\n", err)
+			}
+		}
+	}
+}
+
+func (o *TerminalOptions) cleanup(ctx context.Context, c client.Client, obj *unstructured.Unstructured) {
+	if o.KeepTerminal {
+		fmt.Fprintf(o.IOStreams.ErrOut, "Keeping terminal %s in namespace %s.\n", obj.GetName(), obj.GetNamespace())
+		return
+	}
+
+	fmt.Fprintf(o.IOStreams.ErrOut, "Deleting terminal %s…\n", obj.GetName())
+
+	if err := c.Delete(ctx, obj); client.IgnoreNotFound(err) != nil {
+		fmt.Fprintf(o.IOStreams.ErrOut, "Failed to delete terminal: %v\n", err)
+	}
+}
@@ -0,0 +1,237 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package terminal_test
+
+import (
+	"context"
+	"time"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	internalfake "github.com/gardener/gardenctl-v2/internal/fake"
+	"github.com/gardener/gardenctl-v2/internal/util"
+	"github.com/gardener/gardenctl-v2/pkg/cmd/terminal"
+	"github.com/gardener/gardenctl-v2/pkg/config"
+	"github.com/gardener/gardenctl-v2/pkg/target"
+	targetmocks "github.com/gardener/gardenctl-v2/pkg/target/mocks"
+)
+
+var _ = Describe("Terminal Command", func() {
+	const (
+		gardenName = "mygarden"
+		namespace  = "garden-prod1"
+	)
+
+	var (
+		ctrl           *gomock.Controller
+		clientProvider *targetmocks.MockClientProvider
+		cfg            *config.Config
+		streams        util.IOStreams
+		errOut         *util.SafeBytesBuffer
+		factory        *internalfake.Factory
+		gardenClient   client.Client
+		currentTarget  target.Target
+		attached       []string
+		ctx            context.Context
+		cancel         context.CancelFunc
+	)
+
+	listTerminals := func() []unstructured.Unstructured {
+		list := &unstructured.UnstructuredList{}
+		list.SetAPIVersion("dashboard.gardener.cloud/v1alpha1")
+		list.SetKind("TerminalList")
+		Expect(gardenClient.List(ctx, list, client.InNamespace(namespace))).To(Succeed())
+
+		return list.Items
+	}
+
+	nestedField := func(obj unstructured.Unstructured, fields ...string) interface{} {
+		value, found, err := unstructured.NestedFieldNoCopy(obj.Object, fields...)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(BeTrue())
+
+		return value
+	}
+
+	// simulate the terminal-controller-manager, which creates the terminal pod
+	reconcileTerminal := func() {
+		defer GinkgoRecover()
+
+		Eventually(func() error {
+			terminals := listTerminals()
+			if len(terminals) == 0 {
+				return context.DeadlineExceeded
+			}
+
+			obj := &terminals[0]
+			Expect(unstructured.SetNestedField(obj.Object, "term-host-abcde", "spec", "host", "namespace")).To(Succeed())
+			Expect(unstructured.SetNestedField(obj.Object, "terminal-abcde", "status", "podName")).To(Succeed())
+			Expect(unstructured.SetNestedField(obj.Object, "term-attach-abcde", "status", "attachServiceAccountName")).To(Succeed())
+
+			return gardenClient.Update(ctx, obj)
+		}).Should(Succeed())
+	}
+
+	BeforeEach(func() {
+		terminal.SetPollTerminalStatusInterval(10 * time.Millisecond)
+		terminal.SetIdentifierProvider(func() (string, error) {
+			return "gardenctl-1234", nil
+		})
+
+		attached = nil
+		terminal.SetAttachToPod(func(ctx context.Context, clientConfig clientcmd.ClientConfig, namespace, podName string, ioStreams util.IOStreams) error {
+			rawConfig, err := clientConfig.RawConfig()
+			Expect(err).NotTo(HaveOccurred())
+
+			attached = append(attached, rawConfig.CurrentContext, namespace, podName)
+
+			return nil
+		})
+
+		cfg = &config.Config{
+			LinkKubeconfig: pointer.Bool(false),
+			Gardens: []config.Garden{{
+				Name:       gardenName,
+				Kubeconfig: "/not/a/real/kubeconfig",
+			}},
+		}
+
+		shootKubeconfig := clientcmdapi.NewConfig()
+		shootKubeconfig.CurrentContext = "my-shoot"
+		shootKubeconfigData, err := clientcmd.Write(*shootKubeconfig)
+		Expect(err).NotTo(HaveOccurred())
+
+		gardenClient = internalfake.NewClientWithObjects(
+			&gardencorev1beta1.Project{
+				ObjectMeta: metav1.ObjectMeta{Name: "prod1"},
+				Spec:       gardencorev1beta1.ProjectSpec{Namespace: pointer.String(namespace)},
+			},
+			&gardencorev1beta1.Shoot{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-shoot", Namespace: namespace},
+				Spec:       gardencorev1beta1.ShootSpec{SeedName: pointer.String("my-seed")},
+				Status:     gardencorev1beta1.ShootStatus{TechnicalID: "shoot--prod1--my-shoot"},
+			},
+			&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-shoot.kubeconfig", Namespace: namespace},
+				Data:       map[string]string{"kubeconfig": string(shootKubeconfigData)},
+			},
+		)
+
+		ctrl = gomock.NewController(GinkgoT())
+		clientProvider = targetmocks.NewMockClientProvider(ctrl)
+		clientConfig, err := cfg.ClientConfig(gardenName)
+		Expect(err).NotTo(HaveOccurred())
+		clientProvider.EXPECT().FromClientConfig(gomock.Eq(clientConfig)).Return(gardenClient, nil).AnyTimes()
+
+		currentTarget = target.NewTarget(gardenName, "prod1", "", "my-shoot")
+
+		streams, _, _, errOut = util.NewTestIOStreams()
+
+		ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
+	})
+
+	JustBeforeEach(func() {
+		factory = internalfake.NewFakeFactory(cfg, nil, clientProvider, internalfake.NewFakeTargetProvider(currentTarget))
+		factory.ContextImpl = ctx
+	})
+
+	AfterEach(func() {
+		cancel()
+		ctrl.Finish()
+	})
+
+	It("should create a terminal for the shoot, attach to its pod and delete it", func() {
+		go reconcileTerminal()
+
+		cmd := terminal.NewCmdTerminal(factory, terminal.NewTerminalOptions(streams))
+		cmd.SetArgs([]string{})
+		Expect(cmd.Execute()).To(Succeed())
+
+		Expect(attached).To(Equal([]string{"my-shoot", "term-host-abcde", "terminal-abcde"}))
+		Expect(errOut.String()).To(ContainSubstring("Deleting terminal term-"))
+		Expect(listTerminals()).To(BeEmpty())
+	})
+
+	It("should keep the terminal", func() {
+		go reconcileTerminal()
+
+		cmd := terminal.NewCmdTerminal(factory, terminal.NewTerminalOptions(streams))
+		cmd.SetArgs([]string{"--keep-terminal", "--image", "busybox", "--privileged"})
+		Expect(cmd.Execute()).To(Succeed())
+
+		terminals := listTerminals()
+		Expect(terminals).To(HaveLen(1))
+
+		spec := terminals[0].Object["spec"]
+		Expect(spec).To(HaveKeyWithValue("identifier", "gardenctl-1234"))
+		Expect(nestedField(terminals[0], "spec", "host", "pod", "container", "image")).To(Equal("busybox"))
+		Expect(nestedField(terminals[0], "spec", "host", "pod", "container", "privileged")).To(Equal(true))
+		Expect(nestedField(terminals[0], "spec", "target", "credentials", "shootRef", "name")).To(Equal("my-shoot"))
+		Expect(nestedField(terminals[0], "spec", "target", "roleName")).To(Equal("cluster-admin"))
+	})
+
+	Context("with a targeted control plane", func() {
+		BeforeEach(func() {
+			currentTarget = currentTarget.WithControlPlane(true)
+		})
+
+		It("should create a terminal in the control plane namespace of the seed", func() {
+			cmd := terminal.NewCmdTerminal(factory, terminal.NewTerminalOptions(streams))
+			cmd.SetArgs([]string{"--keep-terminal", "--wait-timeout", "50ms"})
+			Expect(cmd.Execute()).To(MatchError(ContainSubstring("timed out waiting for the terminal to be ready")))
+
+			terminals := listTerminals()
+			Expect(terminals).To(HaveLen(1))
+			Expect(nestedField(terminals[0], "spec", "host", "credentials", "shootRef", "namespace")).To(Equal("garden"))
+			Expect(nestedField(terminals[0], "spec", "host", "credentials", "shootRef", "name")).To(Equal("my-seed"))
+			Expect(nestedField(terminals[0], "spec", "host", "namespace")).To(Equal("shoot--prod1--my-shoot"))
+			Expect(nestedField(terminals[0], "spec", "target", "bindingKind")).To(Equal("RoleBinding"))
+		})
+	})
+
+	It("should fail if the terminal cannot be created", func() {
+		go func() {
+			defer GinkgoRecover()
+
+			Eventually(func() error {
+				terminals := listTerminals()
+				if len(terminals) == 0 {
+					return context.DeadlineExceeded
+				}
+
+				obj := &terminals[0]
+				Expect(unstructured.SetNestedField(obj.Object, "forbidden", "status", "lastError", "description")).To(Succeed())
+
+				return gardenClient.Update(ctx, obj)
+			}).Should(Succeed())
+		}()
+
+		cmd := terminal.NewCmdTerminal(factory, terminal.NewTerminalOptions(streams))
+		cmd.SetArgs([]string{})
+		Expect(cmd.Execute()).To(MatchError("failed to create terminal: forbidden"))
+		Expect(attached).To(BeEmpty())
+	})
+
+	It("should fail without a targeted shoot", func() {
+		currentTarget = target.NewTarget(gardenName, "prod1", "", "")
+		factory = internalfake.NewFakeFactory(cfg, nil, clientProvider, internalfake.NewFakeTargetProvider(currentTarget))
+
+		cmd := terminal.NewCmdTerminal(factory, terminal.NewTerminalOptions(streams))
+		cmd.SetArgs([]string{})
+		Expect(cmd.Execute()).To(MatchError("no Shoot cluster targeted"))
+	})
+})
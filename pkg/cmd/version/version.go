@@ -7,31 +7,37 @@ SPDX-License-Identifier: Apache-2.0
 package version
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+	apimachineryversion "k8s.io/apimachinery/pkg/version"
 	"k8s.io/component-base/version"
 
+	"github.com/gardener/gardenctl-v2/internal/release"
 	"github.com/gardener/gardenctl-v2/internal/util"
 	"github.com/gardener/gardenctl-v2/pkg/cmd/base"
 )
 
+// checkTimeout is the maximum duration to fetch the releases of gardenctl
+const checkTimeout = 10 * time.Second
+
 // NewCmdVersion returns a new version command.
 func NewCmdVersion(f util.Factory, o *VersionOptions) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "version",
 		Short: "Print the gardenctl version information",
-		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := o.Complete(f, cmd, args); err != nil {
-				return fmt.Errorf("failed to complete command options: %w", err)
-			}
-			if err := o.Validate(); err != nil {
-				return err
-			}
-
-			return runCmdVersion(o)
-		},
+		Long: `Print the gardenctl version information, including the git commit, the build date and the Go version.
+With --check, the GitHub releases of gardenctl are fetched and it is printed whether a newer release is available
+and whether any of the newer releases contains security fixes.`,
+		Example: `# print the version information as JSON
+gardenctl version -o json
+
+# check whether a newer release is available
+gardenctl version --check`,
+		RunE: base.WrapRunE(o, f),
 	}
 
 	o.AddFlags(cmd.Flags())
@@ -39,21 +45,66 @@ func NewCmdVersion(f util.Factory, o *VersionOptions) *cobra.Command {
 	return cmd
 }
 
-func runCmdVersion(opt *VersionOptions) error {
+// versionOutput is printed with an output format, Update is only set with --check
+type versionOutput struct {
+	apimachineryversion.Info `json:",inline" yaml:",inline"`
+	Update                   *release.CheckResult `json:"update,omitempty" yaml:"update,omitempty"`
+}
+
+// Run executes the command
+func (o *VersionOptions) Run(f util.Factory) error {
 	versionInfo := version.Get()
 
-	if opt.Output == "" {
+	var update *release.CheckResult
+
+	if o.Check {
 		var err error
-		if opt.Short {
-			_, err = fmt.Fprintf(opt.IOStreams.Out, "Version: %s\n", versionInfo.GitVersion)
-		} else {
-			_, err = fmt.Fprintf(opt.IOStreams.Out, "Version: %#v\n", versionInfo)
+
+		update, err = o.checkForUpdate(f.Context(), versionInfo.GitVersion)
+		if err != nil {
+			return err
 		}
+	}
+
+	if o.Output != "" {
+		return o.PrintObject(versionOutput{Info: versionInfo, Update: update})
+	}
+
+	if o.Short {
+		fmt.Fprintf(o.IOStreams.Out, "Version: %s\n", versionInfo.GitVersion)
+	} else {
+		fmt.Fprintf(o.IOStreams.Out, "Version: %#v\n", versionInfo)
+	}
+
+	if update != nil {
+		switch {
+		case update.SecurityFixes:
+			fmt.Fprintf(o.IOStreams.Out, "A newer release %s with security fixes is available, please update as soon as possible: %s\n", update.LatestVersion, update.URL)
+		case update.UpdateAvailable:
+			fmt.Fprintf(o.IOStreams.Out, "A newer release %s is available: %s\n", update.LatestVersion, update.URL)
+		default:
+			fmt.Fprintf(o.IOStreams.Out, "gardenctl is up to date, the latest release is %s\n", update.LatestVersion)
+		}
+	}
+
+	return nil
+}
+
+func (o *VersionOptions) checkForUpdate(ctx context.Context, currentVersion string) (*release.CheckResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	releases, err := release.List(ctx, o.ReleasesURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for a newer release: %w", err)
+	}
 
-		return err
+	update, err := release.Check(releases, currentVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for a newer release: %w", err)
 	}
 
-	return opt.PrintObject(versionInfo)
+	return update, nil
 }
 
 // VersionOptions is a struct to support version command
@@ -63,6 +114,10 @@ type VersionOptions struct {
 
 	// Short indicates if just the version number should be printed
 	Short bool
+	// Check indicates if the GitHub releases should be checked for a newer release
+	Check bool
+	// ReleasesURL is the GitHub API endpoint that lists the releases of gardenctl
+	ReleasesURL string
 }
 
 // NewVersionOptions returns initialized VersionOptions
@@ -71,11 +126,13 @@ func NewVersionOptions(ioStreams util.IOStreams) *VersionOptions {
 		Options: base.Options{
 			IOStreams: ioStreams,
 		},
+		ReleasesURL: release.DefaultReleasesURL,
 	}
 }
 
 // AddFlags adds flags to adjust the output to a cobra command
 func (o *VersionOptions) AddFlags(flags *pflag.FlagSet) {
 	flags.BoolVar(&o.Short, "short", o.Short, "If true, print just the version number.")
+	flags.BoolVar(&o.Check, "check", o.Check, "If true, check whether a newer release of gardenctl is available.")
 	o.Options.AddFlags(flags)
 }
@@ -8,10 +8,13 @@ package version_test
 
 import (
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
+	"github.com/gardener/gardenctl-v2/internal/release"
 	"github.com/gardener/gardenctl-v2/internal/util"
 	. "github.com/gardener/gardenctl-v2/pkg/cmd/version"
 )
@@ -72,6 +75,63 @@ var _ = Describe("Version Command", func() {
 		Expect(anyJSON["gitVersion"]).To(HavePrefix("v0.0.0-master"))
 	})
 
+	Context("with --check", func() {
+		var (
+			server   *httptest.Server
+			releases []release.Release
+		)
+
+		BeforeEach(func() {
+			releases = []release.Release{
+				{TagName: "v2.0.0", HTMLURL: "https://github.com/gardener/gardenctl-v2/releases/tag/v2.0.0"},
+			}
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Expect(json.NewEncoder(w).Encode(releases)).To(Succeed())
+			}))
+			o.ReleasesURL = server.URL
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("should print that a newer release is available", func() {
+			cmd := NewCmdVersion(factory, o)
+			cmd.SetArgs(append(args, "--short", "--check"))
+			Expect(cmd.Execute()).To(Succeed())
+			Expect(buf.String()).To(HaveSuffix("A newer release v2.0.0 is available: https://github.com/gardener/gardenctl-v2/releases/tag/v2.0.0\n"))
+		})
+
+		It("should print that a newer release contains security fixes", func() {
+			releases[0].Body = "Security fixes"
+
+			cmd := NewCmdVersion(factory, o)
+			cmd.SetArgs(append(args, "--short", "--check"))
+			Expect(cmd.Execute()).To(Succeed())
+			Expect(buf.String()).To(ContainSubstring("A newer release v2.0.0 with security fixes is available"))
+		})
+
+		It("should include the check result in the output", func() {
+			cmd := NewCmdVersion(factory, o)
+			cmd.SetArgs(append(args, "--check", "--output", "json"))
+			Expect(cmd.Execute()).To(Succeed())
+			var anyJSON map[string]interface{}
+			Expect(json.Unmarshal([]byte(buf.String()), &anyJSON)).To(Succeed())
+			Expect(anyJSON["gitVersion"]).To(HavePrefix("v0.0.0-master"))
+			Expect(anyJSON["goVersion"]).NotTo(BeEmpty())
+			Expect(anyJSON["update"]).To(HaveKeyWithValue("latestVersion", "v2.0.0"))
+			Expect(anyJSON["update"]).To(HaveKeyWithValue("updateAvailable", true))
+		})
+
+		It("should fail if the releases cannot be listed", func() {
+			server.Close()
+
+			cmd := NewCmdVersion(factory, o)
+			cmd.SetArgs(append(args, "--check"))
+			Expect(cmd.Execute()).To(MatchError(ContainSubstring("failed to check for a newer release")))
+		})
+	})
+
 	It("should validate the options", func() {
 		Expect(o.Validate()).ToNot(HaveOccurred())
 	})
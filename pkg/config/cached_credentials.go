@@ -0,0 +1,107 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+const (
+	// CachedCredentialOIDCToken is the kind of the cached ID and refresh tokens of gardenctl login
+	CachedCredentialOIDCToken = "oidc-token"
+	// CachedCredentialKubeconfigExec is the kind of the cached output of a kubeconfigExec command
+	CachedCredentialKubeconfigExec = "kubeconfig-exec"
+)
+
+// CachedCredential is a file in the gardenctl home directory that holds cached credentials of a garden
+type CachedCredential struct {
+	// Garden is the identity of the garden
+	Garden string `json:"garden"`
+	// Kind is either CachedCredentialOIDCToken or CachedCredentialKubeconfigExec
+	Kind string `json:"kind"`
+	// Filename is the name of the file the credentials are cached in
+	Filename string `json:"filename"`
+	// Expiry is the time the cached credentials expire
+	Expiry time.Time `json:"expiry"`
+	// Refreshable is true if the credentials can be renewed without user interaction once they expired
+	Refreshable bool `json:"refreshable"`
+}
+
+// Expired returns true if the cached credentials have expired at the given time
+func (c CachedCredential) Expired(now time.Time) bool {
+	return !now.Before(c.Expiry)
+}
+
+// CachedCredentials returns the credentials of the garden that are cached in the token and cache directories.
+// Credentials that are cached for a previous configuration of the garden, e.g. a different kubeconfig command,
+// are not returned.
+func (config *Config) CachedCredentials(name string) ([]CachedCredential, error) {
+	garden, err := config.Garden(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var credentials []CachedCredential
+
+	// only gardens with an OIDC configuration have tokens, the key of the token may require to load the kubeconfig
+	if config.TokenDir != "" && garden.OIDC != nil {
+		key, err := config.TokenCacheKey(garden.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		token, err := config.TokenCache().Load(key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load token of garden %s: %w", garden.Name, err)
+		}
+
+		if token != nil {
+			credentials = append(credentials, CachedCredential{
+				Garden:      garden.Name,
+				Kind:        CachedCredentialOIDCToken,
+				Filename:    config.TokenCache().Filename(key),
+				Expiry:      token.Expiry,
+				Refreshable: token.RefreshToken != "",
+			})
+		}
+	}
+
+	if config.CacheDir != "" && garden.KubeconfigExec != nil {
+		ttl, _ := garden.KubeconfigExec.cacheTTL()
+		filename := garden.KubeconfigExec.cacheFile(config.CacheDir, garden.Name)
+
+		if stat, err := os.Stat(filename); err == nil {
+			credentials = append(credentials, CachedCredential{
+				Garden:      garden.Name,
+				Kind:        CachedCredentialKubeconfigExec,
+				Filename:    filename,
+				Expiry:      stat.ModTime().Add(ttl),
+				Refreshable: true,
+			})
+		}
+	}
+
+	return credentials, nil
+}
+
+// PurgeCachedCredentials removes the cached credentials of the garden and returns the removed credentials
+func (config *Config) PurgeCachedCredentials(name string) ([]CachedCredential, error) {
+	credentials, err := config.CachedCredentials(name)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range credentials {
+		if err := os.Remove(c.Filename); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to remove cached credentials of garden %s: %w", c.Garden, err)
+		}
+	}
+
+	return credentials, nil
+}
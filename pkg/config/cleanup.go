@@ -0,0 +1,69 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultCleanupRetention is the duration unused files are kept if no retention is configured
+const DefaultCleanupRetention = 7 * 24 * time.Hour
+
+// Cleanup configures the removal of the files that gardenctl writes, e.g. kubeconfigs, session files,
+// cached credentials and cloud provider CLI configurations
+type Cleanup struct {
+	// Retention is the duration after which files that have not been modified are removed, e.g. 72h. Defaults to 168h
+	// +optional
+	Retention string `yaml:"retention,omitempty" json:"retention,omitempty" toml:"retention,omitempty"`
+	// Automatic removes the files opportunistically when gardenctl runs, at most once per hour. Defaults to true,
+	// gardenctl cleanup removes the files regardless of this setting
+	// +optional
+	Automatic *bool `yaml:"automatic,omitempty" json:"automatic,omitempty" toml:"automatic,omitempty"`
+}
+
+// Validate checks that the retention is a valid duration
+func (c *Cleanup) Validate() error {
+	_, err := c.retention()
+	return err
+}
+
+func (c *Cleanup) retention() (time.Duration, error) {
+	if c == nil || c.Retention == "" {
+		return DefaultCleanupRetention, nil
+	}
+
+	retention, err := time.ParseDuration(c.Retention)
+	if err != nil {
+		return 0, fmt.Errorf("retention %q is not a valid duration: %w", c.Retention, err)
+	}
+
+	if retention <= 0 {
+		return 0, fmt.Errorf("retention %q must be positive", c.Retention)
+	}
+
+	return retention, nil
+}
+
+// CleanupRetention returns the duration after which unmodified files are removed. The default is used if the retention is invalid.
+func (config *Config) CleanupRetention() time.Duration {
+	if config == nil {
+		return DefaultCleanupRetention
+	}
+
+	retention, err := config.Cleanup.retention()
+	if err != nil {
+		return DefaultCleanupRetention
+	}
+
+	return retention
+}
+
+// AutomaticCleanup returns true if unmodified files are removed opportunistically when gardenctl runs
+func (config *Config) AutomaticCleanup() bool {
+	return config == nil || config.Cleanup == nil || config.Cleanup.Automatic == nil || *config.Cleanup.Automatic
+}
@@ -0,0 +1,186 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultClientQPS is the maximum number of requests per second to a cluster if no QPS is configured
+	DefaultClientQPS float32 = 20
+	// DefaultClientBurst is the maximum burst of requests to a cluster if no burst is configured
+	DefaultClientBurst = 30
+	// DefaultClientRetries is the number of retries of a request that failed with a transient error if no retries are configured
+	DefaultClientRetries = 3
+	// DefaultClientRetryBackoff is the delay before the first retry if no retry backoff is configured
+	DefaultClientRetryBackoff = 500 * time.Millisecond
+	// DefaultClientDialTimeout is the maximum duration to establish a connection to a cluster if no dial timeout is configured
+	DefaultClientDialTimeout = 5 * time.Second
+)
+
+// ClientPolicy configures how gardenctl talks to the API servers of the garden, seed and shoot clusters
+type ClientPolicy struct {
+	// QPS is the maximum number of requests per second to a cluster. Defaults to 20
+	// +optional
+	QPS *float32 `yaml:"qps,omitempty" json:"qps,omitempty" toml:"qps,omitempty"`
+	// Burst is the maximum number of requests that may exceed the QPS for a short time. Defaults to 30
+	// +optional
+	Burst *int `yaml:"burst,omitempty" json:"burst,omitempty" toml:"burst,omitempty"`
+	// Retries is the number of times a request that failed with a transient error, e.g. because the
+	// API server is throttling or temporarily unavailable, is retried. Defaults to 3, 0 disables retries
+	// +optional
+	Retries *int `yaml:"retries,omitempty" json:"retries,omitempty" toml:"retries,omitempty"`
+	// RetryBackoff is the delay before the first retry, e.g. 1s. The delay is doubled for every further retry. Defaults to 500ms
+	// +optional
+	RetryBackoff string `yaml:"retryBackoff,omitempty" json:"retryBackoff,omitempty" toml:"retryBackoff,omitempty"`
+	// DialTimeout is the maximum duration to establish a connection to a cluster, e.g. 10s. If a cluster cannot be
+	// reached within this duration, e.g. because a VPN connection is missing, the command fails. Defaults to 5s
+	// +optional
+	DialTimeout string `yaml:"dialTimeout,omitempty" json:"dialTimeout,omitempty" toml:"dialTimeout,omitempty"`
+}
+
+// Validate checks that all values of the client policy are in their valid range
+func (p *ClientPolicy) Validate() error {
+	if p == nil {
+		return nil
+	}
+
+	if p.QPS != nil && *p.QPS <= 0 {
+		return errors.New("qps must be greater than 0")
+	}
+
+	if p.Burst != nil && *p.Burst < 1 {
+		return errors.New("burst must be at least 1")
+	}
+
+	if p.Retries != nil && *p.Retries < 0 {
+		return errors.New("retries must not be negative")
+	}
+
+	if _, err := p.retryBackoff(); err != nil {
+		return err
+	}
+
+	_, err := p.dialTimeout()
+
+	return err
+}
+
+// GetQPS returns the configured QPS or DefaultClientQPS
+func (p *ClientPolicy) GetQPS() float32 {
+	if p == nil || p.QPS == nil || *p.QPS <= 0 {
+		return DefaultClientQPS
+	}
+
+	return *p.QPS
+}
+
+// GetBurst returns the configured burst or DefaultClientBurst
+func (p *ClientPolicy) GetBurst() int {
+	if p == nil || p.Burst == nil || *p.Burst < 1 {
+		return DefaultClientBurst
+	}
+
+	return *p.Burst
+}
+
+// GetRetries returns the configured number of retries or DefaultClientRetries
+func (p *ClientPolicy) GetRetries() int {
+	if p == nil || p.Retries == nil || *p.Retries < 0 {
+		return DefaultClientRetries
+	}
+
+	return *p.Retries
+}
+
+// GetRetryBackoff returns the configured retry backoff or DefaultClientRetryBackoff if it is not configured or invalid
+func (p *ClientPolicy) GetRetryBackoff() time.Duration {
+	backoff, err := p.retryBackoff()
+	if err != nil {
+		return DefaultClientRetryBackoff
+	}
+
+	return backoff
+}
+
+func (p *ClientPolicy) retryBackoff() (time.Duration, error) {
+	if p == nil || p.RetryBackoff == "" {
+		return DefaultClientRetryBackoff, nil
+	}
+
+	backoff, err := time.ParseDuration(p.RetryBackoff)
+	if err != nil {
+		return 0, fmt.Errorf("retryBackoff %q is not a valid duration: %w", p.RetryBackoff, err)
+	}
+
+	if backoff < 0 {
+		return 0, fmt.Errorf("retryBackoff %q must not be negative", p.RetryBackoff)
+	}
+
+	return backoff, nil
+}
+
+// GetDialTimeout returns the configured dial timeout or DefaultClientDialTimeout if it is not configured or invalid
+func (p *ClientPolicy) GetDialTimeout() time.Duration {
+	timeout, err := p.dialTimeout()
+	if err != nil {
+		return DefaultClientDialTimeout
+	}
+
+	return timeout
+}
+
+func (p *ClientPolicy) dialTimeout() (time.Duration, error) {
+	if p == nil || p.DialTimeout == "" {
+		return DefaultClientDialTimeout, nil
+	}
+
+	timeout, err := time.ParseDuration(p.DialTimeout)
+	if err != nil {
+		return 0, fmt.Errorf("dialTimeout %q is not a valid duration: %w", p.DialTimeout, err)
+	}
+
+	if timeout <= 0 {
+		return 0, fmt.Errorf("dialTimeout %q must be positive", p.DialTimeout)
+	}
+
+	return timeout, nil
+}
+
+// WithOverrides returns a copy of the client policy in which all values that are set in
+// overrides, e.g. by command line flags, replace the configured values
+func (p *ClientPolicy) WithOverrides(overrides ClientPolicy) *ClientPolicy {
+	merged := ClientPolicy{}
+	if p != nil {
+		merged = *p
+	}
+
+	if overrides.QPS != nil {
+		merged.QPS = overrides.QPS
+	}
+
+	if overrides.Burst != nil {
+		merged.Burst = overrides.Burst
+	}
+
+	if overrides.Retries != nil {
+		merged.Retries = overrides.Retries
+	}
+
+	if overrides.RetryBackoff != "" {
+		merged.RetryBackoff = overrides.RetryBackoff
+	}
+
+	if overrides.DialTimeout != "" {
+		merged.DialTimeout = overrides.DialTimeout
+	}
+
+	return &merged
+}
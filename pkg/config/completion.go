@@ -0,0 +1,56 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultCompletionCacheTTL is the duration completion values are cached if no cache TTL is configured
+const DefaultCompletionCacheTTL = 30 * time.Second
+
+// Completion configures the shell completion of gardenctl
+type Completion struct {
+	// CacheTTL is the duration the project, seed, shoot and node names that are queried for shell completion
+	// are cached, e.g. 1m. Defaults to 30s, the names are not cached if it is 0s
+	// +optional
+	CacheTTL string `yaml:"cacheTTL,omitempty" json:"cacheTTL,omitempty" toml:"cacheTTL,omitempty"`
+}
+
+// Validate checks that the cache TTL is a valid duration
+func (c *Completion) Validate() error {
+	_, err := c.cacheTTL()
+	return err
+}
+
+func (c *Completion) cacheTTL() (time.Duration, error) {
+	if c == nil || c.CacheTTL == "" {
+		return DefaultCompletionCacheTTL, nil
+	}
+
+	ttl, err := time.ParseDuration(c.CacheTTL)
+	if err != nil {
+		return 0, fmt.Errorf("cacheTTL %q is not a valid duration: %w", c.CacheTTL, err)
+	}
+
+	if ttl < 0 {
+		return 0, fmt.Errorf("cacheTTL %q must not be negative", c.CacheTTL)
+	}
+
+	return ttl, nil
+}
+
+// CompletionCacheTTL returns the duration completion values are cached. An invalid cache TTL disables the cache.
+func (config *Config) CompletionCacheTTL() time.Duration {
+	ttl, err := config.Completion.cacheTTL()
+	if err != nil {
+		return 0
+	}
+
+	return ttl
+}
@@ -0,0 +1,103 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"bytes"
+	"reflect"
+)
+
+// mergeConcurrentChanges returns the content that is written to the primary config file. It must be called while
+// the exclusive lock of the file is held. If the file has been changed since it has been loaded, e.g. by another
+// gardenctl process, the changes of this configuration (ours) are applied to the current file (theirs), see mergeChanges.
+func (config *Config) mergeConcurrentChanges(data []byte) ([]byte, error) {
+	if config.loaded == nil {
+		return data, nil
+	}
+
+	current, err := readDecryptedFile(config.Filename)
+	if err != nil {
+		return nil, err
+	}
+
+	if bytes.Equal(current, config.loaded) {
+		return data, nil
+	}
+
+	format := FormatFromFilename(config.Filename)
+	base, ours, theirs := &Config{}, &Config{}, &Config{}
+
+	for _, c := range []struct {
+		data   []byte
+		config *Config
+	}{{config.loaded, base}, {data, ours}, {current, theirs}} {
+		if len(c.data) == 0 {
+			continue
+		}
+
+		if err := format.unmarshal(c.data, c.config); err != nil {
+			return nil, formatError("decode", config.Filename, err)
+		}
+	}
+
+	data, err = format.marshal(mergeChanges(base, ours, theirs))
+	if err != nil {
+		return nil, formatError("encode", config.Filename, err)
+	}
+
+	return data, nil
+}
+
+// mergeChanges applies the changes from base to ours to theirs. The fields of the configuration that have been
+// changed in ours replace the fields of theirs. Gardens are merged by identity, so that gardens that have been added,
+// changed or deleted in ours are added, replaced or deleted in theirs and the other gardens of theirs are kept.
+func mergeChanges(base, ours, theirs *Config) *Config {
+	merged := *theirs
+
+	mergedValue := reflect.ValueOf(&merged).Elem()
+	baseValue := reflect.ValueOf(base).Elem()
+	oursValue := reflect.ValueOf(ours).Elem()
+
+	for i := 0; i < mergedValue.NumField(); i++ {
+		field := mergedValue.Type().Field(i)
+		if field.PkgPath != "" || field.Name == "Gardens" {
+			continue
+		}
+
+		if !reflect.DeepEqual(oursValue.Field(i).Interface(), baseValue.Field(i).Interface()) {
+			mergedValue.Field(i).Set(oursValue.Field(i))
+		}
+	}
+
+	merged.Gardens = append([]Garden{}, theirs.Gardens...)
+
+	for _, g := range base.Gardens {
+		if _, ok := indexOfGarden(ours.Gardens, g.Name); ok {
+			continue
+		}
+
+		// the garden has been deleted or renamed
+		if i, ok := indexOfGarden(merged.Gardens, g.Name); ok {
+			merged.Gardens = append(merged.Gardens[:i], merged.Gardens[i+1:]...)
+		}
+	}
+
+	for _, g := range ours.Gardens {
+		if i, ok := indexOfGarden(base.Gardens, g.Name); ok && reflect.DeepEqual(base.Gardens[i], g) {
+			continue
+		}
+
+		// the garden has been added or changed
+		if i, ok := indexOfGarden(merged.Gardens, g.Name); ok {
+			merged.Gardens[i] = g
+		} else {
+			merged.Gardens = append(merged.Gardens, g)
+		}
+	}
+
+	return &merged
+}
@@ -6,79 +6,238 @@ SPDX-License-Identifier: Apache-2.0
 package config
 
 import (
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"os"
+	"reflect"
 	"regexp"
 	"strconv"
+	"strings"
 
 	"github.com/mitchellh/go-homedir"
-	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/tools/clientcmd"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/gardener/gardenctl-v2/internal/audit"
+	"github.com/gardener/gardenctl-v2/internal/filelock"
 )
 
 // Config holds the gardenctl configuration
 type Config struct {
 	// Filename is the name of the gardenctl configuration file
-	Filename string `yaml:"-" json:"-"`
+	Filename string `yaml:"-" json:"-" toml:"-"`
+	// CacheDir is the directory where the output of kubeconfigExec commands is cached, caching is disabled if empty
+	CacheDir string `yaml:"-" json:"-" toml:"-"`
+	// TokenDir is the directory where the tokens of gardenctl login are cached
+	TokenDir string `yaml:"-" json:"-" toml:"-"`
+	// DryRun enables the dry-run mode, the changes to the configuration file and the target are only printed
+	DryRun *DryRun `yaml:"-" json:"-" toml:"-"`
+	// Offline is true if gardenctl must not connect to any cluster, commands use the cached data instead
+	Offline bool `yaml:"-" json:"-" toml:"-"`
+	// Audit records the changes to the configuration file and the target, nothing is recorded if nil
+	Audit *audit.Logger `yaml:"-" json:"-" toml:"-"`
+	// Confirmation asks for the confirmation of changes to protected gardens, they are denied if nil
+	Confirmation *Confirmation `yaml:"-" json:"-" toml:"-"`
 	// LinkKubeconfig defines if kubeconfig is symlinked with the target
-	LinkKubeconfig *bool `yaml:"linkKubeconfig,omitempty" json:"linkKubeconfig,omitempty"`
+	LinkKubeconfig *bool `yaml:"linkKubeconfig,omitempty" json:"linkKubeconfig,omitempty" toml:"linkKubeconfig,omitempty"`
+	// Backups is the number of backup files (.bak, .bak.1, ...) that are kept when the configuration file is saved
+	// +optional
+	Backups int `yaml:"backups,omitempty" json:"backups,omitempty" toml:"backups,omitempty"`
+	// Encryption configures the encryption of the gardenctl configuration file at rest
+	// +optional
+	Encryption *Encryption `yaml:"encryption,omitempty" json:"encryption,omitempty" toml:"encryption,omitempty"`
+	// DefaultGarden is the identity or alias of the garden that is used if a target requires a garden but no garden is targeted
+	// +optional
+	DefaultGarden string `yaml:"defaultGarden,omitempty" json:"defaultGarden,omitempty" toml:"defaultGarden,omitempty"`
 	// Gardens is a list of known Garden clusters
-	Gardens []Garden `yaml:"gardens" json:"gardens"`
+	Gardens []Garden `yaml:"gardens" json:"gardens" toml:"gardens"`
+	// MatchPatterns is a list of regex patterns that are used for targeting if none of the garden scoped patterns matches.
+	// In addition to the capturing groups supported for garden patterns, the garden capturing group can be used
+	// to match the garden identity or alias. If it is not used, the currently targeted garden is used.
+	// +optional
+	MatchPatterns []string `yaml:"matchPatterns,omitempty" json:"matchPatterns,omitempty" toml:"matchPatterns,omitempty"`
+	// PatternPresets is a list of names of built-in match patterns, e.g. dashboard or shorthand, see PatternPresetNames.
+	// The patterns of the presets are used after the MatchPatterns.
+	// +optional
+	PatternPresets []string `yaml:"patternPresets,omitempty" json:"patternPresets,omitempty" toml:"patternPresets,omitempty"`
+	// SyncSources is a list of centrally managed configuration files that are fetched with gardenctl config sync
+	// +optional
+	SyncSources []SyncSource `yaml:"syncSources,omitempty" json:"syncSources,omitempty" toml:"syncSources,omitempty"`
+	// Bastion configures the defaults for bastions created by gardenctl ssh
+	// +optional
+	Bastion *Bastion `yaml:"bastion,omitempty" json:"bastion,omitempty" toml:"bastion,omitempty"`
+	// Completion configures the shell completion of gardenctl
+	// +optional
+	Completion *Completion `yaml:"completion,omitempty" json:"completion,omitempty" toml:"completion,omitempty"`
+	// ClientPolicy configures the rate limiting and retries of the requests to the garden, seed and shoot clusters
+	// +optional
+	ClientPolicy *ClientPolicy `yaml:"clientPolicy,omitempty" json:"clientPolicy,omitempty" toml:"clientPolicy,omitempty"`
+	// CredentialPlugins is a list of exec credential plugins that provide the credentials of the gardens that refer to them
+	// +optional
+	CredentialPlugins []CredentialPlugin `yaml:"credentialPlugins,omitempty" json:"credentialPlugins,omitempty" toml:"credentialPlugins,omitempty"`
+	// Security configures how gardenctl handles credentials, e.g. that kubeconfigs are not written to disk
+	// +optional
+	Security *Security `yaml:"security,omitempty" json:"security,omitempty" toml:"security,omitempty"`
+	// Directories overrides the directories in which gardenctl stores its state, e.g. the cache and the session directories
+	// +optional
+	Directories *Directories `yaml:"directories,omitempty" json:"directories,omitempty" toml:"directories,omitempty"`
+	// Cleanup configures the removal of unused kubeconfigs, session files and cached credentials
+	// +optional
+	Cleanup *Cleanup `yaml:"cleanup,omitempty" json:"cleanup,omitempty" toml:"cleanup,omitempty"`
+	// Templates configures the library of shoot templates that is used by gardenctl shoot create
+	// +optional
+	Templates *Templates `yaml:"templates,omitempty" json:"templates,omitempty" toml:"templates,omitempty"`
+	// Logging configures the audit log of the mutating actions of gardenctl
+	// +optional
+	Logging *Logging `yaml:"logging,omitempty" json:"logging,omitempty" toml:"logging,omitempty"`
+	// inherited holds the merged gardens of all additional config sources
+	inherited []Garden
+	// own holds the gardens as they are defined in the primary config file, only their fields and the changes to
+	// the inherited gardens are written when the configuration is saved
+	own []Garden
+	// inheritedDefaultGarden is the default garden taken from an additional config source
+	inheritedDefaultGarden string
+	// inheritedLinkKubeconfig is the linkKubeconfig setting taken from an additional config source
+	inheritedLinkKubeconfig *bool
+	// inheritedMatchPatterns holds the merged match patterns of all additional config sources
+	inheritedMatchPatterns []string
+	// inheritedCredentialPlugins holds the merged credential plugins of all additional config sources
+	inheritedCredentialPlugins []CredentialPlugin
+	// rawKubeconfigs maps garden identities to the kubeconfig paths before expansion
+	rawKubeconfigs map[string]string
+	// loaded is the decrypted content of the primary config file when it has been loaded or saved, it is nil
+	// if the configuration has not been loaded from a file
+	loaded []byte
 }
 
 // Garden represents one garden cluster
 type Garden struct {
 	// Identity is a unique identifier of this Garden that can be used to target this Garden
-	Name string `yaml:"identity" json:"identity"`
-	// Kubeconfig holds the path for the kubeconfig of the garden cluster
-	Kubeconfig string `yaml:"kubeconfig" json:"kubeconfig"`
+	Name string `yaml:"identity" json:"identity" toml:"identity"`
+	// ClusterIdentity is the cluster identity of the garden cluster, i.e. the value of the cluster-identity ConfigMap in the
+	// kube-system namespace. Unlike the identity of the garden, it does not change if the garden is renamed. The OIDC tokens
+	// of the garden are cached by it and gardens that are configured more than once are detected without looking it up.
+	// +optional
+	ClusterIdentity string `yaml:"clusterIdentity,omitempty" json:"clusterIdentity,omitempty" toml:"clusterIdentity,omitempty"`
+	// Kubeconfig holds the path for the kubeconfig of the garden cluster.
+	// A leading ~ and environment variables in the form $VAR or ${VAR} are expanded, use $$ for a literal $.
+	Kubeconfig string `yaml:"kubeconfig" json:"kubeconfig" toml:"kubeconfig"`
+	// KubeconfigData holds the kubeconfig of the garden cluster inline, either as raw YAML or base64 encoded.
+	// It can be used instead of Kubeconfig to distribute a self-contained configuration.
+	// +optional
+	KubeconfigData string `yaml:"kubeconfigData,omitempty" json:"kubeconfigData,omitempty" toml:"kubeconfigData,omitempty"`
+	// KubeconfigExec configures a command that prints the kubeconfig of the garden cluster,
+	// it can be used instead of Kubeconfig to fetch the kubeconfig at runtime
+	// +optional
+	KubeconfigExec *KubeconfigExec `yaml:"kubeconfigExec,omitempty" json:"kubeconfigExec,omitempty" toml:"kubeconfigExec,omitempty"`
 	// Context overrides the current-context of the garden cluster kubeconfig
 	// +optional
-	Context string `yaml:"context,omitempty" json:"context,omitempty"`
+	Context string `yaml:"context,omitempty" json:"context,omitempty" toml:"context,omitempty"`
+	// Aliases is a list of alternative names that can be used to target this Garden
+	// +optional
+	Aliases []string `yaml:"aliases,omitempty" json:"aliases,omitempty" toml:"aliases,omitempty"`
 	// Patterns is a list of regex patterns that can be defined to use custom input formats for targeting
 	// Use named capturing groups to match target values.
-	// Supported capturing groups: project, namespace, shoot
+	// Supported capturing groups: project, namespace, seed, shoot
+	// +optional
+	Patterns []string `yaml:"patterns,omitempty" json:"patterns,omitempty" toml:"patterns,omitempty"`
+	// Labels are arbitrary key value pairs that can be used to group and select gardens, e.g. env=prod
+	// +optional
+	Labels map[string]string `yaml:"labels,omitempty" json:"labels,omitempty" toml:"labels,omitempty"`
+	// CredentialPlugin is the name of a credential plugin of the gardenctl configuration that provides the
+	// credentials for the garden cluster instead of the user credentials of the kubeconfig
+	// +optional
+	CredentialPlugin string `yaml:"credentialPlugin,omitempty" json:"credentialPlugin,omitempty" toml:"credentialPlugin,omitempty"`
+	// OIDC configures the login to the garden cluster with gardenctl login. The cached ID token replaces the
+	// user credentials of the kubeconfig
+	// +optional
+	OIDC *OIDC `yaml:"oidc,omitempty" json:"oidc,omitempty" toml:"oidc,omitempty"`
+	// DashboardURL is the URL of the Gardener dashboard of the garden, e.g. https://dashboard.garden.example.com.
+	// If it is not set, it is derived from the API server URL of the garden by replacing its api subdomain with dashboard
+	// +optional
+	DashboardURL string `yaml:"dashboardURL,omitempty" json:"dashboardURL,omitempty" toml:"dashboardURL,omitempty"`
+	// Protected requires an interactive confirmation before gardenctl changes any resource of the garden, its seeds or
+	// its shoots, e.g. to protect production landscapes from accidental changes
+	// +optional
+	Protected bool `yaml:"protected,omitempty" json:"protected,omitempty" toml:"protected,omitempty"`
+	// DenyMutations denies all changes of gardenctl to the resources of the garden, its seeds and its shoots,
+	// the garden can only be inspected
 	// +optional
-	Patterns []string `yaml:"patterns,omitempty" json:"patterns,omitempty"`
+	DenyMutations bool `yaml:"denyMutations,omitempty" json:"denyMutations,omitempty" toml:"denyMutations,omitempty"`
+}
+
+// Bastion configures the defaults for bastions created by gardenctl ssh
+type Bastion struct {
+	// IngressCIDRs is a list of CIDRs that are allowed to access the bastion if no --cidr flag is given.
+	// If it is empty, the public IPs of the system are auto-detected.
+	// +optional
+	IngressCIDRs []string `yaml:"ingressCIDRs,omitempty" json:"ingressCIDRs,omitempty" toml:"ingressCIDRs,omitempty"`
 }
 
 // LoadFromFile parses a gardenctl config file and returns a Config struct
 func LoadFromFile(filename string) (*Config, error) {
-	config := &Config{Filename: filename}
+	return LoadFromFiles(filename)
+}
 
-	f, err := os.Open(filename)
+// LoadFromFiles parses the given gardenctl config files and merges them into a single Config struct.
+// The first file is the primary config file. It has the highest precedence and it is the only file
+// that is written when the configuration is saved. The additional sources are merged in descending
+// order of precedence, e.g. to include garden definitions that are shared within a team.
+// Gardens are merged by identity: fields that are not set are taken from the next source defining
+// the same garden, aliases and patterns are appended without duplicates. Gardens of the primary
+// config file come first, followed by gardens that are only defined in additional sources.
+// Match patterns of additional sources are appended to the match patterns of the primary config file.
+// The files synced from the sync sources of the primary config file are merged before the given sources.
+// Files that do not exist are skipped. The format of each file is determined by its extension,
+// see FormatFromFilename, and encrypted files are decrypted transparently.
+func LoadFromFiles(filename string, sources ...string) (*Config, error) {
+	config, data, err := readPrimaryFile(filename)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return config, nil
-		}
-
-		return nil, fmt.Errorf("failed to open file: %w", err)
+		return nil, err
 	}
-	defer f.Close()
 
-	stat, err := f.Stat()
-	if err != nil {
-		return nil, fmt.Errorf("failed to determine filesize: %w", err)
-	}
+	config.Filename = filename
+	config.loaded = append([]byte{}, data...)
+
+	inherited := []Garden{}
+
+	for _, source := range append(config.syncedFilenames(), sources...) {
+		c, err := readFile(source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load config source %s: %w", source, err)
+		}
 
-	if stat.Size() > 0 {
-		if err := yaml.NewDecoder(f).Decode(config); err != nil {
-			return nil, fmt.Errorf("failed to decode as YAML: %w", err)
+		if config.LinkKubeconfig == nil && c.LinkKubeconfig != nil {
+			config.LinkKubeconfig = c.LinkKubeconfig
+			config.inheritedLinkKubeconfig = c.LinkKubeconfig
 		}
 
-		// be nice and handle ~ in paths
-		for i, g := range config.Gardens {
-			expanded, err := homedir.Expand(g.Kubeconfig)
-			if err != nil {
-				return nil, fmt.Errorf("failed to resolve ~ in kubeconfig path: %w", err)
-			}
+		if config.DefaultGarden == "" && c.DefaultGarden != "" {
+			config.DefaultGarden = c.DefaultGarden
+			config.inheritedDefaultGarden = c.DefaultGarden
+		}
 
-			config.Gardens[i].Kubeconfig = expanded
+		inherited = mergeGardens(inherited, c.Gardens)
+		config.inheritedMatchPatterns = appendUnique(config.inheritedMatchPatterns, c.MatchPatterns...)
+		config.inheritedCredentialPlugins = mergeCredentialPlugins(config.inheritedCredentialPlugins, c.CredentialPlugins)
+
+		for name, raw := range c.rawKubeconfigs {
+			if _, ok := config.rawKubeconfigs[name]; !ok {
+				config.setRawKubeconfig(name, raw)
+			}
 		}
 	}
 
+	if len(inherited) > 0 {
+		config.own = mergeGardens(nil, config.Gardens)
+		config.Gardens = mergeGardens(config.Gardens, inherited)
+		config.inherited = inherited
+	}
+
+	config.MatchPatterns = appendUnique(config.MatchPatterns, config.inheritedMatchPatterns...)
+
 	// we don't want a dependency to root command here
 	str, ok := os.LookupEnv("GCTL_LINK_KUBECONFIG")
 	if ok {
@@ -93,30 +252,424 @@ func LoadFromFile(filename string) (*Config, error) {
 	return config, nil
 }
 
-// SymlinkTargetKubeconfig indicates if the kubeconfig of the current target should be always symlinked
+// readFile parses a single gardenctl config file. An empty Config is returned if the file does not exist.
+// The file is not locked, the additional config sources are never written by gardenctl or replaced atomically.
+func readFile(filename string) (*Config, error) {
+	data, err := readDecryptedFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseFile(filename, data)
+}
+
+// readPrimaryFile parses the primary config file while holding a shared lock and returns its decrypted content,
+// which is the base for merging concurrent changes when the configuration is saved.
+func readPrimaryFile(filename string) (*Config, []byte, error) {
+	unlock, err := filelock.RLock(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer unlock()
+
+	data, err := readDecryptedFile(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	config, err := parseFile(filename, data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return config, data, nil
+}
+
+// readDecryptedFile returns the decrypted content of a config file or nil if the file does not exist
+func readDecryptedFile(filename string) ([]byte, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+
+	if len(data) == 0 {
+		return data, nil
+	}
+
+	data, err = decrypt(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt file: %w", err)
+	}
+
+	return data, nil
+}
+
+// parseFile decodes the decrypted content of a config file and expands the kubeconfig paths of its gardens
+func parseFile(filename string, data []byte) (*Config, error) {
+	config := &Config{}
+
+	if len(data) == 0 {
+		return config, nil
+	}
+
+	if err := FormatFromFilename(filename).unmarshal(data, config); err != nil {
+		return nil, formatError("decode", filename, err)
+	}
+
+	// be nice and handle ~ and environment variables in paths
+	for i, g := range config.Gardens {
+		expanded, err := expandPath(g.Kubeconfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve ~ in kubeconfig path: %w", err)
+		}
+
+		if expanded != g.Kubeconfig {
+			config.setRawKubeconfig(g.Name, g.Kubeconfig)
+		}
+
+		config.Gardens[i].Kubeconfig = expanded
+	}
+
+	return config, nil
+}
+
+func (config *Config) setRawKubeconfig(name, raw string) {
+	if config.rawKubeconfigs == nil {
+		config.rawKubeconfigs = map[string]string{}
+	}
+
+	config.rawKubeconfigs[name] = raw
+}
+
+// expandPath expands a leading ~ and environment variables in the form $VAR or ${VAR}.
+// Unset environment variables are replaced by the empty string and $$ is replaced by a literal $.
+func expandPath(path string) (string, error) {
+	path = os.Expand(path, func(key string) string {
+		if key == "$" {
+			return "$"
+		}
+
+		return os.Getenv(key)
+	})
+
+	return homedir.Expand(path)
+}
+
+// mergeGardens merges the gardens of src into dst and returns the resulting slice.
+// Values already set in dst take precedence over the values of src.
+func mergeGardens(dst []Garden, src []Garden) []Garden {
+	for _, g := range src {
+		i, ok := indexOfGarden(dst, g.Name)
+		if !ok {
+			dst = append(dst, Garden{
+				Name:             g.Name,
+				ClusterIdentity:  g.ClusterIdentity,
+				Kubeconfig:       g.Kubeconfig,
+				KubeconfigData:   g.KubeconfigData,
+				KubeconfigExec:   g.KubeconfigExec.deepCopy(),
+				Context:          g.Context,
+				Aliases:          appendUnique(nil, g.Aliases...),
+				Patterns:         appendUnique(nil, g.Patterns...),
+				Labels:           mergeLabels(nil, g.Labels),
+				CredentialPlugin: g.CredentialPlugin,
+				OIDC:             g.OIDC.deepCopy(),
+				DashboardURL:     g.DashboardURL,
+				Protected:        g.Protected,
+				DenyMutations:    g.DenyMutations,
+			})
+
+			continue
+		}
+
+		if !dst[i].hasKubeconfig() {
+			dst[i].Kubeconfig = g.Kubeconfig
+			dst[i].KubeconfigData = g.KubeconfigData
+			dst[i].KubeconfigExec = g.KubeconfigExec.deepCopy()
+		}
+
+		if dst[i].ClusterIdentity == "" {
+			dst[i].ClusterIdentity = g.ClusterIdentity
+		}
+
+		if dst[i].Context == "" {
+			dst[i].Context = g.Context
+		}
+
+		if dst[i].CredentialPlugin == "" {
+			dst[i].CredentialPlugin = g.CredentialPlugin
+		}
+
+		if dst[i].OIDC == nil {
+			dst[i].OIDC = g.OIDC.deepCopy()
+		}
+
+		if dst[i].DashboardURL == "" {
+			dst[i].DashboardURL = g.DashboardURL
+		}
+
+		// a garden that is protected by any of the config sources stays protected
+		dst[i].Protected = dst[i].Protected || g.Protected
+		dst[i].DenyMutations = dst[i].DenyMutations || g.DenyMutations
+
+		dst[i].Aliases = appendUnique(dst[i].Aliases, g.Aliases...)
+		dst[i].Patterns = appendUnique(dst[i].Patterns, g.Patterns...)
+		dst[i].Labels = mergeLabels(dst[i].Labels, g.Labels)
+	}
+
+	return dst
+}
+
+// mergeLabels adds the labels of src to dst that are not already set in dst
+func mergeLabels(dst, src map[string]string) map[string]string {
+	for k, v := range src {
+		if _, ok := dst[k]; ok {
+			continue
+		}
+
+		if dst == nil {
+			dst = map[string]string{}
+		}
+
+		dst[k] = v
+	}
+
+	return dst
+}
+
+func appendUnique(list []string, values ...string) []string {
+	for _, v := range values {
+		if !contains(list, v) {
+			list = append(list, v)
+		}
+	}
+
+	return list
+}
+
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SymlinkTargetKubeconfig indicates if the kubeconfig of the current target should be always symlinked.
+// It is never symlinked if kubeconfigs must not be written to disk, see EphemeralKubeconfigs.
 func (config *Config) SymlinkTargetKubeconfig() bool {
+	if config.EphemeralKubeconfigs() {
+		return false
+	}
+
 	return config.LinkKubeconfig == nil || *config.LinkKubeconfig
 }
 
 // Save updates a gardenctl config file with the values passed via Config struct
+// The file format is determined by the extension of the filename, see FormatFromFilename.
+// If encryption is configured, the file is encrypted for the configured recipients.
+// The configuration is not saved if a pattern that is added to the file is invalid. The file is replaced atomically
+// and the previous content is kept in rotating backup files if Backups is set. If the file has been changed
+// by another process since the configuration has been loaded, the changes of both are merged.
+// In dry-run mode, the differences to the current file are printed instead.
+// If the audit log is enabled, the result is recorded in the audit log.
 func (config *Config) Save() error {
-	f, err := os.OpenFile(config.Filename, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	err := config.save()
+	config.audit(err)
+
+	return err
+}
+
+func (config *Config) save() error {
+	persistent := config.persistentConfig()
+
+	if err := config.validateAddedPatterns(persistent); err != nil {
+		return err
+	}
+
+	data, err := FormatFromFilename(config.Filename).marshal(persistent)
 	if err != nil {
-		return fmt.Errorf("failed to create file: %w", err)
+		return formatError("encode", config.Filename, err)
 	}
-	defer f.Close()
 
-	if err := yaml.NewEncoder(f).Encode(config); err != nil {
-		return fmt.Errorf("failed to encode as YAML: %w", err)
+	if config.IsDryRun() {
+		return config.printDiff(data)
 	}
 
+	// the lock is held while the current file is read again, so that the changes of concurrent gardenctl processes
+	// since the configuration has been loaded are merged instead of being overwritten
+	unlock, err := filelock.Lock(config.Filename)
+	if err != nil {
+		return fmt.Errorf("failed to lock file: %w", err)
+	}
+	defer unlock()
+
+	data, err = config.mergeConcurrentChanges(data)
+	if err != nil {
+		return err
+	}
+
+	plain := data
+
+	if config.Encryption != nil {
+		data, err = config.Encryption.encrypt(data)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt file: %w", err)
+		}
+	}
+
+	if err := rotateBackups(config.Filename, config.Backups); err != nil {
+		return fmt.Errorf("failed to create backup: %w", err)
+	}
+
+	if err := writeFileAtomic(config.Filename, data, 0600); err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+
+	config.loaded = plain
+
 	return nil
 }
 
+// persistentConfig returns the configuration that is written to the primary config file.
+// Only the fields that are owned by the primary config file are written: gardens that have been taken unchanged
+// from additional config sources are omitted and of the other inherited gardens only the fields that are defined in
+// the primary config file or that have been changed are written, see ownedFields. Kubeconfig paths that have not
+// been modified are written as they have been read, so that ~ and environment variables are preserved.
+func (config *Config) persistentConfig() *Config {
+	c := *config
+	c.Gardens = []Garden{}
+	c.MatchPatterns = nil
+
+	for _, p := range config.MatchPatterns {
+		if !contains(config.inheritedMatchPatterns, p) {
+			c.MatchPatterns = append(c.MatchPatterns, p)
+		}
+	}
+
+	if config.inheritedDefaultGarden != "" && c.DefaultGarden == config.inheritedDefaultGarden {
+		c.DefaultGarden = ""
+	}
+
+	if config.inheritedLinkKubeconfig != nil && c.LinkKubeconfig == config.inheritedLinkKubeconfig {
+		c.LinkKubeconfig = nil
+	}
+
+	for _, g := range config.Gardens {
+		if i, ok := indexOfGarden(config.inherited, g.Name); ok {
+			var own *Garden
+			if j, ok := indexOfGarden(config.own, g.Name); ok {
+				own = &config.own[j]
+			}
+
+			if g, ok = ownedFields(g, config.inherited[i], own); !ok {
+				continue
+			}
+		}
+
+		if raw, ok := config.rawKubeconfigs[g.Name]; ok {
+			if expanded, err := expandPath(raw); err == nil && expanded == g.Kubeconfig {
+				g.Kubeconfig = raw
+			}
+		}
+
+		c.Gardens = append(c.Gardens, g)
+	}
+
+	return &c
+}
+
+// ownedFields returns the fields of the merged garden g that are owned by the primary config file, i.e. the fields
+// that are defined for the garden in the primary config file (own, nil if the garden is only defined by additional
+// config sources) or that differ from the inherited garden. It returns false if the primary config file does not
+// own any field of the garden.
+func ownedFields(g, inherited Garden, own *Garden) (Garden, bool) {
+	owned := own != nil
+	if own == nil {
+		own = &Garden{}
+	}
+
+	result := Garden{Name: g.Name}
+
+	if own.hasKubeconfig() ||
+		g.Kubeconfig != inherited.Kubeconfig ||
+		g.KubeconfigData != inherited.KubeconfigData ||
+		!reflect.DeepEqual(g.KubeconfigExec, inherited.KubeconfigExec) {
+		result.Kubeconfig = g.Kubeconfig
+		result.KubeconfigData = g.KubeconfigData
+		result.KubeconfigExec = g.KubeconfigExec
+	}
+
+	if own.ClusterIdentity != "" || g.ClusterIdentity != inherited.ClusterIdentity {
+		result.ClusterIdentity = g.ClusterIdentity
+	}
+
+	if own.Context != "" || g.Context != inherited.Context {
+		result.Context = g.Context
+	}
+
+	if own.CredentialPlugin != "" || g.CredentialPlugin != inherited.CredentialPlugin {
+		result.CredentialPlugin = g.CredentialPlugin
+	}
+
+	if own.OIDC != nil || !reflect.DeepEqual(g.OIDC, inherited.OIDC) {
+		result.OIDC = g.OIDC
+	}
+
+	if own.DashboardURL != "" || g.DashboardURL != inherited.DashboardURL {
+		result.DashboardURL = g.DashboardURL
+	}
+
+	// the protection of a config source cannot be lifted, only the settings of the primary config file are written
+	result.Protected = own.Protected || (g.Protected && !inherited.Protected)
+	result.DenyMutations = own.DenyMutations || (g.DenyMutations && !inherited.DenyMutations)
+
+	result.Aliases = ownedValues(g.Aliases, inherited.Aliases, own.Aliases)
+	result.Patterns = ownedValues(g.Patterns, inherited.Patterns, own.Patterns)
+
+	for k, v := range g.Labels {
+		if inheritedValue, ok := inherited.Labels[k]; ok && inheritedValue == v {
+			if _, ok := own.Labels[k]; !ok {
+				continue
+			}
+		}
+
+		if result.Labels == nil {
+			result.Labels = map[string]string{}
+		}
+
+		result.Labels[k] = v
+	}
+
+	return result, owned || !reflect.DeepEqual(result, Garden{Name: g.Name})
+}
+
+// ownedValues returns the values that are defined by the primary config file or that are not inherited
+func ownedValues(values, inherited, own []string) []string {
+	var result []string
+
+	for _, v := range values {
+		if contains(own, v) || !contains(inherited, v) {
+			result = append(result, v)
+		}
+	}
+
+	return result
+}
+
 // IndexOfGarden returns the index of the Garden with the given name in the configured Gardens slice
 // If no Garden with this name is found it returns -1
 func (config *Config) IndexOfGarden(name string) (int, bool) {
-	for i, g := range config.Gardens {
+	return indexOfGarden(config.Gardens, name)
+}
+
+func indexOfGarden(gardens []Garden, name string) (int, bool) {
+	for i, g := range gardens {
 		if g.Name == name {
 			return i, true
 		}
@@ -135,16 +688,265 @@ func (config *Config) GardenNames() []string {
 	return names
 }
 
+// SelectGardens returns the gardens whose labels match the given label selector, e.g. env=prod or env in (prod,canary)
+func (config *Config) SelectGardens(selector string) ([]Garden, error) {
+	s, err := labels.Parse(selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid label selector %q: %w", selector, err)
+	}
+
+	gardens := []Garden{}
+
+	for _, g := range config.Gardens {
+		if s.Matches(labels.Set(g.Labels)) {
+			gardens = append(gardens, g)
+		}
+	}
+
+	return gardens, nil
+}
+
+// IndexOfGardenAlias returns the index of the Garden that has the given alias in the configured Gardens slice
+// If no Garden with this alias is found it returns -1
+func (config *Config) IndexOfGardenAlias(alias string) (int, bool) {
+	for i, g := range config.Gardens {
+		for _, a := range g.Aliases {
+			if a == alias {
+				return i, true
+			}
+		}
+	}
+
+	return -1, false
+}
+
 // Garden returns a Garden cluster from the list of configured Gardens
+// The name can either be the identity or an alias of the Garden
 func (config *Config) Garden(name string) (*Garden, error) {
 	i, ok := config.IndexOfGarden(name)
 	if !ok {
-		return nil, fmt.Errorf("garden %q is not defined in gardenctl configuration", name)
+		i, ok = config.IndexOfGardenAlias(name)
+	}
+
+	if !ok {
+		return nil, &GardenNotFoundError{Name: name}
 	}
 
 	return &config.Gardens[i], nil
 }
 
+// GardenNotFoundError is returned by Garden if neither the identity nor an alias of a Garden matches the name
+type GardenNotFoundError struct {
+	// Name is the requested identity or alias
+	Name string
+}
+
+var _ error = &GardenNotFoundError{}
+
+func (e *GardenNotFoundError) Error() string {
+	return fmt.Sprintf("garden %q is not defined in gardenctl configuration", e.Name)
+}
+
+// IsGardenNotFound returns true if the error has been caused by a garden that is not defined in the gardenctl configuration
+func IsGardenNotFound(err error) bool {
+	var notFoundErr *GardenNotFoundError
+
+	return errors.As(err, &notFoundErr)
+}
+
+// DeleteGarden removes the Garden with the given name or alias and returns its identity.
+// The default garden is cleared if it refers to the deleted Garden.
+// Gardens that are defined by an additional config source cannot be deleted.
+func (config *Config) DeleteGarden(name string) (string, error) {
+	garden, err := config.Garden(name)
+	if err != nil {
+		return "", err
+	}
+
+	identity := garden.Name
+
+	if err := config.checkNotInherited(identity, "deleted"); err != nil {
+		return "", err
+	}
+
+	if d, err := config.Garden(config.DefaultGarden); err == nil && d.Name == identity {
+		config.DefaultGarden = ""
+	}
+
+	i, _ := config.IndexOfGarden(identity)
+	config.Gardens = append(config.Gardens[:i], config.Gardens[i+1:]...)
+
+	return identity, nil
+}
+
+// ErrGardenExists is returned by AddGarden if a garden with the same identity is already defined
+var ErrGardenExists = errors.New("garden is already defined in gardenctl configuration")
+
+// AddGarden adds the given Garden to the configured Gardens.
+// It returns an error that wraps ErrGardenExists if a Garden with the same identity is already defined.
+func (config *Config) AddGarden(garden Garden) error {
+	if _, ok := config.IndexOfGarden(garden.Name); ok {
+		return fmt.Errorf("failed to add garden %q: %w", garden.Name, ErrGardenExists)
+	}
+
+	if i, ok := config.IndexOfGardenAlias(garden.Name); ok {
+		return fmt.Errorf("failed to add garden %q: it is already an alias of garden %q", garden.Name, config.Gardens[i].Name)
+	}
+
+	config.Gardens = append(config.Gardens, garden)
+
+	return nil
+}
+
+// UpdateGarden updates the configured Garden with the identity of the given Garden.
+// The kubeconfig and the context are replaced if they are set, aliases and patterns are appended without duplicates.
+func (config *Config) UpdateGarden(garden Garden) error {
+	i, ok := config.IndexOfGarden(garden.Name)
+	if !ok {
+		return fmt.Errorf("garden %q is not defined in gardenctl configuration", garden.Name)
+	}
+
+	g := &config.Gardens[i]
+
+	if garden.Kubeconfig != "" {
+		g.Kubeconfig = garden.Kubeconfig
+		g.KubeconfigData = ""
+		g.KubeconfigExec = nil
+
+		delete(config.rawKubeconfigs, g.Name)
+	}
+
+	if garden.Context != "" {
+		g.Context = garden.Context
+	}
+
+	g.Aliases = appendUnique(g.Aliases, garden.Aliases...)
+	g.Patterns = appendUnique(g.Patterns, garden.Patterns...)
+
+	return nil
+}
+
+// AddGardenAlias adds the alias to the Garden with the given name or alias.
+// It fails if the alias is the identity or an alias of another Garden, as it must not resolve to two gardens.
+func (config *Config) AddGardenAlias(name, alias string) error {
+	garden, err := config.Garden(name)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := config.IndexOfGarden(alias); ok {
+		return fmt.Errorf("failed to add alias %q: it is the identity of garden %q", alias, alias)
+	}
+
+	if i, ok := config.IndexOfGardenAlias(alias); ok {
+		if config.Gardens[i].Name == garden.Name {
+			return nil
+		}
+
+		return fmt.Errorf("failed to add alias %q: it is already an alias of garden %q", alias, config.Gardens[i].Name)
+	}
+
+	garden.Aliases = append(garden.Aliases, alias)
+
+	return nil
+}
+
+// RemoveGardenAlias removes the alias from the Garden with the given name or alias.
+// The default garden is set to the identity of the Garden if it refers to the removed alias.
+func (config *Config) RemoveGardenAlias(name, alias string) error {
+	garden, err := config.Garden(name)
+	if err != nil {
+		return err
+	}
+
+	aliases := []string{}
+
+	for _, a := range garden.Aliases {
+		if a != alias {
+			aliases = append(aliases, a)
+		}
+	}
+
+	if len(aliases) == len(garden.Aliases) {
+		return fmt.Errorf("%q is not an alias of garden %q", alias, garden.Name)
+	}
+
+	if len(aliases) == 0 {
+		aliases = nil
+	}
+
+	garden.Aliases = aliases
+
+	if config.DefaultGarden == alias {
+		config.DefaultGarden = garden.Name
+	}
+
+	return nil
+}
+
+// RenameGarden changes the identity of the Garden with the given name or alias.
+// If keepAlias is true, the previous identity is added to the aliases of the Garden.
+// Gardens that are defined by an additional config source cannot be renamed.
+func (config *Config) RenameGarden(name, newName string, keepAlias bool) error {
+	garden, err := config.Garden(name)
+	if err != nil {
+		return err
+	}
+
+	if err := config.checkNotInherited(garden.Name, "renamed"); err != nil {
+		return err
+	}
+
+	if _, ok := config.IndexOfGarden(newName); ok {
+		return fmt.Errorf("garden %q is already defined in gardenctl configuration", newName)
+	}
+
+	if i, ok := config.IndexOfGardenAlias(newName); ok && config.Gardens[i].Name != garden.Name {
+		return fmt.Errorf("garden %q is already defined in gardenctl configuration", newName)
+	}
+
+	oldName := garden.Name
+	aliases := []string{}
+
+	for _, a := range garden.Aliases {
+		if a != newName {
+			aliases = append(aliases, a)
+		}
+	}
+
+	if keepAlias {
+		aliases = appendUnique(aliases, oldName)
+	}
+
+	if len(aliases) == 0 {
+		aliases = nil
+	}
+
+	garden.Name = newName
+	garden.Aliases = aliases
+
+	if config.DefaultGarden == oldName {
+		config.DefaultGarden = newName
+	}
+
+	if raw, ok := config.rawKubeconfigs[oldName]; ok {
+		delete(config.rawKubeconfigs, oldName)
+		config.setRawKubeconfig(newName, raw)
+	}
+
+	return nil
+}
+
+// checkNotInherited returns an error if the garden is defined by an additional config source. Such a garden cannot
+// be deleted or renamed, because the primary config file can only add fields to the garden of the config source.
+func (config *Config) checkNotInherited(name, action string) error {
+	if _, ok := indexOfGarden(config.inherited, name); ok {
+		return fmt.Errorf("garden %q is defined in a config source and cannot be %s, change the config source instead", name, action)
+	}
+
+	return nil
+}
+
 // ClientConfig returns a deferred loading client config for a configured garden cluster
 func (config *Config) ClientConfig(name string) (clientcmd.ClientConfig, error) {
 	garden, err := config.Garden(name)
@@ -152,13 +954,27 @@ func (config *Config) ClientConfig(name string) (clientcmd.ClientConfig, error)
 		return nil, err
 	}
 
-	loader := &clientcmd.ClientConfigLoadingRules{ExplicitPath: garden.Kubeconfig}
-
 	overrides := &clientcmd.ConfigOverrides{}
 	if garden.Context != "" {
 		overrides.CurrentContext = garden.Context
 	}
 
+	// the kubeconfig has to be loaded directly if its credentials are replaced by a credential plugin or OIDC token
+	if garden.KubeconfigData != "" || garden.KubeconfigExec != nil || garden.CredentialPlugin != "" || garden.OIDC != nil {
+		rawConfig, err := garden.loadKubeconfig(config.CacheDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client configuration: %w", err)
+		}
+
+		if err := config.applyCredentials(garden, rawConfig); err != nil {
+			return nil, err
+		}
+
+		return clientcmd.NewDefaultClientConfig(*rawConfig, overrides), nil
+	}
+
+	loader := &clientcmd.ClientConfigLoadingRules{ExplicitPath: garden.Kubeconfig}
+
 	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loader, overrides), nil
 }
 
@@ -169,17 +985,34 @@ func (config *Config) DirectClientConfig(name string) (clientcmd.ClientConfig, e
 		return nil, err
 	}
 
-	rawConfig, err := garden.LoadRawConfig()
+	rawConfig, err := garden.loadRawConfig(config.CacheDir)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := config.applyCredentials(garden, rawConfig); err != nil {
+		return nil, err
+	}
+
 	return clientcmd.NewDefaultClientConfig(*rawConfig, nil), nil
 }
 
-//LoadRawConfig directly loads the raw config from file, validates the content and removes all the irrelevant pieces
+// applyCredentials replaces the user credentials of the garden kubeconfig with the credential plugin or the OIDC token of the garden
+func (config *Config) applyCredentials(garden *Garden, rawConfig *clientcmdapi.Config) error {
+	if err := config.applyCredentialPlugin(garden, rawConfig); err != nil {
+		return fmt.Errorf("failed to apply credential plugin: %w", err)
+	}
+
+	return config.applyOIDC(garden, rawConfig)
+}
+
+//LoadRawConfig directly loads the raw config from file, inline data or exec command, validates the content and removes all the irrelevant pieces
 func (g *Garden) LoadRawConfig() (*clientcmdapi.Config, error) {
-	rawConfig, err := clientcmd.LoadFromFile(g.Kubeconfig)
+	return g.loadRawConfig("")
+}
+
+func (g *Garden) loadRawConfig(cacheDir string) (*clientcmdapi.Config, error) {
+	rawConfig, err := g.loadKubeconfig(cacheDir)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load client configuration: %w", err)
 	}
@@ -202,6 +1035,39 @@ func (g *Garden) LoadRawConfig() (*clientcmdapi.Config, error) {
 	return rawConfig, nil
 }
 
+// hasKubeconfig returns true if one of the kubeconfig sources is set
+func (g *Garden) hasKubeconfig() bool {
+	return g.Kubeconfig != "" || g.KubeconfigData != "" || g.KubeconfigExec != nil
+}
+
+// loadKubeconfig loads the kubeconfig from the exec command, the inline data or the kubeconfig file
+func (g *Garden) loadKubeconfig(cacheDir string) (*clientcmdapi.Config, error) {
+	switch {
+	case g.KubeconfigExec != nil:
+		data, err := g.KubeconfigExec.output(cacheDir, g.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		return clientcmd.Load(data)
+	case g.KubeconfigData != "":
+		return g.loadKubeconfigData()
+	default:
+		return clientcmd.LoadFromFile(g.Kubeconfig)
+	}
+}
+
+// loadKubeconfigData parses the inline kubeconfig, which is decoded first if it is base64 encoded
+func (g *Garden) loadKubeconfigData() (*clientcmdapi.Config, error) {
+	data := []byte(g.KubeconfigData)
+
+	if decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(g.KubeconfigData)); err == nil {
+		data = decoded
+	}
+
+	return clientcmd.Load(data)
+}
+
 // PatternMatch holds (target) values extracted from a provided string
 type PatternMatch struct {
 	// Garden is the matched Garden
@@ -210,24 +1076,42 @@ type PatternMatch struct {
 	Project string
 	// Namespace is the matched Namespace, can be used to find the related project
 	Namespace string
+	// Seed is the matched Seed
+	Seed string
 	// Shoot is the matched Shoot
 	Shoot string
+	// Pattern is the regular expression that matched the provided value
+	Pattern string
 }
 
 // PatternKey is a key that can be used to identify a value in a pattern
 type PatternKey string
 
 const (
+	// PatternKeyGarden is used to identify a Garden by its identity or alias, only supported in global match patterns
+	PatternKeyGarden = PatternKey("garden")
 	// PatternKeyProject is used to identify a Project
 	PatternKeyProject = PatternKey("project")
 	// PatternKeyNamespace is used to identify a Project by the namespace it refers to
 	PatternKeyNamespace = PatternKey("namespace")
+	// PatternKeySeed is used to identify a Seed
+	PatternKeySeed = PatternKey("seed")
 	// PatternKeyShoot is used to identify a Shoot
 	PatternKeyShoot = PatternKey("shoot")
 )
 
+// supportedPatternKeys is the list of named capturing groups that can be used in garden patterns
+var supportedPatternKeys = []PatternKey{PatternKeyProject, PatternKeyNamespace, PatternKeySeed, PatternKeyShoot}
+
+// supportedMatchPatternKeys is the list of named capturing groups that can be used in global match patterns
+var supportedMatchPatternKeys = append([]PatternKey{PatternKeyGarden}, supportedPatternKeys...)
+
 // MatchPattern matches a string against patterns defined in gardenctl config
-// If matched, the function creates and returns a PatternMatch from the provided target string
+// If matched, the function creates and returns a PatternMatch from the provided target string.
+// The patterns of the preferred garden are matched first, then the patterns of all other gardens.
+// The global match patterns, followed by the patterns of the pattern presets, are only used if none of the garden
+// scoped patterns matches. If the matching global pattern does not capture a garden, the preferred garden or the
+// default garden is used.
 func (config *Config) MatchPattern(preferredGardenName string, value string) (*PatternMatch, error) {
 	if preferredGardenName != "" {
 		g, err := config.Garden(preferredGardenName)
@@ -265,11 +1149,44 @@ func (config *Config) MatchPattern(preferredGardenName string, value string) (*P
 		}
 	}
 
-	if patternMatch == nil {
+	if patternMatch != nil {
+		return patternMatch, nil
+	}
+
+	globalPatterns, err := config.globalMatchPatterns()
+	if err != nil {
+		return nil, err
+	}
+
+	match, err := matchPattern(globalPatterns, value)
+	if err != nil {
+		return nil, err
+	}
+
+	if match == nil {
 		return nil, errors.New("the provided value does not match any pattern")
 	}
 
-	return patternMatch, nil
+	if match.Garden == "" {
+		match.Garden = preferredGardenName
+	}
+
+	if match.Garden == "" {
+		if config.DefaultGarden == "" {
+			return nil, errors.New("the provided value does not contain a garden and no garden is targeted")
+		}
+
+		match.Garden = config.DefaultGarden
+	}
+
+	g, err := config.Garden(match.Garden)
+	if err != nil {
+		return nil, err
+	}
+
+	match.Garden = g.Name
+
+	return match, nil
 }
 
 // matchPattern matches pattern with provided list of patterns
@@ -288,14 +1205,18 @@ func matchPattern(patterns []string, value string) (*PatternMatch, error) {
 			continue
 		}
 
-		tm := &PatternMatch{}
+		tm := &PatternMatch{Pattern: p}
 
 		for i, name := range names {
 			switch PatternKey(name) {
+			case PatternKeyGarden:
+				tm.Garden = matches[i]
 			case PatternKeyProject:
 				tm.Project = matches[i]
 			case PatternKeyNamespace:
 				tm.Namespace = matches[i]
+			case PatternKeySeed:
+				tm.Seed = matches[i]
 			case PatternKeyShoot:
 				tm.Shoot = matches[i]
 			}
@@ -7,15 +7,24 @@ SPDX-License-Identifier: Apache-2.0
 package config_test
 
 import (
+	"bytes"
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 	"k8s.io/utils/pointer"
 
+	"github.com/gardener/gardenctl-v2/internal/audit"
+	"github.com/gardener/gardenctl-v2/internal/oidc"
 	"github.com/gardener/gardenctl-v2/pkg/config"
 )
 
@@ -26,6 +35,9 @@ var _ = Describe("Config", func() {
 		fooIdentity      = "fooGarden"
 		project          = "fooProject"
 		shoot            = "fooShoot"
+		garden1Pattern   = fmt.Sprintf("^%s/shoot--(?P<project>.+)--(?P<shoot>.+)$", clusterIdentity1)
+		shootPattern     = "^shoot--(?P<project>.+)--(?P<shoot>.+)$"
+		garden2Pattern   = fmt.Sprintf("^(%s/)?shoot--(?P<project>.+)--(?P<shoot>.+)$", clusterIdentity2)
 		cfg              *config.Config
 	)
 
@@ -36,14 +48,14 @@ var _ = Describe("Config", func() {
 				{
 					Name: clusterIdentity1,
 					Patterns: []string{
-						fmt.Sprintf("^%s/shoot--(?P<project>.+)--(?P<shoot>.+)$", clusterIdentity1),
-						"^shoot--(?P<project>.+)--(?P<shoot>.+)$",
+						garden1Pattern,
+						shootPattern,
 					},
 				},
 				{
 					Name: clusterIdentity2,
 					Patterns: []string{
-						fmt.Sprintf("^(%s/)?shoot--(?P<project>.+)--(?P<shoot>.+)$", clusterIdentity2),
+						garden2Pattern,
 					},
 				}},
 		}
@@ -59,9 +71,9 @@ var _ = Describe("Config", func() {
 	}
 
 	DescribeTable("MatchPattern returns a match",
-		func(currentGardenName string, patternPrefix string, expectedGarden string) {
+		func(currentGardenName string, patternPrefix string, expectedGarden string, expectedPattern string) {
 			value := patternValue(patternPrefix)
-			expectedPM := &config.PatternMatch{Garden: expectedGarden, Project: project, Shoot: shoot}
+			expectedPM := &config.PatternMatch{Garden: expectedGarden, Project: project, Shoot: shoot, Pattern: expectedPattern}
 			match, err := cfg.MatchPattern(currentGardenName, value)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(match).To(Equal(expectedPM))
@@ -70,27 +82,32 @@ var _ = Describe("Config", func() {
 			"when the targetValue contains a garden prefix and preferred gardenName is equal (keep garden) - return extracted garden",
 			clusterIdentity1,
 			clusterIdentity1,
-			clusterIdentity1),
+			clusterIdentity1,
+			garden1Pattern),
 		Entry(
 			"when the targetValue contains a garden prefix and no preferred gardenName is set (set garden) - return extracted garden",
 			"",
 			clusterIdentity1,
-			clusterIdentity1),
+			clusterIdentity1,
+			garden1Pattern),
 		Entry(
 			"when the targetValue contains a garden prefix and preferred gardenName is set to other garden (switch garden) - return extracted garden",
 			clusterIdentity2,
 			clusterIdentity1,
-			clusterIdentity1),
+			clusterIdentity1,
+			garden1Pattern),
 		Entry(
 			"when the targetValue does not contain a garden prefix the preferred gardenName is unchanged (keep garden) - return preferred garden",
 			clusterIdentity1,
 			"",
-			clusterIdentity1),
+			clusterIdentity1,
+			shootPattern),
 		Entry(
 			"when the targetValue does not contain a garden prefix the preferred gardenName is unchanged (keep garden) - return preferred garden",
 			clusterIdentity2,
 			"",
-			clusterIdentity2),
+			clusterIdentity2,
+			garden2Pattern),
 	)
 
 	DescribeTable("MatchPattern returns an error",
@@ -121,6 +138,100 @@ var _ = Describe("Config", func() {
 			fmt.Sprintf("garden %q is not defined in gardenctl configuration", fooIdentity)),
 	)
 
+	Describe("global match patterns", func() {
+		BeforeEach(func() {
+			cfg.MatchPatterns = []string{
+				"^https://dashboard\\.(?P<garden>[^/]+)\\.example\\.com/namespace/(?P<namespace>[^/]+)/shoots/(?P<shoot>[^/]+)$",
+				"^(?P<project>[^/]+)/(?P<shoot>[^/]+)$",
+			}
+		})
+
+		It("should prefer garden scoped patterns", func() {
+			match, err := cfg.MatchPattern("", patternValue(clusterIdentity2))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(match).To(Equal(&config.PatternMatch{Garden: clusterIdentity2, Project: project, Shoot: shoot, Pattern: garden2Pattern}))
+		})
+
+		It("should match the garden capturing group", func() {
+			match, err := cfg.MatchPattern(clusterIdentity1, "https://dashboard.garden2.example.com/namespace/garden-foo/shoots/bar")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(match).To(Equal(&config.PatternMatch{Garden: clusterIdentity2, Namespace: "garden-foo", Shoot: "bar", Pattern: cfg.MatchPatterns[0]}))
+		})
+
+		It("should use the preferred garden if the pattern does not contain a garden", func() {
+			match, err := cfg.MatchPattern(clusterIdentity1, "foo/bar")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(match).To(Equal(&config.PatternMatch{Garden: clusterIdentity1, Project: "foo", Shoot: "bar", Pattern: cfg.MatchPatterns[1]}))
+		})
+
+		It("should match the seed capturing group", func() {
+			cfg.MatchPatterns = []string{"^https://dashboard\\.(?P<garden>[^/]+)\\.example\\.com/seeds/(?P<seed>[^/]+)$"}
+			match, err := cfg.MatchPattern("", "https://dashboard.garden1.example.com/seeds/aws-eu1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(match).To(Equal(&config.PatternMatch{Garden: clusterIdentity1, Seed: "aws-eu1", Pattern: cfg.MatchPatterns[0]}))
+		})
+
+		It("should fail if the pattern does not contain a garden and no garden is targeted", func() {
+			_, err := cfg.MatchPattern("", "foo/bar")
+			Expect(err).To(MatchError("the provided value does not contain a garden and no garden is targeted"))
+		})
+
+		It("should use the default garden if the pattern does not contain a garden and no garden is targeted", func() {
+			cfg.DefaultGarden = clusterIdentity2
+			match, err := cfg.MatchPattern("", "foo/bar")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(match).To(Equal(&config.PatternMatch{Garden: clusterIdentity2, Project: "foo", Shoot: "bar", Pattern: cfg.MatchPatterns[1]}))
+		})
+
+		It("should fail if the matched garden is not defined", func() {
+			_, err := cfg.MatchPattern("", "https://dashboard.garden3.example.com/namespace/garden-foo/shoots/bar")
+			Expect(err).To(MatchError(`garden "garden3" is not defined in gardenctl configuration`))
+		})
+	})
+
+	Describe("pattern presets", func() {
+		BeforeEach(func() {
+			cfg.Gardens[0].Patterns = nil
+			cfg.Gardens[1].Patterns = nil
+			cfg.PatternPresets = config.PatternPresetNames()
+		})
+
+		DescribeTable("MatchPattern matches the patterns of the presets",
+			func(value string, expected config.PatternMatch) {
+				match, err := cfg.MatchPattern(clusterIdentity1, value)
+				Expect(err).NotTo(HaveOccurred())
+				match.Pattern = ""
+				Expect(*match).To(Equal(expected))
+			},
+			Entry("when the value is a dashboard URL",
+				"https://dashboard.garden.example.com/namespace/garden-foo/shoots/bar?tab=overview",
+				config.PatternMatch{Garden: clusterIdentity1, Namespace: "garden-foo", Shoot: "bar"}),
+			Entry("when the value is a kubectl context name",
+				"garden-foo--bar-baz-external",
+				config.PatternMatch{Garden: clusterIdentity1, Namespace: "garden-foo", Shoot: "bar-baz"}),
+			Entry("when the value is the shorthand",
+				"garden2/foo/bar",
+				config.PatternMatch{Garden: clusterIdentity2, Project: "foo", Shoot: "bar"}),
+			Entry("when the value is a technical ID",
+				"shoot--foo--bar",
+				config.PatternMatch{Garden: clusterIdentity1, Project: "foo", Shoot: "bar"}),
+		)
+
+		It("should prefer the match patterns", func() {
+			cfg.MatchPatterns = []string{"^(?P<project>[^/]+)/(?P<shoot>[^/]+)/(?P<namespace>[^/]+)$"}
+			match, err := cfg.MatchPattern(clusterIdentity1, "garden2/foo/bar")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(match.Garden).To(Equal(clusterIdentity1))
+			Expect(match.Pattern).To(Equal(cfg.MatchPatterns[0]))
+		})
+
+		It("should fail for an unknown preset", func() {
+			cfg.PatternPresets = []string{"foo"}
+			_, err := cfg.MatchPattern(clusterIdentity1, "foo")
+			Expect(err).To(MatchError(HavePrefix(`pattern preset "foo" is not defined`)))
+		})
+	})
+
 	It("should find garden by identity", func() {
 		garden, err := cfg.Garden(clusterIdentity1)
 		Expect(err).NotTo(HaveOccurred())
@@ -128,6 +239,312 @@ var _ = Describe("Config", func() {
 
 	})
 
+	It("should find garden by alias", func() {
+		cfg.Gardens[1].Aliases = []string{"foo"}
+		garden, err := cfg.Garden("foo")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(garden.Name).Should(Equal(clusterIdentity2))
+	})
+
+	Describe("inline kubeconfig", func() {
+		const kubeconfigData = `apiVersion: v1
+kind: Config
+clusters:
+- name: garden
+  cluster:
+    server: https://api.garden.example.com
+contexts:
+- name: garden
+  context:
+    cluster: garden
+    user: user
+- name: other
+  context:
+    cluster: garden
+    user: user
+current-context: garden
+users:
+- name: user
+  user:
+    token: secret
+`
+
+		DescribeTable("loading the client config",
+			func(data string) {
+				cfg.Gardens[0].KubeconfigData = data
+				cfg.Gardens[0].Context = "other"
+
+				clientConfig, err := cfg.ClientConfig(clusterIdentity1)
+				Expect(err).NotTo(HaveOccurred())
+				restConfig, err := clientConfig.ClientConfig()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(restConfig.Host).To(Equal("https://api.garden.example.com"))
+				rawConfig, err := clientConfig.RawConfig()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(rawConfig.CurrentContext).To(Equal("garden"))
+
+				rawConfig2, err := cfg.Gardens[0].LoadRawConfig()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(rawConfig2.CurrentContext).To(Equal("other"))
+				Expect(rawConfig2.Contexts).To(HaveLen(1))
+			},
+			Entry("when the data is raw YAML", kubeconfigData),
+			Entry("when the data is base64 encoded", base64.StdEncoding.EncodeToString([]byte(kubeconfigData))),
+		)
+	})
+
+	Describe("credential plugin", func() {
+		BeforeEach(func() {
+			cfg.CredentialPlugins = []config.CredentialPlugin{{
+				Name:    "sso",
+				Command: "sso-login",
+				Args:    []string{"get-token"},
+				Env:     map[string]string{"SSO_TENANT": "example"},
+			}}
+			cfg.Gardens[0].KubeconfigData = `apiVersion: v1
+kind: Config
+clusters:
+- name: garden
+  cluster:
+    server: https://api.garden.example.com
+contexts:
+- name: garden
+  context:
+    cluster: garden
+    user: user
+current-context: garden
+users:
+- name: user
+  user:
+    token: secret
+`
+			cfg.Gardens[0].CredentialPlugin = "sso"
+		})
+
+		It("should replace the user credentials with the credential plugin", func() {
+			clientConfig, err := cfg.ClientConfig(clusterIdentity1)
+			Expect(err).NotTo(HaveOccurred())
+			rawConfig, err := clientConfig.RawConfig()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rawConfig.AuthInfos).To(HaveKey("user"))
+			authInfo := rawConfig.AuthInfos["user"]
+			Expect(authInfo.Token).To(BeEmpty())
+			Expect(authInfo.Exec).NotTo(BeNil())
+			Expect(authInfo.Exec.Command).To(Equal("sso-login"))
+			Expect(authInfo.Exec.Args).To(Equal([]string{"get-token"}))
+			Expect(authInfo.Exec.Env).To(Equal([]clientcmdapi.ExecEnvVar{{Name: "SSO_TENANT", Value: "example"}}))
+			Expect(authInfo.Exec.APIVersion).To(Equal(config.DefaultCredentialPluginAPIVersion))
+
+			directConfig, err := cfg.DirectClientConfig(clusterIdentity1)
+			Expect(err).NotTo(HaveOccurred())
+			rawConfig, err = directConfig.RawConfig()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rawConfig.AuthInfos["user"].Exec.Command).To(Equal("sso-login"))
+		})
+
+		It("should fail if the credential plugin is not defined", func() {
+			cfg.Gardens[0].CredentialPlugin = "unknown"
+			_, err := cfg.ClientConfig(clusterIdentity1)
+			Expect(err).To(MatchError(`failed to apply credential plugin: credential plugin "unknown" is not defined in gardenctl configuration`))
+		})
+	})
+
+	Describe("oidc", func() {
+		BeforeEach(func() {
+			cfg.TokenDir = filepath.Join(gardenHomeDir, "tokens")
+			cfg.Gardens[0].KubeconfigData = `apiVersion: v1
+kind: Config
+clusters:
+- name: garden
+  cluster:
+    server: https://api.garden.example.com
+contexts:
+- name: garden
+  context:
+    cluster: garden
+    user: user
+current-context: garden
+users:
+- name: user
+  user:
+    token: secret
+`
+			cfg.Gardens[0].OIDC = &config.OIDC{IssuerURL: "https://issuer.example.com", ClientID: "gardenctl"}
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(cfg.TokenDir)).To(Succeed())
+		})
+
+		It("should replace the user credentials with the cached ID token", func() {
+			Expect(cfg.TokenCache().Save("https://api.garden.example.com", &oidc.Token{IDToken: "id-token", Expiry: time.Now().Add(time.Hour)})).To(Succeed())
+
+			clientConfig, err := cfg.ClientConfig(clusterIdentity1)
+			Expect(err).NotTo(HaveOccurred())
+			restConfig, err := clientConfig.ClientConfig()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(restConfig.BearerToken).To(Equal("id-token"))
+		})
+
+		It("should cache the token by the cluster identity of the garden", func() {
+			cfg.Gardens[0].ClusterIdentity = "landscape-dev"
+			Expect(cfg.TokenCacheKey(clusterIdentity1)).To(Equal("landscape-dev"))
+			Expect(cfg.TokenCache().Save("landscape-dev", &oidc.Token{IDToken: "id-token", Expiry: time.Now().Add(time.Hour)})).To(Succeed())
+
+			clientConfig, err := cfg.ClientConfig(clusterIdentity1)
+			Expect(err).NotTo(HaveOccurred())
+			restConfig, err := clientConfig.ClientConfig()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(restConfig.BearerToken).To(Equal("id-token"))
+		})
+
+		It("should keep the token if the garden is renamed", func() {
+			Expect(cfg.TokenCache().Save("https://api.garden.example.com", &oidc.Token{IDToken: "id-token", Expiry: time.Now().Add(time.Hour)})).To(Succeed())
+			cfg.Gardens[0].Name = "renamed"
+
+			clientConfig, err := cfg.ClientConfig("renamed")
+			Expect(err).NotTo(HaveOccurred())
+			restConfig, err := clientConfig.ClientConfig()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(restConfig.BearerToken).To(Equal("id-token"))
+		})
+
+		It("should fail if the user is not logged in", func() {
+			_, err := cfg.ClientConfig(clusterIdentity1)
+			Expect(err).To(MatchError(`failed to get token of garden "garden1", run gardenctl login garden1: not logged in`))
+		})
+	})
+
+	Describe("adding a garden", func() {
+		It("should add the garden", func() {
+			Expect(cfg.AddGarden(config.Garden{Name: "garden3", Kubeconfig: "/kubeconfig.yaml"})).To(Succeed())
+			Expect(cfg.GardenNames()).To(Equal([]string{clusterIdentity1, clusterIdentity2, "garden3"}))
+		})
+
+		It("should fail if the garden is already defined", func() {
+			err := cfg.AddGarden(config.Garden{Name: clusterIdentity1})
+			Expect(errors.Is(err, config.ErrGardenExists)).To(BeTrue())
+			Expect(err).To(MatchError(`failed to add garden "garden1": garden is already defined in gardenctl configuration`))
+		})
+
+		It("should fail if the identity is an alias of another garden", func() {
+			cfg.Gardens[1].Aliases = []string{"foo"}
+			Expect(cfg.AddGarden(config.Garden{Name: "foo"})).To(MatchError(`failed to add garden "foo": it is already an alias of garden "garden2"`))
+		})
+	})
+
+	Describe("updating a garden", func() {
+		It("should replace the kubeconfig and append aliases and patterns", func() {
+			cfg.Gardens[0].KubeconfigData = "apiVersion: v1\nkind: Config\n"
+			cfg.Gardens[0].Context = "foo"
+			cfg.Gardens[0].Aliases = []string{"g1"}
+			Expect(cfg.UpdateGarden(config.Garden{
+				Name:       clusterIdentity1,
+				Kubeconfig: "/kubeconfig.yaml",
+				Aliases:    []string{"g1", "one"},
+				Patterns:   []string{shootPattern, "^g1/(?P<shoot>.+)$"},
+			})).To(Succeed())
+			Expect(cfg.Gardens[0]).To(Equal(config.Garden{
+				Name:       clusterIdentity1,
+				Kubeconfig: "/kubeconfig.yaml",
+				Context:    "foo",
+				Aliases:    []string{"g1", "one"},
+				Patterns:   []string{garden1Pattern, shootPattern, "^g1/(?P<shoot>.+)$"},
+			}))
+		})
+
+		It("should fail if the garden is not defined", func() {
+			Expect(cfg.UpdateGarden(config.Garden{Name: "foo"})).To(MatchError(`garden "foo" is not defined in gardenctl configuration`))
+		})
+	})
+
+	Describe("deleting a garden", func() {
+		It("should delete the garden by alias and clear the default garden", func() {
+			cfg.Gardens[0].Aliases = []string{"foo"}
+			cfg.DefaultGarden = "foo"
+			identity, err := cfg.DeleteGarden("foo")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(identity).To(Equal(clusterIdentity1))
+			Expect(cfg.GardenNames()).To(Equal([]string{clusterIdentity2}))
+			Expect(cfg.DefaultGarden).To(BeEmpty())
+		})
+
+		It("should fail if the garden is not defined", func() {
+			_, err := cfg.DeleteGarden("foo")
+			Expect(err).To(MatchError(`garden "foo" is not defined in gardenctl configuration`))
+		})
+	})
+
+	Describe("renaming a garden", func() {
+		It("should rename the garden and keep the alias", func() {
+			cfg.Gardens[0].Aliases = []string{"foo", "bar"}
+			Expect(cfg.RenameGarden("foo", "bar", true)).To(Succeed())
+			Expect(cfg.Gardens[0].Name).To(Equal("bar"))
+			Expect(cfg.Gardens[0].Aliases).To(Equal([]string{"foo", clusterIdentity1}))
+		})
+
+		It("should update the default garden", func() {
+			cfg.DefaultGarden = clusterIdentity1
+			Expect(cfg.RenameGarden(clusterIdentity1, "foo", false)).To(Succeed())
+			Expect(cfg.DefaultGarden).To(Equal("foo"))
+		})
+
+		It("should fail if the new name is an alias of another garden", func() {
+			cfg.Gardens[1].Aliases = []string{"foo"}
+			Expect(cfg.RenameGarden(clusterIdentity1, "foo", false)).To(MatchError(`garden "foo" is already defined in gardenctl configuration`))
+		})
+	})
+
+	Describe("managing garden aliases", func() {
+		It("should add an alias only once", func() {
+			Expect(cfg.AddGardenAlias(clusterIdentity1, "foo")).To(Succeed())
+			Expect(cfg.AddGardenAlias("foo", "bar")).To(Succeed())
+			Expect(cfg.AddGardenAlias(clusterIdentity1, "bar")).To(Succeed())
+			Expect(cfg.Gardens[0].Aliases).To(Equal([]string{"foo", "bar"}))
+		})
+
+		It("should fail if the alias resolves to another garden", func() {
+			cfg.Gardens[1].Aliases = []string{"foo"}
+			Expect(cfg.AddGardenAlias(clusterIdentity1, "foo")).To(MatchError(`failed to add alias "foo": it is already an alias of garden "garden2"`))
+			Expect(cfg.AddGardenAlias(clusterIdentity1, clusterIdentity2)).To(MatchError(`failed to add alias "garden2": it is the identity of garden "garden2"`))
+		})
+
+		It("should remove an alias and update the default garden", func() {
+			cfg.Gardens[0].Aliases = []string{"foo"}
+			cfg.DefaultGarden = "foo"
+			Expect(cfg.RemoveGardenAlias(clusterIdentity1, "foo")).To(Succeed())
+			Expect(cfg.Gardens[0].Aliases).To(BeNil())
+			Expect(cfg.DefaultGarden).To(Equal(clusterIdentity1))
+		})
+
+		It("should fail to remove an unknown alias", func() {
+			Expect(cfg.RemoveGardenAlias(clusterIdentity1, "foo")).To(MatchError(`"foo" is not an alias of garden "garden1"`))
+		})
+	})
+
+	Describe("selecting gardens by labels", func() {
+		BeforeEach(func() {
+			cfg.Gardens[0].Labels = map[string]string{"env": "prod"}
+			cfg.Gardens[1].Labels = map[string]string{"env": "dev"}
+		})
+
+		It("should return the gardens matching the selector", func() {
+			gardens, err := cfg.SelectGardens("env in (prod,canary)")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(gardens).To(HaveLen(1))
+			Expect(gardens[0].Name).To(Equal(clusterIdentity1))
+
+			gardens, err = cfg.SelectGardens("env")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(gardens).To(HaveLen(2))
+		})
+
+		It("should fail for an invalid selector", func() {
+			_, err := cfg.SelectGardens("env in (")
+			Expect(err).To(MatchError(HavePrefix(`invalid label selector "env in ("`)))
+		})
+	})
+
 	It("should throw an error if garden not found", func() {
 		_, err := cfg.Garden("foobar")
 		Expect(err).To(HaveOccurred())
@@ -162,4 +579,397 @@ var _ = Describe("Config", func() {
 		Entry("when LinkKubeconfig is false and envVar is True", pointer.Bool(false), "True", pointer.Bool(true)),
 		Entry("when LinkKubeconfig is false and envVar is False", pointer.Bool(false), "False", pointer.Bool(false)),
 	)
+
+	It("should not save invalid patterns", func() {
+		filename := filepath.Join(gardenHomeDir, "invalid-patterns.yaml")
+		cfg.Filename = filename
+		cfg.Gardens[0].Patterns = append(cfg.Gardens[0].Patterns, "(", "^(?P<garden>.+)$")
+		cfg.MatchPatterns = []string{"^(?P<garden>[^/]+)/(?P<cluster>.+)$"}
+
+		err := cfg.Save()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(HavePrefix("invalid patterns: "))
+		Expect(err.Error()).To(ContainSubstring(`pattern "(" of garden "garden1" is not a valid regular expression`))
+		Expect(err.Error()).To(ContainSubstring(`pattern "^(?P<garden>.+)$" of garden "garden1" contains an invalid subexpression "garden"`))
+		Expect(err.Error()).To(ContainSubstring(`match pattern "^(?P<garden>[^/]+)/(?P<cluster>.+)$" contains an invalid subexpression "cluster"`))
+		Expect(filename).NotTo(BeAnExistingFile())
+	})
+
+	Describe("saving the configuration", func() {
+		var filename string
+
+		BeforeEach(func() {
+			dir, err := os.MkdirTemp(gardenHomeDir, "save-*")
+			Expect(err).NotTo(HaveOccurred())
+			filename = filepath.Join(dir, "gardenctl-v2.yaml")
+			cfg.Filename = filename
+		})
+
+		saveWithIdentity := func(name string) {
+			cfg.Gardens[0].Name = name
+			ExpectWithOffset(1, cfg.Save()).To(Succeed())
+		}
+
+		contentOf := func(filename string) string {
+			data, err := os.ReadFile(filename)
+			ExpectWithOffset(1, err).NotTo(HaveOccurred())
+
+			return string(data)
+		}
+
+		It("should merge the changes of concurrent writers", func() {
+			Expect(os.WriteFile(filename, []byte("gardens:\n- identity: shared\n  kubeconfig: /shared.yaml\n- identity: obsolete\n  kubeconfig: /obsolete.yaml\n"), 0600)).To(Succeed())
+
+			// every writer has loaded the configuration before any of them saves it
+			configs := make([]*config.Config, 10)
+			for i := range configs {
+				c, err := config.LoadFromFile(filename)
+				Expect(err).NotTo(HaveOccurred())
+				configs[i] = c
+			}
+
+			_, err := configs[0].DeleteGarden("obsolete")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(configs[1].UpdateGarden(config.Garden{Name: "shared", Context: "changed"})).To(Succeed())
+
+			var wg sync.WaitGroup
+
+			for i, c := range configs {
+				wg.Add(1)
+
+				go func(i int, c *config.Config) {
+					defer GinkgoRecover()
+					defer wg.Done()
+
+					Expect(c.AddGarden(config.Garden{Name: fmt.Sprintf("garden%d", i), Kubeconfig: "/kubeconfig.yaml"})).To(Succeed())
+					Expect(c.Save()).To(Succeed())
+				}(i, c)
+			}
+
+			wg.Wait()
+
+			c, err := config.LoadFromFile(filename)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c.GardenNames()).To(ConsistOf("shared", "garden0", "garden1", "garden2", "garden3", "garden4", "garden5", "garden6", "garden7", "garden8", "garden9"))
+
+			shared, err := c.Garden("shared")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(shared.Context).To(Equal("changed"))
+		})
+
+		It("should not keep backups by default", func() {
+			saveWithIdentity("v1")
+			saveWithIdentity("v2")
+
+			Expect(contentOf(filename)).To(ContainSubstring("identity: v2"))
+			Expect(filename + ".bak").NotTo(BeAnExistingFile())
+
+			entries, err := os.ReadDir(filepath.Dir(filename))
+			Expect(err).NotTo(HaveOccurred())
+
+			var names []string
+			for _, entry := range entries {
+				names = append(names, entry.Name())
+			}
+
+			Expect(names).To(ConsistOf("gardenctl-v2.yaml", "gardenctl-v2.yaml.lock"))
+		})
+
+		It("should rotate the backups", func() {
+			cfg.Backups = 2
+
+			for _, name := range []string{"v1", "v2", "v3", "v4"} {
+				saveWithIdentity(name)
+			}
+
+			Expect(contentOf(filename)).To(ContainSubstring("identity: v4"))
+			Expect(contentOf(filename + ".bak")).To(ContainSubstring("identity: v3"))
+			Expect(contentOf(filename + ".bak.1")).To(ContainSubstring("identity: v2"))
+			Expect(filename + ".bak.2").NotTo(BeAnExistingFile())
+		})
+
+		It("should replace the target of a symbolic link", func() {
+			target := filepath.Join(filepath.Dir(filename), "target.yaml")
+			Expect(os.WriteFile(target, nil, 0600)).To(Succeed())
+			Expect(os.Symlink(target, filename)).To(Succeed())
+
+			saveWithIdentity("v1")
+
+			info, err := os.Lstat(filename)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.Mode() & os.ModeSymlink).NotTo(BeZero())
+			Expect(contentOf(target)).To(ContainSubstring("identity: v1"))
+		})
+
+		It("should only print the differences in dry-run mode", func() {
+			saveWithIdentity("v1")
+
+			out := &bytes.Buffer{}
+			cfg.DryRun = &config.DryRun{Strategy: config.DryRunClient, Out: out}
+			saveWithIdentity("v2")
+
+			Expect(contentOf(filename)).To(ContainSubstring("identity: v1"))
+			Expect(out.String()).To(HavePrefix("--- " + filename + "\n+++ " + filename + "\n"))
+			Expect(out.String()).To(ContainSubstring("\n-    - identity: v1\n+    - identity: v2\n"))
+		})
+
+		It("should record the changes in the audit log", func() {
+			auditFile := filepath.Join(gardenHomeDir, "audit.log")
+			cfg.Audit = &audit.Logger{Filename: auditFile}
+			saveWithIdentity("v1")
+
+			cfg.DryRun = &config.DryRun{Strategy: config.DryRunClient, Out: &bytes.Buffer{}}
+			saveWithIdentity("v2")
+
+			Expect(contentOf(auditFile)).To(MatchRegexp(`^\{"timestamp":"[^"]+","action":"write","resource":"` + regexp.QuoteMeta(filename) + `","result":"success"\}\n$`))
+		})
+	})
+
+	Describe("expanding kubeconfig paths", func() {
+		var filename string
+
+		BeforeEach(func() {
+			filename = filepath.Join(gardenHomeDir, "gardenctl-v2.yaml")
+			os.Setenv("GCTL_TEST_DIR", "/mnt/kubeconfigs")
+		})
+
+		AfterEach(func() {
+			os.Unsetenv("GCTL_TEST_DIR")
+		})
+
+		DescribeTable("loading the kubeconfig path", func(kubeconfig string, expected string) {
+			content := fmt.Sprintf("gardens:\n- identity: garden1\n  kubeconfig: %q\n", kubeconfig)
+			Expect(os.WriteFile(filename, []byte(content), 0600)).To(Succeed())
+
+			cfg, err := config.LoadFromFile(filename)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.Gardens[0].Kubeconfig).To(Equal(expected))
+		},
+			Entry("when the path does not contain variables", "/path/to/kubeconfig.yaml", "/path/to/kubeconfig.yaml"),
+			Entry("when the path contains $VAR", "$GCTL_TEST_DIR/garden.yaml", "/mnt/kubeconfigs/garden.yaml"),
+			Entry("when the path contains ${VAR}", "${GCTL_TEST_DIR}-dev/garden.yaml", "/mnt/kubeconfigs-dev/garden.yaml"),
+			Entry("when the path contains an unset variable", "/path/$GCTL_TEST_UNSET/garden.yaml", "/path//garden.yaml"),
+			Entry("when the path contains an escaped $", "/path/$$GCTL_TEST_DIR/garden.yaml", "/path/$GCTL_TEST_DIR/garden.yaml"),
+		)
+
+		It("should preserve unmodified kubeconfig paths when saving", func() {
+			content := "gardens:\n- identity: garden1\n  kubeconfig: ${GCTL_TEST_DIR}/garden1.yaml\n- identity: garden2\n  kubeconfig: ${GCTL_TEST_DIR}/garden2.yaml\n"
+			Expect(os.WriteFile(filename, []byte(content), 0600)).To(Succeed())
+
+			cfg, err := config.LoadFromFile(filename)
+			Expect(err).NotTo(HaveOccurred())
+
+			cfg.Gardens[1].Kubeconfig = "/path/to/garden2.yaml"
+			Expect(cfg.Save()).To(Succeed())
+
+			data, err := os.ReadFile(filename)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(ContainSubstring("${GCTL_TEST_DIR}/garden1.yaml"))
+			Expect(string(data)).To(ContainSubstring("/path/to/garden2.yaml"))
+		})
+	})
+
+	Describe("loading multiple config files", func() {
+		var (
+			filename string
+			source   string
+		)
+
+		writeFile := func(filename, content string) {
+			Expect(os.WriteFile(filename, []byte(content), 0600)).To(Succeed())
+		}
+
+		BeforeEach(func() {
+			filename = filepath.Join(gardenHomeDir, "gardenctl-v2.yaml")
+			source = filepath.Join(gardenHomeDir, "shared.yaml")
+
+			writeFile(filename, `gardens:
+- identity: garden1
+  context: personal
+  aliases:
+  - g1
+  patterns:
+  - ^my-(?P<shoot>.+)$
+`)
+			writeFile(source, `linkKubeconfig: false
+gardens:
+- identity: garden2
+  kubeconfig: /shared/garden2.yaml
+- identity: garden1
+  kubeconfig: /shared/garden1.yaml
+  context: shared
+  aliases:
+  - g1
+  - first
+  patterns:
+  - ^shoot--(?P<project>.+)--(?P<shoot>.+)$
+`)
+		})
+
+		It("should merge the gardens of all sources", func() {
+			cfg, err := config.LoadFromFiles(filename, source, filepath.Join(gardenHomeDir, "does-not-exist.yaml"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.Filename).To(Equal(filename))
+			Expect(cfg.LinkKubeconfig).To(Equal(pointer.Bool(false)))
+			Expect(cfg.Gardens).To(Equal([]config.Garden{
+				{
+					Name:       "garden1",
+					Kubeconfig: "/shared/garden1.yaml",
+					Context:    "personal",
+					Aliases:    []string{"g1", "first"},
+					Patterns:   []string{"^my-(?P<shoot>.+)$", "^shoot--(?P<project>.+)--(?P<shoot>.+)$"},
+				},
+				{
+					Name:       "garden2",
+					Kubeconfig: "/shared/garden2.yaml",
+				},
+			}))
+		})
+
+		It("should merge the labels of all sources", func() {
+			writeFile(filename, "gardens:\n- identity: garden1\n  labels:\n    env: dev\n")
+			writeFile(source, "gardens:\n- identity: garden1\n  kubeconfig: /shared/garden1.yaml\n  labels:\n    env: prod\n    team: core\n")
+
+			cfg, err := config.LoadFromFiles(filename, source)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.Gardens[0].Labels).To(Equal(map[string]string{"env": "dev", "team": "core"}))
+		})
+
+		It("should keep repeated arguments of the kubeconfig command", func() {
+			writeFile(source, "gardens:\n- identity: garden2\n  kubeconfigExec:\n    command: vault\n    args: [-field, a, -field, b]\n")
+
+			cfg, err := config.LoadFromFiles(filename, source)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.Gardens[1].KubeconfigExec.Args).To(Equal([]string{"-field", "a", "-field", "b"}))
+		})
+
+		It("should keep gardens protected by any source", func() {
+			writeFile(filename, "gardens:\n- identity: garden1\n  denyMutations: true\n")
+			writeFile(source, "gardens:\n- identity: garden1\n  kubeconfig: /shared/garden1.yaml\n  protected: true\n- identity: garden2\n  protected: true\n")
+
+			cfg, err := config.LoadFromFiles(filename, source)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.Gardens[0].Protected).To(BeTrue())
+			Expect(cfg.Gardens[0].DenyMutations).To(BeTrue())
+			Expect(cfg.Gardens[1].Protected).To(BeTrue())
+		})
+
+		It("should only reject invalid patterns that are added to the primary config file", func() {
+			writeFile(filename, "gardens:\n- identity: garden1\n  patterns:\n  - (\n")
+			writeFile(source, "gardens:\n- identity: garden2\n  kubeconfig: /shared/garden2.yaml\n  patterns:\n  - ^(?P<cluster>.+)$\nmatchPatterns:\n- ^(?P<garden>.+)$\n")
+
+			cfg, err := config.LoadFromFiles(filename, source)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.AddGardenAlias("garden2", "two")).To(Succeed())
+			Expect(cfg.Save()).To(Succeed())
+
+			Expect(cfg.UpdateGarden(config.Garden{Name: "garden1", Patterns: []string{"^(?P<garden>.+)$"}})).To(Succeed())
+			Expect(cfg.Save()).To(MatchError(`invalid patterns: pattern "^(?P<garden>.+)$" of garden "garden1" contains an invalid subexpression "garden"`))
+		})
+
+		It("should only save gardens that are not inherited unchanged", func() {
+			cfg, err := config.LoadFromFiles(filename, source)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.Save()).To(Succeed())
+
+			cfg, err = config.LoadFromFile(filename)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.GardenNames()).To(Equal([]string{"garden1"}))
+		})
+
+		It("should only save the fields of inherited gardens that are owned by the primary config file", func() {
+			writeFile(source, `linkKubeconfig: false
+defaultGarden: garden2
+gardens:
+- identity: garden2
+  kubeconfig: /shared/garden2.yaml
+  aliases:
+  - second
+  protected: true
+  labels:
+    env: prod
+- identity: garden1
+  kubeconfig: /shared/garden1.yaml
+  context: shared
+  aliases:
+  - g1
+  - first
+  patterns:
+  - ^shoot--(?P<project>.+)--(?P<shoot>.+)$
+  protected: true
+`)
+
+			cfg, err := config.LoadFromFiles(filename, source)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.UpdateGarden(config.Garden{Name: "garden1", Aliases: []string{"one"}})).To(Succeed())
+			Expect(cfg.UpdateGarden(config.Garden{Name: "garden2", Context: "personal"})).To(Succeed())
+			Expect(cfg.Save()).To(Succeed())
+
+			data, err := os.ReadFile(filename)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(Equal(`gardens:
+    - identity: garden1
+      kubeconfig: ""
+      context: personal
+      aliases:
+        - g1
+        - one
+      patterns:
+        - ^my-(?P<shoot>.+)$
+    - identity: garden2
+      kubeconfig: ""
+      context: personal
+`))
+
+			cfg, err = config.LoadFromFiles(filename, source)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.Gardens[0].Aliases).To(Equal([]string{"g1", "one", "first"}))
+			Expect(cfg.Gardens[0].Protected).To(BeTrue())
+			Expect(cfg.Gardens[1].Context).To(Equal("personal"))
+			Expect(cfg.Gardens[1].Kubeconfig).To(Equal("/shared/garden2.yaml"))
+		})
+
+		It("should not delete gardens that are defined in a config source", func() {
+			cfg, err := config.LoadFromFiles(filename, source)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = cfg.DeleteGarden("garden2")
+			Expect(err).To(MatchError(`garden "garden2" is defined in a config source and cannot be deleted, change the config source instead`))
+
+			_, err = cfg.DeleteGarden("first")
+			Expect(err).To(MatchError(`garden "garden1" is defined in a config source and cannot be deleted, change the config source instead`))
+			Expect(cfg.GardenNames()).To(Equal([]string{"garden1", "garden2"}))
+		})
+
+		It("should not rename gardens that are defined in a config source", func() {
+			cfg, err := config.LoadFromFiles(filename, source)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(cfg.RenameGarden("garden2", "foo", false)).To(MatchError(`garden "garden2" is defined in a config source and cannot be renamed, change the config source instead`))
+			Expect(cfg.GardenNames()).To(Equal([]string{"garden1", "garden2"}))
+		})
+
+		It("should find the credential plugins of all sources", func() {
+			writeFile(filename, "credentialPlugins:\n- name: sso\n  command: personal-login\ngardens:\n- identity: garden1\n")
+			writeFile(source, "credentialPlugins:\n- name: sso\n  command: shared-login\n- name: team\n  command: team-login\n")
+
+			cfg, err := config.LoadFromFiles(filename, source)
+			Expect(err).NotTo(HaveOccurred())
+
+			plugin, err := cfg.CredentialPlugin("sso")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(plugin.Command).To(Equal("personal-login"))
+
+			plugin, err = cfg.CredentialPlugin("team")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(plugin.Command).To(Equal("team-login"))
+			Expect(cfg.CredentialPlugins).To(HaveLen(1))
+		})
+
+		It("should fail if a source cannot be decoded", func() {
+			writeFile(source, "gardens: foo")
+			_, err := config.LoadFromFiles(filename, source)
+			Expect(err).To(MatchError(ContainSubstring("failed to load config source " + source)))
+		})
+	})
 })
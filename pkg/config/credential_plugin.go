@@ -0,0 +1,165 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// DefaultCredentialPluginAPIVersion is the version of the client.authentication.k8s.io API that is used
+// to talk to a credential plugin if no version is configured
+const DefaultCredentialPluginAPIVersion = "client.authentication.k8s.io/v1beta1"
+
+// CredentialPlugin configures a kubectl compatible exec credential plugin, e.g. a wrapper for a corporate
+// single sign-on, that provides the credentials for the gardens that refer to it. The plugin replaces the
+// user credentials of the garden kubeconfig, so that the kubeconfig does not need to contain an exec block.
+type CredentialPlugin struct {
+	// Name is used by gardens to refer to the plugin
+	Name string `yaml:"name" json:"name" toml:"name"`
+	// Command is the command to execute, it has to print an ExecCredential to stdout
+	Command string `yaml:"command" json:"command" toml:"command"`
+	// Args is a list of arguments that are passed to the command
+	// +optional
+	Args []string `yaml:"args,omitempty" json:"args,omitempty" toml:"args,omitempty"`
+	// Env holds additional environment variables that are set for the command
+	// +optional
+	Env map[string]string `yaml:"env,omitempty" json:"env,omitempty" toml:"env,omitempty"`
+	// APIVersion is the version of the client.authentication.k8s.io API the plugin supports.
+	// Defaults to client.authentication.k8s.io/v1beta1
+	// +optional
+	APIVersion string `yaml:"apiVersion,omitempty" json:"apiVersion,omitempty" toml:"apiVersion,omitempty"`
+	// InstallHint is printed if the command cannot be found, e.g. to explain how to install the plugin
+	// +optional
+	InstallHint string `yaml:"installHint,omitempty" json:"installHint,omitempty" toml:"installHint,omitempty"`
+	// ProvideClusterInfo passes the information about the cluster to the plugin in the KUBERNETES_EXEC_INFO environment variable
+	// +optional
+	ProvideClusterInfo bool `yaml:"provideClusterInfo,omitempty" json:"provideClusterInfo,omitempty" toml:"provideClusterInfo,omitempty"`
+}
+
+// Validate checks that the plugin has a name and a command
+func (p *CredentialPlugin) Validate() error {
+	if p.Name == "" {
+		return errors.New("name must not be empty")
+	}
+
+	if p.Command == "" {
+		return errors.New("command must not be empty")
+	}
+
+	return nil
+}
+
+// ExecConfig returns the exec configuration of a kubeconfig user that runs the plugin
+func (p *CredentialPlugin) ExecConfig() *clientcmdapi.ExecConfig {
+	apiVersion := p.APIVersion
+	if apiVersion == "" {
+		apiVersion = DefaultCredentialPluginAPIVersion
+	}
+
+	names := make([]string, 0, len(p.Env))
+	for name := range p.Env {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	env := make([]clientcmdapi.ExecEnvVar, len(names))
+	for i, name := range names {
+		env[i] = clientcmdapi.ExecEnvVar{Name: name, Value: p.Env[name]}
+	}
+
+	return &clientcmdapi.ExecConfig{
+		Command:            p.Command,
+		Args:               append([]string{}, p.Args...),
+		Env:                env,
+		APIVersion:         apiVersion,
+		InstallHint:        p.InstallHint,
+		ProvideClusterInfo: p.ProvideClusterInfo,
+	}
+}
+
+// CredentialPlugin returns the credential plugin with the given name. The plugins of the primary
+// config file take precedence over the plugins of additional config sources.
+func (config *Config) CredentialPlugin(name string) (*CredentialPlugin, error) {
+	for _, plugins := range [][]CredentialPlugin{config.CredentialPlugins, config.inheritedCredentialPlugins} {
+		for i := range plugins {
+			if plugins[i].Name == name {
+				return &plugins[i], nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("credential plugin %q is not defined in gardenctl configuration", name)
+}
+
+// applyCredentialPlugin replaces the credentials of the user of the current context with the credential plugin
+// of the garden. The kubeconfig is not modified if the garden does not refer to a credential plugin.
+func (config *Config) applyCredentialPlugin(garden *Garden, rawConfig *clientcmdapi.Config) error {
+	if garden.CredentialPlugin == "" {
+		return nil
+	}
+
+	plugin, err := config.CredentialPlugin(garden.CredentialPlugin)
+	if err != nil {
+		return err
+	}
+
+	authInfo := clientcmdapi.NewAuthInfo()
+	authInfo.Exec = plugin.ExecConfig()
+
+	return setAuthInfo(garden, rawConfig, authInfo)
+}
+
+// setAuthInfo replaces the user of the current context with the given user. All other credentials
+// of the user are dropped, the given user is the only source of credentials.
+func setAuthInfo(garden *Garden, rawConfig *clientcmdapi.Config, authInfo *clientcmdapi.AuthInfo) error {
+	currentContext := rawConfig.CurrentContext
+	if garden.Context != "" {
+		currentContext = garden.Context
+	}
+
+	context, ok := rawConfig.Contexts[currentContext]
+	if !ok {
+		return fmt.Errorf("context %q does not exist in kubeconfig of garden %q", currentContext, garden.Name)
+	}
+
+	if rawConfig.AuthInfos == nil {
+		rawConfig.AuthInfos = map[string]*clientcmdapi.AuthInfo{}
+	}
+
+	if context.AuthInfo == "" {
+		context.AuthInfo = garden.Name
+	}
+
+	rawConfig.AuthInfos[context.AuthInfo] = authInfo
+
+	return nil
+}
+
+// mergeCredentialPlugins appends the plugins of src whose names are not yet used in dst
+func mergeCredentialPlugins(dst, src []CredentialPlugin) []CredentialPlugin {
+	for _, p := range src {
+		found := false
+
+		for _, q := range dst {
+			if q.Name == p.Name {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			dst = append(dst, p)
+		}
+	}
+
+	return dst
+}
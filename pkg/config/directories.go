@@ -0,0 +1,67 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import "fmt"
+
+// Directories overrides the directories in which gardenctl stores its state. The directory of the configuration file
+// itself cannot be configured here, use the GCTL_HOME environment variable or the --config flag instead.
+// The paths may start with ~ and contain environment variables.
+type Directories struct {
+	// Cache is the directory of the cached completion values and kubeconfigs of kubeconfigExec commands
+	// +optional
+	Cache string `yaml:"cache,omitempty" json:"cache,omitempty" toml:"cache,omitempty"`
+	// Tokens is the directory of the cached tokens of garden logins
+	// +optional
+	Tokens string `yaml:"tokens,omitempty" json:"tokens,omitempty" toml:"tokens,omitempty"`
+	// Sessions is the parent directory of the session directories, which contain the targets and kubeconfigs of the shell sessions
+	// +optional
+	Sessions string `yaml:"sessions,omitempty" json:"sessions,omitempty" toml:"sessions,omitempty"`
+}
+
+// Validate checks that the paths of the directories can be expanded
+func (d *Directories) Validate() error {
+	for _, dir := range []struct{ name, path string }{
+		{"cache", d.Cache},
+		{"tokens", d.Tokens},
+		{"sessions", d.Sessions},
+	} {
+		if _, err := expandPath(dir.path); err != nil {
+			return fmt.Errorf("%s directory %q is invalid: %w", dir.name, dir.path, err)
+		}
+	}
+
+	return nil
+}
+
+// CacheDirectory returns the expanded cache directory of the configuration or an empty string if it is not configured
+func (config *Config) CacheDirectory() string {
+	return config.directory(func(d *Directories) string { return d.Cache })
+}
+
+// TokenDirectory returns the expanded token directory of the configuration or an empty string if it is not configured
+func (config *Config) TokenDirectory() string {
+	return config.directory(func(d *Directories) string { return d.Tokens })
+}
+
+// SessionsDirectory returns the expanded sessions directory of the configuration or an empty string if it is not configured
+func (config *Config) SessionsDirectory() string {
+	return config.directory(func(d *Directories) string { return d.Sessions })
+}
+
+func (config *Config) directory(get func(*Directories) string) string {
+	if config == nil || config.Directories == nil || get(config.Directories) == "" {
+		return ""
+	}
+
+	path, err := expandPath(get(config.Directories))
+	if err != nil {
+		return ""
+	}
+
+	return path
+}
@@ -0,0 +1,88 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/gardener/gardenctl-v2/internal/diff"
+)
+
+// DryRunStrategy defines how changes are handled in dry-run mode
+type DryRunStrategy string
+
+const (
+	// DryRunNone disables the dry-run mode, all changes are persisted
+	DryRunNone DryRunStrategy = ""
+	// DryRunClient only prints the changes, no change is sent to a cluster
+	DryRunClient DryRunStrategy = "client"
+	// DryRunServer prints the changes and sends the changes of API objects to the cluster as dry-run requests,
+	// so that they are validated and defaulted but not persisted
+	DryRunServer DryRunStrategy = "server"
+)
+
+// ParseDryRunStrategy returns the dry-run strategy of the value of the --dry-run flag
+func ParseDryRunStrategy(value string) (DryRunStrategy, error) {
+	switch strategy := DryRunStrategy(value); strategy {
+	case DryRunNone, DryRunClient, DryRunServer:
+		return strategy, nil
+	}
+
+	return DryRunNone, fmt.Errorf("must be one of '%s' or '%s'", DryRunClient, DryRunServer)
+}
+
+// DryRun configures the dry-run mode. The configuration file and the target are not written,
+// the differences are printed instead.
+type DryRun struct {
+	// Strategy is the dry-run strategy, the dry-run mode is disabled if it is empty
+	Strategy DryRunStrategy
+	// Out receives the changes that would have been made
+	Out io.Writer
+}
+
+// IsDryRun returns true if the dry-run mode is enabled, i.e. if nothing must be changed
+func (config *Config) IsDryRun() bool {
+	return config != nil && config.DryRun != nil && config.DryRun.Strategy != DryRunNone
+}
+
+// printDiff prints the differences between the configuration file and the given unencrypted content
+func (config *Config) printDiff(data []byte) error {
+	current, err := readCurrentFile(config.Filename)
+	if err != nil {
+		return err
+	}
+
+	current, err = decrypt(current)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt file: %w", err)
+	}
+
+	return diff.Print(config.DryRun.Out, config.Filename, current, data)
+}
+
+// printFileDiff prints the differences between the given file, e.g. a synced file, and the given content
+func (config *Config) printFileDiff(filename string, data []byte) error {
+	current, err := readCurrentFile(filename)
+	if err != nil {
+		return err
+	}
+
+	return diff.Print(config.DryRun.Out, filename, current, data)
+}
+
+// readCurrentFile returns the content of the file, a file that does not exist yet is empty
+func readCurrentFile(filename string) ([]byte, error) {
+	current, err := os.ReadFile(filename)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	return current, nil
+}
@@ -0,0 +1,129 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/mitchellh/go-homedir"
+)
+
+// EncryptionProvider is the tool that is used to encrypt the gardenctl configuration file
+type EncryptionProvider string
+
+const (
+	// EncryptionProviderAge encrypts the configuration file with age (https://age-encryption.org)
+	EncryptionProviderAge = EncryptionProvider("age")
+	// EncryptionProviderGPG encrypts the configuration file with GnuPG
+	EncryptionProviderGPG = EncryptionProvider("gpg")
+)
+
+// EnvAgeIdentityFile is the environment variable that holds the path of the age identity file
+// that is used to decrypt an age encrypted configuration file
+const EnvAgeIdentityFile = "GCTL_AGE_IDENTITY_FILE"
+
+const (
+	ageHeader        = "age-encryption.org/v1"
+	ageArmoredHeader = "-----BEGIN AGE ENCRYPTED FILE-----"
+	pgpArmoredHeader = "-----BEGIN PGP MESSAGE-----"
+	gpgCommand       = "gpg"
+	ageCommand       = "age"
+)
+
+// Encryption configures the encryption of the gardenctl configuration file at rest.
+// The encryption section is part of the encrypted content, it is used to re-encrypt the
+// configuration file whenever it is saved. Encrypted files are detected by their header
+// and decrypted transparently when they are loaded.
+type Encryption struct {
+	// Provider is the tool that is used for encryption, either age or gpg
+	Provider EncryptionProvider `yaml:"provider" json:"provider" toml:"provider"`
+	// Recipients is a list of age public keys or GPG key IDs the configuration file is encrypted for
+	Recipients []string `yaml:"recipients" json:"recipients" toml:"recipients"`
+}
+
+// runCommand executes the given command with data as standard input and returns the standard output
+var runCommand = func(data []byte, name string, args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%s failed: %w: %s", name, err, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// Validate checks that the provider is supported and that at least one recipient is configured
+func (e *Encryption) Validate() error {
+	switch e.Provider {
+	case EncryptionProviderAge, EncryptionProviderGPG:
+	default:
+		return fmt.Errorf("encryption provider %q is not supported, must be one of %q or %q", e.Provider, EncryptionProviderAge, EncryptionProviderGPG)
+	}
+
+	if len(e.Recipients) == 0 {
+		return errors.New("at least one encryption recipient is required")
+	}
+
+	return nil
+}
+
+func (e *Encryption) encrypt(data []byte) ([]byte, error) {
+	if err := e.Validate(); err != nil {
+		return nil, err
+	}
+
+	switch e.Provider {
+	case EncryptionProviderAge:
+		args := []string{"--encrypt", "--armor"}
+		for _, r := range e.Recipients {
+			args = append(args, "--recipient", r)
+		}
+
+		return runCommand(data, ageCommand, args...)
+	default:
+		args := []string{"--quiet", "--batch", "--yes", "--armor", "--encrypt"}
+		for _, r := range e.Recipients {
+			args = append(args, "--recipient", r)
+		}
+
+		return runCommand(data, gpgCommand, args...)
+	}
+}
+
+// decrypt decrypts the given data if it has been encrypted with age or gpg, otherwise the data is returned unchanged
+func decrypt(data []byte) ([]byte, error) {
+	content := bytes.TrimSpace(data)
+
+	switch {
+	case bytes.HasPrefix(content, []byte(ageHeader)), bytes.HasPrefix(content, []byte(ageArmoredHeader)):
+		identityFile, ok := os.LookupEnv(EnvAgeIdentityFile)
+		if !ok || identityFile == "" {
+			return nil, fmt.Errorf("the file is encrypted with age, the environment variable %s must point to the identity file", EnvAgeIdentityFile)
+		}
+
+		identityFile, err := homedir.Expand(identityFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve ~ in identity file path: %w", err)
+		}
+
+		return runCommand(data, ageCommand, "--decrypt", "--identity", identityFile)
+	case bytes.HasPrefix(content, []byte(pgpArmoredHeader)):
+		return runCommand(data, gpgCommand, "--quiet", "--batch", "--decrypt")
+	default:
+		return data, nil
+	}
+}
@@ -0,0 +1,136 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/gardener/gardenctl-v2/pkg/config"
+)
+
+var _ = Describe("Encryption", func() {
+	const (
+		header = "-----BEGIN PGP MESSAGE-----\n"
+		footer = "-----END PGP MESSAGE-----\n"
+	)
+
+	var (
+		filename string
+		calls    []string
+		restore  func()
+	)
+
+	BeforeEach(func() {
+		filename = filepath.Join(gardenHomeDir, "encrypted.yaml")
+		calls = nil
+
+		// fake gpg "encrypts" by wrapping the data in an armor header and reversing it
+		restore = config.SetRunCommand(func(data []byte, name string, args ...string) ([]byte, error) {
+			calls = append(calls, name+" "+strings.Join(args, " "))
+
+			switch args[len(args)-1] {
+			case "--decrypt":
+				content := strings.TrimSuffix(strings.TrimPrefix(string(data), header), footer)
+				return []byte(reverse(content)), nil
+			case "unknown":
+				return nil, errors.New("unknown recipient")
+			default:
+				var buf bytes.Buffer
+				buf.WriteString(header)
+				buf.WriteString(reverse(string(data)))
+				buf.WriteString(footer)
+
+				return buf.Bytes(), nil
+			}
+		})
+	})
+
+	AfterEach(func() {
+		restore()
+	})
+
+	It("should encrypt the file when saving and decrypt it when loading", func() {
+		cfg := &config.Config{
+			Filename:   filename,
+			Encryption: &config.Encryption{Provider: config.EncryptionProviderGPG, Recipients: []string{"alice@example.com"}},
+			Gardens:    []config.Garden{{Name: "garden1", Kubeconfig: "/path/to/kubeconfig"}},
+		}
+		Expect(cfg.Save()).To(Succeed())
+
+		data, err := os.ReadFile(filename)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(HavePrefix(header))
+		Expect(string(data)).NotTo(ContainSubstring("/path/to/kubeconfig"))
+
+		loaded, err := config.LoadFromFile(filename)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(loaded.Encryption).To(Equal(cfg.Encryption))
+		Expect(loaded.Gardens).To(Equal(cfg.Gardens))
+
+		Expect(calls).To(Equal([]string{
+			"gpg --quiet --batch --yes --armor --encrypt --recipient alice@example.com",
+			"gpg --quiet --batch --decrypt",
+		}))
+	})
+
+	It("should fail to save if the encryption fails", func() {
+		cfg := &config.Config{
+			Filename:   filename,
+			Encryption: &config.Encryption{Provider: config.EncryptionProviderGPG, Recipients: []string{"unknown"}},
+		}
+		Expect(cfg.Save()).To(MatchError("failed to encrypt file: unknown recipient"))
+	})
+
+	It("should fail to save if the encryption provider is not supported", func() {
+		cfg := &config.Config{
+			Filename:   filename,
+			Encryption: &config.Encryption{Provider: "foo", Recipients: []string{"alice@example.com"}},
+		}
+		Expect(cfg.Save()).To(MatchError(ContainSubstring(`encryption provider "foo" is not supported`)))
+	})
+
+	It("should require the age identity file to decrypt age encrypted files", func() {
+		os.Unsetenv(config.EnvAgeIdentityFile)
+		Expect(os.WriteFile(filename, []byte("age-encryption.org/v1\n..."), 0600)).To(Succeed())
+
+		_, err := config.LoadFromFile(filename)
+		Expect(err).To(MatchError(ContainSubstring("the environment variable GCTL_AGE_IDENTITY_FILE must point to the identity file")))
+	})
+
+	It("should pass the age identity file to age", func() {
+		os.Setenv(config.EnvAgeIdentityFile, "/path/to/identity")
+		defer os.Unsetenv(config.EnvAgeIdentityFile)
+
+		restore()
+		restore = config.SetRunCommand(func(data []byte, name string, args ...string) ([]byte, error) {
+			calls = append(calls, name+" "+strings.Join(args, " "))
+			return []byte("gardens: []\n"), nil
+		})
+
+		Expect(os.WriteFile(filename, []byte("-----BEGIN AGE ENCRYPTED FILE-----\n..."), 0600)).To(Succeed())
+
+		_, err := config.LoadFromFile(filename)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(calls).To(Equal([]string{"age --decrypt --identity /path/to/identity"}))
+	})
+})
+
+func reverse(s string) string {
+	r := []rune(s)
+	for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+		r[i], r[j] = r[j], r[i]
+	}
+
+	return string(r)
+}
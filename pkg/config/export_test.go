@@ -0,0 +1,31 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"net/http"
+)
+
+// SetRunCommand replaces the function that runs the encryption tools and returns a function to restore it
+func SetRunCommand(f func(data []byte, name string, args ...string) ([]byte, error)) func() {
+	orig := runCommand
+	runCommand = f
+
+	return func() {
+		runCommand = orig
+	}
+}
+
+// SetHTTPClient replaces the client that fetches sync sources and returns a function to restore it
+func SetHTTPClient(c *http.Client) func() {
+	orig := httpClient
+	httpClient = c
+
+	return func() {
+		httpClient = orig
+	}
+}
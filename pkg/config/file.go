@@ -0,0 +1,92 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// writeFileAtomic writes data to a temporary file in the directory of filename and renames it to filename,
+// so that the file is either completely written or left unchanged. Symbolic links are resolved first.
+func writeFileAtomic(filename string, data []byte, perm os.FileMode) error {
+	if resolved, err := filepath.EvalSymlinks(filename); err == nil {
+		filename = resolved
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(filename), "."+filepath.Base(filename)+".tmp-*")
+	if err != nil {
+		return err
+	}
+
+	tmpName := f.Name()
+
+	defer func() {
+		// the temporary file no longer exists if it has been renamed successfully
+		_ = os.Remove(tmpName)
+	}()
+
+	if err := f.Chmod(perm); err != nil {
+		f.Close()
+		return err
+	}
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpName, filename)
+}
+
+// backupFilename returns the name of the i-th backup of filename, starting with filename.bak
+func backupFilename(filename string, i int) string {
+	if i == 0 {
+		return filename + ".bak"
+	}
+
+	return fmt.Sprintf("%s.bak.%d", filename, i)
+}
+
+// rotateBackups copies the current content of filename to filename.bak after shifting the existing
+// backups by one (filename.bak becomes filename.bak.1 and so on). At most count backups are kept.
+func rotateBackups(filename string, count int) error {
+	if count <= 0 {
+		return nil
+	}
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	if err := os.Remove(backupFilename(filename, count-1)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	for i := count - 2; i >= 0; i-- {
+		if err := os.Rename(backupFilename(filename, i), backupFilename(filename, i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	return writeFileAtomic(backupFilename(filename, 0), data, 0600)
+}
@@ -0,0 +1,87 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/pelletier/go-toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Format is the file format of a gardenctl configuration file
+type Format string
+
+const (
+	// FormatYAML is used for files with the extension .yaml or .yml and for files with an unknown extension
+	FormatYAML = Format("yaml")
+	// FormatJSON is used for files with the extension .json
+	FormatJSON = Format("json")
+	// FormatTOML is used for files with the extension .toml
+	FormatTOML = Format("toml")
+)
+
+// FormatFromFilename determines the format of a gardenctl configuration file by its extension
+func FormatFromFilename(filename string) Format {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".json":
+		return FormatJSON
+	case ".toml":
+		return FormatTOML
+	default:
+		return FormatYAML
+	}
+}
+
+// String returns the upper case name of the format
+func (f Format) String() string {
+	return strings.ToUpper(string(f))
+}
+
+func (f Format) marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+
+	switch f {
+	case FormatJSON:
+		encoder := json.NewEncoder(&buf)
+		encoder.SetIndent("", "  ")
+
+		if err := encoder.Encode(v); err != nil {
+			return nil, err
+		}
+	case FormatTOML:
+		if err := toml.NewEncoder(&buf).Encode(v); err != nil {
+			return nil, err
+		}
+	default:
+		if err := yaml.NewEncoder(&buf).Encode(v); err != nil {
+			return nil, err
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (f Format) unmarshal(data []byte, v interface{}) error {
+	switch f {
+	case FormatJSON:
+		return json.Unmarshal(data, v)
+	case FormatTOML:
+		return toml.Unmarshal(data, v)
+	default:
+		return yaml.Unmarshal(data, v)
+	}
+}
+
+// formatError wraps err with a message that mentions the format of the given file
+func formatError(action string, filename string, err error) error {
+	return fmt.Errorf("failed to %s as %s: %w", action, FormatFromFilename(filename), err)
+}
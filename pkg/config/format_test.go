@@ -0,0 +1,70 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/pointer"
+
+	"github.com/gardener/gardenctl-v2/pkg/config"
+)
+
+var _ = Describe("Format", func() {
+	DescribeTable("determining the format by the file extension",
+		func(filename string, expected config.Format) {
+			Expect(config.FormatFromFilename(filename)).To(Equal(expected))
+		},
+		Entry("when the extension is .yaml", "gardenctl-v2.yaml", config.FormatYAML),
+		Entry("when the extension is .yml", "gardenctl-v2.yml", config.FormatYAML),
+		Entry("when the extension is .json", "gardenctl-v2.json", config.FormatJSON),
+		Entry("when the extension is .TOML", "gardenctl-v2.TOML", config.FormatTOML),
+		Entry("when the extension is unknown", "gardenctl-v2", config.FormatYAML),
+	)
+
+	DescribeTable("saving and loading the configuration",
+		func(extension string, expectedContent string) {
+			filename := filepath.Join(gardenHomeDir, "gardenctl-format"+extension)
+			cfg := &config.Config{
+				Filename:       filename,
+				LinkKubeconfig: pointer.Bool(false),
+				Gardens: []config.Garden{
+					{
+						Name:       "garden1",
+						Kubeconfig: "/path/to/kubeconfig",
+						Aliases:    []string{"g1"},
+					},
+				},
+				MatchPatterns: []string{"^(?P<shoot>.+)$"},
+			}
+			Expect(cfg.Save()).To(Succeed())
+
+			data, err := os.ReadFile(filename)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(ContainSubstring(expectedContent))
+
+			loaded, err := config.LoadFromFile(filename)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(loaded).To(Equal(cfg))
+		},
+		Entry("when the file is YAML", ".yaml", "- identity: garden1"),
+		Entry("when the file is JSON", ".json", `"identity": "garden1"`),
+		Entry("when the file is TOML", ".toml", "[[gardens]]"),
+	)
+
+	It("should mention the format when decoding fails", func() {
+		filename := filepath.Join(gardenHomeDir, "invalid.json")
+		Expect(os.WriteFile(filename, []byte("{"), 0600)).To(Succeed())
+
+		_, err := config.LoadFromFile(filename)
+		Expect(err).To(MatchError(HavePrefix("failed to decode as JSON")))
+	})
+})
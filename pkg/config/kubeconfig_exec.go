@@ -0,0 +1,114 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// KubeconfigExec configures a command that prints the kubeconfig of a garden cluster to stdout,
+// e.g. to fetch the kubeconfig from a secret store
+type KubeconfigExec struct {
+	// Command is the command to execute
+	Command string `yaml:"command" json:"command" toml:"command"`
+	// Args is a list of arguments that are passed to the command
+	// +optional
+	Args []string `yaml:"args,omitempty" json:"args,omitempty" toml:"args,omitempty"`
+	// CacheTTL is the duration the output of the command is cached, e.g. 8h. The output is not cached if empty
+	// +optional
+	CacheTTL string `yaml:"cacheTTL,omitempty" json:"cacheTTL,omitempty" toml:"cacheTTL,omitempty"`
+}
+
+// Validate checks that a command is set and that the cache TTL is a valid duration
+func (e *KubeconfigExec) Validate() error {
+	if e.Command == "" {
+		return errors.New("command must not be empty")
+	}
+
+	if _, err := e.cacheTTL(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (e *KubeconfigExec) cacheTTL() (time.Duration, error) {
+	if e.CacheTTL == "" {
+		return 0, nil
+	}
+
+	ttl, err := time.ParseDuration(e.CacheTTL)
+	if err != nil {
+		return 0, fmt.Errorf("cacheTTL %q is not a valid duration: %w", e.CacheTTL, err)
+	}
+
+	return ttl, nil
+}
+
+func (e *KubeconfigExec) deepCopy() *KubeconfigExec {
+	if e == nil {
+		return nil
+	}
+
+	out := *e
+	out.Args = append([]string(nil), e.Args...)
+
+	return &out
+}
+
+// cacheFile returns the name of the file the output of the command is cached in.
+// The name depends on the command and its arguments, so that changing them invalidates the cache.
+func (e *KubeconfigExec) cacheFile(cacheDir, name string) string {
+	key := strings.Join(append([]string{name, e.Command}, e.Args...), "\x00")
+	return filepath.Join(cacheDir, fmt.Sprintf("kubeconfig-exec-%x.yaml", sha256.Sum256([]byte(key))))
+}
+
+// output returns the output of the command. If a cache directory is given and a cache TTL is configured,
+// the output is read from the cache as long as it is not expired.
+func (e *KubeconfigExec) output(cacheDir, name string) ([]byte, error) {
+	if err := e.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid kubeconfigExec: %w", err)
+	}
+
+	ttl, _ := e.cacheTTL()
+
+	var cacheFile string
+
+	if cacheDir != "" && ttl > 0 {
+		cacheFile = e.cacheFile(cacheDir, name)
+
+		if stat, err := os.Stat(cacheFile); err == nil && time.Since(stat.ModTime()) < ttl {
+			data, err := os.ReadFile(cacheFile)
+			if err == nil {
+				return data, nil
+			}
+		}
+	}
+
+	data, err := runCommand(nil, e.Command, e.Args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute kubeconfig command: %w", err)
+	}
+
+	if cacheFile != "" {
+		if err := os.MkdirAll(cacheDir, 0700); err != nil {
+			return nil, fmt.Errorf("failed to create cache directory: %w", err)
+		}
+
+		if err := os.WriteFile(cacheFile, data, 0600); err != nil {
+			return nil, fmt.Errorf("failed to write kubeconfig to cache: %w", err)
+		}
+	}
+
+	return data, nil
+}
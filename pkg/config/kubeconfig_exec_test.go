@@ -0,0 +1,156 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/gardener/gardenctl-v2/pkg/config"
+)
+
+var _ = Describe("KubeconfigExec", func() {
+	const kubeconfigData = `apiVersion: v1
+kind: Config
+clusters:
+- name: garden
+  cluster:
+    server: https://api.garden.example.com
+contexts:
+- name: garden
+  context:
+    cluster: garden
+    user: user
+current-context: garden
+users:
+- name: user
+  user:
+    token: secret
+`
+
+	var (
+		cfg      *config.Config
+		cacheDir string
+		calls    []string
+		restore  func()
+	)
+
+	BeforeEach(func() {
+		var err error
+		cacheDir, err = os.MkdirTemp(gardenHomeDir, "cache-*")
+		Expect(err).NotTo(HaveOccurred())
+
+		cfg = &config.Config{
+			CacheDir: cacheDir,
+			Gardens: []config.Garden{
+				{
+					Name: "garden1",
+					KubeconfigExec: &config.KubeconfigExec{
+						Command: "vault",
+						Args:    []string{"read", "garden1"},
+					},
+				},
+			},
+		}
+
+		calls = nil
+		restore = config.SetRunCommand(func(data []byte, name string, args ...string) ([]byte, error) {
+			calls = append(calls, name+" "+strings.Join(args, " "))
+
+			if name == "fail" {
+				return nil, errors.New("permission denied")
+			}
+
+			return []byte(kubeconfigData), nil
+		})
+	})
+
+	AfterEach(func() {
+		restore()
+	})
+
+	It("should load the kubeconfig from the command output", func() {
+		clientConfig, err := cfg.DirectClientConfig("garden1")
+		Expect(err).NotTo(HaveOccurred())
+		restConfig, err := clientConfig.ClientConfig()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(restConfig.Host).To(Equal("https://api.garden.example.com"))
+
+		_, err = cfg.ClientConfig("garden1")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(calls).To(Equal([]string{"vault read garden1", "vault read garden1"}))
+
+		entries, err := os.ReadDir(cacheDir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(entries).To(BeEmpty())
+	})
+
+	It("should cache the command output", func() {
+		cfg.Gardens[0].KubeconfigExec.CacheTTL = "1h"
+
+		for i := 0; i < 2; i++ {
+			_, err := cfg.DirectClientConfig("garden1")
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		Expect(calls).To(Equal([]string{"vault read garden1"}))
+
+		files, err := filepath.Glob(filepath.Join(cacheDir, "kubeconfig-exec-*.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(files).To(HaveLen(1))
+
+		info, err := os.Stat(files[0])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+
+		By("changing the arguments of the command")
+		cfg.Gardens[0].KubeconfigExec.Args = []string{"read", "other"}
+		_, err = cfg.DirectClientConfig("garden1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(calls).To(Equal([]string{"vault read garden1", "vault read other"}))
+	})
+
+	It("should list and purge the cached command output", func() {
+		cfg.Gardens[0].KubeconfigExec.CacheTTL = "1h"
+
+		credentials, err := cfg.CachedCredentials("garden1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(credentials).To(BeEmpty())
+
+		_, err = cfg.DirectClientConfig("garden1")
+		Expect(err).NotTo(HaveOccurred())
+
+		credentials, err = cfg.CachedCredentials("garden1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(credentials).To(HaveLen(1))
+		Expect(credentials[0].Kind).To(Equal(config.CachedCredentialKubeconfigExec))
+		Expect(credentials[0].Expiry).To(BeTemporally("~", time.Now().Add(time.Hour), 5*time.Second))
+		Expect(credentials[0].Expired(time.Now())).To(BeFalse())
+
+		purged, err := cfg.PurgeCachedCredentials("garden1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(purged).To(Equal(credentials))
+		Expect(credentials[0].Filename).NotTo(BeAnExistingFile())
+
+		_, err = cfg.DirectClientConfig("garden1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(calls).To(Equal([]string{"vault read garden1", "vault read garden1"}))
+	})
+
+	It("should fail if the command fails", func() {
+		cfg.Gardens[0].KubeconfigExec.Command = "fail"
+		_, err := cfg.DirectClientConfig("garden1")
+		Expect(err).To(MatchError("failed to load client configuration: failed to execute kubeconfig command: permission denied"))
+	})
+})
@@ -0,0 +1,58 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/gardener/gardenctl-v2/internal/audit"
+)
+
+// Logging configures the logs that gardenctl writes in addition to its output
+type Logging struct {
+	// AuditFile is the path of the audit log. If set, every mutating action of gardenctl, e.g. changes to the
+	// configuration, the target or cluster resources, is appended to the file as JSON line. The path may start
+	// with ~ and contain environment variables
+	// +optional
+	AuditFile string `yaml:"auditFile,omitempty" json:"auditFile,omitempty" toml:"auditFile,omitempty"`
+}
+
+// Validate checks that the path of the audit file can be expanded
+func (l *Logging) Validate() error {
+	if l.AuditFile == "" {
+		return nil
+	}
+
+	if _, err := expandPath(l.AuditFile); err != nil {
+		return fmt.Errorf("audit file %q is invalid: %w", l.AuditFile, err)
+	}
+
+	return nil
+}
+
+// AuditFile returns the expanded path of the audit log or an empty string if the audit log is not enabled
+func (config *Config) AuditFile() string {
+	if config == nil || config.Logging == nil || config.Logging.AuditFile == "" {
+		return ""
+	}
+
+	path, err := expandPath(config.Logging.AuditFile)
+	if err != nil {
+		return ""
+	}
+
+	return path
+}
+
+// audit records a change of the configuration file in the audit log, changes are not recorded in dry-run mode
+func (config *Config) audit(err error) {
+	if config.IsDryRun() {
+		return
+	}
+
+	config.Audit.Log(audit.Entry{Action: "write", Resource: config.Filename}, err)
+}
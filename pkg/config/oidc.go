@@ -0,0 +1,176 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/gardener/gardenctl-v2/internal/oidc"
+)
+
+const (
+	// DefaultOIDCCallbackPort is the port of the local callback listener if no port is configured
+	DefaultOIDCCallbackPort = 8000
+	// oidcRefreshTimeout is the maximum duration to refresh an expired token while loading the client configuration
+	oidcRefreshTimeout = 30 * time.Second
+)
+
+// OIDC configures the login to a garden cluster with an OIDC provider, see gardenctl login
+type OIDC struct {
+	// IssuerURL is the URL of the OIDC provider, it must match the issuer URL configured for the API server
+	IssuerURL string `yaml:"issuerURL" json:"issuerURL" toml:"issuerURL"`
+	// ClientID is the client ID of gardenctl at the OIDC provider
+	ClientID string `yaml:"clientID" json:"clientID" toml:"clientID"`
+	// ClientSecret is only required if the OIDC provider does not support public clients
+	// +optional
+	ClientSecret string `yaml:"clientSecret,omitempty" json:"clientSecret,omitempty" toml:"clientSecret,omitempty"`
+	// ExtraScopes are requested in addition to the openid and offline_access scopes, e.g. email or groups
+	// +optional
+	ExtraScopes []string `yaml:"extraScopes,omitempty" json:"extraScopes,omitempty" toml:"extraScopes,omitempty"`
+	// CallbackPort is the local port the authorization response is sent to, it has to be allowed
+	// as redirect URI http://localhost:<port>/callback by the OIDC provider. Defaults to 8000
+	// +optional
+	CallbackPort int `yaml:"callbackPort,omitempty" json:"callbackPort,omitempty" toml:"callbackPort,omitempty"`
+}
+
+// Validate checks that the issuer URL and the client ID are set
+func (o *OIDC) Validate() error {
+	if o.IssuerURL == "" {
+		return errors.New("issuerURL must not be empty")
+	}
+
+	u, err := url.Parse(o.IssuerURL)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("issuerURL %q must be an https URL", o.IssuerURL)
+	}
+
+	if o.ClientID == "" {
+		return errors.New("clientID must not be empty")
+	}
+
+	if o.CallbackPort < 0 || o.CallbackPort > 65535 {
+		return fmt.Errorf("callbackPort %d is not a valid port", o.CallbackPort)
+	}
+
+	return nil
+}
+
+// GetCallbackPort returns the configured callback port or DefaultOIDCCallbackPort
+func (o *OIDC) GetCallbackPort() int {
+	if o == nil || o.CallbackPort == 0 {
+		return DefaultOIDCCallbackPort
+	}
+
+	return o.CallbackPort
+}
+
+// Provider returns the OIDC provider configuration that is used to request tokens
+func (o *OIDC) Provider() oidc.Provider {
+	return oidc.Provider{
+		IssuerURL:    o.IssuerURL,
+		ClientID:     o.ClientID,
+		ClientSecret: o.ClientSecret,
+		Scopes:       append([]string{"offline_access"}, o.ExtraScopes...),
+	}
+}
+
+func (o *OIDC) deepCopy() *OIDC {
+	if o == nil {
+		return nil
+	}
+
+	out := *o
+	out.ExtraScopes = append([]string(nil), o.ExtraScopes...)
+
+	return &out
+}
+
+// TokenCache returns the cache of the OIDC tokens of the gardens
+func (config *Config) TokenCache() *oidc.TokenCache {
+	return oidc.NewTokenCache(config.TokenDir)
+}
+
+// TokenCacheKey returns the key of the OIDC token of the garden in the token cache
+func (config *Config) TokenCacheKey(name string) (string, error) {
+	garden, err := config.Garden(name)
+	if err != nil {
+		return "", err
+	}
+
+	if garden.ClusterIdentity != "" {
+		return garden.ClusterIdentity, nil
+	}
+
+	rawConfig, err := garden.loadKubeconfig(config.CacheDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to load client configuration: %w", err)
+	}
+
+	return garden.tokenCacheKey(rawConfig)
+}
+
+// tokenCacheKey returns the key of the OIDC token of the garden in the token cache. The tokens are cached by the
+// cluster identity of the garden, so that they are kept if the garden is renamed. If the cluster identity is not
+// configured, the garden cluster is identified by the server of its kubeconfig.
+func (g *Garden) tokenCacheKey(rawConfig *clientcmdapi.Config) (string, error) {
+	if g.ClusterIdentity != "" {
+		return g.ClusterIdentity, nil
+	}
+
+	contextName := rawConfig.CurrentContext
+	if g.Context != "" {
+		contextName = g.Context
+	}
+
+	if context, ok := rawConfig.Contexts[contextName]; ok {
+		if cluster, ok := rawConfig.Clusters[context.Cluster]; ok && cluster.Server != "" {
+			return cluster.Server, nil
+		}
+	}
+
+	return "", fmt.Errorf("failed to identify the cluster of garden %q: the kubeconfig has no server for context %q", g.Name, contextName)
+}
+
+// applyOIDC replaces the credentials of the user of the current context with the cached ID token of the garden.
+// An expired token is refreshed. The kubeconfig is not modified if the garden is not configured for OIDC.
+func (config *Config) applyOIDC(garden *Garden, rawConfig *clientcmdapi.Config) error {
+	if garden.OIDC == nil {
+		return nil
+	}
+
+	if config.TokenDir == "" {
+		return fmt.Errorf("failed to get token of garden %q: the token cache is disabled", garden.Name)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), oidcRefreshTimeout)
+	defer cancel()
+
+	key, err := garden.tokenCacheKey(rawConfig)
+	if err != nil {
+		return err
+	}
+
+	token, err := oidc.ValidToken(ctx, config.TokenCache(), key, garden.OIDC.Provider(), time.Now())
+	if err != nil {
+		if errors.Is(err, oidc.ErrNotLoggedIn) {
+			return fmt.Errorf("failed to get token of garden %q, run gardenctl login %s: %w", garden.Name, garden.Name, err)
+		}
+
+		return err
+	}
+
+	authInfo := clientcmdapi.NewAuthInfo()
+	authInfo.Token = token.IDToken
+
+	return setAuthInfo(garden, rawConfig, authInfo)
+}
@@ -0,0 +1,365 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// pathSegment is a single element of a configuration path like gardens[my-garden]
+type pathSegment struct {
+	// field is the name of the configuration field as used in the config file
+	field string
+	// key selects an element of a list or map. Gardens are selected by identity or alias, all other lists by index
+	key string
+	// hasKey is true if the segment selects an element of a list
+	hasKey bool
+}
+
+func (s pathSegment) String() string {
+	if s.hasKey {
+		return fmt.Sprintf("%s[%s]", s.field, s.key)
+	}
+
+	return s.field
+}
+
+// parsePath splits a configuration path like gardens[my-garden].patterns[0] into its segments.
+// Keys in square brackets are taken verbatim, so that they may contain dots.
+func parsePath(path string) ([]pathSegment, error) {
+	if path == "" {
+		return nil, errors.New("path must not be empty")
+	}
+
+	var (
+		segments []pathSegment
+		segment  pathSegment
+	)
+
+	for i := 0; i < len(path); i++ {
+		switch c := path[i]; c {
+		case '.':
+			if segment.field == "" {
+				return nil, fmt.Errorf("invalid path %q: empty field name at position %d", path, i)
+			}
+
+			segments = append(segments, segment)
+			segment = pathSegment{}
+		case '[':
+			if segment.field == "" || segment.hasKey {
+				return nil, fmt.Errorf("invalid path %q: unexpected [ at position %d", path, i)
+			}
+
+			end := strings.IndexByte(path[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid path %q: missing ]", path)
+			}
+
+			segment.key = path[i+1 : i+end]
+			segment.hasKey = true
+			i += end
+
+			if i+1 < len(path) && path[i+1] != '.' {
+				return nil, fmt.Errorf("invalid path %q: expected . after ] at position %d", path, i)
+			}
+		default:
+			if segment.hasKey {
+				return nil, fmt.Errorf("invalid path %q: unexpected %q at position %d", path, c, i)
+			}
+
+			segment.field += string(c)
+		}
+	}
+
+	if segment.field == "" {
+		return nil, fmt.Errorf("invalid path %q: empty field name at the end", path)
+	}
+
+	return append(segments, segment), nil
+}
+
+// Get returns the value of the configuration field addressed by the given path,
+// e.g. gardens[my-garden].context, gardens[my-garden].labels[env] or matchPatterns[0].
+// Gardens are selected by identity or alias.
+func (config *Config) Get(path string) (interface{}, error) {
+	v, _, err := config.lookup(path, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if v.Kind() == reflect.Ptr && v.IsNil() {
+		return nil, nil
+	}
+
+	return v.Interface(), nil
+}
+
+// Set parses the given value and assigns it to the configuration field addressed by the given path.
+// Lists of strings are set from a comma separated value and maps from comma separated key=value pairs.
+// An empty value clears the field or removes the map entry.
+// Missing intermediate objects like the encryption settings are created on demand.
+func (config *Config) Set(path, value string) error {
+	v, commit, err := config.lookup(path, true)
+	if err != nil {
+		return err
+	}
+
+	if err := setValue(v, value); err != nil {
+		return fmt.Errorf("failed to set %s: %w", path, err)
+	}
+
+	if commit != nil {
+		commit()
+	}
+
+	return nil
+}
+
+// lookup resolves the given path to the addressed field. If create is true, nil pointers are
+// allocated on the way and an index equal to the length of a list appends a new element.
+// Map elements are not addressable, for them a copy is returned together with a function
+// that writes the copy back to the map.
+func (config *Config) lookup(path string, create bool) (reflect.Value, func(), error) {
+	segments, err := parsePath(path)
+	if err != nil {
+		return reflect.Value{}, nil, err
+	}
+
+	var commit func()
+
+	v := reflect.ValueOf(config).Elem()
+
+	for i, segment := range segments {
+		if v.Kind() == reflect.Ptr {
+			switch {
+			case !v.IsNil():
+				v = v.Elem()
+			case create:
+				v.Set(reflect.New(v.Type().Elem()))
+				v = v.Elem()
+			default:
+				// continue with a zero value to return the zero value of the addressed field
+				v = reflect.New(v.Type().Elem()).Elem()
+			}
+		}
+
+		if v.Kind() != reflect.Struct {
+			return reflect.Value{}, nil, fmt.Errorf("invalid path %q: %s has no field %q", path, pathString(segments[:i]), segment.field)
+		}
+
+		field, ok := fieldByTag(v, segment.field)
+		if !ok {
+			return reflect.Value{}, nil, fmt.Errorf("invalid path %q: unknown field %q", path, segment.field)
+		}
+
+		if create && v.Type() == reflect.TypeOf(Garden{}) && segment.field == "identity" {
+			return reflect.Value{}, nil, errors.New("the identity of a garden cannot be set, use gardenctl config rename-garden instead")
+		}
+
+		v = field
+
+		if !segment.hasKey {
+			continue
+		}
+
+		if v.Kind() == reflect.Map {
+			v, commit = mapElement(v, segment.key)
+			continue
+		}
+
+		if v.Kind() != reflect.Slice {
+			return reflect.Value{}, nil, fmt.Errorf("invalid path %q: %s is not a list", path, segment.field)
+		}
+
+		index, err := config.elementIndex(v, segment.key, create)
+		if err != nil {
+			return reflect.Value{}, nil, fmt.Errorf("invalid path %q: %w", path, err)
+		}
+
+		if index == v.Len() {
+			v.Set(reflect.Append(v, reflect.Zero(v.Type().Elem())))
+		}
+
+		v = v.Index(index)
+	}
+
+	return v, commit, nil
+}
+
+// mapElement returns an addressable copy of the element of the map m with the given key
+// and a function that stores the copy in the map or removes the key if the copy is empty.
+func mapElement(m reflect.Value, key string) (reflect.Value, func()) {
+	k := reflect.ValueOf(key)
+	elem := reflect.New(m.Type().Elem()).Elem()
+
+	if existing := m.MapIndex(k); existing.IsValid() {
+		elem.Set(existing)
+	}
+
+	return elem, func() {
+		if elem.IsZero() {
+			m.SetMapIndex(k, reflect.Value{})
+			return
+		}
+
+		if m.IsNil() {
+			m.Set(reflect.MakeMap(m.Type()))
+		}
+
+		m.SetMapIndex(k, elem)
+	}
+}
+
+// elementIndex returns the index of the list element selected by key. Gardens are selected by identity or alias.
+func (config *Config) elementIndex(list reflect.Value, key string, create bool) (int, error) {
+	if list.Type() == reflect.TypeOf([]Garden{}) {
+		i, ok := config.IndexOfGarden(key)
+		if !ok {
+			i, ok = config.IndexOfGardenAlias(key)
+		}
+
+		if !ok {
+			return 0, fmt.Errorf("garden %q is not defined in gardenctl configuration", key)
+		}
+
+		return i, nil
+	}
+
+	index, err := strconv.Atoi(key)
+	if err != nil {
+		return 0, fmt.Errorf("index %q is not a number", key)
+	}
+
+	length := list.Len()
+	if create {
+		length++
+	}
+
+	if index < 0 || index >= length {
+		return 0, fmt.Errorf("index %d is out of range", index)
+	}
+
+	return index, nil
+}
+
+// fieldByTag returns the exported field of the struct v whose yaml name matches the given name
+func fieldByTag(v reflect.Value, name string) (reflect.Value, bool) {
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+
+		tag := strings.Split(f.Tag.Get("yaml"), ",")[0]
+		if tag != "" && tag != "-" && tag == name {
+			return v.Field(i), true
+		}
+	}
+
+	return reflect.Value{}, false
+}
+
+func setValue(v reflect.Value, value string) error {
+	if v.Kind() == reflect.Ptr {
+		if kind := v.Type().Elem().Kind(); kind == reflect.Struct || kind == reflect.Slice {
+			return errors.New("only single values, lists and maps of strings can be set")
+		}
+
+		if value == "" {
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%q is not a boolean", value)
+		}
+
+		v.SetBool(b)
+	case reflect.Int:
+		i, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", value)
+		}
+
+		v.SetInt(int64(i))
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.String {
+			return errors.New("only lists of strings can be set")
+		}
+
+		if value == "" {
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+
+		values := strings.Split(value, ",")
+		list := reflect.MakeSlice(v.Type(), len(values), len(values))
+
+		for i, s := range values {
+			list.Index(i).SetString(strings.TrimSpace(s))
+		}
+
+		v.Set(list)
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String || v.Type().Elem().Kind() != reflect.String {
+			return errors.New("only maps of strings can be set")
+		}
+
+		if value == "" {
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+
+		m := reflect.MakeMap(v.Type())
+
+		for _, pair := range strings.Split(value, ",") {
+			kv := strings.SplitN(pair, "=", 2)
+			if len(kv) != 2 || strings.TrimSpace(kv[0]) == "" {
+				return fmt.Errorf("%q is not a key=value pair", pair)
+			}
+
+			m.SetMapIndex(reflect.ValueOf(strings.TrimSpace(kv[0])), reflect.ValueOf(strings.TrimSpace(kv[1])))
+		}
+
+		v.Set(m)
+	default:
+		return errors.New("only single values, lists and maps of strings can be set")
+	}
+
+	return nil
+}
+
+func pathString(segments []pathSegment) string {
+	if len(segments) == 0 {
+		return "the configuration"
+	}
+
+	s := make([]string, len(segments))
+	for i, segment := range segments {
+		s[i] = segment.String()
+	}
+
+	return strings.Join(s, ".")
+}
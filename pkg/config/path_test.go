@@ -0,0 +1,138 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/types"
+	"k8s.io/utils/pointer"
+
+	"github.com/gardener/gardenctl-v2/pkg/config"
+)
+
+var _ = Describe("Path", func() {
+	var cfg *config.Config
+
+	BeforeEach(func() {
+		cfg = &config.Config{
+			Gardens: []config.Garden{
+				{
+					Name:       "garden1",
+					Kubeconfig: "/path/to/kubeconfig",
+					Context:    "context1",
+					Aliases:    []string{"g1"},
+					Patterns:   []string{"^shoot--(?P<project>.+)--(?P<shoot>.+)$"},
+					Labels:     map[string]string{"env": "prod"},
+				},
+				{
+					Name:       "garden.example.com",
+					Kubeconfig: "/path/to/kubeconfig",
+				},
+			},
+			MatchPatterns: []string{"^(?P<garden>[^/]+)/(?P<shoot>.+)$"},
+		}
+	})
+
+	DescribeTable("Get",
+		func(path string, matcher types.GomegaMatcher) {
+			value, err := cfg.Get(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(value).To(matcher)
+		},
+		Entry("when getting a garden field", "gardens[garden1].context", Equal("context1")),
+		Entry("when getting a garden by alias", "gardens[g1].kubeconfig", Equal("/path/to/kubeconfig")),
+		Entry("when the garden identity contains dots", "gardens[garden.example.com].identity", Equal("garden.example.com")),
+		Entry("when getting a list element", "gardens[garden1].patterns[0]", Equal("^shoot--(?P<project>.+)--(?P<shoot>.+)$")),
+		Entry("when getting a list", "matchPatterns", Equal([]string{"^(?P<garden>[^/]+)/(?P<shoot>.+)$"})),
+		Entry("when getting a label", "gardens[garden1].labels[env]", Equal("prod")),
+		Entry("when getting an unset label", "gardens[garden1].labels[team]", Equal("")),
+		Entry("when getting an unset pointer", "linkKubeconfig", BeNil()),
+		Entry("when getting a field of an unset object", "encryption.provider", BeEquivalentTo("")),
+		Entry("when getting an unset number", "backups", Equal(0)),
+	)
+
+	DescribeTable("Get with an invalid path",
+		func(path string, matcher types.GomegaMatcher) {
+			_, err := cfg.Get(path)
+			Expect(err).To(matcher)
+		},
+		Entry("when the path is empty", "", MatchError("path must not be empty")),
+		Entry("when the field is unknown", "foo", MatchError(`invalid path "foo": unknown field "foo"`)),
+		Entry("when the garden is unknown", "gardens[foo].context", MatchError(`invalid path "gardens[foo].context": garden "foo" is not defined in gardenctl configuration`)),
+		Entry("when the index is out of range", "matchPatterns[1]", MatchError(`invalid path "matchPatterns[1]": index 1 is out of range`)),
+		Entry("when the index is not a number", "matchPatterns[a]", MatchError(`invalid path "matchPatterns[a]": index "a" is not a number`)),
+		Entry("when the field is not a list", "backups[0]", MatchError(`invalid path "backups[0]": backups is not a list`)),
+		Entry("when the field is not an object", "backups.foo", MatchError(`invalid path "backups.foo": backups has no field "foo"`)),
+		Entry("when the bracket is not closed", "gardens[garden1", MatchError(`invalid path "gardens[garden1": missing ]`)),
+		Entry("when a dot is missing", "gardens[garden1]context", MatchError(ContainSubstring("expected . after ]"))),
+		Entry("when a field name is empty", "gardens[garden1].", MatchError(ContainSubstring("empty field name"))),
+	)
+
+	Describe("Set", func() {
+		It("should set a garden field", func() {
+			Expect(cfg.Set("gardens[g1].context", "context2")).To(Succeed())
+			Expect(cfg.Gardens[0].Context).To(Equal("context2"))
+		})
+
+		It("should set a list from a comma separated value", func() {
+			Expect(cfg.Set("gardens[garden.example.com].aliases", "dev, landscape-dev")).To(Succeed())
+			Expect(cfg.Gardens[1].Aliases).To(Equal([]string{"dev", "landscape-dev"}))
+		})
+
+		It("should clear a list", func() {
+			Expect(cfg.Set("gardens[garden1].aliases", "")).To(Succeed())
+			Expect(cfg.Gardens[0].Aliases).To(BeNil())
+		})
+
+		It("should replace and append list elements", func() {
+			Expect(cfg.Set("matchPatterns[0]", "^(?P<garden>.+):(?P<shoot>.+)$")).To(Succeed())
+			Expect(cfg.Set("matchPatterns[1]", "^(?P<shoot>.+)$")).To(Succeed())
+			Expect(cfg.MatchPatterns).To(Equal([]string{"^(?P<garden>.+):(?P<shoot>.+)$", "^(?P<shoot>.+)$"}))
+			Expect(cfg.Set("matchPatterns[3]", "foo")).To(MatchError(ContainSubstring("index 3 is out of range")))
+		})
+
+		It("should set and remove labels", func() {
+			Expect(cfg.Set("gardens[garden.example.com].labels[env]", "dev")).To(Succeed())
+			Expect(cfg.Gardens[1].Labels).To(Equal(map[string]string{"env": "dev"}))
+			Expect(cfg.Set("gardens[garden1].labels[env]", "")).To(Succeed())
+			Expect(cfg.Gardens[0].Labels).To(BeEmpty())
+		})
+
+		It("should set a map from comma separated key=value pairs", func() {
+			Expect(cfg.Set("gardens[garden1].labels", "env=dev, team=core")).To(Succeed())
+			Expect(cfg.Gardens[0].Labels).To(Equal(map[string]string{"env": "dev", "team": "core"}))
+			Expect(cfg.Set("gardens[garden1].labels", "env")).To(MatchError(`failed to set gardens[garden1].labels: "env" is not a key=value pair`))
+		})
+
+		It("should set numbers and booleans", func() {
+			Expect(cfg.Set("backups", "3")).To(Succeed())
+			Expect(cfg.Backups).To(Equal(3))
+			Expect(cfg.Set("linkKubeconfig", "false")).To(Succeed())
+			Expect(cfg.LinkKubeconfig).To(Equal(pointer.Bool(false)))
+			Expect(cfg.Set("linkKubeconfig", "")).To(Succeed())
+			Expect(cfg.LinkKubeconfig).To(BeNil())
+		})
+
+		It("should create missing objects", func() {
+			Expect(cfg.Set("encryption.provider", "age")).To(Succeed())
+			Expect(cfg.Encryption).To(Equal(&config.Encryption{Provider: config.EncryptionProviderAge}))
+		})
+
+		It("should fail to set invalid values", func() {
+			Expect(cfg.Set("backups", "many")).To(MatchError(`failed to set backups: "many" is not a number`))
+			Expect(cfg.Set("linkKubeconfig", "maybe")).To(MatchError(`failed to set linkKubeconfig: "maybe" is not a boolean`))
+			Expect(cfg.Set("gardens[garden1]", "foo")).To(MatchError("failed to set gardens[garden1]: only single values, lists and maps of strings can be set"))
+			Expect(cfg.Set("encryption", "age")).To(MatchError("failed to set encryption: only single values, lists and maps of strings can be set"))
+		})
+
+		It("should not change the identity of a garden", func() {
+			Expect(cfg.Set("gardens[garden1].identity", "foo")).To(MatchError(ContainSubstring("use gardenctl config rename-garden instead")))
+		})
+	})
+})
@@ -0,0 +1,72 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"fmt"
+	"sort"
+)
+
+// patternPresets maps the names of the built-in pattern presets to their match patterns.
+// The patterns of a preset are used like global match patterns, see Config.MatchPatterns.
+var patternPresets = map[string][]string{
+	// dashboard matches shoot URLs of the Gardener dashboard, e.g. https://dashboard.garden.example.com/namespace/garden-dev/shoots/my-shoot
+	"dashboard": {
+		"^https://dashboard\\.[^/]+/namespace/(?P<namespace>[^/]+)/shoots/(?P<shoot>[^/?#]+)(?:[/?#].*)?$",
+	},
+	// kubectl-context matches the context names of shoot kubeconfigs, e.g. garden-dev--my-shoot-external
+	"kubectl-context": {
+		"^(?P<namespace>garden-[^/]+?)--(?P<shoot>[^/]+?)(?:-external|-internal)?$",
+	},
+	// shorthand matches the garden, project and shoot separated by slashes, e.g. my-garden/dev/my-shoot
+	"shorthand": {
+		"^(?P<garden>[^/]+)/(?P<project>[^/]+)/(?P<shoot>[^/]+)$",
+	},
+	// technical-id matches the technical ID of a shoot, which is the name of its namespace in the seed, e.g. shoot--dev--my-shoot
+	"technical-id": {
+		"^shoot--(?P<project>[^/]+?)--(?P<shoot>[^/]+)$",
+	},
+}
+
+// PatternPresetNames returns the sorted names of the built-in pattern presets
+func PatternPresetNames() []string {
+	names := make([]string, 0, len(patternPresets))
+
+	for name := range patternPresets {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// PatternPreset returns the match patterns of the built-in pattern preset with the given name
+func PatternPreset(name string) ([]string, error) {
+	patterns, ok := patternPresets[name]
+	if !ok {
+		return nil, fmt.Errorf("pattern preset %q is not defined, must be one of %v", name, PatternPresetNames())
+	}
+
+	return patterns, nil
+}
+
+// globalMatchPatterns returns the match patterns followed by the patterns of the selected pattern presets
+func (config *Config) globalMatchPatterns() ([]string, error) {
+	patterns := append([]string{}, config.MatchPatterns...)
+
+	for _, name := range config.PatternPresets {
+		presetPatterns, err := PatternPreset(name)
+		if err != nil {
+			return nil, err
+		}
+
+		patterns = appendUnique(patterns, presetPatterns...)
+	}
+
+	return patterns, nil
+}
@@ -0,0 +1,125 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Confirmation asks the user to confirm the changes to protected gardens. The answer for a garden is remembered,
+// so that a command that makes several changes to the same garden asks only once.
+type Confirmation struct {
+	// Confirm asks the given yes/no question and returns true if the user confirmed it
+	Confirm func(question string) (bool, error)
+
+	mutex   sync.Mutex
+	answers map[string]bool
+}
+
+// confirm returns the remembered answer for the garden or asks the question
+func (c *Confirmation) confirm(gardenName, question string) (bool, error) {
+	if c == nil || c.Confirm == nil {
+		return false, nil
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if answer, ok := c.answers[gardenName]; ok {
+		return answer, nil
+	}
+
+	answer, err := c.Confirm(question)
+	if err != nil {
+		return false, err
+	}
+
+	if c.answers == nil {
+		c.answers = map[string]bool{}
+	}
+
+	c.answers[gardenName] = answer
+
+	return answer, nil
+}
+
+// MutationDeniedError is returned if a change to a garden, its seeds or its shoots has been denied by the
+// protected or denyMutations settings of the garden
+type MutationDeniedError struct {
+	// Garden is the identity of the garden
+	Garden string
+	// Action describes the denied change, e.g. patch Shoot garden-foo/bar
+	Action string
+	// Reason explains why the change has been denied
+	Reason string
+}
+
+var _ error = &MutationDeniedError{}
+
+func (e *MutationDeniedError) Error() string {
+	return fmt.Sprintf("%s denied for garden %q: %s", e.Action, e.Garden, e.Reason)
+}
+
+// IsMutationDenied returns true if the error has been caused by a change that has been denied for a protected garden
+func IsMutationDenied(err error) bool {
+	var deniedErr *MutationDeniedError
+
+	return errors.As(err, &deniedErr)
+}
+
+// ProtectsGarden returns true if changes to the garden with the given identity or alias require a confirmation
+// or are denied
+func (config *Config) ProtectsGarden(name string) bool {
+	garden, err := config.Garden(name)
+	if err != nil {
+		return false
+	}
+
+	return garden.Protected || garden.DenyMutations
+}
+
+// ConfirmMutation checks whether the given action may change resources of the garden, its seeds or its shoots.
+// It returns a MutationDeniedError if the garden denies all changes or if the user did not confirm the change
+// to a protected garden. Nothing is changed in dry-run mode, hence the action is allowed without confirmation.
+func (config *Config) ConfirmMutation(gardenName, action string) error {
+	if config.IsDryRun() {
+		return nil
+	}
+
+	garden, err := config.Garden(gardenName)
+	if err != nil {
+		return nil
+	}
+
+	switch {
+	case garden.DenyMutations:
+		return &MutationDeniedError{
+			Garden: garden.Name,
+			Action: action,
+			Reason: "changes are disabled by denyMutations in the gardenctl configuration",
+		}
+	case garden.Protected:
+		question := fmt.Sprintf("Garden %q is protected. Do you really want to %s?", garden.Name, action)
+
+		confirmed, err := config.Confirmation.confirm(garden.Name, question)
+		if err != nil {
+			return err
+		}
+
+		if !confirmed {
+			return &MutationDeniedError{
+				Garden: garden.Name,
+				Action: action,
+				Reason: "the garden is protected and the change has not been confirmed",
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config_test
+
+import (
+	"bytes"
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/gardener/gardenctl-v2/pkg/config"
+)
+
+var _ = Describe("Protection", func() {
+	var (
+		cfg       *config.Config
+		questions []string
+		answer    bool
+	)
+
+	BeforeEach(func() {
+		questions = nil
+		answer = true
+
+		cfg = &config.Config{
+			Gardens: []config.Garden{
+				{Name: "dev"},
+				{Name: "prod", Aliases: []string{"live"}, Protected: true},
+				{Name: "canary", DenyMutations: true},
+			},
+			Confirmation: &config.Confirmation{Confirm: func(question string) (bool, error) {
+				questions = append(questions, question)
+				return answer, nil
+			}},
+		}
+	})
+
+	It("should allow changes to gardens that are not protected", func() {
+		Expect(cfg.ProtectsGarden("dev")).To(BeFalse())
+		Expect(cfg.ConfirmMutation("dev", "patch Shoot garden-foo/bar")).To(Succeed())
+		Expect(cfg.ConfirmMutation("unknown", "patch Shoot garden-foo/bar")).To(Succeed())
+		Expect(questions).To(BeEmpty())
+	})
+
+	It("should ask only once for confirmation of changes to protected gardens", func() {
+		Expect(cfg.ProtectsGarden("live")).To(BeTrue())
+		Expect(cfg.ConfirmMutation("live", "patch Shoot garden-foo/bar")).To(Succeed())
+		Expect(cfg.ConfirmMutation("prod", "delete Shoot garden-foo/bar")).To(Succeed())
+		Expect(questions).To(Equal([]string{`Garden "prod" is protected. Do you really want to patch Shoot garden-foo/bar?`}))
+	})
+
+	It("should deny changes to protected gardens that have not been confirmed", func() {
+		answer = false
+
+		err := cfg.ConfirmMutation("prod", "patch Shoot garden-foo/bar")
+		Expect(err).To(MatchError(`patch Shoot garden-foo/bar denied for garden "prod": the garden is protected and the change has not been confirmed`))
+		Expect(config.IsMutationDenied(err)).To(BeTrue())
+	})
+
+	It("should deny changes to protected gardens without confirmation", func() {
+		cfg.Confirmation = nil
+
+		Expect(config.IsMutationDenied(cfg.ConfirmMutation("prod", "patch Shoot garden-foo/bar"))).To(BeTrue())
+	})
+
+	It("should return the error of the confirmation", func() {
+		cfg.Confirmation.Confirm = func(string) (bool, error) {
+			return false, errors.New("failed to read answer")
+		}
+
+		err := cfg.ConfirmMutation("prod", "patch Shoot garden-foo/bar")
+		Expect(err).To(MatchError("failed to read answer"))
+		Expect(config.IsMutationDenied(err)).To(BeFalse())
+	})
+
+	It("should deny all changes to gardens with denyMutations", func() {
+		Expect(cfg.ProtectsGarden("canary")).To(BeTrue())
+		Expect(cfg.ConfirmMutation("canary", "create Bastion garden-foo/cli-xyz")).To(MatchError(`create Bastion garden-foo/cli-xyz denied for garden "canary": changes are disabled by denyMutations in the gardenctl configuration`))
+		Expect(questions).To(BeEmpty())
+	})
+
+	It("should allow changes in dry-run mode", func() {
+		cfg.DryRun = &config.DryRun{Strategy: config.DryRunClient, Out: &bytes.Buffer{}}
+
+		Expect(cfg.ConfirmMutation("prod", "patch Shoot garden-foo/bar")).To(Succeed())
+		Expect(cfg.ConfirmMutation("canary", "patch Shoot garden-foo/bar")).To(Succeed())
+		Expect(questions).To(BeEmpty())
+	})
+})
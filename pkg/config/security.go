@@ -0,0 +1,33 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+// Security configures how gardenctl handles credentials
+type Security struct {
+	// EphemeralKubeconfigs prevents that kubeconfigs of the targeted clusters are written to disk. gardenctl kubectl
+	// passes the kubeconfig to kubectl in memory and commands that require a kubeconfig file, e.g. kubectl-env, fail.
+	// +optional
+	EphemeralKubeconfigs *bool `yaml:"ephemeralKubeconfigs,omitempty" json:"ephemeralKubeconfigs,omitempty" toml:"ephemeralKubeconfigs,omitempty"`
+	// CacheClientConfigs caches the client configs of the targeted clusters, including their credentials, in the
+	// session directory, so that they can be used in offline mode. It has no effect if EphemeralKubeconfigs is enabled.
+	// +optional
+	CacheClientConfigs *bool `yaml:"cacheClientConfigs,omitempty" json:"cacheClientConfigs,omitempty" toml:"cacheClientConfigs,omitempty"`
+}
+
+// EphemeralKubeconfigs returns true if kubeconfigs of the targeted clusters must not be written to disk
+func (config *Config) EphemeralKubeconfigs() bool {
+	return config != nil && config.Security != nil && config.Security.EphemeralKubeconfigs != nil && *config.Security.EphemeralKubeconfigs
+}
+
+// CacheClientConfigs returns true if the client configs of the targeted clusters are cached for the offline mode
+func (config *Config) CacheClientConfigs() bool {
+	if config.EphemeralKubeconfigs() {
+		return false
+	}
+
+	return config != nil && config.Security != nil && config.Security.CacheClientConfigs != nil && *config.Security.CacheClientConfigs
+}
@@ -0,0 +1,527 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// SyncSourceType is the kind of location a sync source is fetched from
+type SyncSourceType string
+
+const (
+	// SyncSourceTypeHTTPS fetches the configuration file from an https:// URL
+	SyncSourceTypeHTTPS = SyncSourceType("https")
+	// SyncSourceTypeOCI fetches the configuration file from an OCI artifact, e.g. oci://registry.example.com/gardenctl/config:latest
+	SyncSourceTypeOCI = SyncSourceType("oci")
+	// SyncSourceTypeGit fetches the configuration file from a Git repository, e.g. git+https://github.com/example/gardens.git
+	SyncSourceTypeGit = SyncSourceType("git")
+)
+
+// DefaultSyncSourcePath is the path of the configuration file in a Git repository if no path is configured
+const DefaultSyncSourcePath = "gardenctl-v2.yaml"
+
+// syncDirectory is the directory next to the configuration file where the synced configuration files are stored
+const syncDirectory = "sync"
+
+// maxSyncSourceSize is the maximum size of a synced configuration file
+const maxSyncSourceSize = 10 << 20
+
+var (
+	syncSourceNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`)
+	challengeParamRegexp = regexp.MustCompile(`(\w+)="([^"]*)"`)
+)
+
+// httpClient is the client used to fetch sync sources via HTTPS and OCI
+var httpClient = http.DefaultClient
+
+// SyncSource configures a centrally managed gardenctl configuration file, e.g. the garden list of a team.
+// The file is fetched with gardenctl config sync and stored next to the configuration file.
+// Synced files are merged into the configuration like additional config sources: the configuration file
+// takes precedence, followed by the sync sources in the order they are configured.
+type SyncSource struct {
+	// Name identifies the sync source, it is used as name of the synced file
+	Name string `yaml:"name" json:"name" toml:"name"`
+	// URL is the location of the configuration file. Supported schemes are https://, oci:// and git+https:// or git+ssh://
+	URL string `yaml:"url" json:"url" toml:"url"`
+	// Ref is the branch or tag of a Git repository. The default branch is used if empty
+	// +optional
+	Ref string `yaml:"ref,omitempty" json:"ref,omitempty" toml:"ref,omitempty"`
+	// Path is the path of the configuration file in a Git repository or the title of the layer of an OCI artifact.
+	// It defaults to gardenctl-v2.yaml for Git repositories and to the first layer for OCI artifacts
+	// +optional
+	Path string `yaml:"path,omitempty" json:"path,omitempty" toml:"path,omitempty"`
+}
+
+// SyncResult describes the changes of the gardens defined by a sync source
+type SyncResult struct {
+	// Added is the list of gardens that have not been defined by the previously synced file
+	Added []string
+	// Removed is the list of gardens that are no longer defined by the sync source
+	Removed []string
+}
+
+// Type returns the type of the sync source determined by the scheme of its URL
+func (s *SyncSource) Type() (SyncSourceType, error) {
+	switch {
+	case strings.HasPrefix(s.URL, "https://"):
+		return SyncSourceTypeHTTPS, nil
+	case strings.HasPrefix(s.URL, "oci://"):
+		return SyncSourceTypeOCI, nil
+	case strings.HasPrefix(s.URL, "git+https://"), strings.HasPrefix(s.URL, "git+ssh://"):
+		return SyncSourceTypeGit, nil
+	default:
+		return "", fmt.Errorf("url %q is not supported, must start with https://, oci://, git+https:// or git+ssh://", s.URL)
+	}
+}
+
+// Validate checks that the sync source has a valid name and a supported URL
+func (s *SyncSource) Validate() error {
+	if !syncSourceNameRegexp.MatchString(s.Name) {
+		return fmt.Errorf("name %q is invalid, it must consist of alphanumeric characters, '.', '_' or '-'", s.Name)
+	}
+
+	t, err := s.Type()
+	if err != nil {
+		return err
+	}
+
+	if s.Ref != "" && t != SyncSourceTypeGit {
+		return errors.New("ref is only supported for Git repositories")
+	}
+
+	if s.Path != "" && t == SyncSourceTypeHTTPS {
+		return errors.New("path is not supported for https URLs")
+	}
+
+	return nil
+}
+
+// format returns the format of the fetched configuration file
+func (s *SyncSource) format() Format {
+	if s.Path != "" {
+		return FormatFromFilename(s.Path)
+	}
+
+	if u, err := url.Parse(s.URL); err == nil {
+		return FormatFromFilename(u.Path)
+	}
+
+	return FormatYAML
+}
+
+// SyncSource returns the sync source with the given name
+func (config *Config) SyncSource(name string) (*SyncSource, error) {
+	for i, s := range config.SyncSources {
+		if s.Name == name {
+			return &config.SyncSources[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("sync source %q is not defined in gardenctl configuration", name)
+}
+
+// SyncedFilename returns the name of the file the given sync source is stored in.
+// Synced files are stored in the sync directory next to the configuration file.
+func (config *Config) SyncedFilename(source SyncSource) string {
+	return filepath.Join(filepath.Dir(config.Filename), syncDirectory, source.Name+".yaml")
+}
+
+// syncedFilenames returns the synced files of all sync sources in descending order of precedence
+func (config *Config) syncedFilenames() []string {
+	filenames := make([]string, 0, len(config.SyncSources))
+	for _, s := range config.SyncSources {
+		filenames = append(filenames, config.SyncedFilename(s))
+	}
+
+	return filenames
+}
+
+// Sync fetches the configuration file of the given sync source and stores it in the sync directory.
+// The fetched file must be a valid gardenctl configuration, only its gardens and match patterns are used.
+// The returned result lists the gardens that have been added or removed compared to the previously synced file.
+// The loaded configuration itself is not changed, the synced gardens are merged the next time it is loaded.
+// In dry-run mode, the differences to the previously synced file are printed instead.
+func (config *Config) Sync(ctx context.Context, source SyncSource) (*SyncResult, error) {
+	if err := source.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid sync source: %w", err)
+	}
+
+	data, err := source.fetch(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", source.URL, err)
+	}
+
+	synced := &Config{}
+	if err := source.format().unmarshal(data, synced); err != nil {
+		return nil, fmt.Errorf("failed to decode %s as %s: %w", source.URL, source.format(), err)
+	}
+
+	synced = &Config{
+		Gardens:       synced.Gardens,
+		MatchPatterns: synced.MatchPatterns,
+	}
+
+	if err := synced.validatePatterns(); err != nil {
+		return nil, err
+	}
+
+	filename := config.SyncedFilename(source)
+
+	previous, err := readFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read previously synced file: %w", err)
+	}
+
+	data, err = FormatYAML.marshal(synced)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", source.URL, err)
+	}
+
+	if config.IsDryRun() {
+		if err := config.printFileDiff(filename, data); err != nil {
+			return nil, err
+		}
+	} else {
+		if err := os.MkdirAll(filepath.Dir(filename), 0700); err != nil {
+			return nil, fmt.Errorf("failed to create sync directory: %w", err)
+		}
+
+		if err := writeFileAtomic(filename, data, 0600); err != nil {
+			return nil, fmt.Errorf("failed to write synced file: %w", err)
+		}
+	}
+
+	return &SyncResult{
+		Added:   difference(synced.GardenNames(), previous.GardenNames()),
+		Removed: difference(previous.GardenNames(), synced.GardenNames()),
+	}, nil
+}
+
+// difference returns the sorted elements of a that are not contained in b
+func difference(a, b []string) []string {
+	result := []string{}
+
+	for _, s := range a {
+		if !contains(b, s) {
+			result = append(result, s)
+		}
+	}
+
+	sort.Strings(result)
+
+	return result
+}
+
+func (s *SyncSource) fetch(ctx context.Context) ([]byte, error) {
+	t, err := s.Type()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t {
+	case SyncSourceTypeOCI:
+		return s.fetchOCI(ctx)
+	case SyncSourceTypeGit:
+		return s.fetchGit()
+	default:
+		resp, err := httpGet(ctx, s.URL, nil)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		return readBody(resp)
+	}
+}
+
+// fetchGit clones the Git repository with depth 1 and reads the configuration file from the working tree
+func (s *SyncSource) fetchGit() ([]byte, error) {
+	dir, err := os.MkdirTemp("", "gardenctl-sync-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	args := []string{"clone", "--quiet", "--depth", "1"}
+	if s.Ref != "" {
+		args = append(args, "--branch", s.Ref)
+	}
+
+	args = append(args, strings.TrimPrefix(s.URL, "git+"), dir)
+
+	if _, err := runCommand(nil, "git", args...); err != nil {
+		return nil, err
+	}
+
+	p := s.Path
+	if p == "" {
+		p = DefaultSyncSourcePath
+	}
+
+	return os.ReadFile(filepath.Join(dir, filepath.FromSlash(p)))
+}
+
+// ociManifest is the subset of an OCI image manifest that is needed to fetch a layer
+type ociManifest struct {
+	Layers []struct {
+		Digest      string            `json:"digest"`
+		Annotations map[string]string `json:"annotations"`
+	} `json:"layers"`
+}
+
+// fetchOCI pulls the manifest of the OCI artifact and returns the content of the layer titled with the
+// configured path, or of the first layer. Registries that require anonymous bearer tokens are supported.
+func (s *SyncSource) fetchOCI(ctx context.Context) ([]byte, error) {
+	registry, repository, reference, err := parseOCIReference(strings.TrimPrefix(s.URL, "oci://"))
+	if err != nil {
+		return nil, err
+	}
+
+	base := fmt.Sprintf("https://%s/v2/%s", registry, repository)
+	header := http.Header{"Accept": []string{
+		"application/vnd.oci.image.manifest.v1+json",
+		"application/vnd.docker.distribution.manifest.v2+json",
+	}}
+
+	resp, err := httpGet(ctx, base+"/manifests/"+reference, header)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := readBody(resp)
+	resp.Body.Close()
+
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := ociManifest{}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to decode manifest: %w", err)
+	}
+
+	digest := ""
+
+	for _, layer := range manifest.Layers {
+		if s.Path == "" || layer.Annotations["org.opencontainers.image.title"] == s.Path {
+			digest = layer.Digest
+			break
+		}
+	}
+
+	if digest == "" {
+		if s.Path != "" {
+			return nil, fmt.Errorf("artifact does not contain a layer titled %q", s.Path)
+		}
+
+		return nil, errors.New("artifact does not contain any layer")
+	}
+
+	resp, err = httpGet(ctx, base+"/blobs/"+digest, http.Header{"Authorization": header["Authorization"]})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err = readBody(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if hex := strings.TrimPrefix(digest, "sha256:"); hex != digest && fmt.Sprintf("%x", sha256.Sum256(data)) != hex {
+		return nil, fmt.Errorf("digest of layer does not match %s", digest)
+	}
+
+	return data, nil
+}
+
+// parseOCIReference splits a reference like registry.example.com/gardenctl/config:v1 into its parts.
+// The tag defaults to latest, digests are supported in the form repository@sha256:...
+func parseOCIReference(ref string) (registry, repository, reference string, err error) {
+	parts := strings.SplitN(ref, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", "", fmt.Errorf("invalid OCI reference %q, expected registry/repository[:tag]", ref)
+	}
+
+	registry, repository = parts[0], parts[1]
+
+	if i := strings.IndexByte(repository, '@'); i >= 0 {
+		return registry, repository[:i], repository[i+1:], nil
+	}
+
+	reference = "latest"
+
+	if i := strings.LastIndexByte(repository, ':'); i > strings.LastIndexByte(repository, '/') {
+		repository, reference = repository[:i], repository[i+1:]
+	}
+
+	return registry, repository, reference, nil
+}
+
+// httpGet sends a GET request and returns the response if the status code is 200. If the server
+// requests a bearer token, an anonymous token is fetched from the announced realm and the
+// request is repeated. The Authorization header is stored in header to be reused by subsequent requests.
+func httpGet(ctx context.Context, rawURL string, header http.Header) (*http.Response, error) {
+	do := func() (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		for k, v := range header {
+			req.Header[k] = v
+		}
+
+		return httpClient.Do(req)
+	}
+
+	resp, err := do()
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized && header != nil {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+
+		token, err := fetchBearerToken(ctx, challenge)
+		if err != nil {
+			return nil, err
+		}
+
+		header.Set("Authorization", "Bearer "+token)
+
+		if resp, err = do(); err != nil {
+			return nil, err
+		}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("GET %s failed: %s", rawURL, resp.Status)
+	}
+
+	return resp, nil
+}
+
+// fetchBearerToken requests an anonymous token as described by a challenge like
+// Bearer realm="https://auth.example.com/token",service="registry.example.com",scope="repository:foo:pull"
+func fetchBearerToken(ctx context.Context, challenge string) (string, error) {
+	parts := strings.SplitN(challenge, " ", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") {
+		return "", fmt.Errorf("unsupported authentication challenge %q", challenge)
+	}
+
+	values := url.Values{}
+	realm := ""
+
+	for _, m := range challengeParamRegexp.FindAllStringSubmatch(parts[1], -1) {
+		if m[1] == "realm" {
+			realm = m[2]
+		} else {
+			values.Set(m[1], m[2])
+		}
+	}
+
+	if realm == "" {
+		return "", fmt.Errorf("authentication challenge %q does not contain a realm", challenge)
+	}
+
+	u, err := url.Parse(realm)
+	if err != nil {
+		return "", fmt.Errorf("invalid realm %q: %w", realm, err)
+	}
+
+	u.RawQuery = values.Encode()
+
+	resp, err := httpGet(ctx, u.String(), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := readBody(resp)
+	if err != nil {
+		return "", err
+	}
+
+	token := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	if err := json.Unmarshal(data, &token); err != nil {
+		return "", fmt.Errorf("failed to decode token: %w", err)
+	}
+
+	if token.Token != "" {
+		return token.Token, nil
+	}
+
+	return token.AccessToken, nil
+}
+
+func readBody(resp *http.Response) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSyncSourceSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response of %s: %w", resp.Request.URL, err)
+	}
+
+	if len(data) > maxSyncSourceSize {
+		return nil, fmt.Errorf("%s exceeds the maximum size of %d bytes", resp.Request.URL, maxSyncSourceSize)
+	}
+
+	return data, nil
+}
+
+// FetchPatterns downloads a list of patterns from the given https:// URL. The patterns are expected
+// one per line, empty lines and lines starting with # are ignored. All patterns must be valid garden patterns.
+func FetchPatterns(ctx context.Context, rawURL string) ([]string, error) {
+	if !strings.HasPrefix(rawURL, "https://") {
+		return nil, fmt.Errorf("url %q is not supported, must start with https://", rawURL)
+	}
+
+	resp, err := httpGet(ctx, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := readBody(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	patterns := []string{}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if err := ValidatePattern(line); err != nil {
+			return nil, fmt.Errorf("pattern %q %w", line, err)
+		}
+
+		patterns = append(patterns, line)
+	}
+
+	return patterns, nil
+}
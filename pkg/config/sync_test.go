@@ -0,0 +1,254 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/gardener/gardenctl-v2/pkg/config"
+)
+
+var _ = Describe("Sync", func() {
+	const gardens = `gardens:
+- identity: garden1
+  kubeconfig: /shared/garden1.yaml
+- identity: garden2
+  kubeconfig: /shared/garden2.yaml
+`
+
+	var (
+		dir      string
+		cfg      *config.Config
+		server   *httptest.Server
+		handler  http.HandlerFunc
+		restore  func()
+		ctx      context.Context
+		filename string
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp(gardenHomeDir, "sync-*")
+		Expect(err).NotTo(HaveOccurred())
+
+		filename = filepath.Join(dir, "gardenctl-v2.yaml")
+		cfg = &config.Config{
+			Filename: filename,
+			Gardens: []config.Garden{
+				{Name: "garden1", Context: "personal", Aliases: []string{"g1"}},
+			},
+		}
+		ctx = context.Background()
+
+		server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			handler(w, r)
+		}))
+		restore = config.SetHTTPClient(server.Client())
+	})
+
+	AfterEach(func() {
+		restore()
+		server.Close()
+	})
+
+	It("should fetch a configuration file via https and merge it when loading", func() {
+		handler = func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.URL.Path).To(Equal("/gardenctl-v2.yaml"))
+			fmt.Fprint(w, gardens)
+		}
+		source := config.SyncSource{Name: "team", URL: server.URL + "/gardenctl-v2.yaml"}
+		cfg.SyncSources = []config.SyncSource{source}
+		Expect(cfg.Save()).To(Succeed())
+
+		result, err := cfg.Sync(ctx, source)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(&config.SyncResult{Added: []string{"garden1", "garden2"}, Removed: []string{}}))
+		Expect(cfg.SyncedFilename(source)).To(Equal(filepath.Join(dir, "sync", "team.yaml")))
+
+		loaded, err := config.LoadFromFile(filename)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(loaded.Gardens).To(Equal([]config.Garden{
+			{Name: "garden1", Kubeconfig: "/shared/garden1.yaml", Context: "personal", Aliases: []string{"g1"}},
+			{Name: "garden2", Kubeconfig: "/shared/garden2.yaml"},
+		}))
+	})
+
+	It("should report added and removed gardens", func() {
+		content := gardens
+		handler = func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, content)
+		}
+		source := config.SyncSource{Name: "team", URL: server.URL + "/gardens"}
+
+		_, err := cfg.Sync(ctx, source)
+		Expect(err).NotTo(HaveOccurred())
+
+		content = `{"gardens": [{"identity": "garden2"}, {"identity": "garden3"}]}`
+		result, err := cfg.Sync(ctx, source)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(&config.SyncResult{Added: []string{"garden3"}, Removed: []string{"garden1"}}))
+	})
+
+	It("should not store invalid configuration files", func() {
+		handler = func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, "gardens:\n- identity: garden1\n  patterns:\n  - (\n")
+		}
+		source := config.SyncSource{Name: "team", URL: server.URL + "/gardenctl-v2.yaml"}
+
+		_, err := cfg.Sync(ctx, source)
+		Expect(err).To(MatchError(HavePrefix("invalid patterns")))
+		Expect(cfg.SyncedFilename(source)).NotTo(BeAnExistingFile())
+	})
+
+	It("should fail if the server returns an error", func() {
+		handler = func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		source := config.SyncSource{Name: "team", URL: server.URL + "/gardenctl-v2.yaml"}
+
+		_, err := cfg.Sync(ctx, source)
+		Expect(err).To(MatchError(ContainSubstring("404 Not Found")))
+	})
+
+	It("should fetch patterns", func() {
+		handler = func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, "# shoots\n^shoot--(?P<project>.+)--(?P<shoot>.+)$\n\n  ^ns:(?P<namespace>.+)$  \n")
+		}
+
+		patterns, err := config.FetchPatterns(ctx, server.URL+"/patterns")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(patterns).To(Equal([]string{"^shoot--(?P<project>.+)--(?P<shoot>.+)$", "^ns:(?P<namespace>.+)$"}))
+
+		handler = func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, "^(?P<garden>.+)$\n")
+		}
+
+		_, err = config.FetchPatterns(ctx, server.URL+"/patterns")
+		Expect(err).To(MatchError(`pattern "^(?P<garden>.+)$" contains an invalid subexpression "garden"`))
+	})
+
+	Describe("OCI artifacts", func() {
+		var (
+			layer    string
+			digest   string
+			manifest string
+			token    string
+		)
+
+		BeforeEach(func() {
+			layer = gardens
+			digest = fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(layer)))
+			manifest = fmt.Sprintf(`{"layers": [
+  {"digest": "sha256:0000", "annotations": {"org.opencontainers.image.title": "README.md"}},
+  {"digest": %q, "annotations": {"org.opencontainers.image.title": "gardenctl-v2.yaml"}}
+]}`, digest)
+			token = ""
+
+			handler = func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/token" {
+					Expect(r.URL.Query().Get("scope")).To(Equal("repository:gardenctl/config:pull"))
+					fmt.Fprint(w, `{"token": "secret"}`)
+
+					return
+				}
+
+				if r.Header.Get("Authorization") != "Bearer secret" {
+					w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="https://%s/token",service="registry",scope="repository:gardenctl/config:pull"`, r.Host))
+					w.WriteHeader(http.StatusUnauthorized)
+
+					return
+				}
+
+				token = "used"
+
+				switch r.URL.Path {
+				case "/v2/gardenctl/config/manifests/v1":
+					fmt.Fprint(w, manifest)
+				case "/v2/gardenctl/config/blobs/" + digest:
+					fmt.Fprint(w, layer)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}
+		})
+
+		It("should fetch the layer with the configured title", func() {
+			source := config.SyncSource{Name: "team", URL: "oci://" + strings.TrimPrefix(server.URL, "https://") + "/gardenctl/config:v1", Path: "gardenctl-v2.yaml"}
+
+			result, err := cfg.Sync(ctx, source)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Added).To(Equal([]string{"garden1", "garden2"}))
+			Expect(token).To(Equal("used"))
+		})
+
+		It("should fail if the digest does not match", func() {
+			layer = "gardens: []\n"
+			source := config.SyncSource{Name: "team", URL: "oci://" + strings.TrimPrefix(server.URL, "https://") + "/gardenctl/config:v1", Path: "gardenctl-v2.yaml"}
+
+			_, err := cfg.Sync(ctx, source)
+			Expect(err).To(MatchError(ContainSubstring("digest of layer does not match " + digest)))
+		})
+
+		It("should fail if no layer has the configured title", func() {
+			source := config.SyncSource{Name: "team", URL: "oci://" + strings.TrimPrefix(server.URL, "https://") + "/gardenctl/config:v1", Path: "gardens.yaml"}
+
+			_, err := cfg.Sync(ctx, source)
+			Expect(err).To(MatchError(ContainSubstring(`artifact does not contain a layer titled "gardens.yaml"`)))
+		})
+	})
+
+	It("should clone Git repositories", func() {
+		var args []string
+
+		defer config.SetRunCommand(func(data []byte, name string, a ...string) ([]byte, error) {
+			Expect(name).To(Equal("git"))
+			args = a
+			dir := a[len(a)-1]
+			Expect(os.MkdirAll(filepath.Join(dir, "config"), 0700)).To(Succeed())
+
+			return nil, os.WriteFile(filepath.Join(dir, "config", "gardens.yaml"), []byte(gardens), 0600)
+		})()
+
+		source := config.SyncSource{Name: "team", URL: "git+ssh://git@github.com/example/gardens.git", Ref: "main", Path: "config/gardens.yaml"}
+
+		result, err := cfg.Sync(ctx, source)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Added).To(Equal([]string{"garden1", "garden2"}))
+		Expect(args[:6]).To(Equal([]string{"clone", "--quiet", "--depth", "1", "--branch", "main"}))
+		Expect(args[6]).To(Equal("ssh://git@github.com/example/gardens.git"))
+	})
+
+	DescribeTable("validating sync sources",
+		func(source config.SyncSource, errorMessage string) {
+			err := source.Validate()
+			if errorMessage == "" {
+				Expect(err).NotTo(HaveOccurred())
+			} else {
+				Expect(err).To(MatchError(errorMessage))
+			}
+		},
+		Entry("when the url is https", config.SyncSource{Name: "team", URL: "https://example.com/gardens.yaml"}, ""),
+		Entry("when the url is an OCI artifact", config.SyncSource{Name: "team", URL: "oci://registry.example.com/gardens:v1", Path: "gardens.yaml"}, ""),
+		Entry("when the url is a Git repository", config.SyncSource{Name: "team", URL: "git+https://github.com/example/gardens.git", Ref: "v1"}, ""),
+		Entry("when the name is empty", config.SyncSource{URL: "https://example.com/gardens.yaml"}, `name "" is invalid, it must consist of alphanumeric characters, '.', '_' or '-'`),
+		Entry("when the name contains a slash", config.SyncSource{Name: "../team", URL: "https://example.com/gardens.yaml"}, `name "../team" is invalid, it must consist of alphanumeric characters, '.', '_' or '-'`),
+		Entry("when the url is http", config.SyncSource{Name: "team", URL: "http://example.com/gardens.yaml"}, `url "http://example.com/gardens.yaml" is not supported, must start with https://, oci://, git+https:// or git+ssh://`),
+		Entry("when a ref is set for https", config.SyncSource{Name: "team", URL: "https://example.com/gardens.yaml", Ref: "main"}, "ref is only supported for Git repositories"),
+		Entry("when a path is set for https", config.SyncSource{Name: "team", URL: "https://example.com/gardens.yaml", Path: "gardens.yaml"}, "path is not supported for https URLs"),
+	)
+})
@@ -0,0 +1,72 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Templates configures the library of shoot templates that is used by gardenctl shoot create
+type Templates struct {
+	// Directory is a directory that contains shoot templates. The template <name> is read from the file <name>.yaml
+	// in this directory. A leading ~ and environment variables in the form $VAR or ${VAR} are expanded
+	// +optional
+	Directory string `yaml:"directory,omitempty" json:"directory,omitempty" toml:"directory,omitempty"`
+	// Registry is the prefix of OCI repositories that contain shoot templates, e.g. ghcr.io/my-org/shoot-templates.
+	// The template <name>[:<tag>] is pulled from the repository <registry>/<name>, the tag defaults to latest.
+	// Templates in the directory take precedence over templates in the registry
+	// +optional
+	Registry string `yaml:"registry,omitempty" json:"registry,omitempty" toml:"registry,omitempty"`
+}
+
+// Validate checks that the registry is a repository prefix without scheme and tag
+func (t *Templates) Validate() error {
+	if t == nil || t.Registry == "" {
+		return nil
+	}
+
+	if strings.Contains(t.Registry, "://") {
+		return fmt.Errorf("registry %q must not contain a scheme", t.Registry)
+	}
+
+	// the host may contain a port, tags and digests are part of the template names
+	parts := strings.SplitN(t.Registry, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" || strings.HasSuffix(parts[1], "/") || strings.ContainsAny(parts[1], ":@ ") {
+		return fmt.Errorf("registry %q must be a repository prefix like ghcr.io/my-org/shoot-templates", t.Registry)
+	}
+
+	return nil
+}
+
+// TemplateDirectory returns the expanded directory of the shoot templates, it is empty if no directory is configured
+func (config *Config) TemplateDirectory() (string, error) {
+	if config == nil || config.Templates == nil || config.Templates.Directory == "" {
+		return "", nil
+	}
+
+	dir, err := expandPath(config.Templates.Directory)
+	if err != nil {
+		return "", fmt.Errorf("failed to expand template directory %q: %w", config.Templates.Directory, err)
+	}
+
+	if dir == "" {
+		return "", errors.New("the template directory expands to an empty path")
+	}
+
+	return dir, nil
+}
+
+// TemplateRegistry returns the prefix of the OCI repositories of the shoot templates, it is empty if no registry is configured
+func (config *Config) TemplateRegistry() string {
+	if config == nil || config.Templates == nil {
+		return ""
+	}
+
+	return config.Templates.Registry
+}
@@ -0,0 +1,428 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// Severity describes how severe a Diagnostic is
+type Severity string
+
+const (
+	// SeverityError is used for issues that prevent gardenctl from working correctly
+	SeverityError = Severity("error")
+	// SeverityWarning is used for issues that might lead to unexpected behavior
+	SeverityWarning = Severity("warning")
+)
+
+// Diagnostic describes a single issue found while validating the gardenctl configuration
+type Diagnostic struct {
+	// Severity is the severity of the issue
+	Severity Severity `yaml:"severity" json:"severity"`
+	// Garden is the name of the garden the issue relates to
+	// +optional
+	Garden string `yaml:"garden,omitempty" json:"garden,omitempty"`
+	// Field is the path of the configuration field the issue relates to
+	Field string `yaml:"field" json:"field"`
+	// Message is a human readable description of the issue
+	Message string `yaml:"message" json:"message"`
+}
+
+// Diagnostics is a list of issues found while validating the gardenctl configuration
+type Diagnostics []Diagnostic
+
+// HasErrors returns true if at least one of the diagnostics has the severity error
+func (d Diagnostics) HasErrors() bool {
+	for _, diagnostic := range d {
+		if diagnostic.Severity == SeverityError {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Validate checks the configuration for duplicate garden names, colliding aliases,
+// unreadable or invalid kubeconfigs, invalid sync sources, invalid bastion CIDRs and invalid patterns. It does not stop at the first
+// issue but returns all diagnostics that have been found.
+func (config *Config) Validate() Diagnostics {
+	diagnostics := Diagnostics{}
+
+	add := func(severity Severity, garden, field, format string, a ...interface{}) {
+		diagnostics = append(diagnostics, Diagnostic{
+			Severity: severity,
+			Garden:   garden,
+			Field:    field,
+			Message:  fmt.Sprintf(format, a...),
+		})
+	}
+
+	// names maps a garden name or alias to the name of the garden that defines it
+	names := map[string]string{}
+
+	for i, g := range config.Gardens {
+		field := fmt.Sprintf("gardens[%d]", i)
+
+		if g.Name == "" {
+			add(SeverityError, "", field+".identity", "garden identity must not be empty")
+		} else if _, ok := names[g.Name]; ok {
+			add(SeverityError, g.Name, field+".identity", "garden identity %q is defined more than once", g.Name)
+		} else {
+			names[g.Name] = g.Name
+		}
+
+		for j, alias := range g.Aliases {
+			aliasField := fmt.Sprintf("%s.aliases[%d]", field, j)
+
+			if alias == "" {
+				add(SeverityError, g.Name, aliasField, "alias must not be empty")
+				continue
+			}
+
+			if owner, ok := names[alias]; ok {
+				add(SeverityError, g.Name, aliasField, "alias %q collides with garden %q", alias, owner)
+				continue
+			}
+
+			names[alias] = g.Name
+		}
+
+		switch {
+		case countKubeconfigSources(g) > 1:
+			add(SeverityError, g.Name, field, "only one of kubeconfig, kubeconfigData and kubeconfigExec must be set")
+		case g.KubeconfigExec != nil:
+			if err := g.KubeconfigExec.Validate(); err != nil {
+				add(SeverityError, g.Name, field+".kubeconfigExec", "kubeconfigExec is invalid: %v", err)
+			}
+		case g.KubeconfigData != "":
+			if _, err := g.loadKubeconfigData(); err != nil {
+				add(SeverityError, g.Name, field+".kubeconfigData", "kubeconfigData is not a valid kubeconfig: %v", err)
+			}
+		case g.Kubeconfig == "":
+			add(SeverityError, g.Name, field+".kubeconfig", "kubeconfig path must not be empty")
+		default:
+			if err := checkReadable(g.Kubeconfig); err != nil {
+				add(SeverityError, g.Name, field+".kubeconfig", "kubeconfig %q is not readable: %v", g.Kubeconfig, err)
+			}
+		}
+
+		if g.CredentialPlugin != "" {
+			if _, err := config.CredentialPlugin(g.CredentialPlugin); err != nil {
+				add(SeverityError, g.Name, field+".credentialPlugin", "%v", err)
+			}
+		}
+
+		if g.OIDC != nil {
+			if g.CredentialPlugin != "" {
+				add(SeverityError, g.Name, field, "only one of credentialPlugin and oidc must be set")
+			} else if err := g.OIDC.Validate(); err != nil {
+				add(SeverityError, g.Name, field+".oidc", "oidc is invalid: %v", err)
+			}
+		}
+
+		if g.DashboardURL != "" {
+			if u, err := url.Parse(g.DashboardURL); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+				add(SeverityError, g.Name, field+".dashboardURL", "dashboardURL %q must be an absolute http or https URL", g.DashboardURL)
+			}
+		}
+
+		for _, k := range sortedKeys(g.Labels) {
+			labelField := fmt.Sprintf("%s.labels[%s]", field, k)
+
+			if errs := validation.IsQualifiedName(k); len(errs) > 0 {
+				add(SeverityError, g.Name, labelField, "label key %q is invalid: %s", k, strings.Join(errs, "; "))
+			}
+
+			if errs := validation.IsValidLabelValue(g.Labels[k]); len(errs) > 0 {
+				add(SeverityError, g.Name, labelField, "label value %q is invalid: %s", g.Labels[k], strings.Join(errs, "; "))
+			}
+		}
+
+		for j, p := range g.Patterns {
+			patternField := fmt.Sprintf("%s.patterns[%d]", field, j)
+
+			if err := ValidatePattern(p); err != nil {
+				add(SeverityError, g.Name, patternField, "pattern %v", err)
+			} else if !hasNamedSubexp(p) {
+				add(SeverityWarning, g.Name, patternField, "pattern %q does not contain any named capturing group and will never select a target", p)
+			}
+		}
+	}
+
+	if config.DefaultGarden != "" {
+		if _, ok := names[config.DefaultGarden]; !ok {
+			add(SeverityError, "", "defaultGarden", "default garden %q is not defined in gardenctl configuration", config.DefaultGarden)
+		}
+	}
+
+	if config.Encryption != nil {
+		if err := config.Encryption.Validate(); err != nil {
+			add(SeverityError, "", "encryption", "%v", err)
+		}
+	}
+
+	if config.Bastion != nil {
+		for i, cidr := range config.Bastion.IngressCIDRs {
+			field := fmt.Sprintf("bastion.ingressCIDRs[%d]", i)
+
+			_, ipNet, err := net.ParseCIDR(cidr)
+			if err != nil {
+				add(SeverityError, "", field, "CIDR %q is invalid: %v", cidr, err)
+			} else if ones, _ := ipNet.Mask.Size(); ones == 0 {
+				add(SeverityWarning, "", field, "CIDR %q allows access to bastions from any address", cidr)
+			}
+		}
+	}
+
+	if config.Completion != nil {
+		if err := config.Completion.Validate(); err != nil {
+			add(SeverityError, "", "completion.cacheTTL", "%v", err)
+		}
+	}
+
+	if config.Directories != nil {
+		if err := config.Directories.Validate(); err != nil {
+			add(SeverityError, "", "directories", "%v", err)
+		}
+	}
+
+	if config.Cleanup != nil {
+		if err := config.Cleanup.Validate(); err != nil {
+			add(SeverityError, "", "cleanup.retention", "%v", err)
+		}
+	}
+
+	if config.Logging != nil {
+		if err := config.Logging.Validate(); err != nil {
+			add(SeverityError, "", "logging.auditFile", "%v", err)
+		}
+	}
+
+	if config.ClientPolicy != nil {
+		if err := config.ClientPolicy.Validate(); err != nil {
+			add(SeverityError, "", "clientPolicy", "%v", err)
+		}
+	}
+
+	if config.Templates != nil {
+		if err := config.Templates.Validate(); err != nil {
+			add(SeverityError, "", "templates.registry", "%v", err)
+		}
+	}
+
+	credentialPluginNames := map[string]bool{}
+
+	for i, p := range config.CredentialPlugins {
+		field := fmt.Sprintf("credentialPlugins[%d]", i)
+
+		if err := p.Validate(); err != nil {
+			add(SeverityError, "", field, "credential plugin is invalid: %v", err)
+		} else if credentialPluginNames[p.Name] {
+			add(SeverityError, "", field+".name", "credential plugin %q is defined more than once", p.Name)
+		}
+
+		credentialPluginNames[p.Name] = true
+	}
+
+	syncSourceNames := map[string]bool{}
+
+	for i, s := range config.SyncSources {
+		field := fmt.Sprintf("syncSources[%d]", i)
+
+		if err := s.Validate(); err != nil {
+			add(SeverityError, "", field, "sync source is invalid: %v", err)
+		} else if syncSourceNames[s.Name] {
+			add(SeverityError, "", field+".name", "sync source %q is defined more than once", s.Name)
+		}
+
+		syncSourceNames[s.Name] = true
+	}
+
+	for i, p := range config.MatchPatterns {
+		field := fmt.Sprintf("matchPatterns[%d]", i)
+
+		if err := ValidateMatchPattern(p); err != nil {
+			add(SeverityError, "", field, "pattern %v", err)
+		} else if !hasNamedSubexp(p) {
+			add(SeverityWarning, "", field, "pattern %q does not contain any named capturing group and will never select a target", p)
+		}
+	}
+
+	for i, name := range config.PatternPresets {
+		field := fmt.Sprintf("patternPresets[%d]", i)
+
+		if _, err := PatternPreset(name); err != nil {
+			add(SeverityError, "", field, "%v", err)
+		} else if contains(config.PatternPresets[:i], name) {
+			add(SeverityWarning, "", field, "pattern preset %q is selected more than once", name)
+		}
+	}
+
+	return diagnostics
+}
+
+// validateAddedPatterns checks the patterns of the given persistent configuration that have not been loaded from the
+// primary config file, i.e. the patterns that are about to be added to it. Patterns that already exist in the file or
+// that are inherited from additional config sources are not checked, so that they do not prevent unrelated changes.
+// They are reported by Validate instead.
+func (config *Config) validateAddedPatterns(persistent *Config) error {
+	loaded := &Config{}
+
+	if len(config.loaded) > 0 {
+		// if the loaded content cannot be decoded, all patterns are checked
+		_ = FormatFromFilename(config.Filename).unmarshal(config.loaded, loaded)
+	}
+
+	added := &Config{}
+
+	for _, g := range persistent.Gardens {
+		var existing []string
+		if i, ok := indexOfGarden(loaded.Gardens, g.Name); ok {
+			existing = loaded.Gardens[i].Patterns
+		}
+
+		var patterns []string
+
+		for _, p := range g.Patterns {
+			if !contains(existing, p) {
+				patterns = append(patterns, p)
+			}
+		}
+
+		if len(patterns) > 0 {
+			added.Gardens = append(added.Gardens, Garden{Name: g.Name, Patterns: patterns})
+		}
+	}
+
+	for _, p := range persistent.MatchPatterns {
+		if !contains(loaded.MatchPatterns, p) {
+			added.MatchPatterns = append(added.MatchPatterns, p)
+		}
+	}
+
+	return added.validatePatterns()
+}
+
+// validatePatterns checks all garden patterns and match patterns and returns an error that reports every invalid pattern
+func (config *Config) validatePatterns() error {
+	var invalid []string
+
+	for _, g := range config.Gardens {
+		for _, p := range g.Patterns {
+			if err := ValidatePattern(p); err != nil {
+				invalid = append(invalid, fmt.Sprintf("pattern %q of garden %q %v", p, g.Name, err))
+			}
+		}
+	}
+
+	for _, p := range config.MatchPatterns {
+		if err := ValidateMatchPattern(p); err != nil {
+			invalid = append(invalid, fmt.Sprintf("match pattern %q %v", p, err))
+		}
+	}
+
+	if len(invalid) > 0 {
+		return fmt.Errorf("invalid patterns: %s", strings.Join(invalid, "; "))
+	}
+
+	return nil
+}
+
+// ValidatePattern checks that the given garden pattern is a valid regular expression
+// and that only supported named capturing groups are used. The message of the
+// returned error does not contain the pattern itself, callers are expected to
+// prefix it with a reference to the pattern.
+func ValidatePattern(pattern string) error {
+	return validatePattern(pattern, supportedPatternKeys)
+}
+
+// ValidateMatchPattern checks that the given global match pattern is a valid regular expression
+// and that only supported named capturing groups are used. See ValidatePattern for details.
+func ValidateMatchPattern(pattern string) error {
+	return validatePattern(pattern, supportedMatchPatternKeys)
+}
+
+func validatePattern(pattern string, keys []PatternKey) error {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("is not a valid regular expression: %w", err)
+	}
+
+	// unnamed capturing groups, e.g. for an optional prefix, are accepted for compatibility and ignored when matching
+	for _, name := range re.SubexpNames()[1:] {
+		if name == "" {
+			continue
+		}
+
+		if !isSupportedPatternKey(keys, PatternKey(name)) {
+			return fmt.Errorf("contains an invalid subexpression %q", name)
+		}
+	}
+
+	return nil
+}
+
+func isSupportedPatternKey(keys []PatternKey, key PatternKey) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasNamedSubexp(pattern string) bool {
+	for _, name := range regexp.MustCompile(pattern).SubexpNames() {
+		if name != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func countKubeconfigSources(g Garden) int {
+	count := 0
+
+	for _, set := range []bool{g.Kubeconfig != "", g.KubeconfigData != "", g.KubeconfigExec != nil} {
+		if set {
+			count++
+		}
+	}
+
+	return count
+}
+
+func checkReadable(filename string) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+
+	return f.Close()
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
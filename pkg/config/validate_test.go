@@ -0,0 +1,398 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/types"
+	"k8s.io/utils/pointer"
+
+	"github.com/gardener/gardenctl-v2/pkg/config"
+)
+
+var _ = Describe("Validate", func() {
+	var (
+		kubeconfigFile string
+		cfg            *config.Config
+	)
+
+	BeforeEach(func() {
+		kubeconfigFile = filepath.Join(gardenHomeDir, "kubeconfig.yaml")
+		Expect(os.WriteFile(kubeconfigFile, []byte("apiVersion: v1\nkind: Config\n"), 0600)).To(Succeed())
+
+		cfg = &config.Config{
+			Gardens: []config.Garden{
+				{
+					Name:       "garden1",
+					Kubeconfig: kubeconfigFile,
+					Aliases:    []string{"g1"},
+					Patterns:   []string{"^shoot--(?P<project>.+)--(?P<shoot>.+)$"},
+				},
+				{
+					Name:       "garden2",
+					Kubeconfig: kubeconfigFile,
+					Aliases:    []string{"g2"},
+				},
+			},
+		}
+	})
+
+	It("should not return any diagnostics for a valid configuration", func() {
+		diagnostics := cfg.Validate()
+		Expect(diagnostics).To(BeEmpty())
+		Expect(diagnostics.HasErrors()).To(BeFalse())
+	})
+
+	It("should report duplicate garden identities", func() {
+		cfg.Gardens[1].Name = "garden1"
+		diagnostics := cfg.Validate()
+		Expect(diagnostics).To(ConsistOf(config.Diagnostic{
+			Severity: config.SeverityError,
+			Garden:   "garden1",
+			Field:    "gardens[1].identity",
+			Message:  `garden identity "garden1" is defined more than once`,
+		}))
+		Expect(diagnostics.HasErrors()).To(BeTrue())
+	})
+
+	It("should report aliases colliding with other gardens", func() {
+		cfg.Gardens[1].Aliases = []string{"g1", "garden1"}
+		diagnostics := cfg.Validate()
+		Expect(diagnostics).To(HaveLen(2))
+		Expect(diagnostics[0].Field).To(Equal("gardens[1].aliases[0]"))
+		Expect(diagnostics[0].Message).To(Equal(`alias "g1" collides with garden "garden1"`))
+		Expect(diagnostics[1].Field).To(Equal("gardens[1].aliases[1]"))
+	})
+
+	It("should report unreadable kubeconfig files", func() {
+		cfg.Gardens[0].Kubeconfig = filepath.Join(gardenHomeDir, "does-not-exist")
+		cfg.Gardens[1].Kubeconfig = ""
+		diagnostics := cfg.Validate()
+		Expect(diagnostics).To(HaveLen(2))
+		Expect(diagnostics[0].Field).To(Equal("gardens[0].kubeconfig"))
+		Expect(diagnostics[0].Message).To(ContainSubstring("is not readable"))
+		Expect(diagnostics[1].Field).To(Equal("gardens[1].kubeconfig"))
+		Expect(diagnostics[1].Message).To(Equal("kubeconfig path must not be empty"))
+	})
+
+	It("should report invalid inline kubeconfigs", func() {
+		cfg.Gardens[0].Kubeconfig = ""
+		cfg.Gardens[0].KubeconfigData = "apiVersion: v1\nkind: Config\n"
+		cfg.Gardens[1].KubeconfigData = "foo"
+		cfg.Gardens = append(cfg.Gardens, config.Garden{Name: "garden3", KubeconfigData: "{"})
+		diagnostics := cfg.Validate()
+		Expect(diagnostics).To(HaveLen(2))
+		Expect(diagnostics[0].Field).To(Equal("gardens[1]"))
+		Expect(diagnostics[0].Message).To(Equal("only one of kubeconfig, kubeconfigData and kubeconfigExec must be set"))
+		Expect(diagnostics[1].Field).To(Equal("gardens[2].kubeconfigData"))
+		Expect(diagnostics[1].Message).To(HavePrefix("kubeconfigData is not a valid kubeconfig"))
+	})
+
+	It("should report invalid kubeconfig commands", func() {
+		cfg.Gardens[0].Kubeconfig = ""
+		cfg.Gardens[0].KubeconfigExec = &config.KubeconfigExec{Command: "vault", CacheTTL: "1h"}
+		cfg.Gardens[1].Kubeconfig = ""
+		cfg.Gardens[1].KubeconfigExec = &config.KubeconfigExec{Command: "vault", CacheTTL: "forever"}
+		diagnostics := cfg.Validate()
+		Expect(diagnostics).To(HaveLen(1))
+		Expect(diagnostics[0].Field).To(Equal("gardens[1].kubeconfigExec"))
+		Expect(diagnostics[0].Message).To(HavePrefix(`kubeconfigExec is invalid: cacheTTL "forever" is not a valid duration`))
+	})
+
+	DescribeTable("validating patterns",
+		func(pattern string, severity config.Severity, matcher types.GomegaMatcher) {
+			cfg.Gardens[0].Patterns = []string{pattern}
+			diagnostics := cfg.Validate()
+			Expect(diagnostics).To(HaveLen(1))
+			Expect(diagnostics[0].Severity).To(Equal(severity))
+			Expect(diagnostics[0].Field).To(Equal("gardens[0].patterns[0]"))
+			Expect(diagnostics[0].Message).To(matcher)
+		},
+		Entry("when the pattern is not a valid regular expression", "(", config.SeverityError, HavePrefix("pattern is not a valid regular expression")),
+		Entry("when the pattern has an unknown capture group", "^(?P<cluster>.+)$", config.SeverityError, Equal(`pattern contains an invalid subexpression "cluster"`)),
+		Entry("when the pattern has no named capture group", "^shoot--(.+)$", config.SeverityWarning, ContainSubstring("does not contain any named capturing group")),
+	)
+
+	It("should report invalid labels", func() {
+		cfg.Gardens[0].Labels = map[string]string{"env": "prod", "-foo": "bar"}
+		cfg.Gardens[1].Labels = map[string]string{"env": "not valid"}
+		diagnostics := cfg.Validate()
+		Expect(diagnostics).To(HaveLen(2))
+		Expect(diagnostics[0].Field).To(Equal("gardens[0].labels[-foo]"))
+		Expect(diagnostics[0].Message).To(HavePrefix(`label key "-foo" is invalid`))
+		Expect(diagnostics[1].Field).To(Equal("gardens[1].labels[env]"))
+		Expect(diagnostics[1].Message).To(HavePrefix(`label value "not valid" is invalid`))
+	})
+
+	It("should report invalid dashboard URLs", func() {
+		cfg.Gardens[0].DashboardURL = "https://dashboard.garden.example.com"
+		cfg.Gardens[1].DashboardURL = "dashboard.garden.example.com"
+		Expect(cfg.Validate()).To(ConsistOf(config.Diagnostic{
+			Severity: config.SeverityError,
+			Garden:   "garden2",
+			Field:    "gardens[1].dashboardURL",
+			Message:  `dashboardURL "dashboard.garden.example.com" must be an absolute http or https URL`,
+		}))
+	})
+
+	It("should report an undefined default garden", func() {
+		cfg.DefaultGarden = "g2"
+		Expect(cfg.Validate()).To(BeEmpty())
+
+		cfg.DefaultGarden = "garden3"
+		Expect(cfg.Validate()).To(ConsistOf(config.Diagnostic{
+			Severity: config.SeverityError,
+			Field:    "defaultGarden",
+			Message:  `default garden "garden3" is not defined in gardenctl configuration`,
+		}))
+	})
+
+	It("should report invalid and duplicate sync sources", func() {
+		cfg.SyncSources = []config.SyncSource{
+			{Name: "team", URL: "https://example.com/gardenctl-v2.yaml"},
+			{Name: "team", URL: "git+https://github.com/example/gardens.git"},
+			{Name: "other", URL: "ftp://example.com/gardenctl-v2.yaml"},
+		}
+		diagnostics := cfg.Validate()
+		Expect(diagnostics).To(HaveLen(2))
+		Expect(diagnostics[0].Field).To(Equal("syncSources[1].name"))
+		Expect(diagnostics[0].Message).To(Equal(`sync source "team" is defined more than once`))
+		Expect(diagnostics[1].Field).To(Equal("syncSources[2]"))
+		Expect(diagnostics[1].Message).To(HavePrefix(`sync source is invalid: url "ftp://example.com/gardenctl-v2.yaml" is not supported`))
+	})
+
+	It("should report invalid and open bastion ingress CIDRs", func() {
+		cfg.Bastion = &config.Bastion{IngressCIDRs: []string{"10.0.0.0/8", "10.0.0.1", "0.0.0.0/0"}}
+		diagnostics := cfg.Validate()
+		Expect(diagnostics).To(HaveLen(2))
+		Expect(diagnostics[0].Severity).To(Equal(config.SeverityError))
+		Expect(diagnostics[0].Field).To(Equal("bastion.ingressCIDRs[1]"))
+		Expect(diagnostics[1]).To(Equal(config.Diagnostic{
+			Severity: config.SeverityWarning,
+			Field:    "bastion.ingressCIDRs[2]",
+			Message:  `CIDR "0.0.0.0/0" allows access to bastions from any address`,
+		}))
+	})
+
+	It("should report an invalid completion cache TTL", func() {
+		cfg.Completion = &config.Completion{CacheTTL: "1m"}
+		Expect(cfg.Validate()).To(BeEmpty())
+		Expect(cfg.CompletionCacheTTL()).To(Equal(time.Minute))
+
+		cfg.Completion.CacheTTL = "-1s"
+		Expect(cfg.Validate()).To(ConsistOf(config.Diagnostic{
+			Severity: config.SeverityError,
+			Field:    "completion.cacheTTL",
+			Message:  `cacheTTL "-1s" must not be negative`,
+		}))
+		Expect(cfg.CompletionCacheTTL()).To(BeZero())
+
+		cfg.Completion = nil
+		Expect(cfg.CompletionCacheTTL()).To(Equal(config.DefaultCompletionCacheTTL))
+	})
+
+	It("should report an invalid directory and expand the directories", func() {
+		cfg.Directories = &config.Directories{Cache: "~/cache", Sessions: "/sessions"}
+		Expect(cfg.Validate()).To(BeEmpty())
+		Expect(cfg.CacheDirectory()).NotTo(HavePrefix("~"))
+		Expect(cfg.TokenDirectory()).To(BeEmpty())
+		Expect(cfg.SessionsDirectory()).To(Equal("/sessions"))
+
+		cfg.Directories.Tokens = "~other/tokens"
+		diagnostics := cfg.Validate()
+		Expect(diagnostics).To(HaveLen(1))
+		Expect(diagnostics[0].Field).To(Equal("directories"))
+		Expect(diagnostics[0].Message).To(HavePrefix(`tokens directory "~other/tokens" is invalid`))
+	})
+
+	It("should report an invalid cleanup retention", func() {
+		cfg.Cleanup = &config.Cleanup{Retention: "72h", Automatic: pointer.Bool(false)}
+		Expect(cfg.Validate()).To(BeEmpty())
+		Expect(cfg.CleanupRetention()).To(Equal(72 * time.Hour))
+		Expect(cfg.AutomaticCleanup()).To(BeFalse())
+
+		cfg.Cleanup.Retention = "0s"
+		Expect(cfg.Validate()).To(ConsistOf(config.Diagnostic{
+			Severity: config.SeverityError,
+			Field:    "cleanup.retention",
+			Message:  `retention "0s" must be positive`,
+		}))
+		Expect(cfg.CleanupRetention()).To(Equal(config.DefaultCleanupRetention))
+
+		cfg.Cleanup = nil
+		Expect(cfg.CleanupRetention()).To(Equal(config.DefaultCleanupRetention))
+		Expect(cfg.AutomaticCleanup()).To(BeTrue())
+	})
+
+	It("should report an invalid audit file and expand the audit file", func() {
+		Expect(cfg.AuditFile()).To(BeEmpty())
+
+		cfg.Logging = &config.Logging{AuditFile: "~/audit.log"}
+		Expect(cfg.Validate()).To(BeEmpty())
+		Expect(cfg.AuditFile()).To(HaveSuffix("/audit.log"))
+		Expect(cfg.AuditFile()).NotTo(HavePrefix("~"))
+
+		cfg.Logging.AuditFile = "~other/audit.log"
+		diagnostics := cfg.Validate()
+		Expect(diagnostics).To(HaveLen(1))
+		Expect(diagnostics[0].Field).To(Equal("logging.auditFile"))
+		Expect(diagnostics[0].Message).To(HavePrefix(`audit file "~other/audit.log" is invalid`))
+		Expect(cfg.AuditFile()).To(BeEmpty())
+	})
+
+	It("should report an invalid client policy and apply the overrides", func() {
+		cfg.ClientPolicy = &config.ClientPolicy{Retries: pointer.Int(5), RetryBackoff: "1s"}
+		Expect(cfg.Validate()).To(BeEmpty())
+		Expect(cfg.ClientPolicy.GetQPS()).To(Equal(config.DefaultClientQPS))
+		Expect(cfg.ClientPolicy.GetRetries()).To(Equal(5))
+		Expect(cfg.ClientPolicy.GetRetryBackoff()).To(Equal(time.Second))
+
+		merged := cfg.ClientPolicy.WithOverrides(config.ClientPolicy{Retries: pointer.Int(0), Burst: pointer.Int(100)})
+		Expect(merged.GetRetries()).To(BeZero())
+		Expect(merged.GetBurst()).To(Equal(100))
+		Expect(merged.GetRetryBackoff()).To(Equal(time.Second))
+		Expect(cfg.ClientPolicy.GetRetries()).To(Equal(5))
+
+		cfg.ClientPolicy.RetryBackoff = "soon"
+		Expect(cfg.Validate()).To(ConsistOf(config.Diagnostic{
+			Severity: config.SeverityError,
+			Field:    "clientPolicy",
+			Message:  `retryBackoff "soon" is not a valid duration: time: invalid duration "soon"`,
+		}))
+		Expect(cfg.ClientPolicy.GetRetryBackoff()).To(Equal(config.DefaultClientRetryBackoff))
+
+		cfg.ClientPolicy.RetryBackoff = ""
+		cfg.ClientPolicy.DialTimeout = "0s"
+		Expect(cfg.Validate()).To(ConsistOf(config.Diagnostic{
+			Severity: config.SeverityError,
+			Field:    "clientPolicy",
+			Message:  `dialTimeout "0s" must be positive`,
+		}))
+		Expect(cfg.ClientPolicy.GetDialTimeout()).To(Equal(config.DefaultClientDialTimeout))
+	})
+
+	It("should report an invalid template registry and expand the template directory", func() {
+		Expect(os.Setenv("GCTL_TEST_TEMPLATES", "/templates")).To(Succeed())
+		defer os.Unsetenv("GCTL_TEST_TEMPLATES")
+
+		cfg.Templates = &config.Templates{Directory: "${GCTL_TEST_TEMPLATES}/shoots", Registry: "localhost:5000/shoot-templates"}
+		Expect(cfg.Validate()).To(BeEmpty())
+		Expect(cfg.TemplateDirectory()).To(Equal("/templates/shoots"))
+		Expect(cfg.TemplateRegistry()).To(Equal("localhost:5000/shoot-templates"))
+
+		cfg.Templates.Registry = "https://ghcr.io/my-org"
+		Expect(cfg.Validate()).To(ConsistOf(config.Diagnostic{
+			Severity: config.SeverityError,
+			Field:    "templates.registry",
+			Message:  `registry "https://ghcr.io/my-org" must not contain a scheme`,
+		}))
+
+		cfg.Templates.Registry = "ghcr.io/my-org/templates:v1"
+		Expect(cfg.Validate()).To(ConsistOf(config.Diagnostic{
+			Severity: config.SeverityError,
+			Field:    "templates.registry",
+			Message:  `registry "ghcr.io/my-org/templates:v1" must be a repository prefix like ghcr.io/my-org/shoot-templates`,
+		}))
+	})
+
+	It("should report invalid, duplicate and undefined credential plugins", func() {
+		cfg.CredentialPlugins = []config.CredentialPlugin{
+			{Name: "sso", Command: "sso-login"},
+			{Name: "sso", Command: "other-login"},
+			{Name: "broken"},
+		}
+		cfg.Gardens[0].CredentialPlugin = "sso"
+		cfg.Gardens[1].CredentialPlugin = "unknown"
+		diagnostics := cfg.Validate()
+		Expect(diagnostics).To(ConsistOf(
+			config.Diagnostic{
+				Severity: config.SeverityError,
+				Garden:   "garden2",
+				Field:    "gardens[1].credentialPlugin",
+				Message:  `credential plugin "unknown" is not defined in gardenctl configuration`,
+			},
+			config.Diagnostic{
+				Severity: config.SeverityError,
+				Field:    "credentialPlugins[1].name",
+				Message:  `credential plugin "sso" is defined more than once`,
+			},
+			config.Diagnostic{
+				Severity: config.SeverityError,
+				Field:    "credentialPlugins[2]",
+				Message:  "credential plugin is invalid: command must not be empty",
+			},
+		))
+	})
+
+	It("should report invalid oidc configurations", func() {
+		cfg.Gardens[0].OIDC = &config.OIDC{IssuerURL: "http://issuer.example.com", ClientID: "gardenctl"}
+		cfg.Gardens[1].OIDC = &config.OIDC{IssuerURL: "https://issuer.example.com", ClientID: "gardenctl"}
+		cfg.Gardens[1].CredentialPlugin = "sso"
+		cfg.CredentialPlugins = []config.CredentialPlugin{{Name: "sso", Command: "sso-login"}}
+		Expect(cfg.Validate()).To(ConsistOf(
+			config.Diagnostic{
+				Severity: config.SeverityError,
+				Garden:   "garden1",
+				Field:    "gardens[0].oidc",
+				Message:  `oidc is invalid: issuerURL "http://issuer.example.com" must be an https URL`,
+			},
+			config.Diagnostic{
+				Severity: config.SeverityError,
+				Garden:   "garden2",
+				Field:    "gardens[1]",
+				Message:  "only one of credentialPlugin and oidc must be set",
+			},
+		))
+	})
+
+	It("should validate global match patterns", func() {
+		cfg.MatchPatterns = []string{"^(?P<garden>[^/]+)/(?P<shoot>.+)$", "^(?P<cluster>.+)$"}
+		diagnostics := cfg.Validate()
+		Expect(diagnostics).To(ConsistOf(config.Diagnostic{
+			Severity: config.SeverityError,
+			Field:    "matchPatterns[1]",
+			Message:  `pattern contains an invalid subexpression "cluster"`,
+		}))
+	})
+
+	It("should report unknown and duplicate pattern presets", func() {
+		cfg.PatternPresets = []string{"dashboard", "foo", "dashboard"}
+		diagnostics := cfg.Validate()
+		Expect(diagnostics).To(HaveLen(2))
+		Expect(diagnostics[0].Severity).To(Equal(config.SeverityError))
+		Expect(diagnostics[0].Field).To(Equal("patternPresets[1]"))
+		Expect(diagnostics[0].Message).To(Equal(`pattern preset "foo" is not defined, must be one of [dashboard kubectl-context shorthand technical-id]`))
+		Expect(diagnostics[1].Severity).To(Equal(config.SeverityWarning))
+		Expect(diagnostics[1].Field).To(Equal("patternPresets[2]"))
+	})
+
+	DescribeTable("ValidatePattern",
+		func(pattern string, matcher types.GomegaMatcher) {
+			Expect(config.ValidatePattern(pattern)).To(matcher)
+		},
+		Entry("when the pattern is valid", "^(garden/)?shoot--(?P<project>.+)--(?P<shoot>.+)$", Succeed()),
+		Entry("when the pattern uses the namespace group", "^(?P<namespace>[^/]+)$", Succeed()),
+		Entry("when the pattern is invalid", "(", MatchError(HavePrefix("is not a valid regular expression"))),
+		Entry("when the pattern has an unknown capture group", "(?P<foo>.+)", MatchError(`contains an invalid subexpression "foo"`)),
+		Entry("when the pattern has an unnamed capture group", "^(?:garden/)?shoot--(.+)--(?P<shoot>.+)$", Succeed()),
+		Entry("when the pattern uses the garden group", "^(?P<garden>.+)$", MatchError(`contains an invalid subexpression "garden"`)),
+	)
+
+	DescribeTable("ValidateMatchPattern",
+		func(pattern string, matcher types.GomegaMatcher) {
+			Expect(config.ValidateMatchPattern(pattern)).To(matcher)
+		},
+		Entry("when the pattern uses the garden group", "^(?P<garden>[^/]+)/(?P<shoot>.+)$", Succeed()),
+		Entry("when the pattern has an unknown capture group", "(?P<foo>.+)", MatchError(`contains an invalid subexpression "foo"`)),
+	)
+})
@@ -0,0 +1,177 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package target
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	"github.com/gardener/gardenctl-v2/internal/audit"
+)
+
+// auditClientProvider records the write requests of its clients in the audit log
+type auditClientProvider struct {
+	delegate ClientProvider
+	logger   *audit.Logger
+}
+
+var _ ClientProvider = &auditClientProvider{}
+
+// NewAuditClientProvider returns a ClientProvider whose clients record every write request and its result in
+// the audit log. Dry-run requests and reviews are not recorded, because they do not change any resource.
+func NewAuditClientProvider(delegate ClientProvider, logger *audit.Logger) ClientProvider {
+	return &auditClientProvider{
+		delegate: delegate,
+		logger:   logger,
+	}
+}
+
+// FromClientConfig returns a Kubernetes client for the given client config that records its write requests.
+func (p *auditClientProvider) FromClientConfig(clientConfig clientcmd.ClientConfig) (client.Client, error) {
+	c, err := p.delegate.FromClientConfig(clientConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &auditClient{Client: c, logger: p.logger}, nil
+}
+
+// auditClient records the write requests in the audit log after they have been sent
+type auditClient struct {
+	client.Client
+	logger *audit.Logger
+}
+
+var _ client.Client = &auditClient{}
+
+func (c *auditClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	err := c.Client.Create(ctx, obj, opts...)
+
+	if (&client.CreateOptions{}).ApplyOptions(opts).DryRun == nil && !isReview(obj) {
+		c.log("create", obj, err)
+	}
+
+	return err
+}
+
+func (c *auditClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	err := c.Client.Update(ctx, obj, opts...)
+
+	if (&client.UpdateOptions{}).ApplyOptions(opts).DryRun == nil {
+		c.log("update", obj, err)
+	}
+
+	return err
+}
+
+func (c *auditClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	err := c.Client.Patch(ctx, obj, patch, opts...)
+
+	if (&client.PatchOptions{}).ApplyOptions(opts).DryRun == nil {
+		c.log("patch", obj, err)
+	}
+
+	return err
+}
+
+func (c *auditClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	err := c.Client.Delete(ctx, obj, opts...)
+
+	if (&client.DeleteOptions{}).ApplyOptions(opts).DryRun == nil {
+		c.log("delete", obj, err)
+	}
+
+	return err
+}
+
+func (c *auditClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	err := c.Client.DeleteAllOf(ctx, obj, opts...)
+
+	if (&client.DeleteAllOfOptions{}).ApplyOptions(opts).DryRun == nil {
+		c.log("deletecollection", obj, err)
+	}
+
+	return err
+}
+
+func (c *auditClient) Status() client.StatusWriter {
+	return &auditStatusWriter{client: c}
+}
+
+func (c *auditClient) log(action string, obj client.Object, err error) {
+	c.logger.Log(audit.Entry{
+		Action:   action,
+		Resource: describeObject(obj, c.Scheme()),
+	}, err)
+}
+
+// auditStatusWriter records the status updates in the audit log after they have been sent
+type auditStatusWriter struct {
+	client *auditClient
+}
+
+var _ client.StatusWriter = &auditStatusWriter{}
+
+func (w *auditStatusWriter) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	err := w.client.Client.Status().Update(ctx, obj, opts...)
+
+	if (&client.UpdateOptions{}).ApplyOptions(opts).DryRun == nil {
+		w.client.log("update status", obj, err)
+	}
+
+	return err
+}
+
+func (w *auditStatusWriter) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	err := w.client.Client.Status().Patch(ctx, obj, patch, opts...)
+
+	if (&client.PatchOptions{}).ApplyOptions(opts).DryRun == nil {
+		w.client.log("patch status", obj, err)
+	}
+
+	return err
+}
+
+// auditTarget records a change of the target file in the audit log, the entry contains the new target
+func (m *managerImpl) auditTarget(t Target, err error) {
+	m.config.Audit.Log(audit.Entry{
+		Target:   AuditTarget(t),
+		Action:   "write",
+		Resource: filepath.Join(m.sessionDirectory, "target.yaml"),
+	}, err)
+}
+
+// AuditTarget converts the target into the target of audit log entries
+func AuditTarget(t Target) *audit.Target {
+	if t == nil {
+		return nil
+	}
+
+	return &audit.Target{
+		Garden:       t.GardenName(),
+		Project:      t.ProjectName(),
+		Seed:         t.SeedName(),
+		Shoot:        t.ShootName(),
+		ControlPlane: t.ControlPlane(),
+	}
+}
+
+// describeObject returns the kind and the key of the object, e.g. Shoot garden-dev/my-shoot
+func describeObject(obj client.Object, scheme *runtime.Scheme) string {
+	kind := fmt.Sprintf("%T", obj)
+	if gvk, err := apiutil.GVKForObject(obj, scheme); err == nil {
+		kind = gvk.Kind
+	}
+
+	return fmt.Sprintf("%s %s", kind, client.ObjectKeyFromObject(obj))
+}
@@ -0,0 +1,142 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package target_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/gardener/gardenctl-v2/internal/audit"
+	"github.com/gardener/gardenctl-v2/internal/fake"
+	"github.com/gardener/gardenctl-v2/pkg/config"
+	"github.com/gardener/gardenctl-v2/pkg/target"
+	targetmocks "github.com/gardener/gardenctl-v2/pkg/target/mocks"
+)
+
+var _ = Describe("Audit", func() {
+	var (
+		ctx       context.Context
+		ctrl      *gomock.Controller
+		dir       string
+		logger    *audit.Logger
+		configMap *corev1.ConfigMap
+		provider  *targetmocks.MockClientProvider
+	)
+
+	BeforeEach(func() {
+		var err error
+
+		ctx = context.Background()
+		ctrl = gomock.NewController(GinkgoT())
+
+		dir, err = os.MkdirTemp("", "audit-")
+		Expect(err).NotTo(HaveOccurred())
+
+		logger = &audit.Logger{
+			Filename: filepath.Join(dir, "audit.log"),
+			Command:  "gardenctl test",
+			User:     "jdoe",
+			Now: func() time.Time {
+				return time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+			},
+		}
+
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "existing",
+				Namespace: "default",
+			},
+		}
+
+		provider = targetmocks.NewMockClientProvider(ctrl)
+		provider.EXPECT().FromClientConfig(gomock.Any()).Return(fake.NewClientWithObjects(configMap), nil).AnyTimes()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	auditClient := func() client.Client {
+		c, err := target.NewAuditClientProvider(provider, logger).FromClientConfig(nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		return c
+	}
+
+	auditLog := func() string {
+		data, err := os.ReadFile(logger.Filename)
+		if os.IsNotExist(err) {
+			return ""
+		}
+
+		Expect(err).NotTo(HaveOccurred())
+
+		return string(data)
+	}
+
+	It("should record write requests and their result", func() {
+		c := auditClient()
+
+		Expect(c.Delete(ctx, configMap)).To(Succeed())
+		Expect(apierrors.IsNotFound(c.Delete(ctx, configMap))).To(BeTrue())
+
+		Expect(auditLog()).To(Equal(
+			`{"timestamp":"2022-06-01T12:00:00Z","user":"jdoe","command":"gardenctl test","action":"delete","resource":"ConfigMap default/existing","result":"success"}` + "\n" +
+				`{"timestamp":"2022-06-01T12:00:00Z","user":"jdoe","command":"gardenctl test","action":"delete","resource":"ConfigMap default/existing","result":"failure","error":"configmaps \"existing\" not found"}` + "\n",
+		))
+	})
+
+	It("should not record read and dry-run requests", func() {
+		c := auditClient()
+
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(configMap), &corev1.ConfigMap{})).To(Succeed())
+		Expect(c.Delete(ctx, configMap, client.DryRunAll)).To(Succeed())
+
+		Expect(auditLog()).To(BeEmpty())
+	})
+
+	It("should not record the requests of the client dry-run mode", func() {
+		dryRunProvider := target.NewDryRunClientProvider(
+			target.NewAuditClientProvider(provider, logger),
+			config.DryRun{Strategy: config.DryRunClient, Out: &bytes.Buffer{}},
+		)
+		c, err := dryRunProvider.FromClientConfig(nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(c.Delete(ctx, configMap)).To(Succeed())
+		Expect(auditLog()).To(BeEmpty())
+	})
+
+	It("should record the changes of the target with the new target", func() {
+		cfg := &config.Config{
+			LinkKubeconfig: pointer.Bool(false),
+			Gardens:        []config.Garden{{Name: gardenName, Kubeconfig: gardenKubeconfig}},
+			Audit:          logger,
+		}
+
+		manager, _ := createTestManager(target.NewTarget("", "", "", ""), cfg, provider)
+		Expect(manager.TargetGarden(ctx, gardenName)).To(Succeed())
+
+		Expect(auditLog()).To(Equal(
+			`{"timestamp":"2022-06-01T12:00:00Z","user":"jdoe","command":"gardenctl test","target":{"garden":"` + gardenName + `"},` +
+				`"action":"write","resource":"` + filepath.Join(sessionDir, "target.yaml") + `","result":"success"}` + "\n",
+		))
+	})
+})
@@ -7,11 +7,24 @@ package target
 
 import (
 	"fmt"
+	"net"
+	"sync"
+	"time"
 
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	"github.com/gardener/gardenctl-v2/pkg/config"
 )
 
+// defaultRequestTimeout limits the duration of a single API request if the
+// kubeconfig does not configure a timeout. Requests are canceled earlier if
+// their context is done.
+const defaultRequestTimeout = 60 * time.Second
+
 //go:generate mockgen -destination=./mocks/mock_client_provider.go -package=mocks github.com/gardener/gardenctl-v2/pkg/target ClientProvider
 
 // ClientProvider is able to take a kubeconfig either directly or
@@ -21,21 +34,83 @@ type ClientProvider interface {
 	FromClientConfig(config clientcmd.ClientConfig) (client.Client, error)
 }
 
-type clientProvider struct{}
+type clientProvider struct {
+	// policy configures the rate limiting and retries of the requests
+	policy *config.ClientPolicy
+
+	// mappers caches the REST mappers by API server host, so that the API discovery
+	// is only done once per cluster even if several clients are created for it
+	mappers     map[string]meta.RESTMapper
+	mappersLock sync.Mutex
+}
 
 var _ ClientProvider = &clientProvider{}
 
-// NewClientProvider returns a new ClientProvider.
-func NewClientProvider() ClientProvider {
-	return &clientProvider{}
+// NewClientProvider returns a new ClientProvider. The requests of the clients are rate limited
+// and retried according to the given policy, the defaults are used if the policy is nil.
+func NewClientProvider(policy *config.ClientPolicy) ClientProvider {
+	return &clientProvider{
+		policy:  policy,
+		mappers: map[string]meta.RESTMapper{},
+	}
 }
 
 // FromClientConfig returns a Kubernetes client for the given client config.
 func (p *clientProvider) FromClientConfig(clientConfig clientcmd.ClientConfig) (client.Client, error) {
-	config, err := clientConfig.ClientConfig()
+	restConfig, err := clientConfig.ClientConfig()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create restclient config: %w", err)
 	}
 
-	return client.New(config, client.Options{})
+	if restConfig.Timeout == 0 {
+		restConfig.Timeout = defaultRequestTimeout
+	}
+
+	restConfig.QPS = p.policy.GetQPS()
+	restConfig.Burst = p.policy.GetBurst()
+
+	restConfig.Dial = (&net.Dialer{
+		Timeout:   p.policy.GetDialTimeout(),
+		KeepAlive: 30 * time.Second,
+	}).DialContext
+
+	restConfig.Wrap(newLoggingRoundTripper)
+	restConfig.Wrap(newUnreachableRoundTripper)
+	restConfig.Wrap(newRetryRoundTripper(p.policy.GetRetries(), p.policy.GetRetryBackoff()))
+
+	mapper, err := p.restMapper(restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.New(restConfig, client.Options{Mapper: mapper})
+}
+
+// restMapper returns the cached REST mapper of the API server or creates a new one.
+// The API discovery is done without holding the lock, so that clients for different
+// clusters can be created concurrently.
+func (p *clientProvider) restMapper(restConfig *rest.Config) (meta.RESTMapper, error) {
+	p.mappersLock.Lock()
+	mapper, ok := p.mappers[restConfig.Host]
+	p.mappersLock.Unlock()
+
+	if ok {
+		return mapper, nil
+	}
+
+	mapper, err := apiutil.NewDynamicRESTMapper(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create REST mapper: %w", err)
+	}
+
+	p.mappersLock.Lock()
+	defer p.mappersLock.Unlock()
+
+	if cached, ok := p.mappers[restConfig.Host]; ok {
+		return cached, nil
+	}
+
+	p.mappers[restConfig.Host] = mapper
+
+	return mapper, nil
 }
@@ -0,0 +1,245 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package target
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	sigsyaml "sigs.k8s.io/yaml"
+
+	"github.com/gardener/gardenctl-v2/internal/diff"
+	"github.com/gardener/gardenctl-v2/pkg/config"
+)
+
+// dryRunClientProvider is used in dry-run mode, the write requests of its clients are not persisted
+type dryRunClientProvider struct {
+	delegate ClientProvider
+	dryRun   config.DryRun
+}
+
+var _ ClientProvider = &dryRunClientProvider{}
+
+// NewDryRunClientProvider returns a ClientProvider for the dry-run mode. The clients of the delegate are wrapped,
+// so that the objects of write requests are printed instead of being persisted. With the server strategy, the
+// requests are sent as dry-run requests, so that the objects are validated and defaulted by the API server.
+func NewDryRunClientProvider(delegate ClientProvider, dryRun config.DryRun) ClientProvider {
+	return &dryRunClientProvider{
+		delegate: delegate,
+		dryRun:   dryRun,
+	}
+}
+
+// FromClientConfig returns a Kubernetes client for the given client config that does not persist any changes.
+func (p *dryRunClientProvider) FromClientConfig(clientConfig clientcmd.ClientConfig) (client.Client, error) {
+	c, err := p.delegate.FromClientConfig(clientConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dryRunClient{Client: c, dryRun: p.dryRun}, nil
+}
+
+// dryRunClient prints the objects of write requests. Requests that are already dry-run requests, e.g. to validate
+// an object before it is created, and reviews are passed through.
+type dryRunClient struct {
+	client.Client
+	dryRun config.DryRun
+}
+
+var _ client.Client = &dryRunClient{}
+
+func (c *dryRunClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if (&client.CreateOptions{}).ApplyOptions(opts).DryRun != nil || isReview(obj) {
+		return c.Client.Create(ctx, obj, opts...)
+	}
+
+	if c.dryRun.Strategy == config.DryRunServer {
+		if err := c.Client.Create(ctx, obj, append(opts, client.DryRunAll)...); err != nil {
+			return err
+		}
+	}
+
+	return c.printObject("created", obj)
+}
+
+func (c *dryRunClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	if (&client.UpdateOptions{}).ApplyOptions(opts).DryRun != nil {
+		return c.Client.Update(ctx, obj, opts...)
+	}
+
+	if c.dryRun.Strategy == config.DryRunServer {
+		if err := c.Client.Update(ctx, obj, append(opts, client.DryRunAll)...); err != nil {
+			return err
+		}
+	}
+
+	return c.printObject("updated", obj)
+}
+
+func (c *dryRunClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if (&client.PatchOptions{}).ApplyOptions(opts).DryRun != nil {
+		return c.Client.Patch(ctx, obj, patch, opts...)
+	}
+
+	// the patch is computed before the request, the server replaces obj with the patched object
+	data, err := patch.Data(obj)
+	if err != nil {
+		return fmt.Errorf("failed to compute patch: %w", err)
+	}
+
+	if c.dryRun.Strategy == config.DryRunServer {
+		if err := c.Client.Patch(ctx, obj, patch, append(opts, client.DryRunAll)...); err != nil {
+			return err
+		}
+	}
+
+	if err := c.printHeader("patched", obj); err != nil {
+		return err
+	}
+
+	// the patches of gardenctl are JSON documents, which are printed as YAML like the objects
+	if yamlData, err := sigsyaml.JSONToYAML(data); err == nil {
+		data = yamlData
+	}
+
+	_, err = c.dryRun.Out.Write(data)
+
+	return err
+}
+
+func (c *dryRunClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	if (&client.DeleteOptions{}).ApplyOptions(opts).DryRun != nil {
+		return c.Client.Delete(ctx, obj, opts...)
+	}
+
+	if c.dryRun.Strategy == config.DryRunServer {
+		if err := c.Client.Delete(ctx, obj, append(opts, client.DryRunAll)...); err != nil {
+			return err
+		}
+	}
+
+	return c.printHeader("deleted", obj)
+}
+
+func (c *dryRunClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	if (&client.DeleteAllOfOptions{}).ApplyOptions(opts).DryRun != nil {
+		return c.Client.DeleteAllOf(ctx, obj, opts...)
+	}
+
+	if c.dryRun.Strategy == config.DryRunServer {
+		if err := c.Client.DeleteAllOf(ctx, obj, append(opts, client.DryRunAll)...); err != nil {
+			return err
+		}
+	}
+
+	return c.printHeader("deleted", obj)
+}
+
+func (c *dryRunClient) Status() client.StatusWriter {
+	return &dryRunStatusWriter{client: c}
+}
+
+// printHeader prints the kind and the key of the object and what would have happened to it
+func (c *dryRunClient) printHeader(verb string, obj client.Object) error {
+	_, err := fmt.Fprintf(c.dryRun.Out, "%s %s (%s dry run)\n", describeObject(obj, c.Scheme()), verb, c.dryRun.Strategy)
+
+	return err
+}
+
+// printObject prints the header and the object as YAML
+func (c *dryRunClient) printObject(verb string, obj client.Object) error {
+	if err := c.printHeader(verb, obj); err != nil {
+		return err
+	}
+
+	printed, ok := obj.DeepCopyObject().(client.Object)
+	if !ok {
+		return fmt.Errorf("failed to copy %T", obj)
+	}
+
+	// typed objects usually do not have their kind set
+	if gvk, err := apiutil.GVKForObject(obj, c.Scheme()); err == nil {
+		printed.GetObjectKind().SetGroupVersionKind(gvk)
+	}
+
+	if accessor, err := meta.Accessor(printed); err == nil {
+		accessor.SetManagedFields(nil)
+	}
+
+	data, err := sigsyaml.Marshal(printed)
+	if err != nil {
+		return fmt.Errorf("failed to encode as YAML: %w", err)
+	}
+
+	_, err = c.dryRun.Out.Write(data)
+
+	return err
+}
+
+// dryRunStatusWriter prints the objects of status updates instead of persisting them
+type dryRunStatusWriter struct {
+	client *dryRunClient
+}
+
+var _ client.StatusWriter = &dryRunStatusWriter{}
+
+func (w *dryRunStatusWriter) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	if w.client.dryRun.Strategy == config.DryRunServer {
+		if err := w.client.Client.Status().Update(ctx, obj, append(opts, client.DryRunAll)...); err != nil {
+			return err
+		}
+	}
+
+	return w.client.printObject("status updated", obj)
+}
+
+func (w *dryRunStatusWriter) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if w.client.dryRun.Strategy == config.DryRunServer {
+		if err := w.client.Client.Status().Patch(ctx, obj, patch, append(opts, client.DryRunAll)...); err != nil {
+			return err
+		}
+	}
+
+	return w.client.printObject("status patched", obj)
+}
+
+// printTargetDiff prints the changes of the target file in dry-run mode instead of writing it
+func (m *managerImpl) printTargetDiff(previous, current Target) error {
+	oldData, err := yaml.Marshal(previous)
+	if err != nil {
+		return fmt.Errorf("failed to encode as YAML: %w", err)
+	}
+
+	newData, err := yaml.Marshal(current)
+	if err != nil {
+		return fmt.Errorf("failed to encode as YAML: %w", err)
+	}
+
+	return diff.Print(m.config.DryRun.Out, filepath.Join(m.sessionDirectory, "target.yaml"), oldData, newData)
+}
+
+// isReview returns true for the review objects, which are evaluated by the API server but never persisted,
+// e.g. the SelfSubjectAccessReviews that check the permissions of the user
+func isReview(obj client.Object) bool {
+	switch obj.(type) {
+	case *authorizationv1.SelfSubjectAccessReview, *authorizationv1.SelfSubjectRulesReview,
+		*authorizationv1.SubjectAccessReview, *authorizationv1.LocalSubjectAccessReview,
+		*authenticationv1.TokenReview:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,147 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package target_test
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/gardener/gardenctl-v2/internal/fake"
+	"github.com/gardener/gardenctl-v2/pkg/config"
+	"github.com/gardener/gardenctl-v2/pkg/target"
+	targetmocks "github.com/gardener/gardenctl-v2/pkg/target/mocks"
+)
+
+var _ = Describe("Dry Run", func() {
+	var (
+		ctx       context.Context
+		ctrl      *gomock.Controller
+		out       *bytes.Buffer
+		configMap *corev1.ConfigMap
+		delegate  client.Client
+		provider  *targetmocks.MockClientProvider
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		ctrl = gomock.NewController(GinkgoT())
+		out = &bytes.Buffer{}
+
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "existing",
+				Namespace: "default",
+			},
+			Data: map[string]string{"key": "value"},
+		}
+
+		delegate = fake.NewClientWithObjects(configMap)
+		provider = targetmocks.NewMockClientProvider(ctrl)
+		provider.EXPECT().FromClientConfig(gomock.Any()).Return(delegate, nil).AnyTimes()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	dryRunClient := func(strategy config.DryRunStrategy) client.Client {
+		c, err := target.NewDryRunClientProvider(provider, config.DryRun{Strategy: strategy, Out: out}).FromClientConfig(nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		return c
+	}
+
+	newConfigMap := func() *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "new",
+				Namespace: "default",
+			},
+			Data: map[string]string{"foo": "bar"},
+		}
+	}
+
+	It("should print created objects without creating them", func() {
+		Expect(dryRunClient(config.DryRunClient).Create(ctx, newConfigMap())).To(Succeed())
+		Expect(out.String()).To(Equal(`ConfigMap default/new created (client dry run)
+apiVersion: v1
+data:
+  foo: bar
+kind: ConfigMap
+metadata:
+  creationTimestamp: null
+  name: new
+  namespace: default
+`))
+
+		err := delegate.Get(ctx, client.ObjectKey{Namespace: "default", Name: "new"}, &corev1.ConfigMap{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should send dry-run requests with the server strategy", func() {
+		Expect(dryRunClient(config.DryRunServer).Create(ctx, newConfigMap())).To(Succeed())
+		Expect(out.String()).To(HavePrefix("ConfigMap default/new created (server dry run)\n"))
+
+		err := delegate.Get(ctx, client.ObjectKey{Namespace: "default", Name: "new"}, &corev1.ConfigMap{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should print patches without applying them", func() {
+		patch := client.MergeFrom(configMap.DeepCopy())
+		configMap.Data["key"] = "changed"
+
+		Expect(dryRunClient(config.DryRunServer).Patch(ctx, configMap, patch)).To(Succeed())
+		Expect(out.String()).To(Equal("ConfigMap default/existing patched (server dry run)\ndata:\n  key: changed\n"))
+
+		current := &corev1.ConfigMap{}
+		Expect(delegate.Get(ctx, client.ObjectKeyFromObject(configMap), current)).To(Succeed())
+		Expect(current.Data).To(HaveKeyWithValue("key", "value"))
+	})
+
+	It("should print deletions without deleting the objects", func() {
+		Expect(dryRunClient(config.DryRunClient).Delete(ctx, configMap)).To(Succeed())
+		Expect(out.String()).To(Equal("ConfigMap default/existing deleted (client dry run)\n"))
+		Expect(delegate.Get(ctx, client.ObjectKeyFromObject(configMap), &corev1.ConfigMap{})).To(Succeed())
+	})
+
+	It("should pass through requests that are already dry-run requests", func() {
+		Expect(dryRunClient(config.DryRunClient).Create(ctx, newConfigMap(), client.DryRunAll)).To(Succeed())
+		Expect(out.String()).To(BeEmpty())
+	})
+
+	It("should print the changes of the target instead of writing it", func() {
+		cfg := &config.Config{
+			LinkKubeconfig: pointer.Bool(false),
+			Gardens:        []config.Garden{{Name: gardenName, Kubeconfig: gardenKubeconfig}},
+			DryRun:         &config.DryRun{Strategy: config.DryRunClient, Out: out},
+		}
+
+		manager, targetProvider := createTestManager(target.NewTarget("", "", "", ""), cfg, provider)
+		Expect(manager.TargetGarden(ctx, gardenName)).To(Succeed())
+
+		Expect(out.String()).To(Equal(`--- ` + filepath.Join(sessionDir, "target.yaml") + `
++++ ` + filepath.Join(sessionDir, "target.yaml") + `
+@@ -1,1 +1,1 @@
+-{}
++garden: ` + gardenName + `
+`))
+
+		currentTarget, err := targetProvider.Read()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(currentTarget.GardenName()).To(BeEmpty())
+	})
+})
@@ -0,0 +1,24 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package target
+
+import (
+	"net/http"
+	"time"
+)
+
+func NewLoggingRoundTripper(rt http.RoundTripper, now func() time.Time) http.RoundTripper {
+	return &loggingRoundTripper{delegate: rt, now: now}
+}
+
+func NewRetryRoundTripper(rt http.RoundTripper, retries int, backoff time.Duration, after func(time.Duration) <-chan time.Time) http.RoundTripper {
+	return &retryRoundTripper{delegate: rt, retries: retries, backoff: backoff, after: after}
+}
+
+func NewUnreachableRoundTripper(rt http.RoundTripper) http.RoundTripper {
+	return newUnreachableRoundTripper(rt)
+}
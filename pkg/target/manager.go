@@ -13,8 +13,13 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/gardener/gardenctl-v2/internal/gardenclient"
@@ -28,6 +33,7 @@ var (
 	ErrNoShootTargeted               = errors.New("no shoot targeted")
 	ErrNeitherProjectNorSeedTargeted = errors.New("neither project nor seed are targeted")
 	ErrNoControlPlaneTargeted        = errors.New("no control plane targeted")
+	ErrEphemeralKubeconfigs          = errors.New("kubeconfigs must not be written to disk, security.ephemeralKubeconfigs is enabled in the gardenctl configuration")
 )
 
 //go:generate mockgen -destination=./mocks/mock_manager.go -package=mocks github.com/gardener/gardenctl-v2/pkg/target Manager
@@ -72,10 +78,24 @@ type Manager interface {
 	// against patterns defined in gardenctl configuration. Some values may only match a subset
 	// of a pattern
 	TargetMatchPattern(ctx context.Context, value string) error
+	// ReplaceTarget replaces the whole target with the given target
+	// All values of the given target are validated against the garden cluster
+	ReplaceTarget(ctx context.Context, t Target) error
+	// TargetPrevious replaces the whole target with the most recent target of the target history
+	TargetPrevious(ctx context.Context) error
+	// TargetHistory returns the previous targets of the current session, the most recent one first
+	TargetHistory() ([]Target, error)
 
 	//ClientConfig returns the client config for a target
 	ClientConfig(ctx context.Context, t Target) (clientcmd.ClientConfig, error)
-	// WriteClientConfig creates a kubeconfig file in the session directory of the operating system
+	// AdminKubeconfig returns a client config with admin credentials for the shoot of the target, which expire after the given duration
+	// The kubeconfig is cached in the session directory and renewed shortly before it expires
+	AdminKubeconfig(ctx context.Context, t Target, expiration time.Duration) (clientcmd.ClientConfig, error)
+	// ViewerKubeconfig returns a client config with read-only credentials for the shoot of the target, which expire after the given duration
+	// The kubeconfig is cached in the session directory and renewed shortly before it expires
+	ViewerKubeconfig(ctx context.Context, t Target, expiration time.Duration) (clientcmd.ClientConfig, error)
+	// WriteClientConfig creates a kubeconfig file in the session directory of the operating system.
+	// It fails with ErrEphemeralKubeconfigs if kubeconfigs must not be written to disk.
 	WriteClientConfig(config clientcmd.ClientConfig) (string, error)
 	// SeedClient controller-runtime client for accessing the configured seed cluster
 	SeedClient(ctx context.Context, t Target) (client.Client, error)
@@ -89,7 +109,77 @@ type Manager interface {
 	Configuration() *config.Config
 
 	// GardenClient returns a gardenClient for a garden cluster
+	// The client is created once per garden and reused for the lifetime of the manager
 	GardenClient(name string) (gardenclient.Client, error)
+	// ForEachGarden calls fn for every garden of the gardenctl configuration. The gardens are processed
+	// concurrently by a bounded number of workers, so fn must be safe for concurrent use. The failure of
+	// one garden does not affect the others, the errors of all failed gardens are returned sorted by garden name.
+	// A garden with the same cluster identity as a preceding garden is skipped with a DuplicateGardenError,
+	// so that a cluster that is configured more than once is not counted twice.
+	ForEachGarden(ctx context.Context, fn func(ctx context.Context, gardenName string, gardenClient gardenclient.Client) error) []*GardenError
+	// ClusterIdentity returns the cluster identity of the garden. A clusterIdentity that is configured for the garden is
+	// returned without contacting the garden cluster. Otherwise it is read from the cluster-identity ConfigMap in the
+	// kube-system namespace of the garden cluster and cached in the cache directory, so that it is not looked up by
+	// every command. The identity of the garden in the gardenctl configuration is returned if the ConfigMap cannot be read.
+	ClusterIdentity(ctx context.Context, name string) string
+	// GardenClientFromConfig returns a gardenClient for the given client config,
+	// e.g. for a garden cluster that is not yet defined in the gardenctl configuration
+	GardenClientFromConfig(clientConfig clientcmd.ClientConfig) (gardenclient.Client, error)
+}
+
+// clientConfigsDirectory is the directory in the session directory that contains the cached client configs of the targets
+const clientConfigsDirectory = "client-configs"
+
+// maxConcurrentGardens limits the number of gardens that are processed at the same time by ForEachGarden
+const maxConcurrentGardens = 5
+
+const (
+	// clusterIdentityNamespace is the namespace of the ConfigMap that contains the cluster identity of a garden
+	clusterIdentityNamespace = "kube-system"
+	// clusterIdentityName is the name of the ConfigMap that contains the cluster identity of a garden
+	clusterIdentityName = "cluster-identity"
+	// clusterIdentityKey is the key of the cluster identity in the ConfigMap
+	clusterIdentityKey = "cluster-identity"
+)
+
+// GardenError is the error of an operation that failed for a single garden
+type GardenError struct {
+	// Garden is the name of the garden the operation failed for
+	Garden string
+	// Err is the error of the operation
+	Err error
+}
+
+var _ error = &GardenError{}
+
+func (e *GardenError) Error() string {
+	return fmt.Sprintf("garden %s: %v", e.Garden, e.Err)
+}
+
+func (e *GardenError) Unwrap() error {
+	return e.Err
+}
+
+// DuplicateGardenError is the error of a garden that has been skipped by ForEachGarden, because it has the
+// same cluster identity as a garden that precedes it in the gardenctl configuration
+type DuplicateGardenError struct {
+	// ClusterIdentity is the cluster identity of both gardens
+	ClusterIdentity string
+	// Garden is the name of the preceding garden, which has been processed instead
+	Garden string
+}
+
+var _ error = &DuplicateGardenError{}
+
+func (e *DuplicateGardenError) Error() string {
+	return fmt.Sprintf("skipped, it has the same cluster identity %q as garden %s", e.ClusterIdentity, e.Garden)
+}
+
+// IsDuplicateGarden returns true if the garden has been skipped, because it is a duplicate of another garden
+func IsDuplicateGarden(err error) bool {
+	var duplicateErr *DuplicateGardenError
+
+	return errors.As(err, &duplicateErr)
 }
 
 type managerImpl struct {
@@ -97,6 +187,14 @@ type managerImpl struct {
 	targetProvider   TargetProvider
 	clientProvider   ClientProvider
 	sessionDirectory string
+
+	// gardenClients caches the garden clients by garden name
+	gardenClients     map[string]gardenclient.Client
+	gardenClientsLock sync.Mutex
+
+	// clusterIdentities caches the cluster identities by garden name
+	clusterIdentities     map[string]string
+	clusterIdentitiesLock sync.Mutex
 }
 
 var _ Manager = &managerImpl{}
@@ -107,21 +205,27 @@ func newGardenClient(name string, config *config.Config, provider ClientProvider
 		return nil, err
 	}
 
+	return newGardenClientFromConfig(clientConfig, newProtectedClientProvider(provider, config, name))
+}
+
+func newGardenClientFromConfig(clientConfig clientcmd.ClientConfig, provider ClientProvider) (gardenclient.Client, error) {
 	client, err := provider.FromClientConfig(clientConfig)
 	if err != nil {
 		return nil, err
 	}
 
-	return gardenclient.NewGardenClient(client), nil
+	return gardenclient.NewGardenClientWithClientConfig(client, clientConfig), nil
 }
 
 // NewManager returns a new manager
 func NewManager(config *config.Config, targetProvider TargetProvider, clientProvider ClientProvider, sessionDirectory string) (Manager, error) {
 	return &managerImpl{
-		config:           config,
-		targetProvider:   targetProvider,
-		clientProvider:   clientProvider,
-		sessionDirectory: sessionDirectory,
+		config:            config,
+		targetProvider:    targetProvider,
+		clientProvider:    clientProvider,
+		sessionDirectory:  sessionDirectory,
+		gardenClients:     map[string]gardenclient.Client{},
+		clusterIdentities: map[string]string{},
 	}, nil
 }
 
@@ -283,7 +387,13 @@ func (m *managerImpl) TargetShoot(ctx context.Context, shootName string) error {
 
 	tb.Init(currentTarget)
 
-	target, err := tb.SetShoot(ctx, shootName).Build()
+	tb.SetShoot(ctx, shootName)
+
+	if m.TargetFlags().ControlPlane() {
+		tb.SetControlPlane(ctx)
+	}
+
+	target, err := tb.Build()
 	if err != nil {
 		return err
 	}
@@ -392,6 +502,14 @@ func (m *managerImpl) TargetMatchPattern(ctx context.Context, value string) erro
 		tb.SetNamespace(ctx, tm.Namespace)
 	}
 
+	if tm.Seed != "" {
+		if tm.Project != "" || tm.Namespace != "" {
+			return fmt.Errorf("seed %q and a project or namespace set in target match value. It is forbidden to have both values set", tm.Seed)
+		}
+
+		tb.SetSeed(ctx, tm.Seed)
+	}
+
 	if tm.Shoot != "" {
 		tb.SetShoot(ctx, tm.Shoot)
 	}
@@ -408,7 +526,43 @@ func (m *managerImpl) TargetMatchPattern(ctx context.Context, value string) erro
 	return m.updateTarget(ctx, target)
 }
 
+func (m *managerImpl) ReplaceTarget(ctx context.Context, t Target) error {
+	tb, err := NewTargetBuilder(m.config, m.clientProvider)
+	if err != nil {
+		return fmt.Errorf("failed to create new target builder: %w", err)
+	}
+
+	tb.SetGarden(t.GardenName())
+
+	if t.ProjectName() != "" {
+		tb.SetProject(ctx, t.ProjectName())
+	}
+
+	if t.SeedName() != "" {
+		tb.SetSeed(ctx, t.SeedName())
+	}
+
+	if t.ShootName() != "" {
+		tb.SetShoot(ctx, t.ShootName())
+	}
+
+	if t.ControlPlane() {
+		tb.SetControlPlane(ctx)
+	}
+
+	target, err := tb.Build()
+	if err != nil {
+		return err
+	}
+
+	return m.updateTarget(ctx, target)
+}
+
 func (m *managerImpl) updateTarget(ctx context.Context, target Target) error {
+	if err := m.checkAccess(ctx, target); err != nil {
+		return err
+	}
+
 	return m.patchTarget(ctx, func(t *targetImpl) error {
 		t.Garden = target.GardenName()
 		t.Project = target.ProjectName()
@@ -420,7 +574,69 @@ func (m *managerImpl) updateTarget(ctx context.Context, target Target) error {
 	})
 }
 
+// ClientConfig returns the client config for the target. If security.cacheClientConfigs is enabled, the client configs
+// of targets that require access to the garden cluster are cached in the session directory. If the garden cluster is
+// unreachable, e.g. in offline mode, the cached client config is returned instead.
 func (m *managerImpl) ClientConfig(ctx context.Context, t Target) (clientcmd.ClientConfig, error) {
+	if t.ProjectName() == "" && t.SeedName() == "" && t.ShootName() == "" {
+		return m.clientConfig(ctx, t)
+	}
+
+	filename := m.clientConfigCacheFile(t)
+
+	clientConfig, err := m.clientConfig(ctx, t)
+	if err != nil {
+		if !IsUnreachable(err) {
+			return nil, err
+		}
+
+		data, readErr := os.ReadFile(filename)
+		if readErr != nil {
+			return nil, err
+		}
+
+		klog.V(1).Infof("using cached client config for target %s: %v", targetKey(t), err)
+
+		return clientcmd.NewClientConfigFromBytes(data)
+	}
+
+	// the cache contains credentials, it is only written if the offline support has been enabled explicitly
+	if !m.config.CacheClientConfigs() {
+		return clientConfig, nil
+	}
+
+	if err := writeClientConfigCache(filename, clientConfig); err != nil {
+		return nil, fmt.Errorf("failed to cache client config for the offline mode: %w", err)
+	}
+
+	return clientConfig, nil
+}
+
+// writeClientConfigCache writes the client config to the given cache file, which is only readable by the user
+func writeClientConfigCache(filename string, clientConfig clientcmd.ClientConfig) error {
+	data, err := writeRawConfig(clientConfig)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filename), 0700); err != nil {
+		return err
+	}
+
+	return os.WriteFile(filename, data, 0600)
+}
+
+// clientConfigCacheFile returns the name of the file in the session directory the client config of the target is cached in
+func (m *managerImpl) clientConfigCacheFile(t Target) string {
+	return filepath.Join(m.sessionDirectory, clientConfigsDirectory, fmt.Sprintf("%x.yaml", md5.Sum([]byte(targetKey(t)))))
+}
+
+// targetKey returns a string that identifies the target, including the control plane flag
+func targetKey(t Target) string {
+	return fmt.Sprintf("%s/%s/%s/%s/%t", t.GardenName(), t.ProjectName(), t.SeedName(), t.ShootName(), t.ControlPlane())
+}
+
+func (m *managerImpl) clientConfig(ctx context.Context, t Target) (clientcmd.ClientConfig, error) {
 	if t.ControlPlane() {
 		return m.getClientConfig(t, func(client gardenclient.Client) (clientcmd.ClientConfig, error) {
 			shoot, err := client.FindShoot(ctx, t.WithControlPlane(false).AsListOption())
@@ -499,6 +715,10 @@ func (m *managerImpl) ClientConfig(ctx context.Context, t Target) (clientcmd.Cli
 }
 
 func (m *managerImpl) WriteClientConfig(config clientcmd.ClientConfig) (string, error) {
+	if m.config.EphemeralKubeconfigs() {
+		return "", ErrEphemeralKubeconfigs
+	}
+
 	data, err := writeRawConfig(config)
 	if err != nil {
 		return "", fmt.Errorf("failed to serialize temporary kubeconfig file: %w", err)
@@ -537,7 +757,7 @@ func (m *managerImpl) SeedClient(ctx context.Context, t Target) (client.Client,
 		return nil, err
 	}
 
-	return m.clientProvider.FromClientConfig(config)
+	return newProtectedClientProvider(m.clientProvider, m.config, t.GardenName()).FromClientConfig(config)
 }
 
 func (m *managerImpl) ShootClient(ctx context.Context, t Target) (client.Client, error) {
@@ -559,7 +779,7 @@ func (m *managerImpl) ShootClient(ctx context.Context, t Target) (client.Client,
 		return nil, err
 	}
 
-	return m.clientProvider.FromClientConfig(config)
+	return newProtectedClientProvider(m.clientProvider, m.config, t.GardenName()).FromClientConfig(config)
 }
 
 func (m *managerImpl) getClientConfig(t Target, loadClientConfig func(gardenclient.Client) (clientcmd.ClientConfig, error)) (clientcmd.ClientConfig, error) {
@@ -583,15 +803,33 @@ func (m *managerImpl) patchTarget(ctx context.Context, patch func(t *targetImpl)
 		return errors.New("target must be using targetImpl as its underlying type")
 	}
 
+	previous := *impl
+
+	// in dry-run mode neither the target, nor the history or the kubeconfig symlink are changed
+	if m.config.IsDryRun() {
+		patched := previous
+		if err := patch(&patched); err != nil {
+			return err
+		}
+
+		return m.printTargetDiff(&previous, &patched)
+	}
+
 	if err := patch(impl); err != nil {
 		return err
 	}
 
 	err = m.targetProvider.Write(impl)
+	m.auditTarget(impl, err)
+
 	if err != nil {
 		return err
 	}
 
+	if err := m.addToTargetHistory(&previous, impl); err != nil {
+		return fmt.Errorf("failed to update target history: %w", err)
+	}
+
 	if !m.config.SymlinkTargetKubeconfig() {
 		return nil
 	}
@@ -637,7 +875,226 @@ func (m *managerImpl) getTarget(t Target) (Target, error) {
 }
 
 func (m *managerImpl) GardenClient(name string) (gardenclient.Client, error) {
-	return newGardenClient(name, m.config, m.clientProvider)
+	m.gardenClientsLock.Lock()
+	gardenClient, ok := m.gardenClients[name]
+	m.gardenClientsLock.Unlock()
+
+	if ok {
+		return gardenClient, nil
+	}
+
+	// the client is created without holding the lock, so that the clients of several gardens can be created concurrently
+	gardenClient, err := newGardenClient(name, m.config, m.clientProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	m.gardenClientsLock.Lock()
+	defer m.gardenClientsLock.Unlock()
+
+	if cached, ok := m.gardenClients[name]; ok {
+		return cached, nil
+	}
+
+	m.gardenClients[name] = gardenClient
+
+	return gardenClient, nil
+}
+
+func (m *managerImpl) ForEachGarden(ctx context.Context, fn func(ctx context.Context, gardenName string, gardenClient gardenclient.Client) error) []*GardenError {
+	var (
+		errorsLock sync.Mutex
+		gardenErrs = []*GardenError{}
+		names      = m.config.GardenNames()
+		clients    = make([]gardenclient.Client, len(names))
+		identities = make([]string, len(names))
+	)
+
+	addError := func(name string, err error) {
+		errorsLock.Lock()
+		defer errorsLock.Unlock()
+
+		gardenErrs = append(gardenErrs, &GardenError{Garden: name, Err: err})
+	}
+
+	// the clients are created and the cluster identities are looked up first, so that a cluster
+	// that is configured as more than one garden is only processed once
+	forEachIndex(len(names), func(i int) {
+		if err := ctx.Err(); err != nil {
+			addError(names[i], err)
+			return
+		}
+
+		gardenClient, err := m.GardenClient(names[i])
+		if err != nil {
+			addError(names[i], fmt.Errorf("failed to create garden cluster client: %w", err))
+			return
+		}
+
+		clients[i] = gardenClient
+		identities[i] = m.ClusterIdentity(ctx, names[i])
+	})
+
+	unique := []int{}
+	gardenByIdentity := map[string]string{}
+
+	for i, name := range names {
+		if clients[i] == nil {
+			continue
+		}
+
+		if other, ok := gardenByIdentity[identities[i]]; ok {
+			addError(name, &DuplicateGardenError{ClusterIdentity: identities[i], Garden: other})
+			continue
+		}
+
+		gardenByIdentity[identities[i]] = name
+		unique = append(unique, i)
+	}
+
+	forEachIndex(len(unique), func(j int) {
+		i := unique[j]
+
+		err := ctx.Err()
+		if err == nil {
+			err = fn(ctx, names[i], clients[i])
+		}
+
+		if err != nil {
+			addError(names[i], err)
+		}
+	})
+
+	sort.Slice(gardenErrs, func(i, j int) bool {
+		return gardenErrs[i].Garden < gardenErrs[j].Garden
+	})
+
+	return gardenErrs
+}
+
+// forEachIndex calls fn for the indexes 0 to n-1 concurrently by a bounded number of workers and waits until all calls have returned
+func forEachIndex(n int, fn func(i int)) {
+	var (
+		wg      sync.WaitGroup
+		indexes = make(chan int)
+	)
+
+	workers := maxConcurrentGardens
+	if n < workers {
+		workers = n
+	}
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range indexes {
+				fn(i)
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+
+	close(indexes)
+	wg.Wait()
+}
+
+func (m *managerImpl) ClusterIdentity(ctx context.Context, name string) string {
+	garden, err := m.config.Garden(name)
+	if err != nil {
+		return name
+	}
+
+	if garden.ClusterIdentity != "" {
+		return garden.ClusterIdentity
+	}
+
+	m.clusterIdentitiesLock.Lock()
+	identity, ok := m.clusterIdentities[garden.Name]
+	m.clusterIdentitiesLock.Unlock()
+
+	if ok {
+		return identity
+	}
+
+	filename := clusterIdentityCacheFile(m.config.CacheDir, garden)
+
+	identity, ok = readClusterIdentityCache(filename)
+	if !ok {
+		identity = garden.Name
+
+		if gardenClient, err := m.GardenClient(garden.Name); err == nil {
+			cm, err := gardenClient.GetConfigMap(ctx, clusterIdentityNamespace, clusterIdentityName)
+			if err != nil {
+				klog.V(1).Infof("failed to look up the cluster identity of garden %s, using the garden identity: %v", garden.Name, err)
+			} else if cm.Data[clusterIdentityKey] != "" {
+				identity = cm.Data[clusterIdentityKey]
+
+				// the cache is only an optimization, the identity is looked up again if it cannot be written
+				if err := writeClusterIdentityCache(filename, identity); err != nil {
+					klog.V(1).Infof("failed to cache the cluster identity of garden %s: %v", garden.Name, err)
+				}
+			}
+		}
+	}
+
+	m.clusterIdentitiesLock.Lock()
+	defer m.clusterIdentitiesLock.Unlock()
+
+	m.clusterIdentities[garden.Name] = identity
+
+	return identity
+}
+
+// clusterIdentityCacheFile returns the name of the file in the cache directory the cluster identity of the garden is
+// cached in. The name depends on the kubeconfig of the garden, so that the identity is looked up again if the garden
+// is configured for a different cluster. It is empty if caching is disabled.
+func clusterIdentityCacheFile(cacheDir string, garden *config.Garden) string {
+	if cacheDir == "" {
+		return ""
+	}
+
+	key := fmt.Sprintf("%s/%s/%s/%s", garden.Name, garden.Kubeconfig, garden.KubeconfigData, garden.Context)
+
+	return filepath.Join(cacheDir, fmt.Sprintf("cluster-identity-%x", md5.Sum([]byte(key))))
+}
+
+// readClusterIdentityCache returns the cached cluster identity and false if it is not cached
+func readClusterIdentityCache(filename string) (string, bool) {
+	if filename == "" {
+		return "", false
+	}
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return "", false
+	}
+
+	identity := strings.TrimSpace(string(data))
+
+	return identity, identity != ""
+}
+
+// writeClusterIdentityCache caches the cluster identity in the given file, nothing is written if caching is disabled
+func writeClusterIdentityCache(filename, identity string) error {
+	if filename == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filename), 0700); err != nil {
+		return err
+	}
+
+	return os.WriteFile(filename, []byte(identity+"\n"), 0600)
+}
+
+func (m *managerImpl) GardenClientFromConfig(clientConfig clientcmd.ClientConfig) (gardenclient.Client, error) {
+	return newGardenClientFromConfig(clientConfig, m.clientProvider)
 }
 
 func writeRawConfig(config clientcmd.ClientConfig) ([]byte, error) {
@@ -7,20 +7,35 @@ SPDX-License-Identifier: Apache-2.0
 package target_test
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 
+	authenticationv1alpha1 "github.com/gardener/gardener/pkg/apis/authentication/v1alpha1"
 	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/gardener/gardenctl-v2/internal/fake"
+	"github.com/gardener/gardenctl-v2/internal/gardenclient"
 	"github.com/gardener/gardenctl-v2/pkg/config"
 	"github.com/gardener/gardenctl-v2/pkg/target"
 	targetmocks "github.com/gardener/gardenctl-v2/pkg/target/mocks"
@@ -65,6 +80,22 @@ func createTestShoot(name string, namespace string, seedName *string) *gardencor
 	return shoot
 }
 
+// accessDeniedClient denies every SelfSubjectAccessReview
+type accessDeniedClient struct {
+	client.Client
+}
+
+func (c *accessDeniedClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if review, ok := obj.(*authorizationv1.SelfSubjectAccessReview); ok {
+		review.Status.Allowed = false
+		review.Status.Reason = "no RBAC policy matched"
+
+		return nil
+	}
+
+	return c.Client.Create(ctx, obj, opts...)
+}
+
 func cloneTarget(t target.Target) target.Target {
 	return target.NewTarget(t.GardenName(), t.ProjectName(), t.SeedName(), t.ShootName()).WithControlPlane(t.ControlPlane())
 }
@@ -107,6 +138,8 @@ var _ = Describe("Target Manager", func() {
 				Patterns: []string{
 					fmt.Sprintf("^(%s/)?shoot--(?P<project>.+)--(?P<shoot>.+)$", gardenName),
 					"^namespace:(?P<namespace>[^/]+)$",
+					"^seed:(?P<seed>[^/]+)$",
+					"^seed:(?P<seed>[^/]+)/namespace:(?P<namespace>[^/]+)$",
 				},
 			}},
 		}
@@ -289,7 +322,9 @@ var _ = Describe("Target Manager", func() {
 		manager, targetProvider := createTestManager(t, cfg, clientProvider)
 
 		// another seed is already targeted, so even though this shoot exists, it does not match
-		Expect(manager.TargetShoot(ctx, prod1PendingShoot.Name)).NotTo(Succeed())
+		err := manager.TargetShoot(ctx, prod1PendingShoot.Name)
+		Expect(err).To(HaveOccurred())
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
 		assertTargetProvider(targetProvider, t)
 	})
 
@@ -302,6 +337,36 @@ var _ = Describe("Target Manager", func() {
 		assertTargetProvider(targetProvider, target.NewTarget(gardenName, prod1Project.Name, "", prod1GoldenShoot.Name))
 	})
 
+	It("should be able to target valid shoots in the default garden if no garden is targeted", func() {
+		cfg.Gardens = append(cfg.Gardens, config.Garden{Name: "other-garden", Kubeconfig: gardenKubeconfig})
+		cfg.DefaultGarden = gardenName
+		t := target.NewTarget("", "", "", "")
+		manager, targetProvider := createTestManager(t, cfg, clientProvider)
+
+		Expect(manager.TargetShoot(ctx, prod1GoldenShoot.Name)).To(Succeed())
+		assertTargetProvider(targetProvider, target.NewTarget(gardenName, prod1Project.Name, "", prod1GoldenShoot.Name))
+	})
+
+	It("should not persist the target if the user is not allowed to read the shoot", func() {
+		clientProvider = targetmocks.NewMockClientProvider(ctrl)
+		clientProvider.EXPECT().FromClientConfig(gomock.Any()).Return(&accessDeniedClient{gardenClient}, nil).AnyTimes()
+		t := target.NewTarget(gardenName, prod1Project.Name, "", "")
+		manager, targetProvider := createTestManager(t, cfg, clientProvider)
+
+		err := manager.TargetShoot(ctx, prod1GoldenShoot.Name)
+		Expect(errors.Is(err, gardenclient.ErrAccessDenied)).To(BeTrue())
+		Expect(err).To(MatchError(`access denied: you are not allowed to get shoots.core.gardener.cloud "golden-shoot" in namespace "garden-prod1": no RBAC policy matched`))
+		assertTargetProvider(targetProvider, t)
+	})
+
+	It("should fail to target shoots if no garden is targeted and no default garden is configured", func() {
+		t := target.NewTarget("", "", "", "")
+		manager, targetProvider := createTestManager(t, cfg, clientProvider)
+
+		Expect(manager.TargetShoot(ctx, prod1GoldenShoot.Name)).To(MatchError(target.ErrNoGardenTargeted))
+		assertTargetProvider(targetProvider, t)
+	})
+
 	It("should error when multiple shoots match", func() {
 		t := target.NewTarget(gardenName, "", "", "")
 		manager, targetProvider := createTestManager(t, cfg, clientProvider)
@@ -350,6 +415,22 @@ var _ = Describe("Target Manager", func() {
 		assertTargetProvider(targetProvider, target.NewTarget(gardenName, prod1Project.Name, "", ""))
 	})
 
+	It("should be able to target valid seed by matching a pattern containing a seed", func() {
+		t := target.NewTarget(gardenName, prod1Project.Name, "", prod1GoldenShoot.Name)
+		manager, targetProvider := createTestManager(t, cfg, clientProvider)
+
+		Expect(manager.TargetMatchPattern(ctx, fmt.Sprintf("seed:%s", seed.Name))).To(Succeed())
+		assertTargetProvider(targetProvider, target.NewTarget(gardenName, "", seed.Name, ""))
+	})
+
+	It("should fail to target a seed by matching a pattern containing a seed and a namespace", func() {
+		t := target.NewTarget(gardenName, "", "", "")
+		manager, targetProvider := createTestManager(t, cfg, clientProvider)
+
+		Expect(manager.TargetMatchPattern(ctx, fmt.Sprintf("seed:%s/namespace:%s", seed.Name, *prod1Project.Spec.Namespace))).To(MatchError(ContainSubstring("It is forbidden to have both values set")))
+		assertTargetProvider(targetProvider, t)
+	})
+
 	It("should be able to target control plane for a shoot", func() {
 		t := target.NewTarget(gardenName, prod1Project.Name, "", prod1GoldenShoot.Name)
 		manager, targetProvider := createTestManager(t, cfg, clientProvider)
@@ -358,6 +439,16 @@ var _ = Describe("Target Manager", func() {
 		assertTargetProvider(targetProvider, t.WithControlPlane(true))
 	})
 
+	It("should target the control plane of the shoot if the control plane flag is set", func() {
+		targetProvider := target.NewTargetProvider(filepath.Join(sessionDir, "control-plane-target.yaml"), target.NewTargetFlags("", "", "", "", true))
+		Expect(targetProvider.Write(target.NewTarget(gardenName, prod1Project.Name, "", ""))).To(Succeed())
+		manager, err := target.NewManager(cfg, targetProvider, clientProvider, sessionDir)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(manager.TargetShoot(ctx, prod1GoldenShoot.Name)).To(Succeed())
+		assertTargetProvider(targetProvider, target.NewTarget(gardenName, prod1Project.Name, "", prod1GoldenShoot.Name).WithControlPlane(true))
+	})
+
 	It("should fail to target control plane if shoot is not set", func() {
 		t := target.NewTarget(gardenName, prod1Project.Name, "", "")
 		manager, targetProvider := createTestManager(t, cfg, clientProvider)
@@ -383,6 +474,134 @@ var _ = Describe("Target Manager", func() {
 		Expect(newClient).NotTo(BeNil())
 	})
 
+	It("should create the garden client only once per garden", func() {
+		t := target.NewTarget(gardenName, "", "", "")
+		singleClientProvider := targetmocks.NewMockClientProvider(ctrl)
+		singleClientProvider.EXPECT().FromClientConfig(gomock.Any()).Return(gardenClient, nil).Times(1)
+		manager, _ := createTestManager(t, cfg, singleClientProvider)
+
+		firstClient, err := manager.GardenClient(t.GardenName())
+		Expect(err).NotTo(HaveOccurred())
+
+		secondClient, err := manager.GardenClient(t.GardenName())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(secondClient).To(BeIdenticalTo(firstClient))
+	})
+
+	It("should call a function for each garden and isolate the errors of the gardens", func() {
+		cfg.Gardens = append(cfg.Gardens,
+			config.Garden{Name: "broken-garden", Kubeconfig: "/not/a/real/kubeconfig"},
+			config.Garden{Name: "failing-garden", Kubeconfig: gardenKubeconfig},
+		)
+		brokenClientConfig, err := cfg.ClientConfig("broken-garden")
+		Expect(err).NotTo(HaveOccurred())
+		clientProvider.EXPECT().FromClientConfig(gomock.Eq(brokenClientConfig)).Return(nil, errors.New("connection refused"))
+		manager, _ := createTestManager(target.NewTarget("", "", "", ""), cfg, clientProvider)
+
+		var (
+			lock    sync.Mutex
+			visited []string
+		)
+
+		gardenErrs := manager.ForEachGarden(ctx, func(ctx context.Context, gardenName string, gardenClient gardenclient.Client) error {
+			lock.Lock()
+			visited = append(visited, gardenName)
+			lock.Unlock()
+
+			if gardenName == "failing-garden" {
+				return errors.New("list failed")
+			}
+
+			_, err := gardenClient.GetProject(ctx, prod1Project.Name)
+
+			return err
+		})
+
+		Expect(visited).To(ConsistOf(gardenName, "failing-garden"))
+		Expect(gardenErrs).To(HaveLen(2))
+		Expect(gardenErrs[0].Garden).To(Equal("broken-garden"))
+		Expect(gardenErrs[0]).To(MatchError("garden broken-garden: failed to create garden cluster client: connection refused"))
+		Expect(gardenErrs[1].Garden).To(Equal("failing-garden"))
+		Expect(gardenErrs[1].Err).To(MatchError("list failed"))
+	})
+
+	It("should skip gardens with the same cluster identity as a preceding garden", func() {
+		Expect(gardenClient.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-identity", Namespace: "kube-system"},
+			Data:       map[string]string{"cluster-identity": "landscape-prod"},
+		})).To(Succeed())
+
+		cfg.Gardens = append(cfg.Gardens, config.Garden{Name: "copy-garden", Kubeconfig: gardenKubeconfig})
+		manager, _ := createTestManager(target.NewTarget("", "", "", ""), cfg, clientProvider)
+
+		Expect(manager.ClusterIdentity(ctx, gardenName)).To(Equal("landscape-prod"))
+		Expect(manager.ClusterIdentity(ctx, "unknown-garden")).To(Equal("unknown-garden"))
+
+		var (
+			lock    sync.Mutex
+			visited []string
+		)
+
+		gardenErrs := manager.ForEachGarden(ctx, func(ctx context.Context, gardenName string, gardenClient gardenclient.Client) error {
+			lock.Lock()
+			defer lock.Unlock()
+
+			visited = append(visited, gardenName)
+
+			return nil
+		})
+
+		Expect(visited).To(ConsistOf(gardenName))
+		Expect(gardenErrs).To(HaveLen(1))
+		Expect(gardenErrs[0].Garden).To(Equal("copy-garden"))
+		Expect(target.IsDuplicateGarden(gardenErrs[0])).To(BeTrue())
+		Expect(gardenErrs[0].Err).To(MatchError(`skipped, it has the same cluster identity "landscape-prod" as garden ` + gardenName))
+	})
+
+	It("should not look up the configured cluster identity", func() {
+		cfg.Gardens[0].ClusterIdentity = "landscape-dev"
+		cfg.Gardens = append(cfg.Gardens, config.Garden{Name: "copy-garden", ClusterIdentity: "landscape-dev", Kubeconfig: gardenKubeconfig})
+		manager, _ := createTestManager(target.NewTarget("", "", "", ""), cfg, clientProvider)
+
+		// the garden cluster has no cluster-identity ConfigMap
+		Expect(manager.ClusterIdentity(ctx, gardenName)).To(Equal("landscape-dev"))
+
+		gardenErrs := manager.ForEachGarden(ctx, func(ctx context.Context, gardenName string, gardenClient gardenclient.Client) error {
+			return nil
+		})
+
+		Expect(gardenErrs).To(HaveLen(1))
+		Expect(gardenErrs[0].Garden).To(Equal("copy-garden"))
+		Expect(target.IsDuplicateGarden(gardenErrs[0])).To(BeTrue())
+	})
+
+	It("should cache the cluster identity in the cache directory", func() {
+		cacheDir, err := os.MkdirTemp("", "gctlv2-cache-")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(cacheDir)
+
+		cfg.CacheDir = cacheDir
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-identity", Namespace: "kube-system"},
+			Data:       map[string]string{"cluster-identity": "landscape-prod"},
+		}
+		Expect(gardenClient.Create(ctx, configMap)).To(Succeed())
+
+		manager, _ := createTestManager(target.NewTarget("", "", "", ""), cfg, clientProvider)
+		Expect(manager.ClusterIdentity(ctx, gardenName)).To(Equal("landscape-prod"))
+
+		// another command must not look up the cluster identity again
+		Expect(gardenClient.Delete(ctx, configMap)).To(Succeed())
+		manager, _ = createTestManager(target.NewTarget("", "", "", ""), cfg, clientProvider)
+		Expect(manager.ClusterIdentity(ctx, gardenName)).To(Equal("landscape-prod"))
+
+		// the identity is looked up again if the garden is configured for a different cluster
+		cfg.Gardens[0].Context = "other"
+		clientProvider.EXPECT().FromClientConfig(gomock.Any()).Return(nil, errors.New("connection refused"))
+		manager, _ = createTestManager(target.NewTarget("", "", "", ""), cfg, clientProvider)
+		Expect(manager.ClusterIdentity(ctx, gardenName)).To(Equal(gardenName))
+	})
+
 	It("should provide a seed client", func() {
 		t := target.NewTarget(gardenName, "", seed.Name, "")
 		manager, _ := createTestManager(t, cfg, clientProvider)
@@ -522,6 +741,206 @@ var _ = Describe("Target Manager", func() {
 		assertTargetProvider(targetProvider, t)
 	})
 
+	Describe("Requested Kubeconfigs", func() {
+		var (
+			manager          target.Manager
+			sessionDirectory string
+			server           *httptest.Server
+			requests         int
+			kinds            []string
+			expiration       time.Duration
+		)
+
+		BeforeEach(func() {
+			requests = 0
+			kinds = nil
+			expiration = time.Hour
+			server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				defer GinkgoRecover()
+
+				Expect(r.Method).To(Equal(http.MethodPost))
+				Expect(r.URL.Path).To(HavePrefix("/apis/core.gardener.cloud/v1beta1/namespaces/garden-prod1/shoots/golden-shoot/"))
+
+				request := &authenticationv1alpha1.AdminKubeconfigRequest{}
+				Expect(json.NewDecoder(r.Body).Decode(request)).To(Succeed())
+				Expect(request.Spec.ExpirationSeconds).To(Equal(pointer.Int64(int64(expiration.Seconds()))))
+				Expect(strings.ToLower(request.Kind)).To(Equal(path.Base(r.URL.Path) + "request"))
+
+				requests++
+				kinds = append(kinds, request.Kind)
+				request = &authenticationv1alpha1.AdminKubeconfigRequest{
+					TypeMeta: metav1.TypeMeta{
+						APIVersion: authenticationv1alpha1.SchemeGroupVersion.String(),
+						Kind:       "AdminKubeconfigRequest",
+					},
+					Status: authenticationv1alpha1.AdminKubeconfigRequestStatus{
+						Kubeconfig:          createTestKubeconfig(fmt.Sprintf("kubeconfig-%d", requests)),
+						ExpirationTimestamp: metav1.NewTime(time.Now().Add(expiration)),
+					},
+				}
+				w.Header().Set("Content-Type", "application/json")
+				Expect(json.NewEncoder(w).Encode(request)).To(Succeed())
+			}))
+
+			kubeconfig := clientcmdapi.NewConfig()
+			kubeconfig.Clusters["garden"] = &clientcmdapi.Cluster{Server: server.URL, InsecureSkipTLSVerify: true}
+			kubeconfig.AuthInfos["user"] = &clientcmdapi.AuthInfo{Token: "token"}
+			kubeconfig.Contexts["garden"] = &clientcmdapi.Context{Cluster: "garden", AuthInfo: "user"}
+			kubeconfig.CurrentContext = "garden"
+			dir, err := os.MkdirTemp(gardenHomeDir, "session-*")
+			Expect(err).NotTo(HaveOccurred())
+			cfg.Gardens[0].Kubeconfig = filepath.Join(dir, "kubeconfig.yaml")
+			Expect(clientcmd.WriteToFile(*kubeconfig, cfg.Gardens[0].Kubeconfig)).To(Succeed())
+
+			clientProvider = targetmocks.NewMockClientProvider(ctrl)
+			clientProvider.EXPECT().FromClientConfig(gomock.Any()).Return(gardenClient, nil).AnyTimes()
+			manager, err = target.NewManager(cfg, fake.NewFakeTargetProvider(target.NewTarget(gardenName, prod1Project.Name, "", prod1GoldenShoot.Name)), clientProvider, dir)
+			Expect(err).NotTo(HaveOccurred())
+			sessionDirectory = dir
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("should request the admin kubeconfig once and use the cached kubeconfig afterwards", func() {
+			for i := 0; i < 2; i++ {
+				clientConfig, err := manager.AdminKubeconfig(ctx, nil, time.Hour)
+				Expect(err).NotTo(HaveOccurred())
+				rawConfig, err := clientConfig.RawConfig()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(rawConfig.CurrentContext).To(Equal("kubeconfig-1"))
+			}
+
+			Expect(requests).To(Equal(1))
+		})
+
+		It("should renew the admin kubeconfig if it is about to expire", func() {
+			expiration = time.Minute
+
+			_, err := manager.AdminKubeconfig(ctx, nil, time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+			clientConfig, err := manager.AdminKubeconfig(ctx, nil, time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+			rawConfig, err := clientConfig.RawConfig()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rawConfig.CurrentContext).To(Equal("kubeconfig-2"))
+			Expect(requests).To(Equal(2))
+		})
+
+		It("should renew the admin kubeconfig if a shorter expiration is requested", func() {
+			_, err := manager.AdminKubeconfig(ctx, nil, time.Hour)
+			Expect(err).NotTo(HaveOccurred())
+
+			expiration = 10 * time.Minute
+			for i := 0; i < 2; i++ {
+				clientConfig, err := manager.AdminKubeconfig(ctx, nil, expiration)
+				Expect(err).NotTo(HaveOccurred())
+				rawConfig, err := clientConfig.RawConfig()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(rawConfig.CurrentContext).To(Equal("kubeconfig-2"))
+			}
+
+			// a credential that expires earlier than requested is used as long as it is not about to expire
+			_, err = manager.AdminKubeconfig(ctx, nil, time.Hour)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(requests).To(Equal(2))
+		})
+
+		It("should request and cache viewer kubeconfigs separately from admin kubeconfigs", func() {
+			_, err := manager.AdminKubeconfig(ctx, nil, time.Hour)
+			Expect(err).NotTo(HaveOccurred())
+
+			for i := 0; i < 2; i++ {
+				clientConfig, err := manager.ViewerKubeconfig(ctx, nil, time.Hour)
+				Expect(err).NotTo(HaveOccurred())
+				rawConfig, err := clientConfig.RawConfig()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(rawConfig.CurrentContext).To(Equal("kubeconfig-2"))
+			}
+
+			Expect(kinds).To(Equal([]string{"AdminKubeconfigRequest", "ViewerKubeconfigRequest"}))
+		})
+
+		It("should not write the requested kubeconfigs to disk if ephemeral kubeconfigs are enforced", func() {
+			cfg.Security = &config.Security{EphemeralKubeconfigs: pointer.Bool(true)}
+
+			for i := 0; i < 2; i++ {
+				_, err := manager.AdminKubeconfig(ctx, nil, time.Hour)
+				Expect(err).NotTo(HaveOccurred())
+				_, err = manager.ViewerKubeconfig(ctx, nil, time.Hour)
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			Expect(requests).To(Equal(4))
+			Expect(filepath.Join(sessionDirectory, "admin-kubeconfigs")).NotTo(BeAnExistingFile())
+			Expect(filepath.Join(sessionDirectory, "viewer-kubeconfigs")).NotTo(BeAnExistingFile())
+		})
+
+		It("should fail if no shoot is targeted", func() {
+			_, err := manager.AdminKubeconfig(ctx, target.NewTarget(gardenName, prod1Project.Name, "", ""), time.Hour)
+			Expect(err).To(MatchError(target.ErrNoShootTargeted))
+		})
+	})
+
+	Describe("Target History", func() {
+		var (
+			manager        target.Manager
+			targetProvider target.TargetProvider
+		)
+
+		BeforeEach(func() {
+			dir, err := os.MkdirTemp(gardenHomeDir, "session-*")
+			Expect(err).NotTo(HaveOccurred())
+
+			targetProvider = fake.NewFakeTargetProvider(target.NewTarget(gardenName, "", "", ""))
+			manager, err = target.NewManager(cfg, targetProvider, clientProvider, dir)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should record the previous targets and go back to the previous target", func() {
+			Expect(manager.TargetProject(ctx, prod1Project.Name)).To(Succeed())
+			Expect(manager.TargetShoot(ctx, prod1GoldenShoot.Name)).To(Succeed())
+
+			history, err := manager.TargetHistory()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(history).To(Equal([]target.Target{
+				target.NewTarget(gardenName, prod1Project.Name, "", ""),
+				target.NewTarget(gardenName, "", "", ""),
+			}))
+
+			Expect(manager.TargetPrevious(ctx)).To(Succeed())
+			assertTargetProvider(targetProvider, target.NewTarget(gardenName, prod1Project.Name, "", ""))
+
+			Expect(manager.TargetPrevious(ctx)).To(Succeed())
+			assertTargetProvider(targetProvider, target.NewTarget(gardenName, prod1Project.Name, "", prod1GoldenShoot.Name))
+
+			history, err = manager.TargetHistory()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(history).To(Equal([]target.Target{
+				target.NewTarget(gardenName, prod1Project.Name, "", ""),
+				target.NewTarget(gardenName, "", "", ""),
+			}))
+		})
+
+		It("should not record a target more than once", func() {
+			for i := 0; i < 15; i++ {
+				Expect(manager.TargetProject(ctx, prod1Project.Name)).To(Succeed())
+				Expect(manager.TargetSeed(ctx, seed.Name)).To(Succeed())
+				Expect(manager.TargetShoot(ctx, prod1GoldenShoot.Name)).To(Succeed())
+				Expect(manager.TargetGarden(ctx, gardenName)).To(Succeed())
+			}
+
+			history, err := manager.TargetHistory()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(history).To(HaveLen(3))
+		})
+
+		It("should fail if there is no previous target", func() {
+			Expect(manager.TargetPrevious(ctx)).To(MatchError(target.ErrTargetHistoryEmpty))
+		})
+	})
+
 	Describe("Getting Client Configurations", func() {
 		var (
 			manager target.Manager
@@ -554,6 +973,58 @@ var _ = Describe("Target Manager", func() {
 				Expect(err).NotTo(HaveOccurred())
 				assertClientConfig(clientConfig, prod1GoldenShoot.Name, "default")
 			})
+
+			It("should return the cached client configuration in offline mode", func() {
+				cfg.Security = &config.Security{CacheClientConfigs: pointer.Bool(true)}
+
+				_, err := manager.ClientConfig(ctx, t)
+				Expect(err).NotTo(HaveOccurred())
+
+				offlineManager, _ := createTestManager(t, cfg, target.NewOfflineClientProvider())
+				clientConfig, err := offlineManager.ClientConfig(ctx, t)
+				Expect(err).NotTo(HaveOccurred())
+				assertClientConfig(clientConfig, prod1GoldenShoot.Name, "default")
+
+				_, err = offlineManager.ClientConfig(ctx, t.WithShootName("uncached-shoot"))
+				Expect(err).To(MatchError(ContainSubstring(target.ErrOffline.Error())))
+			})
+
+			It("should not cache the client configuration by default", func() {
+				Expect(os.RemoveAll(filepath.Join(sessionDir, "client-configs"))).To(Succeed())
+
+				_, err := manager.ClientConfig(ctx, t)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(filepath.Join(sessionDir, "client-configs")).NotTo(BeADirectory())
+
+				offlineManager, _ := createTestManager(t, cfg, target.NewOfflineClientProvider())
+				_, err = offlineManager.ClientConfig(ctx, t)
+				Expect(err).To(MatchError(ContainSubstring(target.ErrOffline.Error())))
+			})
+
+			It("should fail if the client configuration cannot be cached", func() {
+				cfg.Security = &config.Security{CacheClientConfigs: pointer.Bool(true)}
+				Expect(os.RemoveAll(filepath.Join(sessionDir, "client-configs"))).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(sessionDir, "client-configs"), nil, 0600)).To(Succeed())
+
+				defer os.Remove(filepath.Join(sessionDir, "client-configs"))
+
+				_, err := manager.ClientConfig(ctx, t)
+				Expect(err).To(MatchError(ContainSubstring("failed to cache client config for the offline mode")))
+			})
+
+			It("should neither cache nor write the client configuration with ephemeral kubeconfigs", func() {
+				cfg.Security = &config.Security{EphemeralKubeconfigs: pointer.Bool(true), CacheClientConfigs: pointer.Bool(true)}
+				Expect(os.RemoveAll(filepath.Join(sessionDir, "client-configs"))).To(Succeed())
+
+				clientConfig, err := manager.ClientConfig(ctx, t)
+				Expect(err).NotTo(HaveOccurred())
+				_, err = manager.WriteClientConfig(clientConfig)
+				Expect(err).To(MatchError(target.ErrEphemeralKubeconfigs))
+
+				offlineManager, _ := createTestManager(t, cfg, target.NewOfflineClientProvider())
+				_, err = offlineManager.ClientConfig(ctx, t)
+				Expect(err).To(MatchError(ContainSubstring(target.ErrOffline.Error())))
+			})
 		})
 
 		Context("when seed is targeted", func() {
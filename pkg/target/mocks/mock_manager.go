@@ -7,6 +7,7 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gardenclient "github.com/gardener/gardenctl-v2/internal/gardenclient"
 	config "github.com/gardener/gardenctl-v2/pkg/config"
@@ -39,6 +40,21 @@ func (m *MockManager) EXPECT() *MockManagerMockRecorder {
 	return m.recorder
 }
 
+// AdminKubeconfig mocks base method.
+func (m *MockManager) AdminKubeconfig(arg0 context.Context, arg1 target.Target, arg2 time.Duration) (clientcmd.ClientConfig, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdminKubeconfig", arg0, arg1, arg2)
+	ret0, _ := ret[0].(clientcmd.ClientConfig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdminKubeconfig indicates an expected call of AdminKubeconfig.
+func (mr *MockManagerMockRecorder) AdminKubeconfig(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminKubeconfig", reflect.TypeOf((*MockManager)(nil).AdminKubeconfig), arg0, arg1, arg2)
+}
+
 // ClientConfig mocks base method.
 func (m *MockManager) ClientConfig(arg0 context.Context, arg1 target.Target) (clientcmd.ClientConfig, error) {
 	m.ctrl.T.Helper()
@@ -54,6 +70,20 @@ func (mr *MockManagerMockRecorder) ClientConfig(arg0, arg1 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClientConfig", reflect.TypeOf((*MockManager)(nil).ClientConfig), arg0, arg1)
 }
 
+// ClusterIdentity mocks base method.
+func (m *MockManager) ClusterIdentity(arg0 context.Context, arg1 string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClusterIdentity", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ClusterIdentity indicates an expected call of ClusterIdentity.
+func (mr *MockManagerMockRecorder) ClusterIdentity(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClusterIdentity", reflect.TypeOf((*MockManager)(nil).ClusterIdentity), arg0, arg1)
+}
+
 // Configuration mocks base method.
 func (m *MockManager) Configuration() *config.Config {
 	m.ctrl.T.Helper()
@@ -83,6 +113,20 @@ func (mr *MockManagerMockRecorder) CurrentTarget() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CurrentTarget", reflect.TypeOf((*MockManager)(nil).CurrentTarget))
 }
 
+// ForEachGarden mocks base method.
+func (m *MockManager) ForEachGarden(arg0 context.Context, arg1 func(context.Context, string, gardenclient.Client) error) []*target.GardenError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForEachGarden", arg0, arg1)
+	ret0, _ := ret[0].([]*target.GardenError)
+	return ret0
+}
+
+// ForEachGarden indicates an expected call of ForEachGarden.
+func (mr *MockManagerMockRecorder) ForEachGarden(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForEachGarden", reflect.TypeOf((*MockManager)(nil).ForEachGarden), arg0, arg1)
+}
+
 // GardenClient mocks base method.
 func (m *MockManager) GardenClient(arg0 string) (gardenclient.Client, error) {
 	m.ctrl.T.Helper()
@@ -98,6 +142,35 @@ func (mr *MockManagerMockRecorder) GardenClient(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GardenClient", reflect.TypeOf((*MockManager)(nil).GardenClient), arg0)
 }
 
+// GardenClientFromConfig mocks base method.
+func (m *MockManager) GardenClientFromConfig(arg0 clientcmd.ClientConfig) (gardenclient.Client, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GardenClientFromConfig", arg0)
+	ret0, _ := ret[0].(gardenclient.Client)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GardenClientFromConfig indicates an expected call of GardenClientFromConfig.
+func (mr *MockManagerMockRecorder) GardenClientFromConfig(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GardenClientFromConfig", reflect.TypeOf((*MockManager)(nil).GardenClientFromConfig), arg0)
+}
+
+// ReplaceTarget mocks base method.
+func (m *MockManager) ReplaceTarget(arg0 context.Context, arg1 target.Target) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceTarget", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceTarget indicates an expected call of ReplaceTarget.
+func (mr *MockManagerMockRecorder) ReplaceTarget(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceTarget", reflect.TypeOf((*MockManager)(nil).ReplaceTarget), arg0, arg1)
+}
+
 // SeedClient mocks base method.
 func (m *MockManager) SeedClient(arg0 context.Context, arg1 target.Target) (client.Client, error) {
 	m.ctrl.T.Helper()
@@ -184,6 +257,21 @@ func (mr *MockManagerMockRecorder) TargetGarden(arg0, arg1 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TargetGarden", reflect.TypeOf((*MockManager)(nil).TargetGarden), arg0, arg1)
 }
 
+// TargetHistory mocks base method.
+func (m *MockManager) TargetHistory() ([]target.Target, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TargetHistory")
+	ret0, _ := ret[0].([]target.Target)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TargetHistory indicates an expected call of TargetHistory.
+func (mr *MockManagerMockRecorder) TargetHistory() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TargetHistory", reflect.TypeOf((*MockManager)(nil).TargetHistory))
+}
+
 // TargetMatchPattern mocks base method.
 func (m *MockManager) TargetMatchPattern(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -198,6 +286,20 @@ func (mr *MockManagerMockRecorder) TargetMatchPattern(arg0, arg1 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TargetMatchPattern", reflect.TypeOf((*MockManager)(nil).TargetMatchPattern), arg0, arg1)
 }
 
+// TargetPrevious mocks base method.
+func (m *MockManager) TargetPrevious(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TargetPrevious", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TargetPrevious indicates an expected call of TargetPrevious.
+func (mr *MockManagerMockRecorder) TargetPrevious(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TargetPrevious", reflect.TypeOf((*MockManager)(nil).TargetPrevious), arg0)
+}
+
 // TargetProject mocks base method.
 func (m *MockManager) TargetProject(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -314,6 +416,21 @@ func (mr *MockManagerMockRecorder) UnsetTargetShoot(arg0 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnsetTargetShoot", reflect.TypeOf((*MockManager)(nil).UnsetTargetShoot), arg0)
 }
 
+// ViewerKubeconfig mocks base method.
+func (m *MockManager) ViewerKubeconfig(arg0 context.Context, arg1 target.Target, arg2 time.Duration) (clientcmd.ClientConfig, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewerKubeconfig", arg0, arg1, arg2)
+	ret0, _ := ret[0].(clientcmd.ClientConfig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewerKubeconfig indicates an expected call of ViewerKubeconfig.
+func (mr *MockManagerMockRecorder) ViewerKubeconfig(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewerKubeconfig", reflect.TypeOf((*MockManager)(nil).ViewerKubeconfig), arg0, arg1, arg2)
+}
+
 // WriteClientConfig mocks base method.
 func (m *MockManager) WriteClientConfig(arg0 clientcmd.ClientConfig) (string, error) {
 	m.ctrl.T.Helper()
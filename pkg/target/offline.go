@@ -0,0 +1,94 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package target
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ErrOffline is returned instead of sending a request to an API server if gardenctl runs in offline mode
+var ErrOffline = errors.New("the command requires access to the garden cluster, which is not available in offline mode")
+
+// UnreachableError is returned if the connection to an API server cannot be established,
+// e.g. because there is no network or the API server is only reachable via VPN
+type UnreachableError struct {
+	// Host is the host of the API server
+	Host string
+	// Err is the error of the connection attempt
+	Err error
+}
+
+var _ error = &UnreachableError{}
+
+func (e *UnreachableError) Error() string {
+	return fmt.Sprintf("garden unreachable: failed to connect to %s, check your network connection: %v", e.Host, e.Err)
+}
+
+func (e *UnreachableError) Unwrap() error {
+	return e.Err
+}
+
+// IsUnreachable returns true if the error has been caused by an API server that cannot be reached,
+// either because the connection failed or because gardenctl runs in offline mode
+func IsUnreachable(err error) bool {
+	var unreachableErr *UnreachableError
+
+	return errors.Is(err, ErrOffline) || errors.As(err, &unreachableErr)
+}
+
+// offlineClientProvider is used in offline mode, it refuses to create clients
+type offlineClientProvider struct{}
+
+var _ ClientProvider = &offlineClientProvider{}
+
+// NewOfflineClientProvider returns a ClientProvider for the offline mode, all clients fail with ErrOffline.
+func NewOfflineClientProvider() ClientProvider {
+	return &offlineClientProvider{}
+}
+
+// FromClientConfig always fails with ErrOffline.
+func (p *offlineClientProvider) FromClientConfig(clientConfig clientcmd.ClientConfig) (client.Client, error) {
+	return nil, ErrOffline
+}
+
+// unreachableRoundTripper converts the errors of failed connection attempts into an UnreachableError
+type unreachableRoundTripper struct {
+	delegate http.RoundTripper
+}
+
+var _ http.RoundTripper = &unreachableRoundTripper{}
+
+func newUnreachableRoundTripper(rt http.RoundTripper) http.RoundTripper {
+	return &unreachableRoundTripper{delegate: rt}
+}
+
+func (rt *unreachableRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := rt.delegate.RoundTrip(req)
+	if err != nil && isConnectError(err) {
+		return resp, &UnreachableError{Host: req.URL.Host, Err: err}
+	}
+
+	return resp, err
+}
+
+// isConnectError returns true if the host name could not be resolved or the connection could not be established
+func isConnectError(err error) bool {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+
+	var opErr *net.OpError
+
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
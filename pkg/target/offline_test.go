@@ -0,0 +1,63 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package target_test
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/gardener/gardenctl-v2/pkg/target"
+)
+
+var _ = Describe("Offline", func() {
+	var req *http.Request
+
+	BeforeEach(func() {
+		var err error
+		req, err = http.NewRequest(http.MethodGet, "https://api.garden.example.com/api/v1/namespaces", nil)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should report a failed connection as unreachable", func() {
+		rt := target.NewUnreachableRoundTripper(roundTripperFunc(func(*http.Request) (*http.Response, error) {
+			return nil, &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("i/o timeout")}
+		}))
+
+		_, err := rt.RoundTrip(req)
+		Expect(err).To(MatchError("garden unreachable: failed to connect to api.garden.example.com, check your network connection: dial tcp: i/o timeout"))
+		Expect(target.IsUnreachable(fmt.Errorf("failed to list shoots: %w", err))).To(BeTrue())
+	})
+
+	It("should report an unknown host as unreachable", func() {
+		rt := target.NewUnreachableRoundTripper(roundTripperFunc(func(*http.Request) (*http.Response, error) {
+			return nil, &net.DNSError{Err: "no such host", Name: "api.garden.example.com"}
+		}))
+
+		_, err := rt.RoundTrip(req)
+		Expect(target.IsUnreachable(err)).To(BeTrue())
+	})
+
+	It("should not change other errors", func() {
+		rt := target.NewUnreachableRoundTripper(roundTripperFunc(func(*http.Request) (*http.Response, error) {
+			return nil, &net.OpError{Op: "read", Net: "tcp", Err: errors.New("connection reset by peer")}
+		}))
+
+		_, err := rt.RoundTrip(req)
+		Expect(target.IsUnreachable(err)).To(BeFalse())
+	})
+
+	It("should refuse to create clients in offline mode", func() {
+		_, err := target.NewOfflineClientProvider().FromClientConfig(nil)
+		Expect(err).To(MatchError(target.ErrOffline))
+		Expect(target.IsUnreachable(err)).To(BeTrue())
+	})
+})
@@ -0,0 +1,156 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package target
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/gardener/gardenctl-v2/internal/audit"
+	"github.com/gardener/gardenctl-v2/pkg/config"
+)
+
+// protectedClientProvider provides the clients of the garden, seed and shoot clusters of a protected garden
+type protectedClientProvider struct {
+	delegate   ClientProvider
+	config     *config.Config
+	gardenName string
+}
+
+var _ ClientProvider = &protectedClientProvider{}
+
+// newProtectedClientProvider returns a ClientProvider whose clients ask for confirmation before write requests
+// are sent to the clusters of the garden, or deny them, as configured for the garden. The delegate is returned
+// if the garden is not protected.
+func newProtectedClientProvider(delegate ClientProvider, cfg *config.Config, gardenName string) ClientProvider {
+	if !cfg.ProtectsGarden(gardenName) {
+		return delegate
+	}
+
+	return &protectedClientProvider{
+		delegate:   delegate,
+		config:     cfg,
+		gardenName: gardenName,
+	}
+}
+
+// FromClientConfig returns a Kubernetes client for the given client config that only sends confirmed write requests.
+func (p *protectedClientProvider) FromClientConfig(clientConfig clientcmd.ClientConfig) (client.Client, error) {
+	c, err := p.delegate.FromClientConfig(clientConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &protectedClient{Client: c, config: p.config, gardenName: p.gardenName}, nil
+}
+
+// protectedClient checks the write requests against the protection of the garden before they are sent.
+// Dry-run requests and reviews do not change any resource and are always sent.
+type protectedClient struct {
+	client.Client
+	config     *config.Config
+	gardenName string
+}
+
+var _ client.Client = &protectedClient{}
+
+func (c *protectedClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if (&client.CreateOptions{}).ApplyOptions(opts).DryRun == nil && !isReview(obj) {
+		if err := c.confirm("create", obj); err != nil {
+			return err
+		}
+	}
+
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func (c *protectedClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	if (&client.UpdateOptions{}).ApplyOptions(opts).DryRun == nil {
+		if err := c.confirm("update", obj); err != nil {
+			return err
+		}
+	}
+
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+func (c *protectedClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if (&client.PatchOptions{}).ApplyOptions(opts).DryRun == nil {
+		if err := c.confirm("patch", obj); err != nil {
+			return err
+		}
+	}
+
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func (c *protectedClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	if (&client.DeleteOptions{}).ApplyOptions(opts).DryRun == nil {
+		if err := c.confirm("delete", obj); err != nil {
+			return err
+		}
+	}
+
+	return c.Client.Delete(ctx, obj, opts...)
+}
+
+func (c *protectedClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	if (&client.DeleteAllOfOptions{}).ApplyOptions(opts).DryRun == nil {
+		if err := c.confirm("deletecollection", obj); err != nil {
+			return err
+		}
+	}
+
+	return c.Client.DeleteAllOf(ctx, obj, opts...)
+}
+
+func (c *protectedClient) Status() client.StatusWriter {
+	return &protectedStatusWriter{client: c}
+}
+
+// confirm asks for confirmation of the write request, denied requests are recorded in the audit log,
+// because they are never sent
+func (c *protectedClient) confirm(verb string, obj client.Object) error {
+	resource := describeObject(obj, c.Scheme())
+
+	err := c.config.ConfirmMutation(c.gardenName, fmt.Sprintf("%s %s", verb, resource))
+	if config.IsMutationDenied(err) {
+		c.config.Audit.Log(audit.Entry{Action: verb, Resource: resource}, err)
+	}
+
+	return err
+}
+
+// protectedStatusWriter checks the status updates against the protection of the garden before they are sent
+type protectedStatusWriter struct {
+	client *protectedClient
+}
+
+var _ client.StatusWriter = &protectedStatusWriter{}
+
+func (w *protectedStatusWriter) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	if (&client.UpdateOptions{}).ApplyOptions(opts).DryRun == nil {
+		if err := w.client.confirm("update status", obj); err != nil {
+			return err
+		}
+	}
+
+	return w.client.Client.Status().Update(ctx, obj, opts...)
+}
+
+func (w *protectedStatusWriter) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if (&client.PatchOptions{}).ApplyOptions(opts).DryRun == nil {
+		if err := w.client.confirm("patch status", obj); err != nil {
+			return err
+		}
+	}
+
+	return w.client.Client.Status().Patch(ctx, obj, patch, opts...)
+}
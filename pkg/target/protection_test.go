@@ -0,0 +1,136 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package target_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/gardener/gardenctl-v2/internal/audit"
+	"github.com/gardener/gardenctl-v2/internal/fake"
+	"github.com/gardener/gardenctl-v2/pkg/config"
+	"github.com/gardener/gardenctl-v2/pkg/target"
+	targetmocks "github.com/gardener/gardenctl-v2/pkg/target/mocks"
+)
+
+var _ = Describe("Protection", func() {
+	var (
+		ctx       context.Context
+		ctrl      *gomock.Controller
+		cfg       *config.Config
+		questions []string
+		answer    bool
+		configMap *corev1.ConfigMap
+		delegate  client.Client
+		provider  *targetmocks.MockClientProvider
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		ctrl = gomock.NewController(GinkgoT())
+
+		questions = nil
+		answer = false
+
+		cfg = &config.Config{
+			LinkKubeconfig: pointer.Bool(false),
+			Gardens:        []config.Garden{{Name: gardenName, Kubeconfig: gardenKubeconfig, Protected: true}},
+			Confirmation: &config.Confirmation{Confirm: func(question string) (bool, error) {
+				questions = append(questions, question)
+				return answer, nil
+			}},
+		}
+
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "existing",
+				Namespace: "default",
+			},
+		}
+
+		delegate = fake.NewClientWithObjects(configMap)
+		provider = targetmocks.NewMockClientProvider(ctrl)
+		provider.EXPECT().FromClientConfig(gomock.Any()).Return(delegate, nil).AnyTimes()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	gardenClient := func() client.Client {
+		manager, _ := createTestManager(target.NewTarget(gardenName, "", "", ""), cfg, provider)
+
+		gardenClient, err := manager.GardenClient(gardenName)
+		Expect(err).NotTo(HaveOccurred())
+
+		return gardenClient.RuntimeClient()
+	}
+
+	It("should send write requests to protected gardens only if they have been confirmed", func() {
+		answer = true
+
+		c := gardenClient()
+		Expect(c.Update(ctx, configMap)).To(Succeed())
+		Expect(c.Delete(ctx, configMap)).To(Succeed())
+
+		Expect(questions).To(Equal([]string{`Garden "` + gardenName + `" is protected. Do you really want to update ConfigMap default/existing?`}))
+		Expect(delegate.Get(ctx, client.ObjectKeyFromObject(configMap), &corev1.ConfigMap{})).NotTo(Succeed())
+	})
+
+	It("should not send write requests that have not been confirmed", func() {
+		err := gardenClient().Delete(ctx, configMap)
+		Expect(config.IsMutationDenied(err)).To(BeTrue())
+		Expect(delegate.Get(ctx, client.ObjectKeyFromObject(configMap), &corev1.ConfigMap{})).To(Succeed())
+	})
+
+	It("should not ask for confirmation of read and dry-run requests", func() {
+		c := gardenClient()
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(configMap), &corev1.ConfigMap{})).To(Succeed())
+		Expect(c.Delete(ctx, configMap, client.DryRunAll)).To(Succeed())
+		Expect(questions).To(BeEmpty())
+	})
+
+	It("should not ask for confirmation in dry-run mode", func() {
+		cfg.DryRun = &config.DryRun{Strategy: config.DryRunClient, Out: &bytes.Buffer{}}
+
+		Expect(gardenClient().Delete(ctx, configMap)).To(Succeed())
+		Expect(questions).To(BeEmpty())
+	})
+
+	It("should deny all write requests if mutations are denied", func() {
+		cfg.Gardens[0].DenyMutations = true
+
+		err := gardenClient().Delete(ctx, configMap)
+		Expect(err).To(MatchError(`delete ConfigMap default/existing denied for garden "` + gardenName + `": changes are disabled by denyMutations in the gardenctl configuration`))
+		Expect(questions).To(BeEmpty())
+	})
+
+	It("should record denied write requests in the audit log", func() {
+		dir, err := os.MkdirTemp("", "audit-")
+		Expect(err).NotTo(HaveOccurred())
+
+		defer os.RemoveAll(dir)
+
+		cfg.Audit = &audit.Logger{Filename: filepath.Join(dir, "audit.log")}
+
+		Expect(gardenClient().Delete(ctx, configMap)).NotTo(Succeed())
+
+		data, err := os.ReadFile(cfg.Audit.Filename)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(ContainSubstring(`"action":"delete","resource":"ConfigMap default/existing","result":"failure","error":"delete ConfigMap default/existing denied for garden`))
+	})
+})
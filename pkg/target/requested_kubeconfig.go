@@ -0,0 +1,189 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package target
+
+import (
+	"context"
+	"crypto/md5"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	authenticationv1alpha1 "github.com/gardener/gardener/pkg/apis/authentication/v1alpha1"
+	"gopkg.in/yaml.v3"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/gardener/gardenctl-v2/internal/filelock"
+	"github.com/gardener/gardenctl-v2/internal/gardenclient"
+)
+
+const (
+	// adminKubeconfigsDirectory is the directory in the session directory that contains the cached admin kubeconfigs
+	adminKubeconfigsDirectory = "admin-kubeconfigs"
+	// viewerKubeconfigsDirectory is the directory in the session directory that contains the cached viewer kubeconfigs
+	viewerKubeconfigsDirectory = "viewer-kubeconfigs"
+	// requestedKubeconfigRenewBefore is the remaining validity below which a cached kubeconfig is renewed
+	requestedKubeconfigRenewBefore = 5 * time.Minute
+)
+
+// requestKubeconfigFunc requests a kubeconfig with short-lived credentials for a shoot
+type requestKubeconfigFunc func(client gardenclient.Client, ctx context.Context, namespace, name string, expiration time.Duration) (*authenticationv1alpha1.AdminKubeconfigRequest, error)
+
+// cachedKubeconfig is a requested kubeconfig of a shoot together with the expiration time of its credential
+type cachedKubeconfig struct {
+	ExpirationTimestamp time.Time `yaml:"expirationTimestamp"`
+	Kubeconfig          []byte    `yaml:"kubeconfig"`
+}
+
+// AdminKubeconfig returns a client config with admin credentials for the shoot of the given target.
+// The kubeconfig is requested via the shoots/adminkubeconfig subresource and cached in the session directory
+// until its credential is about to expire or a shorter expiration is requested, unless security.ephemeralKubeconfigs is enabled.
+func (m *managerImpl) AdminKubeconfig(ctx context.Context, t Target, expiration time.Duration) (clientcmd.ClientConfig, error) {
+	return m.requestedKubeconfig(ctx, t, adminKubeconfigsDirectory, expiration, gardenclient.Client.CreateAdminKubeconfigRequest)
+}
+
+// ViewerKubeconfig returns a client config with read-only credentials for the shoot of the given target.
+// The kubeconfig is requested via the shoots/viewerkubeconfig subresource and cached like admin kubeconfigs.
+func (m *managerImpl) ViewerKubeconfig(ctx context.Context, t Target, expiration time.Duration) (clientcmd.ClientConfig, error) {
+	return m.requestedKubeconfig(ctx, t, viewerKubeconfigsDirectory, expiration, gardenclient.Client.CreateViewerKubeconfigRequest)
+}
+
+// requestedKubeconfig returns the cached kubeconfig of the shoot of the given target from the given cache directory.
+// If there is none, its credential is about to expire or it is valid longer than requested, a new kubeconfig is requested. Nothing is cached
+// if ephemeral kubeconfigs are enforced.
+func (m *managerImpl) requestedKubeconfig(ctx context.Context, t Target, directory string, expiration time.Duration, request requestKubeconfigFunc) (clientcmd.ClientConfig, error) {
+	t, err := m.getTarget(t)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.GardenName() == "" {
+		return nil, ErrNoGardenTargeted
+	}
+
+	if t.ShootName() == "" {
+		return nil, ErrNoShootTargeted
+	}
+
+	client, err := m.GardenClient(t.GardenName())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create garden cluster client: %w", err)
+	}
+
+	shoot, err := client.FindShoot(ctx, t.WithControlPlane(false).AsListOption())
+	if err != nil {
+		return nil, err
+	}
+
+	// the credentials must not be written to disk if ephemeral kubeconfigs are enforced, they are requested every time
+	if m.config.EphemeralKubeconfigs() {
+		requested, err := requestKubeconfig(ctx, client, shoot.Namespace, shoot.Name, expiration, request)
+		if err != nil {
+			return nil, err
+		}
+
+		return newRequestedClientConfig(shoot.Name, requested)
+	}
+
+	filename := m.requestedKubeconfigFile(directory, t.GardenName(), shoot.Namespace, shoot.Name)
+	if err := os.MkdirAll(filepath.Dir(filename), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	unlock, err := filelock.Lock(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock file: %w", err)
+	}
+	defer unlock()
+
+	cached, err := readRequestedKubeconfig(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	if !cached.usable(expiration, time.Now()) {
+		cached, err = requestKubeconfig(ctx, client, shoot.Namespace, shoot.Name, expiration, request)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := writeRequestedKubeconfig(filename, cached); err != nil {
+			return nil, err
+		}
+	}
+
+	return newRequestedClientConfig(shoot.Name, cached)
+}
+
+// usable returns true if the cached kubeconfig can be used instead of requesting a new one with the given expiration.
+// Its credential must not be about to expire, nor may it be valid longer than requested, e.g. a cached credential
+// that is valid for 24h is not returned if 10m have been requested.
+func (c *cachedKubeconfig) usable(expiration time.Duration, now time.Time) bool {
+	if c == nil {
+		return false
+	}
+
+	return !c.ExpirationTimestamp.Before(now.Add(requestedKubeconfigRenewBefore)) && !c.ExpirationTimestamp.After(now.Add(expiration))
+}
+
+// requestKubeconfig requests a new kubeconfig for the shoot with the given namespace and name
+func requestKubeconfig(ctx context.Context, client gardenclient.Client, namespace, name string, expiration time.Duration, request requestKubeconfigFunc) (*cachedKubeconfig, error) {
+	result, err := request(client, ctx, namespace, name, expiration)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cachedKubeconfig{
+		ExpirationTimestamp: result.Status.ExpirationTimestamp.Time,
+		Kubeconfig:          result.Status.Kubeconfig,
+	}, nil
+}
+
+func newRequestedClientConfig(shootName string, requested *cachedKubeconfig) (clientcmd.ClientConfig, error) {
+	config, err := clientcmd.NewClientConfigFromBytes(requested.Kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to deserialize requested kubeconfig for shoot %q: %w", shootName, err)
+	}
+
+	return config, nil
+}
+
+// requestedKubeconfigFile returns the name of the cache file for a requested kubeconfig of a shoot.
+// The shoot is identified by the garden, its namespace and its name.
+func (m *managerImpl) requestedKubeconfigFile(directory, gardenName, namespace, shootName string) string {
+	key := md5.Sum([]byte(fmt.Sprintf("%s/%s/%s", gardenName, namespace, shootName)))
+
+	return filepath.Join(m.sessionDirectory, directory, fmt.Sprintf("%x.yaml", key))
+}
+
+func readRequestedKubeconfig(filename string) (*cachedKubeconfig, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	cached := &cachedKubeconfig{}
+	if err := yaml.Unmarshal(data, cached); err != nil {
+		return nil, fmt.Errorf("failed to decode as YAML: %w", err)
+	}
+
+	return cached, nil
+}
+
+func writeRequestedKubeconfig(filename string, cached *cachedKubeconfig) error {
+	data, err := yaml.Marshal(cached)
+	if err != nil {
+		return fmt.Errorf("failed to encode as YAML: %w", err)
+	}
+
+	return os.WriteFile(filename, data, 0600)
+}
@@ -0,0 +1,154 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package target
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// requestLogLevel is the verbosity at which the requests to the Kubernetes API servers are logged
+const requestLogLevel = 4
+
+// loggingRoundTripper logs the method, URL, status and latency of every request at requestLogLevel
+type loggingRoundTripper struct {
+	delegate http.RoundTripper
+	now      func() time.Time
+}
+
+var _ http.RoundTripper = &loggingRoundTripper{}
+
+// newLoggingRoundTripper returns a round tripper that logs the requests of the given round tripper,
+// if requests are logged at the configured verbosity. Otherwise the given round tripper is returned.
+func newLoggingRoundTripper(rt http.RoundTripper) http.RoundTripper {
+	if !klog.V(requestLogLevel).Enabled() {
+		return rt
+	}
+
+	return &loggingRoundTripper{delegate: rt, now: time.Now}
+}
+
+func (rt *loggingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := rt.now()
+	resp, err := rt.delegate.RoundTrip(req)
+	latency := rt.now().Sub(start).Round(time.Millisecond)
+
+	if err != nil {
+		klog.V(requestLogLevel).Infof("%s %s failed after %s: %v", req.Method, req.URL, latency, err)
+		return resp, err
+	}
+
+	klog.V(requestLogLevel).Infof("%s %s %s in %s", req.Method, req.URL, resp.Status, latency)
+
+	return resp, nil
+}
+
+// retryRoundTripper retries requests that failed with a transient error with an exponential backoff.
+// Requests that have been rejected by the API server because it is throttling or unavailable are retried
+// for all methods. Network errors and gateway errors are only retried for reading requests, because the
+// API server may already have processed a modifying request.
+type retryRoundTripper struct {
+	delegate http.RoundTripper
+	retries  int
+	backoff  time.Duration
+	// after waits for the given duration, it is replaced in tests
+	after func(d time.Duration) <-chan time.Time
+}
+
+var _ http.RoundTripper = &retryRoundTripper{}
+
+// newRetryRoundTripper returns a function that wraps a round tripper in a retryRoundTripper,
+// it can be passed to rest.Config.Wrap
+func newRetryRoundTripper(retries int, backoff time.Duration) func(rt http.RoundTripper) http.RoundTripper {
+	return func(rt http.RoundTripper) http.RoundTripper {
+		if retries <= 0 {
+			return rt
+		}
+
+		return &retryRoundTripper{delegate: rt, retries: retries, backoff: backoff, after: time.After}
+	}
+}
+
+func (rt *retryRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	// a request with a body can only be retried if the body can be read again
+	replayable := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+	backoff := rt.backoff
+
+	for attempt := 0; ; attempt++ {
+		resp, err := rt.delegate.RoundTrip(req)
+		if attempt >= rt.retries || !replayable || !isTransient(req, resp, err) {
+			return resp, err
+		}
+
+		delay := backoff
+		if after := retryAfter(resp); after > delay {
+			delay = after
+		}
+
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		klog.V(requestLogLevel).Infof("retrying %s %s in %s (%d/%d)", req.Method, req.URL, delay, attempt+1, rt.retries)
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-rt.after(delay):
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+
+		backoff *= 2
+	}
+}
+
+// isTransient returns true if the request failed with an error that may not occur again if it is retried
+func isTransient(req *http.Request, resp *http.Response, err error) bool {
+	reading := req.Method == http.MethodGet || req.Method == http.MethodHead || req.Method == http.MethodOptions
+
+	if err != nil {
+		// a cluster that cannot be reached is not retried, the command should fail fast
+		return reading && req.Context().Err() == nil && !IsUnreachable(err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		return reading
+	}
+
+	return false
+}
+
+// retryAfter returns the delay requested by the Retry-After header of the response in seconds, or zero
+func retryAfter(resp *http.Response) time.Duration {
+	if resp == nil {
+		return 0
+	}
+
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+
+	return time.Duration(seconds) * time.Second
+}
@@ -0,0 +1,175 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package target_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/klog/v2"
+
+	"github.com/gardener/gardenctl-v2/pkg/target"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+var _ = Describe("Logging Round Tripper", func() {
+	var (
+		logs *bytes.Buffer
+		now  func() time.Time
+		req  *http.Request
+	)
+
+	BeforeEach(func() {
+		logs = &bytes.Buffer{}
+		klog.LogToStderr(false)
+		klog.SetOutput(logs)
+
+		var level klog.Level
+		Expect(level.Set("4")).To(Succeed())
+
+		start := time.Date(2022, 4, 1, 12, 0, 0, 0, time.UTC)
+		calls := 0
+		now = func() time.Time {
+			calls++
+			return start.Add(time.Duration(calls) * 25 * time.Millisecond)
+		}
+
+		var err error
+		req, err = http.NewRequest(http.MethodGet, "https://api.garden.example.com/api/v1/namespaces", nil)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		var level klog.Level
+		Expect(level.Set("0")).To(Succeed())
+		klog.LogToStderr(true)
+	})
+
+	It("should log the method, URL, status and latency of a request", func() {
+		rt := target.NewLoggingRoundTripper(roundTripperFunc(func(*http.Request) (*http.Response, error) {
+			return &http.Response{Status: "200 OK", StatusCode: http.StatusOK}, nil
+		}), now)
+
+		_, err := rt.RoundTrip(req)
+		Expect(err).NotTo(HaveOccurred())
+		klog.Flush()
+		Expect(logs.String()).To(ContainSubstring("GET https://api.garden.example.com/api/v1/namespaces 200 OK in 25ms"))
+	})
+
+	It("should log failed requests", func() {
+		rt := target.NewLoggingRoundTripper(roundTripperFunc(func(*http.Request) (*http.Response, error) {
+			return nil, errors.New("connection refused")
+		}), now)
+
+		_, err := rt.RoundTrip(req)
+		Expect(err).To(MatchError("connection refused"))
+		klog.Flush()
+		Expect(logs.String()).To(ContainSubstring("GET https://api.garden.example.com/api/v1/namespaces failed after 25ms: connection refused"))
+	})
+})
+
+var _ = Describe("Retry Round Tripper", func() {
+	var (
+		delays    []time.Duration
+		after     func(time.Duration) <-chan time.Time
+		responses []int
+		requests  int
+		bodies    []string
+		rt        http.RoundTripper
+	)
+
+	BeforeEach(func() {
+		delays = nil
+		after = func(d time.Duration) <-chan time.Time {
+			delays = append(delays, d)
+			ch := make(chan time.Time, 1)
+			ch <- time.Time{}
+
+			return ch
+		}
+		requests = 0
+		bodies = nil
+		rt = target.NewRetryRoundTripper(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if req.Body != nil {
+				body, err := io.ReadAll(req.Body)
+				Expect(err).NotTo(HaveOccurred())
+
+				bodies = append(bodies, string(body))
+			}
+
+			status := responses[requests]
+			requests++
+
+			if status == 0 {
+				return nil, errors.New("connection reset by peer")
+			}
+
+			resp := &http.Response{StatusCode: status, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(""))}
+			if status == http.StatusTooManyRequests {
+				resp.Header.Set("Retry-After", "2")
+			}
+
+			return resp, nil
+		}), 3, 500*time.Millisecond, after)
+	})
+
+	It("should retry transient errors with an exponential backoff", func() {
+		responses = []int{http.StatusServiceUnavailable, 0, http.StatusOK}
+		req, err := http.NewRequest(http.MethodGet, "https://api.garden.example.com/api/v1/namespaces", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		resp, err := rt.RoundTrip(req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(delays).To(Equal([]time.Duration{500 * time.Millisecond, time.Second}))
+	})
+
+	It("should give up after the configured number of retries and respect Retry-After", func() {
+		responses = []int{http.StatusTooManyRequests, http.StatusTooManyRequests, http.StatusServiceUnavailable, http.StatusServiceUnavailable}
+		req, err := http.NewRequest(http.MethodGet, "https://api.garden.example.com/api/v1/namespaces", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		resp, err := rt.RoundTrip(req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(http.StatusServiceUnavailable))
+		Expect(requests).To(Equal(4))
+		Expect(delays).To(Equal([]time.Duration{2 * time.Second, 2 * time.Second, 2 * time.Second}))
+	})
+
+	It("should replay the body of a throttled modifying request", func() {
+		responses = []int{http.StatusTooManyRequests, http.StatusCreated}
+		req, err := http.NewRequest(http.MethodPost, "https://api.garden.example.com/api/v1/namespaces", strings.NewReader(`{"kind":"Namespace"}`))
+		Expect(err).NotTo(HaveOccurred())
+
+		resp, err := rt.RoundTrip(req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+		Expect(bodies).To(Equal([]string{`{"kind":"Namespace"}`, `{"kind":"Namespace"}`}))
+	})
+
+	It("should not retry network errors of modifying requests", func() {
+		responses = []int{0, http.StatusCreated}
+		req, err := http.NewRequest(http.MethodPost, "https://api.garden.example.com/api/v1/namespaces", strings.NewReader("{}"))
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = rt.RoundTrip(req)
+		Expect(err).To(MatchError("connection reset by peer"))
+		Expect(requests).To(Equal(1))
+		Expect(delays).To(BeEmpty())
+	})
+})
@@ -0,0 +1,64 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package target
+
+import (
+	"context"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+)
+
+// checkAccess verifies that the user is allowed to read the targeted project, seed or shoot.
+// This way a missing permission is reported when targeting and not later by commands that use the target.
+func (m *managerImpl) checkAccess(ctx context.Context, t Target) error {
+	if t.GardenName() == "" || (t.ProjectName() == "" && t.SeedName() == "" && t.ShootName() == "") {
+		return nil
+	}
+
+	client, err := m.GardenClient(t.GardenName())
+	if err != nil {
+		return err
+	}
+
+	if t.ShootName() == "" {
+		if t.ProjectName() != "" {
+			return client.CheckAccess(ctx, gardenResourceAttributes("projects", "", t.ProjectName()))
+		}
+
+		return client.CheckAccess(ctx, gardenResourceAttributes("seeds", "", t.SeedName()))
+	}
+
+	shoot, err := client.FindShoot(ctx, t.WithControlPlane(false).AsListOption())
+	if err != nil {
+		return err
+	}
+
+	if err := client.CheckAccess(ctx, gardenResourceAttributes("shoots", shoot.Namespace, shoot.Name)); err != nil {
+		return err
+	}
+
+	// the kubeconfig of the shoot is read from this configmap
+	return client.CheckAccess(ctx, authorizationv1.ResourceAttributes{
+		Verb:      "get",
+		Version:   "v1",
+		Resource:  "configmaps",
+		Namespace: shoot.Namespace,
+		Name:      shoot.Name + ".kubeconfig",
+	})
+}
+
+func gardenResourceAttributes(resource, namespace, name string) authorizationv1.ResourceAttributes {
+	return authorizationv1.ResourceAttributes{
+		Verb:      "get",
+		Group:     gardencorev1beta1.SchemeGroupVersion.Group,
+		Version:   gardencorev1beta1.SchemeGroupVersion.Version,
+		Resource:  resource,
+		Namespace: namespace,
+		Name:      name,
+	}
+}
@@ -90,8 +90,8 @@ func (b *targetBuilderImpl) SetGarden(name string) TargetBuilder {
 
 func (b *targetBuilderImpl) SetProject(ctx context.Context, name string) TargetBuilder {
 	b.actions = append(b.actions, func(t *targetImpl) error {
-		if t.Garden == "" {
-			return ErrNoGardenTargeted
+		if err := b.requireGarden(t); err != nil {
+			return err
 		}
 
 		// validate that the project exists
@@ -113,8 +113,8 @@ func (b *targetBuilderImpl) SetProject(ctx context.Context, name string) TargetB
 
 func (b *targetBuilderImpl) SetNamespace(ctx context.Context, name string) TargetBuilder {
 	b.actions = append(b.actions, func(t *targetImpl) error {
-		if t.Garden == "" {
-			return ErrNoGardenTargeted
+		if err := b.requireGarden(t); err != nil {
+			return err
 		}
 
 		projectName, err := b.getProjectNameByNamespace(ctx, t.GardenName(), name)
@@ -141,8 +141,8 @@ func (b *targetBuilderImpl) SetNamespace(ctx context.Context, name string) Targe
 
 func (b *targetBuilderImpl) SetSeed(ctx context.Context, name string) TargetBuilder {
 	b.actions = append(b.actions, func(t *targetImpl) error {
-		if t.Garden == "" {
-			return ErrNoGardenTargeted
+		if err := b.requireGarden(t); err != nil {
+			return err
 		}
 
 		// validate that the seed exists
@@ -164,8 +164,8 @@ func (b *targetBuilderImpl) SetSeed(ctx context.Context, name string) TargetBuil
 
 func (b *targetBuilderImpl) SetShoot(ctx context.Context, name string) TargetBuilder {
 	b.actions = append(b.actions, func(t *targetImpl) error {
-		if t.Garden == "" {
-			return ErrNoGardenTargeted
+		if err := b.requireGarden(t); err != nil {
+			return err
 		}
 
 		return b.completeTargetForShoot(ctx, t, name)
@@ -176,8 +176,8 @@ func (b *targetBuilderImpl) SetShoot(ctx context.Context, name string) TargetBui
 
 func (b *targetBuilderImpl) SetControlPlane(ctx context.Context) TargetBuilder {
 	b.actions = append(b.actions, func(t *targetImpl) error {
-		if t.Garden == "" {
-			return ErrNoGardenTargeted
+		if err := b.requireGarden(t); err != nil {
+			return err
 		}
 
 		err := b.completeTargetForShoot(ctx, t, t.Shoot)
@@ -193,6 +193,27 @@ func (b *targetBuilderImpl) SetControlPlane(ctx context.Context) TargetBuilder {
 	return b
 }
 
+// requireGarden ensures that a garden is targeted. If no garden is targeted,
+// the default garden of the configuration is used.
+func (b *targetBuilderImpl) requireGarden(t *targetImpl) error {
+	if t.Garden != "" {
+		return nil
+	}
+
+	if b.config.DefaultGarden == "" {
+		return ErrNoGardenTargeted
+	}
+
+	garden, err := b.config.Garden(b.config.DefaultGarden)
+	if err != nil {
+		return fmt.Errorf("failed to use default garden: %w", err)
+	}
+
+	t.Garden = garden.Name
+
+	return nil
+}
+
 func (b *targetBuilderImpl) completeTargetForShoot(ctx context.Context, t *targetImpl, name string) error {
 	gardenClient, err := b.getGardenClient(t.GardenName())
 	if err != nil {
@@ -0,0 +1,173 @@
+/*
+SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package target
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/gardener/gardenctl-v2/internal/filelock"
+)
+
+const (
+	// TargetHistoryFilename is the name of the file in the session directory that contains the previous targets
+	TargetHistoryFilename = "target-history.yaml"
+	// maxTargetHistoryLength is the maximum number of previous targets that are kept
+	maxTargetHistoryLength = 20
+)
+
+// ErrTargetHistoryEmpty is returned if there is no previous target to go back to
+var ErrTargetHistoryEmpty = errors.New("there is no previous target")
+
+// TargetHistory returns the previous targets of the session, the most recent one first
+func (m *managerImpl) TargetHistory() ([]Target, error) {
+	if m.sessionDirectory == "" {
+		return []Target{}, nil
+	}
+
+	filename := m.targetHistoryFile()
+
+	unlock, err := filelock.RLock(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	history, err := readTargetHistory(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	targets := make([]Target, len(history))
+	for i, t := range history {
+		targets[i] = t
+	}
+
+	return targets, nil
+}
+
+// TargetPrevious sets the most recent target of the target history, like "cd -"
+// The previous target is validated again, as e.g. the shoot could have been deleted meanwhile
+func (m *managerImpl) TargetPrevious(ctx context.Context) error {
+	history, err := m.TargetHistory()
+	if err != nil {
+		return fmt.Errorf("failed to read target history: %w", err)
+	}
+
+	if len(history) == 0 {
+		return ErrTargetHistoryEmpty
+	}
+
+	if err := m.ReplaceTarget(ctx, history[0]); err != nil {
+		return fmt.Errorf("failed to target %s: %w", history[0], err)
+	}
+
+	return nil
+}
+
+func (m *managerImpl) targetHistoryFile() string {
+	return filepath.Join(m.sessionDirectory, TargetHistoryFilename)
+}
+
+// addToTargetHistory adds the previous target to the beginning of the target history.
+// Older entries that are equal to the previous or the current target are removed.
+func (m *managerImpl) addToTargetHistory(previous, current *targetImpl) error {
+	if m.sessionDirectory == "" || previous.IsEmpty() || *previous == *current {
+		return nil
+	}
+
+	filename := m.targetHistoryFile()
+
+	unlock, err := filelock.Lock(filename)
+	if err != nil {
+		return fmt.Errorf("failed to lock file: %w", err)
+	}
+	defer unlock()
+
+	history, err := readTargetHistory(filename)
+	if err != nil {
+		return err
+	}
+
+	updated := []*targetImpl{previous}
+
+	for _, t := range history {
+		if *t != *previous && *t != *current {
+			updated = append(updated, t)
+		}
+	}
+
+	if len(updated) > maxTargetHistoryLength {
+		updated = updated[:maxTargetHistoryLength]
+	}
+
+	return writeTargetHistory(filename, updated)
+}
+
+// RenameGardenInTargetHistory replaces the garden name in all entries of the target history of the session directory,
+// so that going back to a previous target still works after the garden has been renamed in the configuration
+func RenameGardenInTargetHistory(sessionDirectory, oldName, newName string) error {
+	filename := filepath.Join(sessionDirectory, TargetHistoryFilename)
+
+	unlock, err := filelock.Lock(filename)
+	if err != nil {
+		return fmt.Errorf("failed to lock file: %w", err)
+	}
+	defer unlock()
+
+	history, err := readTargetHistory(filename)
+	if err != nil {
+		return err
+	}
+
+	changed := false
+
+	for _, t := range history {
+		if t.Garden == oldName {
+			t.Garden = newName
+			changed = true
+		}
+	}
+
+	if !changed {
+		return nil
+	}
+
+	return writeTargetHistory(filename, history)
+}
+
+func writeTargetHistory(filename string, history []*targetImpl) error {
+	data, err := yaml.Marshal(history)
+	if err != nil {
+		return fmt.Errorf("failed to encode as YAML: %w", err)
+	}
+
+	return os.WriteFile(filename, data, 0600)
+}
+
+func readTargetHistory(filename string) ([]*targetImpl, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*targetImpl{}, nil
+		}
+
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	history := []*targetImpl{}
+	if err := yaml.Unmarshal(data, &history); err != nil {
+		return nil, fmt.Errorf("failed to decode as YAML: %w", err)
+	}
+
+	return history, nil
+}
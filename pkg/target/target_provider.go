@@ -10,6 +10,8 @@ import (
 	"os"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/gardener/gardenctl-v2/internal/filelock"
 )
 
 // TargetReader can read targets.
@@ -45,6 +47,12 @@ type fsTargetProvider struct {
 var _ TargetProvider = &fsTargetProvider{}
 
 func (p *fsTargetProvider) Read() (Target, error) {
+	unlock, err := filelock.RLock(p.targetFile)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
 	f, err := os.Open(p.targetFile)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -76,6 +84,12 @@ func (p *fsTargetProvider) Read() (Target, error) {
 
 // Write takes a target and saves it permanently.
 func (p *fsTargetProvider) Write(t Target) error {
+	unlock, err := filelock.Lock(p.targetFile)
+	if err != nil {
+		return fmt.Errorf("failed to lock file: %w", err)
+	}
+	defer unlock()
+
 	f, err := os.OpenFile(p.targetFile, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
 	if err != nil {
 		return fmt.Errorf("failed to create file: %w", err)